@@ -12,25 +12,24 @@ require (
 	github.com/df-mc/worldupgrader v1.0.15
 	github.com/go-gl/mathgl v1.1.0
 	github.com/google/uuid v1.6.0
+	github.com/klauspost/compress v1.17.9
 	github.com/pelletier/go-toml v1.9.5
 	github.com/rogpeppe/go-internal v1.11.0
 	github.com/sandertv/gophertunnel v1.38.0
 	github.com/segmentio/fasthash v1.0.3
-	github.com/sirupsen/logrus v1.9.3
+	github.com/yuin/gopher-lua v1.1.1
+	golang.org/x/crypto v0.24.0
 	golang.org/x/exp v0.0.0-20230206171751-46f607a40771
+	golang.org/x/net v0.26.0
 	golang.org/x/text v0.16.0
 )
 
 require (
 	github.com/go-jose/go-jose/v3 v3.0.3 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/klauspost/compress v1.17.9 // indirect
 	github.com/muhammadmuzzammil1998/jsonc v1.0.0 // indirect
 	github.com/sandertv/go-raknet v1.14.0 // indirect
-	golang.org/x/crypto v0.24.0 // indirect
 	golang.org/x/image v0.17.0 // indirect
-	golang.org/x/net v0.26.0 // indirect
 	golang.org/x/oauth2 v0.21.0 // indirect
-	golang.org/x/sys v0.21.0 // indirect
 	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
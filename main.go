@@ -2,39 +2,115 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/account"
+	"github.com/df-mc/dragonfly/server/admin"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/cmd/builtin"
+	"github.com/df-mc/dragonfly/server/logging"
 	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/plugin"
+	"github.com/df-mc/dragonfly/server/rcon"
+	"github.com/df-mc/dragonfly/server/script"
 	"github.com/pelletier/go-toml"
-	"github.com/sirupsen/logrus"
 )
 
 func main() {
-	log := logrus.New()
-	log.Formatter = &logrus.TextFormatter{ForceColors: true}
-	log.Level = logrus.DebugLevel
+	uc, err := readConfig()
+	if err != nil {
+		exit(slog.Default(), err)
+	}
+	levels, err := logging.ParseLevels(uc.Logging.Level, uc.Logging.Subsystems)
+	if err != nil {
+		exit(slog.Default(), fmt.Errorf("config: %w", err))
+	}
+	console := admin.NewConsole()
+	log := slog.New(console.Handler(logging.New(os.Stdout, uc.Logging.JSON, levels).Handler()))
 
 	chat.Global.Subscribe(chat.StdoutSubscriber{})
+	cmd.Register(builtin.GameRuleCommand())
+	cmd.Register(builtin.ProfileCommand())
+	cmd.Register(builtin.FunctionCommand())
+	cmd.Register(builtin.LogLevelCommand(levels))
 
-	conf, err := readConfig(log)
+	conf, err := uc.Config(log)
 	if err != nil {
-		log.Fatalln(err)
+		exit(log, err)
 	}
 
 	srv := conf.New()
+	if m := conf.Moderation; m != nil {
+		cmd.Register(builtin.BanCommand(m, srv))
+		cmd.Register(builtin.TempBanCommand(m, srv))
+		cmd.Register(builtin.UnbanCommand(m))
+		cmd.Register(builtin.BanIPCommand(m, srv))
+		cmd.Register(builtin.UnbanIPCommand(m))
+		cmd.Register(builtin.BanListCommand(m))
+		cmd.Register(builtin.WhitelistCommand(m))
+	}
+	if m := conf.Accounts; m != nil {
+		cmd.Register(builtin.AccountCommand(m))
+		if m.Mode() == account.ModePassword {
+			cmd.Register(builtin.LoginCommand(m))
+			cmd.Register(builtin.RegisterCommand(m))
+		}
+	}
+	plugins := plugin.NewLoader(srv, log)
+	if uc.Plugins.Folder != "" {
+		if err := plugins.LoadDir(uc.Plugins.Folder); err != nil {
+			exit(log, err)
+		}
+	}
+	if uc.Scripts.Folder != "" {
+		if err := plugins.Add(script.New(uc.Scripts.Folder)); err != nil {
+			exit(log, err)
+		}
+	}
+	plugins.Enable()
 	srv.CloseOnProgramEnd()
 
 	srv.Listen()
-	for srv.Accept(nil) {
+	srv.ReadConsole(os.Stdin)
+	if uc.Admin.Address != "" {
+		api := admin.Config{
+			Address:        uc.Admin.Address,
+			Token:          uc.Admin.Token,
+			Log:            log,
+			Console:        console,
+			Moderation:     conf.Moderation,
+			AllowedOrigins: uc.Admin.AllowedOrigins,
+		}.New(srv)
+		if err := api.Listen(); err != nil {
+			exit(log, err)
+		}
+		defer api.Close()
+	}
+	if uc.Rcon.Address != "" {
+		rc := rcon.Config{Address: uc.Rcon.Address, Password: uc.Rcon.Password, Log: log}.New(srv)
+		if err := rc.Listen(); err != nil {
+			exit(log, err)
+		}
+		defer rc.Close()
+	}
+	for srv.Accept(plugins.HandlePlayer) {
 	}
+	plugins.Disable()
+}
+
+// exit logs the error passed and ends the program.
+func exit(log *slog.Logger, err error) {
+	log.Error(err.Error())
+	os.Exit(1)
 }
 
 // readConfig reads the configuration from the config.toml file, or creates the
 // file if it does not yet exist.
-func readConfig(log server.Logger) (server.Config, error) {
+func readConfig() (server.UserConfig, error) {
 	c := server.DefaultConfig()
-	var zero server.Config
+	var zero server.UserConfig
 	if _, err := os.Stat("config.toml"); os.IsNotExist(err) {
 		data, err := toml.Marshal(c)
 		if err != nil {
@@ -43,7 +119,7 @@ func readConfig(log server.Logger) (server.Config, error) {
 		if err := os.WriteFile("config.toml", data, 0644); err != nil {
 			return zero, fmt.Errorf("create default config: %v", err)
 		}
-		return c.Config(log)
+		return c, nil
 	}
 	data, err := os.ReadFile("config.toml")
 	if err != nil {
@@ -52,5 +128,5 @@ func readConfig(log server.Logger) (server.Config, error) {
 	if err := toml.Unmarshal(data, &c); err != nil {
 		return zero, fmt.Errorf("decode config: %v", err)
 	}
-	return c.Config(log)
+	return c, nil
 }
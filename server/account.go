@@ -0,0 +1,282 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/account"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/inventory"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+)
+
+// accountLoginTimeout is the time that players have to log in to their account in account.ModePassword
+// before they are disconnected.
+const accountLoginTimeout = time.Minute
+
+// identify replaces the UUID of the connection passed with the UUID of its account in the account.Manager of
+// the listener. The connection is disconnected and false is returned if the account.Manager refused it.
+func (l listener) identify(c *minecraft.Conn) (session.Conn, bool) {
+	a, err := l.accounts.Join(c.IdentityData().DisplayName, c.ClientData().DeviceID)
+	if err != nil {
+		msg := "Your account could not be loaded."
+		if errors.Is(err, account.ErrDeviceNotLinked) {
+			msg = "This name is registered to another device."
+		} else if errors.Is(err, account.ErrNoDeviceID) {
+			msg = "Your client did not send a device ID."
+		} else if errors.Is(err, account.ErrOnline) {
+			msg = "A player with this name is already online."
+		}
+		_ = l.Listener.Disconnect(c, msg)
+		return nil, false
+	}
+	identity := c.IdentityData()
+	identity.Identity = a.UUID.String()
+	return accountConn{Conn: c, identity: identity, m: l.accounts, id: a.UUID, once: new(sync.Once)}, true
+}
+
+// accountConn is a connection of which the identity was replaced with that of its account in an
+// account.Manager. Note that the XUID of the connection is not verified without authentication. The
+// connection quits its account when it is closed or disconnected.
+type accountConn struct {
+	*minecraft.Conn
+	identity login.IdentityData
+
+	m    *account.Manager
+	id   uuid.UUID
+	once *sync.Once
+}
+
+// IdentityData returns the identity data of the connection with the UUID of its account.
+func (c accountConn) IdentityData() login.IdentityData {
+	return c.identity
+}
+
+// Close quits the account of the connection and closes it.
+func (c accountConn) Close() error {
+	c.quit()
+	return c.Conn.Close()
+}
+
+// quit quits the account of the connection in the account.Manager. Calling quit more than once has no effect.
+func (c accountConn) quit() {
+	c.once.Do(func() {
+		c.m.Quit(c.id)
+	})
+}
+
+// guardAccount prevents the player passed from doing anything other than logging in to its account until it
+// has done so, and disconnects the player if it does not log in within the accountLoginTimeout.
+func (srv *Server) guardAccount(p *player.Player, m *account.Manager) {
+	id := p.UUID()
+	p.Subscribe(accountGuard{m: m, id: id}, event.PriorityHighest)
+	// Inventory transactions are not handled by player handlers, so the inventories of the player are guarded
+	// separately. Moving items into the off-hand, a container or the crafting grid always takes them from one
+	// of these inventories first.
+	for _, inv := range []*inventory.Inventory{p.Inventory(), p.Armour().Inventory(), p.EnderChestInventory()} {
+		inv.Handle(inventoryGuard{Handler: inv.Handler(), m: m, id: id})
+	}
+	if a, ok := m.Account(p.Name()); ok && a.PasswordHash != "" {
+		p.Message(text.Colourf("<yellow>Please log in using /login followed by your password.</yellow>"))
+	} else {
+		p.Message(text.Colourf("<yellow>Please register a password using /register followed by the password.</yellow>"))
+	}
+	time.AfterFunc(accountLoginTimeout, func() {
+		if !m.LoggedIn(id) {
+			p.Disconnect("You did not log in in time.")
+		}
+	})
+}
+
+// accountGuard is a player.Handler that cancels the actions of a player until it has logged in to its
+// account. It is subscribed with event.PriorityHighest, so that other handlers cannot allow the actions.
+type accountGuard struct {
+	player.NopHandler
+	m  *account.Manager
+	id uuid.UUID
+}
+
+// HandleCommandExecution cancels all commands other than /login and /register.
+func (g accountGuard) HandleCommandExecution(ctx *event.Context, command cmd.Command, _ []string) {
+	if command.Name() != "login" && command.Name() != "register" {
+		g.restrict(ctx)
+	}
+}
+
+// HandleMove ...
+func (g accountGuard) HandleMove(ctx *event.Context, _ mgl64.Vec3, _, _ float64) {
+	g.restrict(ctx)
+}
+
+// HandleHeldSlotChange ...
+func (g accountGuard) HandleHeldSlotChange(ctx *event.Context, _, _ int) {
+	g.restrict(ctx)
+}
+
+// HandleToggleSprint ...
+func (g accountGuard) HandleToggleSprint(ctx *event.Context, _ bool) {
+	g.restrict(ctx)
+}
+
+// HandleToggleSneak ...
+func (g accountGuard) HandleToggleSneak(ctx *event.Context, _ bool) {
+	g.restrict(ctx)
+}
+
+// HandleEmote ...
+func (g accountGuard) HandleEmote(ctx *event.Context, _ uuid.UUID) {
+	g.restrict(ctx)
+}
+
+// HandleChat ...
+func (g accountGuard) HandleChat(ctx *event.Context, _ *string) {
+	g.restrict(ctx)
+}
+
+// HandleFoodLoss ...
+func (g accountGuard) HandleFoodLoss(ctx *event.Context, _ int, _ *int) {
+	g.restrict(ctx)
+}
+
+// HandleHurt ...
+func (g accountGuard) HandleHurt(ctx *event.Context, _ *float64, _ *time.Duration, _ world.DamageSource) {
+	g.restrict(ctx)
+}
+
+// HandleStartBreak ...
+func (g accountGuard) HandleStartBreak(ctx *event.Context, _ cube.Pos) {
+	g.restrict(ctx)
+}
+
+// HandleBlockBreak ...
+func (g accountGuard) HandleBlockBreak(ctx *event.Context, _ cube.Pos, _ *[]item.Stack, _ *int) {
+	g.restrict(ctx)
+}
+
+// HandleBlockPlace ...
+func (g accountGuard) HandleBlockPlace(ctx *event.Context, _ cube.Pos, _ world.Block) {
+	g.restrict(ctx)
+}
+
+// HandleBlockPick ...
+func (g accountGuard) HandleBlockPick(ctx *event.Context, _ cube.Pos, _ world.Block) {
+	g.restrict(ctx)
+}
+
+// HandleItemUse ...
+func (g accountGuard) HandleItemUse(ctx *event.Context) {
+	g.restrict(ctx)
+}
+
+// HandleItemUseOnBlock ...
+func (g accountGuard) HandleItemUseOnBlock(ctx *event.Context, _ cube.Pos, _ cube.Face, _ mgl64.Vec3) {
+	g.restrict(ctx)
+}
+
+// HandleItemUseOnEntity ...
+func (g accountGuard) HandleItemUseOnEntity(ctx *event.Context, _ world.Entity) {
+	g.restrict(ctx)
+}
+
+// HandleItemConsume ...
+func (g accountGuard) HandleItemConsume(ctx *event.Context, _ item.Stack) {
+	g.restrict(ctx)
+}
+
+// HandleAttackEntity ...
+func (g accountGuard) HandleAttackEntity(ctx *event.Context, _ world.Entity, _, _ *float64, _ *bool) {
+	g.restrict(ctx)
+}
+
+// HandleSignEdit ...
+func (g accountGuard) HandleSignEdit(ctx *event.Context, _ bool, _, _ string) {
+	g.restrict(ctx)
+}
+
+// HandleLecternPageTurn ...
+func (g accountGuard) HandleLecternPageTurn(ctx *event.Context, _ cube.Pos, _ int, _ *int) {
+	g.restrict(ctx)
+}
+
+// HandleItemPickup ...
+func (g accountGuard) HandleItemPickup(ctx *event.Context, _ *item.Stack) {
+	g.restrict(ctx)
+}
+
+// HandleItemDrop ...
+func (g accountGuard) HandleItemDrop(ctx *event.Context, _ world.Entity) {
+	g.restrict(ctx)
+}
+
+// HandlePunchAir ...
+func (g accountGuard) HandlePunchAir(ctx *event.Context) {
+	g.restrict(ctx)
+}
+
+// HandleLeftClickAir ...
+func (g accountGuard) HandleLeftClickAir(ctx *event.Context, _ time.Duration) {
+	g.restrict(ctx)
+}
+
+// HandleRightClickAir ...
+func (g accountGuard) HandleRightClickAir(ctx *event.Context, _ time.Duration) {
+	g.restrict(ctx)
+}
+
+// restrict cancels the event of the Context passed if the player has not yet logged in.
+func (g accountGuard) restrict(ctx *event.Context) {
+	if !g.m.LoggedIn(g.id) {
+		ctx.Cancel()
+	}
+}
+
+// inventoryGuard is an inventory.Handler that cancels all inventory transactions of a player until it has
+// logged in to its account. Transactions are passed on to the Handler that the inventory had before once the
+// player has logged in.
+type inventoryGuard struct {
+	inventory.Handler
+	m  *account.Manager
+	id uuid.UUID
+}
+
+// HandleTake ...
+func (g inventoryGuard) HandleTake(ctx *event.Context, slot int, it item.Stack) {
+	if g.restrict(ctx) {
+		g.Handler.HandleTake(ctx, slot, it)
+	}
+}
+
+// HandlePlace ...
+func (g inventoryGuard) HandlePlace(ctx *event.Context, slot int, it item.Stack) {
+	if g.restrict(ctx) {
+		g.Handler.HandlePlace(ctx, slot, it)
+	}
+}
+
+// HandleDrop ...
+func (g inventoryGuard) HandleDrop(ctx *event.Context, slot int, it item.Stack) {
+	if g.restrict(ctx) {
+		g.Handler.HandleDrop(ctx, slot, it)
+	}
+}
+
+// restrict cancels the event of the Context passed if the player has not yet logged in. True is returned if
+// the player has logged in, in which case the event should be passed on.
+func (g inventoryGuard) restrict(ctx *event.Context) bool {
+	if !g.m.LoggedIn(g.id) {
+		ctx.Cancel()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,357 @@
+// Package account implements local accounts for servers that run without Xbox Live authentication, such as
+// development servers and servers for LAN events. Without authentication, the identity sent by clients when
+// joining cannot be trusted: Any client may claim any name and UUID. A Manager instead assigns every player a
+// UUID derived from its name and protects the name using either the device of the player or a password, so
+// that the data saved for a player stays consistent and cannot easily be taken over by other players.
+package account
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/exp/maps"
+)
+
+// Mode is the way in which a Manager verifies that a player owns the account of its name.
+type Mode int
+
+const (
+	// ModeDevice binds the name of a player to the device that first joined with it. Players joining with the
+	// name from another device are refused until the account is removed.
+	// ModeDevice is not secure: The device ID is sent by the client itself, so a player that learns the device
+	// ID of another player can send it to join with their name. ModeDevice should only be used where players
+	// are trusted, such as on a LAN, and ModePassword used otherwise.
+	ModeDevice Mode = iota
+	// ModePassword protects the name of a player with a password. Players register a password the first time
+	// they join and must log in with it every time they join, before they may do anything else.
+	ModePassword
+)
+
+// ParseMode parses the name of a Mode, which is either "device" or "password".
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "device":
+		return ModeDevice, nil
+	case "password":
+		return ModePassword, nil
+	}
+	return 0, fmt.Errorf("unknown account mode %q", s)
+}
+
+// String returns the name of the Mode, as parsed by ParseMode.
+func (m Mode) String() string {
+	if m == ModePassword {
+		return "password"
+	}
+	return "device"
+}
+
+// MinPasswordLength is the minimum number of characters of a password registered using Manager.Register.
+const MinPasswordLength = 6
+
+// maxLoginAttempts is the number of times that a wrong password may be entered for an account before
+// Manager.Login returns ErrTooManyAttempts.
+const maxLoginAttempts = 3
+
+// loginLockout is the time for which Manager.Login refuses to log in to an account after a wrong password was
+// entered maxLoginAttempts times.
+const loginLockout = time.Minute * 5
+
+var (
+	// ErrNoDeviceID is returned by Manager.Join in ModeDevice if the client did not send a device ID.
+	ErrNoDeviceID = errors.New("client did not send a device ID")
+	// ErrDeviceNotLinked is returned by Manager.Join in ModeDevice if the name is registered to another
+	// device.
+	ErrDeviceNotLinked = errors.New("name is registered to another device")
+	// ErrOnline is returned by Manager.Join in ModePassword if a player with the name is already online.
+	ErrOnline = errors.New("account is already online")
+	// ErrNoPasswords is returned by Manager.Register and Manager.Login in ModeDevice.
+	ErrNoPasswords = errors.New("accounts do not have passwords in device mode")
+	// ErrPasswordTooShort is returned by Manager.Register if the password is shorter than MinPasswordLength.
+	ErrPasswordTooShort = fmt.Errorf("password must be at least %v characters long", MinPasswordLength)
+	// ErrRegistered is returned by Manager.Register if the account already has a password.
+	ErrRegistered = errors.New("account is already registered")
+	// ErrNotRegistered is returned by Manager.Login if the account has no password yet.
+	ErrNotRegistered = errors.New("account is not registered")
+	// ErrWrongPassword is returned by Manager.Login if the password does not match that of the account.
+	ErrWrongPassword = errors.New("wrong password")
+	// ErrTooManyAttempts is returned by Manager.Login once a wrong password was entered too often for the
+	// account. Logging in is refused for a few minutes afterwards.
+	ErrTooManyAttempts = errors.New("too many failed login attempts")
+)
+
+// namespace is the namespace of the UUIDs derived from the names of players.
+var namespace = uuid.MustParse("97862b55-588e-42b1-b606-043d940222ec")
+
+// NameUUID returns the UUID assigned to players with the name passed. Names are case-insensitive, so the
+// same UUID is returned for a name in any case.
+func NameUUID(name string) uuid.UUID {
+	return uuid.NewSHA1(namespace, []byte(strings.ToLower(name)))
+}
+
+// Account is the account of a player, registered the first time the player joined in ModeDevice or when the
+// player registered a password in ModePassword.
+type Account struct {
+	// Name is the name of the account, in the case that it was registered with.
+	Name string
+	// UUID is the UUID that the player is assigned. It is derived from the Name using NameUUID when the
+	// account is created and does not change afterwards.
+	UUID uuid.UUID
+	// Devices holds the IDs of the devices that may join with the Name in ModeDevice.
+	Devices []string `json:",omitempty"`
+	// PasswordHash is the bcrypt hash of the password of the account in ModePassword.
+	PasswordHash string `json:",omitempty"`
+	// Created is the time at which the account was registered. It is zero for accounts that were not yet
+	// registered.
+	Created time.Time
+}
+
+// Manager manages the accounts of a server and stores them using a Provider. A Manager keeps track of the
+// players that joined and whether they have logged in to their account. A Manager is safe for concurrent use.
+type Manager struct {
+	p    Provider
+	mode Mode
+
+	mu sync.Mutex
+	d  Data
+	// sessions holds the login state of the players that are online, indexed by the UUID of their account.
+	sessions map[uuid.UUID]*session
+	// failures holds the failed login attempts of accounts, indexed by their UUID. They are kept when a player
+	// leaves, so that rejoining does not give a player new attempts.
+	failures map[uuid.UUID]*failures
+}
+
+// session is the login state of a player that is online.
+type session struct {
+	loggedIn bool
+	// conns is the number of connections that joined with the account and did not yet quit.
+	conns int
+}
+
+// failures holds the failed login attempts of an account.
+type failures struct {
+	attempts int
+	// until is the time until which logging in is refused after too many failed attempts.
+	until time.Time
+}
+
+// New creates a Manager that verifies players using the Mode passed and stores accounts in the Provider
+// passed. The accounts previously stored in the Provider are loaded immediately.
+func New(p Provider, mode Mode) (*Manager, error) {
+	d, err := p.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load accounts: %w", err)
+	}
+	if d.Accounts == nil {
+		d.Accounts = make(map[string]Account)
+	}
+	return &Manager{
+		p:        p,
+		mode:     mode,
+		d:        d,
+		sessions: make(map[uuid.UUID]*session),
+		failures: make(map[uuid.UUID]*failures),
+	}, nil
+}
+
+// Mode returns the Mode that the Manager verifies players with.
+func (m *Manager) Mode() Mode {
+	return m.mode
+}
+
+// Join identifies a player joining with the name and device ID passed and returns the Account of the name.
+// In ModeDevice, an account is registered for the name and device if the name has no account or an account
+// without devices, and the player is logged in immediately. ErrDeviceNotLinked is returned if the name is
+// registered to another device. In ModePassword, the player must log in using Register or Login before
+// LoggedIn returns true. ErrOnline is returned if a player with the name is already online, so that others
+// cannot reset the login state of that player. The Account returned is not yet registered if the player joins
+// for the first time. Quit must be called with the UUID of the Account once the player leaves.
+func (m *Manager) Join(name, deviceID string) (Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.ToLower(name)
+	a, ok := m.d.Accounts[key]
+	if !ok {
+		a = Account{Name: name, UUID: NameUUID(name)}
+	}
+	if m.mode == ModePassword {
+		if _, online := m.sessions[a.UUID]; online {
+			return Account{}, ErrOnline
+		}
+		m.sessions[a.UUID] = &session{conns: 1}
+		return a, nil
+	}
+	if deviceID == "" {
+		return Account{}, ErrNoDeviceID
+	}
+	if len(a.Devices) == 0 {
+		a.Devices = []string{deviceID}
+		if a.Created.IsZero() {
+			a.Created = time.Now()
+		}
+		m.d.Accounts[key] = a
+		if err := m.p.Save(m.d); err != nil {
+			return Account{}, fmt.Errorf("register account: %w", err)
+		}
+	} else if !slices.Contains(a.Devices, deviceID) {
+		return Account{}, ErrDeviceNotLinked
+	}
+	// The device of the player was verified, so it may replace a connection that is still online.
+	if s, online := m.sessions[a.UUID]; online {
+		s.conns++
+	} else {
+		m.sessions[a.UUID] = &session{loggedIn: true, conns: 1}
+	}
+	return a, nil
+}
+
+// LoggedIn checks if the player with the UUID passed has logged in to its account since it last joined.
+func (m *Manager) LoggedIn(id uuid.UUID) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	return ok && s.loggedIn
+}
+
+// Quit is called when a connection that joined with the Account of the UUID passed leaves the server. Once
+// all of its connections have left, the login state of the player is forgotten, so that it has to log in
+// again the next time it joins.
+func (m *Manager) Quit(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[id]; ok {
+		if s.conns--; s.conns <= 0 {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// Register registers the password passed for the account of the name passed and logs in the player that
+// joined with it. ErrRegistered is returned if the account already has a password.
+func (m *Manager) Register(name, password string) (Account, error) {
+	if m.mode != ModePassword {
+		return Account{}, ErrNoPasswords
+	}
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return Account{}, ErrPasswordTooShort
+	}
+	// Hashing a password is slow on purpose, so the hash is created before acquiring the lock.
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return Account{}, fmt.Errorf("hash password: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.ToLower(name)
+	a, ok := m.d.Accounts[key]
+	if ok && a.PasswordHash != "" {
+		return Account{}, ErrRegistered
+	}
+	if !ok {
+		a = Account{Name: name, UUID: NameUUID(name), Created: time.Now()}
+	}
+	a.PasswordHash = string(hash)
+	m.d.Accounts[key] = a
+	if err := m.p.Save(m.d); err != nil {
+		return Account{}, fmt.Errorf("register account: %w", err)
+	}
+	if s, ok := m.sessions[a.UUID]; ok {
+		s.loggedIn = true
+	}
+	return a, nil
+}
+
+// Login logs the player that joined with the name passed in to its account if the password passed matches the
+// password of the account. ErrWrongPassword is returned if it does not. Failed attempts are counted for the
+// account, regardless of how often the player rejoins: Once a wrong password was entered too often,
+// ErrTooManyAttempts is returned and logging in is refused for a few minutes.
+func (m *Manager) Login(name, password string) (Account, error) {
+	if m.mode != ModePassword {
+		return Account{}, ErrNoPasswords
+	}
+	m.mu.Lock()
+	a, ok := m.d.Accounts[strings.ToLower(name)]
+	if !ok || a.PasswordHash == "" {
+		m.mu.Unlock()
+		return Account{}, ErrNotRegistered
+	}
+	f, ok := m.failures[a.UUID]
+	if ok && time.Now().Before(f.until) {
+		m.mu.Unlock()
+		return Account{}, ErrTooManyAttempts
+	}
+	m.mu.Unlock()
+
+	err := bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(password))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		f, ok := m.failures[a.UUID]
+		if !ok {
+			f = &failures{}
+			m.failures[a.UUID] = f
+		}
+		if f.attempts++; f.attempts >= maxLoginAttempts {
+			f.attempts, f.until = 0, time.Now().Add(loginLockout)
+			return Account{}, ErrTooManyAttempts
+		}
+		return Account{}, ErrWrongPassword
+	}
+	delete(m.failures, a.UUID)
+	if s, ok := m.sessions[a.UUID]; ok {
+		s.loggedIn = true
+	}
+	return a, nil
+}
+
+// Account looks up the registered Account of the name passed.
+func (m *Manager) Account(name string) (Account, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.d.Accounts[strings.ToLower(name)]
+	return a, ok
+}
+
+// Accounts returns all registered accounts, ordered by their name.
+func (m *Manager) Accounts() []Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	accounts := maps.Values(m.d.Accounts)
+	slices.SortFunc(accounts, compareNames)
+	return accounts
+}
+
+// Remove removes the account of the name passed, so that the name may be registered again, for example
+// after a player forgot its password or switched devices. The UUID assigned to the name does not change, so
+// the player keeps its data. False is returned if the name had no account.
+func (m *Manager) Remove(name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToLower(name)
+	if _, ok := m.d.Accounts[key]; !ok {
+		return false, nil
+	}
+	delete(m.d.Accounts, key)
+	return true, m.p.Save(m.d)
+}
+
+// Close closes the Provider of the Manager.
+func (m *Manager) Close() error {
+	return m.p.Close()
+}
+
+// compareNames compares the names of two accounts case-insensitively, so that accounts may be sorted by their
+// name.
+func compareNames(a, b Account) int {
+	return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+}
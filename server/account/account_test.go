@@ -0,0 +1,243 @@
+package account
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newManager(t *testing.T, mode Mode) (*Manager, string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "accounts", "accounts.json")
+	p, err := NewJSONProvider(file)
+	if err != nil {
+		t.Fatalf("NewJSONProvider: %v", err)
+	}
+	m, err := New(p, mode)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m, file
+}
+
+func TestNameUUIDCaseInsensitive(t *testing.T) {
+	if NameUUID("Steve") != NameUUID("sTEVE") {
+		t.Errorf("NameUUID: UUIDs of names differing only in case are not equal")
+	}
+	if NameUUID("Steve") == NameUUID("Alex") {
+		t.Errorf("NameUUID: UUIDs of different names are equal")
+	}
+}
+
+func TestRegisterHashesPassword(t *testing.T) {
+	m, file := newManager(t, ModePassword)
+	if _, err := m.Join("Steve", ""); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if m.LoggedIn(NameUUID("Steve")) {
+		t.Fatalf("LoggedIn: player is logged in before registering")
+	}
+	if _, err := m.Register("Steve", "short"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("Register: got error %v, want %v", err, ErrPasswordTooShort)
+	}
+	a, err := m.Register("Steve", "hunter22")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if a.PasswordHash == "" || strings.Contains(a.PasswordHash, "hunter22") {
+		t.Errorf("Register: password is not hashed: %q", a.PasswordHash)
+	}
+	if !m.LoggedIn(a.UUID) {
+		t.Errorf("LoggedIn: player is not logged in after registering")
+	}
+	if _, err := m.Register("steve", "hunter33"); !errors.Is(err, ErrRegistered) {
+		t.Errorf("Register: got error %v, want %v", err, ErrRegistered)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read accounts: %v", err)
+	}
+	if strings.Contains(string(b), "hunter22") {
+		t.Errorf("Save: plain text password stored in %v", file)
+	}
+	if fi, err := os.Stat(file); err == nil && fi.Mode().Perm() != 0600 {
+		t.Errorf("Save: got file mode %v, want %v", fi.Mode().Perm(), os.FileMode(0600))
+	}
+}
+
+func TestLogin(t *testing.T) {
+	m, _ := newManager(t, ModePassword)
+	if _, err := m.Login("Steve", "hunter22"); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("Login: got error %v, want %v", err, ErrNotRegistered)
+	}
+	if _, err := m.Register("Steve", "hunter22"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	id := NameUUID("Steve")
+	m.Quit(id)
+	if m.LoggedIn(id) {
+		t.Fatalf("LoggedIn: player is still logged in after quitting")
+	}
+
+	if _, err := m.Join("Steve", ""); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if _, err := m.Login("STEVE", "wrong password"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Login: got error %v, want %v", err, ErrWrongPassword)
+	}
+	if m.LoggedIn(id) {
+		t.Errorf("LoggedIn: player is logged in after entering a wrong password")
+	}
+	if _, err := m.Login("Steve", "hunter22"); err != nil {
+		t.Errorf("Login: %v", err)
+	}
+	if !m.LoggedIn(id) {
+		t.Errorf("LoggedIn: player is not logged in after entering the right password")
+	}
+}
+
+func TestLoginTooManyAttempts(t *testing.T) {
+	m, _ := newManager(t, ModePassword)
+	if _, err := m.Register("Steve", "hunter22"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	id := NameUUID("Steve")
+	if _, err := m.Join("Steve", ""); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	for i := 1; i < maxLoginAttempts; i++ {
+		if _, err := m.Login("Steve", "wrong password"); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("Login attempt %v: got error %v, want %v", i, err, ErrWrongPassword)
+		}
+		// Rejoining must not give the player new attempts.
+		m.Quit(id)
+		if _, err := m.Join("Steve", ""); err != nil {
+			t.Fatalf("Join: %v", err)
+		}
+	}
+	if _, err := m.Login("Steve", "wrong password"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Login: got error %v, want %v", err, ErrTooManyAttempts)
+	}
+	// The right password must not be accepted either while the account is locked.
+	if _, err := m.Login("Steve", "hunter22"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Login: got error %v, want %v", err, ErrTooManyAttempts)
+	}
+	m.Quit(id)
+	if _, err := m.Join("Steve", ""); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if _, err := m.Login("Steve", "hunter22"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Login after rejoining: got error %v, want %v", err, ErrTooManyAttempts)
+	}
+
+	// Once the lockout has passed, the player may log in again.
+	m.failures[id].until = time.Now()
+	if _, err := m.Login("Steve", "hunter22"); err != nil {
+		t.Errorf("Login after lockout: %v", err)
+	}
+	if !m.LoggedIn(id) {
+		t.Errorf("LoggedIn: player is not logged in after the lockout")
+	}
+}
+
+func TestJoinWhileOnline(t *testing.T) {
+	m, _ := newManager(t, ModePassword)
+	if _, err := m.Register("Steve", "hunter22"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	id := NameUUID("Steve")
+	if _, err := m.Join("Steve", ""); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if _, err := m.Login("Steve", "hunter22"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := m.Join("STEVE", ""); !errors.Is(err, ErrOnline) {
+		t.Errorf("Join: got error %v, want %v", err, ErrOnline)
+	}
+	if !m.LoggedIn(id) {
+		t.Errorf("LoggedIn: login state of the online player was reset by another join")
+	}
+	m.Quit(id)
+	if _, err := m.Join("Steve", ""); err != nil {
+		t.Errorf("Join after Quit: %v", err)
+	}
+}
+
+func TestDeviceMode(t *testing.T) {
+	m, _ := newManager(t, ModeDevice)
+	if _, err := m.Join("Steve", ""); !errors.Is(err, ErrNoDeviceID) {
+		t.Errorf("Join: got error %v, want %v", err, ErrNoDeviceID)
+	}
+	a, err := m.Join("Steve", "device-a")
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if !m.LoggedIn(a.UUID) {
+		t.Errorf("LoggedIn: player is not logged in after joining with its device")
+	}
+	if _, err := m.Join("steve", "device-b"); !errors.Is(err, ErrDeviceNotLinked) {
+		t.Errorf("Join: got error %v, want %v", err, ErrDeviceNotLinked)
+	}
+	if _, err := m.Register("Steve", "hunter22"); !errors.Is(err, ErrNoPasswords) {
+		t.Errorf("Register: got error %v, want %v", err, ErrNoPasswords)
+	}
+
+	// A verified device may replace a connection that is still online. The player stays logged in until
+	// both connections have quit.
+	if _, err := m.Join("Steve", "device-a"); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	m.Quit(a.UUID)
+	if !m.LoggedIn(a.UUID) {
+		t.Errorf("LoggedIn: player logged out while another connection is online")
+	}
+	m.Quit(a.UUID)
+	if m.LoggedIn(a.UUID) {
+		t.Errorf("LoggedIn: player logged in after all connections quit")
+	}
+
+	if ok, err := m.Remove("STEVE"); !ok || err != nil {
+		t.Fatalf("Remove: got %v, %v, want true, nil", ok, err)
+	}
+	b, err := m.Join("Steve", "device-b")
+	if err != nil {
+		t.Fatalf("Join after Remove: %v", err)
+	}
+	if b.UUID != a.UUID {
+		t.Errorf("Join after Remove: UUID changed from %v to %v", a.UUID, b.UUID)
+	}
+}
+
+func TestJSONProviderPersists(t *testing.T) {
+	m, file := newManager(t, ModePassword)
+	if _, err := m.Register("Steve", "hunter22"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := m.Register("alex", "hunter33"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	p, err := NewJSONProvider(file)
+	if err != nil {
+		t.Fatalf("NewJSONProvider: %v", err)
+	}
+	m2, err := New(p, ModePassword)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	accounts := m2.Accounts()
+	if len(accounts) != 2 || accounts[0].Name != "alex" || accounts[1].Name != "Steve" {
+		t.Fatalf("Accounts: got %v, want alex and Steve", accounts)
+	}
+	if m2.LoggedIn(NameUUID("Steve")) {
+		t.Errorf("LoggedIn: login state persisted across managers")
+	}
+	if _, err := m2.Login("steve", "hunter22"); err != nil {
+		t.Errorf("Login: %v", err)
+	}
+}
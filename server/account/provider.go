@@ -0,0 +1,106 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Data holds the accounts of a Manager in a form that may be stored by a Provider.
+type Data struct {
+	// Accounts holds the accounts registered, indexed by the name of the account in lowercase.
+	Accounts map[string]Account
+}
+
+// Provider represents a value that may store the Data of a Manager, so that accounts persist when the server
+// restarts.
+type Provider interface {
+	// Load loads the Data stored in the Provider. If no Data was stored yet, empty Data and no error are
+	// returned.
+	Load() (Data, error)
+	// Save stores the Data passed in the Provider. It is called every time the Data of a Manager changes.
+	Save(d Data) error
+	// Closer is used on server close to safely close the Provider.
+	io.Closer
+}
+
+// Compile time check to make sure NopProvider implements Provider.
+var _ Provider = NopProvider{}
+
+// NopProvider is a Provider that does not store any Data. Accounts are lost when the server restarts, although
+// players keep their UUIDs as these are derived from their names.
+type NopProvider struct{}
+
+func (NopProvider) Load() (Data, error) { return Data{}, nil }
+func (NopProvider) Save(Data) error     { return nil }
+func (NopProvider) Close() error        { return nil }
+
+// JSONProvider is a Provider that stores Data in a JSON file, which may be read and edited by other programs
+// while the server is not running.
+type JSONProvider struct {
+	file string
+}
+
+// NewJSONProvider creates a Provider that stores Data in the JSON file passed. The directory of the file is
+// created if it does not yet exist.
+func NewJSONProvider(file string) (*JSONProvider, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return nil, fmt.Errorf("create account directory: %w", err)
+	}
+	return &JSONProvider{file: file}, nil
+}
+
+// jsonData is the JSON representation of Data.
+type jsonData struct {
+	Accounts []Account
+}
+
+// Load ...
+func (p *JSONProvider) Load() (Data, error) {
+	b, err := os.ReadFile(p.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return Data{}, nil
+	} else if err != nil {
+		return Data{}, err
+	}
+	var jd jsonData
+	if err := json.Unmarshal(b, &jd); err != nil {
+		return Data{}, err
+	}
+	d := Data{Accounts: make(map[string]Account, len(jd.Accounts))}
+	for _, a := range jd.Accounts {
+		d.Accounts[strings.ToLower(a.Name)] = a
+	}
+	return d, nil
+}
+
+// Save ...
+func (p *JSONProvider) Save(d Data) error {
+	jd := jsonData{Accounts: make([]Account, 0, len(d.Accounts))}
+	for _, a := range d.Accounts {
+		jd.Accounts = append(jd.Accounts, a)
+	}
+	// Sort the accounts so that the file does not change if the Data does not.
+	slices.SortFunc(jd.Accounts, compareNames)
+	b, err := json.MarshalIndent(jd, "", "\t")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that the data is never left half-written if the server stops while
+	// saving. The file holds password hashes, so it is only made readable by its owner.
+	tmp := p.file + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.file)
+}
+
+// Close ...
+func (p *JSONProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package admin implements an HTTP API for administrating a server.Server, so
+// that it may be managed by web panels, Discord bridges and similar tools.
+// Every request must be authenticated with a token, passed in an
+// `Authorization: Bearer <token>` header. Only the /api/console WebSocket
+// also accepts the token in a `token` query parameter, for clients that
+// cannot set headers such as browsers. Browsers may only open the WebSocket
+// from the origin of the API itself or from one of the AllowedOrigins.
+//
+// The API serves the following endpoints, all of which return JSON:
+//
+//	GET    /api/players             -- Lists the players online.
+//	POST   /api/players/{name}/kick -- Kicks a player: {"reason": "..."}
+//	GET    /api/bans                -- Lists the players banned.
+//	POST   /api/bans                -- Bans a player: {"name": "...", "reason": "...", "duration": "12h"}
+//	DELETE /api/bans/{name}         -- Lifts the ban of a player.
+//	POST   /api/command             -- Runs a command as the console: {"command": "..."}
+//	GET    /api/worlds              -- Lists the worlds of the server.
+//	GET    /api/console             -- Streams the console over a WebSocket.
+//
+// The console WebSocket first sends the lines logged recently and then every
+// line logged, each as a JSON object. Text messages sent to it are run as
+// commands by the console.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/moderation"
+)
+
+// Config holds the settings of a Server.
+type Config struct {
+	// Address is the address that the Server listens on, such as
+	// "127.0.0.1:8080".
+	Address string
+	// Token is the secret that every request must be authenticated with. A
+	// Server cannot be started without a Token.
+	Token string
+	// Log is the Logger that actions taken through the API are logged with.
+	Log *slog.Logger
+	// Console is the Console streamed over the /api/console WebSocket. If nil,
+	// the console cannot be streamed.
+	Console *Console
+	// Moderation is used to ban players. If nil, the /api/bans endpoints are
+	// not available.
+	Moderation *moderation.Manager
+	// AllowedOrigins holds the origins, such as "https://panel.example.com",
+	// of web pages that may open the /api/console WebSocket, besides pages
+	// served from the Address of the Server itself. Clients that do not send
+	// an Origin header, such as bots, are always allowed.
+	AllowedOrigins []string
+}
+
+// Server is an HTTP server that serves the admin API of a server.Server.
+type Server struct {
+	conf Config
+	srv  *server.Server
+	http *http.Server
+	// closing is closed when the Server is closed, so that console
+	// WebSockets, which are not closed by http.Server.Close, stop too.
+	closing chan struct{}
+	once    sync.Once
+}
+
+// New creates a Server that serves the admin API of the server.Server
+// passed. The Server starts serving once Listen is called.
+func (conf Config) New(srv *server.Server) *Server {
+	s := &Server{conf: conf, srv: srv, closing: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/players", s.players)
+	mux.HandleFunc("POST /api/players/{name}/kick", s.kick)
+	if conf.Moderation != nil {
+		mux.HandleFunc("GET /api/bans", s.bans)
+		mux.HandleFunc("POST /api/bans", s.ban)
+		mux.HandleFunc("DELETE /api/bans/{name}", s.unban)
+	}
+	mux.HandleFunc("POST /api/command", s.command)
+	mux.HandleFunc("GET /api/worlds", s.worlds)
+	if conf.Console != nil {
+		mux.Handle("GET /api/console", s.console())
+	}
+	s.http = &http.Server{Addr: conf.Address, Handler: s.authenticate(mux), ReadHeaderTimeout: time.Second * 10}
+	return s
+}
+
+// Listen starts listening on the Address of the Config of the Server and
+// serves the API on a separate goroutine. An error is returned if no Token
+// is set or if the Server could not listen on its Address.
+func (s *Server) Listen() error {
+	if s.conf.Token == "" {
+		return errors.New("admin: no token set")
+	}
+	l, err := net.Listen("tcp", s.conf.Address)
+	if err != nil {
+		return fmt.Errorf("admin: listen: %w", err)
+	}
+	go func() {
+		if err := s.http.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.conf.Log.Error("Error serving admin API.", "err", err)
+		}
+	}()
+	s.conf.Log.Info("Serving admin API.", "addr", l.Addr().String())
+	return nil
+}
+
+// Close stops the Server, closing all connections including console
+// WebSockets.
+func (s *Server) Close() error {
+	s.once.Do(func() { close(s.closing) })
+	return s.http.Close()
+}
+
+// authenticate wraps an http.Handler so that it only serves requests that
+// carry the Token of the Server. The Token is read from the Authorization
+// header, or from the token query parameter for WebSocket upgrades of
+// /api/console, so that it does not end up in logs for other requests.
+func (s *Server) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok && r.URL.Path == "/api/console" && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			token = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.conf.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON writes v as JSON to the http.ResponseWriter passed with the
+// status code passed.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error message as JSON to the http.ResponseWriter
+// passed with the status code passed.
+func writeError(w http.ResponseWriter, status int, format string, a ...any) {
+	writeJSON(w, status, map[string]string{"error": fmt.Sprintf(format, a...)})
+}
+
+// readJSON decodes the JSON body of a request into v. v is left unchanged if
+// the body is empty. If the body could not be decoded, an error is written to
+// the http.ResponseWriter and false is returned.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "decode request: %v", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	s := &Server{conf: Config{Token: "s3cret"}}
+	h := s.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		want   int
+	}{
+		{"header", "/api/players", http.Header{"Authorization": {"Bearer s3cret"}}, http.StatusOK},
+		{"wrong header", "/api/players", http.Header{"Authorization": {"Bearer wrong"}}, http.StatusUnauthorized},
+		{"no token", "/api/players", nil, http.StatusUnauthorized},
+		{"query on other endpoint", "/api/players?token=s3cret", nil, http.StatusUnauthorized},
+		{"query on console without upgrade", "/api/console?token=s3cret", nil, http.StatusUnauthorized},
+		{"query on console upgrade", "/api/console?token=s3cret", http.Header{"Upgrade": {"websocket"}}, http.StatusOK},
+		{"wrong query on console upgrade", "/api/console?token=wrong", http.Header{"Upgrade": {"websocket"}}, http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, test.path, nil)
+			for k, v := range test.header {
+				r.Header[k] = v
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != test.want {
+				t.Errorf("got status %v, want %v", w.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	s := &Server{conf: Config{AllowedOrigins: []string{"https://panel.example.com"}}}
+	for origin, allowed := range map[string]bool{
+		"":                          true,
+		"https://panel.example.com": true,
+		"http://admin.local:8080":   true,
+		"https://evil.example.com":  false,
+		"http://admin.local:9090":   false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "http://admin.local:8080/api/console", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if err := s.checkOrigin(r); (err == nil) != allowed {
+			t.Errorf("checkOrigin(%q): got error %v, want allowed %v", origin, err, allowed)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/logging"
+	"golang.org/x/net/websocket"
+)
+
+// backlog is the amount of lines logged recently that a Console keeps, so
+// that they may be sent to clients as soon as they start streaming it.
+const backlog = 100
+
+// Line is a line logged to a Console.
+type Line struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// Console keeps the lines logged recently and streams them to the clients of
+// the /api/console WebSocket. Lines are logged to a Console using the
+// slog.Handler returned by its Handler method, which should be used for the
+// Log of the server.Config of a server.Server, so that everything that the
+// server logs may be streamed. A Console is safe for concurrent use.
+type Console struct {
+	mu    sync.Mutex
+	lines []Line
+	subs  map[chan Line]struct{}
+}
+
+// NewConsole creates an empty Console.
+func NewConsole() *Console {
+	return &Console{subs: make(map[chan Line]struct{})}
+}
+
+// Handler returns a slog.Handler that passes records to the slog.Handler h
+// and adds those that h handles to the Console.
+func (c *Console) Handler(h slog.Handler) slog.Handler {
+	return consoleHandler{c: c, h: h}
+}
+
+// add stores a line logged and passes it to all subscribers. Subscribers
+// that cannot keep up miss lines rather than blocking the caller.
+func (c *Console) add(l Line) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.lines) == backlog {
+		c.lines = append(c.lines[:0], c.lines[1:]...)
+	}
+	c.lines = append(c.lines, l)
+	for ch := range c.subs {
+		select {
+		case ch <- l:
+		default:
+		}
+	}
+}
+
+// consoleHandler is the slog.Handler returned by Console.Handler.
+type consoleHandler struct {
+	c *Console
+	h slog.Handler
+	// attrs holds the attributes added using WithAttrs, with the keys of
+	// attributes in groups prefixed by the names of the groups.
+	attrs  []slog.Attr
+	prefix string
+}
+
+// Enabled ...
+func (h consoleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level)
+}
+
+// Handle ...
+func (h consoleHandler) Handle(ctx context.Context, r slog.Record) error {
+	l := Line{Time: r.Time, Level: strings.ToLower(r.Level.String()), Message: r.Message}
+	if len(h.attrs) > 0 || r.NumAttrs() > 0 {
+		l.Attrs = make(map[string]any, len(h.attrs)+r.NumAttrs())
+		for _, a := range h.attrs {
+			addAttr(l.Attrs, "", a)
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			addAttr(l.Attrs, h.prefix, a)
+			return true
+		})
+	}
+	err := h.h.Handle(ctx, r)
+	h.c.add(l)
+	return err
+}
+
+// WithAttrs ...
+func (h consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.h = h.h.WithAttrs(attrs)
+	all := slices.Clip(h.attrs)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		all = append(all, a)
+	}
+	h.attrs = all
+	return h
+}
+
+// WithGroup ...
+func (h consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h.h = h.h.WithGroup(name)
+	h.prefix += name + "."
+	return h
+}
+
+// addAttr adds an attribute to the attributes of a Line, converting its value
+// to one that may be encoded as JSON.
+func addAttr(m map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		for _, ga := range v.Group() {
+			addAttr(m, prefix+a.Key+".", ga)
+		}
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool, slog.KindTime:
+		m[prefix+a.Key] = v.Any()
+	default:
+		m[prefix+a.Key] = v.String()
+	}
+}
+
+// subscribe returns the lines logged recently and a channel that all lines
+// logged afterwards are sent to, until unsubscribe is called.
+func (c *Console) subscribe() (recent []Line, lines <-chan Line, unsubscribe func()) {
+	ch := make(chan Line, backlog)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subs[ch] = struct{}{}
+	return append([]Line(nil), c.lines...), ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subs, ch)
+	}
+}
+
+// checkOrigin checks if the WebSocket handshake passed was sent from an
+// allowed origin, so that other web pages cannot use the token that a
+// browser of an administrator passes. Requests are authenticated with the
+// token of the Server, so clients without an Origin header, such as bots,
+// are accepted.
+func (s *Server) checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(s.conf.AllowedOrigins, origin) {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	return fmt.Errorf("origin %v not allowed", origin)
+}
+
+// console returns the handler of GET /api/console, which streams the
+// Console of the Server over a WebSocket and runs text messages received as
+// commands of the console.
+func (s *Server) console() websocket.Server {
+	return websocket.Server{
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			return s.checkOrigin(r)
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			recent, lines, unsubscribe := s.conf.Console.subscribe()
+			defer unsubscribe()
+
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				for {
+					var line string
+					if err := websocket.Message.Receive(ws, &line); err != nil {
+						return
+					}
+					if line = strings.TrimPrefix(strings.TrimSpace(line), "/"); line == "" {
+						continue
+					}
+					logging.With(s.conf.Log, logging.Commands).Info("Ran command.", "source", sourceName, "command", line)
+					s.srv.ExecuteConsoleCommand(line)
+				}
+			}()
+			for _, l := range recent {
+				if err := websocket.JSON.Send(ws, l); err != nil {
+					return
+				}
+			}
+			for {
+				select {
+				case l := <-lines:
+					if err := websocket.JSON.Send(ws, l); err != nil {
+						return
+					}
+				case <-closed:
+					return
+				case <-s.closing:
+					return
+				}
+			}
+		},
+	}
+}
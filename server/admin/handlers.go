@@ -0,0 +1,207 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/logging"
+	"github.com/df-mc/dragonfly/server/moderation"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// sourceName is the name of the source of bans and other actions taken
+// through the API, as it appears in logs.
+const sourceName = "Admin API"
+
+// playerInfo is the JSON representation of a player online.
+type playerInfo struct {
+	Name      string     `json:"name"`
+	UUID      string     `json:"uuid"`
+	XUID      string     `json:"xuid"`
+	LatencyMS int64      `json:"latency_ms"`
+	Health    float64    `json:"health"`
+	World     string     `json:"world"`
+	Position  [3]float64 `json:"position"`
+}
+
+// players serves GET /api/players.
+func (s *Server) players(w http.ResponseWriter, _ *http.Request) {
+	players := s.srv.Players()
+	infos := make([]playerInfo, 0, len(players))
+	for _, p := range players {
+		info := playerInfo{
+			Name:      p.Name(),
+			UUID:      p.UUID().String(),
+			XUID:      p.XUID(),
+			LatencyMS: p.Latency().Milliseconds(),
+			Health:    p.Health(),
+			Position:  p.Position(),
+		}
+		if wo := p.World(); wo != nil {
+			info.World = wo.Name()
+		}
+		infos = append(infos, info)
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// kick serves POST /api/players/{name}/kick.
+func (s *Server) kick(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	p, ok := s.srv.PlayerByName(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "player %v is not online", r.PathValue("name"))
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "Kicked from the server."
+	}
+	p.Disconnect(req.Reason)
+	s.conf.Log.Info("Kicked player.", "source", sourceName, "name", p.Name(), "reason", req.Reason)
+	writeJSON(w, http.StatusOK, map[string]string{"kicked": p.Name()})
+}
+
+// banInfo is the JSON representation of a moderation.Ban.
+type banInfo struct {
+	Name    string     `json:"name"`
+	Reason  string     `json:"reason"`
+	Source  string     `json:"source"`
+	Created time.Time  `json:"created"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// newBanInfo converts a moderation.Ban to a banInfo.
+func newBanInfo(b moderation.Ban) banInfo {
+	info := banInfo{Name: b.Target, Reason: b.Reason, Source: b.Source, Created: b.Created}
+	if !b.Expires.IsZero() {
+		info.Expires = &b.Expires
+	}
+	return info
+}
+
+// bans serves GET /api/bans.
+func (s *Server) bans(w http.ResponseWriter, _ *http.Request) {
+	bans := s.conf.Moderation.Bans()
+	infos := make([]banInfo, 0, len(bans))
+	for _, b := range bans {
+		infos = append(infos, newBanInfo(b))
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// ban serves POST /api/bans. Players banned that are online are disconnected.
+func (s *Server) ban(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name     string `json:"name"`
+		Reason   string `json:"reason"`
+		Duration string `json:"duration"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name must be set")
+		return
+	}
+	var d time.Duration
+	if req.Duration != "" {
+		var err error
+		if d, err = time.ParseDuration(req.Duration); err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid duration %q", req.Duration)
+			return
+		}
+	}
+	if err := s.conf.Moderation.Ban(req.Name, req.Reason, sourceName, d); err != nil {
+		writeError(w, http.StatusInternalServerError, "ban %v: %v", req.Name, err)
+		return
+	}
+	b, ok := s.conf.Moderation.Banned(req.Name)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "ban %v: ban was not stored", req.Name)
+		return
+	}
+	for _, p := range s.srv.Players() {
+		if strings.EqualFold(p.Name(), req.Name) {
+			p.Disconnect(b.Message())
+		}
+	}
+	s.conf.Log.Info("Banned player.", "source", sourceName, "name", req.Name)
+	writeJSON(w, http.StatusOK, newBanInfo(b))
+}
+
+// unban serves DELETE /api/bans/{name}.
+func (s *Server) unban(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	ok, err := s.conf.Moderation.Unban(name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unban %v: %v", name, err)
+		return
+	} else if !ok {
+		writeError(w, http.StatusNotFound, "%v is not banned", name)
+		return
+	}
+	s.conf.Log.Info("Unbanned player.", "source", sourceName, "name", name)
+	writeJSON(w, http.StatusOK, map[string]string{"unbanned": name})
+}
+
+// command serves POST /api/command. The messages and errors output by the
+// command are returned.
+func (s *Server) command(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Command string `json:"command"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	o, err := s.srv.RunConsoleCommand(req.Command)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+	logging.With(s.conf.Log, logging.Commands).Info("Ran command.", "source", sourceName, "command", strings.TrimPrefix(strings.TrimSpace(req.Command), "/"))
+	messages, errs := append([]string{}, o.Messages()...), make([]string, 0, o.ErrorCount())
+	for _, err := range o.Errors() {
+		errs = append(errs, err.Error())
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"messages": messages, "errors": errs})
+}
+
+// worldInfo is the JSON representation of a world.World.
+type worldInfo struct {
+	Name         string `json:"name"`
+	Dimension    string `json:"dimension"`
+	Time         int    `json:"time"`
+	Spawn        [3]int `json:"spawn"`
+	LoadedChunks int    `json:"loaded_chunks"`
+	Entities     int    `json:"entities"`
+	Players      int    `json:"players"`
+}
+
+// worlds serves GET /api/worlds.
+func (s *Server) worlds(w http.ResponseWriter, _ *http.Request) {
+	players := map[*world.World]int{}
+	for _, p := range s.srv.Players() {
+		players[p.World()]++
+	}
+	worlds := s.srv.Worlds()
+	infos := make([]worldInfo, 0, len(worlds))
+	for _, wo := range worlds {
+		infos = append(infos, worldInfo{
+			Name:         wo.Name(),
+			Dimension:    fmt.Sprint(wo.Dimension()),
+			Time:         wo.Time(),
+			Spawn:        wo.Spawn(),
+			LoadedChunks: wo.LoadedChunks(),
+			Entities:     len(wo.Entities()),
+			Players:      players[wo],
+		})
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
@@ -25,3 +25,19 @@ type allower struct{}
 func (allower) Allow(net.Addr, login.IdentityData, login.ClientData) (string, bool) {
 	return "", true
 }
+
+// allowers is an Allower that only allows a connection if all of its Allowers
+// allow it. The disconnect message of the first Allower refusing the
+// connection is used.
+type allowers []Allower
+
+// Allow calls Allow on all Allowers and returns false as soon as one of them
+// refuses the connection.
+func (a allowers) Allow(addr net.Addr, d login.IdentityData, c login.ClientData) (string, bool) {
+	for _, allower := range a {
+		if msg, ok := allower.Allow(addr, d, c); !ok {
+			return msg, false
+		}
+	}
+	return "", true
+}
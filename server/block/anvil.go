@@ -13,6 +13,7 @@ import (
 type Anvil struct {
 	gravityAffected
 	transparent
+	sourceWaterDisplacer
 
 	// Type is the type of anvil.
 	Type AnvilType
@@ -25,6 +26,11 @@ func (a Anvil) Model() world.BlockModel {
 	return model.Anvil{Facing: a.Facing}
 }
 
+// SideClosed ...
+func (Anvil) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
 // BreakInfo ...
 func (a Anvil) BreakInfo() BreakInfo {
 	return newBreakInfo(5, pickaxeHarvestable, pickaxeEffective, oneOf(a)).withBlastResistance(6000)
@@ -75,8 +81,9 @@ func (a Anvil) Break() world.Block {
 }
 
 // Landed is called when a falling anvil hits the ground, used to, for example, play a sound.
-func (Anvil) Landed(w *world.World, pos cube.Pos) {
+func (a Anvil) Landed(w *world.World, pos cube.Pos) world.Block {
 	w.PlaySound(pos.Vec3Centre(), sound.AnvilLand{})
+	return a
 }
 
 // EncodeItem ...
@@ -43,7 +43,7 @@ func (a Attachment) RotateLeft() Attachment {
 
 // RotateRight rotates the Attachment the right way around by 90 degrees.
 func (a Attachment) RotateRight() Attachment {
-	return Attachment{hanging: a.hanging, facing: a.facing.RotateLeft(), o: a.o.RotateLeft()}
+	return Attachment{hanging: a.hanging, facing: a.facing.RotateRight(), o: a.o.RotateRight()}
 }
 
 // Rotation returns the rotation of the Attachment, based on the orientation if it's a StandingAttachment, or the
@@ -0,0 +1,205 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"time"
+)
+
+// maxBambooHeight is the maximum height a bamboo stalk can grow to naturally.
+const maxBambooHeight = 16
+
+// Bamboo is a fast growing plant found in jungles. Bamboo grows upwards from a BambooSapling, forming a tall
+// stalk.
+type Bamboo struct {
+	transparent
+
+	// Thick specifies if the bamboo stalk is thick. Bamboo stalks grow thick once they are a few blocks tall.
+	Thick bool
+	// LeafSize is the size of the leaves on the bamboo.
+	LeafSize BambooLeafSize
+	// Ready specifies if the bamboo has stopped growing. Only the top of a bamboo stalk grows.
+	Ready bool
+}
+
+// UseOnBlock ...
+func (b Bamboo) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, b)
+	if !used {
+		return false
+	}
+	switch below := w.Block(pos.Side(cube.FaceDown)).(type) {
+	case Bamboo:
+		place(w, pos, Bamboo{Thick: below.Thick}, user, ctx)
+	case BambooSapling:
+		place(w, pos, Bamboo{}, user, ctx)
+	default:
+		if !bambooSoil(below) {
+			return false
+		}
+		place(w, pos, BambooSapling{}, user, ctx)
+	}
+	return placed(ctx)
+}
+
+// NeighbourUpdateTick ...
+func (b Bamboo) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	switch below := w.Block(pos.Side(cube.FaceDown)).(type) {
+	case Bamboo, BambooSapling:
+	default:
+		if !bambooSoil(below) {
+			w.SetBlock(pos, nil, nil)
+			w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+			dropItem(w, item.NewStack(b, 1), pos.Vec3Centre())
+		}
+	}
+}
+
+// RandomTick ...
+func (b Bamboo) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if b.Ready || r.Intn(3) != 0 {
+		return
+	}
+	above := pos.Side(cube.FaceUp)
+	if _, ok := w.Block(above).(Air); !ok || above.OutOfBounds(w.Range()) || w.Light(above) < 9 {
+		return
+	}
+	if height := bambooHeightBelow(pos, w) + 1; height < maxBambooHeight {
+		b.grow(pos, w, r, height)
+	}
+}
+
+// BoneMeal ...
+func (b Bamboo) BoneMeal(pos cube.Pos, w *world.World) bool {
+	top := pos
+	for {
+		above, ok := w.Block(top.Side(cube.FaceUp)).(Bamboo)
+		if !ok {
+			break
+		}
+		top, b = top.Side(cube.FaceUp), above
+	}
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	height, grown := bambooHeightBelow(top, w)+1, false
+	for i, n := 0, 1+r.Intn(2); i < n; i++ {
+		if _, ok := w.Block(top.Side(cube.FaceUp)).(Air); !ok || b.Ready || height >= maxBambooHeight {
+			break
+		}
+		b.grow(top, w, r, height)
+		top, height, grown = top.Side(cube.FaceUp), height+1, true
+		b, _ = w.Block(top).(Bamboo)
+	}
+	return grown
+}
+
+// grow grows a new bamboo block on top of the bamboo at the position passed, which is at the height passed in
+// its stalk. The leaves of the bamboo below are updated so that the stalk carries large leaves at the top and
+// small leaves right below those.
+func (b Bamboo) grow(pos cube.Pos, w *world.World, r *rand.Rand, height int) {
+	below, belowOk := w.Block(pos.Side(cube.FaceDown)).(Bamboo)
+	belowTwo, belowTwoOk := w.Block(pos.Side(cube.FaceDown).Side(cube.FaceDown)).(Bamboo)
+
+	leaves := NoBambooLeaves()
+	if height >= 1 {
+		if !belowOk || below.LeafSize == NoBambooLeaves() {
+			leaves = SmallBambooLeaves()
+		} else {
+			leaves = LargeBambooLeaves()
+			if belowTwoOk {
+				below.LeafSize, belowTwo.LeafSize = SmallBambooLeaves(), NoBambooLeaves()
+				w.SetBlock(pos.Side(cube.FaceDown), below, nil)
+				w.SetBlock(pos.Side(cube.FaceDown).Side(cube.FaceDown), belowTwo, nil)
+			}
+		}
+	}
+	ready := (height >= 11 && r.Float64() < 0.25) || height == maxBambooHeight-1
+	w.SetBlock(pos.Side(cube.FaceUp), Bamboo{Thick: b.Thick || belowTwoOk, LeafSize: leaves, Ready: ready}, nil)
+}
+
+// bambooHeightBelow returns the amount of bamboo blocks directly below the position passed, up to
+// maxBambooHeight.
+func bambooHeightBelow(pos cube.Pos, w *world.World) (height int) {
+	for height < maxBambooHeight {
+		pos = pos.Side(cube.FaceDown)
+		if _, ok := w.Block(pos).(Bamboo); !ok {
+			break
+		}
+		height++
+	}
+	return height
+}
+
+// bambooSoil checks if bamboo may be planted on the block passed.
+func bambooSoil(b world.Block) bool {
+	switch b.(type) {
+	case Grass, Dirt, Podzol, Mycelium, Sand, Gravel, Mud, MuddyMangroveRoots:
+		return true
+	}
+	return false
+}
+
+// HasLiquidDrops ...
+func (Bamboo) HasLiquidDrops() bool {
+	return true
+}
+
+// FlammabilityInfo ...
+func (Bamboo) FlammabilityInfo() FlammabilityInfo {
+	return newFlammabilityInfo(60, 60, false)
+}
+
+// BreakInfo ...
+func (b Bamboo) BreakInfo() BreakInfo {
+	return newBreakInfo(1, alwaysHarvestable, func(t item.Tool) bool {
+		return t.ToolType() == item.TypeAxe || t.ToolType() == item.TypeSword
+	}, oneOf(Bamboo{}))
+}
+
+// FuelInfo ...
+func (Bamboo) FuelInfo() item.FuelInfo {
+	return newFuelInfo(time.Second * 5 / 2)
+}
+
+// CompostChance ...
+func (Bamboo) CompostChance() float64 {
+	return 0.5
+}
+
+// Model ...
+func (Bamboo) Model() world.BlockModel {
+	return model.Bamboo{}
+}
+
+// EncodeItem ...
+func (Bamboo) EncodeItem() (name string, meta int16) {
+	return "minecraft:bamboo", 0
+}
+
+// EncodeBlock ...
+func (b Bamboo) EncodeBlock() (string, map[string]any) {
+	thickness := "thin"
+	if b.Thick {
+		thickness = "thick"
+	}
+	return "minecraft:bamboo", map[string]any{
+		"age_bit":                boolByte(b.Ready),
+		"bamboo_leaf_size":       b.LeafSize.String(),
+		"bamboo_stalk_thickness": thickness,
+	}
+}
+
+// allBamboo ...
+func allBamboo() (b []world.Block) {
+	for _, size := range BambooLeafSizes() {
+		for _, thick := range []bool{false, true} {
+			b = append(b, Bamboo{LeafSize: size, Thick: thick})
+			b = append(b, Bamboo{LeafSize: size, Thick: thick, Ready: true})
+		}
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package block
+
+// BambooLeafSize represents the size of the leaves growing on a bamboo stalk.
+type BambooLeafSize struct {
+	bambooLeafSize
+}
+
+type bambooLeafSize uint8
+
+// NoBambooLeaves is the leaf size of bamboo that has no leaves.
+func NoBambooLeaves() BambooLeafSize {
+	return BambooLeafSize{0}
+}
+
+// SmallBambooLeaves is the leaf size of bamboo with small leaves.
+func SmallBambooLeaves() BambooLeafSize {
+	return BambooLeafSize{1}
+}
+
+// LargeBambooLeaves is the leaf size of bamboo with large leaves.
+func LargeBambooLeaves() BambooLeafSize {
+	return BambooLeafSize{2}
+}
+
+// Uint8 returns the bamboo leaf size as a uint8.
+func (s bambooLeafSize) Uint8() uint8 {
+	return uint8(s)
+}
+
+// String ...
+func (s bambooLeafSize) String() string {
+	switch s {
+	case 0:
+		return "no_leaves"
+	case 1:
+		return "small_leaves"
+	case 2:
+		return "large_leaves"
+	}
+	panic("unknown bamboo leaf size")
+}
+
+// BambooLeafSizes returns all bamboo leaf sizes.
+func BambooLeafSizes() []BambooLeafSize {
+	return []BambooLeafSize{NoBambooLeaves(), SmallBambooLeaves(), LargeBambooLeaves()}
+}
@@ -0,0 +1,70 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"math/rand"
+)
+
+// BambooSapling is the block placed when bamboo is planted on soil. It grows into a Bamboo stalk.
+type BambooSapling struct {
+	empty
+	transparent
+
+	// Ready specifies if the bamboo sapling has stopped growing.
+	Ready bool
+}
+
+// NeighbourUpdateTick ...
+func (s BambooSapling) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !bambooSoil(w.Block(pos.Side(cube.FaceDown))) {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: s})
+		dropItem(w, item.NewStack(Bamboo{}, 1), pos.Vec3Centre())
+	}
+}
+
+// RandomTick ...
+func (s BambooSapling) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if s.Ready || r.Intn(3) != 0 {
+		return
+	}
+	above := pos.Side(cube.FaceUp)
+	if _, ok := w.Block(above).(Air); ok && !above.OutOfBounds(w.Range()) && w.Light(above) >= 9 {
+		w.SetBlock(above, Bamboo{LeafSize: SmallBambooLeaves()}, nil)
+	}
+}
+
+// BoneMeal ...
+func (s BambooSapling) BoneMeal(pos cube.Pos, w *world.World) bool {
+	above := pos.Side(cube.FaceUp)
+	if _, ok := w.Block(above).(Air); !ok || above.OutOfBounds(w.Range()) {
+		return false
+	}
+	w.SetBlock(above, Bamboo{LeafSize: SmallBambooLeaves()}, nil)
+	return true
+}
+
+// HasLiquidDrops ...
+func (BambooSapling) HasLiquidDrops() bool {
+	return true
+}
+
+// FlammabilityInfo ...
+func (BambooSapling) FlammabilityInfo() FlammabilityInfo {
+	return newFlammabilityInfo(60, 60, false)
+}
+
+// BreakInfo ...
+func (s BambooSapling) BreakInfo() BreakInfo {
+	return newBreakInfo(1, alwaysHarvestable, func(t item.Tool) bool {
+		return t.ToolType() == item.TypeAxe || t.ToolType() == item.TypeSword
+	}, oneOf(Bamboo{}))
+}
+
+// EncodeBlock ...
+func (s BambooSapling) EncodeBlock() (string, map[string]any) {
+	return "minecraft:bamboo_sapling", map[string]any{"age_bit": boolByte(s.Ready)}
+}
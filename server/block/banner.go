@@ -14,6 +14,7 @@ import (
 type Banner struct {
 	empty
 	transparent
+	sourceWaterDisplacer
 
 	// Colour is the colour of the banner.
 	Colour item.Colour
@@ -30,6 +31,11 @@ func (Banner) MaxCount() int {
 	return 16
 }
 
+// SideClosed ...
+func (Banner) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
 // BreakInfo ...
 func (b Banner) BreakInfo() BreakInfo {
 	return newBreakInfo(1, alwaysHarvestable, axeEffective, oneOf(b))
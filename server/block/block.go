@@ -6,7 +6,6 @@ import (
 
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/customblock"
-	"github.com/df-mc/dragonfly/server/block/model"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
@@ -219,13 +218,8 @@ func (g gravityAffected) Solidifies(cube.Pos, *world.World) bool {
 }
 
 // fall spawns a falling block entity at the given position.
-func (g gravityAffected) fall(b world.Block, pos cube.Pos, w *world.World) {
-	_, air := w.Block(pos.Side(cube.FaceDown)).Model().(model.Empty)
-	_, liquid := w.Liquid(pos.Side(cube.FaceDown))
-	if air || liquid {
-		w.SetBlock(pos, nil, nil)
-		w.AddEntity(w.EntityRegistry().Config().FallingBlock(b, pos.Vec3Centre()))
-	}
+func (g gravityAffected) fall(b Fallable, pos cube.Pos, w *world.World) {
+	Fall(b, pos, w)
 }
 
 // Flammable is an interface for blocks that can catch on fire.
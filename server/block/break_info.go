@@ -4,9 +4,11 @@ import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/enchantment"
+	"github.com/df-mc/dragonfly/server/item/loot"
 	"github.com/df-mc/dragonfly/server/world"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -72,6 +74,28 @@ func BreaksInstantly(b world.Block, i item.Stack) bool {
 	return (t.BaseMiningEfficiency(b)+efficiencyVal)*hasteVal >= hardness*30
 }
 
+// BreakDrops returns the items dropped by the block passed when it is broken using the tool and enchantments
+// passed. If a loot.Table is registered under "blocks/" followed by the name of the block without namespace, such
+// as "blocks/gravel", it is generated in the loot.Context passed instead of the Drops of the block's BreakInfo.
+// BreakDrops returns nil if the block is not Breakable.
+func BreakDrops(b world.Block, t item.Tool, enchantments []item.Enchantment, ctx loot.Context) []item.Stack {
+	breakable, ok := b.(Breakable)
+	if !ok {
+		return nil
+	}
+	name, _ := b.EncodeBlock()
+	if table, ok := loot.Lookup("blocks/" + strings.TrimPrefix(name, "minecraft:")); ok {
+		return table.Generate(ctx)
+	}
+	return breakable.BreakInfo().Drops(t, enchantments)
+}
+
+// isBreakable checks if the block passed implements Breakable.
+func isBreakable(b world.Block) bool {
+	_, ok := b.(Breakable)
+	return ok
+}
+
 // BreakInfo is a struct returned by every block. It holds information on block breaking related data, such as
 // the tool type and tier required to break it.
 type BreakInfo struct {
@@ -47,20 +47,26 @@ func (c Cactus) RandomTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
 		c.Age++
 	} else if c.Age == 15 {
 		c.Age = 0
-		if c.canGrowHere(pos.Side(cube.FaceDown), w, false) {
-			for y := 1; y < 3; y++ {
-				if _, ok := w.Block(pos.Add(cube.Pos{0, y})).(Air); ok {
-					w.SetBlock(pos.Add(cube.Pos{0, y}), Cactus{Age: 0}, nil)
-					break
-				} else if _, ok := w.Block(pos.Add(cube.Pos{0, y})).(Cactus); !ok {
-					break
-				}
-			}
+		above := pos.Side(cube.FaceUp)
+		if _, ok := w.Block(above).(Air); ok && !above.OutOfBounds(w.Range()) && c.height(pos, w) < 3 {
+			// Only the top of a cactus grows, until the cactus is three blocks tall. The cactus breaks if the
+			// block grown is not surrounded by air.
+			w.SetBlock(above, Cactus{}, nil)
 		}
 	}
 	w.SetBlock(pos, c, nil)
 }
 
+// height returns the height of the cactus up to the position passed.
+func (c Cactus) height(pos cube.Pos, w *world.World) int {
+	height := 1
+	for _, ok := w.Block(pos.Side(cube.FaceDown)).(Cactus); ok; _, ok = w.Block(pos.Side(cube.FaceDown)).(Cactus) {
+		pos = pos.Side(cube.FaceDown)
+		height++
+	}
+	return height
+}
+
 // canGrowHere implements logic to check if cactus can live/grow here.
 func (c Cactus) canGrowHere(pos cube.Pos, w *world.World, recursive bool) bool {
 	for _, face := range cube.HorizontalFaces() {
@@ -6,9 +6,11 @@ import (
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/inventory"
+	"github.com/df-mc/dragonfly/server/item/loot"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +30,9 @@ type Chest struct {
 	// CustomName is the custom name of the chest. This name is displayed when the chest is opened, and may
 	// include colour codes.
 	CustomName string
+	// LootTable is the name of the loot.Table used to fill the chest the first time it is opened, such as
+	// "chests/simple_dungeon". The LootTable is cleared once the chest has been filled.
+	LootTable string
 
 	paired       bool
 	pairX, pairZ int
@@ -128,6 +133,10 @@ func (c Chest) RemoveViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
 // Activate ...
 func (c Chest) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
 	if opener, ok := u.(ContainerOpener); ok {
+		if c.LootTable != "" {
+			c.fillLoot(u)
+			w.SetBlock(pos, c, nil)
+		}
 		if c.paired && c.pairInv == nil {
 			if ch, pair, ok := c.Pair(w, pos, c.PairPos(pos)); ok {
 				w.SetBlock(pos, ch, nil)
@@ -142,6 +151,31 @@ func (c Chest) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User,
 	return false
 }
 
+// fillLoot fills the inventory of the chest with loot generated from its LootTable and clears the LootTable.
+// Items are placed in random empty slots of the chest.
+func (c *Chest) fillLoot(u item.User) {
+	t, ok := loot.Lookup(c.LootTable)
+	c.LootTable = ""
+	if !ok {
+		return
+	}
+	var slots []int
+	for slot := 0; slot < c.inventory.Size(); slot++ {
+		if it, _ := c.inventory.Item(slot); it.Empty() {
+			slots = append(slots, slot)
+		}
+	}
+	rand.Shuffle(len(slots), func(i, j int) {
+		slots[i], slots[j] = slots[j], slots[i]
+	})
+	for i, s := range t.Generate(loot.Context{This: u}) {
+		if i >= len(slots) {
+			break
+		}
+		_ = c.inventory.SetItem(slots[i], s)
+	}
+}
+
 // UseOnBlock ...
 func (c Chest) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
 	pos, _, used = firstReplaceable(w, pos, face, c)
@@ -194,6 +228,7 @@ func (c Chest) DecodeNBT(data map[string]any) any {
 	c = NewChest()
 	c.Facing = facing
 	c.CustomName = nbtconv.String(data, "CustomName")
+	c.LootTable = strings.TrimSuffix(strings.TrimPrefix(nbtconv.String(data, "LootTable"), "loot_tables/"), ".json")
 
 	pairX, ok := data["pairx"]
 	pairZ, ok2 := data["pairz"]
@@ -210,10 +245,10 @@ func (c Chest) DecodeNBT(data map[string]any) any {
 // EncodeNBT ...
 func (c Chest) EncodeNBT() map[string]any {
 	if c.inventory == nil {
-		facing, customName := c.Facing, c.CustomName
+		facing, customName, lootTable := c.Facing, c.CustomName, c.LootTable
 		//noinspection GoAssignmentToReceiver
 		c = NewChest()
-		c.Facing, c.CustomName = facing, customName
+		c.Facing, c.CustomName, c.LootTable = facing, customName, lootTable
 	}
 	m := map[string]any{
 		"Items": nbtconv.InvToNBT(c.inventory),
@@ -222,6 +257,9 @@ func (c Chest) EncodeNBT() map[string]any {
 	if c.CustomName != "" {
 		m["CustomName"] = c.CustomName
 	}
+	if c.LootTable != "" {
+		m["LootTable"] = "loot_tables/" + c.LootTable + ".json"
+	}
 
 	if c.paired {
 		m["pairx"] = int32(c.pairX)
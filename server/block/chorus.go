@@ -0,0 +1,238 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/loot"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+)
+
+type (
+	// ChorusPlant is a plant that grows on end stone on the outer islands of the End. Chorus plants connect to
+	// each other in all directions and drop chorus fruit when broken.
+	ChorusPlant struct {
+		solid
+		transparent
+	}
+	// ChorusFlower is the growing tip of a chorus plant. Chorus flowers grow upwards and branch out into new
+	// chorus flowers until they die.
+	ChorusFlower struct {
+		solid
+		transparent
+
+		// Age is the age of the chorus flower. Values range from 0 to 5. A chorus flower with an age of 5 is
+		// dead and no longer grows.
+		Age int
+	}
+)
+
+// NeighbourUpdateTick ...
+func (c ChorusPlant) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !c.supported(pos, w) {
+		breakChorus(c, pos, w)
+	}
+}
+
+// supported checks if the chorus plant at the position passed is supported by end stone or by another chorus
+// plant, either below it or next to it.
+func (ChorusPlant) supported(pos cube.Pos, w *world.World) bool {
+	_, airAbove := w.Block(pos.Side(cube.FaceUp)).(Air)
+	_, airBelow := w.Block(pos.Side(cube.FaceDown)).(Air)
+	for _, face := range cube.HorizontalFaces() {
+		side := pos.Side(face)
+		if _, ok := w.Block(side).(ChorusPlant); !ok {
+			continue
+		}
+		if !airAbove && !airBelow {
+			// A chorus plant connected vertically never branches out
+			// horizontally.
+			return false
+		}
+		if chorusSoil(w.Block(side.Side(cube.FaceDown))) {
+			return true
+		}
+	}
+	return chorusSoil(w.Block(pos.Side(cube.FaceDown)))
+}
+
+// BreakInfo ...
+func (c ChorusPlant) BreakInfo() BreakInfo {
+	return newBreakInfo(0.4, alwaysHarvestable, axeEffective, func(item.Tool, []item.Enchantment) []item.Stack {
+		if rand.Intn(2) == 0 {
+			return nil
+		}
+		return []item.Stack{item.NewStack(item.ChorusFruit{}, 1)}
+	})
+}
+
+// EncodeItem ...
+func (ChorusPlant) EncodeItem() (name string, meta int16) {
+	return "minecraft:chorus_plant", 0
+}
+
+// EncodeBlock ...
+func (ChorusPlant) EncodeBlock() (string, map[string]any) {
+	return "minecraft:chorus_plant", nil
+}
+
+// UseOnBlock ...
+func (c ChorusFlower) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, c)
+	if !used || !c.supported(pos, w) {
+		return false
+	}
+
+	place(w, pos, c, user, ctx)
+	return placed(ctx)
+}
+
+// NeighbourUpdateTick ...
+func (c ChorusFlower) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !c.supported(pos, w) {
+		breakChorus(c, pos, w)
+	}
+}
+
+// supported checks if the chorus flower at the position passed is supported by end stone or a chorus plant
+// below it, or by a single chorus plant next to it.
+func (ChorusFlower) supported(pos cube.Pos, w *world.World) bool {
+	below := w.Block(pos.Side(cube.FaceDown))
+	if _, ok := below.(ChorusPlant); ok || chorusSoil(below) {
+		return true
+	}
+	if _, ok := below.(Air); !ok {
+		return false
+	}
+	plants := 0
+	for _, face := range cube.HorizontalFaces() {
+		switch w.Block(pos.Side(face)).(type) {
+		case ChorusPlant:
+			plants++
+		case Air:
+		default:
+			return false
+		}
+	}
+	return plants == 1
+}
+
+// RandomTick grows the chorus flower upwards, or makes it branch out into new chorus flowers next to it. The
+// taller the chorus plant below the flower, the less likely it is to grow upwards.
+func (c ChorusFlower) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if c.Age >= 5 {
+		return
+	}
+	up := pos.Side(cube.FaceUp)
+	if _, ok := w.Block(up).(Air); !ok || up.OutOfBounds(w.Range()) {
+		return
+	}
+	grow, onSoil := false, false
+	switch below := w.Block(pos.Side(cube.FaceDown)).(type) {
+	case Air:
+		grow = true
+	case ChorusPlant:
+		height := 1
+		for i := 0; i < 4; i++ {
+			b := w.Block(pos.Sub(cube.Pos{0, height + 1}))
+			if _, ok := b.(ChorusPlant); ok {
+				height++
+				continue
+			}
+			onSoil = chorusSoil(b)
+			break
+		}
+		limit := 4
+		if onSoil {
+			limit = 5
+		}
+		grow = height < 2 || height <= r.Intn(limit)
+	default:
+		grow = chorusSoil(below)
+	}
+
+	if _, ok := w.Block(up.Side(cube.FaceUp)).(Air); ok && grow && chorusSurroundedByAir(up, w, unknownFace) {
+		w.SetBlock(pos, ChorusPlant{}, nil)
+		w.SetBlock(up, c, nil)
+		return
+	}
+	if c.Age >= 4 {
+		c.Age = 5
+		w.SetBlock(pos, c, nil)
+		return
+	}
+	branches, branched := r.Intn(4), false
+	if onSoil {
+		branches++
+	}
+	for i := 0; i < branches; i++ {
+		face := cube.HorizontalFaces()[r.Intn(4)]
+		side := pos.Side(face)
+		_, airSide := w.Block(side).(Air)
+		_, airBelow := w.Block(side.Side(cube.FaceDown)).(Air)
+		if airSide && airBelow && chorusSurroundedByAir(side, w, face.Opposite()) {
+			w.SetBlock(side, ChorusFlower{Age: c.Age + 1}, nil)
+			branched = true
+		}
+	}
+	if branched {
+		w.SetBlock(pos, ChorusPlant{}, nil)
+		return
+	}
+	c.Age = 5
+	w.SetBlock(pos, c, nil)
+}
+
+// BreakInfo ...
+func (c ChorusFlower) BreakInfo() BreakInfo {
+	return newBreakInfo(0.4, alwaysHarvestable, axeEffective, oneOf(ChorusFlower{}))
+}
+
+// EncodeItem ...
+func (ChorusFlower) EncodeItem() (name string, meta int16) {
+	return "minecraft:chorus_flower", 0
+}
+
+// EncodeBlock ...
+func (c ChorusFlower) EncodeBlock() (string, map[string]any) {
+	return "minecraft:chorus_flower", map[string]any{"age": int32(c.Age)}
+}
+
+// allChorusFlowers returns all possible states of a chorus flower.
+func allChorusFlowers() (b []world.Block) {
+	for i := 0; i <= 5; i++ {
+		b = append(b, ChorusFlower{Age: i})
+	}
+	return
+}
+
+// chorusSoil checks if chorus plants may grow on the block passed.
+func chorusSoil(b world.Block) bool {
+	_, ok := b.(EndStone)
+	return ok
+}
+
+// chorusSurroundedByAir checks if all horizontal neighbours of the position passed are air, except for the
+// neighbour on the face passed. unknownFace may be passed to check all neighbours.
+func chorusSurroundedByAir(pos cube.Pos, w *world.World, except cube.Face) bool {
+	for _, face := range cube.HorizontalFaces() {
+		if face == except {
+			continue
+		}
+		if _, ok := w.Block(pos.Side(face)).(Air); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// breakChorus breaks the part of a chorus plant at the position passed, dropping its items.
+func breakChorus(b world.Block, pos cube.Pos, w *world.World) {
+	w.SetBlock(pos, nil, nil)
+	w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+	for _, drop := range BreakDrops(b, item.ToolNone{}, nil, loot.Context{}) {
+		dropItem(w, drop, pos.Vec3Centre())
+	}
+}
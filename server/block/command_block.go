@@ -0,0 +1,330 @@
+package block
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// CommandBlock is a block that executes a command when it is activated. Command blocks may only be edited by
+// players in creative mode that have the CommandBlockPermission. Depending on their Type, they are activated
+// by a redstone signal, continuously or by the command block pointing into them.
+type CommandBlock struct {
+	solid
+
+	// Type is the type of the command block, which determines how it is activated.
+	Type CommandBlockType
+	// Facing is the direction that the command block is facing. The chain command block at this side of the
+	// command block is activated after the command block executes its command.
+	Facing cube.Face
+	// Conditional specifies if the command block only executes its command if the command block behind it
+	// executed its command successfully the last time it was activated.
+	Conditional bool
+	// AlwaysActive specifies if the command block is active without a redstone signal.
+	AlwaysActive bool
+	// Powered is true if the command block is currently receiving a redstone signal.
+	Powered bool
+	// ExecuteOnFirstTick specifies if a repeating command block executes its command as soon as it becomes
+	// active, instead of waiting for its TickDelay first.
+	ExecuteOnFirstTick bool
+	// TickDelay is the number of ticks between the command block being activated and the command block
+	// executing its command. For repeating command blocks, it is also the number of ticks between executions.
+	TickDelay int
+
+	// Command is the command executed by the command block, optionally prefixed with a '/'.
+	Command string
+	// CustomName is the name of the command block, used as the name of the command source. If empty, the name
+	// '!' is used.
+	CustomName string
+	// TrackOutput specifies if the output of the command executed is stored in LastOutput.
+	TrackOutput bool
+	// LastOutput is the output of the last command executed by the command block, if TrackOutput is true.
+	LastOutput string
+	// SuccessCount is 1 if the command block executed its command successfully the last time it was activated
+	// and 0 otherwise.
+	SuccessCount int
+}
+
+// CommandBlockPermission is the permission node that a player must have to edit command blocks.
+const CommandBlockPermission = "dragonfly.commandblock"
+
+// maxCommandChainLength is the maximum number of command blocks that execute their command as a result of a
+// single command block being activated.
+const maxCommandChainLength = 65536
+
+// Active checks if the command block is active, either because it is always active or because it is receiving
+// a redstone signal.
+func (c CommandBlock) Active() bool {
+	return c.AlwaysActive || c.Powered
+}
+
+// Activate opens the interface of the command block for users in creative mode that are allowed to edit
+// command blocks.
+func (c CommandBlock) Activate(pos cube.Pos, _ cube.Face, _ *world.World, u item.User, _ *item.UseContext) bool {
+	if gm, ok := u.(interface{ GameMode() world.GameMode }); !ok || !gm.GameMode().CreativeInventory() {
+		return false
+	}
+	if p, ok := u.(cmd.Permissible); ok && !p.HasPermission(CommandBlockPermission) {
+		return false
+	}
+	if opener, ok := u.(ContainerOpener); ok {
+		opener.OpenBlockContainer(pos)
+	}
+	return true
+}
+
+// UseOnBlock ...
+func (c CommandBlock) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, c)
+	if !used {
+		return
+	}
+	c.Facing = calculateAnySidedFace(user, pos, true)
+	c.AlwaysActive = c.Type == ChainCommandBlock()
+	c.TrackOutput = true
+	place(w, pos, c, user, ctx)
+	return placed(ctx)
+}
+
+// RedstoneUpdate activates impulse and repeating command blocks when they start receiving a redstone signal.
+func (c CommandBlock) RedstoneUpdate(pos cube.Pos, w *world.World) {
+	powered := receivedRedstonePower(pos, w)
+	if powered == c.Powered {
+		return
+	}
+	wasActive := c.Active()
+	c.Powered = powered
+	w.SetBlock(pos, c, &world.SetOpts{DisableBlockUpdates: true})
+	if !wasActive && c.Active() {
+		c.Trigger(pos, w)
+	}
+}
+
+// Trigger schedules the execution of the command of an impulse or repeating command block, as happens when it
+// becomes active. Chain command blocks are not triggered this way: They are activated by the command block
+// pointing into them.
+func (c CommandBlock) Trigger(pos cube.Pos, w *world.World) {
+	switch c.Type {
+	case ImpulseCommandBlock():
+		w.ScheduleBlockUpdate(pos, c.delay())
+	case RepeatingCommandBlock():
+		if c.ExecuteOnFirstTick {
+			w.ScheduleBlockUpdate(pos, time.Second/20)
+			return
+		}
+		w.ScheduleBlockUpdate(pos, c.delay())
+	}
+}
+
+// ScheduledTick executes the command of the command block. Repeating command blocks schedule their next
+// execution for as long as they remain active.
+func (c CommandBlock) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	switch c.Type {
+	case RepeatingCommandBlock():
+		if !c.Active() {
+			return
+		}
+		w.ScheduleBlockUpdate(pos, c.delay())
+	case ChainCommandBlock():
+		if !c.Active() {
+			return
+		}
+	}
+	c.Execute(pos, w)
+}
+
+// delay returns the delay after which the command block executes its command after being activated. The delay
+// is always at least one tick.
+func (c CommandBlock) delay() time.Duration {
+	return time.Duration(max(c.TickDelay, 1)) * time.Second / 20
+}
+
+// Execute executes the command of the command block at the cube.Pos passed and then activates the chain of
+// command blocks that the command block points into. Chain command blocks with a TickDelay have the rest of
+// the chain executed after their delay.
+func (c CommandBlock) Execute(pos cube.Pos, w *world.World) {
+	executed := make(map[cube.Pos]struct{})
+	for i := 0; i < maxCommandChainLength; i++ {
+		executed[pos] = struct{}{}
+		c.run(pos, w)
+
+		next := pos.Side(c.Facing)
+		n, ok := w.Block(next).(CommandBlock)
+		if _, loop := executed[next]; loop || !ok || n.Type != ChainCommandBlock() || !n.Active() {
+			return
+		}
+		if n.TickDelay > 0 {
+			w.ScheduleBlockUpdate(next, n.delay())
+			return
+		}
+		pos, c = next, n
+	}
+}
+
+// run runs the command of the command block at the cube.Pos passed and stores the result in the command block.
+// Conditional command blocks fail without running their command if the command block behind them failed.
+func (c CommandBlock) run(pos cube.Pos, w *world.World) {
+	c.SuccessCount = 0
+	if c.Conditional && !c.conditionMet(pos, w) {
+		c.update(pos, w)
+		return
+	}
+	src := &commandBlockSource{pos: pos, w: w, name: c.CustomName}
+	if o := src.execute(c.Command); o != nil {
+		if o.ErrorCount() == 0 {
+			c.SuccessCount = 1
+		}
+		if c.TrackOutput {
+			c.LastOutput = commandOutputString(o)
+		}
+	}
+	c.update(pos, w)
+}
+
+// update stores the command block at the cube.Pos passed, unless its command replaced the command block with
+// a different block.
+func (c CommandBlock) update(pos cube.Pos, w *world.World) {
+	if _, ok := w.Block(pos).(CommandBlock); ok {
+		w.SetBlock(pos, c, &world.SetOpts{DisableBlockUpdates: true})
+	}
+}
+
+// conditionMet checks if the command block behind the command block at the cube.Pos passed executed its command
+// successfully.
+func (c CommandBlock) conditionMet(pos cube.Pos, w *world.World) bool {
+	behind, ok := w.Block(pos.Side(c.Facing.Opposite())).(CommandBlock)
+	return ok && behind.SuccessCount > 0
+}
+
+// BreakInfo ...
+func (c CommandBlock) BreakInfo() BreakInfo {
+	return newBreakInfo(-1, neverHarvestable, nothingEffective, simpleDrops())
+}
+
+// DecodeNBT ...
+func (c CommandBlock) DecodeNBT(data map[string]any) any {
+	c.Command = nbtconv.String(data, "Command")
+	c.CustomName = nbtconv.String(data, "CustomName")
+	c.LastOutput = nbtconv.String(data, "LastOutput")
+	c.TrackOutput = nbtconv.Bool(data, "TrackOutput")
+	c.SuccessCount = int(nbtconv.Int32(data, "SuccessCount"))
+	c.TickDelay = int(nbtconv.Int32(data, "TickDelay"))
+	c.AlwaysActive = nbtconv.Bool(data, "auto")
+	c.Powered = nbtconv.Bool(data, "powered")
+	c.ExecuteOnFirstTick = nbtconv.Bool(data, "ExecuteOnFirstTick")
+	return c
+}
+
+// EncodeNBT ...
+func (c CommandBlock) EncodeNBT() map[string]any {
+	m := map[string]any{
+		"id":                 "CommandBlock",
+		"Command":            c.Command,
+		"LastOutput":         c.LastOutput,
+		"TrackOutput":        boolByte(c.TrackOutput),
+		"SuccessCount":       int32(c.SuccessCount),
+		"TickDelay":          int32(c.TickDelay),
+		"auto":               boolByte(c.AlwaysActive),
+		"powered":            boolByte(c.Powered),
+		"conditionalMode":    boolByte(c.Conditional),
+		"ExecuteOnFirstTick": boolByte(c.ExecuteOnFirstTick),
+		"Version":            int32(38),
+	}
+	if c.CustomName != "" {
+		m["CustomName"] = c.CustomName
+	}
+	return m
+}
+
+// EncodeItem ...
+func (c CommandBlock) EncodeItem() (name string, meta int16) {
+	return "minecraft:" + c.Type.String(), 0
+}
+
+// EncodeBlock ...
+func (c CommandBlock) EncodeBlock() (string, map[string]any) {
+	return "minecraft:" + c.Type.String(), map[string]any{"conditional_bit": boolByte(c.Conditional), "facing_direction": int32(c.Facing)}
+}
+
+// allCommandBlocks returns all possible command blocks.
+func allCommandBlocks() (blocks []world.Block) {
+	for _, t := range CommandBlockTypes() {
+		for _, f := range cube.Faces() {
+			blocks = append(blocks, CommandBlock{Type: t, Facing: f})
+			blocks = append(blocks, CommandBlock{Type: t, Facing: f, Conditional: true})
+		}
+	}
+	return
+}
+
+// commandBlockSource is the cmd.Source of commands executed by a command block. It has every permission and
+// collects the output of the commands it executes.
+type commandBlockSource struct {
+	pos    cube.Pos
+	w      *world.World
+	name   string
+	output *cmd.Output
+}
+
+// execute executes the command line passed with the commandBlockSource as source and returns its output. If
+// the command line is empty, nil is returned.
+func (s *commandBlockSource) execute(commandLine string) *cmd.Output {
+	commandLine = strings.TrimPrefix(strings.TrimSpace(commandLine), "/")
+	if commandLine == "" {
+		return nil
+	}
+	name, args, _ := strings.Cut(commandLine, " ")
+	command, ok := cmd.ByAlias(name)
+	if !ok {
+		o := &cmd.Output{}
+		o.Errorf("Unknown command: %v.", name)
+		return o
+	}
+	command.Execute(args, s)
+	return s.output
+}
+
+// Name returns the name of the command block, or '!' if it has no name.
+func (s *commandBlockSource) Name() string {
+	if s.name == "" {
+		return "!"
+	}
+	return s.name
+}
+
+// Position returns the centre of the command block.
+func (s *commandBlockSource) Position() mgl64.Vec3 {
+	return s.pos.Vec3Centre()
+}
+
+// World returns the world that the command block is in.
+func (s *commandBlockSource) World() *world.World {
+	return s.w
+}
+
+// HasPermission always returns true: Command blocks may run every command.
+func (s *commandBlockSource) HasPermission(string) bool {
+	return true
+}
+
+// SendCommandOutput stores the cmd.Output passed, so that it may be stored in the command block.
+func (s *commandBlockSource) SendCommandOutput(o *cmd.Output) {
+	s.output = o
+}
+
+// commandOutputString joins the messages and errors of the cmd.Output passed into a single string.
+func commandOutputString(o *cmd.Output) string {
+	lines := make([]string, 0, o.MessageCount()+o.ErrorCount())
+	lines = append(lines, o.Messages()...)
+	for _, err := range o.Errors() {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,62 @@
+package block
+
+// CommandBlockType represents the type of command block, which determines how the command block is activated.
+type CommandBlockType struct {
+	commandBlockType
+}
+
+// ImpulseCommandBlock returns the impulse command block type. Impulse command blocks execute their command
+// once every time they are activated.
+func ImpulseCommandBlock() CommandBlockType {
+	return CommandBlockType{0}
+}
+
+// RepeatingCommandBlock returns the repeating command block type. Repeating command blocks execute their
+// command repeatedly for as long as they are active.
+func RepeatingCommandBlock() CommandBlockType {
+	return CommandBlockType{1}
+}
+
+// ChainCommandBlock returns the chain command block type. Chain command blocks execute their command when the
+// command block pointing into them executes its command, provided they are active.
+func ChainCommandBlock() CommandBlockType {
+	return CommandBlockType{2}
+}
+
+// CommandBlockTypes returns all command block types.
+func CommandBlockTypes() []CommandBlockType {
+	return []CommandBlockType{ImpulseCommandBlock(), RepeatingCommandBlock(), ChainCommandBlock()}
+}
+
+type commandBlockType uint8
+
+// Uint8 returns the command block type as a uint8.
+func (t commandBlockType) Uint8() uint8 {
+	return uint8(t)
+}
+
+// Name returns the name of the command block type.
+func (t commandBlockType) Name() string {
+	switch t {
+	case 0:
+		return "Impulse Command Block"
+	case 1:
+		return "Repeating Command Block"
+	case 2:
+		return "Chain Command Block"
+	}
+	panic("unknown command block type")
+}
+
+// String returns the command block type as a string.
+func (t commandBlockType) String() string {
+	switch t {
+	case 0:
+		return "command_block"
+	case 1:
+		return "repeating_command_block"
+	case 2:
+		return "chain_command_block"
+	}
+	panic("unknown command block type")
+}
@@ -23,6 +23,19 @@ func (c ConcretePowder) Solidifies(pos cube.Pos, w *world.World) bool {
 	return water
 }
 
+// Landed turns the concrete powder into concrete if it lands in or next to water.
+func (c ConcretePowder) Landed(w *world.World, pos cube.Pos) world.Block {
+	if c.Solidifies(pos, w) {
+		return Concrete{Colour: c.Colour}
+	}
+	for _, face := range cube.Faces() {
+		if _, ok := w.Block(pos.Side(face)).(Water); ok {
+			return Concrete{Colour: c.Colour}
+		}
+	}
+	return c
+}
+
 // NeighbourUpdateTick ...
 func (c ConcretePowder) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
 	for i := cube.Face(0); i < 6; i++ {
@@ -3,6 +3,7 @@ package block
 import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/loot"
 	"github.com/df-mc/dragonfly/server/world"
 )
 
@@ -28,10 +29,8 @@ func (c crop) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
 	if _, ok := w.Block(pos.Side(cube.FaceDown)).(Farmland); !ok {
 		b := w.Block(pos)
 		w.SetBlock(pos, nil, nil)
-		if breakable, ok := b.(Breakable); ok {
-			for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, []item.Enchantment{}) {
-				dropItem(w, drop, pos.Vec3Centre())
-			}
+		for _, drop := range BreakDrops(b, item.ToolNone{}, nil, loot.Context{}) {
+			dropItem(w, drop, pos.Vec3Centre())
 		}
 	}
 }
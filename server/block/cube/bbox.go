@@ -37,6 +37,12 @@ func (box BBox) GrowVec3(vec mgl64.Vec3) BBox {
 	return BBox{min: box.min.Sub(vec), max: box.max.Add(vec)}
 }
 
+// Mul multiplies all coordinates of the BBox by x and returns the new bounding box. For boxes positioned around
+// the origin, this scales the box by a factor of x.
+func (box BBox) Mul(x float64) BBox {
+	return BBox{min: box.min.Mul(x), max: box.max.Mul(x)}
+}
+
 // Min returns the minimum coordinate of the bounding box.
 func (box BBox) Min() mgl64.Vec3 {
 	return box.min
@@ -0,0 +1,129 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+)
+
+// EndGateway is a portal block found in the End. An end gateway is created every time the ender dragon is
+// defeated, which teleports entities to the outer islands of the End. An end gateway that leads back is
+// created on the outer islands once an entity travels through it for the first time.
+type EndGateway struct {
+	empty
+	transparent
+
+	// ExitPortal is the position that entities entering the end gateway are teleported to.
+	ExitPortal cube.Pos
+	// HasExit specifies if the end gateway has an ExitPortal. If false, the exit of the end gateway is
+	// searched for on the outer islands once an entity enters it.
+	HasExit bool
+}
+
+// endGatewayExitDistance is the distance from the centre of the End at which the exit of an end gateway
+// without an ExitPortal is searched for.
+const endGatewayExitDistance = 1024
+
+// EntityInside teleports the entity passed to the exit of the end gateway.
+func (g EndGateway) EntityInside(pos cube.Pos, w *world.World, e world.Entity) {
+	t, ok := e.(interface{ Teleport(pos mgl64.Vec3) })
+	if !ok {
+		return
+	}
+	if !g.HasExit {
+		g.ExitPortal, g.HasExit = g.findExit(pos, w), true
+		w.SetBlock(pos, g, nil)
+		EndGateway{ExitPortal: pos.Add(cube.Pos{0, 3}), HasExit: true}.Build(g.ExitPortal.Add(cube.Pos{0, 10}), w)
+	}
+	w.PlaySound(pos.Vec3Centre(), sound.Teleport{})
+	t.Teleport(g.ExitPortal.Vec3Middle())
+}
+
+// findExit finds the exit of an end gateway at the position passed that does not yet have one. The exit is
+// placed on top of the first outer island found in the direction of the end gateway from the centre of the
+// End. If no island is found, a small platform of end stone is created to serve as the exit.
+func (g EndGateway) findExit(pos cube.Pos, w *world.World) cube.Pos {
+	dx, dz := float64(pos[0]), float64(pos[2])
+	if dx == 0 && dz == 0 {
+		dx = 1
+	}
+	length := math.Hypot(dx, dz)
+	dx, dz = dx/length, dz/length
+	for i := 0; i < 16; i++ {
+		x, z := int(dx*float64(endGatewayExitDistance+i*16)), int(dz*float64(endGatewayExitDistance+i*16))
+		if y := w.HighestBlock(x, z); y > w.Range().Min() {
+			return cube.Pos{x, y + 1, z}
+		}
+	}
+	exit := cube.Pos{int(dx * endGatewayExitDistance), 75, int(dz * endGatewayExitDistance)}
+	for x := -2; x <= 2; x++ {
+		for z := -2; z <= 2; z++ {
+			w.SetBlock(exit.Add(cube.Pos{x, -1, z}), EndStone{}, nil)
+		}
+	}
+	return exit
+}
+
+// Build places the end gateway at the position passed, together with the bedrock that encloses it above and
+// below.
+func (g EndGateway) Build(pos cube.Pos, w *world.World) {
+	for x := -1; x <= 1; x++ {
+		for y := -2; y <= 2; y++ {
+			for z := -1; z <= 1; z++ {
+				var b world.Block
+				switch {
+				case x == 0 && y == 0 && z == 0:
+					b = g
+				case y == 0:
+				case y == -2 || y == 2:
+					if x == 0 && z == 0 {
+						b = Bedrock{}
+					}
+				case x == 0 || z == 0:
+					b = Bedrock{}
+				}
+				w.SetBlock(pos.Add(cube.Pos{x, y, z}), b, nil)
+			}
+		}
+	}
+}
+
+// LightEmissionLevel ...
+func (EndGateway) LightEmissionLevel() uint8 {
+	return 15
+}
+
+// SideClosed ...
+func (EndGateway) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// BreakInfo ...
+func (g EndGateway) BreakInfo() BreakInfo {
+	return newBreakInfo(-1, neverHarvestable, nothingEffective, simpleDrops()).withBlastResistance(18000000)
+}
+
+// EncodeBlock ...
+func (EndGateway) EncodeBlock() (string, map[string]any) {
+	return "minecraft:end_gateway", nil
+}
+
+// EncodeNBT ...
+func (g EndGateway) EncodeNBT() map[string]any {
+	m := map[string]any{"id": "EndGateway", "Age": int32(0)}
+	if g.HasExit {
+		m["ExitPortal"] = nbtconv.PosToInt32Slice(g.ExitPortal)
+	}
+	return m
+}
+
+// DecodeNBT ...
+func (g EndGateway) DecodeNBT(data map[string]any) any {
+	if _, ok := data["ExitPortal"]; ok {
+		g.ExitPortal, g.HasExit = nbtconv.Pos(data, "ExitPortal"), true
+	}
+	return g
+}
@@ -0,0 +1,29 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// EndPortal is the block that forms the portal to and from the End. An end portal is created in the End when the
+// ender dragon is defeated.
+// TODO: Implement travelling through end portals.
+type EndPortal struct {
+	empty
+	transparent
+}
+
+// LightEmissionLevel ...
+func (EndPortal) LightEmissionLevel() uint8 {
+	return 15
+}
+
+// SideClosed ...
+func (EndPortal) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// EncodeBlock ...
+func (EndPortal) EncodeBlock() (string, map[string]any) {
+	return "minecraft:end_portal", nil
+}
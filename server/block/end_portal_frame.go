@@ -0,0 +1,120 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// EndPortalFrame is the block that makes up the frame of an end portal. End portal frames are found in the portal
+// room of strongholds. Once an eye of ender has been placed in all twelve frames of a portal, the portal opens.
+type EndPortalFrame struct {
+	solid
+	bassDrum
+
+	// Facing is the direction that the end portal frame faces. The frames of an end portal all face towards the
+	// centre of the portal.
+	Facing cube.Direction
+	// Eye specifies if an eye of ender was placed in the end portal frame.
+	Eye bool
+}
+
+// LightEmissionLevel ...
+func (EndPortalFrame) LightEmissionLevel() uint8 {
+	return 1
+}
+
+// Activate ...
+func (f EndPortalFrame) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, ctx *item.UseContext) bool {
+	if f.Eye {
+		return false
+	}
+	if held, _ := u.HeldItems(); held.Empty() {
+		return false
+	} else if _, ok := held.Item().(item.EnderEye); !ok {
+		return false
+	}
+	f.Eye = true
+	w.SetBlock(pos, f, nil)
+	w.PlaySound(pos.Vec3Centre(), sound.EnderEyePlace{})
+	ctx.SubtractFromCount(1)
+
+	if centre, ok := f.portalCentre(pos, w); ok {
+		for x := -1; x <= 1; x++ {
+			for z := -1; z <= 1; z++ {
+				w.SetBlock(centre.Add(cube.Pos{x, 0, z}), EndPortal{}, nil)
+			}
+		}
+		w.PlaySound(centre.Vec3Centre(), sound.EndPortalCreate{})
+	}
+	return true
+}
+
+// portalCentre looks for a complete end portal that includes the end portal frame at the position passed. A portal is
+// complete if all twelve of its frames hold an eye of ender and face towards the centre of the portal. If found, the
+// position of the centre of the portal is returned.
+func (f EndPortalFrame) portalCentre(pos cube.Pos, w *world.World) (cube.Pos, bool) {
+	for offset := -1; offset <= 1; offset++ {
+		centre := shiftPos(shiftPos(pos, f.Facing, 2), f.Facing.RotateRight(), offset)
+		if portalComplete(centre, w) {
+			return centre, true
+		}
+	}
+	return cube.Pos{}, false
+}
+
+// portalComplete checks if all frames around the centre passed hold an eye of ender and face towards the centre.
+func portalComplete(centre cube.Pos, w *world.World) bool {
+	for _, d := range cube.Directions() {
+		for offset := -1; offset <= 1; offset++ {
+			frame, ok := w.Block(shiftPos(shiftPos(centre, d, 2), d.RotateRight(), offset)).(EndPortalFrame)
+			if !ok || !frame.Eye || frame.Facing != d.Opposite() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// shiftPos moves the position passed n blocks in the direction passed. If n is negative, the position is moved in the
+// opposite direction.
+func shiftPos(pos cube.Pos, d cube.Direction, n int) cube.Pos {
+	if n < 0 {
+		d, n = d.Opposite(), -n
+	}
+	for i := 0; i < n; i++ {
+		pos = pos.Side(d.Face())
+	}
+	return pos
+}
+
+// UseOnBlock ...
+func (f EndPortalFrame) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, f)
+	if !used {
+		return
+	}
+	f.Facing = user.Rotation().Direction().Opposite()
+	place(w, pos, f, user, ctx)
+	return placed(ctx)
+}
+
+// EncodeItem ...
+func (EndPortalFrame) EncodeItem() (name string, meta int16) {
+	return "minecraft:end_portal_frame", 0
+}
+
+// EncodeBlock ...
+func (f EndPortalFrame) EncodeBlock() (string, map[string]any) {
+	return "minecraft:end_portal_frame", map[string]any{"minecraft:cardinal_direction": f.Facing.String(), "end_portal_eye_bit": f.Eye}
+}
+
+// allEndPortalFrames ...
+func allEndPortalFrames() (frames []world.Block) {
+	for _, d := range cube.Directions() {
+		frames = append(frames, EndPortalFrame{Facing: d}, EndPortalFrame{Facing: d, Eye: true})
+	}
+	return
+}
@@ -3,7 +3,9 @@ package block
 import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/loot"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
@@ -26,6 +28,9 @@ type ExplosionConfig struct {
 	// DisableItemDrops, when set to true, will prevent any item entities from dropping as a result of blocks being
 	// destroyed.
 	DisableItemDrops bool
+	// ItemDropChance is the chance, between 0 and 1, that a block destroyed by the explosion drops its items. If
+	// left 0, the chance defaults to 1/Size, so that larger explosions drop fewer items.
+	ItemDropChance float64
 
 	// Sound is the sound to play when the explosion is created. If set to nil, this will default to the sound of a
 	// regular explosion.
@@ -90,26 +95,26 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 		math.Ceil(explosionPos[2]+d+1),
 	)
 
+	affectedEntities := make([]world.Entity, 0, 32)
 	for _, e := range w.EntitiesWithin(box.Grow(2), nil) {
 		pos := e.Position()
 		if !e.Type().BBox(e).Translate(pos).IntersectsWith(box) {
 			continue
 		}
-		dist := pos.Sub(pos).Len()
-		if dist >= d {
-			continue
-		}
-		if explodable, ok := e.(ExplodableEntity); ok {
-			impact := (1 - dist/d) * exposure(pos, e)
-			explodable.Explode(explosionPos, impact, c)
+		if _, ok := e.(ExplodableEntity); ok && explosionPos.Sub(pos).Len() < d {
+			affectedEntities = append(affectedEntities, e)
 		}
 	}
 
 	affectedBlocks := make([]cube.Pos, 0, 32)
+	seen := make(map[cube.Pos]struct{}, 32)
 	for _, ray := range rays {
 		pos := explosionPos
 		for blastForce := c.Size * (0.7 + r.Float64()*0.6); blastForce > 0.0; blastForce -= 0.225 {
 			current := cube.PosFromVec3(pos)
+			if current.OutOfBounds(w.Range()) {
+				break
+			}
 			currentBlock := w.Block(current)
 
 			resistance := 0.0
@@ -124,28 +129,53 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 
 			pos = pos.Add(ray)
 			if blastForce -= (resistance/5 + 0.3) * 0.3; blastForce > 0 {
-				affectedBlocks = append(affectedBlocks, current)
+				if _, ok := seen[current]; !ok {
+					seen[current] = struct{}{}
+					affectedBlocks = append(affectedBlocks, current)
+				}
 			}
 		}
 	}
+
+	itemDropChance, spawnFire := c.ItemDropChance, c.SpawnFire
+	if itemDropChance == 0 {
+		itemDropChance = 1 / c.Size
+	}
+	if c.DisableItemDrops {
+		itemDropChance = 0
+	}
+	ctx := event.C()
+	if w.Handler().HandleExplosion(ctx, explosionPos, &affectedEntities, &affectedBlocks, &itemDropChance, &spawnFire); ctx.Cancelled() {
+		return
+	}
+
+	for _, e := range affectedEntities {
+		explodable, ok := e.(ExplodableEntity)
+		if !ok {
+			continue
+		}
+		if dist := explosionPos.Sub(e.Position()).Len(); dist < d {
+			explodable.Explode(explosionPos, (1-dist/d)*exposure(explosionPos, e), c)
+		}
+	}
 	for _, pos := range affectedBlocks {
 		bl := w.Block(pos)
 		if explodable, ok := bl.(Explodable); ok {
 			explodable.Explode(explosionPos, pos, w, c)
-		} else if breakable, ok := bl.(Breakable); ok {
+		} else if _, ok := bl.(Breakable); ok {
 			w.SetBlock(pos, nil, nil)
-			if !c.DisableItemDrops && 1/c.Size > r.Float64() {
-				for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, nil) {
+			if itemDropChance > r.Float64() {
+				for _, drop := range BreakDrops(bl, item.ToolNone{}, nil, loot.Context{Rand: r}) {
 					dropItem(w, drop, pos.Vec3Centre())
 				}
 			}
 		}
 	}
-	if c.SpawnFire {
+	if spawnFire {
 		for _, pos := range affectedBlocks {
 			if r.Intn(3) == 0 {
 				if _, ok := w.Block(pos).(Air); ok && w.Block(pos.Side(cube.FaceDown)).Model().FaceSolid(pos, cube.FaceUp, w) {
-					w.SetBlock(pos, Fire{}, nil)
+					Fire{}.Start(w, pos)
 				}
 			}
 		}
@@ -0,0 +1,58 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Fallable represents a block that is affected by gravity, such as sand or gravel. A Fallable block turns into
+// a falling block entity when Fall is called while there is nothing below it, and turns back into a block once
+// it lands.
+type Fallable interface {
+	world.Block
+	// Solidifies returns whether the falling block can solidify at the position it is currently in. If so,
+	// the block will immediately stop falling.
+	Solidifies(pos cube.Pos, w *world.World) bool
+}
+
+// Landable represents a Fallable block that reacts to landing after a fall, for example by playing a sound.
+type Landable interface {
+	Fallable
+	// Landed is called when the falling block lands at the position passed. The block returned is placed at the
+	// position, which may be used, for example, for concrete powder turning into concrete when landing in
+	// water. If nil is returned, no block is placed.
+	Landed(w *world.World, pos cube.Pos) world.Block
+}
+
+// FallDamager represents a Fallable block that damages entities it lands on, such as anvils.
+type FallDamager interface {
+	Fallable
+	// Damage returns the damage dealt per block fallen and the maximum damage that the block can deal.
+	Damage() (damagePerBlock, maxDamage float64)
+}
+
+// FallBreakable represents a FallDamager that may get damaged when it lands on entities, such as anvils.
+type FallBreakable interface {
+	FallDamager
+	// Break returns the block after it was damaged by landing. Air is returned if the block is destroyed.
+	Break() world.Block
+}
+
+// Fall makes the Fallable block at the position passed start falling if the block below it is air or a
+// liquid. The block is removed and a falling block entity is spawned in its place. Fall returns true if the
+// block started falling. Fall is typically called from the NeighbourUpdateTick method of a Fallable block.
+func Fall(b Fallable, pos cube.Pos, w *world.World) bool {
+	below := pos.Side(cube.FaceDown)
+	if below.OutOfBounds(w.Range()) {
+		return false
+	}
+	_, air := w.Block(below).Model().(model.Empty)
+	_, liquid := w.Liquid(below)
+	if !air && !liquid {
+		return false
+	}
+	w.SetBlock(pos, nil, nil)
+	w.AddEntity(w.EntityRegistry().Config().FallingBlock(b, pos.Vec3Centre()))
+	return true
+}
@@ -68,6 +68,10 @@ func (f Fire) burn(from, to cube.Pos, w *world.World, r *rand.Rand, chanceBound
 			f.spread(from, to, w, r)
 			return
 		}
+		ctx := event.C()
+		if w.Handler().HandleBlockBurn(ctx, to); ctx.Cancelled() {
+			return
+		}
 		if t, ok := flammable.(TNT); ok {
 			t.Ignite(to, w, nil)
 			return
@@ -90,7 +94,7 @@ func rainingAround(pos cube.Pos, w *world.World) bool {
 
 // tick ...
 func (f Fire) tick(pos cube.Pos, w *world.World, r *rand.Rand) {
-	if f.Type == SoulFire() {
+	if f.Type == SoulFire() || !world.GameRuleDoFireTick.Value(w) {
 		return
 	}
 	infinitelyBurns := infinitelyBurning(pos, w)
@@ -273,6 +277,7 @@ func (f Fire) Start(w *world.World, pos cube.Pos) {
 		below := w.Block(pos.Side(cube.FaceDown))
 		if below.Model().FaceSolid(pos, cube.FaceUp, w) || neighboursFlammable(pos, w) {
 			w.SetBlock(pos, Fire{}, nil)
+			w.ScheduleBlockUpdate(pos, time.Duration(30+rand.Intn(10))*time.Second/20)
 		}
 	}
 }
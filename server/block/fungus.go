@@ -0,0 +1,97 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+)
+
+// Fungus is a non-solid plant found in crimson and warped forests in the Nether. A fungus planted on nylium of its
+// own type may be grown into a huge fungus using bone meal.
+type Fungus struct {
+	empty
+	transparent
+	sourceWaterDisplacer
+
+	// Warped specifies if the fungus is a warped fungus. If false, the fungus is a crimson fungus.
+	Warped bool
+}
+
+// Wood returns the WoodType of the huge fungus that the fungus grows into.
+func (f Fungus) Wood() WoodType {
+	if f.Warped {
+		return WarpedWood()
+	}
+	return CrimsonWood()
+}
+
+// BoneMeal ...
+func (f Fungus) BoneMeal(pos cube.Pos, w *world.World) bool {
+	if n, ok := w.Block(pos.Side(cube.FaceDown)).(Nylium); !ok || n.Warped != f.Warped {
+		// Fungi only grow on nylium of their own type.
+		return false
+	}
+	if rand.Float64() < 0.4 {
+		if g, ok := treeGrower(f.Wood()); ok {
+			g.GrowTree(pos, w, rand.New(rand.NewSource(rand.Int63())))
+		}
+	}
+	return true
+}
+
+// NeighbourUpdateTick ...
+func (f Fungus) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !supportsVegetation(f, w.Block(pos.Side(cube.FaceDown))) {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: f})
+		dropItem(w, item.NewStack(f, 1), pos.Vec3Centre())
+	}
+}
+
+// UseOnBlock ...
+func (f Fungus) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, f)
+	if !used {
+		return false
+	}
+	if !supportsVegetation(f, w.Block(pos.Side(cube.FaceDown))) {
+		return false
+	}
+
+	place(w, pos, f, user, ctx)
+	return placed(ctx)
+}
+
+// HasLiquidDrops ...
+func (Fungus) HasLiquidDrops() bool {
+	return true
+}
+
+// BreakInfo ...
+func (f Fungus) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(f))
+}
+
+// CompostChance ...
+func (Fungus) CompostChance() float64 {
+	return 0.65
+}
+
+// EncodeItem ...
+func (f Fungus) EncodeItem() (name string, meta int16) {
+	if f.Warped {
+		return "minecraft:warped_fungus", 0
+	}
+	return "minecraft:crimson_fungus", 0
+}
+
+// EncodeBlock ...
+func (f Fungus) EncodeBlock() (string, map[string]any) {
+	if f.Warped {
+		return "minecraft:warped_fungus", nil
+	}
+	return "minecraft:crimson_fungus", nil
+}
@@ -37,7 +37,7 @@ func init() {
 // SoilFor ...
 func (g Grass) SoilFor(block world.Block) bool {
 	switch block.(type) {
-	case ShortGrass, Fern, DoubleTallGrass, Flower, DoubleFlower, NetherSprouts, SugarCane:
+	case ShortGrass, Fern, DoubleTallGrass, Flower, DoubleFlower, NetherSprouts, SugarCane, Sapling, Fungus, Roots:
 		return true
 	}
 	return false
@@ -45,13 +45,19 @@ func (g Grass) SoilFor(block world.Block) bool {
 
 // RandomTick handles the ticking of grass, which may or may not result in the spreading of grass onto dirt.
 func (g Grass) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
-	aboveLight := w.Light(pos.Side(cube.FaceUp))
-	if aboveLight < 4 {
-		// The light above the block is too low: The grass turns to dirt.
+	spreadOntoDirt(g, pos, w, r)
+}
+
+// spreadOntoDirt handles the random ticking of a block covering dirt, such as grass or mycelium. If the block
+// is covered or the light above it is too low, it turns back into dirt. Otherwise, it may spread onto dirt
+// nearby.
+func spreadOntoDirt(b world.Block, pos cube.Pos, w *world.World, r *rand.Rand) {
+	if !dirtCoverSurvives(pos, w) {
+		// The block is covered or the light above the block is too low: It turns to dirt.
 		w.SetBlock(pos, Dirt{}, nil)
 		return
 	}
-	if aboveLight < 9 {
+	if w.Light(pos.Side(cube.FaceUp)) < 9 {
 		// Don't attempt to spread if the light level is lower than 9.
 		return
 	}
@@ -65,18 +71,29 @@ func (g Grass) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
 		n >>= 7
 
 		spreadPos := pos.Add(cube.Pos{x - 1, y - 3, z - 1})
-		// Don't spread grass to locations where dirt is exposed to hardly any light.
-		if w.Light(spreadPos.Side(cube.FaceUp)) < 4 {
+		if dirt, ok := w.Block(spreadPos).(Dirt); !ok || dirt.Coarse {
 			continue
 		}
-		b := w.Block(spreadPos)
-		if dirt, ok := b.(Dirt); !ok || dirt.Coarse {
-			continue
+		// Don't spread onto dirt that is covered or exposed to hardly any light.
+		if dirtCoverSurvives(spreadPos, w) {
+			w.SetBlock(spreadPos, b, nil)
 		}
-		w.SetBlock(spreadPos, g, nil)
 	}
 }
 
+// dirtCoverSurvives checks if a block covering dirt, such as grass or mycelium, can exist at the position
+// passed. It cannot if the block above it is opaque or a liquid, or if the light above it is lower than 4.
+func dirtCoverSurvives(pos cube.Pos, w *world.World) bool {
+	above := pos.Side(cube.FaceUp)
+	if diffuser, ok := w.Block(above).(LightDiffuser); !ok || diffuser.LightDiffusionLevel() == 15 {
+		return false
+	}
+	if _, ok := w.Liquid(above); ok {
+		return false
+	}
+	return w.Light(above) >= 4
+}
+
 // BoneMeal ...
 func (g Grass) BoneMeal(pos cube.Pos, w *world.World) bool {
 	for i := 0; i < 14; i++ {
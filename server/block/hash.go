@@ -122,6 +122,7 @@ const (
 	hashPodzol
 	hashPolishedBlackstoneBrick
 	hashPotato
+	hashPowderSnow
 	hashPrismarine
 	hashPumpkin
 	hashPumpkinSeeds
@@ -595,7 +596,7 @@ func (i IronOre) Hash() uint64 {
 
 // Hash ...
 func (i ItemFrame) Hash() uint64 {
-	return hashItemFrame | uint64(i.Facing)<<8 | uint64(boolByte(i.Glowing))<<11
+	return hashItemFrame | uint64(i.Facing)<<8 | uint64(boolByte(i.Glowing))<<11 | uint64(boolByte(i.displaysMap()))<<12
 }
 
 // Hash ...
@@ -778,6 +779,11 @@ func (p Potato) Hash() uint64 {
 	return hashPotato | uint64(p.Growth)<<8
 }
 
+// Hash ...
+func (PowderSnow) Hash() uint64 {
+	return hashPowderSnow
+}
+
 // Hash ...
 func (p Prismarine) Hash() uint64 {
 	return hashPrismarine | uint64(p.Type.Uint8())<<8
@@ -19,16 +19,72 @@ const (
 	hashSlime
 	hashWoodPressurePlate
 	hashIronDoor
+	hashEndPortal
+	hashMushroom
+	hashSapling
+	hashEndPortalFrame
+	hashFungus
+	hashNylium
+	hashRoots
+	hashChorusFlower
+	hashChorusPlant
+	hashEndGateway
+	hashNetherPortal
+	hashStructureBlock
+	hashLightningRod
+	hashIce
+	hashSnowLayer
+	hashMycelium
+	hashVines
+	hashBamboo
+	hashBambooSapling
+	hashCommandBlock
 )
 
 func (b Button) Hash() uint64 {
 	return hashButton | uint64(b.Type.Uint8())<<8 | uint64(b.Facing)<<14 | uint64(boolByte(b.Pressed))<<17
 }
 
+func (b Bamboo) Hash() uint64 {
+	return hashBamboo | uint64(boolByte(b.Thick))<<8 | uint64(b.LeafSize.Uint8())<<9 | uint64(boolByte(b.Ready))<<11
+}
+
+func (s BambooSapling) Hash() uint64 {
+	return hashBambooSapling | uint64(boolByte(s.Ready))<<8
+}
+
+func (c ChorusFlower) Hash() uint64 {
+	return hashChorusFlower | uint64(c.Age)<<8
+}
+
+func (ChorusPlant) Hash() uint64 {
+	return hashChorusPlant
+}
+
+func (c CommandBlock) Hash() uint64 {
+	return hashCommandBlock | uint64(c.Type.Uint8())<<8 | uint64(c.Facing)<<10 | uint64(boolByte(c.Conditional))<<13
+}
+
 func (d Dropper) Hash() uint64 {
 	return hashDropper | uint64(d.Facing)<<8 | uint64(boolByte(d.Powered))<<11
 }
 
+func (EndGateway) Hash() uint64 {
+	return hashEndGateway
+}
+
+func (EndPortal) Hash() uint64 {
+	return hashEndPortal
+}
+
+func (f EndPortalFrame) Hash() uint64 {
+	return hashEndPortalFrame | uint64(f.Facing)<<8 | uint64(boolByte(f.Eye))<<10
+}
+
+func (f Fungus) Hash() uint64 {
+	return hashFungus | uint64(boolByte(f.Warped))<<8
+}
+
 func (h Hopper) Hash() uint64 {
 	return hashHopper | uint64(h.Facing)<<8 | uint64(boolByte(h.Powered))<<11
 }
@@ -38,14 +94,38 @@ func (d IronDoor) Hash() uint64 {
 	return hashIronDoor | uint64(d.Facing)<<8 | uint64(boolByte(d.Open))<<10 | uint64(boolByte(d.Top))<<11 | uint64(boolByte(d.Right))<<12
 }
 
+func (Ice) Hash() uint64 {
+	return hashIce
+}
+
 func (l Lever) Hash() uint64 {
 	return hashLever | uint64(boolByte(l.Powered))<<8 | uint64(l.Facing)<<9 | uint64(l.Direction)<<12
 }
 
+func (l LightningRod) Hash() uint64 {
+	return hashLightningRod | uint64(l.Facing)<<8
+}
+
 func (Moving) Hash() uint64 {
 	return hashMoving
 }
 
+func (m Mushroom) Hash() uint64 {
+	return hashMushroom | uint64(boolByte(m.Red))<<8
+}
+
+func (Mycelium) Hash() uint64 {
+	return hashMycelium
+}
+
+func (n Nylium) Hash() uint64 {
+	return hashNylium | uint64(boolByte(n.Warped))<<8
+}
+
+func (p NetherPortal) Hash() uint64 {
+	return hashNetherPortal | uint64(p.Axis)<<8
+}
+
 func (o Observer) Hash() uint64 {
 	return hashObserver | uint64(o.Facing)<<8 | uint64(boolByte(o.Powered))<<11
 }
@@ -81,14 +161,34 @@ func (r RedstoneWire) Hash() uint64 {
 	return hashRedstoneWire | uint64(r.Power)<<8
 }
 
+func (r Roots) Hash() uint64 {
+	return hashRoots | uint64(boolByte(r.Warped))<<8
+}
+
+func (s Sapling) Hash() uint64 {
+	return hashSapling | uint64(s.Wood.Uint8())<<8 | uint64(boolByte(s.Aged))<<12
+}
+
 func (Slime) Hash() uint64 {
 	return hashSlime
 }
 
+func (s SnowLayer) Hash() uint64 {
+	return hashSnowLayer | uint64(s.Height)<<8 | uint64(boolByte(s.Covered))<<11
+}
+
 func (p StonePressurePlate) Hash() uint64 {
 	return hashStonePressurePlate | uint64(boolByte(p.Powered))<<8
 }
 
+func (s StructureBlock) Hash() uint64 {
+	return hashStructureBlock | uint64(s.Mode.Uint8())<<8
+}
+
 func (p WoodPressurePlate) Hash() uint64 {
 	return hashWoodPressurePlate | uint64(p.Wood.Uint8())<<8 | uint64(boolByte(p.Powered))<<17
 }
+
+func (v Vines) Hash() uint64 {
+	return hashVines | uint64(boolByte(v.NorthDirection))<<8 | uint64(boolByte(v.EastDirection))<<9 | uint64(boolByte(v.SouthDirection))<<10 | uint64(boolByte(v.WestDirection))<<11
+}
@@ -0,0 +1,66 @@
+package block
+
+import (
+	"math/rand"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+)
+
+// Ice is a transparent, slippery solid block that forms when still water freezes during snowfall. Ice melts back
+// into water when placed near bright light sources.
+type Ice struct {
+	solid
+	transparent
+}
+
+// Instrument ...
+func (Ice) Instrument() sound.Instrument {
+	return sound.Chimes()
+}
+
+// Friction ...
+func (Ice) Friction() float64 {
+	return 0.98
+}
+
+// RandomTick melts the ice if the block light around it is too bright.
+func (i Ice) RandomTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	if w.BlockLight(pos) > 11 {
+		i.melt(pos, w)
+	}
+}
+
+// melt turns the ice into a source block of water, or into air if water evaporates in the dimension.
+func (Ice) melt(pos cube.Pos, w *world.World) {
+	if w.Dimension().WaterEvaporates() {
+		w.SetBlock(pos, nil, nil)
+		return
+	}
+	w.SetBlock(pos, Water{Depth: 8, Still: true}, nil)
+}
+
+// BreakInfo ...
+func (i Ice) BreakInfo() BreakInfo {
+	return newBreakInfo(0.5, alwaysHarvestable, pickaxeEffective, silkTouchOnlyDrop(i)).withBreakHandler(func(pos cube.Pos, w *world.World, u item.User) {
+		if held, _ := u.HeldItems(); hasSilkTouch(held.Enchantments()) || w.Dimension().WaterEvaporates() {
+			return
+		}
+		// Ice broken without silk touch leaves water behind if it was resting on something.
+		if _, ok := w.Block(pos.Side(cube.FaceDown)).(Air); !ok {
+			w.SetBlock(pos, Water{Depth: 8, Still: true}, nil)
+		}
+	})
+}
+
+// EncodeItem ...
+func (Ice) EncodeItem() (name string, meta int16) {
+	return "minecraft:ice", 0
+}
+
+// EncodeBlock ...
+func (Ice) EncodeBlock() (string, map[string]any) {
+	return "minecraft:ice", nil
+}
@@ -34,12 +34,10 @@ type ItemFrame struct {
 // Activate ...
 func (i ItemFrame) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, ctx *item.UseContext) bool {
 	if !i.Item.Empty() {
-		// TODO: Item frames with maps can only be rotated four times.
-		i.Rotations = (i.Rotations + 1) % 8
+		i.Rotations = (i.Rotations + 1) % i.maxRotations()
 		w.PlaySound(pos.Vec3Centre(), sound.ItemFrameRotate{})
 	} else if held, _ := u.HeldItems(); !held.Empty() {
-		i.Item = held.Grow(-held.Count() + 1)
-		// TODO: When maps are implemented, check the item is a map, and if so, display the large version of the frame.
+		i.Item, i.Rotations = held.Grow(-held.Count()+1), 0
 		ctx.SubtractFromCount(1)
 		w.PlaySound(pos.Vec3Centre(), sound.ItemFrameAdd{})
 	} else {
@@ -47,6 +45,7 @@ func (i ItemFrame) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.Us
 	}
 
 	w.SetBlock(pos, i, nil)
+	updateComparators(pos, w)
 	return true
 }
 
@@ -66,6 +65,32 @@ func (i ItemFrame) Punch(pos cube.Pos, _ cube.Face, w *world.World, u item.User)
 	i.Item, i.Rotations = item.Stack{}, 0
 	w.PlaySound(pos.Vec3Centre(), sound.ItemFrameRemove{})
 	w.SetBlock(pos, i, nil)
+	updateComparators(pos, w)
+}
+
+// ComparatorSignal returns the signal strength emitted to a comparator reading the item frame. The signal is 0 if the
+// frame is empty, and otherwise ranges from 1 to 8 depending on the rotation of the item.
+func (i ItemFrame) ComparatorSignal(cube.Pos, *world.World) int {
+	if i.Item.Empty() {
+		return 0
+	}
+	return i.Rotations*(8/i.maxRotations()) + 1
+}
+
+// maxRotations returns the number of distinct rotations the item in the frame may have. Maps may only be rotated in
+// steps of 90 degrees, whereas all other items are rotated in steps of 45 degrees.
+func (i ItemFrame) maxRotations() int {
+	if i.displaysMap() {
+		return 4
+	}
+	return 8
+}
+
+// displaysMap checks if the item frame holds a filled map, which results in the large version of the frame being
+// displayed.
+func (i ItemFrame) displaysMap() bool {
+	_, ok := i.Item.Item().(item.FilledMap)
+	return ok
 }
 
 // UseOnBlock ...
@@ -87,7 +112,21 @@ func (i ItemFrame) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *wor
 
 // BreakInfo ...
 func (i ItemFrame) BreakInfo() BreakInfo {
-	return newBreakInfo(0.25, alwaysHarvestable, nothingEffective, oneOf(i))
+	return newBreakInfo(0.25, alwaysHarvestable, nothingEffective, func(item.Tool, []item.Enchantment) []item.Stack {
+		return i.drops()
+	}).withBreakHandler(func(pos cube.Pos, w *world.World, _ item.User) {
+		updateComparators(pos, w)
+	})
+}
+
+// drops returns the items dropped when the item frame is broken. The frame itself is always dropped, whereas the item
+// inside it is only dropped according to the DropChance of the frame.
+func (i ItemFrame) drops() []item.Stack {
+	d := []item.Stack{item.NewStack(ItemFrame{Glowing: i.Glowing}, 1)}
+	if !i.Item.Empty() && rand.Float64() <= i.DropChance {
+		d = append(d, i.Item)
+	}
+	return d
 }
 
 // EncodeItem ...
@@ -106,7 +145,7 @@ func (i ItemFrame) EncodeBlock() (name string, properties map[string]any) {
 	}
 	return name, map[string]any{
 		"facing_direction":     int32(i.Facing.Opposite()),
-		"item_frame_map_bit":   uint8(0), // TODO: When maps are added, set this to true if the item is a map.
+		"item_frame_map_bit":   boolByte(i.displaysMap()),
 		"item_frame_photo_bit": uint8(0), // Only implemented in Education Edition.
 	}
 }
@@ -154,18 +193,20 @@ func (i ItemFrame) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
 		// TODO: Allow exceptions for pressure plates.
 		w.SetBlock(pos, nil, nil)
 		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: i})
-		dropItem(w, item.NewStack(i, 1), pos.Vec3Centre())
-		if !i.Item.Empty() {
-			dropItem(w, i.Item, pos.Vec3Centre())
+		for _, drop := range i.drops() {
+			dropItem(w, drop, pos.Vec3Centre())
 		}
 	}
 }
 
 // allItemFrames ...
 func allItemFrames() (frames []world.Block) {
+	filledMap := item.NewStack(item.FilledMap{}, 1)
 	for _, f := range cube.Faces() {
-		frames = append(frames, ItemFrame{Facing: f, Glowing: true})
-		frames = append(frames, ItemFrame{Facing: f, Glowing: false})
+		for _, glowing := range []bool{false, true} {
+			frames = append(frames, ItemFrame{Facing: f, Glowing: glowing})
+			frames = append(frames, ItemFrame{Facing: f, Glowing: glowing, Item: filledMap})
+		}
 	}
 	return
 }
@@ -33,6 +33,7 @@ func (j Jukebox) BreakInfo() BreakInfo {
 	return newBreakInfo(0.8, alwaysHarvestable, axeEffective, simpleDrops(d...)).withBreakHandler(func(pos cube.Pos, w *world.World, u item.User) {
 		if _, hasDisc := j.Disc(); hasDisc {
 			w.PlaySound(pos.Vec3Centre(), sound.MusicDiscEnd{})
+			w.EmitGameEvent(world.GameEventJukeboxStopPlay, pos.Vec3Centre(), u)
 		}
 	})
 }
@@ -52,6 +53,7 @@ func (j Jukebox) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User
 		j.Item = item.Stack{}
 		w.SetBlock(pos, j, nil)
 		w.PlaySound(pos.Vec3Centre(), sound.MusicDiscEnd{})
+		w.EmitGameEvent(world.GameEventJukeboxStopPlay, pos.Vec3Centre(), u)
 	} else if held, _ := u.HeldItems(); !held.Empty() {
 		if m, ok := held.Item().(item.MusicDisc); ok {
 			j.Item = held
@@ -61,6 +63,7 @@ func (j Jukebox) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User
 			ctx.SubtractFromCount(1)
 
 			w.PlaySound(pos.Vec3Centre(), sound.MusicDiscPlay{DiscType: m.DiscType})
+			w.EmitGameEvent(world.GameEventJukeboxPlay, pos.Vec3Centre(), u)
 			if u, ok := u.(jukeboxUser); ok {
 				u.SendJukeboxPopup(fmt.Sprintf("Now playing: %v - %v", m.DiscType.Author(), m.DiscType.DisplayName()))
 			}
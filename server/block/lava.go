@@ -50,14 +50,25 @@ func (l Lava) EntityInside(_ cube.Pos, _ *world.World, e world.Entity) {
 
 // RandomTick ...
 func (l Lava) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if !world.GameRuleDoFireTick.Value(w) {
+		return
+	}
+	from := pos
 	i := r.Intn(3)
 	if i > 0 {
 		for j := 0; j < i; j++ {
 			pos = pos.Add(cube.Pos{r.Intn(3) - 1, 1, r.Intn(3) - 1})
-			if _, ok := w.Block(pos).(Air); ok {
-				if neighboursLavaFlammable(pos, w) {
-					w.SetBlock(pos, Fire{}, nil)
+			b := w.Block(pos)
+			if _, ok := b.(Air); !ok {
+				if b.Model().FaceSolid(pos, cube.FaceDown, w) {
+					// Lava cannot ignite blocks through solid blocks.
+					return
 				}
+				continue
+			}
+			if neighboursLavaFlammable(pos, w) {
+				l.ignite(from, pos, w, r)
+				return
 			}
 		}
 	} else {
@@ -65,13 +76,27 @@ func (l Lava) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
 			pos = pos.Add(cube.Pos{r.Intn(3) - 1, 0, r.Intn(3) - 1})
 			if _, ok := w.Block(pos.Side(cube.FaceUp)).(Air); ok {
 				if flammable, ok := w.Block(pos).(Flammable); ok && flammable.FlammabilityInfo().LavaFlammable && flammable.FlammabilityInfo().Encouragement > 0 {
-					w.SetBlock(pos, Fire{}, nil)
+					l.ignite(from, pos.Side(cube.FaceUp), w, r)
 				}
 			}
 		}
 	}
 }
 
+// ignite attempts to start a fire at the position passed, ignited by lava at the position from. No fire is
+// started if it is raining at the position or if the spreading of the fire is cancelled.
+func (l Lava) ignite(from, pos cube.Pos, w *world.World, r *rand.Rand) {
+	if rainingAround(pos, w) {
+		return
+	}
+	ctx := event.C()
+	if w.Handler().HandleFireSpread(ctx, from, pos); ctx.Cancelled() {
+		return
+	}
+	w.SetBlock(pos, Fire{}, nil)
+	w.ScheduleBlockUpdate(pos, time.Duration(30+r.Intn(10))*time.Second/20)
+}
+
 // HasLiquidDrops ...
 func (Lava) HasLiquidDrops() bool {
 	return false
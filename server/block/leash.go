@@ -0,0 +1,52 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// leashable represents an entity that may be leashed to a fence.
+type leashable interface {
+	world.Entity
+	// LeashHolder returns the entity that the leashable is leashed to.
+	LeashHolder() (world.Entity, bool)
+	// Leash leashes the leashable to the holder passed.
+	Leash(holder world.Entity) bool
+}
+
+// leashToFence ties all entities within 7 blocks that are leashed to the user
+// passed to a leash knot on the fence at the position passed. A leash knot is
+// created if none exists yet. leashToFence returns false if no entities were
+// leashed to the user.
+func leashToFence(pos cube.Pos, w *world.World, u item.User) bool {
+	var knot world.Entity
+	centre := pos.Vec3Centre()
+	for _, e := range w.EntitiesWithin(cube.Box(-7, -7, -7, 7, 7, 7).Translate(centre), nil) {
+		l, ok := e.(leashable)
+		if !ok {
+			continue
+		}
+		if holder, leashed := l.LeashHolder(); !leashed || holder != u {
+			continue
+		}
+		if knot == nil {
+			knot = leashKnot(pos, w)
+		}
+		l.Leash(knot)
+	}
+	return knot != nil
+}
+
+// leashKnot returns the leash knot on the fence at the position passed,
+// creating it if it does not yet exist.
+func leashKnot(pos cube.Pos, w *world.World) world.Entity {
+	for _, e := range w.EntitiesWithin(cube.Box(0, 0, 0, 1, 1, 1).Translate(pos.Vec3()), nil) {
+		if e.Type().EncodeEntity() == "minecraft:leash_knot" {
+			return e
+		}
+	}
+	knot := w.EntityRegistry().Config().LeashKnot(pos)
+	w.AddEntity(knot)
+	return knot
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"math/rand"
+	"slices"
 )
 
 // Leaves are blocks that grow as part of trees which mainly drop saplings and sticks.
@@ -96,7 +97,16 @@ func (l Leaves) BreakInfo() BreakInfo {
 		if (l.Wood == OakWood() || l.Wood == DarkOakWood()) && rand.Float64() < 0.005 {
 			drops = append(drops, item.NewStack(item.Apple{}, 1))
 		}
-		// TODO: Saplings and sticks can drop along with apples
+		saplingChance := 0.05
+		if l.Wood == JungleWood() {
+			saplingChance = 0.025
+		}
+		if slices.Contains(SaplingWoodTypes(), l.Wood) && rand.Float64() < saplingChance {
+			drops = append(drops, item.NewStack(Sapling{Wood: l.Wood}, 1))
+		}
+		if rand.Float64() < 0.02 {
+			drops = append(drops, item.NewStack(item.Stick{}, rand.Intn(2)+1))
+		}
 		return drops
 	})
 }
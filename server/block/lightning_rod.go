@@ -0,0 +1,130 @@
+package block
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// LightningRod is a block that attracts lightning striking close to it. A lightning rod struck by lightning emits
+// a redstone signal for a short moment.
+type LightningRod struct {
+	transparent
+	sourceWaterDisplacer
+
+	// Facing is the direction that the tip of the lightning rod points towards.
+	Facing cube.Face
+}
+
+// lightningRodPowerDuration is the duration for which a lightning rod emits a redstone signal after being struck by
+// lightning.
+const lightningRodPowerDuration = time.Second * 8 / 20
+
+// poweredLightningRods holds the positions of all lightning rods that are currently emitting a redstone signal. The
+// state is not held by the LightningRod itself, as Bedrock Edition has no block state for it.
+var poweredLightningRods sync.Map
+
+// lightningRodKey is the key used to store a powered lightning rod in poweredLightningRods.
+type lightningRodKey struct {
+	w   *world.World
+	pos cube.Pos
+}
+
+// StrikeLightning makes the lightning rod emit a redstone signal after being struck by lightning.
+func (l LightningRod) StrikeLightning(pos cube.Pos, w *world.World) {
+	poweredLightningRods.Store(lightningRodKey{w: w, pos: pos}, struct{}{})
+	w.ScheduleBlockUpdate(pos, lightningRodPowerDuration)
+	updateDirectionalRedstone(pos, w, l.Facing.Opposite())
+}
+
+// Powered checks if the lightning rod at the position passed is currently emitting a redstone signal.
+func (l LightningRod) Powered(pos cube.Pos, w *world.World) bool {
+	_, ok := poweredLightningRods.Load(lightningRodKey{w: w, pos: pos})
+	return ok
+}
+
+// ScheduledTick stops the lightning rod from emitting a redstone signal.
+func (l LightningRod) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	if _, ok := poweredLightningRods.LoadAndDelete(lightningRodKey{w: w, pos: pos}); ok {
+		updateDirectionalRedstone(pos, w, l.Facing.Opposite())
+	}
+}
+
+// Source ...
+func (LightningRod) Source() bool {
+	return true
+}
+
+// WeakPower ...
+func (l LightningRod) WeakPower(pos cube.Pos, _ cube.Face, w *world.World, _ bool) int {
+	if l.Powered(pos, w) {
+		return 15
+	}
+	return 0
+}
+
+// StrongPower ...
+func (l LightningRod) StrongPower(pos cube.Pos, face cube.Face, w *world.World, _ bool) int {
+	if face == l.Facing && l.Powered(pos, w) {
+		return 15
+	}
+	return 0
+}
+
+// SideClosed ...
+func (LightningRod) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// Model ...
+func (l LightningRod) Model() world.BlockModel {
+	return model.LightningRod{Axis: l.Facing.Axis()}
+}
+
+// UseOnBlock ...
+func (l LightningRod) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, face, used = firstReplaceable(w, pos, face, l)
+	if !used {
+		return
+	}
+	l.Facing = face
+	if other, ok := w.Block(pos.Side(face.Opposite())).(LightningRod); ok && other.Facing == face {
+		// Lightning rods placed on lightning rods facing the same way are flipped, like in vanilla.
+		l.Facing = face.Opposite()
+	}
+	place(w, pos, l, user, ctx)
+	return placed(ctx)
+}
+
+// BreakInfo ...
+func (l LightningRod) BreakInfo() BreakInfo {
+	return newBreakInfo(3, pickaxeHarvestable, pickaxeEffective, oneOf(l)).withBlastResistance(6).withBreakHandler(func(pos cube.Pos, w *world.World, _ item.User) {
+		if _, ok := poweredLightningRods.LoadAndDelete(lightningRodKey{w: w, pos: pos}); ok {
+			updateDirectionalRedstone(pos, w, l.Facing.Opposite())
+		}
+	})
+}
+
+// EncodeItem ...
+func (LightningRod) EncodeItem() (name string, meta int16) {
+	return "minecraft:lightning_rod", 0
+}
+
+// EncodeBlock ...
+func (l LightningRod) EncodeBlock() (string, map[string]any) {
+	return "minecraft:lightning_rod", map[string]any{"facing_direction": int32(l.Facing)}
+}
+
+// allLightningRods ...
+func allLightningRods() (rods []world.Block) {
+	for _, f := range cube.Faces() {
+		rods = append(rods, LightningRod{Facing: f})
+	}
+	return
+}
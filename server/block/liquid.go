@@ -4,6 +4,7 @@ import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/loot"
 	"github.com/df-mc/dragonfly/server/world"
 	"math"
 	"sync"
@@ -170,8 +171,8 @@ func flowInto(b world.Liquid, src, pos cube.Pos, w *world.World, falling bool) b
 			w.SetBlock(pos, nil, nil)
 		}
 		if removable.HasLiquidDrops() {
-			if b, ok := existing.(Breakable); ok {
-				for _, d := range b.BreakInfo().Drops(item.ToolNone{}, nil) {
+			if _, ok := existing.(Breakable); ok {
+				for _, d := range BreakDrops(existing, item.ToolNone{}, nil, loot.Context{}) {
 					dropItem(w, d, pos.Vec3Centre())
 				}
 			} else {
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// LightningRod is a model used by lightning rods.
+type LightningRod struct {
+	// Axis is the axis along which the lightning rod is placed.
+	Axis cube.Axis
+}
+
+// BBox ...
+func (l LightningRod) BBox(cube.Pos, *world.World) []cube.BBox {
+	return []cube.BBox{cube.Box(0.375, 0.375, 0.375, 0.625, 0.625, 0.625).Stretch(l.Axis, 0.375)}
+}
+
+// FaceSolid ...
+func (LightningRod) FaceSolid(cube.Pos, cube.Face, *world.World) bool {
+	return false
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// SnowLayer is a model used by snow layers.
+type SnowLayer struct {
+	// Height is the height of the snow layer, ranging from 0-7. A snow layer with a height of 7 is as high as a
+	// full block.
+	Height int
+}
+
+// BBox returns a BBox that is one eighth of a block lower than the visual height of the snow layer. A snow layer
+// with a height of 0 has no BBox and may be walked through.
+func (s SnowLayer) BBox(cube.Pos, *world.World) []cube.BBox {
+	if s.Height == 0 {
+		return nil
+	}
+	return []cube.BBox{cube.Box(0, 0, 0, 1, float64(s.Height)/8, 1)}
+}
+
+// FaceSolid returns true for the bottom face, or for all faces if the snow layer is as high as a full block.
+func (s SnowLayer) FaceSolid(_ cube.Pos, face cube.Face, _ *world.World) bool {
+	return face == cube.FaceDown || s.Height == 7
+}
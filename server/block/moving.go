@@ -48,8 +48,8 @@ func (b Moving) EncodeNBT() map[string]any {
 		"pistonPosY":       int32(b.Piston.Y()),
 		"pistonPosZ":       int32(b.Piston.Z()),
 	}
-	if nbt, ok := b.Moving.(world.NBTer); ok {
-		data["movingEntity"] = nbt.EncodeNBT()
+	if nbt, ok := world.EncodeBlockNBT(b.Moving); ok {
+		data["movingEntity"] = nbt
 	}
 	return data
 }
@@ -64,8 +64,8 @@ func (b Moving) DecodeNBT(m map[string]any) any {
 		int(nbtconv.Int32(m, "pistonPosY")),
 		int(nbtconv.Int32(m, "pistonPosZ")),
 	}
-	if nbt, ok := b.Moving.(world.NBTer); ok {
-		b.Moving = nbt.DecodeNBT(m["movingEntity"].(map[string]any)).(world.Block)
+	if _, ok := world.EncodeBlockNBT(b.Moving); ok {
+		b.Moving = world.DecodeBlockNBT(b.Moving, m["movingEntity"].(map[string]any))
 	}
 	return b
 }
\ No newline at end of file
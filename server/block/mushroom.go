@@ -0,0 +1,93 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Mushroom is a non-solid fungus that grows in dark areas, such as caves and dense forests.
+type Mushroom struct {
+	empty
+	transparent
+	sourceWaterDisplacer
+
+	// Red specifies if the mushroom is a red mushroom. If false, the mushroom is brown.
+	Red bool
+}
+
+// LightEmissionLevel ...
+func (m Mushroom) LightEmissionLevel() uint8 {
+	if m.Red {
+		return 0
+	}
+	return 1
+}
+
+// NeighbourUpdateTick ...
+func (m Mushroom) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !m.canSurvive(pos, w) {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: m})
+		dropItem(w, item.NewStack(m, 1), pos.Vec3Centre())
+	}
+}
+
+// UseOnBlock ...
+func (m Mushroom) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, m)
+	if !used {
+		return false
+	}
+	if !m.canSurvive(pos, w) {
+		return false
+	}
+
+	place(w, pos, m, user, ctx)
+	return placed(ctx)
+}
+
+// canSurvive checks if the mushroom can exist at the position passed. Mushrooms survive on any block with a
+// solid top face as long as the light level is low enough, and on podzol and mycelium regardless of the light
+// level.
+func (m Mushroom) canSurvive(pos cube.Pos, w *world.World) bool {
+	below := pos.Side(cube.FaceDown)
+	switch w.Block(below).(type) {
+	case Podzol, Mycelium:
+		return true
+	}
+	return w.Block(below).Model().FaceSolid(below, cube.FaceUp, w) && w.Light(pos) < 13
+}
+
+// HasLiquidDrops ...
+func (m Mushroom) HasLiquidDrops() bool {
+	return true
+}
+
+// BreakInfo ...
+func (m Mushroom) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(m))
+}
+
+// CompostChance ...
+func (m Mushroom) CompostChance() float64 {
+	return 0.65
+}
+
+// EncodeItem ...
+func (m Mushroom) EncodeItem() (name string, meta int16) {
+	if m.Red {
+		return "minecraft:red_mushroom", 0
+	}
+	return "minecraft:brown_mushroom", 0
+}
+
+// EncodeBlock ...
+func (m Mushroom) EncodeBlock() (string, map[string]any) {
+	if m.Red {
+		return "minecraft:red_mushroom", nil
+	}
+	return "minecraft:brown_mushroom", nil
+}
@@ -0,0 +1,48 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+// Mycelium is a variant of dirt that covers the surface of mushroom fields. Like grass, it spreads onto dirt
+// nearby and turns back into dirt when covered.
+type Mycelium struct {
+	solid
+}
+
+// SoilFor ...
+func (Mycelium) SoilFor(block world.Block) bool {
+	switch block.(type) {
+	case Mushroom, Fungus, Roots, NetherSprouts:
+		return true
+	}
+	return false
+}
+
+// RandomTick handles the ticking of mycelium, which may or may not result in the spreading of mycelium onto
+// dirt.
+func (m Mycelium) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	spreadOntoDirt(m, pos, w, r)
+}
+
+// Shovel ...
+func (Mycelium) Shovel() (world.Block, bool) {
+	return DirtPath{}, true
+}
+
+// BreakInfo ...
+func (m Mycelium) BreakInfo() BreakInfo {
+	return newBreakInfo(0.6, alwaysHarvestable, shovelEffective, silkTouchOneOf(Dirt{}, m))
+}
+
+// EncodeItem ...
+func (Mycelium) EncodeItem() (name string, meta int16) {
+	return "minecraft:mycelium", 0
+}
+
+// EncodeBlock ...
+func (Mycelium) EncodeBlock() (string, map[string]any) {
+	return "minecraft:mycelium", nil
+}
@@ -3,6 +3,7 @@ package block
 import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
 )
 
@@ -17,6 +18,11 @@ func (n NetherBrickFence) BreakInfo() BreakInfo {
 	return newBreakInfo(2, pickaxeHarvestable, pickaxeEffective, oneOf(n)).withBlastResistance(30)
 }
 
+// Activate ties mobs leashed to the user to the fence.
+func (NetherBrickFence) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
+	return leashToFence(pos, w, u)
+}
+
 // SideClosed ...
 func (NetherBrickFence) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 	return false
@@ -0,0 +1,194 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// NetherPortal is the translucent block that fills a frame of obsidian once it is lit. Entities that stand in a
+// nether portal are teleported to the Nether, or back to the Overworld when used in the Nether.
+type NetherPortal struct {
+	empty
+	transparent
+
+	// Axis is the horizontal axis along which the nether portal extends. It is either cube.X or cube.Z.
+	Axis cube.Axis
+}
+
+// LightEmissionLevel ...
+func (NetherPortal) LightEmissionLevel() uint8 {
+	return 11
+}
+
+// SideClosed ...
+func (NetherPortal) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// NeighbourUpdateTick removes the nether portal if its frame is no longer complete. The removal of a single
+// portal block causes its neighbours to be updated, so that the entire portal decays.
+func (p NetherPortal) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	for _, face := range []cube.Face{cube.FaceUp, cube.FaceDown, p.positiveFace(), p.positiveFace().Opposite()} {
+		switch b := w.Block(pos.Side(face)).(type) {
+		case NetherPortal:
+			if b.Axis == p.Axis {
+				continue
+			}
+		case Obsidian:
+			if !b.Crying {
+				continue
+			}
+		}
+		w.SetBlock(pos, nil, nil)
+		return
+	}
+}
+
+// EntityInside queues the entity passed for travelling through the nether portal. The entity is teleported to
+// the destination of the portal once it has stood in the portal for long enough.
+func (p NetherPortal) EntityInside(pos cube.Pos, w *world.World, e world.Entity) {
+	portalTravellers.enter(e, pos, w)
+}
+
+// positiveFace returns the face pointing in the positive direction of the axis of the nether portal.
+func (p NetherPortal) positiveFace() cube.Face {
+	if p.Axis == cube.Z {
+		return cube.FaceSouth
+	}
+	return cube.FaceEast
+}
+
+// BreakInfo ...
+func (p NetherPortal) BreakInfo() BreakInfo {
+	return newBreakInfo(-1, neverHarvestable, nothingEffective, simpleDrops())
+}
+
+// EncodeBlock ...
+func (p NetherPortal) EncodeBlock() (string, map[string]any) {
+	if p.Axis == cube.Z {
+		return "minecraft:portal", map[string]any{"portal_axis": "z"}
+	}
+	return "minecraft:portal", map[string]any{"portal_axis": "x"}
+}
+
+// allNetherPortals returns all possible nether portal blocks.
+func allNetherPortals() []world.Block {
+	return []world.Block{NetherPortal{Axis: cube.X}, NetherPortal{Axis: cube.Z}}
+}
+
+// portalFrame is a frame of obsidian that may be filled with nether portal blocks. It describes the inside of the
+// frame.
+type portalFrame struct {
+	axis cube.Axis
+	// bottom is the position of the lowest block of the inside of the frame with the smallest coordinate on the
+	// axis of the frame.
+	bottom        cube.Pos
+	width, height int
+}
+
+const (
+	// minPortalWidth and maxPortalWidth are the minimum and maximum widths of the inside of a nether portal frame.
+	minPortalWidth, maxPortalWidth = 2, 21
+	// minPortalHeight and maxPortalHeight are the minimum and maximum heights of the inside of a nether portal
+	// frame.
+	minPortalHeight, maxPortalHeight = 3, 21
+)
+
+// findPortalFrame finds a complete frame of obsidian around the position passed along either horizontal axis. If
+// no frame is found, false is returned.
+func findPortalFrame(pos cube.Pos, w *world.World) (portalFrame, bool) {
+	if f, ok := findPortalFrameOnAxis(pos, w, cube.X); ok {
+		return f, true
+	}
+	return findPortalFrameOnAxis(pos, w, cube.Z)
+}
+
+// findPortalFrameOnAxis finds a complete frame of obsidian around the position passed along the axis passed.
+func findPortalFrameOnAxis(pos cube.Pos, w *world.World, axis cube.Axis) (portalFrame, bool) {
+	dir := NetherPortal{Axis: axis}.positiveFace()
+	if !portalInside(w.Block(pos)) {
+		return portalFrame{}, false
+	}
+	// Move to the bottom of the frame first, and then to the side of it with the smallest coordinate.
+	for i := 0; i < maxPortalHeight && portalInside(w.Block(pos.Side(cube.FaceDown))); i++ {
+		pos = pos.Side(cube.FaceDown)
+	}
+	if !portalFrameBlock(w.Block(pos.Side(cube.FaceDown))) {
+		return portalFrame{}, false
+	}
+	for i := 0; i < maxPortalWidth && portalInside(w.Block(pos.Side(dir.Opposite()))); i++ {
+		pos = pos.Side(dir.Opposite())
+	}
+	if !portalFrameBlock(w.Block(pos.Side(dir.Opposite()))) {
+		return portalFrame{}, false
+	}
+
+	f := portalFrame{axis: axis, bottom: pos}
+	for p := pos; portalInside(w.Block(p)) && portalFrameBlock(w.Block(p.Side(cube.FaceDown))); p = p.Side(dir) {
+		if f.width++; f.width > maxPortalWidth {
+			return portalFrame{}, false
+		}
+	}
+	if f.width < minPortalWidth || !portalFrameBlock(w.Block(f.at(f.width, 0))) {
+		return portalFrame{}, false
+	}
+	for ; f.height <= maxPortalHeight; f.height++ {
+		row := true
+		for i := 0; i < f.width && row; i++ {
+			row = portalInside(w.Block(f.at(i, f.height)))
+		}
+		if !row {
+			// The first row that is not inside the frame must be the top of the frame.
+			break
+		}
+		if !portalFrameBlock(w.Block(f.at(-1, f.height))) || !portalFrameBlock(w.Block(f.at(f.width, f.height))) {
+			return portalFrame{}, false
+		}
+	}
+	if f.height < minPortalHeight || f.height > maxPortalHeight {
+		return portalFrame{}, false
+	}
+	for i := 0; i < f.width; i++ {
+		if !portalFrameBlock(w.Block(f.at(i, f.height))) {
+			return portalFrame{}, false
+		}
+	}
+	return f, true
+}
+
+// at returns the position of the block at the offsets passed from the bottom of the inside of the frame. i is
+// the offset along the axis of the frame and h the offset upwards.
+func (f portalFrame) at(i, h int) cube.Pos {
+	p := f.bottom.Add(cube.Pos{0, h, 0})
+	if f.axis == cube.Z {
+		return p.Add(cube.Pos{0, 0, i})
+	}
+	return p.Add(cube.Pos{i, 0, 0})
+}
+
+// fill fills the inside of the frame with nether portal blocks and registers the portal so that it may be
+// linked to.
+func (f portalFrame) fill(w *world.World) {
+	portal := NetherPortal{Axis: f.axis}
+	for i := 0; i < f.width; i++ {
+		for h := 0; h < f.height; h++ {
+			w.SetBlock(f.at(i, h), portal, nil)
+		}
+	}
+	knownPortals.add(w, f.bottom)
+}
+
+// portalInside checks if the block passed may be inside a nether portal frame that is lit.
+func portalInside(b world.Block) bool {
+	switch b.(type) {
+	case Air, Fire, NetherPortal:
+		return true
+	}
+	return false
+}
+
+// portalFrameBlock checks if the block passed may be part of a nether portal frame.
+func portalFrameBlock(b world.Block) bool {
+	o, ok := b.(Obsidian)
+	return ok && !o.Crying
+}
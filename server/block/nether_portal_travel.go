@@ -0,0 +1,259 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// portalDelay is the time that a player that can take damage has to stand in a nether portal before it is
+	// teleported. Other entities are teleported immediately.
+	portalDelay = time.Second * 4
+	// portalLeaveTime is the time after which an entity that has not been inside a nether portal is considered
+	// to have left it.
+	portalLeaveTime = time.Millisecond * 150
+	// portalSearchRadius is the horizontal distance from the scaled position of an entity within which the
+	// world is searched for a destination portal, and within which a new portal is created if none is found.
+	portalSearchRadius = 16
+	// portalLinkRadius is the horizontal distance from the scaled position of an entity within which known
+	// portals in the Overworld are linked to. Portals in the Nether are linked to within the portalSearchRadius,
+	// so that each portal in the Overworld may have its own portal in the Nether.
+	portalLinkRadius = 128
+)
+
+// portalRegistry keeps track of the nether portals that were lit or created in each world, so that portals far
+// away from the scaled position of an entity may still be linked to without searching the world for them.
+type portalRegistry struct {
+	mu      sync.Mutex
+	portals map[*world.World][]cube.Pos
+}
+
+// knownPortals is the portalRegistry holding all nether portals lit or created.
+var knownPortals = portalRegistry{portals: make(map[*world.World][]cube.Pos)}
+
+// add registers the nether portal with its lowest portal block at the position passed.
+func (r *portalRegistry) add(w *world.World, pos cube.Pos) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.portals[w] = append(r.portals[w], pos)
+}
+
+// nearest returns the position of the known nether portal closest to the target passed within the radius
+// passed. Portals that no longer exist are removed from the registry.
+func (r *portalRegistry) nearest(w *world.World, target cube.Pos, radius int) (cube.Pos, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found, nearest, dist := false, cube.Pos{}, math.MaxInt
+	r.portals[w] = slices.DeleteFunc(r.portals[w], func(pos cube.Pos) bool {
+		if _, ok := w.Block(pos).(NetherPortal); !ok {
+			return true
+		}
+		dx, dy, dz := pos[0]-target[0], pos[1]-target[1], pos[2]-target[2]
+		if abs(dx) > radius || abs(dz) > radius {
+			return false
+		}
+		if d := dx*dx + dy*dy + dz*dz; d < dist {
+			found, nearest, dist = true, pos, d
+		}
+		return false
+	})
+	return nearest, found
+}
+
+// portalQueue keeps track of the entities standing in nether portals, so that they may be teleported once they
+// have stood in a portal for long enough.
+type portalQueue struct {
+	mu         sync.Mutex
+	travellers map[world.Entity]*portalTraveller
+}
+
+// portalTraveller holds the state of an entity standing in a nether portal.
+type portalTraveller struct {
+	entered, last time.Time
+	// arrived is true if the entity was teleported into the portal it is standing in. Such an entity is not
+	// teleported again until it has left the portal.
+	arrived bool
+}
+
+// portalTravellers is the portalQueue of all entities standing in nether portals.
+var portalTravellers = portalQueue{travellers: make(map[world.Entity]*portalTraveller)}
+
+// enter marks the entity passed as standing in the nether portal at the position passed. If the entity has
+// stood in the portal for long enough, it is teleported to the destination of the portal.
+func (q *portalQueue) enter(e world.Entity, pos cube.Pos, w *world.World) {
+	now := time.Now()
+	q.mu.Lock()
+	for other, t := range q.travellers {
+		if now.Sub(t.last) > portalLeaveTime {
+			// The entity left the portal, so that it needs to wait the full delay once it enters a portal again.
+			delete(q.travellers, other)
+		}
+	}
+	t, ok := q.travellers[e]
+	if !ok {
+		t = &portalTraveller{entered: now}
+		q.travellers[e] = t
+	}
+	t.last = now
+	travel := !t.arrived && now.Sub(t.entered) >= portalTravelDelay(e)
+	if travel {
+		t.arrived = true
+	}
+	q.mu.Unlock()
+
+	if travel {
+		travelThroughPortal(e, pos, w)
+	}
+}
+
+// portalTravelDelay returns the time that the entity passed has to stand in a nether portal before it is
+// teleported.
+func portalTravelDelay(e world.Entity) time.Duration {
+	if g, ok := e.(interface{ GameMode() world.GameMode }); ok && g.GameMode().AllowsTakingDamage() {
+		return portalDelay
+	}
+	return 0
+}
+
+// travelThroughPortal teleports the entity passed from the nether portal at the position passed to the nether
+// portal in the destination world closest to the scaled position of the entity. If no portal exists there, a new
+// one is created.
+func travelThroughPortal(e world.Entity, pos cube.Pos, w *world.World) {
+	t, ok := e.(interface{ Teleport(pos mgl64.Vec3) })
+	if !ok {
+		return
+	}
+	dest := w.PortalDestination(world.Nether)
+	if dest == w || w.Dimension() == world.End || dest.Dimension() == world.End {
+		// Nether portals do not work in the End.
+		return
+	}
+	scale := portalScale(w.Dimension()) / portalScale(dest.Dimension())
+	r := dest.Range()
+	target := cube.Pos{
+		int(math.Floor(e.Position()[0] * scale)),
+		max(min(pos[1], r.Max()-portalSearchRadius), r.Min()+1),
+		int(math.Floor(e.Position()[2] * scale)),
+	}
+	radius := portalLinkRadius
+	if dest.Dimension() == world.Nether {
+		radius = portalSearchRadius
+	}
+	exit, ok := knownPortals.nearest(dest, target, radius)
+	if !ok {
+		exit, ok = findPortal(dest, target)
+	}
+	if !ok {
+		axis := cube.X
+		if p, ok := w.Block(pos).(NetherPortal); ok {
+			axis = p.Axis
+		}
+		exit = createPortal(dest, target, axis)
+	}
+	dest.AddTicket(world.TicketPortal, world.ChunkPos{int32(exit[0] >> 4), int32(exit[2] >> 4)})
+	dest.AddEntity(e)
+	t.Teleport(exit.Vec3Middle())
+}
+
+// portalScale returns the number of blocks in the Overworld that a single block in the dimension passed is worth.
+func portalScale(dim world.Dimension) float64 {
+	if dim == world.Nether {
+		return 8
+	}
+	return 1
+}
+
+// findPortal finds the nether portal block closest to the target passed within the portalSearchRadius. The
+// position returned is the lowest portal block of the column found. If no portal is found, false is returned.
+func findPortal(w *world.World, target cube.Pos) (cube.Pos, bool) {
+	r := w.Range()
+	found, nearest, dist := false, cube.Pos{}, math.MaxInt
+	for x := target[0] - portalSearchRadius; x <= target[0]+portalSearchRadius; x++ {
+		for z := target[2] - portalSearchRadius; z <= target[2]+portalSearchRadius; z++ {
+			for y := min(w.HighestBlock(x, z), r.Max()); y > r.Min(); y-- {
+				pos := cube.Pos{x, y, z}
+				if _, ok := w.Block(pos).(NetherPortal); !ok {
+					continue
+				}
+				if _, ok := w.Block(pos.Side(cube.FaceDown)).(NetherPortal); ok {
+					continue
+				}
+				dx, dy, dz := x-target[0], y-target[1], z-target[2]
+				if d := dx*dx + dy*dy + dz*dz; d < dist {
+					found, nearest, dist = true, pos, d
+				}
+			}
+		}
+	}
+	return nearest, found
+}
+
+// createPortal creates a new nether portal along the axis passed close to the target passed. The portal is
+// placed with its frame sunk into solid ground if there is room for it within the portalSearchRadius. Otherwise,
+// it is placed at the target on top of a small platform of obsidian. The position of the lowest portal block is
+// returned.
+func createPortal(w *world.World, target cube.Pos, axis cube.Axis) cube.Pos {
+	f := portalFrame{axis: axis, width: 2, height: 3}
+	side := cube.Pos{0, 0, 1}
+	if axis == cube.Z {
+		side = cube.Pos{1, 0, 0}
+	}
+	r := w.Range()
+	found, dist := false, math.MaxInt
+	for x := target[0] - portalSearchRadius; x <= target[0]+portalSearchRadius; x++ {
+		for z := target[2] - portalSearchRadius; z <= target[2]+portalSearchRadius; z++ {
+			for y := min(w.HighestBlock(x, z)+1, r.Max()-f.height-1); y > r.Min()+1; y-- {
+				dx, dy, dz := x-target[0], y-target[1], z-target[2]
+				d := dx*dx + dy*dy + dz*dz
+				if d >= dist {
+					continue
+				}
+				candidate := portalFrame{axis: axis, bottom: cube.Pos{x, y, z}, width: f.width, height: f.height}
+				if candidate.roomFor(w) {
+					found, dist, f = true, d, candidate
+				}
+			}
+		}
+	}
+	if !found {
+		f.bottom = target
+		for i := -1; i <= f.width; i++ {
+			for _, s := range []cube.Pos{{}, side, {-side[0], 0, -side[2]}} {
+				w.SetBlock(f.at(i, -1).Add(s), Obsidian{}, nil)
+				for h := 0; h < f.height; h++ {
+					w.SetBlock(f.at(i, h).Add(s), nil, nil)
+				}
+			}
+		}
+	}
+	for i := -1; i <= f.width; i++ {
+		for h := -1; h <= f.height; h++ {
+			if i == -1 || i == f.width || h == -1 || h == f.height {
+				w.SetBlock(f.at(i, h), Obsidian{}, nil)
+			}
+		}
+	}
+	f.fill(w)
+	return f.bottom
+}
+
+// roomFor checks if there is room for a portal with the frame passed, which is the case if the bottom of the
+// frame is solid ground and the blocks above it are air.
+func (f portalFrame) roomFor(w *world.World) bool {
+	for i := -1; i <= f.width; i++ {
+		if below := f.at(i, -1); !w.Block(below).Model().FaceSolid(below, cube.FaceUp, w) {
+			return false
+		}
+		for h := 0; h <= f.height; h++ {
+			if _, ok := w.Block(f.at(i, h)).(Air); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
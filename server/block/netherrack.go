@@ -1,8 +1,10 @@
 package block
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
 )
 
 // Netherrack is a block found in The Nether.
@@ -17,6 +19,30 @@ func (n Netherrack) SoilFor(block world.Block) bool {
 	return ok && flower.Type == WitherRose()
 }
 
+// BoneMeal turns the netherrack into nylium if nylium is found around it. If both crimson and warped nylium are
+// found, the type of nylium is picked at random.
+func (n Netherrack) BoneMeal(pos cube.Pos, w *world.World) bool {
+	above := pos.Side(cube.FaceUp)
+	if w.Block(above).Model().FaceSolid(above, cube.FaceDown, w) {
+		return false
+	}
+	var found []Nylium
+	for x := -1; x <= 1; x++ {
+		for y := -1; y <= 1; y++ {
+			for z := -1; z <= 1; z++ {
+				if nylium, ok := w.Block(pos.Add(cube.Pos{x, y, z})).(Nylium); ok {
+					found = append(found, nylium)
+				}
+			}
+		}
+	}
+	if len(found) == 0 {
+		return false
+	}
+	w.SetBlock(pos, found[rand.Intn(len(found))], nil)
+	return true
+}
+
 // BreakInfo ...
 func (n Netherrack) BreakInfo() BreakInfo {
 	return newBreakInfo(0.4, pickaxeHarvestable, pickaxeEffective, oneOf(n))
@@ -20,9 +20,10 @@ type Note struct {
 }
 
 // playNote ...
-func (n Note) playNote(pos cube.Pos, w *world.World) {
+func (n Note) playNote(pos cube.Pos, w *world.World, src world.Entity) {
 	w.PlaySound(pos.Vec3(), sound.Note{Instrument: n.instrument(pos, w), Pitch: n.Pitch})
 	w.AddParticle(pos.Vec3(), particle.Note{Instrument: n.Instrument(), Pitch: n.Pitch})
+	w.EmitGameEvent(world.GameEventNoteBlockPlay, pos.Vec3Centre(), src)
 }
 
 // updateInstrument ...
@@ -47,12 +48,12 @@ func (n Note) EncodeNBT() map[string]any {
 }
 
 // Activate ...
-func (n Note) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
+func (n Note) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
 	if _, ok := w.Block(pos.Side(cube.FaceUp)).(Air); !ok {
 		return false
 	}
 	n.Pitch = (n.Pitch + 1) % 25
-	n.playNote(pos, w)
+	n.playNote(pos, w, u)
 	w.SetBlock(pos, n, &world.SetOpts{DisableBlockUpdates: true, DisableLiquidDisplacement: true})
 	return true
 }
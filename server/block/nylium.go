@@ -0,0 +1,90 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+// Nylium is a variant of netherrack that is covered in fungal growth. Crimson nylium covers the floor of crimson
+// forests and warped nylium that of warped forests.
+type Nylium struct {
+	solid
+	bassDrum
+
+	// Warped specifies if the nylium is warped nylium. If false, the nylium is crimson nylium.
+	Warped bool
+}
+
+// SoilFor ...
+func (n Nylium) SoilFor(block world.Block) bool {
+	switch b := block.(type) {
+	case Fungus, Roots, NetherSprouts:
+		return true
+	case Flower:
+		return b.Type == WitherRose()
+	}
+	return false
+}
+
+// RandomTick turns the nylium back into netherrack if it is covered by a block with a solid bottom face.
+func (n Nylium) RandomTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	above := pos.Side(cube.FaceUp)
+	if w.Block(above).Model().FaceSolid(above, cube.FaceDown, w) {
+		w.SetBlock(pos, Netherrack{}, nil)
+	}
+}
+
+// BoneMeal grows fungi and roots on the nylium around the nylium.
+func (n Nylium) BoneMeal(pos cube.Pos, w *world.World) bool {
+	vegetation := crimsonVegetation
+	if n.Warped {
+		vegetation = warpedVegetation
+	}
+	for i := 0; i < 32; i++ {
+		c := pos.Add(cube.Pos{rand.Intn(7) - 3, rand.Intn(3) - 1, rand.Intn(7) - 3})
+		above := c.Side(cube.FaceUp)
+		if _, air := w.Block(above).(Air); !air {
+			continue
+		}
+		if other, ok := w.Block(c).(Nylium); ok && other.Warped == n.Warped {
+			w.SetBlock(above, vegetation[rand.Intn(len(vegetation))], nil)
+		}
+	}
+	return true
+}
+
+// crimsonVegetation and warpedVegetation are the plants that are picked from when bone meal is used on crimson
+// and warped nylium respectively.
+var (
+	crimsonVegetation = []world.Block{
+		Roots{}, Roots{}, Roots{}, Roots{}, Roots{}, Roots{}, Roots{}, Roots{},
+		Fungus{}, Fungus{Warped: true},
+	}
+	warpedVegetation = []world.Block{
+		Roots{Warped: true}, Roots{Warped: true}, Roots{Warped: true}, Roots{Warped: true}, Roots{Warped: true},
+		NetherSprouts{}, NetherSprouts{}, NetherSprouts{},
+		Fungus{Warped: true}, Fungus{},
+	}
+)
+
+// BreakInfo ...
+func (n Nylium) BreakInfo() BreakInfo {
+	return newBreakInfo(0.4, pickaxeHarvestable, pickaxeEffective, silkTouchOneOf(Netherrack{}, n))
+}
+
+// EncodeItem ...
+func (n Nylium) EncodeItem() (name string, meta int16) {
+	if n.Warped {
+		return "minecraft:warped_nylium", 0
+	}
+	return "minecraft:crimson_nylium", 0
+}
+
+// EncodeBlock ...
+func (n Nylium) EncodeBlock() (string, map[string]any) {
+	if n.Warped {
+		return "minecraft:warped_nylium", nil
+	}
+	return "minecraft:crimson_nylium", nil
+}
@@ -1,7 +1,10 @@
 package block
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
 )
 
 // Obsidian is a dark purple block known for its high blast resistance and strength, most commonly found when
@@ -21,6 +24,21 @@ func (o Obsidian) LightEmissionLevel() uint8 {
 	return 0
 }
 
+// Ignite lights a nether portal in a frame next to the obsidian, if there is a complete one.
+func (o Obsidian) Ignite(pos cube.Pos, w *world.World, _ world.Entity) bool {
+	if o.Crying {
+		return false
+	}
+	for _, face := range append([]cube.Face{cube.FaceUp}, cube.HorizontalFaces()...) {
+		if f, ok := findPortalFrame(pos.Side(face), w); ok {
+			f.fill(w)
+			w.PlaySound(pos.Side(face).Vec3Centre(), sound.Ignite{})
+			return true
+		}
+	}
+	return false
+}
+
 // EncodeItem ...
 func (o Obsidian) EncodeItem() (name string, meta int16) {
 	if o.Crying {
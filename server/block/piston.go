@@ -7,6 +7,7 @@ import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/loot"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
@@ -190,9 +191,9 @@ func (p Piston) push(pos cube.Pos, w *world.World) bool {
 
 		for _, breakPos := range resolver.breakPositions {
 			p.BreakBlocks = append(p.BreakBlocks, breakPos)
-			if b, ok := w.Block(breakPos).(Breakable); ok {
+			if b := w.Block(breakPos); isBreakable(b) {
 				w.SetBlock(breakPos, nil, nil)
-				for _, drop := range b.BreakInfo().Drops(item.ToolNone{}, nil) {
+				for _, drop := range BreakDrops(b, item.ToolNone{}, nil, loot.Context{}) {
 					dropItem(w, drop, breakPos.Vec3Centre())
 				}
 			}
@@ -269,9 +270,9 @@ func (p Piston) pull(pos cube.Pos, w *world.World) bool {
 
 		for _, breakPos := range resolver.breakPositions {
 			p.BreakBlocks = append(p.BreakBlocks, breakPos)
-			if b, ok := w.Block(breakPos).(Breakable); ok {
+			if b := w.Block(breakPos); isBreakable(b) {
 				w.SetBlock(breakPos, nil, nil)
-				for _, drop := range b.BreakInfo().Drops(item.ToolNone{}, nil) {
+				for _, drop := range BreakDrops(b, item.ToolNone{}, nil, loot.Context{}) {
 					dropItem(w, drop, breakPos.Vec3Centre())
 				}
 			}
@@ -0,0 +1,54 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// PowderSnow is a block of loose snow that entities sink into. Entities inside of powder snow slowly freeze and
+// take damage once they are fully frozen, unless they wear leather armour. Powder snow may only be obtained
+// using a bucket.
+type PowderSnow struct {
+	empty
+}
+
+// EntityInside ...
+func (PowderSnow) EntityInside(_ cube.Pos, _ *world.World, e world.Entity) {
+	if fallEntity, ok := e.(fallDistanceEntity); ok {
+		fallEntity.ResetFallDistance()
+	}
+	if flammable, ok := e.(flammableEntity); ok && flammable.OnFireDuration() > 0 {
+		flammable.Extinguish()
+	}
+	if f, ok := e.(freezableEntity); ok {
+		f.Freeze()
+	}
+}
+
+// Bucketable always returns true, as powder snow may always be picked up using a bucket.
+func (PowderSnow) Bucketable() bool {
+	return true
+}
+
+// BreakInfo ...
+func (PowderSnow) BreakInfo() BreakInfo {
+	return newBreakInfo(0.25, alwaysHarvestable, shovelEffective, simpleDrops())
+}
+
+// EncodeBlock ...
+func (PowderSnow) EncodeBlock() (string, map[string]any) {
+	return "minecraft:powder_snow", nil
+}
+
+// freezableEntity represents an entity that freezes while it is inside of powder snow.
+type freezableEntity interface {
+	// Freeze makes the entity freeze further in the current tick, as if it is inside of powder snow.
+	Freeze()
+}
+
+// FreezeDamageSource is used for damage caused by being fully frozen in powder snow.
+type FreezeDamageSource struct{}
+
+func (FreezeDamageSource) ReducedByResistance() bool { return true }
+func (FreezeDamageSource) ReducedByArmour() bool     { return false }
+func (FreezeDamageSource) Fire() bool                { return false }
@@ -19,6 +19,14 @@ type RedstoneBlocking interface {
 	RedstoneBlocking() bool
 }
 
+// ComparatorEmitter represents a block that emits a signal which may be read by a redstone comparator facing away
+// from it, such as an item frame.
+type ComparatorEmitter interface {
+	// ComparatorSignal returns the signal strength, ranging from 0 to 15, read by a comparator from the block at the
+	// position passed.
+	ComparatorSignal(pos cube.Pos, w *world.World) int
+}
+
 // wireNetwork implements a minimally-invasive bolt-on accelerator that performs a breadth-first search through redstone
 // wires in order to more efficiently and compute new redstone wire power levels and determine the order in which other
 // blocks should be updated. This implementation is heavily based off of RedstoneWireTurbo and MCHPRS.
@@ -1,6 +1,9 @@
 package block
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/model"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
@@ -11,7 +14,6 @@ import (
 
 // RedstoneComparator is a redstone component used to maintain, compare, or subtract signal strength, or to measure
 // certain block states (primarily the fullness of containers).
-// TODO: Implement functionality.
 type RedstoneComparator struct {
 	transparent
 
@@ -35,6 +37,13 @@ func (RedstoneComparator) Model() world.BlockModel {
 	return model.Diode{}
 }
 
+// BreakInfo ...
+func (r RedstoneComparator) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(r)).withBreakHandler(func(pos cube.Pos, w *world.World, _ item.User) {
+		updateGateRedstone(pos, w, r.Facing.Face())
+	})
+}
+
 // EncodeItem ...
 func (RedstoneComparator) EncodeItem() (name string, meta int16) {
 	return "minecraft:comparator", 0
@@ -65,7 +74,11 @@ func (r RedstoneComparator) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec
 	r.Facing = user.Rotation().Direction().Opposite()
 
 	place(w, pos, r, user, ctx)
-	return placed(ctx)
+	if placed(ctx) {
+		r.RedstoneUpdate(pos, w)
+		return true
+	}
+	return false
 }
 
 // NeighbourUpdateTick ...
@@ -80,9 +93,130 @@ func (r RedstoneComparator) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World)
 func (r RedstoneComparator) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
 	r.Subtract = !r.Subtract
 	w.SetBlock(pos, r, nil)
+	r.RedstoneUpdate(pos, w)
 	return false
 }
 
+// RedstoneUpdate ...
+func (r RedstoneComparator) RedstoneUpdate(pos cube.Pos, w *world.World) {
+	if r.outputStrength(pos, w) != r.Power {
+		w.ScheduleBlockUpdate(pos, time.Millisecond*100)
+	}
+}
+
+// ScheduledTick ...
+func (r RedstoneComparator) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	power := r.outputStrength(pos, w)
+	if power == r.Power {
+		return
+	}
+	r.Power, r.Powered = power, power > 0
+	w.SetBlock(pos, r, nil)
+	updateGateRedstone(pos, w, r.Facing.Face().Opposite())
+}
+
+// Source ...
+func (r RedstoneComparator) Source() bool {
+	return r.Power > 0
+}
+
+// WeakPower ...
+func (r RedstoneComparator) WeakPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if face == r.Facing.Face() {
+		return r.Power
+	}
+	return 0
+}
+
+// StrongPower ...
+func (r RedstoneComparator) StrongPower(pos cube.Pos, face cube.Face, w *world.World, accountForDust bool) int {
+	return r.WeakPower(pos, face, w, accountForDust)
+}
+
+// outputStrength returns the strength of the signal that the comparator should output, based on its rear and side
+// inputs and whether it is in subtract mode.
+func (r RedstoneComparator) outputStrength(pos cube.Pos, w *world.World) int {
+	rear, side := r.rearStrength(pos, w), r.sideStrength(pos, w)
+	if r.Subtract {
+		return max(rear-side, 0)
+	}
+	if side > rear {
+		return 0
+	}
+	return rear
+}
+
+// rearStrength returns the strength of the signal received at the rear of the comparator. A ComparatorEmitter
+// directly behind the comparator, or behind a solid block directly behind it, is read instead of its redstone power
+// if its signal is stronger.
+func (r RedstoneComparator) rearStrength(pos cube.Pos, w *world.World) int {
+	face := r.Facing.Face()
+	rearPos := pos.Side(face)
+	rear := w.Block(rearPos)
+	if e, ok := rear.(ComparatorEmitter); ok {
+		return e.ComparatorSignal(rearPos, w)
+	}
+	power := w.RedstonePower(rearPos, face, true)
+	if power >= 15 || !solidBlock(rear, rearPos, w) {
+		return power
+	}
+	behindPos := rearPos.Side(face)
+	switch b := w.Block(behindPos).(type) {
+	case ItemFrame:
+		// Item frames can only be read through the block that they are attached to.
+		if b.Facing != face.Opposite() {
+			return power
+		}
+		return max(power, b.ComparatorSignal(behindPos, w))
+	case ComparatorEmitter:
+		return max(power, b.ComparatorSignal(behindPos, w))
+	}
+	return power
+}
+
+// sideStrength returns the strongest signal received at either side of the comparator. Only redstone wire, redstone
+// blocks and other diodes power a comparator from the side.
+func (r RedstoneComparator) sideStrength(pos cube.Pos, w *world.World) (strength int) {
+	for _, face := range []cube.Face{r.Facing.RotateLeft().Face(), r.Facing.RotateRight().Face()} {
+		sidePos := pos.Side(face)
+		switch b := w.Block(sidePos).(type) {
+		case RedstoneWire, RedstoneBlock, RedstoneRepeater, RedstoneComparator:
+			strength = max(strength, b.(world.Conductor).WeakPower(sidePos, face, w, true))
+		}
+	}
+	return strength
+}
+
+// solidBlock checks if the block passed at the position passed is solid on all of its faces, so that a comparator
+// may read a signal through it.
+func solidBlock(b world.Block, pos cube.Pos, w *world.World) bool {
+	for _, f := range cube.Faces() {
+		if !b.Model().FaceSolid(pos, f, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateComparators updates redstone comparators reading the block at the position passed, either directly or
+// through a solid block. It should be called when the signal emitted by a ComparatorEmitter changes.
+func updateComparators(pos cube.Pos, w *world.World) {
+	for _, face := range cube.HorizontalFaces() {
+		sidePos := pos.Side(face)
+		side := w.Block(sidePos)
+		if c, ok := side.(RedstoneComparator); ok {
+			c.RedstoneUpdate(sidePos, w)
+			continue
+		}
+		if !solidBlock(side, sidePos, w) {
+			continue
+		}
+		if c, ok := w.Block(sidePos.Side(face)).(RedstoneComparator); ok {
+			c.RedstoneUpdate(sidePos.Side(face), w)
+		}
+	}
+}
+
 // EncodeNBT ...
 func (r RedstoneComparator) EncodeNBT() map[string]any {
 	return map[string]any{"OutputSignal": int32(r.Power)}
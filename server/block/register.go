@@ -22,6 +22,7 @@ func init() {
 	world.RegisterBlock(Bookshelf{})
 	world.RegisterBlock(Bricks{})
 	world.RegisterBlock(Calcite{})
+	world.RegisterBlock(ChorusPlant{})
 	world.RegisterBlock(Clay{})
 	world.RegisterBlock(Coal{})
 	world.RegisterBlock(Cobblestone{Mossy: true})
@@ -44,8 +45,12 @@ func init() {
 	world.RegisterBlock(Emerald{})
 	world.RegisterBlock(EnchantingTable{})
 	world.RegisterBlock(EndBricks{})
+	world.RegisterBlock(EndGateway{})
+	world.RegisterBlock(EndPortal{})
 	world.RegisterBlock(EndStone{})
 	world.RegisterBlock(FletchingTable{})
+	world.RegisterBlock(Fungus{Warped: true})
+	world.RegisterBlock(Fungus{})
 	world.RegisterBlock(GlassPane{})
 	world.RegisterBlock(Glass{})
 	world.RegisterBlock(Glowstone{})
@@ -55,6 +60,7 @@ func init() {
 	world.RegisterBlock(Grass{})
 	world.RegisterBlock(Gravel{})
 	world.RegisterBlock(Honeycomb{})
+	world.RegisterBlock(Ice{})
 	world.RegisterBlock(InvisibleBedrock{})
 	world.RegisterBlock(IronBars{})
 	world.RegisterBlock(Iron{})
@@ -74,6 +80,8 @@ func init() {
 	world.RegisterBlock(Netherite{})
 	world.RegisterBlock(Netherrack{})
 	world.RegisterBlock(Note{})
+	world.RegisterBlock(Nylium{Warped: true})
+	world.RegisterBlock(Nylium{})
 	world.RegisterBlock(Obsidian{Crying: true})
 	world.RegisterBlock(Obsidian{})
 	world.RegisterBlock(PackedIce{})
@@ -81,6 +89,7 @@ func init() {
 	world.RegisterBlock(Podzol{})
 	world.RegisterBlock(PolishedBlackstoneBrick{Cracked: true})
 	world.RegisterBlock(PolishedBlackstoneBrick{})
+	world.RegisterBlock(PowderSnow{})
 	world.RegisterBlock(QuartzBricks{})
 	world.RegisterBlock(RawCopper{})
 	world.RegisterBlock(RawGold{})
@@ -89,6 +98,8 @@ func init() {
 	world.RegisterBlock(RedstoneLamp{Lit: true})
 	world.RegisterBlock(RedstoneLamp{})
 	world.RegisterBlock(ReinforcedDeepslate{})
+	world.RegisterBlock(Roots{Warped: true})
+	world.RegisterBlock(Roots{})
 	world.RegisterBlock(Sand{Red: true})
 	world.RegisterBlock(Sand{})
 	world.RegisterBlock(SeaLantern{})
@@ -101,6 +112,11 @@ func init() {
 	world.RegisterBlock(Sponge{Wet: true})
 	world.RegisterBlock(Sponge{})
 	world.RegisterBlock(SporeBlossom{})
+	world.RegisterBlock(Mushroom{})
+	world.RegisterBlock(Mushroom{Red: true})
+	world.RegisterBlock(Mycelium{})
+	world.RegisterBlock(BambooSapling{})
+	world.RegisterBlock(BambooSapling{Ready: true})
 	world.RegisterBlock(Stone{Smooth: true})
 	world.RegisterBlock(Stone{})
 	world.RegisterBlock(StonePressurePlate{})
@@ -136,7 +152,9 @@ func init() {
 	registerAll(allCarrots())
 	registerAll(allChains())
 	registerAll(allChests())
+	registerAll(allChorusFlowers())
 	registerAll(allCocoaBeans())
+	registerAll(allCommandBlocks())
 	registerAll(allComposters())
 	registerAll(allConcrete())
 	registerAll(allConcretePowder())
@@ -148,6 +166,7 @@ func init() {
 	registerAll(allDoubleFlowers())
 	registerAll(allDoubleTallGrass())
 	registerAll(allDroppers())
+	registerAll(allEndPortalFrames())
 	registerAll(allEnderChests())
 	registerAll(allFarmland())
 	registerAll(allFence())
@@ -169,12 +188,14 @@ func init() {
 	registerAll(allLevers())
 	registerAll(allLecterns())
 	registerAll(allLight())
+	registerAll(allLightningRods())
 	registerAll(allLitPumpkins())
 	registerAll(allLogs())
 	registerAll(allLooms())
 	registerAll(allMelonStems())
 	registerAll(allMuddyMangroveRoots())
 	registerAll(allNetherBricks())
+	registerAll(allNetherPortals())
 	registerAll(allNetherWart())
 	//registerAll(allObservers())
 	registerAll(allPistonArmCollisions())
@@ -191,20 +212,24 @@ func init() {
 	registerAll(allRedstoneTorches())
 	registerAll(allRedstoneWires())
 	registerAll(allSandstones())
+	registerAll(allSaplings())
 	registerAll(allSeaPickles())
 	registerAll(allSigns())
 	registerAll(allSkulls())
 	registerAll(allSlabs())
 	registerAll(allSmokers())
+	registerAll(allSnowLayers())
 	registerAll(allStainedGlass())
 	registerAll(allStainedGlassPane())
 	registerAll(allStainedTerracotta())
 	registerAll(allStairs())
 	registerAll(allStoneBricks())
 	registerAll(allStonecutters())
+	registerAll(allStructureBlocks())
 	registerAll(allSugarCane())
 	registerAll(allTorches())
 	registerAll(allTrapdoors())
+	registerAll(allVines())
 	registerAll(allWalls())
 	registerAll(allWater())
 	registerAll(allWheat())
@@ -212,6 +237,7 @@ func init() {
 	registerAll(allWoodPressurePlates())
 	registerAll(allWool())
 	registerAll(allDecoratedPots())
+	registerAll(allBamboo())
 }
 
 func init() {
@@ -239,6 +265,8 @@ func init() {
 	world.RegisterItem(Chain{})
 	world.RegisterItem(Chest{})
 	world.RegisterItem(ChiseledQuartz{})
+	world.RegisterItem(ChorusFlower{})
+	world.RegisterItem(ChorusPlant{})
 	world.RegisterItem(Clay{})
 	world.RegisterItem(Coal{})
 	world.RegisterItem(Cobblestone{Mossy: true})
@@ -264,9 +292,12 @@ func init() {
 	world.RegisterItem(Emerald{})
 	world.RegisterItem(EnchantingTable{})
 	world.RegisterItem(EndBricks{})
+	world.RegisterItem(EndPortalFrame{})
 	world.RegisterItem(EndStone{})
 	world.RegisterItem(EnderChest{})
 	world.RegisterItem(Farmland{})
+	world.RegisterItem(Fungus{Warped: true})
+	world.RegisterItem(Fungus{})
 	world.RegisterItem(Furnace{})
 	world.RegisterItem(GlassPane{})
 	world.RegisterItem(Glass{})
@@ -280,6 +311,7 @@ func init() {
 	world.RegisterItem(HayBale{})
 	world.RegisterItem(Honeycomb{})
 	world.RegisterItem(Hopper{})
+	world.RegisterItem(Ice{})
 	world.RegisterItem(InvisibleBedrock{})
 	world.RegisterItem(IronBars{})
 	world.RegisterItem(Iron{})
@@ -291,6 +323,7 @@ func init() {
 	world.RegisterItem(Lapis{})
 	world.RegisterItem(Lever{})
 	world.RegisterItem(Lectern{})
+	world.RegisterItem(LightningRod{})
 	world.RegisterItem(LitPumpkin{})
 	world.RegisterItem(Loom{})
 	world.RegisterItem(MelonSeeds{})
@@ -309,6 +342,8 @@ func init() {
 	world.RegisterItem(Netherite{})
 	world.RegisterItem(Netherrack{})
 	world.RegisterItem(Note{Pitch: 24})
+	world.RegisterItem(Nylium{Warped: true})
+	world.RegisterItem(Nylium{})
 	world.RegisterItem(Observer{})
 	world.RegisterItem(Obsidian{Crying: true})
 	world.RegisterItem(Obsidian{})
@@ -341,6 +376,8 @@ func init() {
 	world.RegisterItem(RedstoneTorch{})
 	world.RegisterItem(RedstoneWire{})
 	world.RegisterItem(ReinforcedDeepslate{})
+	world.RegisterItem(Roots{Warped: true})
+	world.RegisterItem(Roots{})
 	world.RegisterItem(Sand{Red: true})
 	world.RegisterItem(Sand{})
 	world.RegisterItem(SeaLantern{})
@@ -350,14 +387,21 @@ func init() {
 	world.RegisterItem(SmithingTable{})
 	world.RegisterItem(Smoker{})
 	world.RegisterItem(Snow{})
+	world.RegisterItem(SnowLayer{})
 	world.RegisterItem(SoulSand{})
 	world.RegisterItem(SoulSoil{})
 	world.RegisterItem(Sponge{Wet: true})
 	world.RegisterItem(Sponge{})
 	world.RegisterItem(SporeBlossom{})
+	world.RegisterItem(Mushroom{})
+	world.RegisterItem(Mushroom{Red: true})
+	world.RegisterItem(Mycelium{})
+	world.RegisterItem(Bamboo{})
+	world.RegisterItem(Vines{})
 	world.RegisterItem(Stonecutter{})
 	world.RegisterItem(Stone{Smooth: true})
 	world.RegisterItem(Stone{})
+	world.RegisterItem(StructureBlock{})
 	world.RegisterItem(SugarCane{})
 	world.RegisterItem(TNT{})
 	world.RegisterItem(Terracotta{})
@@ -369,6 +413,7 @@ func init() {
 	world.RegisterItem(item.Bucket{Content: item.LiquidBucketContent(Lava{})})
 	world.RegisterItem(item.Bucket{Content: item.LiquidBucketContent(Water{})})
 	world.RegisterItem(item.Bucket{Content: item.MilkBucketContent()})
+	world.RegisterItem(item.Bucket{Content: item.BlockBucketContent(PowderSnow{})})
 
 	for _, b := range allLight() {
 		world.RegisterItem(b.(world.Item))
@@ -379,6 +424,9 @@ func init() {
 	for _, c := range allCoralBlocks() {
 		world.RegisterItem(c.(world.Item))
 	}
+	for _, t := range CommandBlockTypes() {
+		world.RegisterItem(CommandBlock{Type: t})
+	}
 	for _, t := range SandstoneTypes() {
 		world.RegisterItem(Sandstone{Type: t, Red: true})
 		world.RegisterItem(Sandstone{Type: t})
@@ -403,6 +451,9 @@ func init() {
 		world.RegisterItem(StainedTerracotta{Colour: c})
 		world.RegisterItem(Wool{Colour: c})
 	}
+	for _, w := range SaplingWoodTypes() {
+		world.RegisterItem(Sapling{Wood: w})
+	}
 	for _, w := range WoodTypes() {
 		if w != WarpedWood() && w != CrimsonWood() {
 			world.RegisterItem(Leaves{Wood: w, Persistent: true})
@@ -0,0 +1,153 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Sapling is a non-solid plant that grows into a tree. Saplings grow over time when they receive enough light,
+// and may be grown instantly by using bone meal on them.
+type Sapling struct {
+	empty
+	transparent
+	sourceWaterDisplacer
+
+	// Wood is the type of wood of the tree that the sapling grows into.
+	Wood WoodType
+	// Aged is true if the sapling is in its second stage of growth. An aged sapling grows into a tree the next
+	// time it grows.
+	Aged bool
+}
+
+// TreeGrower grows trees from saplings and huge fungi from fungi. A TreeGrower must be registered for a WoodType
+// using RegisterTreeGrower for saplings and fungi of that WoodType to grow.
+type TreeGrower interface {
+	// GrowTree attempts to grow a tree from the sapling at the position passed. GrowTree returns true if the
+	// tree was grown, in which case the sapling was replaced.
+	GrowTree(pos cube.Pos, w *world.World, r *rand.Rand) bool
+}
+
+var (
+	treeGrowerMu sync.RWMutex
+	treeGrowers  = map[WoodType]TreeGrower{}
+)
+
+// RegisterTreeGrower registers the TreeGrower used to grow saplings of the WoodType passed. A TreeGrower
+// previously registered for the WoodType is replaced.
+func RegisterTreeGrower(wood WoodType, g TreeGrower) {
+	treeGrowerMu.Lock()
+	defer treeGrowerMu.Unlock()
+	treeGrowers[wood] = g
+}
+
+// treeGrower returns the TreeGrower registered for the WoodType passed, if any.
+func treeGrower(wood WoodType) (TreeGrower, bool) {
+	treeGrowerMu.RLock()
+	defer treeGrowerMu.RUnlock()
+	g, ok := treeGrowers[wood]
+	return g, ok
+}
+
+// RandomTick ...
+func (s Sapling) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if w.Light(pos.Side(cube.FaceUp)) >= 9 && r.Intn(7) == 0 {
+		s.grow(pos, w, r)
+	}
+}
+
+// BoneMeal ...
+func (s Sapling) BoneMeal(pos cube.Pos, w *world.World) bool {
+	if rand.Float64() < 0.45 {
+		s.grow(pos, w, rand.New(rand.NewSource(rand.Int63())))
+	}
+	return true
+}
+
+// grow advances the sapling to its next stage of growth, growing a tree if the sapling was already aged.
+func (s Sapling) grow(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if !s.Aged {
+		s.Aged = true
+		w.SetBlock(pos, s, nil)
+		return
+	}
+	if g, ok := treeGrower(s.Wood); ok {
+		g.GrowTree(pos, w, r)
+	}
+}
+
+// NeighbourUpdateTick ...
+func (s Sapling) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !supportsVegetation(s, w.Block(pos.Side(cube.FaceDown))) {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: s})
+		dropItem(w, item.NewStack(Sapling{Wood: s.Wood}, 1), pos.Vec3Centre())
+	}
+}
+
+// UseOnBlock ...
+func (s Sapling) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, s)
+	if !used {
+		return false
+	}
+	if !supportsVegetation(s, w.Block(pos.Side(cube.FaceDown))) {
+		return false
+	}
+
+	place(w, pos, s, user, ctx)
+	return placed(ctx)
+}
+
+// HasLiquidDrops ...
+func (s Sapling) HasLiquidDrops() bool {
+	return true
+}
+
+// FlammabilityInfo ...
+func (s Sapling) FlammabilityInfo() FlammabilityInfo {
+	return newFlammabilityInfo(0, 0, true)
+}
+
+// BreakInfo ...
+func (s Sapling) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(Sapling{Wood: s.Wood}))
+}
+
+// CompostChance ...
+func (s Sapling) CompostChance() float64 {
+	return 0.3
+}
+
+// FuelInfo ...
+func (s Sapling) FuelInfo() item.FuelInfo {
+	return newFuelInfo(time.Second * 5)
+}
+
+// EncodeItem ...
+func (s Sapling) EncodeItem() (name string, meta int16) {
+	return "minecraft:" + s.Wood.String() + "_sapling", 0
+}
+
+// EncodeBlock ...
+func (s Sapling) EncodeBlock() (string, map[string]any) {
+	return "minecraft:" + s.Wood.String() + "_sapling", map[string]any{"age_bit": s.Aged}
+}
+
+// SaplingWoodTypes returns all wood types that have a sapling.
+func SaplingWoodTypes() []WoodType {
+	return []WoodType{OakWood(), SpruceWood(), BirchWood(), JungleWood(), AcaciaWood(), DarkOakWood(), Cherry()}
+}
+
+// allSaplings returns all possible states of a sapling.
+func allSaplings() (saplings []world.Block) {
+	for _, w := range SaplingWoodTypes() {
+		saplings = append(saplings, Sapling{Wood: w}, Sapling{Wood: w, Aged: true})
+	}
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/go-gl/mathgl/mgl64"
 )
 
@@ -63,9 +64,60 @@ func (s Skull) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.W
 		s.Attach = WallAttachment(face.Direction())
 	}
 	place(w, pos, s, user, ctx)
+	if placed(ctx) && s.Type == WitherSkeletonSkull() {
+		trySpawnWither(pos, w)
+	}
 	return placed(ctx)
 }
 
+// trySpawnWither checks if the wither skeleton skull at the position passed completes the structure used to
+// summon a wither: a T-shape of four soul sand or soul soil blocks with three wither skeleton skulls on top. If
+// so, the structure is removed and a wither is spawned in its place. The blocks next to the bottom soul block
+// must be air for the structure to be valid.
+func trySpawnWither(pos cube.Pos, w *world.World) {
+	for _, axis := range []cube.Pos{{1, 0, 0}, {0, 0, 1}} {
+		for i := -1; i <= 1; i++ {
+			centre := pos.Add(cube.Pos{axis[0] * i, 0, axis[2] * i})
+			skulls := []cube.Pos{centre.Sub(axis), centre, centre.Add(axis)}
+			body := centre.Side(cube.FaceDown)
+			souls := []cube.Pos{body.Sub(axis), body, body.Add(axis), body.Side(cube.FaceDown)}
+			corners := []cube.Pos{souls[3].Sub(axis), souls[3].Add(axis)}
+			if !witherStructure(w, skulls, souls, corners) {
+				continue
+			}
+			for _, p := range append(skulls, souls...) {
+				w.AddParticle(p.Vec3Centre(), particle.BlockBreak{Block: w.Block(p)})
+				w.SetBlock(p, nil, nil)
+			}
+			w.AddEntity(w.EntityRegistry().Config().Wither(body.Side(cube.FaceDown).Vec3Middle()))
+			return
+		}
+	}
+}
+
+// witherStructure checks if the positions passed hold wither skeleton skulls, soul sand or soul soil and air
+// respectively.
+func witherStructure(w *world.World, skulls, souls, corners []cube.Pos) bool {
+	for _, p := range skulls {
+		if s, ok := w.Block(p).(Skull); !ok || s.Type != WitherSkeletonSkull() {
+			return false
+		}
+	}
+	for _, p := range souls {
+		switch w.Block(p).(type) {
+		case SoulSand, SoulSoil:
+		default:
+			return false
+		}
+	}
+	for _, p := range corners {
+		if _, ok := w.Block(p).(Air); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // SideClosed ...
 func (Skull) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 	return false
@@ -0,0 +1,117 @@
+package block
+
+import (
+	"math/rand"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/model"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// SnowLayer is a thin layer of snow that accumulates on top of blocks during snowfall. Multiple layers may be
+// stacked on top of each other until the snow is as high as a full block.
+type SnowLayer struct {
+	transparent
+
+	// Height is the height of the snow layer, ranging from 0-7. A snow layer of height 0 is a single layer, while
+	// a snow layer of height 7 is as high as a full block.
+	Height int
+	// Covered specifies if the snow layer covers a plant, such as short grass.
+	Covered bool
+}
+
+// Model ...
+func (s SnowLayer) Model() world.BlockModel {
+	return model.SnowLayer{Height: s.Height}
+}
+
+// ReplaceableBy only allows a single layer of snow to be replaced.
+func (s SnowLayer) ReplaceableBy(world.Block) bool {
+	return s.Height == 0
+}
+
+// SideClosed ...
+func (SnowLayer) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// HasLiquidDrops ...
+func (SnowLayer) HasLiquidDrops() bool {
+	return false
+}
+
+// RandomTick melts the snow layer if the block light around it is too bright.
+func (SnowLayer) RandomTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	if w.BlockLight(pos) > 11 {
+		w.SetBlock(pos, nil, nil)
+	}
+}
+
+// NeighbourUpdateTick ...
+func (s SnowLayer) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if !s.canSurvive(pos, w) {
+		w.SetBlock(pos, nil, nil)
+	}
+}
+
+// canSurvive checks if the snow layer can exist at the position passed.
+func (SnowLayer) canSurvive(pos cube.Pos, w *world.World) bool {
+	below := pos.Side(cube.FaceDown)
+	switch b := w.Block(below).(type) {
+	case SnowLayer:
+		return b.Height == 7
+	case Ice, PackedIce, BlueIce, Barrier:
+		return false
+	default:
+		return b.Model().FaceSolid(below, cube.FaceUp, w)
+	}
+}
+
+// UseOnBlock ...
+func (s SnowLayer) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	if existing, ok := w.Block(pos).(SnowLayer); ok && existing.Height < 7 {
+		existing.Height++
+		place(w, pos, existing, user, ctx)
+		return placed(ctx)
+	}
+	pos, _, used := firstReplaceable(w, pos, face, s)
+	if !used || !s.canSurvive(pos, w) {
+		return false
+	}
+	place(w, pos, s, user, ctx)
+	return placed(ctx)
+}
+
+// BreakInfo ...
+func (s SnowLayer) BreakInfo() BreakInfo {
+	return newBreakInfo(0.1, alwaysHarvestable, shovelEffective, func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+		if hasSilkTouch(enchantments) {
+			return []item.Stack{item.NewStack(SnowLayer{}, s.Height+1)}
+		}
+		if shovelEffective(t) {
+			// Snow layers only drop snowballs when broken using a shovel.
+			return []item.Stack{item.NewStack(item.Snowball{}, s.Height+1)}
+		}
+		return nil
+	})
+}
+
+// EncodeItem ...
+func (SnowLayer) EncodeItem() (name string, meta int16) {
+	return "minecraft:snow_layer", 0
+}
+
+// EncodeBlock ...
+func (s SnowLayer) EncodeBlock() (string, map[string]any) {
+	return "minecraft:snow_layer", map[string]any{"height": int32(s.Height), "covered_bit": s.Covered}
+}
+
+// allSnowLayers ...
+func allSnowLayers() (b []world.Block) {
+	for i := 0; i < 8; i++ {
+		b = append(b, SnowLayer{Height: i}, SnowLayer{Height: i, Covered: true})
+	}
+	return
+}
@@ -0,0 +1,234 @@
+package block
+
+import (
+	"fmt"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// StructureBlock is a block used to save structures and to load them elsewhere. Structure blocks may only be
+// edited by players in creative mode and are triggered either from their interface or by a redstone signal.
+type StructureBlock struct {
+	solid
+
+	// Mode is the mode of the structure block, which determines what it does when triggered.
+	Mode StructureBlockMode
+	// Name is the name of the structure saved or loaded by the structure block. Corner structure blocks with
+	// the same name as a save structure block mark the opposite corner of the region that it saves.
+	Name string
+	// DataField is the text held by a data structure block.
+	DataField string
+	// Offset is the offset from the structure block to the lowest corner of its structure.
+	Offset cube.Pos
+	// Size is the size of the structure saved by the structure block. If any of its components is 0, the size is
+	// detected from a corner structure block with the same name when the structure block is triggered.
+	Size cube.Pos
+	// Rotation is the number of quarter turns that a loaded structure is rotated clockwise.
+	Rotation int
+	// MirrorX and MirrorZ specify if a loaded structure is mirrored along the X and Z axis respectively.
+	MirrorX, MirrorZ bool
+	// Integrity is the chance between 0 and 1 that each block of a loaded structure is placed. If Integrity is 0
+	// or 1, all blocks are placed.
+	Integrity float64
+	// Seed is the seed used to select the blocks omitted from a loaded structure if Integrity is not 1.
+	Seed int64
+	// IncludeEntities specifies if entities are saved and loaded along with the blocks of the structure.
+	IncludeEntities bool
+	// RemoveBlocks specifies if the blocks of a loaded structure are left out, so that only its entities are
+	// placed.
+	RemoveBlocks bool
+	// ShowBoundingBox specifies if the bounding box of the structure is shown to players.
+	ShowBoundingBox bool
+	// Powered is true if the structure block is currently receiving a redstone signal.
+	Powered bool
+}
+
+// structureCornerReach is the maximum distance from a save structure block at which a corner structure block
+// with the same name is detected.
+const structureCornerReach = 32
+
+// Trigger saves or loads the structure of the structure block at the position passed, depending on its Mode.
+// Structure blocks in other modes do nothing. An error is returned if the structure could not be saved or
+// loaded.
+func (s StructureBlock) Trigger(pos cube.Pos, w *world.World) error {
+	switch s.Mode {
+	case StructureBlockSave():
+		if s.Name == "" {
+			return fmt.Errorf("structure block has no structure name")
+		}
+		size := s.Size
+		if size[0] <= 0 || size[1] <= 0 || size[2] <= 0 {
+			var ok bool
+			if s.Offset, size, ok = s.detectCorner(pos, w); !ok {
+				return fmt.Errorf("no corner structure block named %v found", s.Name)
+			}
+			s.Size = size
+			w.SetBlock(pos, s, nil)
+		}
+		t := w.CaptureStructure(pos.Add(s.Offset), [3]int{size[0], size[1], size[2]}, s.IncludeEntities)
+		return w.SaveStructure(s.Name, t)
+	case StructureBlockLoad():
+		t, ok, err := w.LoadStructure(s.Name)
+		if err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("structure %v is not available", s.Name)
+		}
+		w.PlaceStructure(pos.Add(s.Offset), t, s.Placement())
+	}
+	return nil
+}
+
+// Placement returns the world.StructurePlacement with which the structure block loads its structure.
+func (s StructureBlock) Placement() world.StructurePlacement {
+	return world.StructurePlacement{
+		Rotation:       s.Rotation,
+		MirrorX:        s.MirrorX,
+		MirrorZ:        s.MirrorZ,
+		Integrity:      s.Integrity,
+		Seed:           s.Seed,
+		IgnoreBlocks:   s.RemoveBlocks,
+		IgnoreEntities: !s.IncludeEntities,
+	}
+}
+
+// detectCorner finds the corner structure block with the same name as the structure block closest to the
+// position passed and returns the offset and size of the region between the two structure blocks.
+func (s StructureBlock) detectCorner(pos cube.Pos, w *world.World) (offset, size cube.Pos, found bool) {
+	nearest := structureCornerReach * structureCornerReach * 3
+	for x := -structureCornerReach; x <= structureCornerReach; x++ {
+		for y := -structureCornerReach; y <= structureCornerReach; y++ {
+			for z := -structureCornerReach; z <= structureCornerReach; z++ {
+				dist := x*x + y*y + z*z
+				if abs(x) < 2 || abs(y) < 2 || abs(z) < 2 || dist >= nearest {
+					// Corners that do not leave any room between them and the structure block are ignored.
+					continue
+				}
+				c, ok := w.Block(pos.Add(cube.Pos{x, y, z})).(StructureBlock)
+				if !ok || c.Mode != StructureBlockCorner() || c.Name != s.Name {
+					continue
+				}
+				nearest, found = dist, true
+				offset = cube.Pos{min(x, 0) + 1, min(y, 0) + 1, min(z, 0) + 1}
+				size = cube.Pos{abs(x) - 1, abs(y) - 1, abs(z) - 1}
+			}
+		}
+	}
+	return offset, size, found
+}
+
+// RedstoneUpdate triggers the structure block when it starts receiving a redstone signal.
+func (s StructureBlock) RedstoneUpdate(pos cube.Pos, w *world.World) {
+	powered := receivedRedstonePower(pos, w)
+	if powered == s.Powered {
+		return
+	}
+	s.Powered = powered
+	w.SetBlock(pos, s, &world.SetOpts{DisableBlockUpdates: true})
+	if powered {
+		_ = s.Trigger(pos, w)
+	}
+}
+
+// Activate opens the interface of the structure block for users in creative mode.
+func (s StructureBlock) Activate(pos cube.Pos, _ cube.Face, _ *world.World, u item.User, _ *item.UseContext) bool {
+	if gm, ok := u.(interface{ GameMode() world.GameMode }); !ok || !gm.GameMode().CreativeInventory() {
+		return false
+	}
+	if opener, ok := u.(ContainerOpener); ok {
+		opener.OpenBlockContainer(pos)
+	}
+	return true
+}
+
+// UseOnBlock ...
+func (s StructureBlock) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, s)
+	if !used {
+		return
+	}
+	s.Mode, s.Integrity = StructureBlockSave(), 1
+	s.Offset, s.Size = cube.Pos{0, 1, 0}, cube.Pos{5, 5, 5}
+	place(w, pos, s, user, ctx)
+	return placed(ctx)
+}
+
+// BreakInfo ...
+func (s StructureBlock) BreakInfo() BreakInfo {
+	return newBreakInfo(-1, neverHarvestable, nothingEffective, simpleDrops())
+}
+
+// DecodeNBT ...
+func (s StructureBlock) DecodeNBT(data map[string]any) any {
+	s.Name = nbtconv.String(data, "structureName")
+	s.DataField = nbtconv.String(data, "dataField")
+	s.Offset = cube.Pos{int(nbtconv.Int32(data, "xStructureOffset")), int(nbtconv.Int32(data, "yStructureOffset")), int(nbtconv.Int32(data, "zStructureOffset"))}
+	s.Size = cube.Pos{int(nbtconv.Int32(data, "xStructureSize")), int(nbtconv.Int32(data, "yStructureSize")), int(nbtconv.Int32(data, "zStructureSize"))}
+	s.Rotation = int(nbtconv.Uint8(data, "rotation"))
+	mirror := nbtconv.Uint8(data, "mirror")
+	s.MirrorX, s.MirrorZ = mirror&1 != 0, mirror&2 != 0
+	s.Integrity = float64(nbtconv.Float32(data, "integrity")) / 100
+	s.Seed = nbtconv.Int64(data, "seed")
+	s.IncludeEntities = !nbtconv.Bool(data, "ignoreEntities")
+	s.RemoveBlocks = nbtconv.Bool(data, "removeBlocks")
+	s.ShowBoundingBox = nbtconv.Bool(data, "showBoundingBox")
+	s.Powered = nbtconv.Bool(data, "isPowered")
+	return s
+}
+
+// EncodeNBT ...
+func (s StructureBlock) EncodeNBT() map[string]any {
+	var mirror uint8
+	if s.MirrorX {
+		mirror |= 1
+	}
+	if s.MirrorZ {
+		mirror |= 2
+	}
+	return map[string]any{
+		"id":               "StructureBlock",
+		"data":             int32(s.Mode.Uint8()),
+		"structureName":    s.Name,
+		"dataField":        s.DataField,
+		"xStructureOffset": int32(s.Offset[0]),
+		"yStructureOffset": int32(s.Offset[1]),
+		"zStructureOffset": int32(s.Offset[2]),
+		"xStructureSize":   int32(s.Size[0]),
+		"yStructureSize":   int32(s.Size[1]),
+		"zStructureSize":   int32(s.Size[2]),
+		"rotation":         uint8(s.Rotation % 4),
+		"mirror":           mirror,
+		"integrity":        float32(s.Integrity * 100),
+		"seed":             s.Seed,
+		"ignoreEntities":   boolByte(!s.IncludeEntities),
+		"includePlayers":   uint8(0),
+		"removeBlocks":     boolByte(s.RemoveBlocks),
+		"showBoundingBox":  boolByte(s.ShowBoundingBox),
+		"isPowered":        boolByte(s.Powered),
+		"redstoneSaveMode": int32(0),
+		"animationMode":    uint8(0),
+		"animationSeconds": float32(0),
+	}
+}
+
+// EncodeItem ...
+func (StructureBlock) EncodeItem() (name string, meta int16) {
+	return "minecraft:structure_block", 0
+}
+
+// EncodeBlock ...
+func (s StructureBlock) EncodeBlock() (string, map[string]any) {
+	return "minecraft:structure_block", map[string]any{"structure_block_type": s.Mode.String()}
+}
+
+// allStructureBlocks returns all possible structure blocks.
+func allStructureBlocks() (blocks []world.Block) {
+	for _, m := range StructureBlockModes() {
+		blocks = append(blocks, StructureBlock{Mode: m})
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package block
+
+// StructureBlockMode represents the mode of a structure block, which determines what the structure block does
+// when it is triggered.
+type StructureBlockMode struct {
+	structureBlockMode
+}
+
+// StructureBlockData returns the data structure block mode. Data structure blocks mark positions within a
+// structure using the text in their data field.
+func StructureBlockData() StructureBlockMode {
+	return StructureBlockMode{0}
+}
+
+// StructureBlockSave returns the save structure block mode. Save structure blocks save the region they cover
+// as a structure when triggered.
+func StructureBlockSave() StructureBlockMode {
+	return StructureBlockMode{1}
+}
+
+// StructureBlockLoad returns the load structure block mode. Load structure blocks place the structure with
+// their name when triggered.
+func StructureBlockLoad() StructureBlockMode {
+	return StructureBlockMode{2}
+}
+
+// StructureBlockCorner returns the corner structure block mode. Corner structure blocks mark the opposite
+// corner of the region saved by a save structure block with the same name.
+func StructureBlockCorner() StructureBlockMode {
+	return StructureBlockMode{3}
+}
+
+// StructureBlockExport returns the export structure block mode. Export structure blocks allow the region they
+// cover to be exported to a file by the client.
+func StructureBlockExport() StructureBlockMode {
+	return StructureBlockMode{5}
+}
+
+// StructureBlockModes returns all structure block modes.
+func StructureBlockModes() []StructureBlockMode {
+	return []StructureBlockMode{StructureBlockData(), StructureBlockSave(), StructureBlockLoad(), StructureBlockCorner(), StructureBlockExport()}
+}
+
+type structureBlockMode uint8
+
+// Uint8 returns the structure block mode as a uint8.
+func (m structureBlockMode) Uint8() uint8 {
+	return uint8(m)
+}
+
+// String returns the structure block mode as a string.
+func (m structureBlockMode) String() string {
+	switch m {
+	case 0:
+		return "data"
+	case 1:
+		return "save"
+	case 2:
+		return "load"
+	case 3:
+		return "corner"
+	case 5:
+		return "export"
+	}
+	panic("unknown structure block mode")
+}
@@ -47,20 +47,25 @@ func (c SugarCane) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
 		c.Age++
 	} else if c.Age == 15 {
 		c.Age = 0
-		if c.canGrowHere(pos.Side(cube.FaceDown), w, false) {
-			for y := 1; y < 3; y++ {
-				if _, ok := w.Block(pos.Add(cube.Pos{0, y})).(Air); ok {
-					w.SetBlock(pos.Add(cube.Pos{0, y}), SugarCane{}, nil)
-					break
-				} else if _, ok := w.Block(pos.Add(cube.Pos{0, y})).(SugarCane); !ok {
-					break
-				}
-			}
+		above := pos.Side(cube.FaceUp)
+		if _, ok := w.Block(above).(Air); ok && !above.OutOfBounds(w.Range()) && c.height(pos, w) < 3 {
+			// Only the top of a sugar cane stalk grows, until the stalk is three blocks tall.
+			w.SetBlock(above, SugarCane{}, nil)
 		}
 	}
 	w.SetBlock(pos, c, nil)
 }
 
+// height returns the height of the sugar cane stalk up to the position passed.
+func (c SugarCane) height(pos cube.Pos, w *world.World) int {
+	height := 1
+	for _, ok := w.Block(pos.Side(cube.FaceDown)).(SugarCane); ok; _, ok = w.Block(pos.Side(cube.FaceDown)).(SugarCane) {
+		pos = pos.Side(cube.FaceDown)
+		height++
+	}
+	return height
+}
+
 // BoneMeal ...
 func (c SugarCane) BoneMeal(pos cube.Pos, w *world.World) bool {
 	for _, ok := w.Block(pos.Side(cube.FaceDown)).(SugarCane); ok; _, ok = w.Block(pos.Side(cube.FaceDown)).(SugarCane) {
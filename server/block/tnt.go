@@ -20,8 +20,7 @@ type TNT struct {
 // Activate ...
 func (t TNT) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, ctx *item.UseContext) bool {
 	held, _ := u.HeldItems()
-	if _, ok := held.Enchantment(enchantment.FireAspect{}); ok {
-		t.Ignite(pos, w, u)
+	if _, ok := held.Enchantment(enchantment.FireAspect{}); ok && t.Ignite(pos, w, u) {
 		ctx.DamageItem(1)
 		return true
 	}
@@ -30,6 +29,9 @@ func (t TNT) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, ct
 
 // Ignite ...
 func (t TNT) Ignite(pos cube.Pos, w *world.World, igniter world.Entity) bool {
+	if !world.GameRuleTNTExplodes.Value(w) {
+		return false
+	}
 	t.igniter = igniter
 	spawnTnt(pos, w, time.Second*4, t.igniter)
 	return true
@@ -43,6 +45,9 @@ func (t TNT) Igniter() world.Entity {
 
 // Explode ...
 func (t TNT) Explode(_ mgl64.Vec3, pos cube.Pos, w *world.World, _ ExplosionConfig) {
+	if !world.GameRuleTNTExplodes.Value(w) {
+		return
+	}
 	spawnTnt(pos, w, time.Second/2+time.Duration(rand.Intn(int(time.Second+time.Second/2))), t.igniter)
 }
 
@@ -0,0 +1,288 @@
+package block
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+)
+
+// Vines are climbable non-solid blocks that grow on the sides of blocks. They spread slowly to nearby blocks
+// and grow downwards.
+type Vines struct {
+	empty
+	replaceable
+	transparent
+
+	// NorthDirection, EastDirection, SouthDirection and WestDirection specify if the vines are attached to the
+	// block on that side of them.
+	NorthDirection, EastDirection, SouthDirection, WestDirection bool
+}
+
+// WithAttachment returns the Vines with the attachment to the direction passed set to the value passed.
+func (v Vines) WithAttachment(direction cube.Direction, attached bool) Vines {
+	switch direction {
+	case cube.North:
+		v.NorthDirection = attached
+	case cube.East:
+		v.EastDirection = attached
+	case cube.South:
+		v.SouthDirection = attached
+	case cube.West:
+		v.WestDirection = attached
+	}
+	return v
+}
+
+// Attachment checks if the Vines are attached to the block in the direction passed.
+func (v Vines) Attachment(direction cube.Direction) bool {
+	switch direction {
+	case cube.North:
+		return v.NorthDirection
+	case cube.East:
+		return v.EastDirection
+	case cube.South:
+		return v.SouthDirection
+	case cube.West:
+		return v.WestDirection
+	}
+	return false
+}
+
+// Attachments returns all directions that the Vines are attached to.
+func (v Vines) Attachments() (attachments []cube.Direction) {
+	for _, dir := range cube.Directions() {
+		if v.Attachment(dir) {
+			attachments = append(attachments, dir)
+		}
+	}
+	return attachments
+}
+
+// FlammabilityInfo ...
+func (v Vines) FlammabilityInfo() FlammabilityInfo {
+	return newFlammabilityInfo(15, 100, true)
+}
+
+// BreakInfo ...
+func (v Vines) BreakInfo() BreakInfo {
+	return newBreakInfo(0.2, alwaysHarvestable, func(t item.Tool) bool {
+		return t.ToolType() == item.TypeShears || t.ToolType() == item.TypeAxe
+	}, func(t item.Tool, enchantments []item.Enchantment) []item.Stack {
+		if t.ToolType() == item.TypeShears || hasSilkTouch(enchantments) {
+			return []item.Stack{item.NewStack(v, 1)}
+		}
+		return nil
+	})
+}
+
+// CompostChance ...
+func (Vines) CompostChance() float64 {
+	return 0.5
+}
+
+// EntityInside ...
+func (Vines) EntityInside(_ cube.Pos, _ *world.World, e world.Entity) {
+	if fallEntity, ok := e.(fallDistanceEntity); ok {
+		fallEntity.ResetFallDistance()
+	}
+}
+
+// UseOnBlock ...
+func (v Vines) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	if face == cube.FaceUp || face == cube.FaceDown {
+		return false
+	}
+	if _, ok := w.Block(pos).(Vines); ok {
+		// Vines can't be placed on the side of other vines.
+		return false
+	}
+	if !w.Block(pos).Model().FaceSolid(pos, face, w) {
+		return false
+	}
+	pos = pos.Side(face)
+	existing, ok := w.Block(pos).(Vines)
+	if !ok && !replaceableWith(w, pos, v) {
+		return false
+	}
+	dir := face.Opposite().Direction()
+	if existing.Attachment(dir) {
+		return false
+	}
+
+	place(w, pos, existing.WithAttachment(dir, true), user, ctx)
+	return placed(ctx)
+}
+
+// NeighbourUpdateTick ...
+func (v Vines) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	above, _ := w.Block(pos.Side(cube.FaceUp)).(Vines)
+	updated := v
+	for _, dir := range v.Attachments() {
+		if !vinesCanAttach(pos, dir, w) && !above.Attachment(dir) {
+			updated = updated.WithAttachment(dir, false)
+		}
+	}
+	if updated == v {
+		return
+	}
+	if len(updated.Attachments()) == 0 {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: v})
+		return
+	}
+	w.SetBlock(pos, updated, nil)
+}
+
+// RandomTick ...
+func (v Vines) RandomTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if r.Intn(4) != 0 {
+		return
+	}
+	face := cube.Face(r.Intn(6))
+	if face == cube.FaceDown {
+		v.growDown(pos, w, r)
+		return
+	}
+	if vinesNearby(pos, w) {
+		// There are too many vines nearby for the vines to spread further.
+		return
+	}
+	if face == cube.FaceUp {
+		v.growUp(pos, w, r)
+		return
+	}
+	v.growSideways(pos, face.Direction(), w)
+}
+
+// growDown makes the vines grow into the block below them, copying a random selection of their attachments.
+func (v Vines) growDown(pos cube.Pos, w *world.World, r *rand.Rand) {
+	below := pos.Side(cube.FaceDown)
+	if below.OutOfBounds(w.Range()) {
+		return
+	}
+	belowVines, ok := w.Block(below).(Vines)
+	if _, air := w.Block(below).(Air); !ok && !air {
+		return
+	}
+	grown := belowVines
+	for _, dir := range v.Attachments() {
+		if r.Intn(2) == 0 {
+			grown = grown.WithAttachment(dir, true)
+		}
+	}
+	if grown != belowVines {
+		w.SetBlock(below, grown, nil)
+	}
+}
+
+// growUp makes the vines grow into the block above them if the block above is air and the vines are able to
+// attach to any of the blocks next to it.
+func (v Vines) growUp(pos cube.Pos, w *world.World, r *rand.Rand) {
+	above := pos.Side(cube.FaceUp)
+	if above.OutOfBounds(w.Range()) {
+		return
+	}
+	if _, ok := w.Block(above).(Air); !ok {
+		return
+	}
+	var grown Vines
+	for _, dir := range v.Attachments() {
+		if r.Intn(2) == 0 && vinesCanAttach(above, dir, w) {
+			grown = grown.WithAttachment(dir, true)
+		}
+	}
+	if len(grown.Attachments()) > 0 {
+		w.SetBlock(above, grown, nil)
+	}
+}
+
+// growSideways makes the vines grow in the direction passed. The vines either attach to a solid block in that
+// direction, or spread onto the sides of the blocks around the block in that direction.
+func (v Vines) growSideways(pos cube.Pos, dir cube.Direction, w *world.World) {
+	if v.Attachment(dir) {
+		return
+	}
+	target := pos.Side(dir.Face())
+	if _, ok := w.Block(target).(Air); !ok {
+		if vinesCanAttach(pos, dir, w) {
+			w.SetBlock(pos, v.WithAttachment(dir, true), nil)
+		}
+		return
+	}
+	left, right := dir.RotateLeft(), dir.RotateRight()
+	leftAttached, rightAttached := v.Attachment(left), v.Attachment(right)
+	switch {
+	case leftAttached && vinesCanAttach(target, left, w):
+		w.SetBlock(target, Vines{}.WithAttachment(left, true), nil)
+	case rightAttached && vinesCanAttach(target, right, w):
+		w.SetBlock(target, Vines{}.WithAttachment(right, true), nil)
+	case leftAttached && vinesCanSpreadAround(target, left, w):
+		// Spread around the corner of the block the vines are attached to.
+		w.SetBlock(target.Side(left.Face()), Vines{}.WithAttachment(dir.Opposite(), true), nil)
+	case rightAttached && vinesCanSpreadAround(target, right, w):
+		w.SetBlock(target.Side(right.Face()), Vines{}.WithAttachment(dir.Opposite(), true), nil)
+	}
+}
+
+// vinesCanSpreadAround checks if vines can spread around a corner into the block at target in the direction
+// passed, attaching to the block they grew from.
+func vinesCanSpreadAround(target cube.Pos, dir cube.Direction, w *world.World) bool {
+	corner := target.Side(dir.Face())
+	_, air := w.Block(corner).(Air)
+	return air && !corner.OutOfBounds(w.Range())
+}
+
+// vinesCanAttach checks if vines at the position passed can attach to the block in the direction passed.
+func vinesCanAttach(pos cube.Pos, dir cube.Direction, w *world.World) bool {
+	side := pos.Side(dir.Face())
+	return w.Block(side).Model().FaceSolid(side, dir.Opposite().Face(), w)
+}
+
+// vinesNearby checks if there are too many vines around the position passed for vines to spread further.
+func vinesNearby(pos cube.Pos, w *world.World) bool {
+	count := 0
+	for x := -4; x <= 4; x++ {
+		for z := -4; z <= 4; z++ {
+			for y := -1; y <= 1; y++ {
+				if _, ok := w.Block(pos.Add(cube.Pos{x, y, z})).(Vines); ok {
+					if count++; count > 4 {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// EncodeItem ...
+func (Vines) EncodeItem() (name string, meta int16) {
+	return "minecraft:vine", 0
+}
+
+// EncodeBlock ...
+func (v Vines) EncodeBlock() (string, map[string]any) {
+	var bits int32
+	for i, attached := range []bool{v.SouthDirection, v.WestDirection, v.NorthDirection, v.EastDirection} {
+		if attached {
+			bits |= 1 << i
+		}
+	}
+	return "minecraft:vine", map[string]any{"vine_direction_bits": bits}
+}
+
+// allVines ...
+func allVines() (b []world.Block) {
+	for i := 0; i < 16; i++ {
+		b = append(b, Vines{
+			SouthDirection: i&1 != 0,
+			WestDirection:  i&2 != 0,
+			NorthDirection: i&4 != 0,
+			EastDirection:  i&8 != 0,
+		})
+	}
+	return
+}
@@ -25,6 +25,11 @@ func (w WoodFence) BreakInfo() BreakInfo {
 	return newBreakInfo(2, alwaysHarvestable, axeEffective, oneOf(w)).withBlastResistance(15)
 }
 
+// Activate ties mobs leashed to the user to the fence.
+func (WoodFence) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
+	return leashToFence(pos, w, u)
+}
+
 // SideClosed ...
 func (WoodFence) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 	return false
@@ -3,11 +3,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/go-gl/mathgl/mgl64"
-	"math/rand"
 	"reflect"
-	"sort"
 	"strconv"
 	"strings"
 )
@@ -251,42 +248,36 @@ func (p parser) targets(line *Line, v reflect.Value) error {
 	return nil
 }
 
-// parseTargets parses one or more Targets from the Line passed.
+// parseTargets parses one or more Targets from the Line passed. Targets may be passed as the name of a player
+// or as a target selector, such as @a, @p, @r, @e or @s, optionally followed by selector arguments between
+// brackets, for example @e[type=zombie,r=10].
 func (p parser) parseTargets(line *Line) ([]Target, error) {
 	entities, players := targets(line.src)
 	first, ok := line.Next()
 	if !ok {
 		return nil, ErrInsufficientArgs
 	}
-	switch first {
-	case "@p":
-		pos := line.src.Position()
-		playerDistances := make([]float64, len(players))
-		for i, p := range players {
-			playerDistances[i] = p.Position().Sub(pos).Len()
-		}
-		sort.Slice(players, func(i, j int) bool {
-			return playerDistances[i] < playerDistances[j]
-		})
-		if len(players) == 0 {
-			return nil, nil
-		}
-		return sliceutil.Convert[Target](players[0:1]), nil
-	case "@e":
-		return entities, nil
-	case "@a":
-		return sliceutil.Convert[Target](players), nil
-	case "@s":
-		return []Target{line.src}, nil
-	case "@r":
-		if len(players) == 0 {
-			return nil, nil
-		}
-		return []Target{players[rand.Intn(len(players))]}, nil
-	default:
+	if !strings.HasPrefix(first, "@") {
 		target, err := p.parsePlayer(players, first)
 		return []Target{target}, err
 	}
+	// Selector arguments may contain spaces, in which case the selector is split over multiple arguments. These
+	// are joined until the closing bracket is found.
+	n := 1
+	for strings.Contains(first, "[") && !strings.HasSuffix(first, "]") {
+		args, ok := line.NextN(n + 1)
+		if !ok {
+			break
+		}
+		first, n = strings.Join(args, " "), n+1
+	}
+	sel, err := parseSelector(first)
+	if err != nil {
+		return nil, err
+	}
+	// The last argument of the selector is consumed by the caller, so only the arguments before it are removed.
+	line.RemoveN(n - 1)
+	return sel.resolve(line.src, entities, players), nil
 }
 
 // parsePlayer parses one Player from the Line, reading more arguments if necessary to find a valid player
@@ -0,0 +1,124 @@
+package builtin
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/account"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+)
+
+// LoginCommand returns the /login command, which logs a player in to its account using its password. The
+// command may be run by any player.
+func LoginCommand(m *account.Manager) cmd.Command {
+	return cmd.New("login", "Logs in to your account.", nil, Login{m: m})
+}
+
+// RegisterCommand returns the /register command, which registers a password for the account of a player. The
+// command may be run by any player.
+func RegisterCommand(m *account.Manager) cmd.Command {
+	return cmd.New("register", "Registers a password for your account.", nil, Register{m: m})
+}
+
+// AccountCommand returns the /account command, which lists and removes the accounts of players. The command
+// requires the dragonfly.command.account permission.
+func AccountCommand(m *account.Manager) cmd.Command {
+	return cmd.New("account", "Manages the accounts of players.", nil, AccountList{m: m}, AccountRemove{m: m}).WithPermission("dragonfly.command.account")
+}
+
+// Login implements the /login command. Players that enter a wrong password too often are disconnected.
+type Login struct {
+	Password string `cmd:"password"`
+
+	m *account.Manager
+}
+
+// Run ...
+func (l Login) Run(src cmd.Source, o *cmd.Output) {
+	p := src.(*player.Player)
+	if l.m.LoggedIn(p.UUID()) {
+		o.Errorf("You are already logged in.")
+		return
+	}
+	if _, err := l.m.Login(p.Name(), l.Password); errors.Is(err, account.ErrTooManyAttempts) {
+		p.Disconnect("Too many failed login attempts. Please try again later.")
+		return
+	} else if err != nil {
+		o.Errorf("Could not log in: %v.", err)
+		return
+	}
+	o.Printf("Logged in.")
+}
+
+// Allow ...
+func (Login) Allow(src cmd.Source) bool {
+	_, ok := src.(*player.Player)
+	return ok
+}
+
+// Register implements the /register command.
+type Register struct {
+	Password string `cmd:"password"`
+
+	m *account.Manager
+}
+
+// Run ...
+func (r Register) Run(src cmd.Source, o *cmd.Output) {
+	p := src.(*player.Player)
+	if _, err := r.m.Register(p.Name(), r.Password); err != nil {
+		o.Errorf("Could not register: %v.", err)
+		return
+	}
+	o.Printf("Registered your password and logged in.")
+}
+
+// Allow ...
+func (Register) Allow(src cmd.Source) bool {
+	_, ok := src.(*player.Player)
+	return ok
+}
+
+// AccountList implements the /account list command.
+type AccountList struct {
+	List cmd.SubCommand `cmd:"list"`
+
+	m *account.Manager
+}
+
+// Run ...
+func (a AccountList) Run(_ cmd.Source, o *cmd.Output) {
+	accounts := a.m.Accounts()
+	if len(accounts) == 0 {
+		o.Printf("No accounts are registered.")
+		return
+	}
+	names := make([]string, len(accounts))
+	for i, acc := range accounts {
+		names[i] = acc.Name
+	}
+	o.Printf("%v accounts are registered: %v", len(accounts), strings.Join(names, ", "))
+}
+
+// AccountRemove implements the /account remove command. Removing the account of a player allows the name to
+// be registered again, for example after the player forgot its password or switched devices.
+type AccountRemove struct {
+	Remove cmd.SubCommand `cmd:"remove"`
+	Player string         `cmd:"player"`
+
+	m *account.Manager
+}
+
+// Run ...
+func (a AccountRemove) Run(_ cmd.Source, o *cmd.Output) {
+	ok, err := a.m.Remove(a.Player)
+	if err != nil {
+		o.Errorf("Could not remove the account of %v: %v", a.Player, err)
+		return
+	} else if !ok {
+		o.Errorf("%v has no account.", a.Player)
+		return
+	}
+	o.Printf("Removed the account of %v.", a.Player)
+}
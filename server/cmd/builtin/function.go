@@ -0,0 +1,50 @@
+package builtin
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/cmd/function"
+)
+
+// FunctionCommand returns the /function command, which executes a function registered in the function
+// package. The command requires the dragonfly.command.function permission.
+func FunctionCommand() cmd.Command {
+	return cmd.New("function", "Runs commands found in the corresponding function file.", nil, Function{}).WithPermission("dragonfly.command.function")
+}
+
+// Function implements the /function command.
+type Function struct {
+	Name functionName `cmd:"name"`
+}
+
+// Run ...
+func (f Function) Run(src cmd.Source, o *cmd.Output) {
+	fn, ok := function.ByName(string(f.Name))
+	if !ok {
+		o.Errorf("Unknown function %v.", f.Name)
+		return
+	}
+	n, err := fn.Execute(src)
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Executed %v commands from function %v.", n, fn.Name())
+}
+
+// functionName is a cmd.Enum holding the names of all registered functions.
+type functionName string
+
+// Type ...
+func (functionName) Type() string {
+	return "FunctionName"
+}
+
+// Options ...
+func (functionName) Options(cmd.Source) []string {
+	functions := function.Functions()
+	names := make([]string, 0, len(functions))
+	for _, f := range functions {
+		names = append(names, f.Name())
+	}
+	return names
+}
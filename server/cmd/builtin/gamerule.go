@@ -0,0 +1,68 @@
+// Package builtin implements commands that are commonly needed on servers, such as /gamerule and /profile. The
+// commands are not registered by default: Command functions in this package return a cmd.Command that may be
+// registered using cmd.Register.
+package builtin
+
+import (
+	"slices"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// GameRuleCommand returns the /gamerule command, which shows or changes the value of a world.GameRule in the
+// world of the source that runs it. The command requires the dragonfly.command.gamerule permission.
+func GameRuleCommand() cmd.Command {
+	return cmd.New("gamerule", "Sets or queries a game rule value.", nil, GameRule{}).WithPermission("dragonfly.command.gamerule")
+}
+
+// GameRule implements the /gamerule command. If Value is left out, the current value of the game rule is
+// shown.
+type GameRule struct {
+	Rule  gameRuleName         `cmd:"rule"`
+	Value cmd.Optional[string] `cmd:"value"`
+}
+
+// Run ...
+func (g GameRule) Run(src cmd.Source, o *cmd.Output) {
+	w := src.World()
+	r, ok := world.GameRuleByName(string(g.Rule))
+	if !ok {
+		o.Errorf("Unknown game rule %v.", g.Rule)
+		return
+	}
+	s, ok := g.Value.Load()
+	if !ok {
+		o.Printf("%v = %v", r.Name(), w.GameRule(r))
+		return
+	}
+	v, err := r.Parse(s)
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	if err := w.SetGameRule(r, v); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Game rule %v has been updated to %v.", r.Name(), v)
+}
+
+// gameRuleName is a cmd.Enum holding the names of all game rules.
+type gameRuleName string
+
+// Type ...
+func (gameRuleName) Type() string {
+	return "GameRule"
+}
+
+// Options ...
+func (gameRuleName) Options(cmd.Source) []string {
+	rules := world.GameRules()
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Name())
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,76 @@
+package builtin
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/logging"
+)
+
+// LogLevelCommand returns the /loglevel command, which shows or changes the minimum level of messages logged
+// for a subsystem of the server, or for all subsystems at once. The command requires the
+// dragonfly.command.loglevel permission.
+func LogLevelCommand(levels *logging.Levels) cmd.Command {
+	return cmd.New("loglevel", "Shows or changes the level of messages logged.", nil, LogLevel{levels: levels}).WithPermission("dragonfly.command.loglevel")
+}
+
+// LogLevel implements the /loglevel command. If Level is left out, the current level of the subsystem is
+// shown. Setting the level of all subsystems resets the levels set for individual subsystems.
+type LogLevel struct {
+	Subsystem logSubsystem                 `cmd:"subsystem"`
+	Level     cmd.Optional[logLevelOption] `cmd:"level"`
+
+	levels *logging.Levels
+}
+
+// Run ...
+func (l LogLevel) Run(_ cmd.Source, o *cmd.Output) {
+	level, ok := l.Level.Load()
+	if !ok {
+		if l.Subsystem == "all" {
+			o.Printf("Default log level: %v", l.levels.Default())
+			return
+		}
+		o.Printf("Log level of %v: %v", l.Subsystem, l.levels.Level(string(l.Subsystem)))
+		return
+	}
+	lvl, err := logging.ParseLevel(string(level))
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	if l.Subsystem == "all" {
+		l.levels.SetDefault(lvl)
+		for _, s := range logging.Subsystems() {
+			l.levels.Reset(s)
+		}
+		o.Printf("Log level of all subsystems set to %v.", lvl)
+		return
+	}
+	l.levels.Set(string(l.Subsystem), lvl)
+	o.Printf("Log level of %v set to %v.", l.Subsystem, lvl)
+}
+
+// logSubsystem is a cmd.Enum holding the subsystems of the logging package and "all".
+type logSubsystem string
+
+// Type ...
+func (logSubsystem) Type() string {
+	return "LogSubsystem"
+}
+
+// Options ...
+func (logSubsystem) Options(cmd.Source) []string {
+	return append([]string{"all"}, logging.Subsystems()...)
+}
+
+// logLevelOption is a cmd.Enum holding the names of the levels that messages may be logged with.
+type logLevelOption string
+
+// Type ...
+func (logLevelOption) Type() string {
+	return "LogLevel"
+}
+
+// Options ...
+func (logLevelOption) Options(cmd.Source) []string {
+	return []string{"debug", "info", "warn", "error"}
+}
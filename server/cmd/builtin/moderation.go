@@ -0,0 +1,365 @@
+package builtin
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/moderation"
+	"github.com/df-mc/dragonfly/server/player"
+)
+
+// PlayerProvider provides the players currently online, so that moderation commands can disconnect players
+// once they are banned. *server.Server implements PlayerProvider.
+type PlayerProvider interface {
+	Players() []*player.Player
+}
+
+// BanCommand returns the /ban command, which bans a player permanently. Banned players that are online are
+// disconnected. The command requires the dragonfly.command.ban permission.
+func BanCommand(m *moderation.Manager, players PlayerProvider) cmd.Command {
+	return cmd.New("ban", "Bans a player from the server.", nil, Ban{m: m, players: players}).WithPermission("dragonfly.command.ban")
+}
+
+// TempBanCommand returns the /tempban command, which bans a player for a limited duration. Banned players that
+// are online are disconnected. The command requires the dragonfly.command.ban permission.
+func TempBanCommand(m *moderation.Manager, players PlayerProvider) cmd.Command {
+	return cmd.New("tempban", "Bans a player from the server for a duration.", nil, TempBan{m: m, players: players}).WithPermission("dragonfly.command.ban")
+}
+
+// UnbanCommand returns the /unban command, which lifts the ban of a player. The command requires the
+// dragonfly.command.unban permission.
+func UnbanCommand(m *moderation.Manager) cmd.Command {
+	return cmd.New("unban", "Lifts the ban of a player.", []string{"pardon"}, Unban{m: m}).WithPermission("dragonfly.command.unban")
+}
+
+// BanIPCommand returns the /banip command, which bans an IP address, or the IP address of an online player.
+// Players that are online with the IP address are disconnected. The command requires the
+// dragonfly.command.banip permission.
+func BanIPCommand(m *moderation.Manager, players PlayerProvider) cmd.Command {
+	return cmd.New("banip", "Bans an IP address from the server.", []string{"ban-ip"}, BanIP{m: m, players: players}).WithPermission("dragonfly.command.banip")
+}
+
+// UnbanIPCommand returns the /unbanip command, which lifts the ban of an IP address. The command requires the
+// dragonfly.command.unbanip permission.
+func UnbanIPCommand(m *moderation.Manager) cmd.Command {
+	return cmd.New("unbanip", "Lifts the ban of an IP address.", []string{"pardon-ip"}, UnbanIP{m: m}).WithPermission("dragonfly.command.unbanip")
+}
+
+// BanListCommand returns the /banlist command, which lists the players or IP addresses that are banned. The
+// command requires the dragonfly.command.banlist permission.
+func BanListCommand(m *moderation.Manager) cmd.Command {
+	return cmd.New("banlist", "Lists the players or IP addresses that are banned.", nil, BanList{m: m}).WithPermission("dragonfly.command.banlist")
+}
+
+// WhitelistCommand returns the /whitelist command, which enables, disables and edits the whitelist. The
+// command requires the dragonfly.command.whitelist permission.
+func WhitelistCommand(m *moderation.Manager) cmd.Command {
+	return cmd.New("whitelist", "Manages the whitelist of the server.", nil,
+		WhitelistToggle{m: m}, WhitelistAdd{m: m}, WhitelistRemove{m: m}, WhitelistList{m: m}).WithPermission("dragonfly.command.whitelist")
+}
+
+// Ban implements the /ban command.
+type Ban struct {
+	Player string                    `cmd:"player"`
+	Reason cmd.Optional[cmd.Varargs] `cmd:"reason"`
+
+	m       *moderation.Manager
+	players PlayerProvider
+}
+
+// Run ...
+func (b Ban) Run(src cmd.Source, o *cmd.Output) {
+	ban(b.m, b.players, src, o, b.Player, string(b.Reason.LoadOr("")), 0)
+}
+
+// TempBan implements the /tempban command. The Duration is a number followed by a unit, such as 30m, 12h or
+// 7d, and may combine several units, such as 1d12h.
+type TempBan struct {
+	Player   string                    `cmd:"player"`
+	Duration string                    `cmd:"duration"`
+	Reason   cmd.Optional[cmd.Varargs] `cmd:"reason"`
+
+	m       *moderation.Manager
+	players PlayerProvider
+}
+
+// Run ...
+func (b TempBan) Run(src cmd.Source, o *cmd.Output) {
+	d, err := parseDuration(b.Duration)
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	ban(b.m, b.players, src, o, b.Player, string(b.Reason.LoadOr("")), d)
+}
+
+// ban bans the player with the name passed for the duration passed and disconnects it if it is online.
+func ban(m *moderation.Manager, players PlayerProvider, src cmd.Source, o *cmd.Output, name, reason string, d time.Duration) {
+	if err := m.Ban(name, reason, sourceName(src), d); err != nil {
+		o.Errorf("Could not ban %v: %v", name, err)
+		return
+	}
+	if b, ok := m.Banned(name); ok {
+		for _, p := range players.Players() {
+			if strings.EqualFold(p.Name(), name) {
+				p.Disconnect(b.Message())
+			}
+		}
+	}
+	if d > 0 {
+		o.Printf("Banned %v for %v.", name, d)
+		return
+	}
+	o.Printf("Banned %v.", name)
+}
+
+// Unban implements the /unban command.
+type Unban struct {
+	Player string `cmd:"player"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (u Unban) Run(_ cmd.Source, o *cmd.Output) {
+	ok, err := u.m.Unban(u.Player)
+	if err != nil {
+		o.Errorf("Could not unban %v: %v", u.Player, err)
+		return
+	} else if !ok {
+		o.Errorf("%v is not banned.", u.Player)
+		return
+	}
+	o.Printf("Unbanned %v.", u.Player)
+}
+
+// BanIP implements the /banip command. Target is either an IP address or the name of an online player.
+type BanIP struct {
+	Target string                    `cmd:"target"`
+	Reason cmd.Optional[cmd.Varargs] `cmd:"reason"`
+
+	m       *moderation.Manager
+	players PlayerProvider
+}
+
+// Run ...
+func (b BanIP) Run(src cmd.Source, o *cmd.Output) {
+	ip := b.Target
+	if net.ParseIP(ip) == nil {
+		found := false
+		for _, p := range b.players.Players() {
+			if strings.EqualFold(p.Name(), b.Target) {
+				ip, found = addrIP(p.Addr()), true
+				break
+			}
+		}
+		if !found {
+			o.Errorf("%v is not a valid IP address or online player.", b.Target)
+			return
+		}
+	}
+	if err := b.m.BanIP(ip, string(b.Reason.LoadOr("")), sourceName(src), 0); err != nil {
+		o.Errorf("Could not ban %v: %v", ip, err)
+		return
+	}
+	if ban, ok := b.m.IPBanned(ip); ok {
+		for _, p := range b.players.Players() {
+			if addrIP(p.Addr()) == ban.Target {
+				p.Disconnect(ban.Message())
+			}
+		}
+	}
+	o.Printf("Banned IP address %v.", ip)
+}
+
+// UnbanIP implements the /unbanip command.
+type UnbanIP struct {
+	IP string `cmd:"ip"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (u UnbanIP) Run(_ cmd.Source, o *cmd.Output) {
+	ok, err := u.m.UnbanIP(u.IP)
+	if err != nil {
+		o.Errorf("Could not unban %v: %v", u.IP, err)
+		return
+	} else if !ok {
+		o.Errorf("%v is not banned.", u.IP)
+		return
+	}
+	o.Printf("Unbanned IP address %v.", u.IP)
+}
+
+// BanList implements the /banlist command. If List is 'ips', banned IP addresses are listed instead of
+// players.
+type BanList struct {
+	List cmd.Optional[banList] `cmd:"list"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (b BanList) Run(_ cmd.Source, o *cmd.Output) {
+	bans := b.m.Bans()
+	if b.List.LoadOr("players") == "ips" {
+		bans = b.m.IPBans()
+	}
+	o.Printf("There are %v bans.", len(bans))
+	for _, ban := range bans {
+		line := fmt.Sprintf("- %v, banned by %v", ban.Target, ban.Source)
+		if ban.Reason != "" {
+			line += ": " + ban.Reason
+		}
+		if !ban.Expires.IsZero() {
+			line += fmt.Sprintf(" (expires %v)", ban.Expires.Format(time.DateTime))
+		}
+		o.Print(line)
+	}
+}
+
+// banList is a cmd.Enum holding the lists of bans that may be shown by /banlist.
+type banList string
+
+// Type ...
+func (banList) Type() string {
+	return "BanList"
+}
+
+// Options ...
+func (banList) Options(cmd.Source) []string {
+	return []string{"players", "ips"}
+}
+
+// WhitelistToggle implements the /whitelist on and /whitelist off commands.
+type WhitelistToggle struct {
+	State whitelistState `cmd:"state"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (w WhitelistToggle) Run(_ cmd.Source, o *cmd.Output) {
+	if err := w.m.SetWhitelistEnabled(w.State == "on"); err != nil {
+		o.Errorf("Could not change the whitelist: %v", err)
+		return
+	}
+	o.Printf("Turned the whitelist %v.", w.State)
+}
+
+// whitelistState is a cmd.Enum holding the states that the whitelist may be turned to.
+type whitelistState string
+
+// Type ...
+func (whitelistState) Type() string {
+	return "WhitelistState"
+}
+
+// Options ...
+func (whitelistState) Options(cmd.Source) []string {
+	return []string{"on", "off"}
+}
+
+// WhitelistAdd implements the /whitelist add command.
+type WhitelistAdd struct {
+	Add    cmd.SubCommand `cmd:"add"`
+	Player string         `cmd:"player"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (w WhitelistAdd) Run(_ cmd.Source, o *cmd.Output) {
+	if err := w.m.Whitelist(w.Player); err != nil {
+		o.Errorf("Could not whitelist %v: %v", w.Player, err)
+		return
+	}
+	o.Printf("Added %v to the whitelist.", w.Player)
+}
+
+// WhitelistRemove implements the /whitelist remove command.
+type WhitelistRemove struct {
+	Remove cmd.SubCommand `cmd:"remove"`
+	Player string         `cmd:"player"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (w WhitelistRemove) Run(_ cmd.Source, o *cmd.Output) {
+	ok, err := w.m.Unwhitelist(w.Player)
+	if err != nil {
+		o.Errorf("Could not remove %v from the whitelist: %v", w.Player, err)
+		return
+	} else if !ok {
+		o.Errorf("%v is not whitelisted.", w.Player)
+		return
+	}
+	o.Printf("Removed %v from the whitelist.", w.Player)
+}
+
+// WhitelistList implements the /whitelist list command.
+type WhitelistList struct {
+	List cmd.SubCommand `cmd:"list"`
+
+	m *moderation.Manager
+}
+
+// Run ...
+func (w WhitelistList) Run(_ cmd.Source, o *cmd.Output) {
+	names := w.m.WhitelistNames()
+	state := "off"
+	if w.m.WhitelistEnabled() {
+		state = "on"
+	}
+	o.Printf("The whitelist is %v and holds %v players: %v", state, len(names), strings.Join(names, ", "))
+}
+
+// sourceName returns the name of a cmd.Source used as the source of bans.
+func sourceName(src cmd.Source) string {
+	if n, ok := src.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return "Server"
+}
+
+// addrIP returns the IP address of a net.Addr as a string.
+func addrIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return host
+}
+
+// parseDuration parses a duration made up of numbers followed by a unit, which is either s, m, h, d or w, such
+// as 30m, 7d or 1d12h.
+func parseDuration(s string) (time.Duration, error) {
+	units := map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': time.Hour * 24, 'w': time.Hour * 24 * 7}
+	var d time.Duration
+	start := 0
+	for i := 0; i < len(s); i++ {
+		unit, ok := units[s[i]]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(s[start:i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %v", s)
+		}
+		d += time.Duration(n) * unit
+		start = i + 1
+	}
+	if start != len(s) || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %v: use a number followed by s, m, h, d or w, such as 7d", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,121 @@
+package builtin
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/exp/maps"
+)
+
+// ProfileCommand returns the /profile command, which measures the time spent on the different systems of
+// worlds, such as entities, random ticks and block entities. If worlds are passed, the command profiles all of
+// them at once. Otherwise, it profiles the world of the source that runs it. The command requires the
+// dragonfly.command.profile permission.
+func ProfileCommand(worlds ...*world.World) cmd.Command {
+	return cmd.New("profile", "Measures the time spent ticking the systems of a world.", nil, ProfileStart{worlds: worlds}, ProfileStop{worlds: worlds}).WithPermission("dragonfly.command.profile")
+}
+
+// ProfileStart implements the /profile start command, which starts profiling the worlds of the command. If
+// Seconds is set, profiling stops automatically after that many seconds and the results are sent to the
+// source.
+type ProfileStart struct {
+	Start   cmd.SubCommand    `cmd:"start"`
+	Seconds cmd.Optional[int] `cmd:"seconds"`
+
+	worlds []*world.World
+}
+
+// Run ...
+func (p ProfileStart) Run(src cmd.Source, o *cmd.Output) {
+	seconds, timed := p.Seconds.Load()
+	if timed && seconds <= 0 {
+		o.Errorf("The number of seconds to profile must be positive.")
+		return
+	}
+	worlds := profiledWorlds(src, p.worlds)
+	for _, w := range worlds {
+		if w.Profiling() {
+			o.Errorf("%v is already being profiled.", w.Name())
+			return
+		}
+	}
+	for _, w := range worlds {
+		w.StartProfiling()
+	}
+	if !timed {
+		o.Printf("Started profiling. Run /profile stop to see the results.")
+		return
+	}
+	time.AfterFunc(time.Duration(seconds)*time.Second, func() {
+		res := &cmd.Output{}
+		if stopProfiling(worlds, res) {
+			src.SendCommandOutput(res)
+		}
+	})
+	o.Printf("Started profiling for %v seconds.", seconds)
+}
+
+// ProfileStop implements the /profile stop command, which stops profiling the worlds of the command and shows
+// the results.
+type ProfileStop struct {
+	Stop cmd.SubCommand `cmd:"stop"`
+
+	worlds []*world.World
+}
+
+// Run ...
+func (p ProfileStop) Run(src cmd.Source, o *cmd.Output) {
+	if !stopProfiling(profiledWorlds(src, p.worlds), o) {
+		o.Errorf("Nothing is being profiled. Run /profile start first.")
+	}
+}
+
+// profiledWorlds returns the worlds passed, or the world of the source if no worlds are passed.
+func profiledWorlds(src cmd.Source, worlds []*world.World) []*world.World {
+	if len(worlds) == 0 {
+		return []*world.World{src.World()}
+	}
+	return worlds
+}
+
+// stopProfiling stops profiling the worlds passed and prints the results to the output. False is returned if
+// none of the worlds were being profiled.
+func stopProfiling(worlds []*world.World, o *cmd.Output) bool {
+	stopped := false
+	for _, w := range worlds {
+		p, ok := w.StopProfiling()
+		if !ok {
+			continue
+		}
+		stopped = true
+		o.Printf("%v: Profiled %v ticks, taking %v on average (%v total).", w.Name(), p.Ticks, p.Average(), p.Duration)
+		for _, s := range sortedByDuration(p.Systems) {
+			o.Printf("- %v: %v", s, p.Systems[s])
+		}
+		if len(p.BlockEntities) > 0 {
+			o.Printf("Block entities:")
+			for _, name := range sortedByDuration(p.BlockEntities) {
+				o.Printf("- %v: %v", name, p.BlockEntities[name])
+			}
+		}
+		if len(p.Entities) > 0 {
+			o.Printf("Entities:")
+			for _, name := range sortedByDuration(p.Entities) {
+				o.Printf("- %v: %v", name, p.Entities[name])
+			}
+		}
+	}
+	return stopped
+}
+
+// sortedByDuration returns the keys of the map passed, sorted from the longest to the shortest duration.
+func sortedByDuration[K ~string](m map[K]time.Duration) []K {
+	keys := maps.Keys(m)
+	slices.SortFunc(keys, func(a, b K) int {
+		return cmp.Compare(m[b], m[a])
+	})
+	return keys
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"go/ast"
 	"reflect"
@@ -40,6 +41,14 @@ type Allower interface {
 	Allow(src Source) bool
 }
 
+// Permissible may be implemented by a Source that holds permissions, such as a player. If a Command has a
+// permission node set using Command.WithPermission, a Permissible Source must have that permission to run the
+// Command. Sources that do not implement Permissible may run any Command.
+type Permissible interface {
+	// HasPermission checks if the Source has the permission node passed.
+	HasPermission(node string) bool
+}
+
 // Command is a wrapper around a Runnable. It provides additional identity and utility methods for the actual
 // runnable command so that it may be identified more easily.
 type Command struct {
@@ -47,7 +56,9 @@ type Command struct {
 	name        string
 	description string
 	usage       string
+	usages      []string
 	aliases     []string
+	permission  string
 }
 
 // New returns a new Command using the name and description passed. The Runnable passed must be a
@@ -88,7 +99,7 @@ func New(name, description string, aliases []string, r ...Runnable) Command {
 		runnableValues[i], usages[i] = original, parseUsage(name, cp)
 	}
 
-	return Command{name: name, description: description, aliases: aliases, v: runnableValues, usage: strings.Join(usages, "\n")}
+	return Command{name: name, description: description, aliases: aliases, v: runnableValues, usage: strings.Join(usages, "\n"), usages: usages}
 }
 
 // Name returns the name of the command. The name is guaranteed to be lowercase and will never have spaces in
@@ -115,6 +126,29 @@ func (cmd Command) Aliases() []string {
 	return cmd.aliases
 }
 
+// WithPermission returns a copy of the Command that requires the permission node passed to be run by a Source
+// implementing Permissible. Permissible sources without the permission are unable to see or run the Command.
+func (cmd Command) WithPermission(node string) Command {
+	cmd.permission = node
+	return cmd
+}
+
+// Permission returns the permission node required to run the Command, as set using WithPermission. An empty
+// string is returned if the Command does not require a permission.
+func (cmd Command) Permission() string {
+	return cmd.permission
+}
+
+// Allowed checks if the Source passed has the permission required to run the Command. Allowed always returns
+// true if the Command has no permission set or if the Source does not implement Permissible.
+func (cmd Command) Allowed(src Source) bool {
+	if cmd.permission == "" {
+		return true
+	}
+	p, ok := src.(Permissible)
+	return !ok || p.HasPermission(cmd.permission)
+}
+
 // Execute executes the Command as a source with the args passed. The args are parsed assuming they do not
 // start with the command name. Execute will attempt to parse and execute one Runnable at a time. If one of
 // the Runnable was able to parse args correctly, it will be executed and no more Runnables will be attempted
@@ -129,13 +163,18 @@ func (cmd Command) Execute(args string, source Source) {
 	output := &Output{}
 	defer source.SendCommandOutput(output)
 
+	if !cmd.Allowed(source) {
+		output.Errorf("You cannot execute this command.")
+		return
+	}
+
 	var leastErroneous error
 	leastArgsLeft := len(strings.Split(args, " "))
 
-	for _, v := range cmd.v {
+	for i, v := range cmd.v {
 		cp := reflect.New(v.Type())
 		cp.Elem().Set(v)
-		line, err := cmd.executeRunnable(cp, args, source, output)
+		line, err := cmd.executeRunnable(cp, args, source, output, cmd.usages[i])
 		if err == nil {
 			// Command was executed successfully: We won't execute any of the other Runnable values passed, as
 			// we've already found an overload that works.
@@ -174,6 +213,9 @@ type ParamInfo struct {
 // they hold: Only the types are guaranteed to be consistent.
 func (cmd Command) Params(src Source) [][]ParamInfo {
 	params := make([][]ParamInfo, 0, len(cmd.v))
+	if !cmd.Allowed(src) {
+		return params
+	}
 	for _, runnable := range cmd.v {
 		elem := reflect.New(runnable.Type()).Elem()
 		elem.Set(runnable)
@@ -201,6 +243,9 @@ func (cmd Command) Params(src Source) [][]ParamInfo {
 // Runnables returns a map of all Runnable implementations of the Command that a Source can execute.
 func (cmd Command) Runnables(src Source) map[int]Runnable {
 	m := make(map[int]Runnable, len(cmd.v))
+	if !cmd.Allowed(src) {
+		return m
+	}
 	for i, runnable := range cmd.v {
 		v := runnable.Interface().(Runnable)
 		if allower, ok := v.(Allower); !ok || allower.Allow(src) {
@@ -218,8 +263,8 @@ func (cmd Command) String() string {
 
 // executeRunnable executes a Runnable v, by parsing the args passed using the source and output obtained. If
 // parsing was not successful or the Runnable could not be run by this source, an error is returned, and the
-// leftover command line.
-func (cmd Command) executeRunnable(v reflect.Value, args string, source Source, output *Output) (*Line, error) {
+// leftover command line. Errors caused by missing or unexpected arguments include the usage passed.
+func (cmd Command) executeRunnable(v reflect.Value, args string, source Source, output *Output, usage string) (*Line, error) {
 	if a, ok := v.Interface().(Allower); ok && !a.Allow(source) {
 		//lint:ignore ST1005 Error string is capitalised because it is shown to the player.
 		//goland:noinspection GoErrorStringFormat
@@ -254,7 +299,9 @@ func (cmd Command) executeRunnable(v reflect.Value, args string, source Source,
 		}
 
 		err, success := parser.parseArgument(arguments, val, opt, name(t), source)
-		if err != nil {
+		if errors.Is(err, ErrInsufficientArgs) {
+			return arguments, fmt.Errorf("%w: missing argument %v. Usage: %v", err, name(t), usage)
+		} else if err != nil {
 			// Parsing was not successful, we return immediately as we don't need to call the Runnable.
 			return arguments, err
 		}
@@ -263,7 +310,7 @@ func (cmd Command) executeRunnable(v reflect.Value, args string, source Source,
 		}
 	}
 	if arguments.Len() != 0 {
-		return arguments, fmt.Errorf("unexpected '%v'", strings.Join(arguments.args, " "))
+		return arguments, fmt.Errorf("unexpected '%v'. Usage: %v", strings.Join(arguments.args, " "), usage)
 	}
 
 	v.Interface().(Runnable).Run(source, output)
@@ -0,0 +1,104 @@
+// Package function implements functions: Files holding a list of commands that are executed together, similar
+// to the functions of data packs. Functions may be loaded from a directory using Load and executed using
+// Function.Execute or the /function command found in the builtin package.
+package function
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+)
+
+// Function is a list of commands that are executed together. Functions are usually loaded from .mcfunction
+// files, which hold one command per line.
+type Function struct {
+	name  string
+	lines []string
+}
+
+// New creates a Function with the name and command lines passed. Command lines may optionally start with a '/'.
+func New(name string, lines ...string) Function {
+	f := Function{name: name}
+	for _, line := range lines {
+		if line = strings.TrimPrefix(strings.TrimSpace(line), "/"); line != "" {
+			f.lines = append(f.lines, line)
+		}
+	}
+	return f
+}
+
+// Parse parses a Function with the name passed from the io.Reader passed. Every line read holds one command.
+// Empty lines and lines starting with '#' are ignored.
+func Parse(name string, r io.Reader) (Function, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Function{}, fmt.Errorf("parse function %v: %w", name, err)
+	}
+	return New(name, lines...), nil
+}
+
+// Name returns the name of the Function, such as 'namespace:path/to/function'.
+func (f Function) Name() string {
+	return f.name
+}
+
+// Lines returns the command lines executed by the Function, without a leading '/'.
+func (f Function) Lines() []string {
+	return f.lines
+}
+
+// running holds the names of the functions that are currently being executed.
+var (
+	runningMu sync.Mutex
+	running   = map[string]struct{}{}
+)
+
+// Execute executes all commands of the Function in order with the cmd.Source passed. Before any command is
+// executed, Execute checks if all commands exist and may be run by the cmd.Source. If not, no command is
+// executed and an error is returned. A Function may not execute itself, either directly or through other
+// functions: Execute returns an error if the Function is already being executed. The number of commands
+// executed is returned.
+func (f Function) Execute(src cmd.Source) (int, error) {
+	commands := make([]cmd.Command, len(f.lines))
+	args := make([]string, len(f.lines))
+	for i, line := range f.lines {
+		var name string
+		name, args[i], _ = strings.Cut(line, " ")
+		command, ok := cmd.ByAlias(name)
+		if !ok {
+			return 0, fmt.Errorf("function %v: unknown command %v on line %v", f.name, name, i+1)
+		}
+		if !command.Allowed(src) {
+			return 0, fmt.Errorf("function %v: not allowed to run command %v on line %v", f.name, name, i+1)
+		}
+		commands[i] = command
+	}
+
+	runningMu.Lock()
+	if _, ok := running[f.name]; ok {
+		runningMu.Unlock()
+		return 0, fmt.Errorf("function %v: function is already being executed", f.name)
+	}
+	running[f.name] = struct{}{}
+	runningMu.Unlock()
+
+	defer func() {
+		runningMu.Lock()
+		delete(running, f.name)
+		runningMu.Unlock()
+	}()
+	for i, command := range commands {
+		command.Execute(args[i], src)
+	}
+	return len(commands), nil
+}
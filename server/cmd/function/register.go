@@ -0,0 +1,92 @@
+package function
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	functionMu sync.RWMutex
+	functions  = map[string]Function{}
+)
+
+// Register registers a Function so that it may be obtained using ByName and executed using the /function
+// command. A Function registered with the same name as a Function registered before replaces it.
+func Register(f Function) {
+	functionMu.Lock()
+	defer functionMu.Unlock()
+	functions[f.name] = f
+}
+
+// ByName looks up a registered Function by its name. If no Function with the name was registered, false is
+// returned.
+func ByName(name string) (Function, bool) {
+	functionMu.RLock()
+	defer functionMu.RUnlock()
+	f, ok := functions[name]
+	return f, ok
+}
+
+// Functions returns all registered functions, sorted by their names.
+func Functions() []Function {
+	functionMu.RLock()
+	defer functionMu.RUnlock()
+	all := make([]Function, 0, len(functions))
+	for _, f := range functions {
+		all = append(all, f)
+	}
+	slices.SortFunc(all, func(a, b Function) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return all
+}
+
+// Load parses and registers all .mcfunction files found in the directory passed and its subdirectories. The
+// name of a function is its path relative to the directory, without the extension. Functions laid out like
+// those of a data pack, at 'data/<namespace>/functions/<path>.mcfunction', are named '<namespace>:<path>'.
+// The directory is created if it does not yet exist.
+func Load(dir string) error {
+	_ = os.MkdirAll(dir, 0777)
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".mcfunction" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("load function: %w", err)
+		}
+		defer file.Close()
+
+		f, err := Parse(functionName(rel), file)
+		if err != nil {
+			return fmt.Errorf("load function: %w", err)
+		}
+		Register(f)
+		return nil
+	})
+}
+
+// functionName returns the name of the function at the relative path passed.
+func functionName(rel string) string {
+	parts := strings.Split(filepath.ToSlash(strings.TrimSuffix(rel, ".mcfunction")), "/")
+	if parts[0] == "data" && len(parts) > 1 {
+		parts = parts[1:]
+	}
+	if len(parts) > 2 && (parts[1] == "functions" || parts[1] == "function") {
+		return parts[0] + ":" + strings.Join(parts[2:], "/")
+	}
+	return strings.Join(parts, "/")
+}
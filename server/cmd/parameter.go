@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/go-gl/mathgl/mgl64"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -39,6 +40,28 @@ type Enum interface {
 	Options(source Source) []string
 }
 
+// PlayerName is an Enum that may be used as parameter type to accept the name of any player that the Source is
+// able to target. Its options are updated as players join and leave, so that the client auto-completes the
+// names of online players.
+type PlayerName string
+
+// Type ...
+func (PlayerName) Type() string {
+	return "PlayerName"
+}
+
+// Options ...
+func (PlayerName) Options(src Source) []string {
+	_, players := targets(src)
+	names := make([]string, len(players))
+	for i, p := range players {
+		names[i] = p.Name()
+	}
+	// The names are sorted so that the options are only resent to clients if the players actually changed.
+	sort.Strings(names)
+	return names
+}
+
 // SubCommand represents a subcommand that may be added as a static value that must be written. Adding
 // multiple Runnable implementations to the command in New with different SubCommand fields as the
 // first parameter allows for commands with subcommands.
@@ -33,3 +33,13 @@ func Commands() map[string]Command {
 	})
 	return cmd
 }
+
+// Unregister unregisters a command previously registered using Register, removing its name and all aliases that
+// it has. Names and aliases that were overwritten by another command since are left untouched.
+func Unregister(command Command) {
+	for _, alias := range append([]string{command.name}, command.aliases...) {
+		if c, ok := commands.Load(alias); ok && c.(Command).name == command.name {
+			commands.Delete(alias)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// selector is a target selector, such as @a or @e[type=zombie,r=10], parsed from a command argument. It
+// selects the Targets of a command based on the variable of the selector and the arguments passed to it.
+type selector struct {
+	variable string
+	filters  []func(origin selectorOrigin, t Target) bool
+
+	x, y, z *float64
+	count   int
+	limited bool
+}
+
+// selectorOrigin is the origin from which the distance to Targets is measured by a selector.
+type selectorOrigin struct {
+	src Source
+	pos [3]float64
+}
+
+// tagged is a Target that has tags, such as a player. Targets that do not implement tagged are treated as
+// having no tags by the tag selector argument.
+type tagged interface {
+	HasTag(tag string) bool
+}
+
+// nameTagged is a Target that has a name tag.
+type nameTagged interface {
+	NameTag() string
+}
+
+// typed is a Target that has a world.EntityType.
+type typed interface {
+	Type() world.EntityType
+}
+
+// worldTarget is a Target that is in a world.World.
+type worldTarget interface {
+	World() *world.World
+}
+
+// parseSelector parses a selector from the argument passed. An error is returned if the variable or any of the
+// arguments of the selector are invalid.
+func parseSelector(arg string) (selector, error) {
+	variable, args, hasArgs := strings.Cut(arg, "[")
+	s := selector{variable: variable}
+	switch variable {
+	case "@p", "@r":
+		s.count, s.limited = 1, true
+	case "@a", "@e", "@s":
+	default:
+		return s, fmt.Errorf("unknown selector %v", variable)
+	}
+	if !hasArgs {
+		return s, nil
+	}
+	if !strings.HasSuffix(args, "]") {
+		return s, fmt.Errorf("selector %v is missing a closing bracket", arg)
+	}
+	args = strings.TrimSuffix(args, "]")
+	if strings.TrimSpace(args) == "" {
+		return s, nil
+	}
+	for _, a := range strings.Split(args, ",") {
+		key, value, ok := strings.Cut(a, "=")
+		if !ok {
+			return s, fmt.Errorf("invalid selector argument %v: expected key=value", strings.TrimSpace(a))
+		}
+		if err := s.parseArgument(strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// parseArgument parses a single key=value argument of the selector.
+func (s *selector) parseArgument(key, value string) error {
+	switch key {
+	case "type":
+		negate, v := negated(value)
+		v = strings.ToLower(v)
+		if !strings.Contains(v, ":") {
+			v = "minecraft:" + v
+		}
+		s.filter(negate, func(_ selectorOrigin, t Target) bool {
+			ty, ok := t.(typed)
+			return ok && ty.Type().EncodeEntity() == v
+		})
+	case "name":
+		negate, v := negated(strings.Trim(value, `"`))
+		s.filter(negate, func(_ selectorOrigin, t Target) bool {
+			if n, ok := t.(NamedTarget); ok && strings.EqualFold(n.Name(), v) {
+				return true
+			}
+			n, ok := t.(nameTagged)
+			return ok && strings.EqualFold(n.NameTag(), v)
+		})
+	case "tag":
+		negate, v := negated(value)
+		s.filter(negate, func(_ selectorOrigin, t Target) bool {
+			tg, ok := t.(tagged)
+			return ok && tg.HasTag(v)
+		})
+	case "r", "rm":
+		dist, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid selector argument %v: %v is not a number", key, value)
+		}
+		s.filter(false, func(origin selectorOrigin, t Target) bool {
+			if w, ok := t.(worldTarget); ok && w.World() != origin.src.World() {
+				return false
+			}
+			d := origin.distance(t)
+			if key == "r" {
+				return d <= dist
+			}
+			return d >= dist
+		})
+	case "x", "y", "z":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid selector argument %v: %v is not a number", key, value)
+		}
+		switch key {
+		case "x":
+			s.x = &v
+		case "y":
+			s.y = &v
+		case "z":
+			s.z = &v
+		}
+	case "c":
+		c, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid selector argument c: %v is not an integer", value)
+		}
+		s.count, s.limited = c, true
+	default:
+		return fmt.Errorf("unknown selector argument %v", key)
+	}
+	return nil
+}
+
+// filter adds a filter to the selector, which is inverted if negate is true.
+func (s *selector) filter(negate bool, f func(origin selectorOrigin, t Target) bool) {
+	s.filters = append(s.filters, func(origin selectorOrigin, t Target) bool {
+		return f(origin, t) != negate
+	})
+}
+
+// resolve resolves the Targets selected by the selector for the Source passed, using the entities and players
+// that the Source is able to target.
+func (s selector) resolve(src Source, entities []Target, players []NamedTarget) []Target {
+	origin := selectorOrigin{src: src}
+	pos := src.Position()
+	origin.pos = [3]float64{pos[0], pos[1], pos[2]}
+	for i, v := range []*float64{s.x, s.y, s.z} {
+		if v != nil {
+			origin.pos[i] = *v
+		}
+	}
+
+	var candidates []Target
+	switch s.variable {
+	case "@s":
+		candidates = []Target{src}
+	case "@e":
+		candidates = entities
+	default:
+		candidates = make([]Target, len(players))
+		for i, p := range players {
+			candidates[i] = p
+		}
+	}
+
+	selected := make([]Target, 0, len(candidates))
+	for _, t := range candidates {
+		if s.matches(origin, t) {
+			selected = append(selected, t)
+		}
+	}
+	if !s.limited {
+		return selected
+	}
+
+	count := s.count
+	if s.variable == "@r" {
+		rand.Shuffle(len(selected), func(i, j int) {
+			selected[i], selected[j] = selected[j], selected[i]
+		})
+	} else {
+		// A negative count selects the targets furthest away from the origin instead of the closest ones.
+		sort.SliceStable(selected, func(i, j int) bool {
+			if count < 0 {
+				return origin.distance(selected[i]) > origin.distance(selected[j])
+			}
+			return origin.distance(selected[i]) < origin.distance(selected[j])
+		})
+	}
+	if count < 0 {
+		count = -count
+	}
+	if count < len(selected) {
+		selected = selected[:count]
+	}
+	return selected
+}
+
+// matches checks if the Target passed matches all filters of the selector.
+func (s selector) matches(origin selectorOrigin, t Target) bool {
+	for _, f := range s.filters {
+		if !f(origin, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// distance returns the distance between the origin and the Target passed.
+func (origin selectorOrigin) distance(t Target) float64 {
+	pos := t.Position()
+	dx, dy, dz := pos[0]-origin.pos[0], pos[1]-origin.pos[1], pos[2]-origin.pos[2]
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// negated checks if the selector argument value passed is negated using a '!' prefix, and returns the value
+// without the prefix.
+func negated(value string) (bool, string) {
+	if strings.HasPrefix(value, "!") {
+		return true, strings.TrimPrefix(value, "!")
+	}
+	return false, value
+}
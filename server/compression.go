@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/flate"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// NewFlateCompression returns a packet.Compression that compresses packets using flate at the compression
+// level passed, which ranges from flate.BestSpeed (1) to flate.BestCompression (9). Higher levels use more
+// CPU to compress packets to fewer bytes. packet.FlateCompression uses a level of 6. NewFlateCompression
+// panics if the level is not valid.
+func NewFlateCompression(level int) packet.Compression {
+	if _, err := flate.NewWriter(io.Discard, level); err != nil {
+		panic(fmt.Errorf("new flate compression: %w", err))
+	}
+	return &flateCompression{writers: sync.Pool{New: func() any {
+		w, _ := flate.NewWriter(io.Discard, level)
+		return w
+	}}}
+}
+
+// flateCompression is a packet.Compression that compresses packets using flate at a specific level.
+type flateCompression struct {
+	writers sync.Pool
+}
+
+// EncodeCompression ...
+func (c *flateCompression) EncodeCompression() uint16 {
+	return packet.CompressionAlgorithmFlate
+}
+
+// Compress ...
+func (c *flateCompression) Compress(decompressed []byte) ([]byte, error) {
+	w := c.writers.Get().(*flate.Writer)
+	defer c.writers.Put(w)
+
+	compressed := bytes.NewBuffer(make([]byte, 0, len(decompressed)/2))
+	w.Reset(compressed)
+	if _, err := w.Write(decompressed); err != nil {
+		return nil, fmt.Errorf("compress flate: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("close flate writer: %w", err)
+	}
+	return compressed.Bytes(), nil
+}
+
+// Decompress ...
+func (c *flateCompression) Decompress(compressed []byte) ([]byte, error) {
+	return packet.FlateCompression.Decompress(compressed)
+}
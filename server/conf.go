@@ -1,32 +1,46 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"github.com/df-mc/dragonfly/server/account"
 	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/cmd/function"
 	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/df-mc/dragonfly/server/internal/packbuilder"
+	"github.com/df-mc/dragonfly/server/legacy"
+	"github.com/df-mc/dragonfly/server/logging"
+	"github.com/df-mc/dragonfly/server/moderation"
+	"github.com/df-mc/dragonfly/server/permission"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/playerdb"
+	"github.com/df-mc/dragonfly/server/player/transfer"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/biome"
 	"github.com/df-mc/dragonfly/server/world/generator"
 	"github.com/df-mc/dragonfly/server/world/mcdb"
 	"github.com/google/uuid"
+	"github.com/klauspost/compress/flate"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/sandertv/gophertunnel/minecraft/resource"
-	"github.com/sirupsen/logrus"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 )
 
 // Config contains options for starting a Minecraft server.
 type Config struct {
-	// Log is the Logger to use for logging information. If the Logger is a
-	// logrus.Logger, additional fields may be added to it for individual worlds
-	// to provide additional context. If left empty, Log will be set to a logger
-	// created with logrus.New().
-	Log Logger
+	// Log is the Logger to use for logging information. Records are
+	// attributed to the subsystems of the logging package, such as
+	// logging.Network and logging.World, so that their levels may be set
+	// individually using a logging.Handler. If left empty, Log will be set to
+	// slog.Default().
+	Log *slog.Logger
 	// Listeners is a list of functions to create a Listener using a Config, one
 	// for each Listener to be added to the Server. If left empty, no players
 	// will be able to connect to the Server.
@@ -36,21 +50,68 @@ type Config struct {
 	Name string
 	// Resources is a slice of resource packs to use on the server. When joining
 	// the server, the player will then first be requested to download these
-	// resource packs.
+	// resource packs. The packs are stacked in the order of the slice.
+	// Encrypted packs must have their content key set using
+	// resource.Pack.WithContentKey.
 	Resources []*resource.Pack
 	// ResourcesRequires specifies if the downloading of resource packs is
 	// required to join the server. If set to true, players will not be able to
-	// join without first downloading and applying the Resources above.
+	// join without first downloading and applying the Resources above, except
+	// for the packs listed in OptionalResources.
 	ResourcesRequired bool
+	// OptionalResources holds the UUIDs of the packs in Resources that players
+	// do not have to download, even if ResourcesRequired is true. Players are
+	// asked to accept or decline all packs offered to them at once, so a player
+	// must still accept the optional packs offered along with a required pack.
+	// A ResourceSelector may be used to leave optional packs out for players
+	// that do not want them.
+	OptionalResources []string
+	// ResourceSelector, if non-nil, selects the packs out of the Resources that
+	// a player is asked to download when joining, for example depending on the
+	// game mode that the player is transferred to. If nil, players are asked
+	// to download all Resources. ResourceSelector is only used by the listener
+	// created by UserConfig.Config.
+	ResourceSelector ResourceSelector
+	// AcceptedProtocols holds the protocols of older Minecraft versions that
+	// clients may join the server with, besides the version that the server
+	// implements. Each minecraft.Protocol translates the packets sent and
+	// received to and from the version it implements, including the block
+	// and item runtime IDs found in them. The protocols implemented by
+	// dragonfly are returned by legacy.Protocols. Clients of versions without
+	// a protocol in AcceptedProtocols are disconnected when joining.
+	// AcceptedProtocols is only used by the listener created by
+	// UserConfig.Config.
+	AcceptedProtocols []minecraft.Protocol
 	// DisableResourceBuilding specifies if automatic resource pack building for
 	// custom items should be disabled. Dragonfly, by default, automatically
 	// produces a resource pack for custom items. If this is not desired (for
 	// example if a resource pack already exists), this can be set to false.
 	DisableResourceBuilding bool
+	// StatusProvider provides the status of the server shown in the server
+	// list, such as the MOTD, the sub MOTD and the player counts. The status
+	// is requested from the StatusProvider every few seconds, so it may be
+	// changed dynamically, for example to show rotating MOTDs or the player
+	// count of an entire network. If nil, the Name of the server and its
+	// actual player counts are shown.
+	StatusProvider minecraft.ServerStatusProvider
 	// Allower may be used to specify what players can join the server and what
 	// players cannot. By returning false in the Allow method, for example if
 	// the player has been banned, will prevent the player from joining.
 	Allower Allower
+	// Moderation holds the bans and whitelist of the server. If non-nil,
+	// players that are banned, that join from a banned IP address or that are
+	// not whitelisted while the whitelist is enabled are refused when joining,
+	// before the Allower is called. Moderation is closed when the Server is
+	// closed.
+	Moderation *moderation.Manager
+	// Accounts, if non-nil, identifies players using local accounts instead
+	// of the UUID sent by their client, so that players keep their data
+	// without Xbox Live authentication. Accounts is only used by the
+	// listener created by UserConfig.Config and should only be set if
+	// AuthDisabled is true. In account.ModePassword, players must log in to
+	// their account before they can do anything else. Accounts is closed
+	// when the Server is closed.
+	Accounts *account.Manager
 	// AuthDisabled specifies if XBOX Live authentication should be disabled.
 	// Note that this should generally only be done for testing purposes or for
 	// local games. Allowing players to join without authentication is generally
@@ -59,6 +120,22 @@ type Config struct {
 	// MaxPlayers is the maximum amount of players allowed to join the server at
 	// once.
 	MaxPlayers int
+	// FloodProtection holds the limits on the packets and logins that clients
+	// may send, protecting the Server against spam bots. Clients exceeding
+	// the limits are disconnected and may be blocked from joining for a
+	// while. If left empty, no limits are enforced.
+	FloodProtection FloodProtection
+	// Compression is the packet.Compression used to compress the packets sent
+	// to players, such as packet.FlateCompression, packet.SnappyCompression or
+	// a flate compression with a custom level created using
+	// NewFlateCompression. Snappy compresses faster than flate but produces
+	// larger packets. If nil, packet.DefaultCompression is used.
+	Compression packet.Compression
+	// FlushRate is the interval at which the packets sent to a player are
+	// batched, compressed and sent together. Longer intervals lead to fewer,
+	// better compressed batches at the cost of latency. If 0, packets are
+	// flushed every tick (time.Second/20).
+	FlushRate time.Duration
 	// MaxChunkRadius is the maximum view distance that each player may have,
 	// measured in chunks. A chunk radius generally leads to more memory usage.
 	MaxChunkRadius int
@@ -72,11 +149,27 @@ type Config struct {
 	// data. If left as nil, player data will be newly created every time a
 	// player joins the server and no data will be stored.
 	PlayerProvider player.Provider
+	// PermissionProvider is the permission.Provider used for storing and
+	// loading the permissions of players. If left as nil, players will join
+	// with no permissions every time and permissions will not be stored.
+	PermissionProvider permission.Provider
+	// TransferStore is the transfer.Store that the metadata of players
+	// transferred to other servers is stored in and retrieved from when
+	// players join. It must be shared with other servers for the metadata
+	// to be delivered across servers. If nil, no metadata is stored.
+	TransferStore transfer.Store
 	// WorldProvider is the world.Provider used for storing and loading world
 	// data. If left as nil, world data will be newly created every time and
 	// chunks will always be newly generated when loaded. The world provider
 	// will be used for storing/loading the default overworld, nether and end.
 	WorldProvider world.Provider
+	// AutoSaveInterval is the interval at which the Server saves the chunks
+	// changed in its worlds and the data of the players online in the
+	// background, so that a crash does not lose everything since the Server
+	// was started. Regardless of AutoSaveInterval, the Server attempts to
+	// save its worlds and players one last time if one of its worlds or
+	// sessions panics. If 0, the Server is only saved when it is closed.
+	AutoSaveInterval time.Duration
 	// ReadOnlyWorld specifies if the standard worlds should be read only. If
 	// set to true, the WorldProvider won't be saved to at all.
 	ReadOnlyWorld bool
@@ -84,28 +177,48 @@ type Config struct {
 	// use for every world.Dimension (world.Overworld, world.Nether and
 	// world.End). If left empty, Generator will be set to a flat world for each
 	// of the dimensions (with netherrack and end stone for nether/end
-	// respectively).
+	// respectively). If nil is returned for a world.CustomDimension, the
+	// Generator of the dimension is used.
 	Generator func(dim world.Dimension) world.Generator
+	// Dimensions holds additional dimensions, such as world.CustomDimensions,
+	// for which the Server creates a world next to the Overworld, Nether and
+	// End. Every dimension must be registered using world.RegisterDimension.
+	// The worlds created may be obtained using Server.Dimension.
+	Dimensions []world.Dimension
 	// RandomTickSpeed specifies the rate at which blocks should be ticked in
 	// the default worlds. Setting this value to -1 or lower will stop random
 	// ticking altogether, while setting it higher results in faster ticking. If
-	// left as 0, the RandomTickSpeed will default to a speed of 3 blocks per
-	// sub chunk per tick (normal ticking speed).
+	// left as 0, the randomTickSpeed game rule of the worlds is used, which
+	// defaults to a speed of 3 blocks per sub chunk per tick (normal ticking
+	// speed).
 	RandomTickSpeed int
 	// Entities is a world.EntityRegistry with all entity types registered that
 	// may be added to the Server's worlds. If no entity types are registered,
 	// Entities will be set to entity.DefaultRegistry.
 	Entities world.EntityRegistry
-}
-
-// Logger is used to report information and errors from a dragonfly Server. Any
-// Logger implementation may be used by passing it to the Log field in Config.
-type Logger interface {
-	world.Logger
-	session.Logger
-	Infof(format string, v ...any)
-	Fatalf(format string, v ...any)
-	Warnf(format string, v ...any)
+	// Combat holds the player.Combat settings that every player joining the
+	// Server starts with, controlling attack cooldown, knockback, hit delay
+	// and critical hits. If left empty, Combat will be set to
+	// player.DefaultCombat(). The settings of a single player may be changed
+	// using player.Player.SetCombat.
+	Combat player.Combat
+	// MovementValidation holds the player.MovementValidation settings that
+	// every player joining the Server starts with, controlling which checks
+	// the movement of players is validated with and whether movement
+	// failing them is corrected. If left empty, movement is not validated.
+	MovementValidation player.MovementValidation
+	// MetricsAddress is the address on which the Server exports its metrics
+	// over HTTP at the /metrics path, in the Prometheus text format. The
+	// metrics include tick durations, loaded chunks, entity and player counts,
+	// packet throughput and world provider latency. If empty, metrics are not
+	// exported.
+	MetricsAddress string
+	// Pprof specifies if the runtime profiles of net/http/pprof are exported
+	// at the /debug/pprof/ path of the MetricsAddress, for debugging the
+	// performance of the Server. Pprof has no effect if MetricsAddress is
+	// empty. Profiles expose details of the Server, so the address should
+	// not be publicly reachable if Pprof is enabled.
+	Pprof bool
 }
 
 // New creates a Server using fields of conf. The Server's worlds are created
@@ -113,10 +226,10 @@ type Logger interface {
 // Server.Listen() and Server.Accept() afterwards.
 func (conf Config) New() *Server {
 	if conf.Log == nil {
-		conf.Log = logrus.New()
+		conf.Log = slog.Default()
 	}
 	if len(conf.Listeners) == 0 {
-		conf.Log.Warnf("config: no listeners set, no connections will be accepted")
+		conf.Log.Warn("config: no listeners set, no connections will be accepted")
 	}
 	if conf.Name == "" {
 		conf.Name = "Dragonfly Server"
@@ -124,9 +237,21 @@ func (conf Config) New() *Server {
 	if conf.PlayerProvider == nil {
 		conf.PlayerProvider = player.NopProvider{}
 	}
+	if conf.PermissionProvider == nil {
+		conf.PermissionProvider = permission.NopProvider{}
+	}
+	if conf.TransferStore == nil {
+		conf.TransferStore = transfer.NopStore{}
+	}
 	if conf.Allower == nil {
 		conf.Allower = allower{}
 	}
+	if conf.Moderation != nil {
+		conf.Allower = allowers{conf.Moderation, conf.Allower}
+	}
+	if conf.StatusProvider == nil {
+		conf.StatusProvider = statusProvider{name: conf.Name}
+	}
 	if conf.WorldProvider == nil {
 		conf.WorldProvider = world.NopProvider{}
 	}
@@ -139,6 +264,9 @@ func (conf Config) New() *Server {
 	if len(conf.Entities.Types()) == 0 {
 		conf.Entities = entity.DefaultRegistry
 	}
+	if conf.Combat == (player.Combat{}) {
+		conf.Combat = player.DefaultCombat()
+	}
 	if !conf.DisableResourceBuilding {
 		if pack, ok := packbuilder.BuildResourcePack(); ok {
 			conf.Resources = append(conf.Resources, pack)
@@ -146,16 +274,28 @@ func (conf Config) New() *Server {
 	}
 	// Copy resources so that the slice can't be edited afterwards.
 	conf.Resources = slices.Clone(conf.Resources)
+	conf.OptionalResources = slices.Clone(conf.OptionalResources)
+	conf.AcceptedProtocols = slices.Clone(conf.AcceptedProtocols)
+	conf.Dimensions = slices.Clone(conf.Dimensions)
 
 	srv := &Server{
 		conf:     conf,
 		incoming: make(chan *session.Session),
 		p:        make(map[uuid.UUID]*player.Player),
+		online:   make(map[uuid.UUID]int),
 		world:    &world.World{}, nether: &world.World{}, end: &world.World{},
 	}
 	srv.world = srv.createWorld(world.Overworld, &srv.nether, &srv.end)
 	srv.nether = srv.createWorld(world.Nether, &srv.world, &srv.end)
 	srv.end = srv.createWorld(world.End, &srv.nether, &srv.world)
+	srv.dimensions = make(map[world.Dimension]*world.World, len(conf.Dimensions))
+	for _, dim := range conf.Dimensions {
+		if _, ok := world.DimensionID(dim); !ok {
+			conf.Log.Error("config: dimension is not registered", "dimension", dim)
+			os.Exit(1)
+		}
+		srv.dimensions[dim] = srv.createWorld(dim, &srv.nether, &srv.end)
+	}
 
 	srv.registerTargetFunc()
 	srv.checkNetIsolation()
@@ -173,6 +313,48 @@ type UserConfig struct {
 		// Address is the address on which the server should listen. Players may
 		// connect to this address in order to join.
 		Address string
+		// MaxPacketsPerSecond is the maximum amount of packets a player may
+		// send per second before being disconnected. Set to 0 to disable.
+		MaxPacketsPerSecond int
+		// MaxPacketSize is the maximum size in bytes of a packet sent by a
+		// player before being disconnected. Set to 0 to disable.
+		MaxPacketSize int
+		// MaxLoginsPerMinute is the maximum amount of times players from the
+		// same IP address may join per minute. Set to 0 to disable.
+		MaxLoginsPerMinute int
+		// FloodBlockSeconds is the number of seconds for which an IP address
+		// is refused after one of the limits above was exceeded from it. Set
+		// to 0 to only disconnect players exceeding the limits.
+		FloodBlockSeconds int
+		// Compression is the algorithm used to compress packets sent to
+		// players: Either "flate" or "snappy". Snappy uses less CPU, while
+		// flate uses less bandwidth.
+		Compression string
+		// CompressionLevel is the level of flate compression, from 1 (fastest)
+		// to 9 (smallest), or 0 for the default level of 6. It has no effect
+		// for snappy.
+		CompressionLevel int
+		// FlushIntervalMillis is the interval in milliseconds at which packets
+		// sent to a player are batched and sent together.
+		FlushIntervalMillis int
+		// AcceptLegacyVersions specifies if players may join with older
+		// Minecraft versions supported by dragonfly, next to the version that
+		// the server implements. Blocks and items that do not exist in the
+		// version of a player are shown as unknown to them.
+		AcceptLegacyVersions bool
+	}
+	Proxy struct {
+		// Enabled specifies if the server runs behind a proxy, such as
+		// WaterdogPE. If true, the server trusts the XUID and IP address
+		// forwarded by the proxy in the login of players instead of
+		// authenticating players with XBOX Live itself, and players that
+		// connect to the server directly are disconnected.
+		Enabled bool
+		// Secret is the secret that the proxy includes in the Proxy_Secret
+		// field of the client data of players it forwards. Logins without
+		// the Secret are disconnected. Secret must be set if the proxy is
+		// Enabled.
+		Secret string
 	}
 	Server struct {
 		// Name is the name of the server as it shows up in the server list.
@@ -202,6 +384,23 @@ type UserConfig struct {
 		SaveData bool
 		// Folder is the folder that the data of the world resides in.
 		Folder string
+		// Generator is the generator used to generate new chunks of the
+		// overworld, the Nether and the End. It may be either "flat" or
+		// "normal", the latter of which generates noise based terrain with
+		// biomes.
+		Generator string
+		// Seed is the seed used by the "normal" generator. The same seed
+		// always results in the same terrain.
+		Seed int64
+		// RandomTickSpeed is the number of blocks randomly ticked in every
+		// sub chunk each tick. If set to 0, the randomTickSpeed game rule of
+		// the worlds is used. Setting it to -1 or lower disables random
+		// ticking altogether.
+		RandomTickSpeed int
+		// AutoSaveMinutes is the interval in minutes at which changed chunks
+		// and the data of online players are saved in the background. Set to
+		// 0 to only save when the server is closed.
+		AutoSaveMinutes int
 	}
 	Players struct {
 		// MaxCount is the maximum amount of players allowed to join the server
@@ -219,26 +418,159 @@ type UserConfig struct {
 		// provider.
 		SaveData bool
 		// Folder controls where the player data will be stored by the default
-		// LevelDB player provider if it is enabled.
+		// player providers if SaveData is enabled.
 		Folder string
+		// Format is the format that player data is stored in if SaveData is
+		// true. It may be either "leveldb" or "json", the latter of which
+		// stores the data of every player in a separate JSON file in Folder.
+		Format string
 	}
 	Resources struct {
 		// AutoBuildPack is if the server should automatically generate a
 		// resource pack for custom features.
 		AutoBuildPack bool
 		// Folder controls the location where resource packs will be loaded
-		// from.
+		// from. Packs are stacked in the alphabetical order of their file
+		// names. The content key of an encrypted pack is read from a file
+		// with the name of the pack and a .key extension, for example
+		// pack.zip.key for pack.zip.
 		Folder string
-		// Required is a boolean to force the client to load the resource pack
-		// on join. If they do not accept, they'll have to leave the server.
+		// Required is a boolean to force the client to load the resource
+		// packs on join. If they do not accept, they'll have to leave the
+		// server.
 		Required bool
+		// Optional holds the file names of packs in the Folder that clients
+		// never have to load, even if Required is true.
+		Optional []string
+	}
+	Moderation struct {
+		// File is the JSON file that bans, IP bans and the whitelist are
+		// stored in. Leave this empty to disable bans and the whitelist.
+		File string
+	}
+	Accounts struct {
+		// Enabled specifies if players are identified using local accounts
+		// instead of the UUID sent by their client. Accounts may only be
+		// enabled if AuthEnabled is false and the proxy is disabled.
+		Enabled bool
+		// Mode is either "password", to make players register a password
+		// and log in with it every time they join, or "device", to bind the
+		// name of a player to the device that first joined with it. The
+		// device ID is sent by the client and may be spoofed, so "device"
+		// should only be used if all players are trusted.
+		Mode string
+		// File is the JSON file that accounts are stored in.
+		File string
+	}
+	Functions struct {
+		// Folder is the folder that functions are loaded from. Functions are
+		// .mcfunction files holding one command per line, which may be run
+		// using the /function command. Leave this empty to not load any
+		// functions.
+		Folder string
+	}
+	Plugins struct {
+		// Folder is the folder that Go plugins, compiled using
+		// -buildmode=plugin, are loaded from when the server starts. Leave
+		// this empty to not load any plugins.
+		Folder string
+	}
+	Scripts struct {
+		// Folder is the folder that Lua scripts are loaded from. Scripts are
+		// reloaded automatically when they are changed. Leave this empty to
+		// not run any scripts.
+		Folder string
+	}
+	Combat struct {
+		// AttackCooldownTicks is the number of ticks it takes for an attack
+		// of a player to fully charge, like in Java Edition 1.9 and newer.
+		// Attacks made earlier deal reduced damage. Set this to 0 to disable
+		// the attack cooldown.
+		AttackCooldownTicks int
+		// KnockBackHorizontal and KnockBackVertical are the base horizontal
+		// and vertical knockback of attacks.
+		KnockBackHorizontal, KnockBackVertical float64
+		// SprintKnockBackHorizontal and SprintKnockBackVertical are added to
+		// the knockback of attacks made while sprinting.
+		SprintKnockBackHorizontal, SprintKnockBackVertical float64
+		// HitDelayTicks is the number of ticks that a player is immune to
+		// attacks after being hurt.
+		HitDelayTicks int
+		// Criticals controls whether attacks made while falling are critical
+		// hits, dealing CriticalMultiplier times the normal damage.
+		Criticals bool
+		// CriticalMultiplier is the factor by which the damage of critical
+		// hits is multiplied.
+		CriticalMultiplier float64
+		// SprintCriticals controls whether critical hits may also be made
+		// while sprinting, like in Java Edition 1.8.
+		SprintCriticals bool
+	}
+	Movement struct {
+		// Validate controls whether the movement of players is validated by
+		// the server, checking for speed, flying, phasing through blocks and
+		// stepping up too high.
+		Validate bool
+		// Correct controls whether movement failing validation is corrected
+		// by moving the player back. If false, it is only reported to
+		// handlers.
+		Correct bool
+		// Tolerance is the distance in blocks by which movement may exceed
+		// the limits of validation before failing it.
+		Tolerance float64
+	}
+	Metrics struct {
+		// Address is the address on which metrics of the server are
+		// exported at the /metrics path, so that they may be scraped by
+		// Prometheus. Leave this empty to not export metrics.
+		Address string
+		// Pprof controls whether runtime profiles are exported at the
+		// /debug/pprof/ path of Address for debugging performance.
+		Pprof bool
+	}
+	Logging struct {
+		// Level is the minimum level of messages logged: debug, info, warn
+		// or error. It may be changed while the server is running using the
+		// /loglevel command.
+		Level string
+		// Subsystems holds the levels of subsystems that should log at a
+		// different Level, such as network = "warn". The subsystems are
+		// server, network, world, chunkgen, entities and commands.
+		Subsystems map[string]string
+		// JSON controls whether messages are logged as JSON objects, one
+		// per line, for log aggregation, instead of as text.
+		JSON bool
+	}
+	Admin struct {
+		// Address is the address on which the admin API is served over
+		// HTTP, which lists players and worlds, kicks and bans players, runs
+		// commands and streams the console over a WebSocket for web panels
+		// and bots. Leave this empty to not serve the admin API.
+		Address string
+		// Token is the secret that requests to the admin API must be
+		// authenticated with. The admin API is not served without a Token.
+		Token string
+		// AllowedOrigins holds the origins of web panels, such as
+		// "https://panel.example.com", that may stream the console from a
+		// browser.
+		AllowedOrigins []string
+	}
+	Rcon struct {
+		// Address is the address on which the server accepts RCON
+		// connections, which hosting panels and other tools use to run
+		// commands remotely. The default RCON port is 25575. Leave this
+		// empty to disable RCON.
+		Address string
+		// Password is the password that RCON clients must authenticate
+		// with. RCON is disabled without a Password.
+		Password string
 	}
 }
 
 // Config converts a UserConfig to a Config, so that it may be used for creating
 // a Server. An error is returned if creating data providers or loading
 // resources failed.
-func (uc UserConfig) Config(log Logger) (Config, error) {
+func (uc UserConfig) Config(log *slog.Logger) (Config, error) {
 	var err error
 	conf := Config{
 		Log:                     log,
@@ -251,19 +583,111 @@ func (uc UserConfig) Config(log Logger) (Config, error) {
 		QuitMessage:             uc.Server.QuitMessage,
 		ShutdownMessage:         uc.Server.ShutdownMessage,
 		DisableResourceBuilding: !uc.Resources.AutoBuildPack,
+		RandomTickSpeed:         uc.World.RandomTickSpeed,
+		AutoSaveInterval:        time.Duration(uc.World.AutoSaveMinutes) * time.Minute,
+		MetricsAddress:          uc.Metrics.Address,
+		Pprof:                   uc.Metrics.Pprof,
+		FloodProtection: FloodProtection{
+			MaxPacketsPerSecond: uc.Network.MaxPacketsPerSecond,
+			MaxPacketSize:       uc.Network.MaxPacketSize,
+			MaxLoginsPerMinute:  uc.Network.MaxLoginsPerMinute,
+			BlockDuration:       time.Duration(uc.Network.FloodBlockSeconds) * time.Second,
+		},
+		FlushRate: time.Duration(uc.Network.FlushIntervalMillis) * time.Millisecond,
+		Combat: player.Combat{
+			AttackCooldown:        time.Duration(uc.Combat.AttackCooldownTicks) * time.Second / 20,
+			KnockBackForce:        uc.Combat.KnockBackHorizontal,
+			KnockBackHeight:       uc.Combat.KnockBackVertical,
+			SprintKnockBackForce:  uc.Combat.SprintKnockBackHorizontal,
+			SprintKnockBackHeight: uc.Combat.SprintKnockBackVertical,
+			HitDelay:              time.Duration(uc.Combat.HitDelayTicks) * time.Second / 20,
+			Criticals:             uc.Combat.Criticals,
+			CriticalMultiplier:    uc.Combat.CriticalMultiplier,
+			SprintCriticals:       uc.Combat.SprintCriticals,
+		},
+	}
+	if uc.Proxy.Enabled && uc.Proxy.Secret == "" {
+		return conf, fmt.Errorf("proxy: a secret must be set to enable the proxy")
+	}
+	if uc.Network.AcceptLegacyVersions {
+		conf.AcceptedProtocols = legacy.Protocols()
+	}
+	switch strings.ToLower(uc.Network.Compression) {
+	case "", "flate":
+		if level := uc.Network.CompressionLevel; level != 0 {
+			if level < flate.BestSpeed || level > flate.BestCompression {
+				return conf, fmt.Errorf("invalid flate compression level %v: must be between 1 and 9", level)
+			}
+			conf.Compression = NewFlateCompression(level)
+		}
+	case "snappy":
+		conf.Compression = packet.SnappyCompression
+	default:
+		return conf, fmt.Errorf("unknown compression %v: must be flate or snappy", uc.Network.Compression)
+	}
+	if uc.Movement.Validate {
+		conf.MovementValidation = player.DefaultMovementValidation()
+		conf.MovementValidation.Correct, conf.MovementValidation.Tolerance = uc.Movement.Correct, uc.Movement.Tolerance
+	}
+	if uc.World.Generator == "normal" {
+		conf.Generator = func(dim world.Dimension) world.Generator {
+			switch dim {
+			case world.Overworld:
+				return generator.NewOverworld(uc.World.Seed)
+			case world.Nether:
+				return generator.NewNether(uc.World.Seed)
+			case world.End:
+				return generator.NewEnd(uc.World.Seed)
+			}
+			return loadGenerator(dim)
+		}
 	}
 	if uc.World.SaveData {
-		conf.WorldProvider, err = mcdb.Config{Log: log}.Open(uc.World.Folder)
+		conf.WorldProvider, err = mcdb.Config{Log: logging.With(log, logging.World)}.Open(uc.World.Folder)
 		if err != nil {
 			return conf, fmt.Errorf("create world provider: %w", err)
 		}
 	}
-	conf.Resources, err = loadResources(uc.Resources.Folder)
+	conf.Resources, conf.OptionalResources, err = loadResources(uc.Resources.Folder, uc.Resources.Optional)
 	if err != nil {
 		return conf, fmt.Errorf("load resources: %w", err)
 	}
+	if uc.Moderation.File != "" {
+		p, err := moderation.NewJSONProvider(uc.Moderation.File)
+		if err != nil {
+			return conf, fmt.Errorf("create moderation provider: %w", err)
+		}
+		if conf.Moderation, err = moderation.New(p); err != nil {
+			return conf, err
+		}
+	}
+	if uc.Accounts.Enabled {
+		if uc.Server.AuthEnabled || uc.Proxy.Enabled {
+			return conf, fmt.Errorf("accounts: authentication and the proxy must be disabled to use accounts")
+		}
+		mode, err := account.ParseMode(uc.Accounts.Mode)
+		if err != nil {
+			return conf, fmt.Errorf("accounts: %w", err)
+		}
+		p, err := account.NewJSONProvider(uc.Accounts.File)
+		if err != nil {
+			return conf, fmt.Errorf("create account provider: %w", err)
+		}
+		if conf.Accounts, err = account.New(p, mode); err != nil {
+			return conf, err
+		}
+	}
+	if uc.Functions.Folder != "" {
+		if err := function.Load(uc.Functions.Folder); err != nil {
+			return conf, fmt.Errorf("load functions: %w", err)
+		}
+	}
 	if uc.Players.SaveData {
-		conf.PlayerProvider, err = playerdb.NewProvider(uc.Players.Folder)
+		if uc.Players.Format == "json" {
+			conf.PlayerProvider, err = playerdb.NewJSONProvider(uc.Players.Folder)
+		} else {
+			conf.PlayerProvider, err = playerdb.NewProvider(uc.Players.Folder)
+		}
 		if err != nil {
 			return conf, fmt.Errorf("create player provider: %w", err)
 		}
@@ -272,25 +696,43 @@ func (uc UserConfig) Config(log Logger) (Config, error) {
 	return conf, nil
 }
 
-// loadResources loads all resource packs found in a directory passed.
-func loadResources(dir string) ([]*resource.Pack, error) {
+// loadResources loads all resource packs found in a directory passed, in the
+// alphabetical order of their names. If a file with the name of a pack and a
+// .key extension is present, its content is used as content key of the pack.
+// The UUIDs of the packs with a name found in optional are returned too.
+func loadResources(dir string, optional []string) ([]*resource.Pack, []string, error) {
 	_ = os.MkdirAll(dir, 0777)
 
 	resources, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, fmt.Errorf("read dir: %w", err)
+		return nil, nil, fmt.Errorf("read dir: %w", err)
 	}
-	packs := make([]*resource.Pack, len(resources))
-	for i, entry := range resources {
-		packs[i], err = resource.ReadPath(filepath.Join(dir, entry.Name()))
+	var optionalUUIDs []string
+	packs := make([]*resource.Pack, 0, len(resources))
+	for _, entry := range resources {
+		if filepath.Ext(entry.Name()) == ".key" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		pack, err := resource.ReadPath(path)
 		if err != nil {
-			return nil, fmt.Errorf("compile resource (%v): %w", entry.Name(), err)
+			return nil, nil, fmt.Errorf("compile resource (%v): %w", entry.Name(), err)
+		}
+		if key, err := os.ReadFile(path + ".key"); err == nil {
+			pack = pack.WithContentKey(strings.TrimSpace(string(key)))
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("read content key (%v): %w", entry.Name(), err)
+		}
+		if slices.Contains(optional, entry.Name()) {
+			optionalUUIDs = append(optionalUUIDs, pack.UUID())
 		}
+		packs = append(packs, pack)
 	}
-	return packs, nil
+	return packs, optionalUUIDs, nil
 }
 
-// loadGenerator loads a standard world.Generator for a world.Dimension.
+// loadGenerator loads a standard world.Generator for a world.Dimension. It
+// returns nil for dimensions other than the Overworld, Nether and End.
 func loadGenerator(dim world.Dimension) world.Generator {
 	switch dim {
 	case world.Overworld:
@@ -300,13 +742,21 @@ func loadGenerator(dim world.Dimension) world.Generator {
 	case world.End:
 		return generator.NewFlat(biome.End{}, []world.Block{block.EndStone{}, block.EndStone{}, block.EndStone{}, block.Bedrock{}})
 	}
-	panic("should never happen")
+	// Custom dimensions use their own generator.
+	return nil
 }
 
 // DefaultConfig returns a configuration with the default values filled out.
 func DefaultConfig() UserConfig {
 	c := UserConfig{}
 	c.Network.Address = ":19132"
+	c.Network.MaxPacketsPerSecond = 500
+	c.Network.MaxPacketSize = 4 << 20
+	c.Network.MaxLoginsPerMinute = 10
+	c.Network.FloodBlockSeconds = 300
+	c.Network.Compression = "flate"
+	c.Network.CompressionLevel = 6
+	c.Network.FlushIntervalMillis = 50
 	c.Server.Name = "Dragonfly Server"
 	c.Server.ShutdownMessage = "Server closed."
 	c.Server.AuthEnabled = true
@@ -314,11 +764,30 @@ func DefaultConfig() UserConfig {
 	c.Server.QuitMessage = "%v has left the game"
 	c.World.SaveData = true
 	c.World.Folder = "world"
+	c.World.Generator = "flat"
+	c.World.Seed = time.Now().Unix()
+	c.World.AutoSaveMinutes = 5
 	c.Players.MaximumChunkRadius = 32
 	c.Players.SaveData = true
 	c.Players.Folder = "players"
+	c.Players.Format = "leveldb"
 	c.Resources.AutoBuildPack = true
 	c.Resources.Folder = "resources"
 	c.Resources.Required = false
+	c.Moderation.File = "moderation.json"
+	c.Accounts.Mode = "password"
+	c.Accounts.File = "accounts.json"
+	c.Functions.Folder = "functions"
+	c.Plugins.Folder = "plugins"
+	c.Scripts.Folder = "scripts"
+	c.Combat.KnockBackHorizontal = 0.45
+	c.Combat.KnockBackVertical = 0.3608
+	c.Combat.HitDelayTicks = 10
+	c.Combat.Criticals = true
+	c.Combat.CriticalMultiplier = 1.5
+	c.Movement.Correct = true
+	c.Movement.Tolerance = 0.1
+	c.Logging.Level = "debug"
+	c.Logging.Subsystems = map[string]string{}
 	return c
 }
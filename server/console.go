@@ -0,0 +1,126 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/logging"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// ConsoleSource is a cmd.Source that represents the console of a Server. It
+// has every permission and writes the output of commands to the Logger of the
+// Server. A ConsoleSource is located at the spawn of the overworld.
+type ConsoleSource struct {
+	srv *Server
+}
+
+// Name returns "Console".
+func (ConsoleSource) Name() string {
+	return "Console"
+}
+
+// Position returns the spawn position of the overworld of the Server.
+func (c ConsoleSource) Position() mgl64.Vec3 {
+	return c.srv.world.Spawn().Vec3Middle()
+}
+
+// World returns the overworld of the Server.
+func (c ConsoleSource) World() *world.World {
+	return c.srv.world
+}
+
+// HasPermission always returns true: The console may run every command.
+func (ConsoleSource) HasPermission(string) bool {
+	return true
+}
+
+// SendCommandOutput logs the messages and errors of the cmd.Output passed
+// using the Logger of the Server, attributed to logging.Commands.
+func (c ConsoleSource) SendCommandOutput(o *cmd.Output) {
+	log := logging.With(c.srv.conf.Log, logging.Commands)
+	for _, m := range o.Messages() {
+		log.Info(m)
+	}
+	for _, err := range o.Errors() {
+		log.Error(err.Error())
+	}
+}
+
+// Console returns the ConsoleSource of the Server, which may be used to run
+// commands as the console.
+func (srv *Server) Console() ConsoleSource {
+	return ConsoleSource{srv: srv}
+}
+
+// ExecuteConsoleCommand executes the command line passed as the console of
+// the Server. The command line may optionally start with a '/'. An error is
+// logged if the command does not exist.
+func (srv *Server) ExecuteConsoleCommand(commandLine string) {
+	commandLine = strings.TrimPrefix(strings.TrimSpace(commandLine), "/")
+	if commandLine == "" {
+		return
+	}
+	name, args, _ := strings.Cut(commandLine, " ")
+	command, ok := cmd.ByAlias(name)
+	if !ok {
+		logging.With(srv.conf.Log, logging.Commands).Error("Unknown command.", "name", name)
+		return
+	}
+	command.Execute(args, srv.Console())
+}
+
+// RunConsoleCommand executes the command line passed as the console of the
+// Server, like ExecuteConsoleCommand, but returns the cmd.Output of the command
+// instead of logging it. The command line may optionally start with a '/'. An
+// error is returned if the command does not exist.
+func (srv *Server) RunConsoleCommand(commandLine string) (*cmd.Output, error) {
+	o := &cmd.Output{}
+	commandLine = strings.TrimPrefix(strings.TrimSpace(commandLine), "/")
+	if commandLine == "" {
+		return o, nil
+	}
+	name, args, _ := strings.Cut(commandLine, " ")
+	command, ok := cmd.ByAlias(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown command: %v", name)
+	}
+	command.Execute(args, outputSource{ConsoleSource: srv.Console(), o: o})
+	return o, nil
+}
+
+// outputSource is a ConsoleSource that stores the output of commands in a
+// cmd.Output instead of logging it.
+type outputSource struct {
+	ConsoleSource
+	o *cmd.Output
+}
+
+// SendCommandOutput adds the messages and errors of the cmd.Output passed to
+// the output stored, so that no output is lost if a command sends output more
+// than once.
+func (s outputSource) SendCommandOutput(o *cmd.Output) {
+	for _, err := range o.Errors() {
+		s.o.Errorf("%w", err)
+	}
+	for _, m := range o.Messages() {
+		s.o.Print(m)
+	}
+}
+
+// ReadConsole starts reading command lines from the io.Reader passed, such as
+// os.Stdin, and executes every line read as a command run by the console of
+// the Server. ReadConsole does not block: Lines are read on a different
+// goroutine until the io.Reader returns an error or the Server is closed.
+func (srv *Server) ReadConsole(r io.Reader) {
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() && !srv.closed.Load() {
+			srv.ExecuteConsoleCommand(scanner.Text())
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+)
+
+func TestOutputSourceAppends(t *testing.T) {
+	o := &cmd.Output{}
+	s := outputSource{o: o}
+
+	first, second := &cmd.Output{}, &cmd.Output{}
+	first.Print("one")
+	first.Errorf("failed")
+	second.Print("two")
+	second.Printf("three %v", 3)
+	s.SendCommandOutput(first)
+	s.SendCommandOutput(second)
+
+	if want := []string{"one", "two", "three 3"}; !slices.Equal(o.Messages(), want) {
+		t.Errorf("SendCommandOutput: got messages %v, want %v", o.Messages(), want)
+	}
+	if o.ErrorCount() != 1 || !errors.Is(o.Errors()[0], first.Errors()[0]) {
+		t.Errorf("SendCommandOutput: got errors %v, want [failed]", o.Errors())
+	}
+}
@@ -47,6 +47,10 @@ type FireworkExplosionAction struct{ action }
 // TotemUseAction is a world.EntityAction that displays the totem use particles and animation.
 type TotemUseAction struct{ action }
 
+// LoveAction is a world.EntityAction that makes an entity display heart particles, indicating that it entered love
+// mode.
+type LoveAction struct{ action }
+
 // action implements the Action interface. Structures in this package may embed it to gets its functionality
 // out of the box.
 type action struct{}
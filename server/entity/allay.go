@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"time"
+)
+
+// NewAllay creates a new allay at the position passed.
+func NewAllay(pos mgl64.Vec3) *Mob {
+	conf := allayConf
+	conf.Behaviour = AllayBehaviourConfig{}.New()
+	return conf.New(AllayType{}, pos)
+}
+
+var allayConf = MobConfig{
+	MaxHealth: 20,
+	Speed:     0.15,
+	Drag:      0.1,
+	Leashable: true,
+	Drops: func(m *Mob, _ world.DamageSource) []item.Stack {
+		a := m.Behaviour().(*AllayBehaviour)
+		held, _ := a.HeldItems()
+		var drops []item.Stack
+		for _, s := range []item.Stack{held, a.Inventory()} {
+			if !s.Empty() {
+				drops = append(drops, s)
+			}
+		}
+		return drops
+	},
+}
+
+// AllayType is a world.EntityType implementation for the allay.
+type AllayType struct{}
+
+func (AllayType) EncodeEntity() string { return "minecraft:allay" }
+func (AllayType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.175, 0, -0.175, 0.175, 0.6, 0.175)
+}
+
+func (AllayType) DecodeNBT(m map[string]any) world.Entity {
+	a := NewAllay(nbtconv.Vec3(m, "Pos"))
+	b := a.Behaviour().(*AllayBehaviour)
+	b.held = nbtconv.MapItem(m, "Mainhand")
+	b.inventory = nbtconv.MapItem(m, "Inventory")
+	b.duplication = nbtconv.TickDuration[int64](m, "AllayDuplicationCooldown")
+	return decodeMobNBT(a, m)
+}
+
+func (AllayType) EncodeNBT(e world.Entity) map[string]any {
+	a := e.(*Mob)
+	b := a.Behaviour().(*AllayBehaviour)
+	data := encodeMobNBT(a)
+	held, _ := b.HeldItems()
+	if !held.Empty() {
+		data["Mainhand"] = nbtconv.WriteItem(held, true)
+	}
+	if inv := b.Inventory(); !inv.Empty() {
+		data["Inventory"] = nbtconv.WriteItem(inv, true)
+	}
+	data["AllayDuplicationCooldown"] = int64(b.DuplicationCooldown() / (time.Second / 20))
+	return data
+}
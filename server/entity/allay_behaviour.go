@@ -0,0 +1,386 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// AllayBehaviourConfig holds optional parameters for an AllayBehaviour.
+type AllayBehaviourConfig struct {
+	// CollectRange is the range within which the allay looks for items that
+	// match the item it is holding. If 0, a range of 32 blocks is used.
+	CollectRange float64
+	// DeliverRange is the maximum distance from the allay of the player or
+	// note block that it delivers items to. If 0, a range of 64 blocks is
+	// used.
+	DeliverRange float64
+	// NoteBlockDuration is the duration for which the allay delivers items
+	// to a note block it heard instead of to the player it likes. If 0, a
+	// duration of 30 seconds is used.
+	NoteBlockDuration time.Duration
+	// DuplicationCooldown is the time after duplicating during which the
+	// allay cannot duplicate again. If 0, a duration of 5 minutes is used.
+	DuplicationCooldown time.Duration
+}
+
+// New creates an AllayBehaviour using the parameters in conf.
+func (conf AllayBehaviourConfig) New() *AllayBehaviour {
+	if conf.CollectRange == 0 {
+		conf.CollectRange = 32
+	}
+	if conf.DeliverRange == 0 {
+		conf.DeliverRange = 64
+	}
+	if conf.NoteBlockDuration == 0 {
+		conf.NoteBlockDuration = time.Second * 30
+	}
+	if conf.DuplicationCooldown == 0 {
+		conf.DuplicationCooldown = time.Minute * 5
+	}
+	return &AllayBehaviour{conf: conf}
+}
+
+// AllayBehaviour implements the behaviour of the allay. A player may give an
+// allay an item, after which the allay collects items of the same type and
+// delivers them to that player, or to a note block it recently heard. Allays
+// dance near jukeboxes playing music, during which they may be duplicated by
+// giving them an amethyst shard.
+type AllayBehaviour struct {
+	conf AllayBehaviourConfig
+
+	mu          sync.Mutex
+	held        item.Stack
+	inventory   item.Stack
+	liked       world.Entity
+	noteBlock   mgl64.Vec3
+	noteHeard   time.Duration
+	jukebox     mgl64.Vec3
+	dancing     bool
+	duplication time.Duration
+	pickup      time.Duration
+	wander      mgl64.Vec3
+}
+
+// HeldItems returns the item given to the allay in its main hand.
+func (a *AllayBehaviour) HeldItems() (mainHand, offHand item.Stack) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.held, item.Stack{}
+}
+
+// Inventory returns the items that the allay collected and has not yet
+// delivered.
+func (a *AllayBehaviour) Inventory() item.Stack {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.inventory
+}
+
+// Liked returns the player that the allay likes and delivers items to. False
+// is returned if the allay does not like any player.
+func (a *AllayBehaviour) Liked() (world.Entity, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.liked, a.liked != nil
+}
+
+// Dancing checks if the allay is dancing to a jukebox playing nearby.
+func (a *AllayBehaviour) Dancing() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.dancing
+}
+
+// DuplicationCooldown returns the time left until the allay may be
+// duplicated again.
+func (a *AllayBehaviour) DuplicationCooldown() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.duplication
+}
+
+// Hurt makes the allay immune to damage dealt by the player it likes.
+func (a *AllayBehaviour) Hurt(_ *Mob, dmg float64, src world.DamageSource) (float64, bool) {
+	if s, ok := src.(AttackDamageSource); ok {
+		if liked, ok := a.Liked(); ok && s.Attacker == liked {
+			return 0, false
+		}
+	}
+	return dmg, true
+}
+
+// Interact gives the item held by the user to the allay if it does not hold
+// an item yet, or takes the item back if the user has an empty hand. Giving a
+// dancing allay an amethyst shard duplicates it.
+func (a *AllayBehaviour) Interact(m *Mob, user item.User, ctx *item.UseContext) bool {
+	held, _ := user.HeldItems()
+	if _, ok := held.Item().(item.AmethystShard); ok && a.duplicate(m) {
+		ctx.SubtractFromCount(1)
+		return true
+	}
+
+	a.mu.Lock()
+	switch {
+	case a.held.Empty() && !held.Empty():
+		a.held, a.liked = held.Grow(1-held.Count()), user
+		a.mu.Unlock()
+		ctx.SubtractFromCount(1)
+	case !a.held.Empty() && held.Empty():
+		ctx.NewItem = a.held
+		inventory := a.inventory
+		a.held, a.inventory, a.liked = item.Stack{}, item.Stack{}, nil
+		a.mu.Unlock()
+		if !inventory.Empty() {
+			m.World().AddEntity(NewItem(inventory, user.Position()))
+		}
+	default:
+		a.mu.Unlock()
+		return false
+	}
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityItems(m)
+	}
+	return true
+}
+
+// duplicate duplicates the allay if it is dancing and its duplication
+// cooldown has expired. duplicate returns false if the allay could not be
+// duplicated.
+func (a *AllayBehaviour) duplicate(m *Mob) bool {
+	a.mu.Lock()
+	if !a.dancing || a.duplication > 0 {
+		a.mu.Unlock()
+		return false
+	}
+	a.duplication = a.conf.DuplicationCooldown
+	a.mu.Unlock()
+
+	w, pos := m.World(), m.Position()
+	child := NewAllay(pos)
+	child.Behaviour().(*AllayBehaviour).duplication = a.conf.DuplicationCooldown
+	w.AddEntity(child)
+	for _, v := range w.Viewers(pos) {
+		v.ViewEntityAction(m, LoveAction{})
+	}
+	return true
+}
+
+// HandleGameEvent makes the allay remember note blocks played nearby and
+// start or stop dancing when a jukebox nearby starts or stops playing.
+func (a *AllayBehaviour) HandleGameEvent(m *Mob, e world.GameEvent, pos mgl64.Vec3, _ world.Entity) {
+	a.mu.Lock()
+	changed := false
+	switch e {
+	case world.GameEventNoteBlockPlay:
+		a.noteBlock, a.noteHeard = pos, a.conf.NoteBlockDuration
+	case world.GameEventJukeboxPlay:
+		if pos.Sub(m.Position()).Len() <= 10 {
+			changed = !a.dancing
+			a.jukebox, a.dancing = pos, true
+		}
+	case world.GameEventJukeboxStopPlay:
+		if a.dancing && a.jukebox == pos {
+			a.dancing, changed = false, true
+		}
+	}
+	a.mu.Unlock()
+	if changed {
+		m.updateState()
+	}
+}
+
+// Tick ticks the allay, making it collect items that match the item it
+// holds and deliver them, or follow the player it likes.
+func (a *AllayBehaviour) Tick(m *Mob) {
+	a.mu.Lock()
+	a.noteHeard = max(a.noteHeard-time.Second/20, 0)
+	a.duplication = max(a.duplication-time.Second/20, 0)
+	a.pickup = max(a.pickup-time.Second/20, 0)
+	a.mu.Unlock()
+
+	if m.Age()%time.Second == 0 {
+		a.checkJukebox(m)
+	}
+	switch {
+	case a.tickCollect(m):
+	case a.tickDeliver(m):
+	case a.tickFollow(m):
+	default:
+		a.tickWander(m)
+	}
+}
+
+// checkJukebox stops the allay from dancing if the jukebox it is dancing to
+// stopped playing or if the allay moved too far away from it.
+func (a *AllayBehaviour) checkJukebox(m *Mob) {
+	a.mu.Lock()
+	dancing, jukebox := a.dancing, a.jukebox
+	a.mu.Unlock()
+	if !dancing {
+		return
+	}
+	j, ok := m.World().Block(cube.PosFromVec3(jukebox)).(block.Jukebox)
+	if ok {
+		_, ok = j.Disc()
+	}
+	if ok && jukebox.Sub(m.Position()).Len() <= 10 {
+		return
+	}
+	a.mu.Lock()
+	a.dancing = false
+	a.mu.Unlock()
+	m.updateState()
+}
+
+// tickCollect makes the allay fly towards the nearest item that matches the
+// item it is holding and pick it up. tickCollect returns false if the allay
+// cannot collect any items.
+func (a *AllayBehaviour) tickCollect(m *Mob) bool {
+	a.mu.Lock()
+	held, inventory, pickup := a.held, a.inventory, a.pickup
+	a.mu.Unlock()
+	if held.Empty() || pickup > 0 || (!inventory.Empty() && inventory.Count() >= inventory.MaxCount()) {
+		return false
+	}
+	target, ok := nearestEntity(m, a.conf.CollectRange, func(e world.Entity) bool {
+		i, ok := e.(*Ent)
+		if !ok {
+			return false
+		}
+		b, ok := i.Behaviour().(*ItemBehaviour)
+		return ok && b.Item().Comparable(held)
+	})
+	if !ok {
+		return false
+	}
+	if target.Position().Sub(m.Position()).Len() > 1 {
+		m.FlyTowards(target.Position(), 1)
+		return true
+	}
+	i := target.(*Ent)
+	stack := i.Behaviour().(*ItemBehaviour).Item()
+
+	a.mu.Lock()
+	if a.inventory.Empty() {
+		a.inventory = stack.Grow(-stack.Count())
+	}
+	n := min(stack.Count(), a.inventory.MaxCount()-a.inventory.Count())
+	a.inventory = a.inventory.Grow(n)
+	a.mu.Unlock()
+
+	w, pos := m.World(), i.Position()
+	_ = i.Close()
+	if n < stack.Count() {
+		w.AddEntity(NewItem(stack.Grow(-n), pos))
+	}
+	return true
+}
+
+// tickDeliver makes the allay fly towards the note block it heard or the
+// player it likes and throw the items it collected at it. tickDeliver returns
+// false if the allay has nothing to deliver or nowhere to deliver it to.
+func (a *AllayBehaviour) tickDeliver(m *Mob) bool {
+	a.mu.Lock()
+	inventory := a.inventory
+	a.mu.Unlock()
+	if inventory.Empty() {
+		return false
+	}
+	dst, ok := a.deliveryTarget(m)
+	if !ok {
+		return false
+	}
+	pos := m.Position()
+	if dst.Sub(pos).Len() > 2.5 {
+		m.FlyTowards(dst, 1)
+		return true
+	}
+	m.SetVelocity(mgl64.Vec3{})
+	m.LookAt(dst)
+
+	a.mu.Lock()
+	a.inventory, a.pickup = item.Stack{}, time.Second*3
+	a.mu.Unlock()
+
+	it := NewItem(inventory, pos)
+	it.SetVelocity(dst.Sub(pos).Normalize().Mul(0.3))
+	m.World().AddEntity(it)
+	return true
+}
+
+// deliveryTarget returns the position that the allay delivers its items to.
+// Note blocks heard recently take priority over the player that the allay
+// likes.
+func (a *AllayBehaviour) deliveryTarget(m *Mob) (mgl64.Vec3, bool) {
+	a.mu.Lock()
+	noteBlock, heard, liked := a.noteBlock, a.noteHeard > 0, a.liked
+	a.mu.Unlock()
+
+	pos := m.Position()
+	if heard && noteBlock.Sub(pos).Len() <= a.conf.DeliverRange {
+		return noteBlock.Add(mgl64.Vec3{0, 1, 0}), true
+	}
+	if !a.likedNearby(m, liked) {
+		return mgl64.Vec3{}, false
+	}
+	return EyePosition(liked), true
+}
+
+// tickFollow makes the allay follow the player it likes, staying a few
+// blocks away from it. tickFollow returns false if the allay does not like a
+// player nearby.
+func (a *AllayBehaviour) tickFollow(m *Mob) bool {
+	a.mu.Lock()
+	liked := a.liked
+	a.mu.Unlock()
+	if !a.likedNearby(m, liked) {
+		return false
+	}
+	dst := EyePosition(liked)
+	if dst.Sub(m.Position()).Len() > 4 {
+		m.FlyTowards(dst, 1)
+	} else {
+		m.SetVelocity(m.Velocity().Mul(0.8))
+		m.LookAt(dst)
+	}
+	return true
+}
+
+// likedNearby checks if the player liked by the allay is alive, in the same
+// world and within delivery range of the allay.
+func (a *AllayBehaviour) likedNearby(m *Mob, liked world.Entity) bool {
+	if liked == nil || liked.World() != m.World() {
+		return false
+	}
+	if l, ok := liked.(Living); ok && l.Dead() {
+		return false
+	}
+	return liked.Position().Sub(m.Position()).Len() <= a.conf.DeliverRange
+}
+
+// tickWander makes the allay float around randomly.
+func (a *AllayBehaviour) tickWander(m *Mob) {
+	pos := m.Position()
+
+	a.mu.Lock()
+	if a.wander == (mgl64.Vec3{}) || a.wander.Sub(pos).Len() < 1 || rand.Intn(200) == 0 {
+		a.wander = pos.Add(mgl64.Vec3{rand.Float64()*16 - 8, rand.Float64()*4 - 2, rand.Float64()*16 - 8})
+	}
+	dst := a.wander
+	a.mu.Unlock()
+
+	if w := m.World(); len(w.Block(cube.PosFromVec3(dst)).Model().BBox(cube.PosFromVec3(dst), w)) > 0 {
+		// Don't fly into solid blocks.
+		a.mu.Lock()
+		a.wander = mgl64.Vec3{}
+		a.mu.Unlock()
+		return
+	}
+	m.FlyTowards(dst, 0.5)
+}
@@ -0,0 +1,315 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// AnimalBehaviourConfig holds optional parameters for an AnimalBehaviour.
+type AnimalBehaviourConfig struct {
+	// BreedingItem checks if the item passed may be fed to the animal. Adult
+	// animals fed with a breeding item enter love mode, while babies fed with
+	// one grow up faster. If nil, the animal cannot be bred.
+	BreedingItem func(it world.Item) bool
+	// Offspring creates a new baby animal at the position passed. It is
+	// called when two animals in love mode breed. If nil, the animal cannot
+	// be bred.
+	Offspring func(pos mgl64.Vec3) *Mob
+	// GrowthDuration is the time it takes for a baby animal to grow into an
+	// adult. If 0, a duration of 20 minutes is used.
+	GrowthDuration time.Duration
+	// LoveDuration is the time an animal remains in love mode after being fed.
+	// If 0, a duration of 30 seconds is used.
+	LoveDuration time.Duration
+	// BreedingCooldown is the time after breeding during which the parents
+	// cannot enter love mode again. If 0, a duration of 5 minutes is used.
+	BreedingCooldown time.Duration
+	// Tick is called for every tick that the animal is alive, after the
+	// AnimalBehaviour has ticked.
+	Tick func(m *Mob)
+}
+
+// New creates an AnimalBehaviour using the parameters in conf. If baby is
+// true, the animal starts as a baby that grows up over time.
+func (conf AnimalBehaviourConfig) New(baby bool) *AnimalBehaviour {
+	if conf.GrowthDuration == 0 {
+		conf.GrowthDuration = time.Minute * 20
+	}
+	if conf.LoveDuration == 0 {
+		conf.LoveDuration = time.Second * 30
+	}
+	if conf.BreedingCooldown == 0 {
+		conf.BreedingCooldown = time.Minute * 5
+	}
+	a := &AnimalBehaviour{conf: conf}
+	if baby {
+		a.growth = conf.GrowthDuration
+	}
+	return a
+}
+
+// AnimalBehaviour implements the behaviour of passive animals. They wander
+// around, follow players holding their breeding item and may be bred by
+// feeding them, producing baby animals that grow up over time.
+type AnimalBehaviour struct {
+	conf AnimalBehaviourConfig
+
+	mu       sync.Mutex
+	growth   time.Duration
+	love     time.Duration
+	cooldown time.Duration
+	target   mgl64.Vec3
+	walking  bool
+}
+
+// Baby checks if the animal is currently a baby.
+func (a *AnimalBehaviour) Baby() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.growth > 0
+}
+
+// Scale returns the scale of the animal. Babies are half the size of adults.
+func (a *AnimalBehaviour) Scale() float64 {
+	if a.Baby() {
+		return 0.5
+	}
+	return 1
+}
+
+// GrowthLeft returns the time left until the baby animal grows into an adult.
+// GrowthLeft returns 0 for adult animals.
+func (a *AnimalBehaviour) GrowthLeft() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.growth
+}
+
+// InLove checks if the animal is currently in love mode, meaning it is
+// looking for a partner to breed with.
+func (a *AnimalBehaviour) InLove() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.love > 0
+}
+
+// BreedingCooldown returns the time left until the animal may enter love
+// mode again.
+func (a *AnimalBehaviour) BreedingCooldown() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cooldown
+}
+
+// Interact feeds the item held by the user to the animal if it is a breeding
+// item. Adults enter love mode and babies grow up by 10% of the growth
+// duration.
+func (a *AnimalBehaviour) Interact(m *Mob, user item.User, ctx *item.UseContext) bool {
+	held, _ := user.HeldItems()
+	if held.Empty() || a.conf.BreedingItem == nil || !a.conf.BreedingItem(held.Item()) {
+		return false
+	}
+	a.mu.Lock()
+	if a.growth > 0 {
+		a.growth -= a.conf.GrowthDuration / 10
+		grown := a.growth <= 0
+		a.growth = max(a.growth, 0)
+		a.mu.Unlock()
+
+		ctx.SubtractFromCount(1)
+		if grown {
+			m.updateState()
+		}
+		return true
+	}
+	if a.love > 0 || a.cooldown > 0 || a.conf.Offspring == nil {
+		a.mu.Unlock()
+		return false
+	}
+	a.love = a.conf.LoveDuration
+	a.mu.Unlock()
+
+	ctx.SubtractFromCount(1)
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityAction(m, LoveAction{})
+	}
+	return true
+}
+
+// Tick ticks the animal, progressing its growth and love mode and moving it
+// towards its current target.
+func (a *AnimalBehaviour) Tick(m *Mob) {
+	a.mu.Lock()
+	wasBaby := a.growth > 0
+	a.growth = max(a.growth-time.Second/20, 0)
+	a.love = max(a.love-time.Second/20, 0)
+	a.cooldown = max(a.cooldown-time.Second/20, 0)
+	grown, inLove := wasBaby && a.growth == 0, a.love > 0
+	a.mu.Unlock()
+
+	if grown {
+		m.updateState()
+	}
+	switch {
+	case inLove && a.tickLove(m):
+	case a.tickTempt(m):
+	default:
+		a.tickWander(m)
+	}
+	if a.conf.Tick != nil {
+		a.conf.Tick(m)
+	}
+}
+
+// tickLove moves the animal towards a nearby partner in love mode and breeds
+// with it once close enough. tickLove returns false if no partner was found.
+func (a *AnimalBehaviour) tickLove(m *Mob) bool {
+	pos := m.Position()
+	partner, ok := nearestEntity(m, 8, func(e world.Entity) bool {
+		other, ok := e.(*Mob)
+		if !ok || other.Type() != m.Type() || other.Dead() {
+			return false
+		}
+		b, ok := other.Behaviour().(*AnimalBehaviour)
+		return ok && !b.Baby() && b.InLove()
+	})
+	if !ok {
+		return false
+	}
+	other := partner.(*Mob)
+	if partner.Position().Sub(pos).Len() > 1.5 {
+		m.MoveTowards(partner.Position(), 1)
+		return true
+	}
+	a.breed(m, other)
+	return true
+}
+
+// breed breeds the animal with the partner passed, spawning a baby animal
+// and experience orbs between them and resetting love mode of both parents.
+func (a *AnimalBehaviour) breed(m, partner *Mob) {
+	b := partner.Behaviour().(*AnimalBehaviour)
+	for _, parent := range []*AnimalBehaviour{a, b} {
+		parent.mu.Lock()
+		parent.love, parent.cooldown = 0, parent.conf.BreedingCooldown
+		parent.mu.Unlock()
+	}
+
+	w, pos := m.World(), m.Position().Add(partner.Position()).Mul(0.5)
+	w.AddEntity(a.conf.Offspring(pos))
+	for _, orb := range NewExperienceOrbs(pos, rand.Intn(7)+1) {
+		w.AddEntity(orb)
+	}
+}
+
+// tickTempt makes the animal follow the nearest player holding its breeding
+// item. tickTempt returns false if no such player was found.
+func (a *AnimalBehaviour) tickTempt(m *Mob) bool {
+	if a.conf.BreedingItem == nil {
+		return false
+	}
+	tempter, ok := nearestEntity(m, 10, func(e world.Entity) bool {
+		u, ok := e.(item.User)
+		if !ok {
+			return false
+		}
+		held, _ := u.HeldItems()
+		return !held.Empty() && a.conf.BreedingItem(held.Item())
+	})
+	if !ok {
+		return false
+	}
+	m.LookAt(EyePosition(tempter))
+	if tempter.Position().Sub(m.Position()).Len() > 2.5 {
+		m.MoveTowards(tempter.Position(), 1)
+	}
+	return true
+}
+
+// tickWander makes the animal wander around randomly, occasionally picking a
+// new position nearby to walk to.
+func (a *AnimalBehaviour) tickWander(m *Mob) {
+	pos := m.Position()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.walking {
+		if rand.Intn(120) == 0 {
+			a.target = pos.Add(mgl64.Vec3{rand.Float64()*20 - 10, 0, rand.Float64()*20 - 10})
+			a.walking = true
+		}
+		return
+	}
+	diff := a.target.Sub(pos)
+	if math.Hypot(diff[0], diff[2]) < 1 || rand.Intn(200) == 0 {
+		a.walking = false
+		return
+	}
+	m.MoveTowards(a.target, 0.8)
+}
+
+// decodeAnimalNBT decodes the NBT properties of an animal into the
+// AnimalBehaviour of the Mob passed. A negative "Age" represents the growth
+// left for a baby animal in ticks, similarly to vanilla.
+func decodeAnimalNBT(m *Mob, data map[string]any) *Mob {
+	a := m.Behaviour().(*AnimalBehaviour)
+	if age := nbtconv.Int32(data, "Age"); age < 0 {
+		a.growth = time.Duration(-age) * time.Second / 20
+	} else {
+		a.cooldown = time.Duration(age) * time.Second / 20
+	}
+	a.love = nbtconv.TickDuration[int32](data, "InLove")
+	return decodeMobNBT(m, data)
+}
+
+// encodeAnimalNBT encodes the Mob passed, which must have an AnimalBehaviour,
+// into an NBT map.
+func encodeAnimalNBT(m *Mob) map[string]any {
+	a := m.Behaviour().(*AnimalBehaviour)
+	data := encodeMobNBT(m)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.growth > 0 {
+		data["Age"] = -int32(a.growth / (time.Second / 20))
+	} else {
+		data["Age"] = int32(a.cooldown / (time.Second / 20))
+	}
+	data["InLove"] = int32(a.love / (time.Second / 20))
+	return data
+}
+
+// nearestEntity finds the entity nearest to the Mob passed within the radius
+// passed for which the filter function returns true.
+func nearestEntity(m *Mob, radius float64, filter func(e world.Entity) bool) (world.Entity, bool) {
+	pos := m.Position()
+	var (
+		nearest world.Entity
+		dist    = math.MaxFloat64
+	)
+	for _, e := range m.World().EntitiesWithin(cube.Box(-radius, -radius, -radius, radius, radius, radius).Translate(pos), func(e world.Entity) bool {
+		return e == m || !filter(e)
+	}) {
+		if d := e.Position().Sub(pos).Len(); d < dist {
+			nearest, dist = e, d
+		}
+	}
+	return nearest, nearest != nil
+}
+
+// scaledBBox scales the cube.BBox passed by the scale of the entity passed,
+// so that entities such as baby animals have smaller bounding boxes.
+func scaledBBox(e world.Entity, box cube.BBox) cube.BBox {
+	if m, ok := e.(*Mob); ok {
+		if s, ok := m.Behaviour().(interface{ Scale() float64 }); ok {
+			return box.Mul(s.Scale())
+		}
+	}
+	return box
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Attackable represents an entity that is not Living, but that may still be
+// attacked by other entities or hit by projectiles, such as an end crystal.
+type Attackable interface {
+	world.Entity
+	// Attack is called when the entity is attacked with the
+	// world.DamageSource passed. Attack returns true if the attack had an
+	// effect on the entity.
+	Attack(src world.DamageSource) bool
+}
@@ -0,0 +1,58 @@
+package attribute
+
+import (
+	"math"
+)
+
+// Attribute is a property of an entity, such as its maximum health or its
+// movement speed. The value of an Attribute is computed from a base value and
+// any number of Modifiers applied to it.
+type Attribute struct {
+	name          string
+	def, min, max float64
+}
+
+var (
+	// MaxHealth is the maximum health of an entity.
+	MaxHealth = Attribute{name: "minecraft:health", def: 20, min: 1, max: 1024}
+	// MovementSpeed is the movement speed of an entity in blocks/tick.
+	MovementSpeed = Attribute{name: "minecraft:movement", def: 0.1, min: 0, max: math.MaxFloat32}
+	// AttackDamage is the damage dealt by an entity with melee attacks.
+	AttackDamage = Attribute{name: "minecraft:attack_damage", def: 1, min: 0, max: 2048}
+	// KnockBackResistance is the resistance of an entity to knock back. A
+	// value of 0 means normal knock back force, while a value of 1 means all
+	// knock back is ignored.
+	KnockBackResistance = Attribute{name: "minecraft:knockback_resistance", def: 0, min: 0, max: 1}
+)
+
+// Name returns the name of the Attribute as it is sent to the client, such as
+// "minecraft:movement".
+func (a Attribute) Name() string {
+	return a.name
+}
+
+// Default returns the default base value of the Attribute.
+func (a Attribute) Default() float64 {
+	return a.def
+}
+
+// Min returns the minimum value of the Attribute.
+func (a Attribute) Min() float64 {
+	return a.min
+}
+
+// Max returns the maximum value of the Attribute.
+func (a Attribute) Max() float64 {
+	return a.max
+}
+
+// Clamp clamps the value passed between the minimum and maximum value of the
+// Attribute.
+func (a Attribute) Clamp(v float64) float64 {
+	return math.Max(a.min, math.Min(a.max, v))
+}
+
+// Attributes returns a list of all Attributes.
+func Attributes() []Attribute {
+	return []Attribute{MaxHealth, MovementSpeed, AttackDamage, KnockBackResistance}
+}
@@ -0,0 +1,129 @@
+package attribute
+
+import (
+	"slices"
+	"sync"
+)
+
+// Manager manages the Attributes of an entity and the Modifiers applied to
+// them. Manager is safe for concurrent use.
+type Manager struct {
+	mu         sync.Mutex
+	attributes map[Attribute]*instance
+}
+
+// instance holds the base value and Modifiers of a single Attribute.
+type instance struct {
+	base      float64
+	modifiers []Modifier
+}
+
+// NewManager returns a new Manager. All Attributes have their default base
+// value and no Modifiers.
+func NewManager() *Manager {
+	return &Manager{attributes: make(map[Attribute]*instance)}
+}
+
+// Base returns the base value of the Attribute passed.
+func (m *Manager) Base(a Attribute) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.instance(a).base
+}
+
+// SetBase sets the base value of the Attribute passed and returns the new
+// value of the Attribute after applying its Modifiers.
+func (m *Manager) SetBase(a Attribute, v float64) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst := m.instance(a)
+	inst.base = v
+	return inst.value(a)
+}
+
+// Value returns the value of the Attribute passed, computed from its base
+// value and all Modifiers applied to it.
+func (m *Manager) Value(a Attribute) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.instance(a).value(a)
+}
+
+// AddModifier adds a Modifier to the Attribute passed, replacing any existing
+// Modifier with the same ID. The new value of the Attribute is returned.
+func (m *Manager) AddModifier(a Attribute, mod Modifier) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst := m.instance(a)
+	if i := slices.IndexFunc(inst.modifiers, func(other Modifier) bool { return other.ID == mod.ID }); i != -1 {
+		inst.modifiers[i] = mod
+	} else {
+		inst.modifiers = append(inst.modifiers, mod)
+	}
+	return inst.value(a)
+}
+
+// RemoveModifier removes the Modifier with the ID passed from the Attribute
+// passed. The new value of the Attribute is returned.
+func (m *Manager) RemoveModifier(a Attribute, id string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst := m.instance(a)
+	inst.modifiers = slices.DeleteFunc(inst.modifiers, func(mod Modifier) bool { return mod.ID == id })
+	return inst.value(a)
+}
+
+// Modifier returns the Modifier with the ID passed applied to the Attribute
+// passed. If no such Modifier exists, false is returned.
+func (m *Manager) Modifier(a Attribute, id string) (Modifier, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mod := range m.instance(a).modifiers {
+		if mod.ID == id {
+			return mod, true
+		}
+	}
+	return Modifier{}, false
+}
+
+// Modifiers returns all Modifiers applied to the Attribute passed.
+func (m *Manager) Modifiers(a Attribute) []Modifier {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.instance(a).modifiers)
+}
+
+// instance returns the instance of the Attribute passed, creating it with the
+// default base value if it does not yet exist. instance must be called with
+// m.mu locked.
+func (m *Manager) instance(a Attribute) *instance {
+	inst, ok := m.attributes[a]
+	if !ok {
+		inst = &instance{base: a.def}
+		m.attributes[a] = inst
+	}
+	return inst
+}
+
+// value computes the value of the Attribute from the base value and the
+// Modifiers of the instance.
+func (inst *instance) value(a Attribute) float64 {
+	v := inst.base
+	for _, mod := range inst.modifiers {
+		if mod.Operation == OperationAdd {
+			v += mod.Amount
+		}
+	}
+	base := v
+	for _, mod := range inst.modifiers {
+		if mod.Operation == OperationMultiplyBase {
+			v += base * mod.Amount
+		}
+	}
+	for _, mod := range inst.modifiers {
+		if mod.Operation == OperationMultiplyTotal {
+			v *= 1 + mod.Amount
+		}
+	}
+	return a.Clamp(v)
+}
@@ -0,0 +1,35 @@
+package attribute
+
+// Operation is the operation a Modifier performs on the value of an
+// Attribute.
+type Operation int
+
+const (
+	// OperationAdd adds the Amount of the Modifier to the base value of the
+	// Attribute.
+	OperationAdd Operation = iota
+	// OperationMultiplyBase adds the base value of the Attribute, multiplied by
+	// the Amount of the Modifier, to the value. Multiple OperationMultiplyBase
+	// Modifiers stack additively.
+	OperationMultiplyBase
+	// OperationMultiplyTotal multiplies the value of the Attribute by 1 plus
+	// the Amount of the Modifier. Multiple OperationMultiplyTotal Modifiers
+	// stack multiplicatively.
+	OperationMultiplyTotal
+)
+
+// Modifier modifies the value of an Attribute, for example as the result of
+// an effect or an item held. Modifiers are applied in order of their
+// Operation: OperationAdd first, then OperationMultiplyBase and finally
+// OperationMultiplyTotal.
+type Modifier struct {
+	// ID uniquely identifies the Modifier within an Attribute, such as
+	// "effect.speed". Adding a Modifier with the same ID as an existing one
+	// replaces it.
+	ID string
+	// Amount is the amount by which the Modifier changes the value of the
+	// Attribute. How it is applied depends on the Operation.
+	Amount float64
+	// Operation is the Operation performed by the Modifier.
+	Operation Operation
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/player/bossbar"
+	"github.com/df-mc/dragonfly/server/world"
+	"math"
+	"sync"
+)
+
+// bossBarViewer is an entity that can be shown a boss bar, such as a player.
+type bossBarViewer interface {
+	world.Entity
+	ShowBossBar(id string, bar bossbar.BossBar)
+	HideBossBar(id string)
+}
+
+// mobBossBar manages the boss bar of a boss Mob, such as the wither or the
+// ender dragon. The boss bar shows the health of the Mob to all players
+// within range. Every mobBossBar is shown with its own ID, so that the boss
+// bars of multiple bosses may be shown at the same time.
+type mobBossBar struct {
+	mu         sync.Mutex
+	viewers    map[bossBarViewer]struct{}
+	lastHealth float64
+}
+
+// tick updates the boss bar of the Mob passed, showing it to players that
+// came into the range passed and removing it for players that left it. The
+// boss bar is resent to all viewers if the health of the Mob changed.
+func (b *mobBossBar) tick(m *Mob, name string, r float64) {
+	pos := m.Position()
+	nearby := map[bossBarViewer]struct{}{}
+	for _, e := range m.World().EntitiesWithin(cube.Box(-r, -r, -r, r, r, r).Translate(pos), nil) {
+		if v, ok := e.(bossBarViewer); ok && e.Position().Sub(pos).Len() <= r {
+			nearby[v] = struct{}{}
+		}
+	}
+	if n := m.NameTag(); n != "" {
+		name = n
+	}
+	health := m.Health()
+	bar := bossbar.New(name).WithHealthPercentage(math.Min(math.Max(health/m.MaxHealth(), 0), 1))
+
+	b.mu.Lock()
+	if b.viewers == nil {
+		b.viewers = map[bossBarViewer]struct{}{}
+	}
+	changed := health != b.lastHealth
+	b.lastHealth = health
+	var add, remove []bossBarViewer
+	for v := range b.viewers {
+		if _, ok := nearby[v]; !ok {
+			remove = append(remove, v)
+			delete(b.viewers, v)
+		}
+	}
+	for v := range nearby {
+		if _, ok := b.viewers[v]; !ok || changed {
+			add = append(add, v)
+			b.viewers[v] = struct{}{}
+		}
+	}
+	b.mu.Unlock()
+
+	for _, v := range remove {
+		v.HideBossBar(b.id())
+	}
+	for _, v := range add {
+		v.ShowBossBar(b.id(), bar)
+	}
+}
+
+// remove removes the boss bar for all players that it is currently shown to.
+func (b *mobBossBar) remove() {
+	b.mu.Lock()
+	viewers := b.viewers
+	b.viewers = nil
+	b.mu.Unlock()
+
+	for v := range viewers {
+		v.HideBossBar(b.id())
+	}
+}
+
+// id returns the ID that the boss bar is shown to players with.
+func (b *mobBossBar) id() string {
+	return fmt.Sprintf("dragonfly:boss:%p", b)
+}
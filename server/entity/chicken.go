@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewChicken creates a new adult chicken at the position passed.
+func NewChicken(pos mgl64.Vec3) *Mob {
+	return newChicken(pos, false)
+}
+
+// NewBabyChicken creates a new baby chicken at the position passed.
+func NewBabyChicken(pos mgl64.Vec3) *Mob {
+	return newChicken(pos, true)
+}
+
+// newChicken creates a new chicken that is either an adult or a baby.
+func newChicken(pos mgl64.Vec3, baby bool) *Mob {
+	conf := chickenConf
+	conf.Behaviour = AnimalBehaviourConfig{
+		BreedingItem: func(it world.Item) bool {
+			switch it.(type) {
+			case block.WheatSeeds, block.BeetrootSeeds, block.MelonSeeds, block.PumpkinSeeds:
+				return true
+			}
+			return false
+		},
+		Offspring: NewBabyChicken,
+		Tick:      tickChicken,
+	}.New(baby)
+	return conf.New(ChickenType{}, pos)
+}
+
+// tickChicken makes chickens fall slowly by flapping their wings.
+func tickChicken(m *Mob) {
+	if vel := m.Velocity(); !m.OnGround() && vel[1] < 0 {
+		vel[1] *= 0.6
+		m.SetVelocity(vel)
+	}
+}
+
+var chickenConf = MobConfig{
+	MaxHealth:  4,
+	Speed:      0.1,
+	Gravity:    0.08,
+	Drag:       0.02,
+	Experience: 3,
+	LootTable:  "entities/chicken",
+	Leashable:  true,
+}
+
+// ChickenType is a world.EntityType implementation for Chicken.
+type ChickenType struct{}
+
+func (ChickenType) EncodeEntity() string { return "minecraft:chicken" }
+func (ChickenType) BBox(e world.Entity) cube.BBox {
+	return scaledBBox(e, cube.Box(-0.2, 0, -0.2, 0.2, 0.7, 0.2))
+}
+
+func (ChickenType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeAnimalNBT(newChicken(nbtconv.Vec3(m, "Pos"), false), m)
+}
+
+func (ChickenType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeAnimalNBT(e.(*Mob))
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+)
+
+// crowdPushForce is the horizontal velocity added to an entity for every
+// entity overlapping with it.
+const crowdPushForce = 0.05
+
+// pushable checks if the world.Entity passed pushes and is pushed by other
+// entities when their bounding boxes overlap.
+func pushable(e world.Entity) bool {
+	l, ok := e.(Living)
+	if !ok || l.Dead() {
+		return false
+	}
+	if g, ok := e.(interface{ GameMode() world.GameMode }); ok && !g.GameMode().HasCollision() {
+		return false
+	}
+	if r, ok := e.(Rider); ok {
+		if _, riding := r.Riding(); riding {
+			return false
+		}
+	}
+	return true
+}
+
+// crowdPush returns the velocity that pushes the world.Entity passed away from
+// all pushable entities that its bounding box overlaps with, along with the
+// number of those entities.
+func crowdPush(e world.Entity, w *world.World) (mgl64.Vec3, int) {
+	pos := e.Position()
+	box := e.Type().BBox(e).Translate(pos)
+	overlapping := w.TickedEntitiesWithin(box.Grow(2), func(other world.Entity) bool {
+		return other == e || !pushable(other) || !other.Type().BBox(other).Translate(other.Position()).IntersectsWith(box)
+	})
+
+	var push mgl64.Vec3
+	for _, other := range overlapping {
+		dx, dz := pos[0]-other.Position()[0], pos[2]-other.Position()[2]
+		dist := math.Max(math.Abs(dx), math.Abs(dz))
+		if dist < 0.01 {
+			continue
+		}
+		dist = math.Sqrt(dist)
+		force := math.Min(1/dist, 1) * crowdPushForce / dist
+		push = push.Add(mgl64.Vec3{dx * force, 0, dz * force})
+	}
+	return push, len(overlapping)
+}
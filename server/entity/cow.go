@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewCow creates a new adult cow at the position passed.
+func NewCow(pos mgl64.Vec3) *Mob {
+	return newCow(pos, false)
+}
+
+// NewBabyCow creates a new baby cow at the position passed.
+func NewBabyCow(pos mgl64.Vec3) *Mob {
+	return newCow(pos, true)
+}
+
+// newCow creates a new cow that is either an adult or a baby.
+func newCow(pos mgl64.Vec3, baby bool) *Mob {
+	conf := cowConf
+	conf.Behaviour = AnimalBehaviourConfig{
+		BreedingItem: func(it world.Item) bool {
+			_, ok := it.(item.Wheat)
+			return ok
+		},
+		Offspring: NewBabyCow,
+	}.New(baby)
+	return conf.New(CowType{}, pos)
+}
+
+var cowConf = MobConfig{
+	MaxHealth:  10,
+	Speed:      0.1,
+	Gravity:    0.08,
+	Drag:       0.02,
+	Experience: 3,
+	LootTable:  "entities/cow",
+	Leashable:  true,
+}
+
+// CowType is a world.EntityType implementation for Cow.
+type CowType struct{}
+
+func (CowType) EncodeEntity() string { return "minecraft:cow" }
+func (CowType) BBox(e world.Entity) cube.BBox {
+	return scaledBBox(e, cube.Box(-0.45, 0, -0.45, 0.45, 1.4, 0.45))
+}
+
+func (CowType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeAnimalNBT(newCow(nbtconv.Vec3(m, "Pos"), false), m)
+}
+
+func (CowType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeAnimalNBT(e.(*Mob))
+}
@@ -46,6 +46,21 @@ type (
 
 	// ExplosionDamageSource is used for damage caused by an explosion.
 	ExplosionDamageSource struct{}
+
+	// CrammingDamageSource is used for damage caused by too many entities
+	// being pushed into the same space.
+	CrammingDamageSource struct{}
+
+	// BorderDamageSource is used for damage caused by being too far outside
+	// of the border of a world.
+	BorderDamageSource struct{}
+
+	// SonicBoomDamageSource is used for damage caused by the sonic boom
+	// attack of a warden. The damage is not reduced by armour.
+	SonicBoomDamageSource struct {
+		// Warden is the warden that performed the sonic boom.
+		Warden world.Entity
+	}
 )
 
 func (FallDamageSource) ReducedByArmour() bool     { return false }
@@ -87,3 +102,12 @@ func (ExplosionDamageSource) AffectedByEnchantment(e item.EnchantmentType) bool
 	_, prot := e.(enchantment.BlastProtection)
 	return prot
 }
+func (CrammingDamageSource) ReducedByResistance() bool  { return false }
+func (CrammingDamageSource) ReducedByArmour() bool      { return false }
+func (CrammingDamageSource) Fire() bool                 { return false }
+func (BorderDamageSource) ReducedByResistance() bool    { return false }
+func (BorderDamageSource) ReducedByArmour() bool        { return false }
+func (BorderDamageSource) Fire() bool                   { return false }
+func (SonicBoomDamageSource) ReducedByResistance() bool { return true }
+func (SonicBoomDamageSource) ReducedByArmour() bool     { return false }
+func (SonicBoomDamageSource) Fire() bool                { return false }
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item/potion"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"time"
+)
+
+// NewDragonFireball creates a dragon fireball entity at a position with an
+// owner entity.
+func NewDragonFireball(pos mgl64.Vec3, owner world.Entity) *Ent {
+	return Config{Behaviour: dragonFireballConf.New(owner)}.New(DragonFireballType{}, pos)
+}
+
+var dragonFireballConf = ProjectileBehaviourConfig{
+	Damage: -1,
+	Hit:    hitDragonFireball,
+}
+
+// hitDragonFireball creates a cloud of dragon's breath at the position where
+// the dragon fireball hit.
+func hitDragonFireball(e *Ent, target trace.Result) {
+	if r, ok := target.(trace.EntityResult); ok {
+		if _, dragon := r.Entity().Type().(EnderDragonType); dragon {
+			// The ender dragon is not affected by its own fireballs.
+			return
+		}
+	}
+	e.World().AddEntity(NewAreaEffectCloudWith(target.Position(), potion.Harming(), time.Second*30, time.Second, 0, 3, 0, 4.0/600))
+}
+
+// DragonFireballType is a world.EntityType implementation for the dragon
+// fireball.
+type DragonFireballType struct{}
+
+func (DragonFireballType) EncodeEntity() string { return "minecraft:dragon_fireball" }
+func (DragonFireballType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.5, 0, -0.5, 0.5, 1, 0.5)
+}
+
+func (DragonFireballType) DecodeNBT(m map[string]any) world.Entity {
+	f := NewDragonFireball(nbtconv.Vec3(m, "Pos"), nil)
+	f.vel = nbtconv.Vec3(m, "Motion")
+	return f
+}
+
+func (DragonFireballType) EncodeNBT(e world.Entity) map[string]any {
+	f := e.(*Ent)
+	return map[string]any{
+		"Pos":    nbtconv.Vec3ToFloat32Slice(f.Position()),
+		"Motion": nbtconv.Vec3ToFloat32Slice(f.Velocity()),
+	}
+}
@@ -132,5 +132,5 @@ func (m *EffectManager) Tick(entity Living) {
 
 // expired checks if an Effect has expired.
 func (m *EffectManager) expired(e effect.Effect) bool {
-	return e.Duration() <= 0
+	return !e.Infinite() && e.Duration() <= 0
 }
@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// BadOmen is a lasting effect that is given to players that kill the captain of a raid. A player with the
+// effect starts a raid when entering a village. Raids are not yet implemented, so BadOmen has no behaviour.
+type BadOmen struct {
+	nopLasting
+}
+
+// RGBA ...
+func (BadOmen) RGBA() color.RGBA {
+	return color.RGBA{R: 0x0b, G: 0x61, B: 0x38, A: 0xff}
+}
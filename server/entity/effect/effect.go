@@ -1,8 +1,10 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
+	"math"
 	"time"
 )
 
@@ -38,12 +40,16 @@ type Type interface {
 // Effect is an effect that can be added to an entity. Effects are either instant (applying the effect only once) or
 // lasting (applying the effect every tick).
 type Effect struct {
-	t                        Type
-	d                        time.Duration
-	lvl                      int
-	ambient, particlesHidden bool
+	t                                  Type
+	d                                  time.Duration
+	lvl                                int
+	ambient, particlesHidden, infinite bool
 }
 
+// InfiniteDuration is the duration of an infinite Effect when it is created. Because the duration of an
+// infinite Effect is still ticked down, Effect.Infinite should be used to check if an Effect is infinite.
+const InfiniteDuration = time.Duration(math.MaxInt64)
+
 // NewInstant returns a new instant Effect using the Type passed. The effect will be applied to an entity once
 // and will expire immediately after.
 func NewInstant(t Type, lvl int) Effect {
@@ -62,6 +68,25 @@ func NewAmbient(t LastingType, lvl int, d time.Duration) Effect {
 	return Effect{t: t, lvl: lvl, d: d, ambient: true}
 }
 
+// NewInfinite creates a new Effect using a LastingType passed that never expires. The effect remains on an entity
+// until it is removed.
+func NewInfinite(t LastingType, lvl int) Effect {
+	return Effect{t: t, lvl: lvl, d: InfiniteDuration, infinite: true}
+}
+
+// WithAmbient returns the same Effect as an ambient effect, as if it was given by a beacon, leading to reduced
+// particles shown to the client.
+func (e Effect) WithAmbient() Effect {
+	e.ambient = true
+	return e
+}
+
+// WithParticles returns the same Effect with particles enabled, undoing a call to WithoutParticles.
+func (e Effect) WithParticles() Effect {
+	e.particlesHidden = false
+	return e
+}
+
 // WithoutParticles returns the same Effect with particles disabled. Adding the effect to players will not display the
 // particles around the player.
 func (e Effect) WithoutParticles() Effect {
@@ -80,13 +105,20 @@ func (e Effect) Level() int {
 }
 
 // Duration returns the leftover duration of the Effect. The duration returned is always 0 if NewInstant was used to
-// create the effect.
+// create the effect. For effects created using NewInfinite, the duration returned has no meaning other than that it
+// decreases every tick.
 func (e Effect) Duration() time.Duration {
 	return e.d
 }
 
+// Infinite returns whether the Effect never expires. True is only returned if the Effect was created using
+// NewInfinite.
+func (e Effect) Infinite() bool {
+	return e.infinite
+}
+
 // Ambient returns whether the Effect is an ambient effect, leading to reduced particles shown to the client. False is
-// always returned if the Effect was created using New or NewInstant.
+// returned if the Effect was created using New or NewInstant, unless WithAmbient was called.
 func (e Effect) Ambient() bool {
 	return e.ambient
 }
@@ -167,3 +199,13 @@ type living interface {
 	// SetSpeed sets the speed of an entity to a new value.
 	SetSpeed(float64)
 }
+
+// attributable represents an entity with attributes that may be modified by effects.
+type attributable interface {
+	world.Entity
+	// AddAttributeModifier adds an attribute.Modifier to an attribute of the entity, replacing any modifier with
+	// the same ID.
+	AddAttributeModifier(a attribute.Attribute, m attribute.Modifier)
+	// RemoveAttributeModifier removes the attribute.Modifier with the ID passed from an attribute of the entity.
+	RemoveAttributeModifier(a attribute.Attribute, id string)
+}
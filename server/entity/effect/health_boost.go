@@ -1,6 +1,7 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
@@ -12,15 +13,19 @@ type HealthBoost struct {
 
 // Start ...
 func (HealthBoost) Start(e world.Entity, lvl int) {
-	if l, ok := e.(living); ok {
-		l.SetMaxHealth(l.MaxHealth() + 4*float64(lvl))
+	if a, ok := e.(attributable); ok {
+		a.AddAttributeModifier(attribute.MaxHealth, attribute.Modifier{
+			ID:        "effect.health_boost",
+			Amount:    4 * float64(lvl),
+			Operation: attribute.OperationAdd,
+		})
 	}
 }
 
 // End ...
-func (HealthBoost) End(e world.Entity, lvl int) {
-	if l, ok := e.(living); ok {
-		l.SetMaxHealth(l.MaxHealth() - 4*float64(lvl))
+func (HealthBoost) End(e world.Entity, _ int) {
+	if a, ok := e.(attributable); ok {
+		a.RemoveAttributeModifier(attribute.MaxHealth, "effect.health_boost")
 	}
 }
 
@@ -0,0 +1,17 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// HeroOfTheVillage is a lasting effect that is given to players that defeat a raid, causing villagers to
+// offer discounts and gifts to the player. Villagers are not yet implemented, so HeroOfTheVillage has no
+// behaviour.
+type HeroOfTheVillage struct {
+	nopLasting
+}
+
+// RGBA ...
+func (HeroOfTheVillage) RGBA() color.RGBA {
+	return color.RGBA{R: 0x44, G: 0xff, B: 0x44, A: 0xff}
+}
@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// Infested is a lasting effect that gives an entity a chance to spawn silverfish when it is hurt.
+// Silverfish are not yet implemented, so Infested has no behaviour.
+type Infested struct {
+	nopLasting
+}
+
+// RGBA ...
+func (Infested) RGBA() color.RGBA {
+	return color.RGBA{R: 0x8c, G: 0x9b, B: 0x8c, A: 0xff}
+}
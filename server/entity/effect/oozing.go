@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// Oozing is a lasting effect that makes an entity spawn slimes when it dies. Slimes are not yet
+// implemented, so Oozing has no behaviour.
+type Oozing struct {
+	nopLasting
+}
+
+// RGBA ...
+func (Oozing) RGBA() color.RGBA {
+	return color.RGBA{R: 0x99, G: 0xff, B: 0xa3, A: 0xff}
+}
@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// RaidOmen is a lasting effect that starts a raid once it expires. Raids are not yet implemented, so
+// RaidOmen has no behaviour.
+type RaidOmen struct {
+	nopLasting
+}
+
+// RGBA ...
+func (RaidOmen) RGBA() color.RGBA {
+	return color.RGBA{R: 0xde, G: 0x40, B: 0x58, A: 0xff}
+}
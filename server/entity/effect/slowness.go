@@ -1,8 +1,10 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
+	"math"
 )
 
 // Slowness is a lasting effect that decreases the movement speed of a living entity by 15% for each level
@@ -13,23 +15,19 @@ type Slowness struct {
 
 // Start ...
 func (Slowness) Start(e world.Entity, lvl int) {
-	slowness := 1 - float64(lvl)*0.15
-	if slowness <= 0 {
-		slowness = 0.00001
-	}
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() * slowness)
+	if a, ok := e.(attributable); ok {
+		a.AddAttributeModifier(attribute.MovementSpeed, attribute.Modifier{
+			ID:        "effect.slowness",
+			Amount:    -math.Min(float64(lvl)*0.15, 1),
+			Operation: attribute.OperationMultiplyTotal,
+		})
 	}
 }
 
 // End ...
-func (Slowness) End(e world.Entity, lvl int) {
-	slowness := 1 - float64(lvl)*0.15
-	if slowness <= 0 {
-		slowness = 0.00001
-	}
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() / slowness)
+func (Slowness) End(e world.Entity, _ int) {
+	if a, ok := e.(attributable); ok {
+		a.RemoveAttributeModifier(attribute.MovementSpeed, "effect.slowness")
 	}
 }
 
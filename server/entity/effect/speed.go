@@ -1,6 +1,7 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
@@ -12,17 +13,19 @@ type Speed struct {
 
 // Start ...
 func (Speed) Start(e world.Entity, lvl int) {
-	speed := 1 + float64(lvl)*0.2
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() * speed)
+	if a, ok := e.(attributable); ok {
+		a.AddAttributeModifier(attribute.MovementSpeed, attribute.Modifier{
+			ID:        "effect.speed",
+			Amount:    float64(lvl) * 0.2,
+			Operation: attribute.OperationMultiplyTotal,
+		})
 	}
 }
 
 // End ...
-func (Speed) End(e world.Entity, lvl int) {
-	speed := 1 + float64(lvl)*0.2
-	if l, ok := e.(living); ok {
-		l.SetSpeed(l.Speed() / speed)
+func (Speed) End(e world.Entity, _ int) {
+	if a, ok := e.(attributable); ok {
+		a.RemoveAttributeModifier(attribute.MovementSpeed, "effect.speed")
 	}
 }
 
@@ -1,6 +1,8 @@
 package effect
 
 import (
+	"github.com/df-mc/dragonfly/server/entity/attribute"
+	"github.com/df-mc/dragonfly/server/world"
 	"image/color"
 )
 
@@ -14,6 +16,24 @@ func (Strength) Multiplier(lvl int) float64 {
 	return 0.3 * float64(lvl)
 }
 
+// Start ...
+func (s Strength) Start(e world.Entity, lvl int) {
+	if a, ok := e.(attributable); ok {
+		a.AddAttributeModifier(attribute.AttackDamage, attribute.Modifier{
+			ID:        "effect.strength",
+			Amount:    s.Multiplier(lvl),
+			Operation: attribute.OperationMultiplyTotal,
+		})
+	}
+}
+
+// End ...
+func (Strength) End(e world.Entity, _ int) {
+	if a, ok := e.(attributable); ok {
+		a.RemoveAttributeModifier(attribute.AttackDamage, "effect.strength")
+	}
+}
+
 // RGBA ...
 func (Strength) RGBA() color.RGBA {
 	return color.RGBA{R: 0x93, G: 0x24, B: 0x23, A: 0xff}
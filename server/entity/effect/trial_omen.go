@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// TrialOmen is a lasting effect that turns trial spawners near the entity into ominous trial spawners.
+// Trial spawners are not yet implemented, so TrialOmen has no behaviour.
+type TrialOmen struct {
+	nopLasting
+}
+
+// RGBA ...
+func (TrialOmen) RGBA() color.RGBA {
+	return color.RGBA{R: 0x16, G: 0xa6, B: 0xa6, A: 0xff}
+}
@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// Weaving is a lasting effect that makes an entity spread cobwebs around it when it dies. The cobwebs are
+// not yet implemented, so Weaving has no behaviour.
+type Weaving struct {
+	nopLasting
+}
+
+// RGBA ...
+func (Weaving) RGBA() color.RGBA {
+	return color.RGBA{R: 0x78, G: 0x69, B: 0x5a, A: 0xff}
+}
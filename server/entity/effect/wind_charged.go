@@ -0,0 +1,16 @@
+package effect
+
+import (
+	"image/color"
+)
+
+// WindCharged is a lasting effect that makes an entity emit a burst of wind when it dies, knocking back
+// entities around it. The burst is not yet implemented, so WindCharged has no behaviour.
+type WindCharged struct {
+	nopLasting
+}
+
+// RGBA ...
+func (WindCharged) RGBA() color.RGBA {
+	return color.RGBA{R: 0xbd, G: 0xc9, B: 0xff, A: 0xff}
+}
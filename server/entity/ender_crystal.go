@@ -0,0 +1,179 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"sync"
+)
+
+// NewEnderCrystal creates a new end crystal at the position passed. If
+// showBase is true, the crystal is displayed on top of a bedrock base.
+func NewEnderCrystal(pos mgl64.Vec3, showBase bool) *EnderCrystal {
+	return &EnderCrystal{pos: pos, showBase: showBase}
+}
+
+// EnderCrystal is an entity found on top of the obsidian pillars in the End.
+// End crystals heal the ender dragon and explode when attacked.
+type EnderCrystal struct {
+	mu          sync.Mutex
+	pos         mgl64.Vec3
+	showBase    bool
+	beamTarget  cube.Pos
+	beam        bool
+	destroyed   bool
+	destroyedBy world.DamageSource
+}
+
+// Type returns EnderCrystalType.
+func (c *EnderCrystal) Type() world.EntityType {
+	return EnderCrystalType{}
+}
+
+// Position returns the current position of the end crystal.
+func (c *EnderCrystal) Position() mgl64.Vec3 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pos
+}
+
+// Rotation always returns an empty rotation, as end crystals cannot rotate.
+func (c *EnderCrystal) Rotation() cube.Rotation {
+	return cube.Rotation{}
+}
+
+// World returns the world of the end crystal.
+func (c *EnderCrystal) World() *world.World {
+	w, _ := world.OfEntity(c)
+	return w
+}
+
+// ShowBase checks if the end crystal is displayed on top of a bedrock base.
+func (c *EnderCrystal) ShowBase() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.showBase
+}
+
+// BeamTarget returns the position that the beam of the end crystal is
+// pointed at. False is returned if the end crystal has no beam.
+func (c *EnderCrystal) BeamTarget() (cube.Pos, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.beamTarget, c.beam
+}
+
+// SetBeamTarget points the beam of the end crystal at the position passed.
+func (c *EnderCrystal) SetBeamTarget(pos cube.Pos) {
+	c.mu.Lock()
+	changed := !c.beam || c.beamTarget != pos
+	c.beamTarget, c.beam = pos, true
+	c.mu.Unlock()
+	if changed {
+		c.updateState()
+	}
+}
+
+// ClearBeamTarget removes the beam of the end crystal.
+func (c *EnderCrystal) ClearBeamTarget() {
+	c.mu.Lock()
+	changed := c.beam
+	c.beam = false
+	c.mu.Unlock()
+	if changed {
+		c.updateState()
+	}
+}
+
+// Destroyed checks if the end crystal was destroyed. If so, the
+// world.DamageSource that destroyed it is returned too.
+func (c *EnderCrystal) Destroyed() (world.DamageSource, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.destroyedBy, c.destroyed
+}
+
+// Attack destroys the end crystal, creating an explosion at its position.
+func (c *EnderCrystal) Attack(src world.DamageSource) bool {
+	c.destroy(src)
+	return true
+}
+
+// Explode destroys the end crystal when it is caught in an explosion.
+func (c *EnderCrystal) Explode(mgl64.Vec3, float64, block.ExplosionConfig) {
+	c.destroy(ExplosionDamageSource{})
+}
+
+// destroy destroys the end crystal, closing it and creating an explosion at
+// its position.
+func (c *EnderCrystal) destroy(src world.DamageSource) {
+	c.mu.Lock()
+	if c.destroyed {
+		c.mu.Unlock()
+		return
+	}
+	c.destroyed, c.destroyedBy = true, src
+	pos := c.pos
+	c.mu.Unlock()
+
+	w := c.World()
+	_ = c.Close()
+	block.ExplosionConfig{Size: 6}.Explode(w, pos)
+}
+
+// Tick keeps a fire burning at the position of the end crystal if it is in
+// the End.
+func (c *EnderCrystal) Tick(w *world.World, _ int64) {
+	if w.Dimension() != world.End {
+		return
+	}
+	pos := cube.PosFromVec3(c.Position())
+	if _, ok := w.Block(pos).(block.Air); ok {
+		w.SetBlock(pos, block.Fire{}, nil)
+	}
+}
+
+// updateState updates the state of the end crystal for all viewers.
+func (c *EnderCrystal) updateState() {
+	w := c.World()
+	for _, v := range w.Viewers(c.Position()) {
+		v.ViewEntityState(c)
+	}
+}
+
+// Close closes the end crystal, removing it from the world.
+func (c *EnderCrystal) Close() error {
+	c.World().RemoveEntity(c)
+	return nil
+}
+
+// EnderCrystalType is a world.EntityType implementation for EnderCrystal.
+type EnderCrystalType struct{}
+
+func (EnderCrystalType) EncodeEntity() string { return "minecraft:ender_crystal" }
+func (EnderCrystalType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-1, 0, -1, 1, 2, 1)
+}
+
+func (EnderCrystalType) DecodeNBT(m map[string]any) world.Entity {
+	c := NewEnderCrystal(nbtconv.Vec3(m, "Pos"), nbtconv.Bool(m, "ShowBottom"))
+	if _, ok := m["BlockTargetX"]; ok {
+		c.beam = true
+		c.beamTarget = cube.Pos{int(nbtconv.Int32(m, "BlockTargetX")), int(nbtconv.Int32(m, "BlockTargetY")), int(nbtconv.Int32(m, "BlockTargetZ"))}
+	}
+	return c
+}
+
+func (EnderCrystalType) EncodeNBT(e world.Entity) map[string]any {
+	c := e.(*EnderCrystal)
+	data := map[string]any{
+		"Pos":        nbtconv.Vec3ToFloat32Slice(c.Position()),
+		"ShowBottom": boolByte(c.ShowBase()),
+	}
+	if target, ok := c.BeamTarget(); ok {
+		data["BlockTargetX"], data["BlockTargetY"], data["BlockTargetZ"] = int32(target[0]), int32(target[1]), int32(target[2])
+	}
+	return data
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewEnderDragon creates a new ender dragon at the position passed. The
+// ender dragon circles around the exit portal at the centre of the End,
+// which is assumed to be at x=0 and z=0.
+func NewEnderDragon(pos mgl64.Vec3) *Mob {
+	conf := enderDragonConf
+	conf.Behaviour = EnderDragonBehaviourConfig{}.New()
+	return conf.New(EnderDragonType{}, pos)
+}
+
+var enderDragonConf = MobConfig{
+	MaxHealth: 200,
+	Speed:     0.6,
+	Drag:      0.1,
+	NoClip:    true,
+}
+
+// EnderDragonType is a world.EntityType implementation for the ender dragon.
+type EnderDragonType struct{}
+
+func (EnderDragonType) EncodeEntity() string { return "minecraft:ender_dragon" }
+func (EnderDragonType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-6.5, 0, -6.5, 6.5, 4, 6.5)
+}
+
+func (EnderDragonType) DecodeNBT(m map[string]any) world.Entity {
+	d := decodeMobNBT(NewEnderDragon(nbtconv.Vec3(m, "Pos")), m)
+	d.Behaviour().(*EnderDragonBehaviour).conf.PreviouslyKilled = nbtconv.Bool(m, "PreviouslyKilled")
+	return d
+}
+
+func (EnderDragonType) EncodeNBT(e world.Entity) map[string]any {
+	d := e.(*Mob)
+	data := encodeMobNBT(d)
+	data["PreviouslyKilled"] = boolByte(d.Behaviour().(*EnderDragonBehaviour).conf.PreviouslyKilled)
+	return data
+}
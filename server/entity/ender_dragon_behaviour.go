@@ -0,0 +1,523 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item/potion"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// EnderDragonBehaviourConfig holds optional parameters for an
+// EnderDragonBehaviour.
+type EnderDragonBehaviourConfig struct {
+	// PreviouslyKilled specifies if an ender dragon was killed in the world
+	// before. The first dragon killed drops 12000 experience and leaves a
+	// dragon egg on top of the exit portal, while other dragons only drop 500
+	// experience.
+	PreviouslyKilled bool
+	// CircleRadius is the radius of the circle that the ender dragon flies
+	// around the exit portal. If 0, a radius of 40 blocks is used.
+	CircleRadius float64
+	// TargetRange is the range within which the ender dragon finds players to
+	// strafe. If 0, a range of 64 blocks is used.
+	TargetRange float64
+	// CrystalRange is the range within which the ender dragon is healed by
+	// end crystals. If 0, a range of 32 blocks is used.
+	CrystalRange float64
+	// BossBarRange is the range within which players are shown the boss bar
+	// of the ender dragon. If 0, a range of 128 blocks is used.
+	BossBarRange float64
+}
+
+// New creates an EnderDragonBehaviour using the parameters in conf.
+func (conf EnderDragonBehaviourConfig) New() *EnderDragonBehaviour {
+	if conf.CircleRadius == 0 {
+		conf.CircleRadius = 40
+	}
+	if conf.TargetRange == 0 {
+		conf.TargetRange = 64
+	}
+	if conf.CrystalRange == 0 {
+		conf.CrystalRange = 32
+	}
+	if conf.BossBarRange == 0 {
+		conf.BossBarRange = 128
+	}
+	return &EnderDragonBehaviour{conf: conf, angle: rand.Float64() * math.Pi * 2, clockwise: rand.Intn(2) == 0}
+}
+
+// dragonPhase is a phase of the AI of the ender dragon.
+type dragonPhase int
+
+const (
+	// dragonPhaseCircling is the phase in which the dragon circles around the
+	// exit portal.
+	dragonPhaseCircling dragonPhase = iota
+	// dragonPhaseStrafing is the phase in which the dragon flies towards a
+	// player and shoots a dragon fireball at it.
+	dragonPhaseStrafing
+	// dragonPhaseLanding is the phase in which the dragon flies down to perch
+	// on top of the exit portal.
+	dragonPhaseLanding
+	// dragonPhasePerched is the phase in which the dragon sits on top of the
+	// exit portal, breathing dragon's breath.
+	dragonPhasePerched
+	// dragonPhaseTakeoff is the phase in which the dragon flies back up from
+	// the exit portal.
+	dragonPhaseTakeoff
+)
+
+// EnderDragonBehaviour implements the behaviour of the ender dragon. The
+// dragon circles around the exit portal in the centre of the End, strafes
+// players with dragon fireballs and occasionally perches on the exit portal.
+// Nearby end crystals heal the dragon, and destroying the crystal that is
+// healing it damages the dragon. Once killed, the dragon rises into the air,
+// drops its experience and creates the exit portal.
+type EnderDragonBehaviour struct {
+	conf EnderDragonBehaviourConfig
+
+	mu           sync.Mutex
+	phase        dragonPhase
+	phaseTicks   int
+	perchDamage  float64
+	angle        float64
+	clockwise    bool
+	fountain     cube.Pos
+	fountainSet  bool
+	strafeTarget Living
+	crystal      *EnderCrystal
+	bossBar      mobBossBar
+}
+
+// Perched checks if the ender dragon is currently perched on top of the exit
+// portal.
+func (d *EnderDragonBehaviour) Perched() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.phase == dragonPhasePerched
+}
+
+// HealingCrystal returns the end crystal currently healing the ender dragon.
+// False is returned if no end crystal is healing the dragon.
+func (d *EnderDragonBehaviour) HealingCrystal() (*EnderCrystal, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.crystal, d.crystal != nil
+}
+
+// Hurt makes the ender dragon immune to fire, drowning, falling and
+// suffocation. Damage taken while perched is tracked so that the dragon
+// takes off after taking too much of it.
+func (d *EnderDragonBehaviour) Hurt(_ *Mob, dmg float64, src world.DamageSource) (float64, bool) {
+	if src.Fire() {
+		return 0, false
+	}
+	switch src.(type) {
+	case DrowningDamageSource, FallDamageSource, SuffocationDamageSource:
+		return 0, false
+	}
+	d.mu.Lock()
+	if d.phase == dragonPhasePerched {
+		d.perchDamage += dmg
+	}
+	d.mu.Unlock()
+	return dmg, true
+}
+
+// Death removes the boss bar of the ender dragon and stops the end crystal
+// healing it.
+func (d *EnderDragonBehaviour) Death(*Mob, world.DamageSource) {
+	d.bossBar.remove()
+	d.clearCrystal()
+}
+
+// Close removes the boss bar of the ender dragon from all players it was
+// shown to.
+func (d *EnderDragonBehaviour) Close(*Mob) {
+	d.bossBar.remove()
+	d.clearCrystal()
+}
+
+// Tick ticks the ender dragon, progressing its current phase, healing it
+// using end crystals and hurting entities that it collides with.
+func (d *EnderDragonBehaviour) Tick(m *Mob) {
+	d.bossBar.tick(m, "Ender Dragon", d.conf.BossBarRange)
+	d.tickCrystal(m)
+	if m.Dead() {
+		return
+	}
+
+	fountain := d.fountainPos(m.World())
+	d.mu.Lock()
+	d.phaseTicks++
+	phase := d.phase
+	d.mu.Unlock()
+
+	switch phase {
+	case dragonPhaseCircling:
+		d.tickCircling(m, fountain)
+	case dragonPhaseStrafing:
+		d.tickStrafing(m)
+	case dragonPhaseLanding:
+		d.tickLanding(m, fountain)
+	case dragonPhasePerched:
+		d.tickPerched(m)
+	case dragonPhaseTakeoff:
+		d.tickTakeoff(m, fountain)
+	}
+	d.tickCollisions(m, phase)
+}
+
+// setPhase switches the ender dragon to the phase passed.
+func (d *EnderDragonBehaviour) setPhase(phase dragonPhase) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.phase, d.phaseTicks, d.perchDamage = phase, 0, 0
+}
+
+// tickCircling makes the ender dragon fly around the exit portal. Every time
+// the dragon reaches a waypoint on its circle, it may start strafing a player
+// or land on the exit portal. The fewer end crystals are left, the more
+// likely the dragon is to land.
+func (d *EnderDragonBehaviour) tickCircling(m *Mob, fountain cube.Pos) {
+	d.mu.Lock()
+	angle := d.angle
+	d.mu.Unlock()
+
+	centre := fountain.Vec3Middle()
+	waypoint := centre.Add(mgl64.Vec3{math.Cos(angle) * d.conf.CircleRadius, 20 + math.Sin(angle*3)*5, math.Sin(angle) * d.conf.CircleRadius})
+	m.FlyTowards(waypoint, 1)
+	if waypoint.Sub(m.Position()).Len() > 10 {
+		return
+	}
+
+	d.mu.Lock()
+	if d.clockwise {
+		d.angle += math.Pi / 6
+	} else {
+		d.angle -= math.Pi / 6
+	}
+	d.mu.Unlock()
+
+	crystals := len(m.World().EntitiesWithin(cube.Box(-1, -1, -1, 1, 1, 1).Mul(d.conf.CircleRadius*2).Translate(centre), func(e world.Entity) bool {
+		_, ok := e.(*EnderCrystal)
+		return !ok
+	}))
+	if rand.Intn(crystals+3) == 0 {
+		d.setPhase(dragonPhaseLanding)
+		return
+	}
+	if rand.Intn(3) != 0 {
+		return
+	}
+	if target, ok := nearestEntity(m, d.conf.TargetRange, hostileTargetable); ok {
+		d.mu.Lock()
+		d.strafeTarget = target.(Living)
+		d.mu.Unlock()
+		d.setPhase(dragonPhaseStrafing)
+	}
+}
+
+// tickStrafing makes the ender dragon fly towards its strafe target and shoot
+// a dragon fireball at it once close enough, after which it returns to
+// circling.
+func (d *EnderDragonBehaviour) tickStrafing(m *Mob) {
+	d.mu.Lock()
+	target, ticks := d.strafeTarget, d.phaseTicks
+	d.mu.Unlock()
+
+	if target == nil || target.Dead() || !hostileTargetable(target) || ticks > 200 {
+		d.setPhase(dragonPhaseCircling)
+		return
+	}
+	pos, dst := m.Position(), target.Position()
+	diff := dst.Sub(pos)
+	if math.Hypot(diff[0], diff[2]) > 40 {
+		m.FlyTowards(dst.Add(mgl64.Vec3{0, 10, 0}), 1)
+		return
+	}
+	head := d.headPosition(m)
+	if dir := EyePosition(target).Sub(head); dir.LenSqr() > 0 {
+		fireball := NewDragonFireball(head, m)
+		fireball.vel = dir.Normalize().Mul(1.5)
+		m.World().AddEntity(fireball)
+	}
+	d.setPhase(dragonPhaseCircling)
+}
+
+// tickLanding makes the ender dragon descend towards the top of the exit
+// portal, perching once it has reached it.
+func (d *EnderDragonBehaviour) tickLanding(m *Mob, fountain cube.Pos) {
+	dst := fountain.Vec3Middle().Add(mgl64.Vec3{0, 0.5, 0})
+	if dst.Sub(m.Position()).Len() < 1.5 {
+		m.SetVelocity(mgl64.Vec3{})
+		d.setPhase(dragonPhasePerched)
+		return
+	}
+	m.FlyTowards(dst, 0.6)
+}
+
+// tickPerched keeps the ender dragon on top of the exit portal, looking at
+// the nearest player and breathing dragon's breath. The dragon takes off
+// after some time or once it has taken too much damage.
+func (d *EnderDragonBehaviour) tickPerched(m *Mob) {
+	d.mu.Lock()
+	ticks, damage := d.phaseTicks, d.perchDamage
+	d.mu.Unlock()
+
+	m.SetVelocity(mgl64.Vec3{})
+	if ticks > 200 || damage >= 25 {
+		d.setPhase(dragonPhaseTakeoff)
+		return
+	}
+	target, ok := nearestEntity(m, 20, hostileTargetable)
+	if !ok {
+		return
+	}
+	m.LookAt(EyePosition(target))
+	if ticks%60 == 40 {
+		// Breathe dragon's breath on the ground in front of the dragon.
+		breath := d.headPosition(m)
+		breath[1] = m.Position()[1]
+		m.World().AddEntity(NewAreaEffectCloudWith(breath, potion.Harming(), time.Second*10, time.Second, 0, 3, 0, 0))
+	}
+}
+
+// tickTakeoff makes the ender dragon fly up and away from the exit portal,
+// after which it resumes circling.
+func (d *EnderDragonBehaviour) tickTakeoff(m *Mob, fountain cube.Pos) {
+	d.mu.Lock()
+	angle := d.angle
+	d.mu.Unlock()
+
+	dst := fountain.Vec3Middle().Add(mgl64.Vec3{math.Cos(angle) * 20, 20, math.Sin(angle) * 20})
+	m.FlyTowards(dst, 0.8)
+	if m.Position()[1] >= float64(fountain.Y())+15 {
+		d.setPhase(dragonPhaseCircling)
+	}
+}
+
+// headPosition returns the position of the head of the ender dragon.
+func (d *EnderDragonBehaviour) headPosition(m *Mob) mgl64.Vec3 {
+	yaw := mgl64.DegToRad(m.Rotation().Yaw())
+	return m.Position().Add(mgl64.Vec3{-math.Sin(yaw) * 6, 2, math.Cos(yaw) * 6})
+}
+
+// tickCollisions hurts entities close to the head of the ender dragon and
+// knocks back entities hit by its wings.
+func (d *EnderDragonBehaviour) tickCollisions(m *Mob, phase dragonPhase) {
+	pos, head := m.Position(), d.headPosition(m)
+	box := m.Type().BBox(m).Translate(pos)
+	for _, e := range m.World().EntitiesWithin(box.Grow(1), func(e world.Entity) bool {
+		l, ok := e.(Living)
+		if g, ok := e.(interface{ GameMode() world.GameMode }); ok && !g.GameMode().AllowsTakingDamage() {
+			return true
+		}
+		return !ok || e == m || l.Dead()
+	}) {
+		l := e.(Living)
+		if e.Position().Sub(head).Len() < 3 {
+			l.Hurt(10, AttackDamageSource{Attacker: m})
+			continue
+		}
+		if phase != dragonPhasePerched && m.Age()%(time.Second/4) == 0 {
+			l.KnockBack(pos, 0.8, 0.4)
+		}
+	}
+}
+
+// hostileTargetable checks if an entity may be targeted by hostile mobs, such
+// as the ender dragon or slimes. Hostile mobs only target living players that
+// can take damage.
+func hostileTargetable(e world.Entity) bool {
+	g, ok := e.(interface{ GameMode() world.GameMode })
+	if !ok || !g.GameMode().AllowsTakingDamage() {
+		return false
+	}
+	l, ok := e.(Living)
+	return ok && !l.Dead()
+}
+
+// tickCrystal heals the ender dragon using the nearest end crystal. If the
+// end crystal healing the dragon is destroyed, the dragon takes damage from
+// the source that destroyed it.
+func (d *EnderDragonBehaviour) tickCrystal(m *Mob) {
+	d.mu.Lock()
+	crystal := d.crystal
+	d.mu.Unlock()
+
+	pos := m.Position()
+	if crystal != nil {
+		if src, destroyed := crystal.Destroyed(); destroyed {
+			d.clearCrystal()
+			if !m.Dead() {
+				m.Hurt(10, src)
+			}
+			return
+		}
+		if m.Dead() || crystal.Position().Sub(pos).Len() > d.conf.CrystalRange {
+			d.clearCrystal()
+			return
+		}
+		if m.Age()%(time.Second/2) == 0 {
+			crystal.SetBeamTarget(cube.PosFromVec3(pos))
+			m.Heal(1, nil)
+		}
+		return
+	}
+	if m.Dead() || m.Age()%(time.Second/2) != 0 {
+		return
+	}
+	nearest, ok := nearestEntity(m, d.conf.CrystalRange, func(e world.Entity) bool {
+		c, ok := e.(*EnderCrystal)
+		if !ok {
+			return false
+		}
+		_, destroyed := c.Destroyed()
+		return !destroyed
+	})
+	if !ok {
+		return
+	}
+	crystal = nearest.(*EnderCrystal)
+	d.mu.Lock()
+	d.crystal = crystal
+	d.mu.Unlock()
+	crystal.SetBeamTarget(cube.PosFromVec3(pos))
+}
+
+// clearCrystal stops the end crystal healing the ender dragon, if any.
+func (d *EnderDragonBehaviour) clearCrystal() {
+	d.mu.Lock()
+	crystal := d.crystal
+	d.crystal = nil
+	d.mu.Unlock()
+	if crystal != nil {
+		crystal.ClearBeamTarget()
+	}
+}
+
+// DeathTick makes the dead ender dragon slowly rise while exploding and
+// dropping its experience. After 10 seconds, the dragon is removed and the
+// exit portal and a new end gateway are created.
+func (d *EnderDragonBehaviour) DeathTick(m *Mob, ticks int) bool {
+	w, pos := m.World(), m.Position()
+	if ticks%5 == 0 {
+		offset := mgl64.Vec3{rand.Float64()*8 - 4, rand.Float64()*4 - 2, rand.Float64()*8 - 4}
+		w.AddParticle(pos.Add(offset).Add(mgl64.Vec3{0, 2, 0}), particle.HugeExplosion{})
+	}
+	m.Teleport(pos.Add(mgl64.Vec3{0, 0.1, 0}))
+
+	experience := 12000
+	if d.conf.PreviouslyKilled {
+		experience = 500
+	}
+	if ticks > 150 && ticks%5 == 0 && ticks < 200 {
+		d.dropExperience(w, pos, int(float64(experience)*0.08))
+	}
+	if ticks < 200 {
+		return false
+	}
+	d.dropExperience(w, pos, int(float64(experience)*0.2))
+	fountain := d.fountainPos(w)
+	createExitPortal(w, fountain, !d.conf.PreviouslyKilled)
+	createEndGateway(w, fountain)
+	return true
+}
+
+// endGatewayCount is the number of end gateways that may be created around the
+// exit portal, one for every time an ender dragon is killed.
+const endGatewayCount = 20
+
+// createEndGateway creates an end gateway at one of the positions on a ring
+// around the exit portal at which no end gateway exists yet. Once end gateways
+// exist at all of these positions, no more end gateways are created.
+func createEndGateway(w *world.World, fountain cube.Pos) {
+	var free []cube.Pos
+	for i := 0; i < endGatewayCount; i++ {
+		angle := 2 * (-math.Pi + math.Pi/endGatewayCount*float64(i))
+		pos := cube.Pos{fountain[0] + int(math.Floor(96*math.Cos(angle))), 75, fountain[2] + int(math.Floor(96*math.Sin(angle)))}
+		if _, ok := w.Block(pos).(block.EndGateway); !ok {
+			free = append(free, pos)
+		}
+	}
+	if len(free) == 0 {
+		return
+	}
+	block.EndGateway{}.Build(free[rand.Intn(len(free))], w)
+}
+
+// dropExperience drops experience orbs worth the amount passed at a position.
+func (d *EnderDragonBehaviour) dropExperience(w *world.World, pos mgl64.Vec3, amount int) {
+	for _, orb := range NewExperienceOrbs(pos, amount) {
+		w.AddEntity(orb)
+	}
+}
+
+// fountainPos returns the position of the exit portal in the world passed.
+// The position is found once by looking for the highest block at x=0 and
+// z=0, skipping the bedrock pillar of an exit portal that already exists.
+func (d *EnderDragonBehaviour) fountainPos(w *world.World) cube.Pos {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fountainSet {
+		return d.fountain
+	}
+	y := w.HighestBlock(0, 0)
+	if y <= w.Range()[0] {
+		d.fountain, d.fountainSet = cube.Pos{0, 64, 0}, true
+		return d.fountain
+	}
+	pillar := false
+	for ; y > w.Range()[0]; y-- {
+		switch w.Block(cube.Pos{0, y, 0}).(type) {
+		case block.Bedrock, block.DragonEgg:
+			pillar = true
+			continue
+		}
+		break
+	}
+	if pillar {
+		// The pillar extends one block below the portal itself.
+		y++
+	}
+	d.fountain, d.fountainSet = cube.Pos{0, y + 1, 0}, true
+	return d.fountain
+}
+
+// createExitPortal creates the exit portal with its bedrock frame and central
+// pillar at the position passed, which is the centre of the portal layer. If
+// egg is true, a dragon egg is placed on top of the pillar.
+func createExitPortal(w *world.World, pos cube.Pos, egg bool) {
+	for x := -4; x <= 4; x++ {
+		for z := -4; z <= 4; z++ {
+			dist := math.Sqrt(float64(x*x + z*z))
+			if dist > 3.5 {
+				continue
+			}
+			below, layer := pos.Add(cube.Pos{x, -1, z}), pos.Add(cube.Pos{x, 0, z})
+			if dist <= 2.5 {
+				w.SetBlock(below, block.Bedrock{}, nil)
+				w.SetBlock(layer, block.EndPortal{}, nil)
+			} else {
+				w.SetBlock(below, block.EndStone{}, nil)
+				w.SetBlock(layer, block.Bedrock{}, nil)
+			}
+		}
+	}
+	for y := 0; y < 4; y++ {
+		w.SetBlock(pos.Add(cube.Pos{0, y, 0}), block.Bedrock{}, nil)
+	}
+	for _, face := range cube.HorizontalFaces() {
+		w.SetBlock(pos.Add(cube.Pos{0, 2, 0}).Side(face), block.Torch{Facing: face.Opposite(), Type: block.NormalFire()}, nil)
+	}
+	if egg {
+		w.SetBlock(pos.Add(cube.Pos{0, 4, 0}), block.DragonEgg{}, nil)
+	}
+}
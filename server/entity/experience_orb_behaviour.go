@@ -5,6 +5,7 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -31,7 +32,10 @@ func (conf ExperienceOrbBehaviourConfig) New() *ExperienceOrbBehaviour {
 	if conf.ExistenceDuration == 0 {
 		conf.ExistenceDuration = time.Minute * 5
 	}
-	b := &ExperienceOrbBehaviour{conf: conf, lastSearch: time.Now()}
+	// Offset the first search by a random duration so that orbs spawned in the
+	// same tick, such as those dropped by mob farms, do not all search for
+	// targets in the same tick.
+	b := &ExperienceOrbBehaviour{conf: conf, lastSearch: time.Now().Add(-time.Duration(rand.Int63n(int64(searchInterval))))}
 	b.passive = PassiveBehaviourConfig{
 		Gravity:           conf.Gravity,
 		Drag:              conf.Drag,
@@ -61,9 +65,17 @@ func (exp *ExperienceOrbBehaviour) Tick(e *Ent) *Movement {
 	return exp.passive.Tick(e)
 }
 
+// searchInterval is the interval at which experience orbs search for nearby
+// collectors and orbs to merge with.
+const searchInterval = time.Second
+
 // followBox is the bounding box used to search for collectors to follow for experience orbs.
 var followBox = cube.Box(-8, -8, -8, 8, 8, 8)
 
+// mergeDistance is the maximum distance between two experience orbs for them
+// to merge into a single orb.
+const mergeDistance = 1.5
+
 // tick finds a target for the experience orb and moves the orb towards it.
 func (exp *ExperienceOrbBehaviour) tick(e *Ent) {
 	w, pos := e.World(), e.Position()
@@ -71,26 +83,77 @@ func (exp *ExperienceOrbBehaviour) tick(e *Ent) {
 		exp.target = nil
 	}
 
-	if time.Since(exp.lastSearch) >= time.Second {
-		exp.findTarget(w, pos)
+	if time.Since(exp.lastSearch) >= searchInterval {
+		if exp.searchNearby(e, w, pos) {
+			// The orb merged into another orb and was closed.
+			return
+		}
 	}
 	if exp.target != nil {
 		exp.moveToTarget(e)
 	}
 }
 
-// findTarget attempts to find a target for an experience orb in w around pos.
-func (exp *ExperienceOrbBehaviour) findTarget(w *world.World, pos mgl64.Vec3) {
-	if exp.target == nil {
-		collectors := w.EntitiesWithin(followBox.Translate(pos), func(o world.Entity) bool {
-			_, ok := o.(experienceCollector)
-			return !ok
-		})
-		if len(collectors) > 0 {
-			exp.target = collectors[0].(experienceCollector)
+// searchNearby searches the area around the experience orb in a single pass,
+// finding the nearest collector to follow if the orb has no target yet and
+// merging the orb with nearby orbs. True is returned if the orb was merged
+// and closed.
+func (exp *ExperienceOrbBehaviour) searchNearby(e *Ent, w *world.World, pos mgl64.Vec3) bool {
+	exp.lastSearch = time.Now()
+
+	var (
+		nearest   = math.MaxFloat64
+		collector experienceCollector
+		orbs      []*Ent
+	)
+	for _, other := range w.EntitiesWithin(followBox.Translate(pos), func(o world.Entity) bool { return o == e }) {
+		dist := other.Position().Sub(pos).Len()
+		if c, ok := other.(experienceCollector); ok {
+			if dist < nearest && !c.Dead() && collects(c) {
+				collector, nearest = c, dist
+			}
+			continue
+		}
+		if o, ok := other.(*Ent); ok && dist <= mergeDistance {
+			if _, ok := o.Type().(ExperienceOrbType); ok {
+				orbs = append(orbs, o)
+			}
 		}
 	}
-	exp.lastSearch = time.Now()
+	if exp.target == nil && collector != nil {
+		exp.target = collector
+	}
+	if exp.target != nil || len(orbs) == 0 {
+		// Orbs that are being attracted by a collector are not merged, as they
+		// are about to be collected anyway.
+		return false
+	}
+	return exp.merge(e, w, orbs)
+}
+
+// merge merges the experience orb with the orbs passed, closing all of them
+// and adding a single orb holding their combined experience. Orbs that are
+// following a collector are not merged.
+func (exp *ExperienceOrbBehaviour) merge(e *Ent, w *world.World, orbs []*Ent) bool {
+	xp, merged := exp.conf.Experience, 0
+	for _, o := range orbs {
+		b := o.Behaviour().(*ExperienceOrbBehaviour)
+		if b.target != nil || b.conf.Experience > math.MaxInt32-xp {
+			continue
+		}
+		xp += b.conf.Experience
+		merged++
+		_ = o.Close()
+	}
+	if merged == 0 {
+		return false
+	}
+	orb := NewExperienceOrb(e.Position(), xp)
+	orb.SetVelocity(e.Velocity())
+	orb.age = e.Age()
+	w.AddEntity(orb)
+	_ = e.Close()
+	return true
 }
 
 // moveToTarget applies velocity to the experience orb so that it moves towards
@@ -119,3 +182,11 @@ type experienceCollector interface {
 	// 100ms delay between experience collection.
 	CollectExperience(value int) bool
 }
+
+// collects checks if an experienceCollector is able to collect experience orbs.
+// Players in a game mode that does not allow interaction, such as spectator
+// mode, do not attract experience orbs.
+func collects(c experienceCollector) bool {
+	g, ok := c.(interface{ GameMode() world.GameMode })
+	return !ok || g.GameMode().AllowsInteraction()
+}
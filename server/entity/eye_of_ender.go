@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// NewEyeOfEnder creates an eye of ender entity at the position passed that
+// flies towards the target passed. If the target is far away, the eye flies
+// only part of the way, so that it shows the direction of the target. After
+// its flight, the eye either drops as an item or shatters.
+func NewEyeOfEnder(pos, target mgl64.Vec3) *Ent {
+	diff := mgl64.Vec3{target[0] - pos[0], 0, target[2] - pos[2]}
+	if diff.Len() > eyeOfEnderRange {
+		diff = diff.Normalize().Mul(eyeOfEnderRange)
+		target = mgl64.Vec3{pos[0] + diff[0], pos[1] + 8, pos[2] + diff[2]}
+	}
+	b := &EyeOfEnderBehaviour{target: target, survives: rand.Float64() < 0.8}
+	b.passive = PassiveBehaviourConfig{
+		ExistenceDuration: time.Second * 4,
+		Expire:            b.expire,
+		Tick:              b.tick,
+	}.New()
+	return Config{Behaviour: b}.New(EyeOfEnderType{}, pos)
+}
+
+// eyeOfEnderRange is the maximum horizontal distance that an eye of ender
+// flies.
+const eyeOfEnderRange = 12
+
+// EyeOfEnderBehaviour implements the behaviour of a thrown eye of ender.
+type EyeOfEnderBehaviour struct {
+	passive *PassiveBehaviour
+
+	target   mgl64.Vec3
+	survives bool
+}
+
+// Target returns the position that the eye of ender flies towards.
+func (b *EyeOfEnderBehaviour) Target() mgl64.Vec3 {
+	return b.target
+}
+
+// Tick moves the eye of ender towards its target.
+func (b *EyeOfEnderBehaviour) Tick(e *Ent) *Movement {
+	return b.passive.Tick(e)
+}
+
+// tick updates the velocity of the eye of ender so that it flies towards its
+// target, slowing down as it comes closer.
+func (b *EyeOfEnderBehaviour) tick(e *Ent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	dx, dz := b.target[0]-e.pos[0], b.target[2]-e.pos[2]
+	dist := math.Sqrt(dx*dx + dz*dz)
+	angle := math.Atan2(dz, dx)
+
+	horizontal := math.Sqrt(e.vel[0]*e.vel[0] + e.vel[2]*e.vel[2])
+	speed, vertical := horizontal+(dist-horizontal)*0.0025, e.vel[1]
+	if dist < 1 {
+		speed, vertical = speed*0.8, vertical*0.8
+	}
+	dir := 1.0
+	if e.pos[1] >= b.target[1] {
+		dir = -1
+	}
+	e.vel = mgl64.Vec3{math.Cos(angle) * speed, vertical + (dir-vertical)*0.015, math.Sin(angle) * speed}
+}
+
+// expire either drops the eye of ender as an item or makes it shatter.
+func (b *EyeOfEnderBehaviour) expire(e *Ent) {
+	w, pos := e.World(), e.Position()
+	if b.survives {
+		w.AddEntity(NewItem(item.NewStack(item.EnderEye{}, 1), pos))
+		return
+	}
+	w.AddParticle(pos, particle.EyeOfEnderDeath{})
+}
+
+// EyeOfEnderType is a world.EntityType implementation for EyeOfEnder.
+type EyeOfEnderType struct{}
+
+func (EyeOfEnderType) EncodeEntity() string { return "minecraft:eye_of_ender_signal" }
+func (EyeOfEnderType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.125, 0, -0.125, 0.125, 0.25, 0.125)
+}
+
+func (EyeOfEnderType) DecodeNBT(m map[string]any) world.Entity {
+	e := NewEyeOfEnder(nbtconv.Vec3(m, "Pos"), nbtconv.Vec3(m, "Target"))
+	e.vel = nbtconv.Vec3(m, "Motion")
+	return e
+}
+
+func (EyeOfEnderType) EncodeNBT(e world.Entity) map[string]any {
+	eye := e.(*Ent)
+	return map[string]any{
+		"Pos":    nbtconv.Vec3ToFloat32Slice(eye.Position()),
+		"Motion": nbtconv.Vec3ToFloat32Slice(eye.Velocity()),
+		"Target": nbtconv.Vec3ToFloat32Slice(eye.Behaviour().(*EyeOfEnderBehaviour).Target()),
+	}
+}
@@ -52,7 +52,7 @@ func (f *FallingBlockBehaviour) Tick(e *Ent) *Movement {
 func (f *FallingBlockBehaviour) tick(e *Ent) {
 	pos := e.Position()
 	bpos, w := cube.PosFromVec3(pos), e.World()
-	if a, ok := f.block.(Solidifiable); (ok && a.Solidifies(bpos, w)) || f.passive.mc.OnGround() {
+	if a, ok := f.block.(block.Fallable); (ok && a.Solidifies(bpos, w)) || f.passive.mc.OnGround() {
 		f.solidify(e, pos, w)
 	}
 }
@@ -64,24 +64,27 @@ func (f *FallingBlockBehaviour) tick(e *Ent) {
 func (f *FallingBlockBehaviour) solidify(e *Ent, pos mgl64.Vec3, w *world.World) {
 	bpos := cube.PosFromVec3(pos)
 
-	if d, ok := f.block.(damager); ok {
+	if d, ok := f.block.(block.FallDamager); ok {
 		f.damageEntities(e, d, pos, w)
 	}
-	if l, ok := f.block.(landable); ok {
-		l.Landed(w, bpos)
-	}
 	f.passive.close = true
 
-	if r, ok := w.Block(bpos).(replaceable); ok && r.ReplaceableBy(f.block) {
-		w.SetBlock(bpos, f.block, nil)
-	} else if i, ok := f.block.(world.Item); ok {
+	b := f.block
+	if l, ok := b.(block.Landable); ok {
+		if b = l.Landed(w, bpos); b == nil {
+			return
+		}
+	}
+	if r, ok := w.Block(bpos).(replaceable); ok && r.ReplaceableBy(b) {
+		w.SetBlock(bpos, b, nil)
+	} else if i, ok := b.(world.Item); ok {
 		w.AddEntity(NewItem(item.NewStack(i, 1), bpos.Vec3Middle()))
 	}
 }
 
 // damageEntities attempts to damage any entities standing below the falling
 // block. This functionality is used by falling anvils.
-func (f *FallingBlockBehaviour) damageEntities(e *Ent, d damager, pos mgl64.Vec3, w *world.World) {
+func (f *FallingBlockBehaviour) damageEntities(e *Ent, d block.FallDamager, pos mgl64.Vec3, w *world.World) {
 	damagePerBlock, maxDamage := d.Damage()
 	dist := math.Ceil(f.passive.fallDistance - 1.0)
 	if dist <= 0 {
@@ -97,34 +100,11 @@ func (f *FallingBlockBehaviour) damageEntities(e *Ent, d damager, pos mgl64.Vec3
 	for _, e := range targets {
 		e.(Living).Hurt(dmg, src)
 	}
-	if b, ok := f.block.(breakable); ok && dmg > 0.0 && rand.Float64() < (dist+1)*0.05 {
+	if b, ok := f.block.(block.FallBreakable); ok && dmg > 0.0 && rand.Float64() < (dist+1)*0.05 {
 		f.block = b.Break()
 	}
 }
 
-// Solidifiable represents a block that can solidify by specific adjacent blocks. An example is concrete
-// powder, which can turn into concrete by touching water.
-type Solidifiable interface {
-	// Solidifies returns whether the falling block can solidify at the position it is currently in. If so,
-	// the block will immediately stop falling.
-	Solidifies(pos cube.Pos, w *world.World) bool
-}
-
 type replaceable interface {
 	ReplaceableBy(b world.Block) bool
 }
-
-// damager ...
-type damager interface {
-	Damage() (damagePerBlock, maxDamage float64)
-}
-
-// breakable ...
-type breakable interface {
-	Break() world.Block
-}
-
-// landable ...
-type landable interface {
-	Landed(w *world.World, pos cube.Pos)
-}
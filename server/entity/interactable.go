@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Interactable represents an entity that may be interacted with by a user,
+// for example by right-clicking it while holding an item.
+type Interactable interface {
+	world.Entity
+	// Interact is called when a user interacts with the entity. The
+	// item.UseContext passed may be used to subtract from the count of the
+	// item held by the user. Interact returns true if the interaction had an
+	// effect.
+	Interact(user item.User, ctx *item.UseContext) bool
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+)
+
+const (
+	// leashPullDistance is the distance from its holder above which a leashed
+	// entity is pulled towards the holder.
+	leashPullDistance = 6
+	// leashBreakDistance is the distance from its holder above which the lead
+	// of a leashed entity breaks.
+	leashBreakDistance = 10
+)
+
+// Leashable is a world.Entity that may be leashed to another entity, such as
+// a player or a LeashKnot, using a lead.
+type Leashable interface {
+	world.Entity
+	// LeashHolder returns the entity that the Leashable is leashed to. If the
+	// Leashable is not leashed, false is returned.
+	LeashHolder() (world.Entity, bool)
+	// Leash leashes the Leashable to the holder passed, replacing its current
+	// holder. Leash returns false if the Leashable cannot be leashed.
+	Leash(holder world.Entity) bool
+	// Unleash removes the lead of the Leashable. If drop is true, the lead is
+	// dropped as an item.
+	Unleash(drop bool)
+}
+
+// LeashedTo returns all Leashable entities within the radius passed around pos
+// that are leashed to the holder passed.
+func LeashedTo(holder world.Entity, pos mgl64.Vec3, radius float64) []Leashable {
+	w := holder.World()
+	if w == nil {
+		return nil
+	}
+	var leashed []Leashable
+	for _, e := range w.EntitiesWithin(cube.Box(-radius, -radius, -radius, radius, radius, radius).Translate(pos), nil) {
+		if l, ok := e.(Leashable); ok {
+			if h, ok := l.LeashHolder(); ok && h == holder {
+				leashed = append(leashed, l)
+			}
+		}
+	}
+	return leashed
+}
+
+// leashPull returns the velocity added to a leashed entity at the position
+// passed to pull it towards its holder, based on the distance between them.
+func leashPull(pos, holder mgl64.Vec3) mgl64.Vec3 {
+	diff := holder.Sub(pos)
+	dist := diff.Len()
+	if dist <= leashPullDistance {
+		return mgl64.Vec3{}
+	}
+	dir := diff.Mul(1 / dist)
+	return mgl64.Vec3{
+		math.Copysign(dir[0]*dir[0]*0.4, dir[0]),
+		math.Copysign(dir[1]*dir[1]*0.4, dir[1]),
+		math.Copysign(dir[2]*dir[2]*0.4, dir[2]),
+	}
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"sync/atomic"
+)
+
+// NewLeashKnot creates a new leash knot on the fence at the position passed.
+func NewLeashKnot(pos cube.Pos) *LeashKnot {
+	return &LeashKnot{pos: pos}
+}
+
+// LeashKnot is the knot created when a leashed mob is tied to a fence. The
+// knot holds the leads of all mobs tied to the fence and is removed once the
+// fence is broken or no mobs are tied to it anymore.
+type LeashKnot struct {
+	pos cube.Pos
+	age atomic.Int64
+}
+
+// Type returns LeashKnotType.
+func (k *LeashKnot) Type() world.EntityType {
+	return LeashKnotType{}
+}
+
+// Position returns the position of the leash knot, which is in the centre of
+// the fence it is tied to.
+func (k *LeashKnot) Position() mgl64.Vec3 {
+	return k.pos.Vec3Centre()
+}
+
+// Rotation always returns an empty rotation, as leash knots cannot rotate.
+func (k *LeashKnot) Rotation() cube.Rotation {
+	return cube.Rotation{}
+}
+
+// World returns the world of the leash knot.
+func (k *LeashKnot) World() *world.World {
+	w, _ := world.OfEntity(k)
+	return w
+}
+
+// Fence returns the position of the fence that the leash knot is tied to.
+func (k *LeashKnot) Fence() cube.Pos {
+	return k.pos
+}
+
+// Interact ties the mobs leashed to the user to the leash knot. If no mobs are
+// leashed to the user, the leash knot is removed and the leads of the mobs
+// tied to it are dropped.
+func (k *LeashKnot) Interact(user item.User, _ *item.UseContext) bool {
+	tied := false
+	for _, l := range LeashedTo(user, k.Position(), 7) {
+		tied = l.Leash(k) || tied
+	}
+	if !tied {
+		k.remove()
+	}
+	return true
+}
+
+// Attack removes the leash knot, dropping the leads of the mobs tied to it.
+func (k *LeashKnot) Attack(world.DamageSource) bool {
+	k.remove()
+	return true
+}
+
+// Tick removes the leash knot once the fence it is tied to is broken or no
+// mobs are tied to it anymore.
+func (k *LeashKnot) Tick(w *world.World, _ int64) {
+	if k.age.Add(1)%20 != 0 {
+		return
+	}
+	switch w.Block(k.pos).(type) {
+	case block.WoodFence, block.NetherBrickFence:
+		if len(LeashedTo(k, k.Position(), leashBreakDistance)) > 0 {
+			return
+		}
+	}
+	k.remove()
+}
+
+// remove unleashes all mobs tied to the leash knot, dropping their leads, and
+// closes the leash knot.
+func (k *LeashKnot) remove() {
+	for _, l := range LeashedTo(k, k.Position(), leashBreakDistance) {
+		l.Unleash(true)
+	}
+	_ = k.Close()
+}
+
+// Close closes the leash knot, removing it from the world.
+func (k *LeashKnot) Close() error {
+	if w := k.World(); w != nil {
+		w.RemoveEntity(k)
+	}
+	return nil
+}
+
+// LeashKnotType is a world.EntityType implementation for LeashKnot.
+type LeashKnotType struct{}
+
+func (LeashKnotType) EncodeEntity() string { return "minecraft:leash_knot" }
+func (LeashKnotType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.1875, -0.25, -0.1875, 0.1875, 0.25, 0.1875)
+}
+
+func (LeashKnotType) DecodeNBT(m map[string]any) world.Entity {
+	return NewLeashKnot(cube.PosFromVec3(nbtconv.Vec3(m, "Pos")))
+}
+
+func (LeashKnotType) EncodeNBT(e world.Entity) map[string]any {
+	return map[string]any{"Pos": nbtconv.Vec3ToFloat32Slice(e.Position())}
+}
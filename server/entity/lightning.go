@@ -26,6 +26,7 @@ func NewLightningWithDamage(pos mgl64.Vec3, dmg float64, blockFire bool, entityF
 		BlockFire:          blockFire,
 		state:              2,
 		lifetime:           rand.Intn(4) + 1,
+		struck:             make(map[world.Entity]struct{}),
 	}
 	conf := lightningConf
 	conf.Tick = state.tick
@@ -40,12 +41,18 @@ type lightningState struct {
 	EntityFireDuration time.Duration
 	BlockFire          bool
 	state, lifetime    int
+	// struck holds the entities that were struck by the lightning, so that
+	// each entity is only notified of the strike once.
+	struck map[world.Entity]struct{}
 }
 
 // tick carries out lightning logic, dealing damage and setting blocks/entities
 // on fire when appropriate.
 func (s *lightningState) tick(e *Ent) {
 	w, pos := e.World(), e.Position()
+	if s.state == 2 {
+		s.strikeBlock(w, cube.PosFromVec3(pos).Side(cube.FaceDown))
+	}
 
 	if s.state--; s.state < 0 {
 		if s.lifetime == 0 {
@@ -64,21 +71,39 @@ func (s *lightningState) tick(e *Ent) {
 	}
 }
 
+// strikeBlock notifies the block struck by the lightning at the position
+// passed if it is a world.LightningAttractor. Lightning striking a
+// world.LightningAttractor does not set blocks on fire.
+func (s *lightningState) strikeBlock(w *world.World, pos cube.Pos) {
+	if a, ok := w.Block(pos).(world.LightningAttractor); ok {
+		s.BlockFire = false
+		a.StrikeLightning(pos, w)
+	}
+}
+
 // dealDamage deals damage to all entities around the lightning and sets them
-// on fire.
+// on fire. Entities with a StruckByLightning method are notified of the
+// strike once.
 func (s *lightningState) dealDamage(e *Ent) {
 	w, pos := e.World(), e.Position()
 	bb := e.Type().BBox(e).GrowVec3(mgl64.Vec3{3, 6, 3}).Translate(pos.Add(mgl64.Vec3{0, 3}))
-	for _, e := range w.EntitiesWithin(bb, nil) {
+	for _, other := range w.EntitiesWithin(bb, nil) {
 		// Only damage entities that weren't already dead.
-		if l, ok := e.(Living); ok && l.Health() > 0 {
+		if l, ok := other.(Living); ok && l.Health() > 0 {
 			if s.Damage > 0 {
 				l.Hurt(s.Damage, LightningDamageSource{})
 			}
-			if f, ok := e.(Flammable); ok && f.OnFireDuration() < s.EntityFireDuration {
+			if f, ok := other.(Flammable); ok && f.OnFireDuration() < s.EntityFireDuration {
 				f.SetOnFire(s.EntityFireDuration)
 			}
 		}
+		if _, ok := s.struck[other]; ok || other == e {
+			continue
+		}
+		s.struck[other] = struct{}{}
+		if l, ok := other.(interface{ StruckByLightning(lightning world.Entity) }); ok {
+			l.StruckByLightning(e)
+		}
 	}
 }
 
@@ -87,8 +112,7 @@ func (s *lightningState) dealDamage(e *Ent) {
 func (s *lightningState) spreadFire(w *world.World, pos cube.Pos) {
 	s.fire().Start(w, pos)
 	for i := 0; i < 4; i++ {
-		pos.Add(cube.Pos{rand.Intn(3) - 1, rand.Intn(3) - 1, rand.Intn(3) - 1})
-		s.fire().Start(w, pos)
+		s.fire().Start(w, pos.Add(cube.Pos{rand.Intn(3) - 1, rand.Intn(3) - 1, rand.Intn(3) - 1}))
 	}
 }
 
@@ -0,0 +1,973 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/enchantment"
+	"github.com/df-mc/dragonfly/server/item/loot"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// MobBehaviour implements the behaviour, typically the AI, of a Mob.
+type MobBehaviour interface {
+	// Tick ticks the Mob using the MobBehaviour. Tick is called every tick
+	// before the movement of the Mob is computed, so that the MobBehaviour
+	// may change the velocity of the Mob.
+	Tick(m *Mob)
+}
+
+// MobConfig allows specifying options that influence the way a Mob behaves.
+type MobConfig struct {
+	// Behaviour is the MobBehaviour of the Mob. It must not be nil.
+	Behaviour MobBehaviour
+	// MaxHealth is the maximum health of the Mob. If 0, a maximum health of
+	// 10 is used.
+	MaxHealth float64
+	// Speed is the movement speed of the Mob in blocks/tick. If 0, a speed of
+	// 0.1 is used.
+	Speed float64
+	// KnockBackResistance is the resistance of the Mob to knock back, ranging
+	// from 0 (normal knock back) to 1 (no knock back at all).
+	KnockBackResistance float64
+	// Gravity is the amount of Y velocity subtracted every tick. Drag is used
+	// to reduce all axes of the velocity every tick.
+	Gravity, Drag float64
+	// NoClip specifies if the Mob moves through blocks without colliding with
+	// them, such as the ender dragon.
+	NoClip bool
+	// Leashable specifies if the Mob may be leashed using a lead.
+	Leashable bool
+	// Despawnable specifies if the Mob despawns when no players are nearby,
+	// such as most hostile mobs. A Mob that is Persistent never despawns.
+	Despawnable bool
+	// LootTable is the name of the loot.Table used to generate the items
+	// dropped by the Mob when it dies, such as "entities/cow". If empty, or
+	// if the Mob is a baby, no items are generated from a loot table.
+	LootTable string
+	// Drops is called when the Mob dies to obtain the items it drops in
+	// addition to those generated from the LootTable. The world.DamageSource
+	// that killed the Mob is passed. Drops may be nil.
+	Drops func(m *Mob, src world.DamageSource) []item.Stack
+	// Experience is the amount of experience dropped by the Mob when it is
+	// killed by a player.
+	Experience int
+}
+
+// New creates a new Mob using conf. The Mob has a type and a position.
+func (conf MobConfig) New(t world.EntityType, pos mgl64.Vec3) *Mob {
+	if conf.MaxHealth == 0 {
+		conf.MaxHealth = 10
+	}
+	if conf.Speed == 0 {
+		conf.Speed = 0.1
+	}
+	attributes := attribute.NewManager()
+	attributes.SetBase(attribute.MaxHealth, conf.MaxHealth)
+	attributes.SetBase(attribute.MovementSpeed, conf.Speed)
+	attributes.SetBase(attribute.KnockBackResistance, conf.KnockBackResistance)
+	return &Mob{
+		conf:       conf,
+		t:          t,
+		pos:        pos,
+		health:     NewHealthManager(conf.MaxHealth, conf.MaxHealth),
+		effects:    NewEffectManager(),
+		attributes: attributes,
+		mc:         &MovementComputer{Gravity: conf.Gravity, Drag: conf.Drag, NoClip: conf.NoClip},
+	}
+}
+
+// Mob is a world.Entity implementation for living, non-player entities such
+// as animals and monsters. Similarly to Ent, a Mob delegates its behaviour
+// to a MobBehaviour, while the Mob itself implements the Living interface.
+type Mob struct {
+	conf MobConfig
+	t    world.EntityType
+
+	mu  sync.Mutex
+	pos mgl64.Vec3
+	vel mgl64.Vec3
+	rot cube.Rotation
+
+	name         string
+	nameVisible  bool
+	fireDuration time.Duration
+	age          time.Duration
+	immunity     time.Duration
+	fallDistance float64
+	deathTicks   int
+	lastAttacker world.Entity
+	ridden       Rideable
+	leashHolder  world.Entity
+	// leashKnot is the position of the fence that the Mob was tied to when it
+	// was saved. The LeashKnot is looked up once the Mob is ticked.
+	leashKnot *cube.Pos
+
+	mc         *MovementComputer
+	health     *HealthManager
+	effects    *EffectManager
+	attributes *attribute.Manager
+}
+
+// Type returns the world.EntityType passed to MobConfig.New.
+func (m *Mob) Type() world.EntityType {
+	return m.t
+}
+
+// Behaviour returns the MobBehaviour of the Mob.
+func (m *Mob) Behaviour() MobBehaviour {
+	return m.conf.Behaviour
+}
+
+// Position returns the current position of the Mob.
+func (m *Mob) Position() mgl64.Vec3 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pos
+}
+
+// Rotation returns the rotation of the Mob.
+func (m *Mob) Rotation() cube.Rotation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rot
+}
+
+// World returns the world of the Mob.
+func (m *Mob) World() *world.World {
+	w, _ := world.OfEntity(m)
+	return w
+}
+
+// Velocity returns the current velocity of the Mob. The values in the Vec3
+// returned represent the speed on that axis in blocks/tick.
+func (m *Mob) Velocity() mgl64.Vec3 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.vel
+}
+
+// SetVelocity sets the velocity of the Mob. The values in the Vec3 passed
+// represent the speed on that axis in blocks/tick.
+func (m *Mob) SetVelocity(v mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vel = v
+}
+
+// Teleport teleports the Mob to the position passed.
+func (m *Mob) Teleport(pos mgl64.Vec3) {
+	m.mu.Lock()
+	m.pos, m.fallDistance = pos, 0
+	m.mu.Unlock()
+	for _, v := range m.World().Viewers(pos) {
+		v.ViewEntityTeleport(m, pos)
+	}
+}
+
+// Age returns the total time lived of this Mob. It increases by
+// time.Second/20 for every time Tick is called.
+func (m *Mob) Age() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.age
+}
+
+// OnGround checks if the Mob is currently standing on the ground.
+func (m *Mob) OnGround() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mc.OnGround()
+}
+
+// Health returns the current health of the Mob.
+func (m *Mob) Health() float64 {
+	return m.health.Health()
+}
+
+// MaxHealth returns the maximum health of the Mob.
+func (m *Mob) MaxHealth() float64 {
+	return m.health.MaxHealth()
+}
+
+// SetMaxHealth sets the base maximum health of the Mob. Modifiers of the
+// attribute.MaxHealth attribute, such as those of the health boost effect, are
+// applied on top of it.
+func (m *Mob) SetMaxHealth(v float64) {
+	m.health.SetMaxHealth(m.attributes.SetBase(attribute.MaxHealth, v))
+}
+
+// Attribute returns the value of an attribute of the Mob, computed from its
+// base value and all attribute.Modifiers applied to it.
+func (m *Mob) Attribute(a attribute.Attribute) float64 {
+	return m.attributes.Value(a)
+}
+
+// AddAttributeModifier adds an attribute.Modifier to an attribute of the Mob,
+// replacing any attribute.Modifier with the same ID.
+func (m *Mob) AddAttributeModifier(a attribute.Attribute, mod attribute.Modifier) {
+	m.updateAttribute(a, m.attributes.AddModifier(a, mod))
+}
+
+// RemoveAttributeModifier removes the attribute.Modifier with the ID passed
+// from an attribute of the Mob.
+func (m *Mob) RemoveAttributeModifier(a attribute.Attribute, id string) {
+	m.updateAttribute(a, m.attributes.RemoveModifier(a, id))
+}
+
+// updateAttribute updates the state of the Mob after the value of an
+// attribute changed to v.
+func (m *Mob) updateAttribute(a attribute.Attribute, v float64) {
+	if a == attribute.MaxHealth {
+		m.health.SetMaxHealth(v)
+	}
+}
+
+// Dead checks if the Mob is dead.
+func (m *Mob) Dead() bool {
+	return m.health.Health() <= mgl64.Epsilon
+}
+
+// AttackImmune checks if the Mob is currently immune to attacks.
+func (m *Mob) AttackImmune() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.immunity > 0
+}
+
+// LastAttacker returns the entity that last attacked the Mob, or nil if the
+// Mob was not attacked by an entity.
+func (m *Mob) LastAttacker() world.Entity {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastAttacker
+}
+
+// Hurt hurts the Mob for a given amount of damage. The final damage dealt and
+// whether the Mob was vulnerable to the damage are returned.
+func (m *Mob) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
+	if _, ok := m.Effect(effect.FireResistance{}); (ok && src.Fire()) || m.Dead() || dmg < 0 {
+		return 0, false
+	}
+	if h, ok := m.conf.Behaviour.(interface {
+		Hurt(m *Mob, dmg float64, src world.DamageSource) (float64, bool)
+	}); ok {
+		var vulnerable bool
+		if dmg, vulnerable = h.Hurt(m, dmg, src); !vulnerable {
+			return 0, false
+		}
+	}
+	if res, ok := m.Effect(effect.Resistance{}); ok {
+		dmg *= effect.Resistance{}.Multiplier(src, res.Level())
+	}
+	m.health.AddHealth(-dmg)
+
+	var attacker world.Entity
+	if s, ok := src.(AttackDamageSource); ok {
+		attacker = s.Attacker
+	} else if s, ok := src.(ProjectileDamageSource); ok {
+		attacker = s.Owner
+	}
+	m.mu.Lock()
+	m.immunity = time.Second / 2
+	if attacker != nil {
+		m.lastAttacker = attacker
+	}
+	m.mu.Unlock()
+
+	w, pos := m.World(), m.Position()
+	for _, v := range w.Viewers(pos) {
+		v.ViewEntityAction(m, HurtAction{})
+	}
+	w.EmitGameEvent(world.GameEventEntityDamage, pos, attacker)
+	if src.Fire() {
+		w.PlaySound(pos, sound.Burning{})
+	}
+	if m.Dead() {
+		m.kill(src)
+	}
+	return dmg, true
+}
+
+// kill kills the Mob, showing the death animation and dropping its items and
+// experience. The Mob is removed from the world shortly after.
+func (m *Mob) kill(src world.DamageSource) {
+	w, pos := m.World(), m.Position()
+	for _, v := range w.Viewers(pos) {
+		v.ViewEntityAction(m, DeathAction{})
+	}
+	if d, ok := m.conf.Behaviour.(interface {
+		Death(m *Mob, src world.DamageSource)
+	}); ok {
+		d.Death(m, src)
+	}
+	if world.GameRuleDoMobLoot.Value(w) {
+		for _, it := range m.drops(src) {
+			w.AddEntity(NewItem(it, pos))
+		}
+		if _, ok := m.killedBy(src).(experienceCollector); ok && m.conf.Experience > 0 {
+			for _, orb := range NewExperienceOrbs(pos, m.conf.Experience) {
+				w.AddEntity(orb)
+			}
+		}
+	}
+	for _, e := range m.Effects() {
+		m.RemoveEffect(e.Type())
+	}
+}
+
+// drops returns the items dropped by the Mob when killed with the
+// world.DamageSource passed.
+func (m *Mob) drops(src world.DamageSource) []item.Stack {
+	var drops []item.Stack
+	if b, ok := m.conf.Behaviour.(interface{ Baby() bool }); !ok || !b.Baby() {
+		if t, ok := loot.Lookup(m.conf.LootTable); ok {
+			drops = t.Generate(m.lootContext(src))
+		}
+	}
+	if m.conf.Drops != nil {
+		drops = append(drops, m.conf.Drops(m, src)...)
+	}
+	return drops
+}
+
+// lootContext returns the loot.Context in which the loot of the Mob is
+// generated when it is killed with the world.DamageSource passed.
+func (m *Mob) lootContext(src world.DamageSource) loot.Context {
+	ctx := loot.Context{This: m, Killer: m.killedBy(src)}
+	if ctx.Killer == nil {
+		return ctx
+	}
+	ctx.KilledByPlayer = ctx.Killer.Type().EncodeEntity() == "minecraft:player"
+	if u, ok := ctx.Killer.(item.User); ok {
+		if _, attack := src.(AttackDamageSource); attack {
+			held, _ := u.HeldItems()
+			if e, ok := held.Enchantment(enchantment.Looting{}); ok {
+				ctx.Looting = e.Level()
+			}
+		}
+	}
+	return ctx
+}
+
+// killedBy returns the entity responsible for the death of the Mob with the
+// world.DamageSource passed, or nil if no entity was responsible.
+func (m *Mob) killedBy(src world.DamageSource) world.Entity {
+	switch s := src.(type) {
+	case AttackDamageSource:
+		return s.Attacker
+	case ProjectileDamageSource:
+		return s.Owner
+	}
+	return nil
+}
+
+// Heal heals the Mob for a given amount of health.
+func (m *Mob) Heal(health float64, _ world.HealingSource) {
+	if m.Dead() || health < 0 {
+		return
+	}
+	m.health.AddHealth(health)
+}
+
+// KnockBack knocks the Mob back with a given force and height, away from the
+// source position passed.
+func (m *Mob) KnockBack(src mgl64.Vec3, force, height float64) {
+	if m.Dead() {
+		return
+	}
+	velocity := m.Position().Sub(src)
+	velocity[1] = 0
+	if velocity.Len() != 0 {
+		velocity = velocity.Normalize().Mul(force)
+	}
+	velocity[1] = height
+	m.SetVelocity(velocity.Mul(1 - m.attributes.Value(attribute.KnockBackResistance)))
+}
+
+// Explode hurts the Mob and knocks it back as a result of an explosion.
+func (m *Mob) Explode(src mgl64.Vec3, impact float64, conf block.ExplosionConfig) {
+	diff := m.Position().Sub(src)
+	m.Hurt(math.Floor((impact*impact+impact)*3.5*conf.Size+1), ExplosionDamageSource{})
+	m.KnockBack(src, impact, diff[1]/diff.Len()*impact)
+}
+
+// AddEffect adds an effect.Effect to the Mob.
+func (m *Mob) AddEffect(e effect.Effect) {
+	m.effects.Add(e, m)
+	m.updateState()
+}
+
+// RemoveEffect removes any effect of the type passed from the Mob.
+func (m *Mob) RemoveEffect(e effect.Type) {
+	m.effects.Remove(e, m)
+	m.updateState()
+}
+
+// Effect returns the effect of the type passed and true if the Mob has it.
+func (m *Mob) Effect(e effect.Type) (effect.Effect, bool) {
+	return m.effects.Effect(e)
+}
+
+// Effects returns all effects currently applied to the Mob.
+func (m *Mob) Effects() []effect.Effect {
+	return m.effects.Effects()
+}
+
+// Speed returns the movement speed of the Mob in blocks/tick, including the
+// attribute.Modifiers applied to the attribute.MovementSpeed attribute.
+func (m *Mob) Speed() float64 {
+	return m.attributes.Value(attribute.MovementSpeed)
+}
+
+// SetSpeed sets the base movement speed of the Mob in blocks/tick.
+func (m *Mob) SetSpeed(v float64) {
+	m.attributes.SetBase(attribute.MovementSpeed, v)
+}
+
+// OnFireDuration ...
+func (m *Mob) OnFireDuration() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fireDuration
+}
+
+// SetOnFire ...
+func (m *Mob) SetOnFire(duration time.Duration) {
+	if duration < 0 {
+		duration = 0
+	}
+	m.mu.Lock()
+	before, after := m.fireDuration > 0, duration > 0
+	m.fireDuration = duration
+	m.mu.Unlock()
+
+	if before != after {
+		m.updateState()
+	}
+}
+
+// Extinguish ...
+func (m *Mob) Extinguish() {
+	m.SetOnFire(0)
+}
+
+// NameTag returns the name tag of the Mob. An empty string is returned if no
+// name tag was set.
+func (m *Mob) NameTag() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.name
+}
+
+// SetNameTag changes the name tag of the Mob. The name tag is removed if an
+// empty string is passed.
+func (m *Mob) SetNameTag(s string) {
+	m.mu.Lock()
+	m.name = s
+	m.mu.Unlock()
+	m.updateState()
+}
+
+// NameTagAlwaysVisible checks if the name tag of the Mob is always shown to
+// viewers. If false, the name tag is only shown when a viewer looks at the
+// Mob.
+func (m *Mob) NameTagAlwaysVisible() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nameVisible
+}
+
+// SetNameTagAlwaysVisible changes if the name tag of the Mob is always shown
+// to viewers, rather than only when a viewer looks at the Mob.
+func (m *Mob) SetNameTagAlwaysVisible(v bool) {
+	m.mu.Lock()
+	m.nameVisible = v
+	m.mu.Unlock()
+	m.updateState()
+}
+
+// Persistent checks if the Mob is exempt from despawning. This is the case if
+// the Mob is not Despawnable according to its MobConfig, or if it has a name
+// tag, is leashed or is riding another entity.
+func (m *Mob) Persistent() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.conf.Despawnable || m.name != "" || m.leashHolder != nil || m.leashKnot != nil || m.ridden != nil
+}
+
+// Interact is called when a user interacts with the Mob, for example by
+// using an item on it. Interact returns true if the interaction had an
+// effect.
+func (m *Mob) Interact(user item.User, ctx *item.UseContext) bool {
+	if m.Dead() {
+		return false
+	}
+	if holder, leashed := m.LeashHolder(); leashed && holder == user {
+		m.Unleash(true)
+		return true
+	}
+	held, _ := user.HeldItems()
+	switch held.Item().(type) {
+	case item.Lead:
+		if m.conf.Leashable {
+			// Let the lead leash the Mob rather than the MobBehaviour
+			// handling the interaction.
+			return false
+		}
+	case item.NameTag:
+		if held.CustomName() != "" {
+			return false
+		}
+	}
+	if i, ok := m.conf.Behaviour.(interface {
+		Interact(m *Mob, user item.User, ctx *item.UseContext) bool
+	}); ok {
+		return i.Interact(m, user, ctx)
+	}
+	return false
+}
+
+// HeldItems returns the items held by the Mob in its main hand and off hand.
+// Mobs whose MobBehaviour does not hold items always return empty stacks.
+func (m *Mob) HeldItems() (mainHand, offHand item.Stack) {
+	if c, ok := m.conf.Behaviour.(interface {
+		HeldItems() (mainHand, offHand item.Stack)
+	}); ok {
+		return c.HeldItems()
+	}
+	return item.Stack{}, item.Stack{}
+}
+
+// HandleGameEvent passes a world.GameEvent emitted near the Mob to its
+// MobBehaviour, if the MobBehaviour listens to game events.
+func (m *Mob) HandleGameEvent(e world.GameEvent, pos mgl64.Vec3, src world.Entity) {
+	if m.Dead() {
+		return
+	}
+	if l, ok := m.conf.Behaviour.(interface {
+		HandleGameEvent(m *Mob, e world.GameEvent, pos mgl64.Vec3, src world.Entity)
+	}); ok {
+		l.HandleGameEvent(m, e, pos, src)
+	}
+}
+
+// MoveTowards makes the Mob walk towards the target position passed at its
+// current speed, multiplied by the multiplier passed. The Mob turns to face
+// the target and jumps if a block obstructs its path.
+func (m *Mob) MoveTowards(target mgl64.Vec3, multiplier float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	diff := target.Sub(m.pos)
+	diff[1] = 0
+	if diff.Len() < 0.1 {
+		return
+	}
+	dir := diff.Normalize().Mul(m.attributes.Value(attribute.MovementSpeed) * multiplier)
+	m.vel[0], m.vel[2] = dir[0], dir[2]
+	m.rot = cube.Rotation{mgl64.RadToDeg(math.Atan2(-diff[0], diff[2])), m.rot[1]}
+
+	if m.mc.OnGround() {
+		w, _ := world.OfEntity(m)
+		front := cube.PosFromVec3(m.pos.Add(diff.Normalize().Mul(0.6)))
+		if len(w.Block(front).Model().BBox(front, w)) > 0 && len(w.Block(front.Side(cube.FaceUp)).Model().BBox(front.Side(cube.FaceUp), w)) == 0 {
+			m.vel[1] = 0.42
+		}
+	}
+}
+
+// FlyTowards makes the Mob fly towards the target position passed at its
+// current speed, multiplied by the multiplier passed. Rather than changing
+// direction instantly, the velocity of the Mob is steered gradually towards
+// the target. The Mob turns to face the direction it is flying in.
+func (m *Mob) FlyTowards(target mgl64.Vec3, multiplier float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	diff := target.Sub(m.pos)
+	if diff.Len() < 0.1 {
+		return
+	}
+	desired := diff.Normalize().Mul(m.attributes.Value(attribute.MovementSpeed) * multiplier)
+	m.vel = m.vel.Add(desired.Sub(m.vel).Mul(0.1))
+	horizontal := math.Sqrt(m.vel[0]*m.vel[0] + m.vel[2]*m.vel[2])
+	m.rot = cube.Rotation{mgl64.RadToDeg(math.Atan2(-m.vel[0], m.vel[2])), mgl64.RadToDeg(-math.Atan2(m.vel[1], horizontal))}
+}
+
+// LookAt makes the Mob turn to face the position passed.
+func (m *Mob) LookAt(target mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	diff := target.Sub(m.pos)
+	horizontal := math.Sqrt(diff[0]*diff[0] + diff[2]*diff[2])
+	m.rot = cube.Rotation{mgl64.RadToDeg(math.Atan2(-diff[0], diff[2])), mgl64.RadToDeg(-math.Atan2(diff[1], horizontal))}
+}
+
+// Tick ticks the Mob, ticking its MobBehaviour, effects and movement.
+func (m *Mob) Tick(w *world.World, current int64) {
+	if m.Dead() {
+		m.Dismount()
+		m.Seats().DismountAll()
+		m.Unleash(true)
+
+		m.mu.Lock()
+		m.deathTicks++
+		ticks := m.deathTicks
+		m.mu.Unlock()
+
+		done := ticks >= 20
+		if d, ok := m.conf.Behaviour.(interface {
+			DeathTick(m *Mob, ticks int) bool
+		}); ok {
+			// The behaviour implements its own death animation and decides
+			// when the Mob is removed.
+			done = d.DeathTick(m, ticks)
+		}
+		if done {
+			_ = m.Close()
+		}
+		return
+	}
+	if m.Position()[1] < float64(w.Range()[0]) && current%10 == 0 {
+		m.Hurt(4, VoidDamageSource{})
+	}
+	m.effects.Tick(m)
+	if m.Dead() {
+		return
+	}
+	if fire := m.OnFireDuration(); fire > 0 {
+		m.SetOnFire(fire - time.Second/20)
+		if fire%time.Second == 0 {
+			m.Hurt(1, block.FireDamageSource{})
+		}
+	}
+
+	m.conf.Behaviour.Tick(m)
+	if _, ok := world.OfEntity(m); !ok || m.Dead() {
+		// The Mob died or was removed by its MobBehaviour.
+		return
+	}
+	if r, riding := m.Riding(); riding {
+		// The position of a riding Mob is determined by its seat.
+		m.tickRiding(r)
+		return
+	}
+	m.tickLeash(w)
+	if current%20 == 0 && m.tickDespawn(w) {
+		return
+	}
+
+	push, crowded := crowdPush(m, w)
+	if limit := w.MaxEntityCramming(); limit > 0 && crowded >= limit && rand.Intn(4) == 0 {
+		m.Hurt(6, CrammingDamageSource{})
+		if m.Dead() {
+			return
+		}
+	}
+
+	m.mu.Lock()
+	m.vel = m.vel.Add(push)
+	mov := m.mc.TickMovement(m, m.pos, m.vel, m.rot)
+	m.pos, m.vel = mov.pos, mov.vel
+	fallDistance := m.fallDistance
+	if mov.onGround {
+		m.fallDistance = 0
+	} else {
+		m.fallDistance = math.Max(m.fallDistance-mov.dvel[1], 0)
+	}
+	if m.immunity > 0 {
+		m.immunity -= time.Second / 20
+	}
+	m.age += time.Second / 20
+	m.mu.Unlock()
+
+	mov.Send()
+	if mov.onGround && fallDistance > 3 {
+		m.Hurt(math.Ceil(fallDistance-3), FallDamageSource{})
+	}
+}
+
+// LeashHolder returns the entity that the Mob is leashed to. If the Mob is not
+// leashed, false is returned.
+func (m *Mob) LeashHolder() (world.Entity, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.leashHolder, m.leashHolder != nil
+}
+
+// Leash leashes the Mob to the holder passed, replacing its current holder.
+// Leash returns false if the Mob is not Leashable according to its MobConfig.
+func (m *Mob) Leash(holder world.Entity) bool {
+	if !m.conf.Leashable || m.Dead() || holder == world.Entity(m) {
+		return false
+	}
+	m.mu.Lock()
+	m.leashHolder, m.leashKnot = holder, nil
+	m.mu.Unlock()
+	m.updateState()
+	return true
+}
+
+// Unleash removes the lead of the Mob. If drop is true, the lead is dropped as
+// an item at the position of the Mob.
+func (m *Mob) Unleash(drop bool) {
+	m.mu.Lock()
+	leashed := m.leashHolder != nil || m.leashKnot != nil
+	m.leashHolder, m.leashKnot = nil, nil
+	m.mu.Unlock()
+	if !leashed {
+		return
+	}
+	m.updateState()
+	if w := m.World(); drop && w != nil {
+		w.AddEntity(NewItem(item.NewStack(item.Lead{}, 1), m.Position()))
+	}
+}
+
+// tickLeash pulls the Mob towards the entity it is leashed to if it is too far
+// away, and breaks the lead if the distance becomes too large or if the holder
+// is no longer in the same world.
+func (m *Mob) tickLeash(w *world.World) {
+	m.mu.Lock()
+	holder, knot := m.leashHolder, m.leashKnot
+	m.mu.Unlock()
+	if knot != nil {
+		m.resolveLeashKnot(w, *knot)
+		return
+	}
+	if holder == nil {
+		return
+	}
+	hw, ok := world.OfEntity(holder)
+	if l, living := holder.(Living); !ok || hw != w || (living && l.Dead()) {
+		m.Unleash(true)
+		return
+	}
+	pos, holderPos := m.Position(), holder.Position()
+	if holderPos.Sub(pos).Len() > leashBreakDistance {
+		m.Unleash(true)
+		return
+	}
+	if pull := leashPull(pos, holderPos); pull != (mgl64.Vec3{}) {
+		m.SetVelocity(m.Velocity().Add(pull))
+	}
+}
+
+// tickDespawn despawns the Mob if it is not Persistent and no players are
+// nearby. The Mob is removed immediately if no player is within 128 blocks and
+// has a chance to despawn if it has lived for over 30 seconds without a player
+// within 32 blocks. tickDespawn returns true if the Mob was despawned.
+func (m *Mob) tickDespawn(w *world.World) bool {
+	if m.Persistent() {
+		return false
+	}
+	pos, nearest := m.Position(), math.MaxFloat64
+	for _, e := range w.EntitiesWithin(cube.Box(-128, -128, -128, 128, 128, 128).Translate(pos), func(e world.Entity) bool {
+		if g, ok := e.(interface{ GameMode() world.GameMode }); ok && !g.GameMode().Visible() {
+			// Players in spectator mode do not keep mobs from despawning.
+			return true
+		}
+		return e.Type().EncodeEntity() != "minecraft:player"
+	}) {
+		nearest = math.Min(nearest, e.Position().Sub(pos).Len())
+	}
+	if nearest > 128 || (nearest > 32 && m.Age() > time.Second*30 && rand.Intn(40) == 0) {
+		_ = m.Close()
+		return true
+	}
+	return false
+}
+
+// resolveLeashKnot leashes the Mob to the LeashKnot on the fence at the
+// position passed after it was loaded from disk, creating the LeashKnot if it
+// does not exist. If the fence no longer exists, the lead is dropped.
+func (m *Mob) resolveLeashKnot(w *world.World, pos cube.Pos) {
+	switch w.Block(pos).(type) {
+	case block.WoodFence, block.NetherBrickFence:
+	default:
+		m.Unleash(true)
+		return
+	}
+	for _, e := range w.EntitiesWithin(cube.Box(0, 0, 0, 1, 1, 1).Translate(pos.Vec3()), nil) {
+		if knot, ok := e.(*LeashKnot); ok {
+			m.Leash(knot)
+			return
+		}
+	}
+	knot := NewLeashKnot(pos)
+	w.AddEntity(knot)
+	m.Leash(knot)
+}
+
+// Mount makes the Mob ride the Rideable passed, occupying its first free
+// seat. Mount returns false if the Mob is already riding or if no seat is
+// free.
+func (m *Mob) Mount(r Rideable) bool {
+	if world.Entity(r) == m || m.Dead() {
+		return false
+	}
+	if _, riding := m.Riding(); riding {
+		return false
+	}
+	seat, ok := r.Seats().Sit(m)
+	if !ok {
+		return false
+	}
+	m.mu.Lock()
+	m.ridden, m.pos, m.vel = r, SeatPosition(r, seat), mgl64.Vec3{}
+	m.mu.Unlock()
+
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityMount(m, r, seat == 0)
+		v.ViewEntityState(m)
+	}
+	return true
+}
+
+// Dismount makes the Mob stop riding its current Rideable, if any. The Mob
+// is placed on top of the Rideable.
+func (m *Mob) Dismount() {
+	m.mu.Lock()
+	r := m.ridden
+	m.ridden = nil
+	m.mu.Unlock()
+	if r == nil {
+		return
+	}
+	r.Seats().Leave(m)
+	w := m.World()
+	if w == nil {
+		return
+	}
+	for _, v := range w.Viewers(m.Position()) {
+		v.ViewEntityDismount(m, r)
+		v.ViewEntityState(m)
+	}
+	m.Teleport(DismountPosition(r))
+}
+
+// Riding returns the Rideable currently ridden by the Mob. If the Mob is not
+// riding anything, false is returned.
+func (m *Mob) Riding() (Rideable, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ridden, m.ridden != nil
+}
+
+// Seats returns the Seats of the Mob if its MobBehaviour implements a
+// Seats() *Seats method, such as horses. Otherwise, nil is returned and the
+// Mob cannot be ridden.
+func (m *Mob) Seats() *Seats {
+	if r, ok := m.conf.Behaviour.(interface{ Seats() *Seats }); ok {
+		return r.Seats()
+	}
+	return nil
+}
+
+// Drive forwards the input of the driver of the Mob to its MobBehaviour, if
+// it implements a Drive(m *Mob, driver Rider, input RiderInput) method.
+func (m *Mob) Drive(driver Rider, input RiderInput) {
+	if d, ok := m.conf.Behaviour.(interface {
+		Drive(m *Mob, driver Rider, input RiderInput)
+	}); ok {
+		d.Drive(m, driver, input)
+	}
+}
+
+// StruckByLightning forwards a lightning strike to the MobBehaviour of the
+// Mob, if it implements a StruckByLightning(m *Mob, lightning world.Entity)
+// method. Behaviours may use this to convert the Mob into another entity,
+// like a pig turning into a zombified piglin.
+func (m *Mob) StruckByLightning(lightning world.Entity) {
+	if l, ok := m.conf.Behaviour.(interface {
+		StruckByLightning(m *Mob, lightning world.Entity)
+	}); ok {
+		l.StruckByLightning(m, lightning)
+	}
+}
+
+// tickRiding moves the Mob to its seat on the Rideable passed. If the
+// Rideable was removed from the world, the Mob dismounts it.
+func (m *Mob) tickRiding(r Rideable) {
+	seat, ok := r.Seats().Seat(m)
+	if _, inWorld := world.OfEntity(r); !inWorld || !ok {
+		m.Dismount()
+		return
+	}
+	m.mu.Lock()
+	m.pos, m.vel, m.fallDistance = SeatPosition(r, seat), mgl64.Vec3{}, 0
+	m.age += time.Second / 20
+	m.mu.Unlock()
+}
+
+// updateState sends the state of the Mob to all viewers.
+func (m *Mob) updateState() {
+	w := m.World()
+	if w == nil {
+		return
+	}
+	for _, v := range w.Viewers(m.Position()) {
+		v.ViewEntityState(m)
+	}
+}
+
+// Close closes the Mob and removes it from the world.
+func (m *Mob) Close() error {
+	if c, ok := m.conf.Behaviour.(interface{ Close(m *Mob) }); ok {
+		c.Close(m)
+	}
+	m.Dismount()
+	m.Seats().DismountAll()
+	m.World().RemoveEntity(m)
+	return nil
+}
+
+// decodeMobNBT decodes the properties shared by all mobs from the NBT map
+// passed into the Mob.
+func decodeMobNBT(m *Mob, data map[string]any) *Mob {
+	m.vel, m.rot = nbtconv.Vec3(data, "Motion"), nbtconv.Rotation(data)
+	m.name = nbtconv.String(data, "CustomName")
+	m.nameVisible = nbtconv.Bool(data, "CustomNameVisible")
+	m.fireDuration = nbtconv.TickDuration[int16](data, "Fire")
+	if health, ok := data["Health"].(float32); ok {
+		m.health.AddHealth(float64(health) - m.health.Health())
+	}
+	if leash, ok := data["Leash"].(map[string]any); ok {
+		m.leashKnot = &cube.Pos{int(nbtconv.Int32(leash, "X")), int(nbtconv.Int32(leash, "Y")), int(nbtconv.Int32(leash, "Z"))}
+	}
+	return m
+}
+
+// encodeMobNBT encodes the properties shared by all mobs into an NBT map.
+func encodeMobNBT(m *Mob) map[string]any {
+	rot := m.Rotation()
+	data := map[string]any{
+		"Pos":    nbtconv.Vec3ToFloat32Slice(m.Position()),
+		"Motion": nbtconv.Vec3ToFloat32Slice(m.Velocity()),
+		"Yaw":    float32(rot.Yaw()),
+		"Pitch":  float32(rot.Pitch()),
+		"Health": float32(m.Health()),
+		"Fire":   int16(m.OnFireDuration() / (time.Second / 20)),
+	}
+	if name := m.NameTag(); name != "" {
+		data["CustomName"] = name
+		data["CustomNameVisible"] = boolByte(m.NameTagAlwaysVisible())
+	}
+	if holder, ok := m.LeashHolder(); ok {
+		if knot, ok := holder.(*LeashKnot); ok {
+			fence := knot.Fence()
+			data["Leash"] = map[string]any{"X": int32(fence[0]), "Y": int32(fence[1]), "Z": int32(fence[2])}
+		}
+	}
+	return data
+}
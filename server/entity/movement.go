@@ -12,6 +12,9 @@ import (
 type MovementComputer struct {
 	Gravity, Drag     float64
 	DragBeforeGravity bool
+	// NoClip specifies if the entity moves through blocks without colliding
+	// with them.
+	NoClip bool
 
 	onGround bool
 }
@@ -68,7 +71,12 @@ func (c *MovementComputer) TickMovement(e world.Entity, pos, vel mgl64.Vec3, rot
 
 	velBefore := vel
 	vel = c.applyHorizontalForces(w, pos, c.applyVerticalForces(vel))
-	dPos, vel := c.checkCollision(e, pos, vel)
+	dPos := vel
+	if !c.NoClip {
+		dPos, vel = c.checkCollision(e, pos, vel)
+	} else {
+		c.onGround = false
+	}
 
 	return &Movement{v: viewers, e: e,
 		pos: pos.Add(dPos), vel: vel, dpos: dPos, dvel: vel.Sub(velBefore),
@@ -0,0 +1,154 @@
+package entity
+
+import (
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player/dialogue"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NPCConfig holds the settings of an NPC. NPCConfig.New may be called to
+// create a new NPC with this config.
+type NPCConfig struct {
+	// Name is the name of the NPC, which is shown above its head.
+	Name string
+	// Skin is the index of the skin of the NPC in the list of NPC skins built
+	// into the game.
+	Skin int
+	// Rotation is the rotation that the NPC faces.
+	Rotation cube.Rotation
+	// Dialogue returns the dialogue.Dialogue shown to a dialogue.Submitter
+	// that interacts with the NPC. It may return a different Dialogue for every
+	// Submitter, for example depending on the progress of the Submitter in a
+	// quest. If false is returned, no Dialogue is shown.
+	Dialogue func(s dialogue.Submitter) (dialogue.Dialogue, bool)
+}
+
+// New creates a new NPC with the settings of the NPCConfig at the position
+// passed.
+func (conf NPCConfig) New(pos mgl64.Vec3) *NPC {
+	return &NPC{conf: conf, pos: pos}
+}
+
+// NPC is a non-player character that shows a dialogue.Dialogue to players
+// interacting with it. NPCs do not move and cannot be hurt. They are not
+// saved in the world, so they should be spawned again when the server starts.
+type NPC struct {
+	pos mgl64.Vec3
+
+	mu   sync.Mutex
+	conf NPCConfig
+}
+
+// Type returns NPCType.
+func (n *NPC) Type() world.EntityType {
+	return NPCType{}
+}
+
+// Position returns the position of the NPC.
+func (n *NPC) Position() mgl64.Vec3 {
+	return n.pos
+}
+
+// Rotation returns the rotation of the NPC, as set in its NPCConfig.
+func (n *NPC) Rotation() cube.Rotation {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.conf.Rotation
+}
+
+// World returns the world of the NPC.
+func (n *NPC) World() *world.World {
+	w, _ := world.OfEntity(n)
+	return w
+}
+
+// NameTag returns the name of the NPC, which is shown above its head.
+func (n *NPC) NameTag() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.conf.Name
+}
+
+// SetNameTag changes the name of the NPC, which is shown above its head and
+// in its dialogue.
+func (n *NPC) SetNameTag(s string) {
+	n.mu.Lock()
+	n.conf.Name = s
+	n.mu.Unlock()
+	n.updateState()
+}
+
+// SkinIndex returns the index of the skin of the NPC in the list of NPC
+// skins built into the game.
+func (n *NPC) SkinIndex() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.conf.Skin
+}
+
+// SetSkinIndex changes the skin of the NPC to the skin with the index passed
+// in the list of NPC skins built into the game.
+func (n *NPC) SetSkinIndex(skin int) {
+	n.mu.Lock()
+	n.conf.Skin = skin
+	n.mu.Unlock()
+	n.updateState()
+}
+
+// Interact shows the dialogue.Dialogue of the NPC to the user, if the user
+// is a dialogue.Submitter and the NPC has a Dialogue for it.
+func (n *NPC) Interact(user item.User, _ *item.UseContext) bool {
+	s, ok := user.(dialogue.Submitter)
+	if !ok {
+		return false
+	}
+	n.mu.Lock()
+	f := n.conf.Dialogue
+	n.mu.Unlock()
+	if f == nil {
+		return false
+	}
+	d, ok := f(s)
+	if !ok {
+		return false
+	}
+	s.SendDialogue(d, n)
+	return true
+}
+
+// updateState updates the state of the NPC for all viewers.
+func (n *NPC) updateState() {
+	w := n.World()
+	if w == nil {
+		return
+	}
+	for _, v := range w.Viewers(n.pos) {
+		v.ViewEntityState(n)
+	}
+}
+
+// Close closes the NPC, removing it from the world.
+func (n *NPC) Close() error {
+	if w := n.World(); w != nil {
+		w.RemoveEntity(n)
+	}
+	return nil
+}
+
+// NPCType is a world.EntityType implementation for NPC.
+type NPCType struct{}
+
+func (NPCType) EncodeEntity() string { return "minecraft:npc" }
+func (NPCType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 2.1, 0.3)
+}
+
+// DecodeNBT returns nil, as NPCs are not saved in the world.
+func (NPCType) DecodeNBT(map[string]any) world.Entity { return nil }
+
+// EncodeNBT returns nil, as the dialogue of an NPC cannot be saved.
+func (NPCType) EncodeNBT(world.Entity) map[string]any { return nil }
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewPig creates a new adult pig at the position passed.
+func NewPig(pos mgl64.Vec3) *Mob {
+	return newPig(pos, false)
+}
+
+// NewBabyPig creates a new baby pig at the position passed.
+func NewBabyPig(pos mgl64.Vec3) *Mob {
+	return newPig(pos, true)
+}
+
+// newPig creates a new pig that is either an adult or a baby.
+func newPig(pos mgl64.Vec3, baby bool) *Mob {
+	conf := pigConf
+	conf.Behaviour = AnimalBehaviourConfig{
+		BreedingItem: func(it world.Item) bool {
+			switch it.(type) {
+			case block.Carrot, block.Potato, item.Beetroot:
+				return true
+			}
+			return false
+		},
+		Offspring: NewBabyPig,
+	}.New(baby)
+	return conf.New(PigType{}, pos)
+}
+
+var pigConf = MobConfig{
+	MaxHealth:  10,
+	Speed:      0.1,
+	Gravity:    0.08,
+	Drag:       0.02,
+	Experience: 3,
+	LootTable:  "entities/pig",
+	Leashable:  true,
+}
+
+// PigType is a world.EntityType implementation for Pig.
+type PigType struct{}
+
+func (PigType) EncodeEntity() string { return "minecraft:pig" }
+func (PigType) BBox(e world.Entity) cube.BBox {
+	return scaledBBox(e, cube.Box(-0.45, 0, -0.45, 0.45, 0.9, 0.45))
+}
+
+func (PigType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeAnimalNBT(newPig(nbtconv.Vec3(m, "Pos"), false), m)
+}
+
+func (PigType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeAnimalNBT(e.(*Mob))
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/potion"
 	"github.com/df-mc/dragonfly/server/world"
@@ -17,10 +18,12 @@ import (
 // ProjectileBehaviourConfig.New() creates a ProjectileBehaviour using these
 // settings.
 type ProjectileBehaviourConfig struct {
-	// Gravity is the amount of Y velocity subtracted every tick.
+	// Gravity is the amount of Y velocity subtracted every tick. It is not
+	// applied if Handler implements ProjectileMotion.
 	Gravity float64
 	// Drag is used to reduce all axes of the velocity every tick. Velocity is
-	// multiplied with (1-Drag) every tick.
+	// multiplied with (1-Drag) every tick. It is not applied if Handler
+	// implements ProjectileMotion.
 	Drag float64
 	// Damage specifies the base damage dealt by the Projectile. If set to a
 	// negative number, entities hit are not hurt at all and are not knocked
@@ -74,6 +77,13 @@ type ProjectileBehaviourConfig struct {
 	// PickupItem is the item that is given to a player when it picks up this
 	// projectile. If left as an empty item.Stack, no item is given upon pickup.
 	PickupItem item.Stack
+	// Handler is a ProjectileHandler that is called when the projectile hits
+	// a block or an entity or when it is picked up. It may be used to change or
+	// cancel the default behaviour of the projectile. If Handler implements
+	// ProjectileMotion, it also controls the movement of the projectile. If
+	// left nil, the projectile behaves as configured in
+	// ProjectileBehaviourConfig.
+	Handler ProjectileHandler
 }
 
 // New creates a new ProjectileBehaviour using conf. The owner passed may be nil
@@ -82,6 +92,9 @@ func (conf ProjectileBehaviourConfig) New(owner world.Entity) *ProjectileBehavio
 	if conf.ParticleCount == 0 && conf.Particle != nil {
 		conf.ParticleCount = 1
 	}
+	if conf.Handler == nil {
+		conf.Handler = NopProjectileHandler{}
+	}
 	return &ProjectileBehaviour{conf: conf, owner: owner, mc: &MovementComputer{
 		Gravity:           conf.Gravity,
 		Drag:              conf.Drag,
@@ -161,14 +174,24 @@ func (lt *ProjectileBehaviour) Tick(e *Ent) *Movement {
 	if lt.conf.Sound != nil {
 		w.PlaySound(result.Position(), lt.conf.Sound)
 	}
+	w.EmitGameEvent(world.GameEventProjectileLand, result.Position(), lt.owner)
 
+	ctx := event.C()
 	switch r := result.(type) {
 	case trace.EntityResult:
+		if lt.conf.Handler.HandleHitEntity(ctx, e, r.Entity()); ctx.Cancelled() {
+			break
+		}
 		if l, ok := r.Entity().(Living); ok && lt.conf.Damage >= 0 {
 			lt.hitEntity(l, e, before, vel)
+		} else if a, ok := r.Entity().(Attackable); ok {
+			a.Attack(ProjectileDamageSource{Projectile: e, Owner: lt.owner})
 		}
 	case trace.BlockResult:
 		bpos := r.BlockPosition()
+		if lt.conf.Handler.HandleHitBlock(ctx, e, bpos, r.Face()); ctx.Cancelled() {
+			break
+		}
 		if t, ok := w.Block(bpos).(block.TNT); ok && e.OnFireDuration() > 0 {
 			t.Ignite(bpos, w, e)
 		}
@@ -221,6 +244,10 @@ func (lt *ProjectileBehaviour) tryPickup(e *Ent) {
 		if !ok {
 			continue
 		}
+		ctx := event.C()
+		if lt.conf.Handler.HandlePickup(ctx, e, collector); ctx.Cancelled() {
+			continue
+		}
 		// A collector was within range to pick up the entity.
 		lt.close = true
 		for _, viewer := range w.Viewers(e.pos) {
@@ -287,7 +314,12 @@ func (lt *ProjectileBehaviour) tickMovement(e *Ent) (*Movement, trace.Result) {
 	viewers := w.Viewers(pos)
 
 	velBefore := vel
-	vel = lt.mc.applyHorizontalForces(w, pos, lt.mc.applyVerticalForces(vel))
+	motion, custom := lt.conf.Handler.(ProjectileMotion)
+	if custom {
+		vel = motion.Motion(w, pos, vel)
+	} else {
+		vel = lt.mc.applyHorizontalForces(w, pos, lt.mc.applyVerticalForces(vel))
+	}
 	rot := cube.Rotation{
 		mgl64.RadToDeg(math.Atan2(vel[0], vel[2])),
 		mgl64.RadToDeg(math.Atan2(vel[1], math.Hypot(vel[0], vel[2]))),
@@ -301,9 +333,11 @@ func (lt *ProjectileBehaviour) tickMovement(e *Ent) (*Movement, trace.Result) {
 	if !mgl64.FloatEqual(end.Sub(pos).LenSqr(), 0) {
 		if hit, ok = trace.Perform(pos, end, w, e.Type().BBox(e).Grow(1.0), lt.ignores(e)); ok {
 			if _, ok := hit.(trace.BlockResult); ok {
-				// Undo the gravity because the velocity as a result of gravity
-				// at the point of collision should be 0.
-				vel[1] = (vel[1] + lt.mc.Gravity) / (1 - lt.mc.Drag)
+				if !custom {
+					// Undo the gravity because the velocity as a result of gravity
+					// at the point of collision should be 0.
+					vel[1] = (vel[1] + lt.mc.Gravity) / (1 - lt.mc.Drag)
+				}
 				x, y, z := vel.Mul(lt.conf.BlockCollisionVelocityMultiplier).Elem()
 				// Calculate multipliers for all coordinates: 1 for the ones that
 				// weren't on the same axis as the one collided with, -1 for the one
@@ -321,12 +355,13 @@ func (lt *ProjectileBehaviour) tickMovement(e *Ent) (*Movement, trace.Result) {
 }
 
 // ignores returns a function to ignore entities in trace.Perform that are
-// either a spectator, not living, the entity itself or its owner in the first
-// 5 ticks.
+// either a spectator, neither living nor attackable, the entity itself or its
+// owner in the first 5 ticks.
 func (lt *ProjectileBehaviour) ignores(e *Ent) func(other world.Entity) bool {
 	return func(other world.Entity) (ignored bool) {
 		g, ok := other.(interface{ GameMode() world.GameMode })
 		_, living := other.(Living)
-		return (ok && !g.GameMode().HasCollision()) || e == other || !living || (e.age < time.Second/4 && lt.owner == other)
+		_, attackable := other.(Attackable)
+		return (ok && !g.GameMode().HasCollision()) || e == other || (!living && !attackable) || (e.age < time.Second/4 && lt.owner == other)
 	}
 }
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// ProjectileHandler handles the events of a projectile created using a
+// ProjectileBehaviourConfig. Implementations may be used to create custom
+// projectiles without reimplementing the movement and collision of
+// projectiles. NopProjectileHandler may be embedded to implement only some of
+// the methods. A ProjectileHandler may additionally implement ProjectileMotion
+// to control the movement of the projectile.
+type ProjectileHandler interface {
+	// HandleHitEntity is called when the projectile hits an entity, before the
+	// entity is damaged and knocked back. ctx.Cancel() may be called to
+	// prevent the default behaviour. The projectile is removed regardless.
+	HandleHitEntity(ctx *event.Context, e *Ent, target world.Entity)
+	// HandleHitBlock is called when the projectile hits the face of the block
+	// at the position passed. ctx.Cancel() may be called to prevent the
+	// default behaviour, such as igniting TNT or the projectile getting stuck
+	// in the block. The projectile is removed if cancelled.
+	HandleHitBlock(ctx *event.Context, e *Ent, pos cube.Pos, face cube.Face)
+	// HandlePickup is called when a Collector attempts to pick up the
+	// projectile after it got stuck in a block. ctx.Cancel() may be called to
+	// prevent the Collector from picking up the projectile.
+	HandlePickup(ctx *event.Context, e *Ent, collector Collector)
+}
+
+// ProjectileMotion may be implemented by a ProjectileHandler to control the
+// movement of a projectile, for example to create projectiles that home in on
+// a target or that slow down faster in water. If implemented, the Gravity and
+// Drag of ProjectileBehaviourConfig are not applied to the projectile. Its
+// collision with blocks and entities is still handled as usual.
+type ProjectileMotion interface {
+	// Motion returns the velocity of the projectile in the current tick,
+	// based on its position and its velocity in the previous tick. Motion is
+	// called while the projectile is being ticked, so methods of the
+	// projectile must not be called from it.
+	Motion(w *world.World, pos, vel mgl64.Vec3) mgl64.Vec3
+}
+
+// NopProjectileHandler implements the ProjectileHandler interface but does
+// not execute any code when an event is called.
+type NopProjectileHandler struct{}
+
+// Compile time check to make sure NopProjectileHandler implements ProjectileHandler.
+var _ ProjectileHandler = (*NopProjectileHandler)(nil)
+
+func (NopProjectileHandler) HandleHitEntity(*event.Context, *Ent, world.Entity)       {}
+func (NopProjectileHandler) HandleHitBlock(*event.Context, *Ent, cube.Pos, cube.Face) {}
+func (NopProjectileHandler) HandlePickup(*event.Context, *Ent, Collector)             {}
@@ -13,21 +13,40 @@ import (
 // DefaultRegistry is a world.EntityRegistry that registers all default entities
 // implemented by Dragonfly.
 var DefaultRegistry = conf.New([]world.EntityType{
+	AllayType{},
 	AreaEffectCloudType{},
 	ArrowType{},
 	BottleOfEnchantingType{},
+	ChickenType{},
+	CowType{},
+	DragonFireballType{},
 	EggType{},
+	EnderCrystalType{},
+	EnderDragonType{},
 	EnderPearlType{},
 	ExperienceOrbType{},
+	EyeOfEnderType{},
 	FallingBlockType{},
 	FireworkType{},
 	ItemType{},
+	LeashKnotType{},
 	LightningType{},
 	LingeringPotionType{},
+	MagmaCubeType{},
+	PigType{},
+	SheepType{},
+	ShulkerBulletType{},
+	ShulkerType{},
+	SlimeType{},
 	SnowballType{},
 	SplashPotionType{},
 	TNTType{},
 	TextType{},
+	VillagerType{},
+	WardenType{},
+	WitherSkullDangerousType{},
+	WitherSkullType{},
+	WitherType{},
 })
 
 var conf = world.EntityRegistryConfig{
@@ -69,6 +88,9 @@ var conf = world.EntityRegistryConfig{
 		e.vel = vel
 		return e
 	},
+	EyeOfEnder: func(pos, target mgl64.Vec3) world.Entity {
+		return NewEyeOfEnder(pos, target)
+	},
 	Firework: func(pos mgl64.Vec3, rot cube.Rotation, attached bool, firework world.Item, owner world.Entity) world.Entity {
 		return NewFireworkAttached(pos, rot, firework.(item.Firework), owner, attached)
 	},
@@ -90,4 +112,13 @@ var conf = world.EntityRegistryConfig{
 	Lightning: func(pos mgl64.Vec3) world.Entity {
 		return NewLightning(pos)
 	},
+	Wither: func(pos mgl64.Vec3) world.Entity {
+		return NewWither(pos)
+	},
+	EnderCrystal: func(pos mgl64.Vec3, showBase bool) world.Entity {
+		return NewEnderCrystal(pos, showBase)
+	},
+	LeashKnot: func(pos cube.Pos) world.Entity {
+		return NewLeashKnot(pos)
+	},
 }
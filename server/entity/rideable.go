@@ -0,0 +1,193 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Rideable is a world.Entity that may be ridden by Riders, such as boats,
+// minecarts, horses or vehicles defined by plugins. The seats of a Rideable
+// and the Riders occupying them are held by its Seats.
+type Rideable interface {
+	world.Entity
+	// Seats returns the Seats of the Rideable. Seats may return nil if the
+	// Rideable currently cannot be ridden.
+	Seats() *Seats
+}
+
+// Drivable is a Rideable that may be controlled by the Rider in its first
+// seat, the driver. The input of the driver is forwarded to the Drivable
+// every tick.
+type Drivable interface {
+	Rideable
+	// Drive is called every tick with the input of the Rider in the first
+	// seat of the Drivable.
+	Drive(driver Rider, input RiderInput)
+}
+
+// RiderInput holds the movement input of a Rider, such as a player pressing
+// the movement keys while sitting in a boat.
+type RiderInput struct {
+	// Forward and Strafe are the forward and sideways movement input of the
+	// Rider, ranging from -1 to 1. A positive Strafe value means movement to
+	// the left.
+	Forward, Strafe float64
+	// Jumping and Sneaking specify if the Rider is pressing the jump and
+	// sneak keys respectively.
+	Jumping, Sneaking bool
+	// Yaw and Pitch are the rotation of the Rider in degrees.
+	Yaw, Pitch float64
+}
+
+// Rider is a world.Entity that is able to ride a Rideable.
+type Rider interface {
+	world.Entity
+	// Mount makes the Rider ride the Rideable passed, occupying the first free
+	// seat. Mount returns false if the Rider is already riding or if the
+	// Rideable has no free seats.
+	Mount(r Rideable) bool
+	// Dismount makes the Rider stop riding its current Rideable, if any.
+	Dismount()
+	// Riding returns the Rideable currently ridden by the Rider. If the Rider
+	// is not riding anything, false is returned.
+	Riding() (Rideable, bool)
+}
+
+// Seats holds the seats of a Rideable and the Riders occupying them. Each
+// seat has an offset relative to the position of the Rideable. The first
+// seat is the seat of the driver. Seats is safe for concurrent use. The
+// methods of a nil *Seats behave as if there are no seats.
+type Seats struct {
+	mu      sync.Mutex
+	offsets []mgl64.Vec3
+	riders  []Rider
+}
+
+// NewSeats creates Seats with a seat for each of the offsets passed. The
+// offsets are relative to the position of the Rideable and are rotated with
+// its yaw.
+func NewSeats(offsets ...mgl64.Vec3) *Seats {
+	return &Seats{offsets: offsets, riders: make([]Rider, len(offsets))}
+}
+
+// Len returns the number of seats.
+func (s *Seats) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.offsets)
+}
+
+// Offset returns the offset of the seat passed relative to the position of
+// the Rideable, before rotating it with the yaw of the Rideable.
+func (s *Seats) Offset(seat int) mgl64.Vec3 {
+	if seat < 0 || seat >= s.Len() {
+		return mgl64.Vec3{}
+	}
+	return s.offsets[seat]
+}
+
+// Rider returns the Rider occupying the seat passed. If the seat is empty,
+// false is returned.
+func (s *Seats) Rider(seat int) (Rider, bool) {
+	if seat < 0 || seat >= s.Len() {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.riders[seat], s.riders[seat] != nil
+}
+
+// Driver returns the Rider occupying the first seat. If the seat is empty,
+// false is returned.
+func (s *Seats) Driver() (Rider, bool) {
+	return s.Rider(0)
+}
+
+// Riders returns all Riders currently occupying a seat.
+func (s *Seats) Riders() []Rider {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	riders := make([]Rider, 0, len(s.riders))
+	for _, r := range s.riders {
+		if r != nil {
+			riders = append(riders, r)
+		}
+	}
+	return riders
+}
+
+// Seat returns the seat occupied by the world.Entity passed. If it does not
+// occupy any seat, false is returned.
+func (s *Seats) Seat(e world.Entity) (int, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seat := slices.IndexFunc(s.riders, func(r Rider) bool { return r != nil && world.Entity(r) == e })
+	return seat, seat != -1
+}
+
+// Sit makes the Rider passed occupy the first free seat and returns it. Sit
+// is called by implementations of Rider.Mount and does not update the Rider
+// itself. If no seat is free, or if the Rider already occupies a seat, false
+// is returned.
+func (s *Seats) Sit(r Rider) (int, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.IndexFunc(s.riders, func(other Rider) bool { return other == r }) != -1 {
+		return 0, false
+	}
+	seat := slices.IndexFunc(s.riders, func(other Rider) bool { return other == nil })
+	if seat == -1 {
+		return 0, false
+	}
+	s.riders[seat] = r
+	return seat, true
+}
+
+// Leave frees the seat occupied by the Rider passed. Leave is called by
+// implementations of Rider.Dismount and does not update the Rider itself.
+func (s *Seats) Leave(r Rider) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seat := slices.IndexFunc(s.riders, func(other Rider) bool { return other == r }); seat != -1 {
+		s.riders[seat] = nil
+	}
+}
+
+// DismountAll dismounts all Riders occupying a seat. It should be called when
+// the Rideable is removed from its world.
+func (s *Seats) DismountAll() {
+	for _, r := range s.Riders() {
+		r.Dismount()
+	}
+}
+
+// SeatPosition returns the position of a seat of the Rideable passed, taking
+// into account the rotation of the Rideable.
+func SeatPosition(r Rideable, seat int) mgl64.Vec3 {
+	offset := r.Seats().Offset(seat)
+	yaw := mgl64.DegToRad(r.Rotation().Yaw())
+	sin, cos := math.Sincos(yaw)
+	return r.Position().Add(mgl64.Vec3{offset[0]*cos - offset[2]*sin, offset[1], offset[0]*sin + offset[2]*cos})
+}
+
+// DismountPosition returns the position at which a Rider is placed after
+// dismounting the Rideable passed: On top of the Rideable.
+func DismountPosition(r Rideable) mgl64.Vec3 {
+	return r.Position().Add(mgl64.Vec3{0, r.Type().BBox(r).Height()})
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewSheep creates a new adult sheep at the position passed.
+func NewSheep(pos mgl64.Vec3) *Mob {
+	return newSheep(pos, false)
+}
+
+// NewBabySheep creates a new baby sheep at the position passed.
+func NewBabySheep(pos mgl64.Vec3) *Mob {
+	return newSheep(pos, true)
+}
+
+// newSheep creates a new sheep that is either an adult or a baby.
+func newSheep(pos mgl64.Vec3, baby bool) *Mob {
+	conf := sheepConf
+	conf.Behaviour = AnimalBehaviourConfig{
+		BreedingItem: func(it world.Item) bool {
+			_, ok := it.(item.Wheat)
+			return ok
+		},
+		Offspring: NewBabySheep,
+	}.New(baby)
+	return conf.New(SheepType{}, pos)
+}
+
+var sheepConf = MobConfig{
+	MaxHealth:  8,
+	Speed:      0.1,
+	Gravity:    0.08,
+	Drag:       0.02,
+	Experience: 3,
+	LootTable:  "entities/sheep",
+	Leashable:  true,
+	Drops: func(m *Mob, _ world.DamageSource) []item.Stack {
+		if m.Behaviour().(*AnimalBehaviour).Baby() {
+			return nil
+		}
+		return []item.Stack{item.NewStack(block.Wool{Colour: item.ColourWhite()}, 1)}
+	},
+}
+
+// SheepType is a world.EntityType implementation for Sheep.
+type SheepType struct{}
+
+func (SheepType) EncodeEntity() string { return "minecraft:sheep" }
+func (SheepType) BBox(e world.Entity) cube.BBox {
+	return scaledBBox(e, cube.Box(-0.45, 0, -0.45, 0.45, 1.3, 0.45))
+}
+
+func (SheepType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeAnimalNBT(newSheep(nbtconv.Vec3(m, "Pos"), false), m)
+}
+
+func (SheepType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeAnimalNBT(e.(*Mob))
+}
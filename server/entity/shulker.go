@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewShulker creates a new shulker at the position passed. Shulkers are found
+// in end cities, where they hide in their shells and shoot shulker bullets at
+// players that come close.
+func NewShulker(pos mgl64.Vec3) *Mob {
+	conf := shulkerConf
+	conf.Behaviour = ShulkerBehaviourConfig{}.New()
+	return conf.New(ShulkerType{}, pos)
+}
+
+var shulkerConf = MobConfig{
+	MaxHealth:           30,
+	KnockBackResistance: 1,
+	LootTable:           "entities/shulker",
+	Experience:          5,
+}
+
+// ShulkerType is a world.EntityType implementation for shulkers.
+type ShulkerType struct{}
+
+func (ShulkerType) EncodeEntity() string { return "minecraft:shulker" }
+func (ShulkerType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.5, 0, -0.5, 0.5, 1, 0.5)
+}
+
+func (ShulkerType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(NewShulker(nbtconv.Vec3(m, "Pos")), m)
+}
+
+func (ShulkerType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
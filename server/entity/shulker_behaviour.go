@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"sync"
+)
+
+// ShulkerBehaviourConfig holds optional parameters for a ShulkerBehaviour.
+type ShulkerBehaviourConfig struct {
+	// TargetRange is the range within which the shulker finds players to
+	// shoot shulker bullets at. If 0, a range of 16 blocks is used.
+	TargetRange float64
+}
+
+// New creates a ShulkerBehaviour using the parameters in conf.
+func (conf ShulkerBehaviourConfig) New() *ShulkerBehaviour {
+	if conf.TargetRange == 0 {
+		conf.TargetRange = 16
+	}
+	return &ShulkerBehaviour{conf: conf, attackDelay: 20}
+}
+
+// ShulkerBehaviour implements the behaviour of shulkers. Shulkers stay at the
+// position they were spawned at, hiding in their shell. Once a player comes
+// close, the shulker opens its shell and periodically shoots shulker bullets
+// at the player. Projectiles bounce off the shell of a closed shulker.
+type ShulkerBehaviour struct {
+	conf ShulkerBehaviourConfig
+
+	mu          sync.Mutex
+	anchor      mgl64.Vec3
+	anchored    bool
+	peek        uint8
+	attackDelay int
+}
+
+// Peek returns how far the shulker has opened its shell, ranging from 0
+// (closed) to 100 (fully open).
+func (s *ShulkerBehaviour) Peek() uint8 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peek
+}
+
+// Variant returns 16, which is the variant of shulkers that were not dyed.
+func (s *ShulkerBehaviour) Variant() int32 {
+	return 16
+}
+
+// Hurt makes a shulker with a closed shell immune to projectiles.
+func (s *ShulkerBehaviour) Hurt(_ *Mob, dmg float64, src world.DamageSource) (float64, bool) {
+	if _, ok := src.(ProjectileDamageSource); ok && s.Peek() == 0 {
+		return 0, false
+	}
+	return dmg, true
+}
+
+// Tick keeps the shulker at its position and opens its shell to shoot at
+// nearby players.
+func (s *ShulkerBehaviour) Tick(m *Mob) {
+	s.mu.Lock()
+	if !s.anchored {
+		s.anchor, s.anchored = m.Position(), true
+	}
+	anchor := s.anchor
+	s.mu.Unlock()
+
+	// Shulkers never move from the position they are attached to.
+	m.SetVelocity(mgl64.Vec3{})
+	if m.Position().Sub(anchor).LenSqr() > 0.0001 {
+		m.Teleport(anchor)
+	}
+
+	target, ok := nearestEntity(m, s.conf.TargetRange, hostileTargetable)
+	if !ok {
+		if rand.Intn(40) == 0 {
+			// Shulkers without a target occasionally peek out of their shell.
+			s.setPeek(m, uint8(rand.Intn(2)*30))
+		}
+		return
+	}
+	s.setPeek(m, 100)
+	m.LookAt(EyePosition(target))
+
+	s.mu.Lock()
+	s.attackDelay--
+	attack := s.attackDelay <= 0
+	if attack {
+		s.attackDelay = 20 + rand.Intn(90)
+	}
+	s.mu.Unlock()
+	if attack {
+		m.World().AddEntity(NewShulkerBullet(m.Position().Add(mgl64.Vec3{0, 1.2}), m, target))
+	}
+}
+
+// setPeek changes how far the shulker has opened its shell, updating the
+// state of the shulker for viewers if it changed.
+func (s *ShulkerBehaviour) setPeek(m *Mob, peek uint8) {
+	s.mu.Lock()
+	changed := s.peek != peek
+	s.peek = peek
+	s.mu.Unlock()
+	if changed {
+		m.updateState()
+	}
+}
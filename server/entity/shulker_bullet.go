@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"time"
+)
+
+// NewShulkerBullet creates a shulker bullet at the position passed, fired by
+// the owner passed. The bullet homes in on the target passed, which may be
+// nil for a bullet that flies straight on.
+func NewShulkerBullet(pos mgl64.Vec3, owner, target world.Entity) *Ent {
+	b := &ShulkerBulletBehaviour{target: target, projectile: shulkerBulletConf.New(owner)}
+	return Config{Behaviour: b}.New(ShulkerBulletType{}, pos)
+}
+
+var shulkerBulletConf = ProjectileBehaviourConfig{
+	Damage: -1,
+	Hit:    hitShulkerBullet,
+}
+
+// shulkerBulletLifetime is the duration after which a shulker bullet that has
+// not hit anything disappears.
+const shulkerBulletLifetime = time.Second * 15
+
+// ShulkerBulletBehaviour implements the behaviour of a shulker bullet, which
+// is a projectile that follows its target.
+type ShulkerBulletBehaviour struct {
+	projectile *ProjectileBehaviour
+	target     world.Entity
+}
+
+// Owner returns the shulker that fired the shulker bullet.
+func (b *ShulkerBulletBehaviour) Owner() world.Entity {
+	return b.projectile.Owner()
+}
+
+// Tick steers the shulker bullet towards its target and moves it.
+func (b *ShulkerBulletBehaviour) Tick(e *Ent) *Movement {
+	if e.Age() > shulkerBulletLifetime {
+		_ = e.Close()
+		return nil
+	}
+	if l, ok := b.target.(Living); ok && !l.Dead() {
+		if _, ok := world.OfEntity(l); ok {
+			e.mu.Lock()
+			if diff := EyePosition(l).Sub(e.pos); diff.LenSqr() > 0 {
+				e.vel = e.vel.Add(diff.Normalize().Mul(0.3).Sub(e.vel).Mul(0.2))
+			}
+			e.mu.Unlock()
+		}
+	}
+	return b.projectile.Tick(e)
+}
+
+// hitShulkerBullet hurts the entity hit by a shulker bullet and makes it
+// levitate.
+func hitShulkerBullet(e *Ent, target trace.Result) {
+	r, ok := target.(trace.EntityResult)
+	if !ok {
+		return
+	}
+	if l, ok := r.Entity().(Living); ok {
+		src := ProjectileDamageSource{Projectile: e, Owner: e.Behaviour().(*ShulkerBulletBehaviour).Owner()}
+		if _, vulnerable := l.Hurt(4, src); vulnerable {
+			l.AddEffect(effect.New(effect.Levitation{}, 1, time.Second*10))
+		}
+	}
+}
+
+// ShulkerBulletType is a world.EntityType implementation for shulker bullets.
+type ShulkerBulletType struct{}
+
+func (ShulkerBulletType) EncodeEntity() string { return "minecraft:shulker_bullet" }
+func (ShulkerBulletType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.15625, 0, -0.15625, 0.15625, 0.3125, 0.15625)
+}
+
+func (ShulkerBulletType) DecodeNBT(m map[string]any) world.Entity {
+	b := NewShulkerBullet(nbtconv.Vec3(m, "Pos"), nil, nil)
+	b.vel = nbtconv.Vec3(m, "Motion")
+	return b
+}
+
+func (ShulkerBulletType) EncodeNBT(e world.Entity) map[string]any {
+	b := e.(*Ent)
+	return map[string]any{
+		"Pos":    nbtconv.Vec3ToFloat32Slice(b.Position()),
+		"Motion": nbtconv.Vec3ToFloat32Slice(b.Velocity()),
+	}
+}
@@ -0,0 +1,171 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+)
+
+// NewSlime creates a new slime of the size passed at a position. Vanilla
+// slimes have a size of 1, 2 or 4. Slimes larger than size 1 split into
+// smaller slimes when they die.
+func NewSlime(pos mgl64.Vec3, size int) *Mob {
+	conf := slimeConf(size)
+	conf.Behaviour = SlimeBehaviourConfig{
+		Size: size,
+		Damage: func(size int) float64 {
+			if size <= 1 {
+				// Small slimes do not deal damage.
+				return 0
+			}
+			return float64(size)
+		},
+		Offspring: NewSlime,
+	}.New()
+	if size <= 1 {
+		conf.LootTable = "entities/slime"
+	}
+	return conf.New(SlimeType{}, pos)
+}
+
+// NewMagmaCube creates a new magma cube of the size passed at a position.
+// Vanilla magma cubes have a size of 1, 2 or 4. Magma cubes are immune to
+// fire, jump higher than slimes and split into smaller magma cubes when they
+// die.
+func NewMagmaCube(pos mgl64.Vec3, size int) *Mob {
+	conf := slimeConf(size)
+	conf.Behaviour = SlimeBehaviourConfig{
+		Size: size,
+		Damage: func(size int) float64 {
+			return float64(size + 2)
+		},
+		JumpVelocity: func(size int) float64 {
+			return 0.42 + float64(size)*0.1
+		},
+		FireImmune: true,
+		Offspring:  NewMagmaCube,
+	}.New()
+	if size > 1 {
+		conf.LootTable = "entities/magma_cube"
+	}
+	return conf.New(MagmaCubeType{}, pos)
+}
+
+// slimeConf returns the MobConfig shared by slimes and magma cubes of the size
+// passed.
+func slimeConf(size int) MobConfig {
+	size = max(size, 1)
+	return MobConfig{
+		MaxHealth:   float64(size * size),
+		Speed:       0.2 + float64(size)*0.05,
+		Gravity:     0.08,
+		Drag:        0.02,
+		Experience:  size,
+		Leashable:   true,
+		Despawnable: true,
+	}
+}
+
+// SlimeChunk checks if the chunk at the chunk coordinates passed is a slime
+// chunk. Slimes spawn in slime chunks regardless of the biome, below y=40.
+// Similarly to Bedrock Edition, slime chunks do not depend on the world seed.
+func SlimeChunk(x, z int32) bool {
+	return mt19937First(uint32(x)*0x1f1f1f1f^uint32(z))%10 == 0
+}
+
+// mt19937First returns the first value generated by a 32-bit Mersenne Twister
+// seeded with the seed passed.
+func mt19937First(seed uint32) uint32 {
+	var mt [398]uint32
+	mt[0] = seed
+	for i := uint32(1); i < uint32(len(mt)); i++ {
+		mt[i] = 1812433253*(mt[i-1]^(mt[i-1]>>30)) + i
+	}
+	y := (mt[0] & 0x80000000) | (mt[1] & 0x7fffffff)
+	v := mt[397] ^ (y >> 1)
+	if y&1 != 0 {
+		v ^= 0x9908b0df
+	}
+	v ^= v >> 11
+	v ^= (v << 7) & 0x9d2c5680
+	v ^= (v << 15) & 0xefc60000
+	return v ^ (v >> 18)
+}
+
+// SlimeSpawnable checks if a slime may naturally spawn at the position
+// passed. Slimes spawn on solid blocks in the Overworld, either below y=40 in
+// slime chunks or between y=51 and y=69 in swamps with a low light level.
+func SlimeSpawnable(w *world.World, pos cube.Pos) bool {
+	if w.Dimension() != world.Overworld || !mobSpawnable(w, pos) {
+		return false
+	}
+	if pos.Y() < 40 && SlimeChunk(int32(pos.X()>>4), int32(pos.Z()>>4)) {
+		return true
+	}
+	switch w.Biome(pos).(type) {
+	case biome.Swamp, biome.MangroveSwamp:
+		return pos.Y() > 50 && pos.Y() < 70 && w.Light(pos) <= uint8(rand.Intn(8))
+	}
+	return false
+}
+
+// MagmaCubeSpawnable checks if a magma cube may naturally spawn at the
+// position passed. Magma cubes spawn on solid blocks anywhere in the Nether.
+func MagmaCubeSpawnable(w *world.World, pos cube.Pos) bool {
+	return w.Dimension() == world.Nether && mobSpawnable(w, pos)
+}
+
+// RandomSlimeSize returns a random size for a naturally spawned slime or
+// magma cube: 1, 2 or 4.
+func RandomSlimeSize() int {
+	return 1 << rand.Intn(3)
+}
+
+// mobSpawnable checks if the block at the position passed is free and the
+// block below it is solid, so that a mob may spawn there.
+func mobSpawnable(w *world.World, pos cube.Pos) bool {
+	below := pos.Side(cube.FaceDown)
+	if len(w.Block(pos).Model().BBox(pos, w)) != 0 {
+		return false
+	}
+	return w.Block(below).Model().FaceSolid(below, cube.FaceUp, w)
+}
+
+// SlimeType is a world.EntityType implementation for slimes.
+type SlimeType struct{}
+
+func (SlimeType) EncodeEntity() string          { return "minecraft:slime" }
+func (SlimeType) BBox(e world.Entity) cube.BBox { return slimeBBox(e) }
+
+func (SlimeType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(NewSlime(nbtconv.Vec3(m, "Pos"), int(nbtconv.Uint8(m, "Size"))), m)
+}
+
+func (SlimeType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeSlimeNBT(e.(*Mob))
+}
+
+// MagmaCubeType is a world.EntityType implementation for magma cubes.
+type MagmaCubeType struct{}
+
+func (MagmaCubeType) EncodeEntity() string          { return "minecraft:magma_cube" }
+func (MagmaCubeType) BBox(e world.Entity) cube.BBox { return slimeBBox(e) }
+
+func (MagmaCubeType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(NewMagmaCube(nbtconv.Vec3(m, "Pos"), int(nbtconv.Uint8(m, "Size"))), m)
+}
+
+func (MagmaCubeType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeSlimeNBT(e.(*Mob))
+}
+
+// encodeSlimeNBT encodes a slime or magma cube into an NBT map, including its
+// size.
+func encodeSlimeNBT(m *Mob) map[string]any {
+	data := encodeMobNBT(m)
+	data["Size"] = uint8(m.Behaviour().(*SlimeBehaviour).Size())
+	return data
+}
@@ -0,0 +1,187 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// SlimeBehaviourConfig holds optional parameters for a SlimeBehaviour.
+type SlimeBehaviourConfig struct {
+	// Size is the size of the slime. Vanilla slimes have a size of 1, 2 or 4.
+	// If 0, a size of 1 is used.
+	Size int
+	// Damage returns the damage dealt by a slime of the size passed to
+	// entities it touches. If nil, or if 0 is returned, the slime does not
+	// deal damage.
+	Damage func(size int) float64
+	// JumpVelocity returns the vertical velocity with which a slime of the
+	// size passed jumps. If nil, a velocity of 0.42 is used.
+	JumpVelocity func(size int) float64
+	// FireImmune specifies if the slime is immune to fire damage, such as the
+	// magma cube.
+	FireImmune bool
+	// Offspring creates a new slime of the size passed. It is called when a
+	// slime larger than size 1 dies to split it into smaller slimes. If nil,
+	// the slime does not split.
+	Offspring func(pos mgl64.Vec3, size int) *Mob
+	// TargetRange is the range within which the slime finds players to jump
+	// towards. If 0, a range of 16 blocks is used.
+	TargetRange float64
+}
+
+// New creates a SlimeBehaviour using the parameters in conf.
+func (conf SlimeBehaviourConfig) New() *SlimeBehaviour {
+	if conf.Size <= 0 {
+		conf.Size = 1
+	}
+	if conf.TargetRange == 0 {
+		conf.TargetRange = 16
+	}
+	return &SlimeBehaviour{conf: conf, jumpDelay: rand.Intn(20) + 10}
+}
+
+// SlimeBehaviour implements the behaviour of slimes and magma cubes. They
+// move by bouncing around, jump towards nearby players to damage them and
+// split into several smaller slimes when they die.
+type SlimeBehaviour struct {
+	conf SlimeBehaviourConfig
+
+	mu        sync.Mutex
+	jumpDelay int
+	airborne  bool
+}
+
+// Size returns the size of the slime.
+func (s *SlimeBehaviour) Size() int {
+	return s.conf.Size
+}
+
+// Scale returns the scale of the slime, which is equal to its size.
+func (s *SlimeBehaviour) Scale() float64 {
+	return float64(s.conf.Size)
+}
+
+// Variant returns the size of the slime, which the client uses as variant.
+func (s *SlimeBehaviour) Variant() int32 {
+	return int32(s.conf.Size)
+}
+
+// Hurt makes the slime immune to fall damage and, if it is FireImmune, to
+// fire.
+func (s *SlimeBehaviour) Hurt(_ *Mob, dmg float64, src world.DamageSource) (float64, bool) {
+	if _, ok := src.(FallDamageSource); ok || (src.Fire() && s.conf.FireImmune) {
+		return 0, false
+	}
+	return dmg, true
+}
+
+// Death splits the slime into two to four slimes of half its size, if its
+// size is larger than 1.
+func (s *SlimeBehaviour) Death(m *Mob, _ world.DamageSource) {
+	if s.conf.Size <= 1 || s.conf.Offspring == nil {
+		return
+	}
+	w, pos := m.World(), m.Position()
+	size, n := s.conf.Size/2, rand.Intn(3)+2
+	for i := 0; i < n; i++ {
+		offset := mgl64.Vec3{(float64(i%2) - 0.5) * float64(size) / 2, 0.5, (float64(i/2) - 0.5) * float64(size) / 2}
+		child := s.conf.Offspring(pos.Add(offset), size)
+		child.SetNameTag(m.NameTag())
+		w.AddEntity(child)
+	}
+}
+
+// Tick ticks the slime, making it jump towards its target or in a random
+// direction and hurting entities it touches.
+func (s *SlimeBehaviour) Tick(m *Mob) {
+	target, hasTarget := nearestEntity(m, s.conf.TargetRange, hostileTargetable)
+	if hasTarget {
+		m.LookAt(EyePosition(target))
+	}
+	s.tickJump(m, target, hasTarget)
+	s.tickAttack(m)
+}
+
+// tickJump makes the slime jump once its jump delay has expired. Slimes with
+// a target jump towards it more frequently. Upon landing, the slime stops
+// moving horizontally.
+func (s *SlimeBehaviour) tickJump(m *Mob, target world.Entity, hasTarget bool) {
+	onGround := m.OnGround()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !onGround {
+		s.airborne = true
+		return
+	}
+	if s.airborne {
+		// The slime landed, so it stops moving until its next jump.
+		s.airborne = false
+		vel := m.Velocity()
+		m.SetVelocity(mgl64.Vec3{0, vel[1], 0})
+	}
+	if s.jumpDelay--; s.jumpDelay > 0 {
+		return
+	}
+	s.jumpDelay = rand.Intn(20) + 10
+	if hasTarget {
+		s.jumpDelay /= 3
+	}
+
+	var dir mgl64.Vec3
+	if hasTarget {
+		dir = target.Position().Sub(m.Position())
+	} else {
+		yaw := rand.Float64() * math.Pi * 2
+		dir = mgl64.Vec3{math.Cos(yaw), 0, math.Sin(yaw)}
+	}
+	dir[1] = 0
+	if dir.LenSqr() == 0 {
+		return
+	}
+	dir = dir.Normalize()
+	if !hasTarget {
+		m.LookAt(m.Position().Add(dir))
+	}
+	jump := 0.42
+	if s.conf.JumpVelocity != nil {
+		jump = s.conf.JumpVelocity(s.conf.Size)
+	}
+	horizontal := dir.Mul(m.Speed())
+	m.SetVelocity(mgl64.Vec3{horizontal[0], jump, horizontal[2]})
+}
+
+// tickAttack hurts all targetable entities that the slime touches.
+func (s *SlimeBehaviour) tickAttack(m *Mob) {
+	if s.conf.Damage == nil {
+		return
+	}
+	dmg := s.conf.Damage(s.conf.Size)
+	if dmg <= 0 {
+		return
+	}
+	box := m.Type().BBox(m).Translate(m.Position()).Grow(0.2)
+	for _, e := range m.World().EntitiesWithin(box, func(e world.Entity) bool {
+		return e == m || !hostileTargetable(e)
+	}) {
+		if l, ok := e.(Living); ok && !l.AttackImmune() {
+			l.Hurt(dmg, AttackDamageSource{Attacker: m})
+		}
+	}
+}
+
+// slimeBBox returns the bounding box of the slime passed, which grows with
+// its size.
+func slimeBBox(e world.Entity) cube.BBox {
+	size := 1.0
+	if m, ok := e.(*Mob); ok {
+		if s, ok := m.Behaviour().(*SlimeBehaviour); ok {
+			size = float64(s.Size())
+		}
+	}
+	return cube.Box(-0.26, 0, -0.26, 0.26, 0.52, 0.26).Mul(size)
+}
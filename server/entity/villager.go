@@ -0,0 +1,231 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewVillager creates a new villager with the VillagerProfession passed at the
+// position passed. The appearance of the villager is based on the world.Biome
+// passed, such as a desert or a taiga.
+func NewVillager(pos mgl64.Vec3, profession VillagerProfession, b world.Biome) *Mob {
+	return newVillager(pos, profession, villagerKind(b))
+}
+
+// newVillager creates a new villager with the profession and kind passed.
+func newVillager(pos mgl64.Vec3, profession VillagerProfession, kind int32) *Mob {
+	conf := villagerConf
+	conf.Behaviour = &VillagerBehaviour{profession: profession, kind: kind, wander: AnimalBehaviourConfig{}.New(false)}
+	return conf.New(VillagerType{}, pos)
+}
+
+var villagerConf = MobConfig{
+	MaxHealth: 20,
+	Speed:     0.1,
+	Gravity:   0.08,
+	Drag:      0.02,
+	Leashable: true,
+}
+
+// VillagerBehaviour implements the behaviour of villagers. Villagers wander
+// around their village and have a VillagerProfession.
+type VillagerBehaviour struct {
+	profession VillagerProfession
+	kind       int32
+	wander     *AnimalBehaviour
+}
+
+// Profession returns the VillagerProfession of the villager.
+func (v *VillagerBehaviour) Profession() VillagerProfession {
+	return v.profession
+}
+
+// Variant returns the profession of the villager as an int32, which the
+// client uses to change the clothing of the villager.
+func (v *VillagerBehaviour) Variant() int32 {
+	return int32(v.profession.profession)
+}
+
+// MarkVariant returns the kind of the villager, which depends on the biome
+// that the villager was spawned in.
+func (v *VillagerBehaviour) MarkVariant() int32 {
+	return v.kind
+}
+
+// Tick makes the villager wander around randomly.
+func (v *VillagerBehaviour) Tick(m *Mob) {
+	v.wander.tickWander(m)
+}
+
+// villagerKind returns the kind of villager that spawns in the world.Biome
+// passed.
+func villagerKind(b world.Biome) int32 {
+	switch b.(type) {
+	case biome.Desert, biome.Badlands:
+		return 1
+	case biome.Jungle:
+		return 2
+	case biome.Savanna:
+		return 3
+	case biome.SnowyPlains, biome.SnowyTaiga, biome.SnowySlopes, biome.IceSpikes, biome.FrozenRiver:
+		return 4
+	case biome.Swamp, biome.MangroveSwamp:
+		return 5
+	case biome.Taiga, biome.OldGrowthPineTaiga, biome.OldGrowthSpruceTaiga:
+		return 6
+	}
+	return 0
+}
+
+// VillagerType is a world.EntityType implementation for villagers.
+type VillagerType struct{}
+
+func (VillagerType) EncodeEntity() string { return "minecraft:villager_v2" }
+func (VillagerType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.9, 0.3)
+}
+
+func (VillagerType) DecodeNBT(m map[string]any) world.Entity {
+	profession := VillagerProfession{profession(nbtconv.Int32(m, "Variant"))}
+	return decodeMobNBT(newVillager(nbtconv.Vec3(m, "Pos"), profession, nbtconv.Int32(m, "MarkVariant")), m)
+}
+
+func (VillagerType) EncodeNBT(e world.Entity) map[string]any {
+	m := e.(*Mob)
+	v := m.Behaviour().(*VillagerBehaviour)
+	data := encodeMobNBT(m)
+	data["Variant"], data["MarkVariant"] = v.Variant(), v.kind
+	return data
+}
+
+// VillagerProfession is the profession of a villager. The profession of a
+// villager determines its clothing and the workstation that it works at.
+type VillagerProfession struct {
+	profession
+}
+
+type profession uint8
+
+// ProfessionNone returns the profession of villagers without a profession.
+func ProfessionNone() VillagerProfession {
+	return VillagerProfession{0}
+}
+
+// ProfessionFarmer returns the farmer profession, which works at a composter.
+func ProfessionFarmer() VillagerProfession {
+	return VillagerProfession{1}
+}
+
+// ProfessionFisherman returns the fisherman profession, which works at a
+// barrel.
+func ProfessionFisherman() VillagerProfession {
+	return VillagerProfession{2}
+}
+
+// ProfessionShepherd returns the shepherd profession, which works at a loom.
+func ProfessionShepherd() VillagerProfession {
+	return VillagerProfession{3}
+}
+
+// ProfessionFletcher returns the fletcher profession, which works at a
+// fletching table.
+func ProfessionFletcher() VillagerProfession {
+	return VillagerProfession{4}
+}
+
+// ProfessionLibrarian returns the librarian profession, which works at a
+// lectern.
+func ProfessionLibrarian() VillagerProfession {
+	return VillagerProfession{5}
+}
+
+// ProfessionCartographer returns the cartographer profession, which works at
+// a cartography table.
+func ProfessionCartographer() VillagerProfession {
+	return VillagerProfession{6}
+}
+
+// ProfessionCleric returns the cleric profession, which works at a brewing
+// stand.
+func ProfessionCleric() VillagerProfession {
+	return VillagerProfession{7}
+}
+
+// ProfessionArmourer returns the armourer profession, which works at a blast
+// furnace.
+func ProfessionArmourer() VillagerProfession {
+	return VillagerProfession{8}
+}
+
+// ProfessionWeaponsmith returns the weaponsmith profession, which works at a
+// grindstone.
+func ProfessionWeaponsmith() VillagerProfession {
+	return VillagerProfession{9}
+}
+
+// ProfessionToolsmith returns the toolsmith profession, which works at a
+// smithing table.
+func ProfessionToolsmith() VillagerProfession {
+	return VillagerProfession{10}
+}
+
+// ProfessionButcher returns the butcher profession, which works at a smoker.
+func ProfessionButcher() VillagerProfession {
+	return VillagerProfession{11}
+}
+
+// ProfessionLeatherworker returns the leatherworker profession, which works
+// at a cauldron.
+func ProfessionLeatherworker() VillagerProfession {
+	return VillagerProfession{12}
+}
+
+// ProfessionMason returns the mason profession, which works at a
+// stonecutter.
+func ProfessionMason() VillagerProfession {
+	return VillagerProfession{13}
+}
+
+// ProfessionNitwit returns the nitwit profession. Nitwits never work at a
+// workstation.
+func ProfessionNitwit() VillagerProfession {
+	return VillagerProfession{14}
+}
+
+// String ...
+func (p profession) String() string {
+	switch p {
+	case 0:
+		return "none"
+	case 1:
+		return "farmer"
+	case 2:
+		return "fisherman"
+	case 3:
+		return "shepherd"
+	case 4:
+		return "fletcher"
+	case 5:
+		return "librarian"
+	case 6:
+		return "cartographer"
+	case 7:
+		return "cleric"
+	case 8:
+		return "armourer"
+	case 9:
+		return "weaponsmith"
+	case 10:
+		return "toolsmith"
+	case 11:
+		return "butcher"
+	case 12:
+		return "leatherworker"
+	case 13:
+		return "mason"
+	}
+	return "nitwit"
+}
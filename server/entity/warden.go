@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// NewWarden creates a new warden at the position passed. The warden emerges
+// from the ground after being created.
+func NewWarden(pos mgl64.Vec3) *Mob {
+	return newWarden(pos, true)
+}
+
+// newWarden creates a new warden that either emerges from the ground or
+// starts out fully emerged.
+func newWarden(pos mgl64.Vec3, emerge bool) *Mob {
+	conf := wardenConf
+	conf.Behaviour = WardenBehaviourConfig{}.New(emerge)
+	return conf.New(WardenType{}, pos)
+}
+
+var wardenConf = MobConfig{
+	MaxHealth:           500,
+	Speed:               0.3,
+	KnockBackResistance: 1,
+	Gravity:             0.08,
+	Drag:                0.02,
+	Experience:          5,
+}
+
+// WardenType is a world.EntityType implementation for the warden.
+type WardenType struct{}
+
+func (WardenType) EncodeEntity() string { return "minecraft:warden" }
+func (WardenType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.45, 0, -0.45, 0.45, 2.9, 0.45)
+}
+
+func (WardenType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(newWarden(nbtconv.Vec3(m, "Pos"), false), m)
+}
+
+func (WardenType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
@@ -0,0 +1,435 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// WardenBehaviourConfig holds optional parameters for a WardenBehaviour.
+type WardenBehaviourConfig struct {
+	// AngerThreshold is the anger level at which the warden starts attacking
+	// an entity. If 0, a threshold of 80 is used.
+	AngerThreshold int
+	// MeleeDamage is the damage dealt by the melee attack of the warden. If 0,
+	// a damage of 30 is used.
+	MeleeDamage float64
+	// SonicBoomDamage is the damage dealt by the sonic boom of the warden,
+	// which ignores armour. If 0, a damage of 10 is used.
+	SonicBoomDamage float64
+	// SonicBoomRange is the maximum distance to a target that the warden
+	// uses its sonic boom on. If 0, a range of 15 blocks is used.
+	SonicBoomRange float64
+	// DarknessRange is the range within which the warden periodically gives
+	// players the darkness effect. If 0, a range of 20 blocks is used.
+	DarknessRange float64
+	// DigDelay is the time that the warden must go without being disturbed
+	// before it digs back into the ground and disappears. If 0, a delay of
+	// 60 seconds is used.
+	DigDelay time.Duration
+}
+
+// New creates a WardenBehaviour using the parameters in conf. If emerge is
+// true, the warden starts by emerging from the ground.
+func (conf WardenBehaviourConfig) New(emerge bool) *WardenBehaviour {
+	if conf.AngerThreshold == 0 {
+		conf.AngerThreshold = 80
+	}
+	if conf.MeleeDamage == 0 {
+		conf.MeleeDamage = 30
+	}
+	if conf.SonicBoomDamage == 0 {
+		conf.SonicBoomDamage = 10
+	}
+	if conf.SonicBoomRange == 0 {
+		conf.SonicBoomRange = 15
+	}
+	if conf.DarknessRange == 0 {
+		conf.DarknessRange = 20
+	}
+	if conf.DigDelay == 0 {
+		conf.DigDelay = time.Second * 60
+	}
+	w := &WardenBehaviour{conf: conf, anger: map[world.Entity]int{}}
+	if emerge {
+		w.emerging = wardenEmergeTicks
+	}
+	return w
+}
+
+const (
+	// wardenEmergeTicks is the duration in ticks of the emerging animation.
+	wardenEmergeTicks = 134
+	// wardenDigTicks is the duration in ticks of the digging animation.
+	wardenDigTicks = 110
+	// wardenSniffTicks is the duration in ticks of the sniffing animation.
+	wardenSniffTicks = 34
+	// wardenSonicBoomTicks is the time in ticks that the warden charges its
+	// sonic boom before releasing it.
+	wardenSonicBoomTicks = 34
+	// wardenMaxAnger is the maximum anger level the warden has towards an
+	// entity.
+	wardenMaxAnger = 150
+)
+
+// WardenBehaviour implements the behaviour of the warden. The warden is blind
+// and senses its surroundings through vibrations, such as footsteps or blocks
+// being broken, and by sniffing. Every disturbance caused by an entity makes
+// the warden angrier at it, and once angry enough, the warden chases the
+// entity and attacks it in melee or with a ranged sonic boom. Wardens
+// periodically give nearby players the darkness effect and dig back into the
+// ground when left undisturbed.
+type WardenBehaviour struct {
+	conf WardenBehaviourConfig
+
+	mu             sync.Mutex
+	anger          map[world.Entity]int
+	emerging       int
+	digging        int
+	sniffing       int
+	sonicBoom      int
+	sonicCooldown  int
+	attackCooldown int
+	listenCooldown int
+	sniffCooldown  int
+	idle           int
+	investigate    mgl64.Vec3
+	investigating  bool
+}
+
+// Emerging checks if the warden is currently emerging from the ground.
+func (w *WardenBehaviour) Emerging() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.emerging > 0
+}
+
+// Digging checks if the warden is currently digging into the ground.
+func (w *WardenBehaviour) Digging() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.digging > 0
+}
+
+// Sniffing checks if the warden is currently sniffing.
+func (w *WardenBehaviour) Sniffing() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sniffing > 0
+}
+
+// ChargingSonicBoom checks if the warden is currently charging its sonic
+// boom attack.
+func (w *WardenBehaviour) ChargingSonicBoom() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sonicBoom > 0
+}
+
+// Anger returns the anger level of the warden towards the entity passed.
+func (w *WardenBehaviour) Anger(e world.Entity) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.anger[e]
+}
+
+// Target returns the entity that the warden is currently angry enough at to
+// attack. False is returned if the warden is not angry at any entity.
+func (w *WardenBehaviour) Target() (Living, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var (
+		target Living
+		anger  int
+	)
+	for e, a := range w.anger {
+		if l, ok := e.(Living); ok && a >= w.conf.AngerThreshold && a > anger {
+			target, anger = l, a
+		}
+	}
+	return target, target != nil
+}
+
+// increaseAnger increases the anger of the warden towards the entity passed
+// by the amount passed, if the warden may target the entity.
+func (w *WardenBehaviour) increaseAnger(e world.Entity, n int) {
+	if !wardenTargetable(e) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.anger[e] = min(w.anger[e]+n, wardenMaxAnger)
+	w.idle = 0
+}
+
+// Hurt makes the warden invulnerable while it is emerging or digging and
+// immune to fire. Entities attacking the warden make it very angry.
+func (w *WardenBehaviour) Hurt(_ *Mob, dmg float64, src world.DamageSource) (float64, bool) {
+	if w.Emerging() || w.Digging() || src.Fire() {
+		return 0, false
+	}
+	if s, ok := src.(AttackDamageSource); ok {
+		w.increaseAnger(s.Attacker, 100)
+	} else if s, ok := src.(ProjectileDamageSource); ok {
+		w.increaseAnger(s.Owner, 100)
+	}
+	return dmg, true
+}
+
+// HandleGameEvent makes the warden perceive the world.GameEvent passed as a
+// vibration. The warden investigates the position of the vibration and grows
+// angrier at the entity that caused it.
+func (w *WardenBehaviour) HandleGameEvent(_ *Mob, e world.GameEvent, pos mgl64.Vec3, src world.Entity) {
+	if src != nil {
+		if _, warden := src.Type().(WardenType); warden {
+			return
+		}
+	}
+	w.mu.Lock()
+	if w.emerging > 0 || w.digging > 0 || w.listenCooldown > 0 {
+		w.mu.Unlock()
+		return
+	}
+	w.listenCooldown, w.idle = 20, 0
+	w.investigate, w.investigating = pos, true
+	w.mu.Unlock()
+
+	if src == nil {
+		return
+	}
+	anger := 35
+	if e == world.GameEventProjectileLand {
+		anger += 10
+	}
+	w.increaseAnger(src, anger)
+}
+
+// Tick ticks the warden, progressing its animations, anger levels and
+// attacks.
+func (w *WardenBehaviour) Tick(m *Mob) {
+	// Wardens are not affected by knock back, so they only move horizontally
+	// when walking.
+	m.SetVelocity(mgl64.Vec3{0, m.Velocity()[1], 0})
+
+	w.mu.Lock()
+	w.listenCooldown = max(w.listenCooldown-1, 0)
+	w.attackCooldown = max(w.attackCooldown-1, 0)
+	w.sonicCooldown = max(w.sonicCooldown-1, 0)
+	w.sniffCooldown = max(w.sniffCooldown-1, 0)
+	if w.emerging > 0 {
+		w.emerging--
+		done := w.emerging == 0
+		w.mu.Unlock()
+		if done {
+			m.updateState()
+		}
+		return
+	}
+	if w.digging > 0 {
+		w.digging--
+		done := w.digging == 0
+		w.mu.Unlock()
+		if done {
+			_ = m.Close()
+		}
+		return
+	}
+	if w.sniffing > 0 {
+		w.sniffing--
+		done := w.sniffing == 0
+		w.mu.Unlock()
+		if done {
+			m.updateState()
+		}
+		return
+	}
+	w.mu.Unlock()
+
+	if m.Age()%time.Second == 0 {
+		w.tickAnger(m)
+	}
+	if m.Age()%(time.Second*6) == 0 {
+		w.emitDarkness(m)
+	}
+	if w.tickDig(m) {
+		return
+	}
+	if target, ok := w.Target(); ok {
+		w.tickAttack(m, target)
+		return
+	}
+	w.mu.Lock()
+	w.sonicBoom = 0
+	investigate, investigating := w.investigate, w.investigating
+	w.mu.Unlock()
+
+	if investigating {
+		if investigate.Sub(m.Position()).Len() > 2 {
+			m.MoveTowards(investigate, 0.7)
+			return
+		}
+		w.mu.Lock()
+		w.investigating = false
+		w.mu.Unlock()
+	}
+	w.tickSniff(m)
+}
+
+// tickAnger decreases the anger of the warden towards all entities by one,
+// forgetting entities that died, left the world or that it is no longer
+// angry at.
+func (w *WardenBehaviour) tickAnger(m *Mob) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for e, a := range w.anger {
+		l, living := e.(Living)
+		if a <= 1 || !living || l.Dead() || e.World() != m.World() {
+			delete(w.anger, e)
+			continue
+		}
+		w.anger[e] = a - 1
+	}
+}
+
+// emitDarkness gives all players within range of the warden the darkness
+// effect.
+func (w *WardenBehaviour) emitDarkness(m *Mob) {
+	pos, r := m.Position(), w.conf.DarknessRange
+	for _, e := range m.World().EntitiesWithin(cube.Box(-r, -r, -r, r, r, r).Translate(pos), func(e world.Entity) bool {
+		return !hostileTargetable(e) || e.Position().Sub(pos).Len() > r
+	}) {
+		e.(Living).AddEffect(effect.New(effect.Darkness{}, 1, time.Second*13))
+	}
+}
+
+// tickDig makes the warden dig back into the ground if it has not been
+// disturbed for a while. tickDig returns true if the warden started digging.
+func (w *WardenBehaviour) tickDig(m *Mob) bool {
+	w.mu.Lock()
+	if len(w.anger) > 0 || w.investigating {
+		w.idle = 0
+		w.mu.Unlock()
+		return false
+	}
+	w.idle++
+	dig := time.Duration(w.idle)*time.Second/20 >= w.conf.DigDelay
+	if dig {
+		w.digging = wardenDigTicks
+	}
+	w.mu.Unlock()
+	if dig {
+		m.updateState()
+	}
+	return dig
+}
+
+// tickAttack makes the warden chase the target passed and attack it in melee
+// once close enough. If the target is out of reach, the warden charges a
+// sonic boom.
+func (w *WardenBehaviour) tickAttack(m *Mob, target Living) {
+	pos, dst := m.Position(), target.Position()
+	dist := dst.Sub(pos).Len()
+	m.LookAt(EyePosition(target))
+
+	w.mu.Lock()
+	charging := w.sonicBoom > 0
+	if charging {
+		w.sonicBoom--
+	}
+	release := charging && w.sonicBoom == 0
+	w.mu.Unlock()
+
+	if charging {
+		if release {
+			w.releaseSonicBoom(m, target)
+		}
+		return
+	}
+	if dist <= 2.5 {
+		w.mu.Lock()
+		attack := w.attackCooldown == 0
+		if attack {
+			w.attackCooldown = 18
+		}
+		w.mu.Unlock()
+		if attack {
+			target.Hurt(w.conf.MeleeDamage, AttackDamageSource{Attacker: m})
+			target.KnockBack(pos, 0.5, 0.4)
+		}
+		return
+	}
+	w.mu.Lock()
+	boom := dist <= w.conf.SonicBoomRange && dist > 5 && w.sonicCooldown == 0 && rand.Intn(20) == 0
+	if boom {
+		w.sonicBoom = wardenSonicBoomTicks
+	}
+	w.mu.Unlock()
+	if boom {
+		m.updateState()
+		return
+	}
+	m.MoveTowards(dst, 1.2)
+}
+
+// releaseSonicBoom releases the sonic boom of the warden at the target
+// passed, if it is still in range, damaging it regardless of armour and
+// knocking it back.
+func (w *WardenBehaviour) releaseSonicBoom(m *Mob, target Living) {
+	w.mu.Lock()
+	w.sonicCooldown = 40
+	w.mu.Unlock()
+	m.updateState()
+
+	origin, dst := m.Position().Add(mgl64.Vec3{0, 1.6, 0}), EyePosition(target)
+	diff := dst.Sub(origin)
+	if diff.Len() > w.conf.SonicBoomRange+5 || target.Dead() {
+		return
+	}
+	wo := m.World()
+	for i := 1.0; i < diff.Len(); i += 1.5 {
+		wo.AddParticle(origin.Add(diff.Normalize().Mul(i)), particle.SonicExplosion{})
+	}
+	if _, vulnerable := target.Hurt(w.conf.SonicBoomDamage, SonicBoomDamageSource{Warden: m}); vulnerable {
+		target.KnockBack(m.Position(), 2.5, 0.5)
+	}
+}
+
+// tickSniff makes the warden sniff every now and then, growing angrier at
+// the nearest entity it smells.
+func (w *WardenBehaviour) tickSniff(m *Mob) {
+	w.mu.Lock()
+	if w.sniffCooldown > 0 {
+		w.mu.Unlock()
+		return
+	}
+	w.sniffCooldown = 100 + rand.Intn(100)
+	w.sniffing = wardenSniffTicks
+	w.mu.Unlock()
+	m.updateState()
+
+	if nearest, ok := nearestEntity(m, 24, wardenTargetable); ok {
+		w.increaseAnger(nearest, 35)
+	}
+}
+
+// wardenTargetable checks if an entity may be targeted by a warden. Wardens
+// target all living entities apart from other wardens and players that
+// cannot take damage.
+func wardenTargetable(e world.Entity) bool {
+	if e == nil {
+		return false
+	}
+	if _, warden := e.Type().(WardenType); warden {
+		return false
+	}
+	if g, ok := e.(interface{ GameMode() world.GameMode }); ok && !g.GameMode().AllowsTakingDamage() {
+		return false
+	}
+	l, ok := e.(Living)
+	return ok && !l.Dead()
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"time"
+)
+
+// NewWither creates a new wither at the position passed. The wither starts in
+// its spawning phase, during which it is invulnerable and regains health,
+// after which it explodes and starts attacking nearby entities.
+func NewWither(pos mgl64.Vec3) *Mob {
+	conf := witherConf
+	conf.Behaviour = WitherBehaviourConfig{}.New()
+	m := conf.New(WitherType{}, pos)
+	m.health.AddHealth(-conf.MaxHealth * 2 / 3)
+	return m
+}
+
+var witherConf = MobConfig{
+	MaxHealth:  600,
+	Speed:      0.6,
+	Drag:       0.1,
+	Experience: 50,
+	Drops: func(*Mob, world.DamageSource) []item.Stack {
+		return []item.Stack{item.NewStack(item.NetherStar{}, 1)}
+	},
+}
+
+// WitherType is a world.EntityType implementation for the wither.
+type WitherType struct{}
+
+func (WitherType) EncodeEntity() string { return "minecraft:wither" }
+func (WitherType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.5, 0, -0.5, 0.5, 3.5, 0.5)
+}
+
+func (WitherType) DecodeNBT(m map[string]any) world.Entity {
+	w := decodeMobNBT(NewWither(nbtconv.Vec3(m, "Pos")), m)
+	w.Behaviour().(*WitherBehaviour).invulnerable = nbtconv.TickDuration[int32](m, "Invul")
+	return w
+}
+
+func (WitherType) EncodeNBT(e world.Entity) map[string]any {
+	w := e.(*Mob)
+	data := encodeMobNBT(w)
+	data["Invul"] = int32(w.Behaviour().(*WitherBehaviour).InvulnerableDuration() / (time.Second / 20))
+	return data
+}
@@ -0,0 +1,352 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/item/loot"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/particle"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// WitherBehaviourConfig holds optional parameters for a WitherBehaviour.
+type WitherBehaviourConfig struct {
+	// SpawnDuration is the duration of the spawning phase of the wither,
+	// during which it is invulnerable and regains health. If 0, a duration
+	// of 11 seconds is used.
+	SpawnDuration time.Duration
+	// SpawnExplosionSize is the size of the explosion created at the end of
+	// the spawning phase. If 0, a size of 7 is used.
+	SpawnExplosionSize float64
+	// TargetRange is the range within which the wither finds targets. If 0, a
+	// range of 20 blocks is used.
+	TargetRange float64
+	// BossBarRange is the range within which players are shown the boss bar
+	// of the wither. If 0, a range of 64 blocks is used.
+	BossBarRange float64
+}
+
+// New creates a WitherBehaviour using the parameters in conf.
+func (conf WitherBehaviourConfig) New() *WitherBehaviour {
+	if conf.SpawnDuration == 0 {
+		conf.SpawnDuration = time.Second * 11
+	}
+	if conf.SpawnExplosionSize == 0 {
+		conf.SpawnExplosionSize = 7
+	}
+	if conf.TargetRange == 0 {
+		conf.TargetRange = 20
+	}
+	if conf.BossBarRange == 0 {
+		conf.BossBarRange = 64
+	}
+	return &WitherBehaviour{conf: conf, invulnerable: conf.SpawnDuration}
+}
+
+// WitherBehaviour implements the behaviour of the wither boss. After its
+// spawning phase, the wither flies towards its targets, shoots wither skulls
+// at them from its three heads and destroys blocks around it after taking
+// damage. Players nearby are shown a boss bar with the health of the wither.
+type WitherBehaviour struct {
+	conf WitherBehaviourConfig
+
+	mu            sync.Mutex
+	invulnerable  time.Duration
+	targets       [3]Living
+	cooldowns     [3]int
+	destroyBlocks int
+	bossBar       mobBossBar
+}
+
+// InvulnerableDuration returns the time left in the spawning phase of the
+// wither, during which it is invulnerable. InvulnerableDuration returns 0
+// once the wither has fully spawned.
+func (w *WitherBehaviour) InvulnerableDuration() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.invulnerable
+}
+
+// Armoured checks if the wither is armoured, which is the case once its
+// health drops to half or lower. An armoured wither is immune to projectiles.
+func (w *WitherBehaviour) Armoured(m *Mob) bool {
+	return m.Health() <= m.MaxHealth()/2
+}
+
+// HeadTargets returns the targets of the three heads of the wither. The
+// first element is the target of the centre head. Heads without a target
+// have a nil entry.
+func (w *WitherBehaviour) HeadTargets() []world.Entity {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	targets := make([]world.Entity, 0, 3)
+	for _, t := range w.targets {
+		if t == nil {
+			targets = append(targets, nil)
+			continue
+		}
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+// Hurt makes the wither immune to damage during its spawning phase, to fire,
+// drowning and falling, and to projectiles while it is armoured. Taking
+// damage from any other source makes the wither destroy blocks around it.
+func (w *WitherBehaviour) Hurt(m *Mob, dmg float64, src world.DamageSource) (float64, bool) {
+	if w.InvulnerableDuration() > 0 || src.Fire() {
+		return 0, false
+	}
+	switch src.(type) {
+	case DrowningDamageSource, FallDamageSource, SuffocationDamageSource:
+		return 0, false
+	case ProjectileDamageSource:
+		if w.Armoured(m) {
+			return 0, false
+		}
+	}
+	w.mu.Lock()
+	if w.destroyBlocks <= 0 {
+		w.destroyBlocks = 20
+	}
+	w.mu.Unlock()
+	return dmg, true
+}
+
+// Death removes the boss bar of the wither from all players it was shown to.
+func (w *WitherBehaviour) Death(*Mob, world.DamageSource) {
+	w.bossBar.remove()
+}
+
+// Close removes the boss bar of the wither from all players it was shown to.
+func (w *WitherBehaviour) Close(*Mob) {
+	w.bossBar.remove()
+}
+
+// Tick ticks the wither, progressing its spawning phase or, once spawned,
+// selecting targets, moving, attacking and destroying blocks.
+func (w *WitherBehaviour) Tick(m *Mob) {
+	w.bossBar.tick(m, "Wither", w.conf.BossBarRange)
+
+	w.mu.Lock()
+	if w.invulnerable > 0 {
+		w.invulnerable -= time.Second / 20
+		done := w.invulnerable <= 0
+		w.mu.Unlock()
+
+		m.Heal(m.MaxHealth()*2/3/float64(w.conf.SpawnDuration/(time.Second/20)), nil)
+		if done {
+			block.ExplosionConfig{Size: w.conf.SpawnExplosionSize}.Explode(m.World(), m.Position())
+		}
+		m.updateState()
+		return
+	}
+	w.destroyBlocks--
+	destroy := w.destroyBlocks == 0
+	w.mu.Unlock()
+
+	if destroy {
+		w.breakBlocks(m)
+	}
+	w.tickTargets(m)
+	w.tickMovement(m)
+	w.tickAttack(m)
+	if m.Age()%time.Second == 0 {
+		// The wither regenerates 1 health every second.
+		m.Heal(1, nil)
+	}
+}
+
+// tickTargets updates the targets of the heads of the wither, dropping
+// targets that are out of range and finding new ones.
+func (w *WitherBehaviour) tickTargets(m *Mob) {
+	pos := m.Position()
+	w.mu.Lock()
+	changed := false
+	for i, t := range w.targets {
+		if t != nil && (t.Dead() || t.Position().Sub(pos).Len() > w.conf.TargetRange || !witherTargetable(t)) {
+			w.targets[i], changed = nil, true
+		}
+	}
+	w.mu.Unlock()
+
+	if m.Age()%(time.Second/2) != 0 {
+		if changed {
+			m.updateState()
+		}
+		return
+	}
+	candidates := m.World().EntitiesWithin(cube.Box(-1, -1, -1, 1, 1, 1).Mul(w.conf.TargetRange).Translate(pos), func(e world.Entity) bool {
+		l, ok := e.(Living)
+		return !ok || e == m || l.Dead() || !witherTargetable(e) || e.Position().Sub(pos).Len() > w.conf.TargetRange
+	})
+	if len(candidates) == 0 {
+		if changed {
+			m.updateState()
+		}
+		return
+	}
+	w.mu.Lock()
+	for i, t := range w.targets {
+		if t != nil {
+			continue
+		}
+		if i == 0 {
+			// The centre head always targets the nearest entity.
+			nearest, dist := candidates[0], math.MaxFloat64
+			for _, c := range candidates {
+				if d := c.Position().Sub(pos).Len(); d < dist {
+					nearest, dist = c, d
+				}
+			}
+			w.targets[i] = nearest.(Living)
+		} else {
+			w.targets[i] = candidates[rand.Intn(len(candidates))].(Living)
+		}
+		changed = true
+	}
+	w.mu.Unlock()
+	if changed {
+		m.updateState()
+	}
+}
+
+// witherTargetable checks if an entity may be targeted by a wither. Withers
+// do not target other withers or players that cannot take damage.
+func witherTargetable(e world.Entity) bool {
+	if _, ok := e.Type().(WitherType); ok {
+		return false
+	}
+	if g, ok := e.(interface{ GameMode() world.GameMode }); ok {
+		return g.GameMode().AllowsTakingDamage()
+	}
+	return true
+}
+
+// tickMovement makes the wither fly towards the target of its centre head,
+// hovering a few blocks above it.
+func (w *WitherBehaviour) tickMovement(m *Mob) {
+	w.mu.Lock()
+	target := w.targets[0]
+	w.mu.Unlock()
+
+	pos, vel := m.Position(), m.Velocity()
+	if target == nil {
+		// Slowly sink towards the ground when there is no target.
+		vel[1] = math.Max(vel[1]-0.01, -0.1)
+		if m.OnGround() {
+			vel[1] = 0
+		}
+		m.SetVelocity(vel)
+		return
+	}
+	dst := target.Position()
+	m.LookAt(EyePosition(target))
+
+	if pos[1] < dst[1]+5 {
+		vel[1] = math.Max(vel[1], 0) + (0.5-math.Max(vel[1], 0))*0.6
+	} else {
+		vel[1] *= 0.6
+	}
+	diff := dst.Sub(pos)
+	diff[1] = 0
+	if diff.LenSqr() > 9*9 {
+		dir := diff.Normalize().Mul(m.Speed())
+		vel[0] += (dir[0] - vel[0]) * 0.3
+		vel[2] += (dir[2] - vel[2]) * 0.3
+	}
+	m.SetVelocity(vel)
+}
+
+// tickAttack makes the heads of the wither shoot wither skulls at their
+// targets once their cooldown has expired.
+func (w *WitherBehaviour) tickAttack(m *Mob) {
+	w.mu.Lock()
+	var shoot [3]Living
+	for i, t := range w.targets {
+		if w.cooldowns[i] > 0 {
+			w.cooldowns[i]--
+			continue
+		}
+		if t == nil {
+			continue
+		}
+		shoot[i] = t
+		if i == 0 {
+			w.cooldowns[i] = 40
+		} else {
+			w.cooldowns[i] = 10 + rand.Intn(10)
+		}
+	}
+	w.mu.Unlock()
+
+	for i, t := range shoot {
+		if t != nil {
+			w.shootSkull(m, i, EyePosition(t), i != 0 && rand.Intn(1000) == 0)
+		}
+	}
+}
+
+// shootSkull shoots a wither skull from the head with the index passed
+// towards the target position. If dangerous is true, a blue skull is shot,
+// which is slower but also destroys blocks that the black skull cannot.
+func (w *WitherBehaviour) shootSkull(m *Mob, head int, target mgl64.Vec3, dangerous bool) {
+	origin := w.headPosition(m, head)
+	speed := 0.8
+	if dangerous {
+		speed = 0.4
+	}
+	dir := target.Sub(origin)
+	if dir.LenSqr() == 0 {
+		return
+	}
+	skull := NewWitherSkull(origin, m, dangerous)
+	skull.vel = dir.Normalize().Mul(speed)
+	m.World().AddEntity(skull)
+}
+
+// headPosition returns the position of the head of the wither with the index
+// passed. The centre head is at index 0, the side heads at index 1 and 2.
+func (w *WitherBehaviour) headPosition(m *Mob, head int) mgl64.Vec3 {
+	pos := m.Position()
+	if head == 0 {
+		return pos.Add(mgl64.Vec3{0, 3, 0})
+	}
+	yaw := mgl64.DegToRad(m.Rotation().Yaw() + float64(180*(head-1)))
+	return pos.Add(mgl64.Vec3{math.Cos(yaw) * 1.3, 2.2, math.Sin(yaw) * 1.3})
+}
+
+// breakBlocks destroys all breakable blocks in the area occupied by the
+// wither, so that it cannot be trapped easily.
+func (w *WitherBehaviour) breakBlocks(m *Mob) {
+	wo, pos := m.World(), cube.PosFromVec3(m.Position())
+	if !world.GameRuleMobGriefing.Value(wo) {
+		return
+	}
+	for x := -1; x <= 1; x++ {
+		for y := 0; y <= 3; y++ {
+			for z := -1; z <= 1; z++ {
+				bpos := pos.Add(cube.Pos{x, y, z})
+				b := wo.Block(bpos)
+				if _, air := b.(block.Air); air {
+					continue
+				}
+				// Blocks that cannot be broken by players, such as bedrock and
+				// end portal frames, cannot be destroyed by the wither either.
+				if _, ok := b.(block.Breakable); !ok {
+					continue
+				}
+				wo.SetBlock(bpos, nil, nil)
+				wo.AddParticle(bpos.Vec3Centre(), particle.BlockBreak{Block: b})
+				for _, drop := range block.BreakDrops(b, item.ToolNone{}, nil, loot.Context{This: m}) {
+					wo.AddEntity(NewItem(drop, bpos.Vec3Centre()))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/internal/nbtconv"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"time"
+)
+
+// NewWitherSkull creates a wither skull entity at a position with an owner
+// entity. If dangerous is true, the skull is a blue wither skull.
+func NewWitherSkull(pos mgl64.Vec3, owner world.Entity, dangerous bool) *Ent {
+	if dangerous {
+		return Config{Behaviour: witherSkullConf.New(owner)}.New(WitherSkullDangerousType{}, pos)
+	}
+	return Config{Behaviour: witherSkullConf.New(owner)}.New(WitherSkullType{}, pos)
+}
+
+var witherSkullConf = ProjectileBehaviourConfig{
+	Damage: -1,
+	Hit:    hitWitherSkull,
+}
+
+// hitWitherSkull hurts and withers the entity hit by a wither skull and
+// creates a small explosion at the position of the impact. Withers hit by a
+// wither skull are not affected.
+func hitWitherSkull(e *Ent, target trace.Result) {
+	if r, ok := target.(trace.EntityResult); ok {
+		if _, wither := r.Entity().Type().(WitherType); wither {
+			// Withers are immune to wither skulls.
+			return
+		}
+		if l, ok := r.Entity().(Living); ok {
+			src := ProjectileDamageSource{Projectile: e, Owner: e.Behaviour().(*ProjectileBehaviour).Owner()}
+			if _, vulnerable := l.Hurt(8, src); vulnerable {
+				l.AddEffect(effect.New(effect.Wither{}, 2, time.Second*10))
+			}
+		}
+	}
+	block.ExplosionConfig{Size: 1}.Explode(e.World(), target.Position())
+}
+
+// WitherSkullType is a world.EntityType implementation for the black wither
+// skull.
+type WitherSkullType struct{}
+
+func (WitherSkullType) EncodeEntity() string { return "minecraft:wither_skull" }
+func (WitherSkullType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.15625, 0, -0.15625, 0.15625, 0.3125, 0.15625)
+}
+
+func (WitherSkullType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeWitherSkull(m, false)
+}
+
+func (WitherSkullType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeWitherSkull(e)
+}
+
+// WitherSkullDangerousType is a world.EntityType implementation for the blue
+// wither skull.
+type WitherSkullDangerousType struct{}
+
+func (WitherSkullDangerousType) EncodeEntity() string { return "minecraft:wither_skull_dangerous" }
+func (WitherSkullDangerousType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.15625, 0, -0.15625, 0.15625, 0.3125, 0.15625)
+}
+
+func (WitherSkullDangerousType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeWitherSkull(m, true)
+}
+
+func (WitherSkullDangerousType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeWitherSkull(e)
+}
+
+// decodeWitherSkull decodes a wither skull from the NBT map passed.
+func decodeWitherSkull(m map[string]any, dangerous bool) *Ent {
+	s := NewWitherSkull(nbtconv.Vec3(m, "Pos"), nil, dangerous)
+	s.vel = nbtconv.Vec3(m, "Motion")
+	return s
+}
+
+// encodeWitherSkull encodes a wither skull into an NBT map.
+func encodeWitherSkull(e world.Entity) map[string]any {
+	s := e.(*Ent)
+	return map[string]any{
+		"Pos":    nbtconv.Vec3ToFloat32Slice(s.Position()),
+		"Motion": nbtconv.Vec3ToFloat32Slice(s.Velocity()),
+	}
+}
@@ -0,0 +1,222 @@
+package event
+
+import (
+	"slices"
+	"sync"
+)
+
+// Priority is the priority of a listener subscribed to a Bus. Listeners with a lower priority are called first,
+// so that listeners with a higher priority have the final say over the outcome of an event. Listeners with the
+// same priority are called in the order that they were subscribed in.
+type Priority int
+
+const (
+	PriorityLowest Priority = iota
+	PriorityLow
+	PriorityNormal
+	PriorityHigh
+	PriorityHighest
+	// PriorityMonitor listeners are called after all other listeners, once the outcome of the event is final.
+	// They are meant to observe events, such as for logging, and may not change their outcome: The Context
+	// passed to them cannot be cancelled and they must not change any values passed to them by pointer.
+	PriorityMonitor
+)
+
+// Bus passes events to multiple listeners of type H, which is generally an interface with a method for every
+// event, such as player.Handler. Listeners may be subscribed with a Priority and unsubscribed at any time. A
+// Bus is safe for concurrent use. The zero value of a Bus is ready for use.
+type Bus[H any] struct {
+	mu sync.Mutex
+	// subs holds the listeners subscribed, ordered by their priority. The slice is never modified after it is
+	// stored, so that events may be dispatched without holding mu.
+	subs []*subscription[H]
+	// closed is true once Close was called. Listeners subscribed afterwards are not added.
+	closed bool
+}
+
+// subscription is a listener subscribed to a Bus.
+type subscription[H any] struct {
+	h        H
+	priority Priority
+	// q is non-nil for asynchronous listeners.
+	q *queue
+}
+
+// Subscribe subscribes a listener to the Bus with the Priority passed. The function returned unsubscribes the
+// listener again. Listeners subscribed after the Bus was closed using Close are not added and are never passed
+// any events.
+func (b *Bus[H]) Subscribe(h H, priority Priority) (unsubscribe func()) {
+	return b.add(&subscription[H]{h: h, priority: priority})
+}
+
+// SubscribeAsync subscribes a listener to the Bus that is called on a separate goroutine after the outcome of
+// an event is final, so that slow listeners, such as those that write to a database, do not delay the event.
+// Like listeners with PriorityMonitor, asynchronous listeners cannot change the outcome of events: They are
+// passed copies of values passed by pointer. Events are passed to the listener in the order that they
+// happened in. The function returned unsubscribes the listener again, after which it is passed the events
+// already dispatched.
+func (b *Bus[H]) SubscribeAsync(h H) (unsubscribe func()) {
+	q := newQueue()
+	remove := b.add(&subscription[H]{h: h, priority: PriorityMonitor, q: q})
+	return func() {
+		remove()
+		q.close()
+	}
+}
+
+// add adds a subscription to the Bus and returns a function that removes it again. If the Bus is closed, the
+// subscription is not added and its queue, if any, is closed.
+func (b *Bus[H]) add(s *subscription[H]) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		if s.q != nil {
+			s.q.close()
+		}
+		return func() {}
+	}
+	subs := slices.Clone(b.subs)
+	i, _ := slices.BinarySearchFunc(subs, s.priority+1, func(s *subscription[H], p Priority) int {
+		return int(s.priority - p)
+	})
+	b.subs = slices.Insert(subs, i, s)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.subs = slices.DeleteFunc(slices.Clone(b.subs), func(other *subscription[H]) bool { return other == s })
+		})
+	}
+}
+
+// Dispatch passes an event to all listeners subscribed to the Bus. call is called for every synchronous
+// listener with the Context of the event, which is read-only for listeners with PriorityMonitor. ctx may be
+// nil for events that cannot be cancelled. If any asynchronous listeners are subscribed, snapshot is called
+// once before the first listener with PriorityMonitor to copy the values of the event: The function it returns
+// is called on the goroutine of every asynchronous listener. If snapshot is nil, call is used for asynchronous
+// listeners too.
+func (b *Bus[H]) Dispatch(ctx *Context, call func(h H, ctx *Context), snapshot func() func(h H, ctx *Context)) {
+	b.mu.Lock()
+	subs := b.subs
+	b.mu.Unlock()
+	dispatch(subs, ctx, call, snapshot)
+}
+
+// Close unsubscribes all listeners from the Bus and passes a final event to them, as with Dispatch. No more
+// events are passed to the listeners afterwards and listeners subscribed after Close are not added.
+// Asynchronous listeners stop once they were passed all events dispatched before.
+func (b *Bus[H]) Close(ctx *Context, call func(h H, ctx *Context), snapshot func() func(h H, ctx *Context)) {
+	b.mu.Lock()
+	subs := b.subs
+	b.subs, b.closed = nil, true
+	b.mu.Unlock()
+
+	dispatch(subs, ctx, call, snapshot)
+	for _, s := range subs {
+		if s.q != nil {
+			s.q.close()
+		}
+	}
+}
+
+// dispatch passes an event to the subscriptions passed. See Bus.Dispatch for more information.
+func dispatch[H any](subs []*subscription[H], ctx *Context, call func(h H, ctx *Context), snapshot func() func(h H, ctx *Context)) {
+	var monitorCtx *Context
+	var async func(h H, ctx *Context)
+	for _, s := range subs {
+		c := ctx
+		if s.priority >= PriorityMonitor && monitorCtx == nil {
+			// The outcome of the event is final once the first monitor is reached, so the values of the event
+			// are copied before any monitor is called.
+			monitorCtx = &Context{readOnly: true}
+			if ctx != nil {
+				monitorCtx.cancel = ctx.cancel
+			}
+			async = call
+			if snapshot != nil && slices.ContainsFunc(subs, func(s *subscription[H]) bool { return s.q != nil }) {
+				async = snapshot()
+			}
+		}
+		if s.priority >= PriorityMonitor && ctx != nil {
+			c = monitorCtx
+		}
+		if s.q == nil {
+			call(s.h, c)
+			continue
+		}
+		h := s.h
+		s.q.push(func() { async(h, c) })
+	}
+}
+
+// Clone returns a copy of the value that p points to, or nil if p is nil. It may be used in snapshot functions
+// passed to Bus.Dispatch to copy values passed by pointer.
+func Clone[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// queue runs functions pushed to it one by one on a separate goroutine.
+type queue struct {
+	mu     sync.Mutex
+	fs     []func()
+	closed bool
+	notify chan struct{}
+}
+
+// newQueue creates a queue and starts its goroutine.
+func newQueue() *queue {
+	q := &queue{notify: make(chan struct{}, 1)}
+	go q.run()
+	return q
+}
+
+// push adds a function to the queue. Nothing happens if the queue is closed.
+func (q *queue) push(f func()) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.fs = append(q.fs, f)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// close stops the goroutine of the queue once all functions already pushed were run.
+func (q *queue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+// signal wakes up the goroutine of the queue.
+func (q *queue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run runs the functions pushed to the queue until it is closed.
+func (q *queue) run() {
+	for range q.notify {
+		q.mu.Lock()
+		fs, closed := q.fs, q.closed
+		q.fs = nil
+		q.mu.Unlock()
+
+		for _, f := range fs {
+			f()
+		}
+		if closed {
+			return
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package event
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// listener is a listener used in tests that records its name when called.
+type listener struct {
+	name   string
+	record func(name string, ctx *Context)
+}
+
+func dispatchTo(l listener, ctx *Context) {
+	l.record(l.name, ctx)
+}
+
+func TestBusPriorityOrder(t *testing.T) {
+	var b Bus[listener]
+	var order []string
+	record := func(name string, _ *Context) { order = append(order, name) }
+
+	b.Subscribe(listener{"monitor", record}, PriorityMonitor)
+	b.Subscribe(listener{"high", record}, PriorityHigh)
+	b.Subscribe(listener{"normal1", record}, PriorityNormal)
+	b.Subscribe(listener{"lowest", record}, PriorityLowest)
+	b.Subscribe(listener{"normal2", record}, PriorityNormal)
+	b.Dispatch(C(), dispatchTo, nil)
+
+	want := []string{"lowest", "normal1", "normal2", "high", "monitor"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Dispatch: got order %v, want %v", order, want)
+	}
+}
+
+func TestBusCancellation(t *testing.T) {
+	var b Bus[listener]
+	var seen []bool
+	b.Subscribe(listener{"cancel", func(_ string, ctx *Context) { ctx.Cancel() }}, PriorityLow)
+	b.Subscribe(listener{"observe", func(_ string, ctx *Context) { seen = append(seen, ctx.Cancelled()) }}, PriorityNormal)
+	b.Subscribe(listener{"uncancel-attempt", func(_ string, ctx *Context) {
+		seen = append(seen, ctx.Cancelled())
+		ctx.Cancel()
+	}}, PriorityMonitor)
+
+	ctx := C()
+	b.Dispatch(ctx, dispatchTo, nil)
+	if !ctx.Cancelled() {
+		t.Fatalf("Dispatch: context not cancelled")
+	}
+	if !slices.Equal(seen, []bool{true, true}) {
+		t.Errorf("Dispatch: listeners saw cancellation %v, want [true true]", seen)
+	}
+
+	// A monitor may not cancel an event that was not cancelled before.
+	var b2 Bus[listener]
+	b2.Subscribe(listener{"monitor", func(_ string, ctx *Context) { ctx.Cancel() }}, PriorityMonitor)
+	ctx = C()
+	b2.Dispatch(ctx, dispatchTo, nil)
+	if ctx.Cancelled() {
+		t.Errorf("Dispatch: monitor cancelled the event")
+	}
+}
+
+func TestBusUnsubscribe(t *testing.T) {
+	var b Bus[listener]
+	n := 0
+	unsubscribe := b.Subscribe(listener{"l", func(string, *Context) { n++ }}, PriorityNormal)
+	b.Dispatch(C(), dispatchTo, nil)
+	unsubscribe()
+	unsubscribe()
+	b.Dispatch(C(), dispatchTo, nil)
+	if n != 1 {
+		t.Errorf("Dispatch: listener called %v times, want 1", n)
+	}
+}
+
+func TestBusClose(t *testing.T) {
+	var b Bus[listener]
+	var calls []string
+	record := func(name string, _ *Context) { calls = append(calls, name) }
+	b.Subscribe(listener{"before", record}, PriorityNormal)
+	b.Close(nil, dispatchTo, nil)
+	b.Subscribe(listener{"after", record}, PriorityNormal)
+	b.SubscribeAsync(listener{"async", record})()
+	b.Dispatch(C(), dispatchTo, nil)
+
+	if !slices.Equal(calls, []string{"before"}) {
+		t.Errorf("Close: got calls %v, want [before]", calls)
+	}
+}
+
+func TestBusAsync(t *testing.T) {
+	var b Bus[listener]
+	var mu sync.Mutex
+	var got []int
+	done := make(chan struct{})
+
+	value := 0
+	b.Subscribe(listener{"sync", func(string, *Context) { value++ }}, PriorityNormal)
+	b.SubscribeAsync(listener{"async", func(name string, _ *Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		if got = append(got, len(name)); len(got) == 3 {
+			close(done)
+		}
+	}})
+	for i := 0; i < 3; i++ {
+		v := i
+		b.Dispatch(C(), dispatchTo, func() func(listener, *Context) {
+			return func(l listener, ctx *Context) {
+				l.record(string(make([]byte, v)), ctx)
+			}
+		})
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("SubscribeAsync: listener was not called")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("SubscribeAsync: got events %v, want [0 1 2]", got)
+	}
+	if value != 3 {
+		t.Errorf("Dispatch: synchronous listener called %v times, want 3", value)
+	}
+}
+
+func TestClone(t *testing.T) {
+	if Clone[int](nil) != nil {
+		t.Errorf("Clone(nil): expected nil")
+	}
+	v := 5
+	c := Clone(&v)
+	*c = 6
+	if v != 5 {
+		t.Errorf("Clone: original was modified")
+	}
+}
@@ -4,6 +4,9 @@ package event
 // the result of the event.
 type Context struct {
 	cancel bool
+	// readOnly is true for contexts passed to listeners with PriorityMonitor, which may not change the result
+	// of the event.
+	readOnly bool
 }
 
 // C returns a new event context.
@@ -16,7 +19,10 @@ func (ctx *Context) Cancelled() bool {
 	return ctx.cancel
 }
 
-// Cancel cancels the context.
+// Cancel cancels the context. Cancel has no effect if the context was passed to a listener with
+// PriorityMonitor, as the outcome of the event is already final.
 func (ctx *Context) Cancel() {
-	ctx.cancel = true
+	if !ctx.readOnly {
+		ctx.cancel = true
+	}
 }
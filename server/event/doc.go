@@ -3,4 +3,6 @@
 // Generally, the caller of `event.C()` calls `Context.Cancelled()` to check if the `Context` was cancelled (using
 // `Context.Cancel()`) by whatever code it was passed to.
 // who is then able to cancel it by calling `Context.Cancel()`.
+// Events may be passed to multiple listeners using a `Bus`, which calls listeners in order of their `Priority`,
+// followed by read-only monitor listeners and asynchronous listeners.
 package event
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// FloodProtection holds the limits that protect a Server against clients flooding it with packets or logins,
+// such as spam bots. Clients that exceed one of the limits are disconnected and, if BlockDuration is non-zero,
+// refused when joining from the same IP address for BlockDuration afterwards. Limits that are 0 are not
+// enforced. The packet limits are only enforced by the Listener created by UserConfig.Config.
+type FloodProtection struct {
+	// MaxPacketsPerSecond is the maximum amount of packets that a single connection may send per second.
+	MaxPacketsPerSecond int
+	// MaxPacketSize is the maximum size in bytes of the payload of a single packet sent by a connection.
+	MaxPacketSize int
+	// MaxLoginsPerMinute is the maximum amount of times that clients from the same IP address may join per
+	// minute.
+	MaxLoginsPerMinute int
+	// BlockDuration is the duration for which clients from the IP address of a client that exceeded one of the
+	// limits are refused when joining.
+	BlockDuration time.Duration
+}
+
+// floodGuard enforces the limits of a FloodProtection for the connections of a Listener.
+type floodGuard struct {
+	conf FloodProtection
+
+	mu        sync.Mutex
+	local     string
+	clients   map[string]*floodClient
+	logins    map[string][]time.Time
+	blocked   map[string]time.Time
+	lastSweep time.Time
+}
+
+// floodClient holds the packets sent by a single connection in the current second.
+type floodClient struct {
+	start     time.Time
+	packets   int
+	violation string
+
+	// ip and kick are set once the connection was accepted. kick disconnects the connection with a message.
+	// Clients of accepted connections are kept until the connection is released, rather than swept.
+	ip   string
+	kick func(msg string)
+}
+
+// newFloodGuard creates a floodGuard that enforces the limits of the FloodProtection passed.
+func newFloodGuard(conf FloodProtection) *floodGuard {
+	return &floodGuard{
+		conf:    conf,
+		clients: make(map[string]*floodClient),
+		logins:  make(map[string][]time.Time),
+		blocked: make(map[string]time.Time),
+	}
+}
+
+// handlePacket counts a packet sent by a connection and checks it against the packet limits. It is passed to
+// minecraft.ListenConfig as PacketFunc. Connections exceeding a limit are disconnected if they were already
+// accepted, or refused by admit once they are.
+func (g *floodGuard) handlePacket(header packet.Header, payload []byte, src, _ net.Addr) {
+	if g.conf.MaxPacketsPerSecond <= 0 && g.conf.MaxPacketSize <= 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	addr := src.String()
+	if addr == g.local {
+		// The packet was written by the server rather than read from a connection.
+		return
+	}
+	now := time.Now()
+	g.sweep(now)
+
+	c, ok := g.clients[addr]
+	if !ok {
+		c = &floodClient{start: now}
+		g.clients[addr] = c
+	}
+	if now.Sub(c.start) >= time.Second {
+		c.start, c.packets = now, 0
+	}
+	if header.PacketID != packet.IDResourcePackChunkRequest {
+		// Clients downloading resource packs request their chunks as fast as they can, so these requests are
+		// not counted.
+		c.packets++
+	}
+
+	switch {
+	case g.conf.MaxPacketSize > 0 && len(payload) > g.conf.MaxPacketSize:
+		g.violate(c, "Sent a packet that was too large.", now)
+	case g.conf.MaxPacketsPerSecond > 0 && c.packets > g.conf.MaxPacketsPerSecond:
+		g.violate(c, "Sent too many packets.", now)
+	}
+}
+
+// violate records a violation of the packet limits by the floodClient passed. If the connection of the client
+// was already accepted, it is disconnected and its IP address is blocked.
+func (g *floodGuard) violate(c *floodClient, msg string, now time.Time) {
+	if c.violation != "" {
+		return
+	}
+	c.violation = msg
+	if c.kick != nil {
+		g.block(c.ip, now)
+		// Disconnecting writes to the connection, which must not happen on the goroutine reading from it.
+		go c.kick(msg)
+	}
+}
+
+// admit checks if the accepted connection passed may join, returning the message to disconnect it with if not.
+// conn is the connection as accepted by the minecraft.Listener, while addr is the address that the connection
+// reports to the Server, which differs from that of conn if it was forwarded by a proxy. If the connection may
+// join, it is disconnected using kick as soon as it exceeds one of the packet limits.
+func (g *floodGuard) admit(conn, addr net.Addr, kick func(msg string)) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	g.sweep(now)
+
+	ip := addrIP(addr)
+	if until, ok := g.blocked[ip]; ok && now.Before(until) {
+		return "You are temporarily blocked from joining this server.", false
+	}
+	c, ok := g.clients[conn.String()]
+	if !ok {
+		c = &floodClient{start: now}
+		g.clients[conn.String()] = c
+	}
+	if c.violation != "" {
+		g.block(ip, now)
+		return c.violation, false
+	}
+	if g.conf.MaxLoginsPerMinute > 0 {
+		logins := g.logins[ip][:0]
+		for _, t := range g.logins[ip] {
+			if now.Sub(t) < time.Minute {
+				logins = append(logins, t)
+			}
+		}
+		g.logins[ip] = append(logins, now)
+		if len(g.logins[ip]) > g.conf.MaxLoginsPerMinute {
+			g.block(ip, now)
+			return "You are joining too often. Please wait before joining again.", false
+		}
+	}
+	c.ip, c.kick = ip, kick
+	return "", true
+}
+
+// release removes the client of the connection with the address passed, as accepted by the
+// minecraft.Listener. It is called when the connection is closed, so that a later connection from the same
+// address starts without the packets counted for the previous one.
+func (g *floodGuard) release(conn net.Addr) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.clients, conn.String())
+}
+
+// block blocks the IP address passed from joining for the BlockDuration of the floodGuard.
+func (g *floodGuard) block(ip string, now time.Time) {
+	if g.conf.BlockDuration > 0 {
+		g.blocked[ip] = now.Add(g.conf.BlockDuration)
+	}
+}
+
+// sweep removes clients of connections that were never accepted and have not sent packets for a while,
+// logins older than a minute and blocks that have expired, so that the floodGuard does not keep growing. It
+// does so at most once every few seconds. Clients of accepted connections are removed using release instead.
+func (g *floodGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < time.Second*10 {
+		return
+	}
+	g.lastSweep = now
+	for addr, c := range g.clients {
+		if c.kick == nil && now.Sub(c.start) > time.Minute {
+			delete(g.clients, addr)
+		}
+	}
+	for ip, logins := range g.logins {
+		if len(logins) == 0 || now.Sub(logins[len(logins)-1]) >= time.Minute {
+			delete(g.logins, ip)
+		}
+	}
+	for ip, until := range g.blocked {
+		if !now.Before(until) {
+			delete(g.blocked, ip)
+		}
+	}
+}
+
+// addrIP returns the IP address of a net.Addr as a string.
+func addrIP(addr net.Addr) string {
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// kickRecorder records the message that a connection admitted by a floodGuard was kicked with.
+type kickRecorder chan string
+
+func (k kickRecorder) kick(msg string) {
+	k <- msg
+}
+
+// wait waits for the connection to be kicked, returning the message and false if it was not kicked in time.
+func (k kickRecorder) wait() (string, bool) {
+	select {
+	case msg := <-k:
+		return msg, true
+	case <-time.After(time.Second):
+		return "", false
+	}
+}
+
+func udpAddr(ip string, port int) net.Addr {
+	return &net.UDPAddr{IP: net.ParseIP(ip), Port: port}
+}
+
+func sendPackets(g *floodGuard, src net.Addr, n, size int) {
+	for range n {
+		g.handlePacket(packet.Header{PacketID: packet.IDText}, make([]byte, size), src, nil)
+	}
+}
+
+func TestFloodPacketsPerSecond(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxPacketsPerSecond: 10, BlockDuration: time.Minute})
+	addr := udpAddr("10.0.0.1", 1000)
+	k := make(kickRecorder, 1)
+	if msg, ok := g.admit(addr, addr, k.kick); !ok {
+		t.Fatalf("admit: refused with %q", msg)
+	}
+	sendPackets(g, addr, 10, 1)
+	select {
+	case msg := <-k:
+		t.Fatalf("handlePacket: kicked with %q within the limit", msg)
+	default:
+	}
+	sendPackets(g, addr, 1, 1)
+	if _, ok := k.wait(); !ok {
+		t.Fatalf("handlePacket: not kicked after exceeding the limit")
+	}
+	if _, ok := g.admit(udpAddr("10.0.0.1", 1001), udpAddr("10.0.0.1", 1001), nil); ok {
+		t.Errorf("admit: IP address not blocked after exceeding the limit")
+	}
+}
+
+func TestFloodResourcePackChunksNotCounted(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxPacketsPerSecond: 10})
+	addr := udpAddr("10.0.0.1", 1000)
+	for range 100 {
+		g.handlePacket(packet.Header{PacketID: packet.IDResourcePackChunkRequest}, nil, addr, nil)
+	}
+	if msg, ok := g.admit(addr, addr, nil); !ok {
+		t.Errorf("admit: refused with %q after resource pack chunk requests", msg)
+	}
+}
+
+func TestFloodViolationBeforeAdmit(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxPacketSize: 100, BlockDuration: time.Minute})
+	addr := udpAddr("10.0.0.1", 1000)
+	sendPackets(g, addr, 1, 101)
+	if _, ok := g.admit(addr, addr, nil); ok {
+		t.Fatalf("admit: connection that sent a too large packet was admitted")
+	}
+	if _, ok := g.admit(udpAddr("10.0.0.1", 1001), udpAddr("10.0.0.1", 1001), nil); ok {
+		t.Errorf("admit: IP address not blocked after a violation")
+	}
+	if msg, ok := g.admit(udpAddr("10.0.0.2", 1000), udpAddr("10.0.0.2", 1000), nil); !ok {
+		t.Errorf("admit: other IP address refused with %q", msg)
+	}
+}
+
+func TestFloodLocalPacketsIgnored(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxPacketsPerSecond: 1})
+	local := udpAddr("127.0.0.1", 19132)
+	g.local = local.String()
+	sendPackets(g, local, 10, 1)
+	if len(g.clients) != 0 {
+		t.Errorf("handlePacket: packets written by the server were counted")
+	}
+}
+
+func TestFloodLoginsPerMinute(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxLoginsPerMinute: 3})
+	for i := range 3 {
+		addr := udpAddr("10.0.0.1", 1000+i)
+		if msg, ok := g.admit(addr, addr, nil); !ok {
+			t.Fatalf("admit: login %v refused with %q", i+1, msg)
+		}
+	}
+	addr := udpAddr("10.0.0.1", 2000)
+	if _, ok := g.admit(addr, addr, nil); ok {
+		t.Errorf("admit: login exceeding the limit was admitted")
+	}
+	addr = udpAddr("10.0.0.2", 1000)
+	if msg, ok := g.admit(addr, addr, nil); !ok {
+		t.Errorf("admit: login from other IP address refused with %q", msg)
+	}
+}
+
+func TestFloodAdmittedClientKeptUntilRelease(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxPacketsPerSecond: 10, BlockDuration: time.Minute})
+	addr := udpAddr("10.0.0.1", 1000)
+	k := make(kickRecorder, 1)
+	if msg, ok := g.admit(addr, addr, k.kick); !ok {
+		t.Fatalf("admit: refused with %q", msg)
+	}
+	// A sweep long after the connection was admitted must not forget that it was admitted, or it could no
+	// longer be kicked.
+	g.mu.Lock()
+	g.sweep(time.Now().Add(time.Hour))
+	g.mu.Unlock()
+	if _, ok := g.clients[addr.String()]; !ok {
+		t.Fatalf("sweep: admitted client was removed")
+	}
+	sendPackets(g, addr, 11, 1)
+	if _, ok := k.wait(); !ok {
+		t.Errorf("handlePacket: admitted client not kicked after a sweep")
+	}
+
+	g.release(addr)
+	if _, ok := g.clients[addr.String()]; ok {
+		t.Errorf("release: client was not removed")
+	}
+}
+
+func TestFloodSweepRemovesUnadmittedClients(t *testing.T) {
+	g := newFloodGuard(FloodProtection{MaxPacketsPerSecond: 10})
+	addr := udpAddr("10.0.0.1", 1000)
+	sendPackets(g, addr, 1, 1)
+
+	g.mu.Lock()
+	g.sweep(time.Now().Add(time.Hour))
+	g.mu.Unlock()
+	if _, ok := g.clients[addr.String()]; ok {
+		t.Errorf("sweep: client of a connection that was never admitted was kept")
+	}
+}
@@ -0,0 +1,80 @@
+// Package crash runs functions, such as saving data, when a goroutine of the
+// server panics, before the panic crashes the program.
+package crash
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	mu       sync.Mutex
+	handlers = map[*func()]struct{}{}
+	once     sync.Once
+)
+
+// Timeout is the maximum duration that the functions registered using Handle
+// may run for after a panic. The goroutine that panicked may have left locks
+// held, so the program crashes after Timeout even if the functions have not
+// yet returned.
+const Timeout = time.Second * 30
+
+// Handle registers f to be called when a goroutine that recovers using
+// Recover panics. The function returned removes f again.
+func Handle(f func()) (remove func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	h := &f
+	handlers[h] = struct{}{}
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(handlers, h)
+	}
+}
+
+// Recover recovers from a panic in the goroutine that it is deferred in, calls
+// all functions registered using Handle and panics again with the same value,
+// so that the program still crashes with the original stack trace. Recover must
+// be deferred directly, as in `defer crash.Recover()`.
+func Recover() {
+	if v := recover(); v != nil {
+		run()
+		panic(v)
+	}
+}
+
+// run calls all functions registered using Handle concurrently and waits for
+// them to return for at most Timeout. The functions are only called for the
+// first panic: Goroutines panicking afterwards wait for the functions to
+// return just the same.
+func run() {
+	once.Do(func() {
+		mu.Lock()
+		fs := make([]func(), 0, len(handlers))
+		for h := range handlers {
+			fs = append(fs, *h)
+		}
+		mu.Unlock()
+
+		var wg sync.WaitGroup
+		for _, f := range fs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// A function panicking itself must not hide the original panic.
+				defer func() { _ = recover() }()
+				f()
+			}()
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(Timeout):
+		}
+	})
+}
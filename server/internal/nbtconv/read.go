@@ -86,6 +86,20 @@ func Slice[T any](m map[string]any, k string) []T {
 	return v
 }
 
+// Strings reads a list of string values from a map at key k.
+func Strings(m map[string]any, k string) []string {
+	if v, ok := m[k].([]string); ok {
+		return v
+	}
+	var v []string
+	for _, s := range Slice[any](m, k) {
+		if str, ok := s.(string); ok {
+			v = append(v, str)
+		}
+	}
+	return v
+}
+
 // Vec3 converts x, y and z values in an NBT map to an mgl64.Vec3.
 func Vec3(x map[string]any, k string) mgl64.Vec3 {
 	if i, ok := x[k].([]any); ok {
@@ -207,7 +221,8 @@ func readItemStack(m, t map[string]any) item.Stack {
 	if n, ok := it.(world.NBTer); ok {
 		it = n.DecodeNBT(t).(world.Item)
 	}
-	return item.NewStack(it, int(Uint8(m, "Count")))
+	s := item.NewStack(it, int(Uint8(m, "Count")))
+	return s.WithCanPlaceOn(Strings(m, "CanPlaceOn")...).WithCanDestroy(Strings(m, "CanDestroy")...)
 }
 
 // readDamage reads the damage value stored in the NBT with the Damage tag and saves it to the item.Stack passed.
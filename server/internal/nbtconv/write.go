@@ -54,6 +54,12 @@ func writeItemStack(m, t map[string]any, s item.Stack) {
 		m["Block"] = v
 	}
 	m["Count"] = byte(s.Count())
+	if blocks := s.CanPlaceOn(); len(blocks) != 0 {
+		m["CanPlaceOn"] = blocks
+	}
+	if blocks := s.CanDestroy(); len(blocks) != 0 {
+		m["CanDestroy"] = blocks
+	}
 	if len(t) > 0 {
 		m["tag"] = t
 	}
@@ -5,12 +5,14 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
+	"strings"
 	"time"
 )
 
 // BucketContent is the content of a bucket.
 type BucketContent struct {
 	liquid world.Liquid
+	block  world.Block
 	milk   bool
 }
 
@@ -19,6 +21,11 @@ func LiquidBucketContent(l world.Liquid) BucketContent {
 	return BucketContent{liquid: l}
 }
 
+// BlockBucketContent returns a new BucketContent with the Bucketable block passed in, such as powder snow.
+func BlockBucketContent(b Bucketable) BucketContent {
+	return BucketContent{block: b}
+}
+
 // MilkBucketContent returns a new BucketContent with the milk flag set.
 func MilkBucketContent() BucketContent {
 	return BucketContent{milk: true}
@@ -30,12 +37,21 @@ func (b BucketContent) Liquid() (world.Liquid, bool) {
 	return b.liquid, b.liquid != nil
 }
 
+// Block returns the world.Block that a Bucket with this BucketContent places. If this BucketContent does not
+// place a block other than a liquid, false is returned.
+func (b BucketContent) Block() (world.Block, bool) {
+	return b.block, b.block != nil
+}
+
 // String converts the BucketContent to a string.
 func (b BucketContent) String() string {
 	if b.milk {
 		return "milk"
 	} else if b.liquid != nil {
 		return b.liquid.LiquidType()
+	} else if b.block != nil {
+		name, _ := b.block.EncodeBlock()
+		return strings.TrimPrefix(name, "minecraft:")
 	}
 	return ""
 }
@@ -48,6 +64,14 @@ func (b BucketContent) LiquidType() string {
 	return "milk"
 }
 
+// Bucketable represents a block that may be picked up using an empty Bucket, such as powder snow. The Bucket
+// is filled with a BlockBucketContent holding the block, which places the block again when used.
+type Bucketable interface {
+	world.Block
+	// Bucketable returns true if the block may currently be picked up using a Bucket.
+	Bucketable() bool
+}
+
 // Bucket is a tool used to carry water, lava and fish.
 type Bucket struct {
 	// Content is the content that the bucket has. By default, this value resolves to an empty bucket.
@@ -87,7 +111,7 @@ func (b Bucket) Consume(_ *world.World, c Consumer) Stack {
 
 // Empty returns true if the bucket is empty.
 func (b Bucket) Empty() bool {
-	return b.Content.liquid == nil && !b.Content.milk
+	return b.Content.liquid == nil && b.Content.block == nil && !b.Content.milk
 }
 
 // FuelInfo ...
@@ -106,6 +130,9 @@ func (b Bucket) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.
 	if b.Empty() {
 		return b.fillFrom(pos, w, ctx)
 	}
+	if bl, ok := b.Content.Block(); ok {
+		return b.placeBlock(pos, face, bl, w, ctx)
+	}
 	liq := b.Content.liquid.WithDepth(8, false)
 	if bl := w.Block(pos); canDisplace(bl, liq) || replaceableWith(bl, liq) {
 		w.SetLiquid(pos, liq)
@@ -122,9 +149,35 @@ func (b Bucket) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.
 	return true
 }
 
+// placeBlock places the block held by a bucket at the position passed, or at the side of it if the block at
+// the position cannot be replaced. If neither position can be replaced, placeBlock returns false.
+func (b Bucket) placeBlock(pos cube.Pos, face cube.Face, bl world.Block, w *world.World, ctx *UseContext) bool {
+	if !replaceableWith(w.Block(pos), bl) {
+		if pos = pos.Side(face); !replaceableWith(w.Block(pos), bl) {
+			return false
+		}
+	}
+	w.SetBlock(pos, bl, nil)
+	w.PlaySound(pos.Vec3Centre(), sound.BucketEmpty{Block: bl})
+
+	ctx.NewItem = NewStack(Bucket{}, 1)
+	ctx.NewItemSurvivalOnly = true
+	ctx.SubtractFromCount(1)
+	return true
+}
+
 // fillFrom fills a bucket from the liquid at the position passed in the world. If there is no liquid or if
-// the liquid is no source, fillFrom returns false.
+// the liquid is no source, fillFrom returns false. Bucketable blocks at the position are picked up instead.
 func (b Bucket) fillFrom(pos cube.Pos, w *world.World, ctx *UseContext) bool {
+	if bl, ok := w.Block(pos).(Bucketable); ok && bl.Bucketable() {
+		w.SetBlock(pos, nil, nil)
+		w.PlaySound(pos.Vec3Centre(), sound.BucketFill{Block: bl})
+
+		ctx.NewItem = NewStack(Bucket{Content: BlockBucketContent(bl)}, 1)
+		ctx.NewItemSurvivalOnly = true
+		ctx.SubtractFromCount(1)
+		return true
+	}
 	liquid, ok := w.Liquid(pos)
 	if !ok {
 		return false
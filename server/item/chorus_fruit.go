@@ -0,0 +1,62 @@
+package item
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/go-gl/mathgl/mgl64"
+	"math/rand"
+	"time"
+)
+
+// ChorusFruit is a food item obtained from chorus plants in the End. Eating chorus fruit teleports the consumer
+// to a random safe position nearby.
+type ChorusFruit struct{}
+
+// AlwaysConsumable ...
+func (ChorusFruit) AlwaysConsumable() bool {
+	return true
+}
+
+// ConsumeDuration ...
+func (ChorusFruit) ConsumeDuration() time.Duration {
+	return DefaultConsumeDuration
+}
+
+// Consume ...
+func (ChorusFruit) Consume(w *world.World, c Consumer) Stack {
+	c.Saturate(4, 2.4)
+	t, ok := c.(interface{ Teleport(pos mgl64.Vec3) })
+	if !ok {
+		return Stack{}
+	}
+	// Up to 16 random positions within 8 blocks are tried, of which the first
+	// one that the consumer can stand on is used.
+	origin := c.Position()
+	for i := 0; i < 16; i++ {
+		pos := cube.PosFromVec3(origin.Add(mgl64.Vec3{rand.Float64()*16 - 8, float64(rand.Intn(16) - 8), rand.Float64()*16 - 8}))
+		if pos.OutOfBounds(w.Range()) || !chorusFruitSafe(w, pos) {
+			continue
+		}
+		w.PlaySound(origin, sound.Teleport{})
+		t.Teleport(pos.Vec3Middle())
+		w.PlaySound(pos.Vec3Middle(), sound.Teleport{})
+		break
+	}
+	return Stack{}
+}
+
+// chorusFruitSafe checks if the position passed is free and the block below it is solid, so that an entity
+// may be teleported there by eating chorus fruit.
+func chorusFruitSafe(w *world.World, pos cube.Pos) bool {
+	below, above := pos.Side(cube.FaceDown), pos.Side(cube.FaceUp)
+	if len(w.Block(pos).Model().BBox(pos, w)) != 0 || len(w.Block(above).Model().BBox(above, w)) != 0 {
+		return false
+	}
+	return w.Block(below).Model().FaceSolid(below, cube.FaceUp, w)
+}
+
+// EncodeItem ...
+func (ChorusFruit) EncodeItem() (name string, meta int16) {
+	return "minecraft:chorus_fruit", 0
+}
@@ -0,0 +1,41 @@
+package enchantment
+
+import (
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Looting is a sword enchantment that causes mobs to drop more items when killed.
+type Looting struct{}
+
+// Name ...
+func (Looting) Name() string {
+	return "Looting"
+}
+
+// MaxLevel ...
+func (Looting) MaxLevel() int {
+	return 3
+}
+
+// Cost ...
+func (Looting) Cost(level int) (int, int) {
+	min := 15 + (level-1)*9
+	return min, min + 50
+}
+
+// Rarity ...
+func (Looting) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// CompatibleWithEnchantment ...
+func (Looting) CompatibleWithEnchantment(item.EnchantmentType) bool {
+	return true
+}
+
+// CompatibleWithItem ...
+func (Looting) CompatibleWithItem(i world.Item) bool {
+	t, ok := i.(item.Tool)
+	return ok && t.ToolType() == item.TypeSword
+}
@@ -0,0 +1,35 @@
+package item
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// EndCrystal is an item that can be placed on top of obsidian or bedrock to
+// create an end crystal. End crystals are used to respawn the ender dragon.
+type EndCrystal struct{}
+
+// UseOnBlock ...
+func (EndCrystal) UseOnBlock(pos cube.Pos, _ cube.Face, _ mgl64.Vec3, w *world.World, _ User, ctx *UseContext) bool {
+	if name, _ := w.Block(pos).EncodeBlock(); name != "minecraft:obsidian" && name != "minecraft:bedrock" {
+		return false
+	}
+	above := pos.Side(cube.FaceUp)
+	if w.Block(above) != air() || w.Block(above.Side(cube.FaceUp)) != air() {
+		return false
+	}
+	if len(w.EntitiesWithin(cube.Box(0, 0, 0, 1, 2, 1).Translate(above.Vec3()), nil)) > 0 {
+		return false
+	}
+	create := w.EntityRegistry().Config().EnderCrystal
+	w.AddEntity(create(above.Vec3Middle(), false))
+
+	ctx.SubtractFromCount(1)
+	return true
+}
+
+// EncodeItem ...
+func (EndCrystal) EncodeItem() (name string, meta int16) {
+	return "minecraft:end_crystal", 0
+}
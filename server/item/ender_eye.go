@@ -0,0 +1,31 @@
+package item
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/sound"
+)
+
+// EnderEye is an item used to locate strongholds and to activate end portals. When thrown, an eye of ender flies
+// towards the nearest stronghold.
+type EnderEye struct{}
+
+// Use ...
+func (EnderEye) Use(w *world.World, user User, ctx *UseContext) bool {
+	pos := eyePosition(user)
+	target, ok := w.LocateStructure("stronghold", cube.PosFromVec3(pos))
+	if !ok {
+		return false
+	}
+	create := w.EntityRegistry().Config().EyeOfEnder
+	w.AddEntity(create(pos, target.Vec3Centre()))
+	w.PlaySound(user.Position(), sound.ItemThrow{})
+
+	ctx.SubtractFromCount(1)
+	return true
+}
+
+// EncodeItem ...
+func (EnderEye) EncodeItem() (name string, meta int16) {
+	return "minecraft:ender_eye", 0
+}
@@ -0,0 +1,24 @@
+package item
+
+// FilledMap is a map that has been filled with the terrain of an area of a world. It may be displayed inside an item
+// frame to show its contents on a wall.
+type FilledMap struct {
+	// ID is the unique ID of the map. Maps with the same ID display the same contents.
+	ID int64
+}
+
+// DecodeNBT ...
+func (m FilledMap) DecodeNBT(data map[string]any) any {
+	m.ID, _ = data["map_uuid"].(int64)
+	return m
+}
+
+// EncodeNBT ...
+func (m FilledMap) EncodeNBT() map[string]any {
+	return map[string]any{"map_uuid": m.ID}
+}
+
+// EncodeItem ...
+func (FilledMap) EncodeItem() (name string, meta int16) {
+	return "minecraft:filled_map", 0
+}
@@ -0,0 +1,37 @@
+package item
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Lead is an item used to leash mobs, attaching them to the player holding the
+// lead. Leashed mobs may then be tied to a fence by using the fence.
+type Lead struct{}
+
+// UseOnEntity leashes the entity passed to the user if it may be leashed and
+// is not yet leashed.
+func (Lead) UseOnEntity(e world.Entity, _ *world.World, user User, ctx *UseContext) bool {
+	l, ok := e.(leashable)
+	if !ok {
+		return false
+	}
+	if _, leashed := l.LeashHolder(); leashed || !l.Leash(user) {
+		return false
+	}
+	ctx.SubtractFromCount(1)
+	return true
+}
+
+// EncodeItem ...
+func (Lead) EncodeItem() (name string, meta int16) {
+	return "minecraft:lead", 0
+}
+
+// leashable represents an entity that may be leashed using a Lead.
+type leashable interface {
+	world.Entity
+	// LeashHolder returns the entity that the leashable is leashed to.
+	LeashHolder() (world.Entity, bool)
+	// Leash leashes the leashable to the holder passed.
+	Leash(holder world.Entity) bool
+}
@@ -0,0 +1,64 @@
+package loot
+
+import "time"
+
+// Condition is a condition that must be satisfied for a Pool, Entry or Function to apply.
+type Condition interface {
+	// Satisfied checks if the Condition is satisfied in the Context passed.
+	Satisfied(ctx Context) bool
+}
+
+// KilledByPlayer is a Condition that is satisfied if the entity that the loot is generated for was killed by a
+// player.
+type KilledByPlayer struct{}
+
+// Satisfied ...
+func (KilledByPlayer) Satisfied(ctx Context) bool {
+	return ctx.KilledByPlayer
+}
+
+// RandomChance is a Condition that is satisfied with a fixed chance.
+type RandomChance struct {
+	// Chance is the chance, ranging from 0 to 1, that the Condition is satisfied.
+	Chance float64
+}
+
+// Satisfied ...
+func (r RandomChance) Satisfied(ctx Context) bool {
+	return ctx.Rand.Float64() < r.Chance
+}
+
+// RandomChanceWithLooting is a Condition that is satisfied with a chance that increases with the Looting level in
+// the Context.
+type RandomChanceWithLooting struct {
+	// Chance is the base chance, ranging from 0 to 1, that the Condition is satisfied.
+	Chance float64
+	// LootingMultiplier is added to the Chance for every level of Looting.
+	LootingMultiplier float64
+}
+
+// Satisfied ...
+func (r RandomChanceWithLooting) Satisfied(ctx Context) bool {
+	return ctx.Rand.Float64() < r.Chance+r.LootingMultiplier*float64(ctx.Looting)
+}
+
+// OnFire is a Condition that is satisfied if the entity that the loot is generated for is on fire.
+type OnFire struct{}
+
+// Satisfied ...
+func (OnFire) Satisfied(ctx Context) bool {
+	f, ok := ctx.This.(interface {
+		OnFireDuration() time.Duration
+	})
+	return ok && f.OnFireDuration() > 0
+}
+
+// satisfied checks if all conditions passed are satisfied in the Context.
+func satisfied(conditions []Condition, ctx Context) bool {
+	for _, c := range conditions {
+		if !c.Satisfied(ctx) {
+			return false
+		}
+	}
+	return true
+}
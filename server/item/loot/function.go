@@ -0,0 +1,139 @@
+package loot
+
+import (
+	"github.com/df-mc/dragonfly/server/item"
+)
+
+// Function is a function applied to the item stack produced by an Entry, such as setting its count or enchanting it.
+type Function interface {
+	// Apply applies the Function to the item stack passed and returns the resulting item stack.
+	Apply(s item.Stack, ctx Context) item.Stack
+}
+
+// SetCount is a Function that sets the count of the item stack to a random value in a Range. A count of 0 results
+// in the item stack being discarded.
+type SetCount struct {
+	// Count is the range of the count set.
+	Count Range
+}
+
+// Apply ...
+func (f SetCount) Apply(s item.Stack, ctx Context) item.Stack {
+	return s.Grow(f.Count.Int(ctx.Rand) - s.Count())
+}
+
+// LootingEnchant is a Function that increases the count of the item stack by a random value in a Range for every
+// level of Looting in the Context.
+type LootingEnchant struct {
+	// Count is the range of the count added per level of Looting.
+	Count Range
+}
+
+// Apply ...
+func (f LootingEnchant) Apply(s item.Stack, ctx Context) item.Stack {
+	if s.Empty() {
+		return s
+	}
+	n := 0
+	for i := 0; i < ctx.Looting; i++ {
+		n += f.Count.Int(ctx.Rand)
+	}
+	return s.Grow(min(n, s.MaxCount()-s.Count()))
+}
+
+// SetDamage is a Function that sets the durability of a durable item stack to a random fraction of its maximum
+// durability.
+type SetDamage struct {
+	// Min and Max are the minimum and maximum fraction, ranging from 0 to 1, of the durability left.
+	Min, Max float64
+}
+
+// Apply ...
+func (f SetDamage) Apply(s item.Stack, ctx Context) item.Stack {
+	if s.MaxDurability() == -1 {
+		return s
+	}
+	frac := f.Min + ctx.Rand.Float64()*(f.Max-f.Min)
+	return s.WithDurability(max(int(float64(s.MaxDurability())*frac), 1))
+}
+
+// EnchantRandomly is a Function that applies a single random enchantment that is compatible with the item stack.
+type EnchantRandomly struct {
+	// Treasure specifies if treasure enchantments, such as Mending, may also be selected.
+	Treasure bool
+}
+
+// Apply ...
+func (f EnchantRandomly) Apply(s item.Stack, ctx Context) item.Stack {
+	var candidates []item.EnchantmentType
+	for _, e := range item.Enchantments() {
+		if !e.CompatibleWithItem(s.Item()) || (!f.Treasure && treasure(e)) {
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+	if len(candidates) == 0 {
+		return s
+	}
+	e := candidates[ctx.Rand.Intn(len(candidates))]
+	return s.WithEnchantments(item.NewEnchantment(e, ctx.Rand.Intn(e.MaxLevel())+1))
+}
+
+// EnchantWithLevels is a Function that enchants the item stack as if it was enchanted in an enchanting table using
+// a random number of levels in a Range.
+type EnchantWithLevels struct {
+	// Levels is the range of levels used to enchant the item stack.
+	Levels Range
+	// Treasure specifies if treasure enchantments, such as Mending, may also be selected.
+	Treasure bool
+}
+
+// Apply ...
+func (f EnchantWithLevels) Apply(s item.Stack, ctx Context) item.Stack {
+	levels := f.Levels.Int(ctx.Rand)
+	for _, e := range item.Enchantments() {
+		if !e.CompatibleWithItem(s.Item()) || (!f.Treasure && treasure(e)) || ctx.Rand.Intn(3) != 0 {
+			continue
+		}
+		compatible := true
+		for _, existing := range s.Enchantments() {
+			if !e.CompatibleWithEnchantment(existing.Type()) {
+				compatible = false
+				break
+			}
+		}
+		if !compatible {
+			continue
+		}
+		for lvl := e.MaxLevel(); lvl > 0; lvl-- {
+			if minCost, maxCost := e.Cost(lvl); levels >= minCost && levels <= maxCost {
+				s = s.WithEnchantments(item.NewEnchantment(e, lvl))
+				break
+			}
+		}
+	}
+	return s
+}
+
+// FurnaceSmelt is a Function that replaces the item stack with its smelted product, if it has one. It is typically
+// used with the OnFire condition to drop cooked food from mobs that died while burning.
+type FurnaceSmelt struct {
+	// Conditions must all be satisfied for the item stack to be smelted.
+	Conditions []Condition
+}
+
+// Apply ...
+func (f FurnaceSmelt) Apply(s item.Stack, ctx Context) item.Stack {
+	sm, ok := s.Item().(item.Smeltable)
+	if !ok || !satisfied(f.Conditions, ctx) {
+		return s
+	}
+	return item.NewStack(sm.SmeltInfo().Product.Item(), s.Count())
+}
+
+// treasure checks if an enchantment is a treasure enchantment, which cannot be obtained through an enchanting
+// table.
+func treasure(e item.EnchantmentType) bool {
+	t, ok := e.(interface{ Treasure() bool })
+	return ok && t.Treasure()
+}
@@ -0,0 +1,215 @@
+package loot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Parse parses a Table from JSON data in the format used by loot tables in behaviour packs. Entries referring to
+// items that are not registered are treated as empty entries. An error is returned if the data is not valid JSON or
+// if it contains conditions or functions that are not supported.
+func Parse(data []byte) (Table, error) {
+	var t jsonTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Table{}, fmt.Errorf("decode loot table: %w", err)
+	}
+	var table Table
+	for i, p := range t.Pools {
+		pool, err := p.pool()
+		if err != nil {
+			return Table{}, fmt.Errorf("decode loot table: pool %v: %w", i, err)
+		}
+		table.Pools = append(table.Pools, pool)
+	}
+	return table, nil
+}
+
+// jsonTable is the JSON representation of a Table.
+type jsonTable struct {
+	Pools []jsonPool `json:"pools"`
+}
+
+// jsonPool is the JSON representation of a Pool.
+type jsonPool struct {
+	Rolls      jsonRange       `json:"rolls"`
+	BonusRolls float64         `json:"bonus_rolls"`
+	Conditions []jsonCondition `json:"conditions"`
+	Entries    []jsonEntry     `json:"entries"`
+}
+
+// pool converts the jsonPool to a Pool.
+func (p jsonPool) pool() (Pool, error) {
+	conditions, err := parseConditions(p.Conditions)
+	if err != nil {
+		return Pool{}, err
+	}
+	pool := Pool{Rolls: Range(p.Rolls), BonusRolls: p.BonusRolls, Conditions: conditions}
+	for i, e := range p.Entries {
+		entry, err := e.entry()
+		if err != nil {
+			return Pool{}, fmt.Errorf("entry %v: %w", i, err)
+		}
+		pool.Entries = append(pool.Entries, entry)
+	}
+	return pool, nil
+}
+
+// jsonEntry is the JSON representation of an Entry.
+type jsonEntry struct {
+	Type       string          `json:"type"`
+	Name       string          `json:"name"`
+	Weight     int             `json:"weight"`
+	Quality    int             `json:"quality"`
+	Conditions []jsonCondition `json:"conditions"`
+	Functions  []jsonFunction  `json:"functions"`
+}
+
+// entry converts the jsonEntry to an Entry.
+func (e jsonEntry) entry() (Entry, error) {
+	conditions, err := parseConditions(e.Conditions)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{Weight: e.Weight, Quality: e.Quality, Conditions: conditions}
+	switch e.Type {
+	case "empty":
+		return entry, nil
+	case "item":
+		it, ok := world.ItemByName(e.Name, 0)
+		if !ok {
+			// The item is not implemented, so the entry is treated as empty.
+			return entry, nil
+		}
+		entry.Item = it
+	default:
+		return Entry{}, fmt.Errorf("unsupported entry type %q", e.Type)
+	}
+	for _, f := range e.Functions {
+		fn, err := f.function()
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Functions = append(entry.Functions, fn)
+	}
+	return entry, nil
+}
+
+// jsonCondition is the JSON representation of a Condition.
+type jsonCondition struct {
+	Condition         string  `json:"condition"`
+	Chance            float64 `json:"chance"`
+	LootingMultiplier float64 `json:"looting_multiplier"`
+	Properties        struct {
+		OnFire bool `json:"on_fire"`
+	} `json:"properties"`
+}
+
+// condition converts the jsonCondition to a Condition.
+func (c jsonCondition) condition() (Condition, error) {
+	switch c.Condition {
+	case "killed_by_player", "killed_by_player_or_pets":
+		return KilledByPlayer{}, nil
+	case "random_chance":
+		return RandomChance{Chance: c.Chance}, nil
+	case "random_chance_with_looting":
+		return RandomChanceWithLooting{Chance: c.Chance, LootingMultiplier: c.LootingMultiplier}, nil
+	case "entity_properties":
+		if c.Properties.OnFire {
+			return OnFire{}, nil
+		}
+		return nil, fmt.Errorf("unsupported entity properties")
+	}
+	return nil, fmt.Errorf("unsupported condition %q", c.Condition)
+}
+
+// parseConditions converts a slice of jsonConditions to Conditions.
+func parseConditions(s []jsonCondition) ([]Condition, error) {
+	conditions := make([]Condition, 0, len(s))
+	for _, c := range s {
+		cond, err := c.condition()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+// jsonFunction is the JSON representation of a Function.
+type jsonFunction struct {
+	Function   string          `json:"function"`
+	Count      jsonRange       `json:"count"`
+	Levels     jsonRange       `json:"levels"`
+	Data       int16           `json:"data"`
+	Treasure   bool            `json:"treasure"`
+	Conditions []jsonCondition `json:"conditions"`
+	Damage     struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	} `json:"damage"`
+}
+
+// function converts the jsonFunction to a Function.
+func (f jsonFunction) function() (Function, error) {
+	switch f.Function {
+	case "set_count":
+		return SetCount{Count: Range(f.Count)}, nil
+	case "looting_enchant":
+		return LootingEnchant{Count: Range(f.Count)}, nil
+	case "set_damage":
+		return SetDamage{Min: f.Damage.Min, Max: f.Damage.Max}, nil
+	case "set_data":
+		return SetData{Data: f.Data}, nil
+	case "enchant_randomly":
+		return EnchantRandomly{Treasure: f.Treasure}, nil
+	case "enchant_with_levels":
+		return EnchantWithLevels{Levels: Range(f.Levels), Treasure: f.Treasure}, nil
+	case "furnace_smelt":
+		conditions, err := parseConditions(f.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		return FurnaceSmelt{Conditions: conditions}, nil
+	}
+	return nil, fmt.Errorf("unsupported function %q", f.Function)
+}
+
+// jsonRange is the JSON representation of a Range. It may either be a single number or an object with a minimum
+// and maximum.
+type jsonRange Range
+
+// UnmarshalJSON ...
+func (r *jsonRange) UnmarshalJSON(b []byte) error {
+	var n float64
+	if err := json.Unmarshal(b, &n); err == nil {
+		r.Min, r.Max = int(n), int(n)
+		return nil
+	}
+	var v struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	r.Min, r.Max = int(v.Min), int(v.Max)
+	return nil
+}
+
+// SetData is a Function that sets the metadata value of the item stack, replacing its item with the item registered
+// with the same name and the metadata value passed.
+type SetData struct {
+	// Data is the metadata value set.
+	Data int16
+}
+
+// Apply ...
+func (f SetData) Apply(s item.Stack, _ Context) item.Stack {
+	name, _ := s.Item().EncodeItem()
+	if it, ok := world.ItemByName(name, f.Data); ok {
+		return item.NewStack(it, s.Count())
+	}
+	return s
+}
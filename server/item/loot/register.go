@@ -0,0 +1,75 @@
+package loot
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+var (
+	//go:embed tables
+	tableFS embed.FS
+
+	tablesMu   sync.RWMutex
+	tables     = map[string]Table{}
+	tablesOnce sync.Once
+)
+
+// loadEmbedded registers the loot tables embedded in the tables directory, unless a Table with the same name was
+// already registered. The embedded tables are loaded lazily, so that all items, including those registered by
+// other packages such as blocks, are registered by the time they are parsed.
+func loadEmbedded() {
+	err := fs.WalkDir(tableFS, "tables", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, _ := tableFS.ReadFile(path)
+		t, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "tables/"), ".json")
+		if _, ok := tables[name]; !ok {
+			tables[name] = t
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Register registers a Table under the name passed, such as "entities/cow", so that it may be obtained using
+// Lookup. Registering a Table under a name that is already in use overwrites the existing Table, which may be used
+// to change the loot of mobs and chests. Block loot may be overwritten by registering a Table under "blocks/"
+// followed by the name of the block without namespace, such as "blocks/gravel". See block.BreakDrops.
+func Register(name string, t Table) {
+	tablesMu.Lock()
+	defer tablesMu.Unlock()
+	tables[name] = t
+}
+
+// Lookup looks up the Table registered under the name passed. False is returned if no Table with the name exists.
+func Lookup(name string) (Table, bool) {
+	tablesOnce.Do(func() {
+		tablesMu.Lock()
+		defer tablesMu.Unlock()
+		loadEmbedded()
+	})
+	tablesMu.RLock()
+	defer tablesMu.RUnlock()
+	t, ok := tables[name]
+	return t, ok
+}
+
+// Load parses the JSON data passed as a Table using Parse and registers it under the name passed.
+func Load(name string, data []byte) error {
+	t, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	Register(name, t)
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package loot implements data-driven loot tables. Loot tables describe the items dropped by mobs when they die or
+// by blocks when they are broken, and the items generated in chests. They may be created in code or loaded from
+// JSON in the same format as the loot tables found in behaviour packs.
+package loot
+
+import (
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+// Context holds the context in which a Table is used to generate loot. Conditions and Functions use the Context to
+// determine whether they apply and how.
+type Context struct {
+	// This is the entity that the loot is generated for, such as a mob that died. It may be nil.
+	This world.Entity
+	// Killer is the entity that killed This. It may be nil.
+	Killer world.Entity
+	// KilledByPlayer specifies if This was killed by a player, either directly or using a projectile.
+	KilledByPlayer bool
+	// Looting is the level of the Looting enchantment on the item used to kill This.
+	Looting int
+	// Luck is the luck of the entity that caused the loot to be generated, such as through the Luck effect. Luck
+	// increases the weight of entries with a positive quality.
+	Luck float64
+	// Rand is the source of randomness used to generate loot. If nil, a new source is created.
+	Rand *rand.Rand
+}
+
+// Table is a loot table. It is made up of Pools that are each rolled independently to generate loot.
+type Table struct {
+	// Pools holds the pools of the Table.
+	Pools []Pool
+}
+
+// Generate generates loot from the Table in the Context passed. Every Pool of the Table is rolled, and the items
+// obtained are returned.
+func (t Table) Generate(ctx Context) []item.Stack {
+	if ctx.Rand == nil {
+		ctx.Rand = rand.New(rand.NewSource(rand.Int63()))
+	}
+	var stacks []item.Stack
+	for _, p := range t.Pools {
+		stacks = append(stacks, p.generate(ctx)...)
+	}
+	return stacks
+}
+
+// Pool is a pool of entries in a Table. Every roll of a Pool selects a single Entry based on the weights of the
+// entries.
+type Pool struct {
+	// Rolls is the number of times the Pool is rolled.
+	Rolls Range
+	// BonusRolls is the number of additional rolls per point of luck.
+	BonusRolls float64
+	// Conditions must all be satisfied for the Pool to be rolled.
+	Conditions []Condition
+	// Entries holds the entries of the Pool.
+	Entries []Entry
+}
+
+// generate rolls the Pool and returns the items obtained.
+func (p Pool) generate(ctx Context) []item.Stack {
+	if !satisfied(p.Conditions, ctx) {
+		return nil
+	}
+	rolls := p.Rolls.Int(ctx.Rand) + int(p.BonusRolls*ctx.Luck)
+
+	var stacks []item.Stack
+	for i := 0; i < rolls; i++ {
+		entries, total := make([]Entry, 0, len(p.Entries)), 0
+		for _, e := range p.Entries {
+			if w := e.weight(ctx); w > 0 && satisfied(e.Conditions, ctx) {
+				entries, total = append(entries, e), total+w
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		n := ctx.Rand.Intn(total)
+		for _, e := range entries {
+			if n -= e.weight(ctx); n < 0 {
+				if s, ok := e.generate(ctx); ok {
+					stacks = append(stacks, s)
+				}
+				break
+			}
+		}
+	}
+	return stacks
+}
+
+// Entry is an entry in a Pool. If selected, an Entry produces an item stack, unless it is empty.
+type Entry struct {
+	// Item is the item produced by the Entry. If nil, the Entry is empty and produces no item when selected.
+	Item world.Item
+	// Weight is the weight of the Entry relative to the other entries in the Pool. Entries with a higher weight are
+	// selected more often. If 0, a weight of 1 is used.
+	Weight int
+	// Quality modifies the weight of the Entry based on the luck in the Context.
+	Quality int
+	// Conditions must all be satisfied for the Entry to be selected.
+	Conditions []Condition
+	// Functions are applied to the item stack produced by the Entry, in order.
+	Functions []Function
+}
+
+// weight returns the weight of the Entry in the Context passed.
+func (e Entry) weight(ctx Context) int {
+	w := e.Weight
+	if w == 0 {
+		w = 1
+	}
+	return max(w+int(float64(e.Quality)*ctx.Luck), 0)
+}
+
+// generate produces the item stack of the Entry, applying all of its functions. False is returned if the Entry is
+// empty or if the resulting stack is empty.
+func (e Entry) generate(ctx Context) (item.Stack, bool) {
+	if e.Item == nil {
+		return item.Stack{}, false
+	}
+	s := item.NewStack(e.Item, 1)
+	for _, f := range e.Functions {
+		s = f.Apply(s, ctx)
+	}
+	return s, !s.Empty()
+}
+
+// Range is a range of integers between Min and Max, both inclusive.
+type Range struct {
+	Min, Max int
+}
+
+// Exactly returns a Range that always produces n.
+func Exactly(n int) Range {
+	return Range{Min: n, Max: n}
+}
+
+// Int returns a random integer within the Range.
+func (r Range) Int(rd *rand.Rand) int {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + rd.Intn(r.Max-r.Min+1)
+}
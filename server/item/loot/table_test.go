@@ -0,0 +1,166 @@
+package loot
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/item"
+)
+
+func TestParse(t *testing.T) {
+	table, err := Parse([]byte(`{
+		"pools": [{
+			"rolls": {"min": 1, "max": 3},
+			"conditions": [{"condition": "killed_by_player"}],
+			"entries": [
+				{"type": "item", "name": "minecraft:bone", "weight": 3, "functions": [{"function": "set_count", "count": {"min": 0, "max": 2}}]},
+				{"type": "item", "name": "minecraft:not_an_item"},
+				{"type": "empty", "weight": 2}
+			]
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(table.Pools) != 1 {
+		t.Fatalf("Parse: got %v pools, want 1", len(table.Pools))
+	}
+	p := table.Pools[0]
+	if p.Rolls != (Range{Min: 1, Max: 3}) {
+		t.Errorf("Parse: got rolls %v, want 1-3", p.Rolls)
+	}
+	if _, ok := p.Conditions[0].(KilledByPlayer); !ok || len(p.Conditions) != 1 {
+		t.Errorf("Parse: got conditions %v, want [KilledByPlayer]", p.Conditions)
+	}
+	if len(p.Entries) != 3 {
+		t.Fatalf("Parse: got %v entries, want 3", len(p.Entries))
+	}
+	if _, ok := p.Entries[0].Item.(item.Bone); !ok || p.Entries[0].Weight != 3 {
+		t.Errorf("Parse: got entry %+v, want bone with weight 3", p.Entries[0])
+	}
+	if f, ok := p.Entries[0].Functions[0].(SetCount); !ok || f.Count != (Range{Min: 0, Max: 2}) {
+		t.Errorf("Parse: got functions %v, want [SetCount{0-2}]", p.Entries[0].Functions)
+	}
+	if p.Entries[1].Item != nil || p.Entries[2].Item != nil {
+		t.Errorf("Parse: unknown item and empty entries should have no item")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid json":          `{"pools": [`,
+		"unsupported entry":     `{"pools": [{"rolls": 1, "entries": [{"type": "loot_table"}]}]}`,
+		"unsupported condition": `{"pools": [{"rolls": 1, "conditions": [{"condition": "weather"}], "entries": []}]}`,
+		"unsupported function":  `{"pools": [{"rolls": 1, "entries": [{"type": "item", "name": "minecraft:bone", "functions": [{"function": "explode"}]}]}]}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse: expected error for %v", name)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	table := Table{Pools: []Pool{{
+		Rolls:   Exactly(4),
+		Entries: []Entry{{Item: item.Bone{}, Functions: []Function{SetCount{Count: Exactly(2)}}}},
+	}, {
+		Rolls:      Exactly(1),
+		Conditions: []Condition{KilledByPlayer{}},
+		Entries:    []Entry{{Item: item.Stick{}}},
+	}}}
+	ctx := Context{Rand: rand.New(rand.NewSource(1))}
+
+	stacks := table.Generate(ctx)
+	if len(stacks) != 4 {
+		t.Fatalf("Generate: got %v stacks, want 4", len(stacks))
+	}
+	for _, s := range stacks {
+		if _, ok := s.Item().(item.Bone); !ok || s.Count() != 2 {
+			t.Errorf("Generate: got %v, want 2 bones", s)
+		}
+	}
+
+	ctx.KilledByPlayer = true
+	if stacks := table.Generate(ctx); len(stacks) != 5 {
+		t.Errorf("Generate: got %v stacks with pool condition satisfied, want 5", len(stacks))
+	}
+}
+
+func TestGenerateDiscardsEmpty(t *testing.T) {
+	table := Table{Pools: []Pool{{
+		Rolls: Exactly(10),
+		Entries: []Entry{
+			{},
+			{Item: item.Bone{}, Functions: []Function{SetCount{Count: Exactly(0)}}},
+		},
+	}}}
+	if stacks := table.Generate(Context{Rand: rand.New(rand.NewSource(1))}); len(stacks) != 0 {
+		t.Errorf("Generate: got %v, want no stacks", stacks)
+	}
+}
+
+func TestEntryWeight(t *testing.T) {
+	for _, test := range []struct {
+		e    Entry
+		luck float64
+		want int
+	}{
+		{Entry{}, 0, 1},
+		{Entry{Weight: 10}, 0, 10},
+		{Entry{Weight: 10, Quality: 2}, 3, 16},
+		{Entry{Weight: 1, Quality: -2}, 1, 0},
+	} {
+		if got := test.e.weight(Context{Luck: test.luck}); got != test.want {
+			t.Errorf("weight(%+v, luck %v): got %v, want %v", test.e, test.luck, got, test.want)
+		}
+	}
+}
+
+func TestWeightedSelection(t *testing.T) {
+	pool := Pool{Rolls: Exactly(10000), Entries: []Entry{{Item: item.Bone{}, Weight: 3}, {Item: item.Stick{}, Weight: 1}}}
+	bones := 0
+	for _, s := range pool.generate(Context{Rand: rand.New(rand.NewSource(1))}) {
+		if _, ok := s.Item().(item.Bone); ok {
+			bones++
+		}
+	}
+	if bones < 7000 || bones > 8000 {
+		t.Errorf("generate: got %v bones out of 10000, want about 7500", bones)
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if n := (Range{Min: 2, Max: 5}).Int(r); n < 2 || n > 5 {
+			t.Fatalf("Int: got %v, want value in 2-5", n)
+		}
+	}
+	if n := (Range{Min: 4, Max: 1}).Int(r); n != 4 {
+		t.Errorf("Int: got %v for inverted range, want 4", n)
+	}
+}
+
+func TestRandomChanceWithLooting(t *testing.T) {
+	c := RandomChanceWithLooting{Chance: 0, LootingMultiplier: 0.5}
+	ctx := Context{Rand: rand.New(rand.NewSource(1))}
+	if c.Satisfied(ctx) {
+		t.Errorf("Satisfied: got true without looting")
+	}
+	ctx.Looting = 2
+	if !c.Satisfied(ctx) {
+		t.Errorf("Satisfied: got false with a chance of 1")
+	}
+}
+
+func TestRegisterOverridesEmbedded(t *testing.T) {
+	if _, ok := Lookup("entities/cow"); !ok {
+		t.Fatalf("Lookup: embedded table entities/cow not found")
+	}
+	want := Table{Pools: []Pool{{Rolls: Exactly(1), Entries: []Entry{{Item: item.Stick{}}}}}}
+	Register("entities/cow", want)
+	got, _ := Lookup("entities/cow")
+	if len(got.Pools) != 1 || got.Pools[0].Entries[0].Item != (item.Stick{}) {
+		t.Errorf("Lookup: registered table did not override embedded table")
+	}
+}
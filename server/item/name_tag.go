@@ -0,0 +1,38 @@
+package item
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// NameTag is an item used to give a custom name to a mob. The name of the name
+// tag must first be changed in an anvil. Named mobs display their name and
+// never despawn.
+type NameTag struct{}
+
+// UseOnEntity sets the custom name of the name tag held by the user as the
+// name tag of the entity passed.
+func (NameTag) UseOnEntity(e world.Entity, _ *world.World, user User, ctx *UseContext) bool {
+	held, _ := user.HeldItems()
+	name := held.CustomName()
+	n, ok := e.(nameableEntity)
+	if !ok || name == "" || n.NameTag() == name || e.Type().EncodeEntity() == "minecraft:player" {
+		return false
+	}
+	n.SetNameTag(name)
+	ctx.SubtractFromCount(1)
+	return true
+}
+
+// EncodeItem ...
+func (NameTag) EncodeItem() (name string, meta int16) {
+	return "minecraft:name_tag", 0
+}
+
+// nameableEntity represents an entity that may be given a name using a NameTag.
+type nameableEntity interface {
+	world.Entity
+	// NameTag returns the current name tag of the entity.
+	NameTag() string
+	// SetNameTag changes the name tag of the entity.
+	SetNameTag(s string)
+}
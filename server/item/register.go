@@ -32,6 +32,7 @@ func init() {
 	world.RegisterItem(Charcoal{})
 	world.RegisterItem(Chicken{Cooked: true})
 	world.RegisterItem(Chicken{})
+	world.RegisterItem(ChorusFruit{})
 	world.RegisterItem(ClayBall{})
 	world.RegisterItem(Clock{})
 	world.RegisterItem(Coal{})
@@ -48,11 +49,14 @@ func init() {
 	world.RegisterItem(Egg{})
 	world.RegisterItem(Elytra{})
 	world.RegisterItem(Emerald{})
+	world.RegisterItem(EndCrystal{})
 	world.RegisterItem(EnchantedApple{})
 	world.RegisterItem(EnchantedBook{})
+	world.RegisterItem(EnderEye{})
 	world.RegisterItem(EnderPearl{})
 	world.RegisterItem(Feather{})
 	world.RegisterItem(FermentedSpiderEye{})
+	world.RegisterItem(FilledMap{})
 	world.RegisterItem(FireCharge{})
 	world.RegisterItem(Firework{})
 	world.RegisterItem(FlintAndSteel{})
@@ -73,12 +77,14 @@ func init() {
 	world.RegisterItem(IronIngot{})
 	world.RegisterItem(IronNugget{})
 	world.RegisterItem(LapisLazuli{})
+	world.RegisterItem(Lead{})
 	world.RegisterItem(Leather{})
 	world.RegisterItem(MagmaCream{})
 	world.RegisterItem(MelonSlice{})
 	world.RegisterItem(MushroomStew{})
 	world.RegisterItem(Mutton{Cooked: true})
 	world.RegisterItem(Mutton{})
+	world.RegisterItem(NameTag{})
 	world.RegisterItem(NautilusShell{})
 	world.RegisterItem(NetherBrick{})
 	world.RegisterItem(NetherQuartz{})
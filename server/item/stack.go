@@ -22,6 +22,8 @@ type Stack struct {
 	customName string
 	lore       []string
 
+	canPlaceOn, canDestroy []string
+
 	damage int
 
 	anvilCost int
@@ -228,6 +230,43 @@ func (s Stack) Lore() []string {
 	return s.lore
 }
 
+// WithCanPlaceOn returns a copy of the Stack that may be placed on the blocks with the identifiers passed, such
+// as 'minecraft:stone', by players in adventure mode. Identifiers without a namespace are assumed to be in the
+// 'minecraft' namespace. The list may be cleared by passing no identifiers.
+func (s Stack) WithCanPlaceOn(blocks ...string) Stack {
+	s.canPlaceOn = blockIdentifiers(blocks)
+	return s
+}
+
+// CanPlaceOn returns the identifiers of the blocks that the Stack may be placed on by players in adventure mode.
+func (s Stack) CanPlaceOn() []string {
+	return s.canPlaceOn
+}
+
+// PlaceableOn checks if the Stack may be placed on the world.Block passed by players in adventure mode.
+func (s Stack) PlaceableOn(b world.Block) bool {
+	return b != nil && containsBlock(s.canPlaceOn, b)
+}
+
+// WithCanDestroy returns a copy of the Stack that may be used to break the blocks with the identifiers passed,
+// such as 'minecraft:stone', by players in adventure mode. Identifiers without a namespace are assumed to be in
+// the 'minecraft' namespace. The list may be cleared by passing no identifiers.
+func (s Stack) WithCanDestroy(blocks ...string) Stack {
+	s.canDestroy = blockIdentifiers(blocks)
+	return s
+}
+
+// CanDestroy returns the identifiers of the blocks that the Stack may be used to break by players in adventure
+// mode.
+func (s Stack) CanDestroy() []string {
+	return s.canDestroy
+}
+
+// Destroys checks if the Stack may be used to break the world.Block passed by players in adventure mode.
+func (s Stack) Destroys(b world.Block) bool {
+	return b != nil && containsBlock(s.canDestroy, b)
+}
+
 // WithValue returns the current Stack with a value set at a specific key. This method may be used to
 // associate custom data with the item stack, which will persist through server restarts.
 // The value stored may later be obtained by making a call to Stack.Value().
@@ -369,6 +408,9 @@ func (s Stack) Comparable(s2 Stack) bool {
 	for !slices.Equal(s.lore, s2.lore) {
 		return false
 	}
+	if !slices.Equal(s.canPlaceOn, s2.canPlaceOn) || !slices.Equal(s.canDestroy, s2.canDestroy) {
+		return false
+	}
 	if len(s.enchantments) != len(s2.enchantments) {
 		return false
 	}
@@ -401,6 +443,28 @@ func (s Stack) Values() map[string]any {
 	return copyMap(s.data)
 }
 
+// blockIdentifiers returns a copy of the block identifiers passed with the 'minecraft' namespace added to
+// identifiers without a namespace. Nil is returned if no identifiers are passed.
+func blockIdentifiers(blocks []string) []string {
+	if len(blocks) == 0 {
+		return nil
+	}
+	identifiers := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if !strings.Contains(b, ":") {
+			b = "minecraft:" + b
+		}
+		identifiers = append(identifiers, b)
+	}
+	return identifiers
+}
+
+// containsBlock checks if the identifier of the world.Block passed is in the list of identifiers passed.
+func containsBlock(identifiers []string, b world.Block) bool {
+	name, _ := b.EncodeBlock()
+	return slices.Contains(identifiers, name)
+}
+
 // stackID is a counter for unique stack IDs.
 var stackID = new(int32)
 
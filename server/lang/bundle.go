@@ -0,0 +1,197 @@
+// Package lang implements a server-side translation system. Translations are stored in a Bundle as messages
+// indexed by a key, for every language supported. Messages are looked up using the language of a player, as
+// reported by its client, so that command output and other text may be shown in the language of the player.
+//
+// Messages may contain fmt verbs, such as %v, which are replaced with the arguments passed when translating.
+// Plural forms of a message may be added by suffixing the key with the CLDR plural form, such as
+// "coins.one" and "coins.other", after which Bundle.TranslateN picks the correct form for a count.
+package lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Default is the default Bundle, using English as fallback language. Messages may be added to it using its
+// methods, after which they may be translated using Translate and TranslateN.
+var Default = NewBundle(language.English)
+
+// Bundle holds the messages of multiple languages. When translating a key for a language, the messages of the
+// language itself are checked first, followed by the messages of its parent languages (such as English for
+// British English) and the messages of the fallback language of the Bundle. If none of these hold a message
+// for the key, the key itself is returned.
+// Bundle is safe for concurrent use.
+type Bundle struct {
+	fallback language.Tag
+
+	mu       sync.RWMutex
+	messages map[language.Tag]map[string]string
+}
+
+// NewBundle creates a new empty Bundle that falls back to the language passed for keys missing in the
+// language that a key is translated for.
+func NewBundle(fallback language.Tag) *Bundle {
+	return &Bundle{fallback: fallback, messages: map[language.Tag]map[string]string{}}
+}
+
+// Fallback returns the fallback language of the Bundle.
+func (b *Bundle) Fallback() language.Tag {
+	return b.fallback
+}
+
+// Add adds the messages passed, indexed by their key, to the language passed. Existing messages with the same
+// key are overwritten.
+func (b *Bundle) Add(tag language.Tag, messages map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, ok := b.messages[tag]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		b.messages[tag] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+// LoadJSON adds the messages in the JSON object passed to the language passed. The JSON must be an object
+// mapping keys to messages.
+func (b *Bundle) LoadJSON(tag language.Tag, data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("load json: %w", err)
+	}
+	b.Add(tag, messages)
+	return nil
+}
+
+// LoadDir loads all JSON files in the directory passed into the Bundle. The name of every file, without its
+// extension, must be a language tag such as "en_GB" or "nl", which the messages in the file are added to.
+func (b *Bundle) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("load dir: %w", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		tag, err := language.Parse(strings.ReplaceAll(name, "_", "-"))
+		if err != nil {
+			return fmt.Errorf("load dir: file %v: %w", file, err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("load dir: %w", err)
+		}
+		if err := b.LoadJSON(tag, data); err != nil {
+			return fmt.Errorf("load dir: file %v: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// Has checks if the Bundle holds a message for the key passed in the language passed or any of the languages
+// it falls back to.
+func (b *Bundle) Has(tag language.Tag, key string) bool {
+	_, ok := b.lookup(tag, key)
+	return ok
+}
+
+// Translate translates the key passed to the language passed. If arguments are passed, the message is
+// formatted with them according to the rules of fmt.Sprintf. If no message exists for the key, the key is
+// returned.
+func (b *Bundle) Translate(tag language.Tag, key string, a ...any) string {
+	msg, ok := b.lookup(tag, key)
+	if !ok {
+		return key
+	}
+	if len(a) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, a...)
+}
+
+// TranslateN translates the plural form of the key passed for the count n to the language passed. The plural
+// form is picked following the CLDR plural rules of the language, and its message is looked up with a key
+// suffixed with the name of the form, such as "coins.one" or "coins.other". If no message exists for the form,
+// the ".other" form is used. The forms of the fallback language are only used if the language and its parent
+// languages have neither form, and the key itself is translated if no forms exist at all. The count n is
+// passed to the message as its first argument, followed by the arguments passed.
+func (b *Bundle) TranslateN(tag language.Tag, key string, n int, a ...any) string {
+	args := append([]any{n}, a...)
+	if msg, ok := b.lookup(tag, key+"."+pluralForm(tag, n), key+".other"); ok {
+		return fmt.Sprintf(msg, args...)
+	}
+	return b.Translate(tag, key, args...)
+}
+
+// lookup looks up the message for the first of the keys passed that has a message, following the fallback
+// chain of the language passed. All keys are checked for a language before moving on to the next language.
+func (b *Bundle) lookup(tag language.Tag, keys ...string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, t := range []language.Tag{tag, b.fallback} {
+		for ; ; t = t.Parent() {
+			for _, key := range keys {
+				if msg, ok := b.messages[t][key]; ok {
+					return msg, true
+				}
+			}
+			if t == language.Und {
+				break
+			}
+		}
+	}
+	return "", false
+}
+
+// pluralForm returns the name of the CLDR plural form of the count n in the language passed.
+func pluralForm(tag language.Tag, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0) {
+	case plural.Zero:
+		return "zero"
+	case plural.One:
+		return "one"
+	case plural.Two:
+		return "two"
+	case plural.Few:
+		return "few"
+	case plural.Many:
+		return "many"
+	}
+	return "other"
+}
+
+// Localised represents a value that has a language, such as a player.
+type Localised interface {
+	// Locale returns the language of the value.
+	Locale() language.Tag
+}
+
+// Locale returns the language of the value passed if it implements Localised, such as a player or a command
+// source that is a player. If not, the fallback language of the Default Bundle is returned.
+func Locale(v any) language.Tag {
+	if l, ok := v.(Localised); ok {
+		return l.Locale()
+	}
+	return Default.Fallback()
+}
+
+// Translate translates the key passed to the language passed using the Default Bundle.
+func Translate(tag language.Tag, key string, a ...any) string {
+	return Default.Translate(tag, key, a...)
+}
+
+// TranslateN translates the plural form of the key passed for the count n to the language passed using the
+// Default Bundle.
+func TranslateN(tag language.Tag, key string, n int, a ...any) string {
+	return Default.TranslateN(tag, key, n, a...)
+}
@@ -0,0 +1,119 @@
+package legacy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/segmentio/fasthash/fnv1a"
+	"golang.org/x/exp/maps"
+)
+
+// blockTable translates the runtime IDs of the block states of the current
+// version to the network IDs of the block states of an older version and back.
+// Older versions are sent the hashes of block states as network IDs, so that
+// the network ID of a block does not depend on the block states registered.
+type blockTable struct {
+	once      sync.Once
+	downgrade func(name string, properties map[string]any) (string, map[string]any, bool)
+
+	hashes []uint32
+	rids   map[uint32]uint32
+}
+
+// load builds the blockTable from all block states registered, if it was not
+// yet built. The block states are only complete once the server is started, so
+// load must not be called before a connection is accepted.
+func (t *blockTable) load() {
+	t.once.Do(func() {
+		t.rids = make(map[uint32]uint32)
+		for rid := uint32(0); ; rid++ {
+			name, properties, ok := chunk.RuntimeIDToState(rid)
+			if !ok {
+				break
+			}
+			oldName, oldProperties, exact := t.downgrade(name, maps.Clone(properties))
+			h := networkHash(oldName, oldProperties)
+			t.hashes = append(t.hashes, h)
+			if exact {
+				t.rids[h] = rid
+			}
+		}
+	})
+}
+
+// toLegacy returns the network ID of the block with the runtime ID passed in
+// the older version.
+func (t *blockTable) toLegacy(rid uint32) uint32 {
+	if rid >= uint32(len(t.hashes)) {
+		return rid
+	}
+	return t.hashes[rid]
+}
+
+// fromLegacy returns the runtime ID of the block with the network ID in the
+// older version passed.
+func (t *blockTable) fromLegacy(id uint32) uint32 {
+	if rid, ok := t.rids[id]; ok {
+		return rid
+	}
+	return id
+}
+
+// NBT tag types used to encode block states for hashing them.
+const (
+	tagEnd      = 0
+	tagByte     = 1
+	tagInt      = 3
+	tagString   = 8
+	tagCompound = 10
+)
+
+// networkHash returns the network ID of a block state with the name and
+// properties passed as the client computes it: The FNV-1a hash of the little
+// endian NBT encoding of the name and properties, ordered by key.
+func networkHash(name string, properties map[string]any) uint32 {
+	buf := bytes.NewBuffer(make([]byte, 0, 128))
+	writeTag := func(t byte, name string) {
+		buf.WriteByte(t)
+		_ = binary.Write(buf, binary.LittleEndian, uint16(len(name)))
+		buf.WriteString(name)
+	}
+	writeTag(tagCompound, "")
+	writeTag(tagString, "name")
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(name)))
+	buf.WriteString(name)
+
+	writeTag(tagCompound, "states")
+	keys := maps.Keys(properties)
+	slices.Sort(keys)
+	for _, k := range keys {
+		switch v := properties[k].(type) {
+		case uint8:
+			writeTag(tagByte, k)
+			buf.WriteByte(v)
+		case bool:
+			writeTag(tagByte, k)
+			if v {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+		case int32:
+			writeTag(tagInt, k)
+			_ = binary.Write(buf, binary.LittleEndian, v)
+		case int:
+			writeTag(tagInt, k)
+			_ = binary.Write(buf, binary.LittleEndian, int32(v))
+		case string:
+			writeTag(tagString, k)
+			_ = binary.Write(buf, binary.LittleEndian, uint16(len(v)))
+			buf.WriteString(v)
+		}
+	}
+	buf.WriteByte(tagEnd)
+	buf.WriteByte(tagEnd)
+	return fnv1a.HashBytes32(buf.Bytes())
+}
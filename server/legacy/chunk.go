@@ -0,0 +1,88 @@
+package legacy
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// translateSubChunk rewrites the block runtime IDs in the palettes of the
+// network encoded sub chunk at the start of b using f. It returns the sub
+// chunk rewritten and the number of bytes that the sub chunk took up in b, so
+// that data following the sub chunk, such as block entities, may be kept.
+func translateSubChunk(b []byte, f func(rid uint32) uint32) ([]byte, int, error) {
+	if len(b) < 2 {
+		return nil, 0, fmt.Errorf("sub chunk of %v bytes is too short", len(b))
+	}
+	buf := bytes.NewBuffer(b)
+	out := bytes.NewBuffer(make([]byte, 0, len(b)))
+
+	ver, _ := buf.ReadByte()
+	out.WriteByte(ver)
+	storages := 1
+	switch ver {
+	case 1:
+	case 8, 9:
+		n, _ := buf.ReadByte()
+		out.WriteByte(n)
+		storages = int(n)
+		if ver == 9 {
+			y, err := buf.ReadByte()
+			if err != nil {
+				return nil, 0, fmt.Errorf("read sub chunk y index: %w", err)
+			}
+			out.WriteByte(y)
+		}
+	default:
+		return nil, 0, fmt.Errorf("unknown sub chunk version %v", ver)
+	}
+	for i := 0; i < storages; i++ {
+		if err := translateStorage(buf, out, f); err != nil {
+			return nil, 0, fmt.Errorf("translate storage %v: %w", i, err)
+		}
+	}
+	return out.Bytes(), len(b) - buf.Len(), nil
+}
+
+// translateStorage copies a paletted storage from buf to out, rewriting the
+// runtime IDs in its palette using f.
+func translateStorage(buf, out *bytes.Buffer, f func(rid uint32) uint32) error {
+	header, err := buf.ReadByte()
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	out.WriteByte(header)
+	size := int(header >> 1)
+	if size == 0x7f {
+		// The storage is a copy of the previous one and holds no data.
+		return nil
+	}
+	if size > 32 {
+		return fmt.Errorf("invalid bits per index %v", size)
+	}
+	if size != 0 {
+		indicesPerWord := 32 / size
+		n := (4096 + indicesPerWord - 1) / indicesPerWord * 4
+		if buf.Len() < n {
+			return fmt.Errorf("storage of %v bytes is too short, expected %v", buf.Len(), n)
+		}
+		out.Write(buf.Next(n))
+	}
+
+	var count int32 = 1
+	if size != 0 {
+		if err := protocol.Varint32(buf, &count); err != nil {
+			return fmt.Errorf("read palette size: %w", err)
+		}
+		_ = protocol.WriteVarint32(out, count)
+	}
+	for i := int32(0); i < count; i++ {
+		var rid int32
+		if err := protocol.Varint32(buf, &rid); err != nil {
+			return fmt.Errorf("read palette entry: %w", err)
+		}
+		_ = protocol.WriteVarint32(out, int32(f(uint32(rid))))
+	}
+	return nil
+}
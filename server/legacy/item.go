@@ -0,0 +1,84 @@
+package legacy
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// itemName is the name and metadata value of an item in an older version.
+type itemName struct {
+	name string
+	meta int16
+}
+
+// legacyItem is the network ID and metadata value of an item in an older
+// version.
+type legacyItem struct {
+	id   int32
+	meta int16
+}
+
+// itemTable translates the network IDs of the items of the current version to
+// the network IDs and metadata values of the items of an older version and
+// back. Items that were split into several items in the current version, such
+// as the coral blocks, share one network ID in the older version and are told
+// apart by their metadata value.
+type itemTable struct {
+	entries []protocol.ItemEntry
+	down    map[int32]legacyItem
+	up      map[legacyItem]int32
+}
+
+// newItemTable creates an itemTable from the item entries sent to clients of
+// the current version. names holds the name and metadata value in the older
+// version of every item that was renamed or split since.
+func newItemTable(entries []protocol.ItemEntry, names map[string]itemName) *itemTable {
+	t := &itemTable{down: make(map[int32]legacyItem), up: make(map[legacyItem]int32)}
+
+	// The items that an older item was split into all get the network ID of
+	// the one with the lowest metadata value.
+	base := make(map[string]protocol.ItemEntry)
+	for _, e := range entries {
+		old, ok := names[e.Name]
+		if !ok {
+			t.entries = append(t.entries, e)
+			continue
+		}
+		if b, ok := base[old.name]; !ok || old.meta < names[b.Name].meta {
+			base[old.name] = e
+		}
+	}
+	for name, b := range base {
+		t.entries = append(t.entries, protocol.ItemEntry{Name: name, RuntimeID: b.RuntimeID, ComponentBased: b.ComponentBased})
+	}
+	for _, e := range entries {
+		old, ok := names[e.Name]
+		if !ok {
+			continue
+		}
+		l := legacyItem{id: int32(base[old.name].RuntimeID), meta: old.meta}
+		t.up[l] = int32(e.RuntimeID)
+		if l.id != int32(e.RuntimeID) || l.meta != 0 {
+			t.down[int32(e.RuntimeID)] = l
+		}
+	}
+	return t
+}
+
+// toLegacy returns the network ID and metadata value in the older version of
+// the item with the network ID and metadata value passed.
+func (t *itemTable) toLegacy(id int32, meta int16) (int32, int16) {
+	if l, ok := t.down[id]; ok {
+		return l.id, l.meta
+	}
+	return id, meta
+}
+
+// fromLegacy returns the network ID and metadata value in the current version
+// of the item with the network ID and metadata value in the older version
+// passed.
+func (t *itemTable) fromLegacy(id int32, meta int16) (int32, int16) {
+	if rid, ok := t.up[legacyItem{id: id, meta: meta}]; ok {
+		return rid, 0
+	}
+	return id, meta
+}
@@ -0,0 +1,22 @@
+// Package legacy implements minecraft.Protocols that let clients of older
+// Minecraft versions join a server of the current version. The packets sent
+// to and received from these clients are translated between versions,
+// including the runtime IDs of blocks and the network IDs of items found in
+// them. Blocks and items that do not exist in an older version are sent as
+// they are and show up as unknown to its clients.
+//
+// Only versions for which the block and item upgrade schemas of the
+// worldupgrader module exist may be implemented, as the changes to blocks and
+// items between versions are derived from them.
+package legacy
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+// Protocols returns the minecraft.Protocols of all older versions that are
+// implemented, ordered from the newest version to the oldest. They may be set
+// in minecraft.ListenConfig.AcceptedProtocols.
+func Protocols() []minecraft.Protocol {
+	return []minecraft.Protocol{v671}
+}
@@ -0,0 +1,128 @@
+package legacy
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	_ "github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/df-mc/worldupgrader/itemupgrader"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"golang.org/x/exp/maps"
+)
+
+// version671 is a block state version of Minecraft 1.20.80, after the last
+// upgrade schema for 1.20.80 and before the first one for 1.21.0.
+const version671 = 1<<24 | 20<<16 | 80<<8 | 4
+
+func TestNetworkHash(t *testing.T) {
+	if h := int32(networkHash("minecraft:air", nil)); h != -604749536 {
+		t.Errorf("networkHash: got %v for air, want %v", h, -604749536)
+	}
+}
+
+func TestDowngrade671(t *testing.T) {
+	hashes := make(map[uint32]string)
+	for rid := uint32(0); ; rid++ {
+		name, properties, ok := chunk.RuntimeIDToState(rid)
+		if !ok {
+			break
+		}
+		oldName, oldProperties, exact := downgrade671(name, maps.Clone(properties))
+		if !exact {
+			continue
+		}
+		upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{Name: oldName, Properties: maps.Clone(oldProperties), Version: version671})
+		if upgraded.Name != name || !maps.Equal(upgraded.Properties, properties) {
+			t.Errorf("downgrade671: %v %v downgraded to %v %v, which upgrades to %v %v", name, properties, oldName, oldProperties, upgraded.Name, upgraded.Properties)
+		}
+		h := networkHash(oldName, oldProperties)
+		if other, ok := hashes[h]; ok {
+			t.Errorf("networkHash: %v %v has the same hash as %v", name, properties, other)
+		}
+		hashes[h] = name
+	}
+}
+
+func TestItems671(t *testing.T) {
+	for name, old := range items671 {
+		if upgraded := itemupgrader.Upgrade(itemupgrader.ItemMeta{Name: old.name, Meta: old.meta}); upgraded.Name != name {
+			t.Errorf("items671: %v upgrades to %v, want %v", old, upgraded.Name, name)
+		}
+	}
+
+	entries := []protocol.ItemEntry{
+		{Name: "minecraft:stone", RuntimeID: 1},
+		{Name: "minecraft:fern", RuntimeID: 2},
+		{Name: "minecraft:short_grass", RuntimeID: 3},
+		{Name: "minecraft:music_disc_creator", RuntimeID: 4},
+	}
+	table := newItemTable(entries, items671)
+	names := make([]string, 0, len(table.entries))
+	for _, e := range table.entries {
+		names = append(names, e.Name)
+	}
+	slices.Sort(names)
+	if want := []string{"minecraft:record_creator", "minecraft:stone", "minecraft:tallgrass"}; !slices.Equal(names, want) {
+		t.Errorf("newItemTable: got entries %v, want %v", names, want)
+	}
+	tests := []struct {
+		id, legacyID     int32
+		meta, legacyMeta int16
+	}{
+		{1, 1, 0, 0},
+		{1, 1, 5, 5},
+		{2, 3, 0, 2},
+		{3, 3, 0, 0},
+		{4, 4, 0, 0},
+	}
+	for _, test := range tests {
+		if id, meta := table.toLegacy(test.id, test.meta); id != test.legacyID || meta != test.legacyMeta {
+			t.Errorf("toLegacy(%v, %v): got (%v, %v), want (%v, %v)", test.id, test.meta, id, meta, test.legacyID, test.legacyMeta)
+		}
+		if id, meta := table.fromLegacy(test.legacyID, test.legacyMeta); id != test.id || meta != test.meta {
+			t.Errorf("fromLegacy(%v, %v): got (%v, %v), want (%v, %v)", test.legacyID, test.legacyMeta, id, meta, test.id, test.meta)
+		}
+	}
+}
+
+func TestTranslateSubChunk(t *testing.T) {
+	air, _ := chunk.StateToRuntimeID("minecraft:air", nil)
+	r := cube.Range{-64, 319}
+	c := chunk.New(air, r)
+	for i := 0; i < 40; i++ {
+		c.SetBlock(uint8(i%16), int16(i), uint8(i/16), 0, uint32(i+1))
+	}
+	c.SetBlock(3, 5, 7, 1, 2)
+	f := func(rid uint32) uint32 { return rid*31 + 7 }
+
+	data := chunk.Encode(c, chunk.NetworkEncoding)
+	var payload []byte
+	for i, sub := range data.SubChunks {
+		translated, read, err := translateSubChunk(append(slices.Clone(sub), 1, 2, 3), f)
+		if err != nil {
+			t.Fatalf("translateSubChunk: sub chunk %v: %v", i, err)
+		}
+		if read != len(sub) {
+			t.Errorf("translateSubChunk: sub chunk %v: read %v bytes, want %v", i, read, len(sub))
+		}
+		payload = append(payload, translated...)
+	}
+	decoded, err := chunk.NetworkDecode(f(air), append(payload, data.Biomes...), len(data.SubChunks), r)
+	if err != nil {
+		t.Fatalf("decode translated chunk: %v", err)
+	}
+	for y := int16(-64); y < 64; y++ {
+		for x := uint8(0); x < 16; x++ {
+			for z := uint8(0); z < 16; z++ {
+				for layer := uint8(0); layer < 2; layer++ {
+					if got, want := decoded.Block(x, y, z, layer), f(c.Block(x, y, z, layer)); got != want {
+						t.Fatalf("translateSubChunk: got block %v at %v %v %v layer %v, want %v", got, x, y, z, layer, want)
+					}
+				}
+			}
+		}
+	}
+}
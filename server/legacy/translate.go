@@ -0,0 +1,354 @@
+package legacy
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"golang.org/x/exp/maps"
+)
+
+// translator translates the block runtime IDs and item network IDs in the
+// packets sent to and received from clients of an older version. Clients of
+// older versions are sent the hashes of block states rather than runtime IDs,
+// because the runtime IDs of the current version are indices into a list of
+// block states that differs between versions.
+type translator struct {
+	blocks blockTable
+	names  map[string]itemName
+	items  atomic.Pointer[itemTable]
+}
+
+// newTranslator returns a translator that downgrades the block states of the
+// current version using downgrade and translates items of the current version
+// according to the item names passed. downgrade returns false if the block
+// state passed does not exist in the older version, in which case the state
+// returned is shown in its place.
+func newTranslator(downgrade func(name string, properties map[string]any) (string, map[string]any, bool), names map[string]itemName) *translator {
+	return &translator{blocks: blockTable{downgrade: downgrade}, names: names}
+}
+
+// blockSounds holds the sound events that carry the runtime ID of a block in
+// their extra data.
+var blockSounds = []uint32{
+	packet.SoundEventPlace, packet.SoundEventHit, packet.SoundEventItemUseOn,
+	packet.SoundEventDoorOpen, packet.SoundEventDoorClose,
+	packet.SoundEventTrapdoorOpen, packet.SoundEventTrapdoorClose,
+	packet.SoundEventFenceGateOpen, packet.SoundEventFenceGateClose,
+}
+
+// fromLatest translates the runtime IDs in a packet of the current version
+// sent to a client of the older version. Packets that are changed are copied
+// first, so that packets sent to several clients are not changed for all of
+// them.
+func (t *translator) fromLatest(pk packet.Packet) packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.StartGame:
+		t.blocks.load()
+		items := newItemTable(pk.Items, t.names)
+		t.items.Store(items)
+
+		start := *pk
+		start.UseBlockNetworkIDHashes = true
+		start.Items = items.entries
+		return &start
+	case *packet.SubChunk:
+		sub := *pk
+		sub.SubChunkEntries = slices.Clone(pk.SubChunkEntries)
+		for i, entry := range sub.SubChunkEntries {
+			if entry.Result != protocol.SubChunkResultSuccess || entry.BlobHash != 0 {
+				continue
+			}
+			if payload, ok := t.translateSubChunks(entry.RawPayload, 1); ok {
+				sub.SubChunkEntries[i].RawPayload = payload
+			}
+		}
+		return &sub
+	case *packet.LevelChunk:
+		if pk.CacheEnabled || pk.SubChunkCount == protocol.SubChunkRequestModeLimited || pk.SubChunkCount == protocol.SubChunkRequestModeLimitless {
+			return pk
+		}
+		c := *pk
+		if payload, ok := t.translateSubChunks(pk.RawPayload, int(pk.SubChunkCount)); ok {
+			c.RawPayload = payload
+		}
+		return &c
+	case *packet.UpdateBlock:
+		u := *pk
+		u.NewBlockRuntimeID = t.blocks.toLegacy(pk.NewBlockRuntimeID)
+		return &u
+	case *packet.UpdateBlockSynced:
+		u := *pk
+		u.NewBlockRuntimeID = t.blocks.toLegacy(pk.NewBlockRuntimeID)
+		return &u
+	case *packet.LevelEvent:
+		e := *pk
+		switch pk.EventType {
+		case packet.LevelEventParticlesDestroyBlock:
+			e.EventData = int32(t.blocks.toLegacy(uint32(pk.EventData)))
+		case packet.LevelEventParticlesCrackBlock:
+			e.EventData = int32(t.blocks.toLegacy(uint32(pk.EventData)&0xffffff)) | pk.EventData&^0xffffff
+		}
+		return &e
+	case *packet.LevelSoundEvent:
+		if pk.ExtraData < 0 || !slices.Contains(blockSounds, pk.SoundType) {
+			return pk
+		}
+		e := *pk
+		e.ExtraData = int32(t.blocks.toLegacy(uint32(pk.ExtraData)))
+		return &e
+	case *packet.AddActor:
+		variant, ok := pk.EntityMetadata[protocol.EntityDataKeyVariant].(int32)
+		if pk.EntityType != "minecraft:falling_block" || !ok {
+			return pk
+		}
+		a := *pk
+		a.EntityMetadata = maps.Clone(pk.EntityMetadata)
+		a.EntityMetadata[protocol.EntityDataKeyVariant] = int32(t.blocks.toLegacy(uint32(variant)))
+		return &a
+	}
+	items := t.items.Load()
+	if items == nil {
+		return pk
+	}
+	switch pk := pk.(type) {
+	case *packet.InventoryContent:
+		c := *pk
+		c.Content = slices.Clone(pk.Content)
+		for i, it := range c.Content {
+			c.Content[i] = t.instanceToLegacy(items, it)
+		}
+		return &c
+	case *packet.InventorySlot:
+		s := *pk
+		s.NewItem = t.instanceToLegacy(items, pk.NewItem)
+		return &s
+	case *packet.MobEquipment:
+		e := *pk
+		e.NewItem = t.instanceToLegacy(items, pk.NewItem)
+		return &e
+	case *packet.MobArmourEquipment:
+		e := *pk
+		e.Helmet = t.instanceToLegacy(items, pk.Helmet)
+		e.Chestplate = t.instanceToLegacy(items, pk.Chestplate)
+		e.Leggings = t.instanceToLegacy(items, pk.Leggings)
+		e.Boots = t.instanceToLegacy(items, pk.Boots)
+		return &e
+	case *packet.AddItemActor:
+		a := *pk
+		a.Item = t.instanceToLegacy(items, pk.Item)
+		return &a
+	case *packet.AddPlayer:
+		a := *pk
+		a.HeldItem = t.instanceToLegacy(items, pk.HeldItem)
+		return &a
+	case *packet.CreativeContent:
+		c := *pk
+		c.Items = slices.Clone(pk.Items)
+		for i, it := range c.Items {
+			c.Items[i].Item = t.stackToLegacy(items, it.Item)
+		}
+		return &c
+	case *packet.CraftingData:
+		c := *pk
+		c.Recipes = make([]protocol.Recipe, len(pk.Recipes))
+		for i, r := range pk.Recipes {
+			c.Recipes[i] = t.recipeToLegacy(items, r)
+		}
+		c.PotionRecipes = slices.Clone(pk.PotionRecipes)
+		for i, r := range c.PotionRecipes {
+			c.PotionRecipes[i].InputPotionID, _ = items.toLegacy(r.InputPotionID, 0)
+			c.PotionRecipes[i].ReagentItemID, _ = items.toLegacy(r.ReagentItemID, 0)
+			c.PotionRecipes[i].OutputPotionID, _ = items.toLegacy(r.OutputPotionID, 0)
+		}
+		c.PotionContainerChangeRecipes = slices.Clone(pk.PotionContainerChangeRecipes)
+		for i, r := range c.PotionContainerChangeRecipes {
+			c.PotionContainerChangeRecipes[i].InputItemID, _ = items.toLegacy(r.InputItemID, 0)
+			c.PotionContainerChangeRecipes[i].ReagentItemID, _ = items.toLegacy(r.ReagentItemID, 0)
+			c.PotionContainerChangeRecipes[i].OutputItemID, _ = items.toLegacy(r.OutputItemID, 0)
+		}
+		return &c
+	}
+	return pk
+}
+
+// toLatest translates the runtime IDs in a packet received from a client of
+// the older version to those of the current version.
+func (t *translator) toLatest(pk packet.Packet) packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.ClientCacheStatus:
+		// Blobs are cached by the hash of the sub chunks of the current
+		// version, which are translated before they are sent, so the blob
+		// cache is never enabled for older versions.
+		pk.Enabled = false
+		return pk
+	}
+	items := t.items.Load()
+	if items == nil {
+		return pk
+	}
+	switch pk := pk.(type) {
+	case *packet.InventoryTransaction:
+		t.actionsFromLegacy(items, pk.Actions)
+		switch data := pk.TransactionData.(type) {
+		case *protocol.UseItemTransactionData:
+			t.useItemFromLegacy(items, data)
+		case *protocol.UseItemOnEntityTransactionData:
+			data.HeldItem = t.instanceFromLegacy(items, data.HeldItem)
+		case *protocol.ReleaseItemTransactionData:
+			data.HeldItem = t.instanceFromLegacy(items, data.HeldItem)
+		}
+	case *packet.PlayerAuthInput:
+		t.useItemFromLegacy(items, &pk.ItemInteractionData)
+	case *packet.MobEquipment:
+		pk.NewItem = t.instanceFromLegacy(items, pk.NewItem)
+	}
+	return pk
+}
+
+// translateSubChunks translates the n sub chunks found at the start of the
+// payload passed and keeps the data that follows them. False is returned if
+// the sub chunks could not be decoded.
+func (t *translator) translateSubChunks(payload []byte, n int) ([]byte, bool) {
+	out := make([]byte, 0, len(payload))
+	for i := 0; i < n; i++ {
+		sub, read, err := translateSubChunk(payload, t.blocks.toLegacy)
+		if err != nil {
+			return nil, false
+		}
+		out, payload = append(out, sub...), payload[read:]
+	}
+	return append(out, payload...), true
+}
+
+// useItemFromLegacy translates the held item, actions and block runtime ID of
+// a protocol.UseItemTransactionData received from a client of the older
+// version.
+func (t *translator) useItemFromLegacy(items *itemTable, data *protocol.UseItemTransactionData) {
+	t.actionsFromLegacy(items, data.Actions)
+	data.HeldItem = t.instanceFromLegacy(items, data.HeldItem)
+	data.BlockRuntimeID = t.blocks.fromLegacy(data.BlockRuntimeID)
+}
+
+// actionsFromLegacy translates the items of the inventory actions passed.
+func (t *translator) actionsFromLegacy(items *itemTable, actions []protocol.InventoryAction) {
+	for i, a := range actions {
+		actions[i].OldItem = t.instanceFromLegacy(items, a.OldItem)
+		actions[i].NewItem = t.instanceFromLegacy(items, a.NewItem)
+	}
+}
+
+// recipeToLegacy returns a copy of the recipe passed with the items of its
+// input and output translated. Recipes without items are returned as is.
+func (t *translator) recipeToLegacy(items *itemTable, r protocol.Recipe) protocol.Recipe {
+	switch r := r.(type) {
+	case *protocol.ShapedRecipe:
+		c := *r
+		c.Input, c.Output = t.descriptorsToLegacy(items, r.Input), t.stacksToLegacy(items, r.Output)
+		return &c
+	case *protocol.ShapedChemistryRecipe:
+		c := *r
+		c.Input, c.Output = t.descriptorsToLegacy(items, r.Input), t.stacksToLegacy(items, r.Output)
+		return &c
+	case *protocol.ShapelessRecipe:
+		c := *r
+		c.Input, c.Output = t.descriptorsToLegacy(items, r.Input), t.stacksToLegacy(items, r.Output)
+		return &c
+	case *protocol.ShapelessChemistryRecipe:
+		c := *r
+		c.Input, c.Output = t.descriptorsToLegacy(items, r.Input), t.stacksToLegacy(items, r.Output)
+		return &c
+	case *protocol.ShulkerBoxRecipe:
+		c := *r
+		c.Input, c.Output = t.descriptorsToLegacy(items, r.Input), t.stacksToLegacy(items, r.Output)
+		return &c
+	case *protocol.FurnaceRecipe:
+		c := *r
+		c.InputType, c.Output = t.typeToLegacy(items, r.InputType), t.stackToLegacy(items, r.Output)
+		return &c
+	case *protocol.FurnaceDataRecipe:
+		c := *r
+		c.InputType, c.Output = t.typeToLegacy(items, r.InputType), t.stackToLegacy(items, r.Output)
+		return &c
+	case *protocol.SmithingTransformRecipe:
+		c := *r
+		c.Template, c.Base, c.Addition = t.descriptorToLegacy(items, r.Template), t.descriptorToLegacy(items, r.Base), t.descriptorToLegacy(items, r.Addition)
+		c.Result = t.stackToLegacy(items, r.Result)
+		return &c
+	case *protocol.SmithingTrimRecipe:
+		c := *r
+		c.Template, c.Base, c.Addition = t.descriptorToLegacy(items, r.Template), t.descriptorToLegacy(items, r.Base), t.descriptorToLegacy(items, r.Addition)
+		return &c
+	}
+	return r
+}
+
+// descriptorsToLegacy returns a copy of the item descriptors passed with their
+// items translated.
+func (t *translator) descriptorsToLegacy(items *itemTable, descriptors []protocol.ItemDescriptorCount) []protocol.ItemDescriptorCount {
+	out := make([]protocol.ItemDescriptorCount, len(descriptors))
+	for i, d := range descriptors {
+		out[i] = t.descriptorToLegacy(items, d)
+	}
+	return out
+}
+
+// descriptorToLegacy translates the item of a protocol.DefaultItemDescriptor.
+// Other descriptors refer to items by name or tag and are returned as is.
+func (t *translator) descriptorToLegacy(items *itemTable, d protocol.ItemDescriptorCount) protocol.ItemDescriptorCount {
+	if def, ok := d.Descriptor.(*protocol.DefaultItemDescriptor); ok {
+		id, meta := items.toLegacy(int32(def.NetworkID), def.MetadataValue)
+		d.Descriptor = &protocol.DefaultItemDescriptor{NetworkID: int16(id), MetadataValue: meta}
+	}
+	return d
+}
+
+// stacksToLegacy returns a copy of the item stacks passed, translated.
+func (t *translator) stacksToLegacy(items *itemTable, stacks []protocol.ItemStack) []protocol.ItemStack {
+	out := make([]protocol.ItemStack, len(stacks))
+	for i, s := range stacks {
+		out[i] = t.stackToLegacy(items, s)
+	}
+	return out
+}
+
+// instanceToLegacy translates the item stack of a protocol.ItemInstance.
+func (t *translator) instanceToLegacy(items *itemTable, i protocol.ItemInstance) protocol.ItemInstance {
+	i.Stack = t.stackToLegacy(items, i.Stack)
+	return i
+}
+
+// instanceFromLegacy translates the item stack of a protocol.ItemInstance
+// received from a client of the older version.
+func (t *translator) instanceFromLegacy(items *itemTable, i protocol.ItemInstance) protocol.ItemInstance {
+	i.Stack = t.stackFromLegacy(items, i.Stack)
+	return i
+}
+
+// stackToLegacy translates the item type and block runtime ID of an item
+// stack.
+func (t *translator) stackToLegacy(items *itemTable, s protocol.ItemStack) protocol.ItemStack {
+	s.ItemType = t.typeToLegacy(items, s.ItemType)
+	if s.BlockRuntimeID > 0 {
+		s.BlockRuntimeID = int32(t.blocks.toLegacy(uint32(s.BlockRuntimeID)))
+	}
+	return s
+}
+
+// stackFromLegacy translates the item type and block runtime ID of an item
+// stack received from a client of the older version.
+func (t *translator) stackFromLegacy(items *itemTable, s protocol.ItemStack) protocol.ItemStack {
+	id, meta := items.fromLegacy(s.NetworkID, int16(s.MetadataValue))
+	s.NetworkID, s.MetadataValue = id, uint32(meta)
+	if s.BlockRuntimeID != 0 {
+		s.BlockRuntimeID = int32(t.blocks.fromLegacy(uint32(s.BlockRuntimeID)))
+	}
+	return s
+}
+
+// typeToLegacy translates a protocol.ItemType.
+func (t *translator) typeToLegacy(items *itemTable, it protocol.ItemType) protocol.ItemType {
+	id, meta := items.toLegacy(it.NetworkID, int16(it.MetadataValue))
+	return protocol.ItemType{NetworkID: id, MetadataValue: uint32(meta)}
+}
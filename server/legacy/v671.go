@@ -0,0 +1,205 @@
+package legacy
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"golang.org/x/exp/maps"
+)
+
+// v671 is the minecraft.Protocol of Minecraft 1.20.80.
+var v671 = &protocol671{translator: newTranslator(downgrade671, items671)}
+
+// protocol671 implements minecraft.Protocol for Minecraft 1.20.80. Besides
+// the blocks and items that were split or renamed in 1.21.0, the
+// ContainerClose and CodeBuilderSource packets changed and the
+// AwardAchievement packet was added.
+type protocol671 struct {
+	*translator
+}
+
+// ID ...
+func (*protocol671) ID() int32 { return 671 }
+
+// Ver ...
+func (*protocol671) Ver() string { return "1.20.80" }
+
+// Packets ...
+func (*protocol671) Packets(listener bool) packet.Pool {
+	if listener {
+		pool := packet.NewClientPool()
+		pool[packet.IDContainerClose] = func() packet.Packet { return &containerClose671{} }
+		pool[packet.IDCodeBuilderSource] = func() packet.Packet { return &codeBuilderSource671{} }
+		return pool
+	}
+	pool := packet.NewServerPool()
+	pool[packet.IDContainerClose] = func() packet.Packet { return &containerClose671{} }
+	delete(pool, packet.IDAwardAchievement)
+	return pool
+}
+
+// NewReader ...
+func (*protocol671) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+// NewWriter ...
+func (*protocol671) NewWriter(w minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(w, shieldID)
+}
+
+// ConvertToLatest ...
+func (p *protocol671) ConvertToLatest(pk packet.Packet, _ *minecraft.Conn) []packet.Packet {
+	switch pk := pk.(type) {
+	case *containerClose671:
+		return []packet.Packet{&packet.ContainerClose{WindowID: pk.WindowID, ServerSide: pk.ServerSide}}
+	case *codeBuilderSource671:
+		return []packet.Packet{&packet.CodeBuilderSource{Operation: pk.Operation, Category: pk.Category}}
+	}
+	return []packet.Packet{p.toLatest(pk)}
+}
+
+// ConvertFromLatest ...
+func (p *protocol671) ConvertFromLatest(pk packet.Packet, _ *minecraft.Conn) []packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.ContainerClose:
+		return []packet.Packet{&containerClose671{WindowID: pk.WindowID, ServerSide: pk.ServerSide}}
+	case *packet.AwardAchievement:
+		return nil
+	case *packet.StartGame:
+		start := p.fromLatest(pk).(*packet.StartGame)
+		start.GameVersion = p.Ver()
+		if start.BaseGameVersion == protocol.CurrentVersion {
+			start.BaseGameVersion = p.Ver()
+		}
+		return []packet.Packet{start}
+	}
+	return []packet.Packet{p.fromLatest(pk)}
+}
+
+// containerClose671 is packet.ContainerClose as found in Minecraft 1.20.80,
+// which does not have a container type.
+type containerClose671 struct {
+	WindowID   byte
+	ServerSide bool
+}
+
+// ID ...
+func (*containerClose671) ID() uint32 { return packet.IDContainerClose }
+
+// Marshal ...
+func (pk *containerClose671) Marshal(io protocol.IO) {
+	io.Uint8(&pk.WindowID)
+	io.Bool(&pk.ServerSide)
+}
+
+// codeBuilderSource671 is packet.CodeBuilderSource as found in Minecraft
+// 1.20.80, which does not have a code status.
+type codeBuilderSource671 struct {
+	Operation byte
+	Category  byte
+}
+
+// ID ...
+func (*codeBuilderSource671) ID() uint32 { return packet.IDCodeBuilderSource }
+
+// Marshal ...
+func (pk *codeBuilderSource671) Marshal(io protocol.IO) {
+	io.Uint8(&pk.Operation)
+	io.Uint8(&pk.Category)
+}
+
+// downgrade671 returns the name and properties that a block state of the
+// current version had in Minecraft 1.20.80. It reverses the block upgrade
+// schema from 1.20.80 to 1.21.0. Ominous trial spawners and vaults did not
+// exist in 1.20.80 and are downgraded to their regular variants, in which
+// case false is returned.
+func downgrade671(name string, properties map[string]any) (string, map[string]any, bool) {
+	if s, ok := blocks671[name]; ok {
+		if properties == nil {
+			properties = make(map[string]any, len(s.properties))
+		}
+		maps.Copy(properties, s.properties)
+		return s.name, properties, true
+	}
+	if name == "minecraft:trial_spawner" || name == "minecraft:vault" {
+		ominous := properties["ominous"] == uint8(1)
+		delete(properties, "ominous")
+		return name, properties, !ominous
+	}
+	return name, properties, true
+}
+
+// blockState671 is the name and the properties of a block in Minecraft
+// 1.20.80 that were replaced by a block name in 1.21.0.
+type blockState671 struct {
+	name       string
+	properties map[string]any
+}
+
+// blocks671 maps the names of blocks that were split off a block in 1.21.0
+// to the name and properties of the block they were part of in 1.20.80.
+// Properties kept in 1.21.0, such as upper_block_bit, are not listed.
+var blocks671 = map[string]blockState671{
+	"minecraft:tube_coral_block":        {"minecraft:coral_block", map[string]any{"coral_color": "blue", "dead_bit": uint8(0)}},
+	"minecraft:brain_coral_block":       {"minecraft:coral_block", map[string]any{"coral_color": "pink", "dead_bit": uint8(0)}},
+	"minecraft:bubble_coral_block":      {"minecraft:coral_block", map[string]any{"coral_color": "purple", "dead_bit": uint8(0)}},
+	"minecraft:fire_coral_block":        {"minecraft:coral_block", map[string]any{"coral_color": "red", "dead_bit": uint8(0)}},
+	"minecraft:horn_coral_block":        {"minecraft:coral_block", map[string]any{"coral_color": "yellow", "dead_bit": uint8(0)}},
+	"minecraft:dead_tube_coral_block":   {"minecraft:coral_block", map[string]any{"coral_color": "blue", "dead_bit": uint8(1)}},
+	"minecraft:dead_brain_coral_block":  {"minecraft:coral_block", map[string]any{"coral_color": "pink", "dead_bit": uint8(1)}},
+	"minecraft:dead_bubble_coral_block": {"minecraft:coral_block", map[string]any{"coral_color": "purple", "dead_bit": uint8(1)}},
+	"minecraft:dead_fire_coral_block":   {"minecraft:coral_block", map[string]any{"coral_color": "red", "dead_bit": uint8(1)}},
+	"minecraft:dead_horn_coral_block":   {"minecraft:coral_block", map[string]any{"coral_color": "yellow", "dead_bit": uint8(1)}},
+	"minecraft:sunflower":               {"minecraft:double_plant", map[string]any{"double_plant_type": "sunflower"}},
+	"minecraft:lilac":                   {"minecraft:double_plant", map[string]any{"double_plant_type": "syringa"}},
+	"minecraft:tall_grass":              {"minecraft:double_plant", map[string]any{"double_plant_type": "grass"}},
+	"minecraft:large_fern":              {"minecraft:double_plant", map[string]any{"double_plant_type": "fern"}},
+	"minecraft:rose_bush":               {"minecraft:double_plant", map[string]any{"double_plant_type": "rose"}},
+	"minecraft:peony":                   {"minecraft:double_plant", map[string]any{"double_plant_type": "paeonia"}},
+	"minecraft:smooth_stone_slab":       {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "smooth_stone"}},
+	"minecraft:sandstone_slab":          {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "sandstone"}},
+	"minecraft:petrified_oak_slab":      {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "wood"}},
+	"minecraft:cobblestone_slab":        {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "cobblestone"}},
+	"minecraft:brick_slab":              {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "brick"}},
+	"minecraft:stone_brick_slab":        {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "stone_brick"}},
+	"minecraft:quartz_slab":             {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "quartz"}},
+	"minecraft:nether_brick_slab":       {"minecraft:stone_block_slab", map[string]any{"stone_slab_type": "nether_brick"}},
+	"minecraft:short_grass":             {"minecraft:tallgrass", map[string]any{"tall_grass_type": "default"}},
+	"minecraft:fern":                    {"minecraft:tallgrass", map[string]any{"tall_grass_type": "fern"}},
+}
+
+// items671 maps the names of items that were split off an item or renamed in
+// 1.21.0 to their name and metadata value in Minecraft 1.20.80. It reverses
+// the item upgrade schema from 1.20.80 to 1.21.0.
+var items671 = map[string]itemName{
+	"minecraft:tube_coral_block":             {"minecraft:coral_block", 0},
+	"minecraft:brain_coral_block":            {"minecraft:coral_block", 1},
+	"minecraft:bubble_coral_block":           {"minecraft:coral_block", 2},
+	"minecraft:fire_coral_block":             {"minecraft:coral_block", 3},
+	"minecraft:horn_coral_block":             {"minecraft:coral_block", 4},
+	"minecraft:dead_tube_coral_block":        {"minecraft:coral_block", 8},
+	"minecraft:dead_brain_coral_block":       {"minecraft:coral_block", 9},
+	"minecraft:dead_bubble_coral_block":      {"minecraft:coral_block", 10},
+	"minecraft:dead_fire_coral_block":        {"minecraft:coral_block", 11},
+	"minecraft:dead_horn_coral_block":        {"minecraft:coral_block", 12},
+	"minecraft:sunflower":                    {"minecraft:double_plant", 0},
+	"minecraft:lilac":                        {"minecraft:double_plant", 1},
+	"minecraft:tall_grass":                   {"minecraft:double_plant", 2},
+	"minecraft:large_fern":                   {"minecraft:double_plant", 3},
+	"minecraft:rose_bush":                    {"minecraft:double_plant", 4},
+	"minecraft:peony":                        {"minecraft:double_plant", 5},
+	"minecraft:smooth_stone_slab":            {"minecraft:stone_block_slab", 0},
+	"minecraft:sandstone_slab":               {"minecraft:stone_block_slab", 1},
+	"minecraft:petrified_oak_slab":           {"minecraft:stone_block_slab", 2},
+	"minecraft:cobblestone_slab":             {"minecraft:stone_block_slab", 3},
+	"minecraft:brick_slab":                   {"minecraft:stone_block_slab", 4},
+	"minecraft:stone_brick_slab":             {"minecraft:stone_block_slab", 5},
+	"minecraft:quartz_slab":                  {"minecraft:stone_block_slab", 6},
+	"minecraft:nether_brick_slab":            {"minecraft:stone_block_slab", 7},
+	"minecraft:short_grass":                  {"minecraft:tallgrass", 0},
+	"minecraft:fern":                         {"minecraft:tallgrass", 2},
+	"minecraft:music_disc_creator":           {"minecraft:record_creator", 0},
+	"minecraft:music_disc_creator_music_box": {"minecraft:record_creator_music_box", 0},
+	"minecraft:music_disc_precipice":         {"minecraft:record_precipice", 0},
+}
@@ -2,11 +2,14 @@ package server
 
 import (
 	"fmt"
+	"github.com/df-mc/dragonfly/server/account"
+	"github.com/df-mc/dragonfly/server/logging"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/sandertv/gophertunnel/minecraft"
-	"github.com/sirupsen/logrus"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 )
 
 // Listener is a source for connections that may be listened on by a Server using Server.listen. Proxies can use this to
@@ -25,41 +28,138 @@ type Listener interface {
 func (uc UserConfig) listenerFunc(conf Config) (Listener, error) {
 	cfg := minecraft.ListenConfig{
 		MaximumPlayers:         conf.MaxPlayers,
-		StatusProvider:         statusProvider{name: conf.Name},
+		StatusProvider:         conf.StatusProvider,
 		AuthenticationDisabled: conf.AuthDisabled,
 		ResourcePacks:          conf.Resources,
 		Biomes:                 biomes(),
 		TexturePacksRequired:   conf.ResourcesRequired,
+		Compression:            conf.Compression,
+		FlushRate:              conf.FlushRate,
 	}
-	if l, ok := conf.Log.(*logrus.Logger); ok {
-		cfg.ErrorLog = log.Default()
-		log.SetOutput(l.WithField("src", "gophertunnel").WriterLevel(logrus.DebugLevel))
+	// The default protocol is wrapped as well, so that resource packs are
+	// selected for players of every version, including the current one.
+	for _, p := range append([]minecraft.Protocol{minecraft.DefaultProtocol}, conf.AcceptedProtocols...) {
+		cfg.AcceptedProtocols = append(cfg.AcceptedProtocols, newPackProtocol(p, conf.ResourceSelector, conf.ResourcesRequired, conf.OptionalResources))
+	}
+	cfg.ErrorLog = slog.NewLogLogger(logging.With(conf.Log, logging.Network).With("src", "gophertunnel").Handler(), slog.LevelDebug)
+	g := newFloodGuard(conf.FloodProtection)
+	cfg.PacketFunc = g.handlePacket
+
+	var f *forwarding
+	if uc.Proxy.Enabled {
+		if uc.Proxy.Secret == "" {
+			return nil, fmt.Errorf("create proxy listener: a secret must be set to enable the proxy")
+		}
+		// Proxies authenticate players themselves and sign the logins they forward with their own key.
+		f = &forwarding{secret: uc.Proxy.Secret, logins: make(map[string]forwardedLogin)}
+		cfg.AuthenticationDisabled = true
+		cfg.PacketFunc = func(header packet.Header, payload []byte, src, dst net.Addr) {
+			g.handlePacket(header, payload, src, dst)
+			f.handlePacket(header, payload, src, dst)
+		}
 	}
 	l, err := cfg.Listen("raknet", uc.Network.Address)
 	if err != nil {
 		return nil, fmt.Errorf("create minecraft listener: %w", err)
 	}
-	conf.Log.Infof("Server running on %v.\n", l.Addr())
-	return listener{l}, nil
+	g.mu.Lock()
+	g.local = l.Addr().String()
+	g.mu.Unlock()
+
+	logging.With(conf.Log, logging.Network).Info("Server running.", "addr", l.Addr().String())
+	if f != nil {
+		return proxyListener{listener: listener{Listener: l, flood: g}, f: f}, nil
+	}
+	return listener{Listener: l, flood: g, accounts: conf.Accounts}, nil
 }
 
 // listener is a Listener implementation that wraps around a minecraft.Listener so that it can be listened on by
-// Server.
+// Server. Connections that flood the listener with packets or logins are disconnected. If accounts is
+// non-nil, connections are identified using their account.
 type listener struct {
 	*minecraft.Listener
+	flood    *floodGuard
+	accounts *account.Manager
 }
 
 // Accept blocks until the next connection is established and returns it. An error is returned if the Listener was
 // closed using Close.
 func (l listener) Accept() (session.Conn, error) {
-	conn, err := l.Listener.Accept()
-	if err != nil {
-		return nil, err
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		c := conn.(*minecraft.Conn)
+		if !l.admit(c, c) {
+			continue
+		}
+		if l.accounts == nil {
+			return l.track(c, c), nil
+		}
+		if ac, ok := l.identify(c); ok {
+			return l.track(ac, c), nil
+		}
+		l.flood.release(c.RemoteAddr())
 	}
-	return conn.(session.Conn), err
+}
+
+// admit checks if the connection passed may join under the limits of the floodGuard of the listener and
+// disconnects it if not. raw is the connection as accepted by the minecraft.Listener.
+func (l listener) admit(conn session.Conn, raw *minecraft.Conn) bool {
+	kick := func(msg string) {
+		_ = l.Listener.Disconnect(raw, msg)
+	}
+	if msg, ok := l.flood.admit(raw.RemoteAddr(), conn.RemoteAddr(), kick); !ok {
+		kick(msg)
+		l.flood.release(raw.RemoteAddr())
+		return false
+	}
+	return true
+}
+
+// track wraps the admitted connection passed so that its state in the floodGuard of the listener is released
+// once it is closed. raw is the connection as accepted by the minecraft.Listener.
+func (l listener) track(conn session.Conn, raw *minecraft.Conn) session.Conn {
+	return floodConn{Conn: conn, raw: raw, flood: l.flood}
 }
 
 // Disconnect disconnects a connection from the Listener with a reason.
 func (l listener) Disconnect(conn session.Conn, reason string) error {
-	return l.Listener.Disconnect(conn.(*minecraft.Conn), reason)
+	if c, ok := conn.(floodConn); ok {
+		conn = c.Conn
+	}
+	raw := rawConn(conn)
+	l.flood.release(raw.RemoteAddr())
+	if c, ok := conn.(accountConn); ok {
+		c.quit()
+	}
+	return l.Listener.Disconnect(raw, reason)
+}
+
+// rawConn returns the *minecraft.Conn that a connection returned by a listener wraps.
+func rawConn(conn session.Conn) *minecraft.Conn {
+	switch c := conn.(type) {
+	case floodConn:
+		return c.raw
+	case proxiedConn:
+		return c.Conn
+	case accountConn:
+		return c.Conn
+	}
+	return conn.(*minecraft.Conn)
+}
+
+// floodConn is a connection admitted by the floodGuard of a listener. Its state in the floodGuard is released
+// when it is closed.
+type floodConn struct {
+	session.Conn
+	raw   *minecraft.Conn
+	flood *floodGuard
+}
+
+// Close releases the state of the connection in the floodGuard and closes it.
+func (c floodConn) Close() error {
+	c.flood.release(c.raw.RemoteAddr())
+	return c.Conn.Close()
 }
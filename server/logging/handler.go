@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler is a slog.Handler that filters records using the level of the
+// subsystem they are attributed to and passes them to another slog.Handler.
+// The subsystem of a record is set using the SubsystemKey attribute: A
+// subsystem set on a record or using slog.Logger.With replaces the subsystem
+// set before, so that every record has exactly one.
+type Handler struct {
+	h         slog.Handler
+	levels    *Levels
+	subsystem string
+	// grouped is true if the Handler was returned by WithGroup, after which
+	// attributes are no longer treated as a subsystem and the subsystem was
+	// already added to h.
+	grouped bool
+}
+
+// NewHandler returns a Handler that passes records to the slog.Handler h if
+// their level is at least that of their subsystem in the Levels passed.
+func NewHandler(h slog.Handler, levels *Levels) *Handler {
+	return &Handler{h: h, levels: levels, subsystem: Server}
+}
+
+// Enabled checks if records of the level passed are logged for the subsystem
+// of the Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.subsystem)
+}
+
+// Handle passes the record to the underlying slog.Handler with its subsystem
+// added, unless the record is attributed to a subsystem with a higher level
+// than the level of the record.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	subsystem := h.subsystem
+	attrs := make([]slog.Attr, 0, r.NumAttrs()+1)
+	r.Attrs(func(a slog.Attr) bool {
+		if s, ok := h.subsystemOf(a); ok {
+			subsystem = s
+		} else {
+			attrs = append(attrs, a)
+		}
+		return true
+	})
+	if r.Level < h.levels.Level(subsystem) {
+		return nil
+	}
+	rec := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	if !h.grouped {
+		rec.AddAttrs(slog.String(SubsystemKey, subsystem))
+	}
+	rec.AddAttrs(attrs...)
+	return h.h.Handle(ctx, rec)
+}
+
+// WithAttrs returns a Handler with the attributes passed. A SubsystemKey
+// attribute changes the subsystem of the Handler returned.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	rest := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if s, ok := h.subsystemOf(a); ok {
+			c.subsystem = s
+		} else {
+			rest = append(rest, a)
+		}
+	}
+	if len(rest) > 0 {
+		c.h = h.h.WithAttrs(rest)
+	}
+	return &c
+}
+
+// WithGroup returns a Handler that adds the attributes of records to the
+// group passed. The subsystem of the Handler can no longer be changed
+// afterwards.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	if h.grouped {
+		c.h = h.h.WithGroup(name)
+		return &c
+	}
+	c.h = h.h.WithAttrs([]slog.Attr{slog.String(SubsystemKey, h.subsystem)}).WithGroup(name)
+	c.grouped = true
+	return &c
+}
+
+// subsystemOf returns the subsystem set by an attribute, if it is a
+// SubsystemKey attribute outside of a group.
+func (h *Handler) subsystemOf(a slog.Attr) (string, bool) {
+	if h.grouped || a.Key != SubsystemKey {
+		return "", false
+	}
+	return a.Value.Resolve().String(), true
+}
+
+// Compile time check to make sure Handler implements slog.Handler.
+var _ slog.Handler = (*Handler)(nil)
@@ -0,0 +1,142 @@
+// Package logging implements structured logging for a server using log/slog.
+// Records are attributed to a subsystem of the server, such as the network or
+// worlds, using the "subsystem" attribute, which is added to a slog.Logger
+// using With. The minimum level of records logged may be set per subsystem
+// and changed while the server is running, and records may be written either
+// as text or as JSON for log aggregation.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SubsystemKey is the key of the attribute that holds the subsystem that a
+// record is attributed to.
+const SubsystemKey = "subsystem"
+
+// The subsystems of a server. Records without a subsystem are attributed to
+// Server.
+const (
+	Server   = "server"
+	Network  = "network"
+	World    = "world"
+	ChunkGen = "chunkgen"
+	Entities = "entities"
+	Commands = "commands"
+)
+
+// Subsystems returns the names of all subsystems of a server.
+func Subsystems() []string {
+	return []string{Server, Network, World, ChunkGen, Entities, Commands}
+}
+
+// With returns a slog.Logger that attributes all records logged to the
+// subsystem passed. If l already has a subsystem, the subsystem passed
+// replaces it for loggers using a Handler.
+func With(l *slog.Logger, subsystem string) *slog.Logger {
+	return l.With(SubsystemKey, subsystem)
+}
+
+// Levels holds the minimum level of records logged for every subsystem. The
+// levels may be changed at any time. Levels is safe for concurrent use.
+type Levels struct {
+	def slog.LevelVar
+
+	mu sync.RWMutex
+	// m holds the levels of subsystems that do not use the default level.
+	m map[string]slog.Level
+}
+
+// NewLevels creates Levels that use the level passed for all subsystems.
+func NewLevels(def slog.Level) *Levels {
+	l := &Levels{m: make(map[string]slog.Level)}
+	l.def.Set(def)
+	return l
+}
+
+// ParseLevels creates Levels from the name of the default level and the
+// names of the levels of specific subsystems, as parsed by ParseLevel. An
+// error is returned if a level or subsystem is invalid.
+func ParseLevels(def string, subsystems map[string]string) (*Levels, error) {
+	level, err := ParseLevel(def)
+	if err != nil {
+		return nil, err
+	}
+	l := NewLevels(level)
+	for subsystem, name := range subsystems {
+		if !slices.Contains(Subsystems(), subsystem) {
+			return nil, fmt.Errorf("unknown subsystem %q", subsystem)
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		l.Set(subsystem, level)
+	}
+	return l, nil
+}
+
+// ParseLevel parses the name of a level, such as "debug", "info", "warn" or
+// "error". Names are case-insensitive and may have an offset, such as
+// "info+2".
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("invalid level %q", name)
+	}
+	return level, nil
+}
+
+// Default returns the level used for subsystems without a level of their
+// own.
+func (l *Levels) Default() slog.Level {
+	return l.def.Level()
+}
+
+// SetDefault changes the level used for subsystems without a level of their
+// own.
+func (l *Levels) SetDefault(level slog.Level) {
+	l.def.Set(level)
+}
+
+// Level returns the minimum level of records logged for the subsystem
+// passed.
+func (l *Levels) Level(subsystem string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if level, ok := l.m[subsystem]; ok {
+		return level
+	}
+	return l.def.Level()
+}
+
+// Set changes the minimum level of records logged for the subsystem passed.
+func (l *Levels) Set(subsystem string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.m[subsystem] = level
+}
+
+// Reset makes the subsystem passed use the default level again.
+func (l *Levels) Reset(subsystem string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.m, subsystem)
+}
+
+// New returns a slog.Logger that writes records to the io.Writer passed,
+// either as JSON or as text, filtering them using the Levels passed.
+func New(w io.Writer, json bool, levels *Levels) *slog.Logger {
+	// Records are filtered by the Handler, so the handler writing them must
+	// accept records of every level.
+	opts := &slog.HandlerOptions{Level: slog.Level(-1 << 10)}
+	if json {
+		return slog.New(NewHandler(slog.NewJSONHandler(w, opts), levels))
+	}
+	return slog.New(NewHandler(slog.NewTextHandler(w, opts), levels))
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/df-mc/dragonfly/server/metrics"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// serveMetrics starts exporting the metrics of the Server, and its runtime
+// profiles if Config.Pprof is true, over HTTP on the MetricsAddress of its
+// Config. Nothing happens if the address is empty.
+func (srv *Server) serveMetrics() {
+	if srv.conf.MetricsAddress == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(srv.metrics()...))
+	if srv.conf.Pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	srv.metricsSrv = &http.Server{Addr: srv.conf.MetricsAddress, Handler: mux}
+	go func() {
+		if err := srv.metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			srv.conf.Log.Error("Error serving metrics.", "err", err)
+		}
+	}()
+	srv.conf.Log.Info("Exporting metrics.", "addr", srv.conf.MetricsAddress)
+}
+
+// metrics returns the metrics.Metrics of the Server that are exported next to
+// the metrics recorded by its worlds and sessions.
+func (srv *Server) metrics() []metrics.Metric {
+	return []metrics.Metric{
+		metrics.NewGaugeFunc("dragonfly_players_online", "Players online.", "", func() map[string]float64 {
+			return map[string]float64{"": float64(len(srv.Players()))}
+		}),
+		metrics.NewGaugeFunc("dragonfly_world_loaded_chunks", "Chunks loaded in worlds.", "dimension", srv.perWorld((*world.World).LoadedChunks)),
+		metrics.NewGaugeFunc("dragonfly_world_entities", "Entities in worlds.", "dimension", srv.perWorld(func(w *world.World) int {
+			return len(w.Entities())
+		})),
+	}
+}
+
+// perWorld returns a function that returns the values returned by f for every
+// world of the Server, indexed by the dimension of the world.
+func (srv *Server) perWorld(f func(w *world.World) int) func() map[string]float64 {
+	return func() map[string]float64 {
+		m := map[string]float64{}
+		for _, w := range append([]*world.World{srv.world, srv.nether, srv.end}, srv.customWorlds()...) {
+			m[fmt.Sprint(w.Dimension())] = float64(f(w))
+		}
+		return m
+	}
+}
+
+// customWorlds returns the worlds created for the custom dimensions of the
+// Config of the Server.
+func (srv *Server) customWorlds() []*world.World {
+	worlds := make([]*world.World, 0, len(srv.dimensions))
+	for _, w := range srv.dimensions {
+		worlds = append(worlds, w)
+	}
+	return worlds
+}
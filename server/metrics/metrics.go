@@ -0,0 +1,219 @@
+// Package metrics implements metrics of a server that may be exported in the Prometheus text format, so that
+// the server may be monitored using Prometheus and, for example, Grafana. Metrics are recorded by the packages
+// of the server in the metrics declared in this package and are exported over HTTP using Handler.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/df-mc/atomic"
+)
+
+var (
+	// TickDuration measures the time spent on ticks of worlds in seconds, labelled by the dimension of the
+	// world.
+	TickDuration = NewHistogram("dragonfly_world_tick_duration_seconds", "Time spent on ticks of worlds.",
+		"dimension", 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25)
+	// ProviderDuration measures the time spent loading and storing chunks using the providers of worlds in
+	// seconds, labelled by the operation, which is either 'load' or 'store'.
+	ProviderDuration = NewHistogram("dragonfly_provider_duration_seconds", "Time spent loading and storing chunks in world providers.",
+		"operation", 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25)
+	// PacketsReceived counts the packets received from the clients of players.
+	PacketsReceived = NewCounter("dragonfly_packets_received_total", "Packets received from players.")
+	// PacketsSent counts the packets sent to the clients of players.
+	PacketsSent = NewCounter("dragonfly_packets_sent_total", "Packets sent to players.")
+)
+
+// Metric is a metric that may be exported using Handler or Write.
+type Metric interface {
+	// write writes the metric in the Prometheus text format to the io.Writer passed.
+	write(w io.Writer)
+}
+
+// Handler returns a http.Handler that exports the metrics declared in this package and the Metrics passed in
+// the Prometheus text format.
+func Handler(m ...Metric) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w, m...)
+	})
+}
+
+// Write writes the metrics declared in this package and the Metrics passed to the io.Writer passed in the
+// Prometheus text format.
+func Write(w io.Writer, m ...Metric) {
+	for _, metric := range append([]Metric{TickDuration, ProviderDuration, PacketsReceived, PacketsSent}, m...) {
+		metric.write(w)
+	}
+}
+
+// Counter is a Metric holding a value that only ever increases, such as the number of packets sent.
+type Counter struct {
+	name, help string
+	v          atomic.Uint64
+}
+
+// NewCounter creates a Counter with the name and help text passed.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc increases the value of the Counter by one.
+func (c *Counter) Inc() {
+	c.v.Add(1)
+}
+
+// Add increases the value of the Counter by n.
+func (c *Counter) Add(n uint64) {
+	c.v.Add(n)
+}
+
+// write ...
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	_, _ = fmt.Fprintf(w, "%v %v\n", c.name, c.v.Load())
+}
+
+// Histogram is a Metric that counts observed values, such as durations, in buckets. Values observed may be
+// labelled with the value of a single label.
+type Histogram struct {
+	name, help, label string
+	buckets           []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+// histogramSeries holds the values observed by a Histogram for one value of its label.
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a Histogram with the name, help text and name of the label passed. Values observed
+// are counted in buckets with the upper bounds passed. If label is empty, values are not labelled.
+func NewHistogram(name, help, label string, buckets ...float64) *Histogram {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Histogram{name: name, help: help, label: label, buckets: buckets, series: make(map[string]*histogramSeries)}
+}
+
+// Observe adds the value v to the Histogram, labelled with the label value passed. The label value is ignored
+// if the Histogram has no label.
+func (h *Histogram) Observe(label string, v float64) {
+	if h.label == "" {
+		label = ""
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[label]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[label] = s
+	}
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// write ...
+func (h *Histogram) write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	labels := make([]string, 0, len(h.series))
+	for label := range h.series {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		s, cumulative := h.series[label], uint64(0)
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			_, _ = fmt.Fprintf(w, "%v_bucket%v %v\n", h.name, labelSet(h.label, label, "le", formatFloat(upper)), cumulative)
+		}
+		_, _ = fmt.Fprintf(w, "%v_bucket%v %v\n", h.name, labelSet(h.label, label, "le", "+Inf"), s.count)
+		_, _ = fmt.Fprintf(w, "%v_sum%v %v\n", h.name, labelSet(h.label, label), formatFloat(s.sum))
+		_, _ = fmt.Fprintf(w, "%v_count%v %v\n", h.name, labelSet(h.label, label), s.count)
+	}
+}
+
+// GaugeFunc is a Metric holding values that may go up and down, such as the number of players online. The
+// values are obtained by calling a function every time the GaugeFunc is exported.
+type GaugeFunc struct {
+	name, help, label string
+	f                 func() map[string]float64
+}
+
+// NewGaugeFunc creates a GaugeFunc with the name, help text and name of the label passed. f is called every
+// time the GaugeFunc is exported and returns the current values of the gauge, indexed by the values of the
+// label. If label is empty, only the value indexed by an empty string is exported.
+func NewGaugeFunc(name, help, label string, f func() map[string]float64) *GaugeFunc {
+	return &GaugeFunc{name: name, help: help, label: label, f: f}
+}
+
+// write ...
+func (g *GaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	values := g.f()
+	if g.label == "" {
+		_, _ = fmt.Fprintf(w, "%v %v\n", g.name, formatFloat(values[""]))
+		return
+	}
+	labels := make([]string, 0, len(values))
+	for label := range values {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		_, _ = fmt.Fprintf(w, "%v%v %v\n", g.name, labelSet(g.label, label), formatFloat(values[label]))
+	}
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric to the io.Writer passed.
+func writeHeader(w io.Writer, name, help, typ string) {
+	_, _ = fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", name, help, name, typ)
+}
+
+// labelSet formats pairs of label names and values as a Prometheus label set, such as {dimension="Nether"}.
+// Pairs with an empty label name are left out.
+func labelSet(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i] == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteByte('{')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i] + "=" + strconv.Quote(pairs[i+1]))
+	}
+	if b.Len() != 0 {
+		b.WriteByte('}')
+	}
+	return b.String()
+}
+
+// formatFloat formats a float64 as a value in the Prometheus text format.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
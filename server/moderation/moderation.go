@@ -0,0 +1,283 @@
+// Package moderation implements bans, IP bans and a whitelist for a server. A Manager holds the bans and the
+// whitelist and stores them using a Provider, so that they persist when the server restarts. A Manager may be
+// set in the Config of a server, so that banned players and players that are not whitelisted are refused while
+// logging in.
+package moderation
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"golang.org/x/exp/maps"
+)
+
+// Ban is a ban of a player or an IP address. Players that are banned are refused when they try to join the
+// server until the ban expires.
+type Ban struct {
+	// Target is the name of the player banned, or the IP address banned for IP bans.
+	Target string
+	// Reason is the reason of the ban that is shown to the player when it is refused. It may be empty.
+	Reason string
+	// Source is the name of the player or other source that issued the ban.
+	Source string
+	// Created is the time at which the ban was issued.
+	Created time.Time
+	// Expires is the time at which the ban expires. If zero, the ban never expires.
+	Expires time.Time
+}
+
+// Expired checks if the Ban has expired at the time passed. Bans without an expiry time never expire.
+func (b Ban) Expired(t time.Time) bool {
+	return !b.Expires.IsZero() && !t.Before(b.Expires)
+}
+
+// Message returns the message shown to a player that is disconnected because of the Ban.
+func (b Ban) Message() string {
+	msg := "You are banned from this server."
+	if b.Reason != "" {
+		msg += "\nReason: " + b.Reason
+	}
+	if !b.Expires.IsZero() {
+		msg += "\nExpires: " + b.Expires.Format(time.DateTime)
+	}
+	return msg
+}
+
+// Manager manages the bans, IP bans and whitelist of a server. Any changes are stored in the Provider of the
+// Manager immediately. Manager implements the Allower interface of the server, so that it may also be used to
+// refuse players that are banned or not whitelisted. A Manager is safe for concurrent use.
+type Manager struct {
+	p Provider
+
+	mu sync.Mutex
+	d  Data
+}
+
+// New creates a Manager that stores its bans and whitelist in the Provider passed. The data previously stored
+// in the Provider is loaded immediately.
+func New(p Provider) (*Manager, error) {
+	d, err := p.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load moderation data: %w", err)
+	}
+	if d.Bans == nil {
+		d.Bans = make(map[string]Ban)
+	}
+	if d.IPBans == nil {
+		d.IPBans = make(map[string]Ban)
+	}
+	if d.Whitelist == nil {
+		d.Whitelist = make(map[string]struct{})
+	}
+	return &Manager{p: p, d: d}, nil
+}
+
+// Ban bans the player with the name passed for the reason passed. If duration is 0 or lower, the ban never
+// expires. A ban previously issued for the player is replaced.
+func (m *Manager) Ban(name, reason, source string, duration time.Duration) error {
+	return m.update(func(d *Data) {
+		d.Bans[strings.ToLower(name)] = newBan(name, reason, source, duration)
+	})
+}
+
+// Unban lifts the ban of the player with the name passed. False is returned if the player was not banned.
+func (m *Manager) Unban(name string) (bool, error) {
+	return m.remove(func(d *Data) map[string]Ban { return d.Bans }, strings.ToLower(name))
+}
+
+// Banned checks if the player with the name passed is banned and returns its Ban if so.
+func (m *Manager) Banned(name string) (Ban, bool) {
+	return m.lookup(func(d *Data) map[string]Ban { return d.Bans }, strings.ToLower(name))
+}
+
+// Bans returns all bans of players that have not yet expired, ordered by the name of the player.
+func (m *Manager) Bans() []Ban {
+	return m.list(func(d *Data) map[string]Ban { return d.Bans })
+}
+
+// BanIP bans the IP address passed for the reason passed. If duration is 0 or lower, the ban never expires. A
+// ban previously issued for the IP address is replaced. An error is returned if ip is not a valid IP address.
+func (m *Manager) BanIP(ip, reason, source string, duration time.Duration) error {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return fmt.Errorf("ban ip: invalid IP address %v", ip)
+	}
+	return m.update(func(d *Data) {
+		d.IPBans[addr.String()] = newBan(addr.String(), reason, source, duration)
+	})
+}
+
+// UnbanIP lifts the ban of the IP address passed. False is returned if the IP address was not banned.
+func (m *Manager) UnbanIP(ip string) (bool, error) {
+	return m.remove(func(d *Data) map[string]Ban { return d.IPBans }, normaliseIP(ip))
+}
+
+// IPBanned checks if the IP address passed is banned and returns its Ban if so.
+func (m *Manager) IPBanned(ip string) (Ban, bool) {
+	return m.lookup(func(d *Data) map[string]Ban { return d.IPBans }, normaliseIP(ip))
+}
+
+// IPBans returns all bans of IP addresses that have not yet expired, ordered by the IP address.
+func (m *Manager) IPBans() []Ban {
+	return m.list(func(d *Data) map[string]Ban { return d.IPBans })
+}
+
+// SetWhitelistEnabled enables or disables the whitelist. If enabled, only players on the whitelist may join.
+func (m *Manager) SetWhitelistEnabled(enabled bool) error {
+	return m.update(func(d *Data) {
+		d.WhitelistEnabled = enabled
+	})
+}
+
+// WhitelistEnabled checks if the whitelist is enabled, meaning only players on the whitelist may join.
+func (m *Manager) WhitelistEnabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.d.WhitelistEnabled
+}
+
+// Whitelist adds the player with the name passed to the whitelist.
+func (m *Manager) Whitelist(name string) error {
+	return m.update(func(d *Data) {
+		d.Whitelist[strings.ToLower(name)] = struct{}{}
+	})
+}
+
+// Unwhitelist removes the player with the name passed from the whitelist. False is returned if the player was
+// not on the whitelist.
+func (m *Manager) Unwhitelist(name string) (bool, error) {
+	name = strings.ToLower(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.d.Whitelist[name]; !ok {
+		return false, nil
+	}
+	delete(m.d.Whitelist, name)
+	return true, m.p.Save(m.d)
+}
+
+// Whitelisted checks if the player with the name passed is on the whitelist.
+func (m *Manager) Whitelisted(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.d.Whitelist[strings.ToLower(name)]
+	return ok
+}
+
+// WhitelistNames returns the names of all players on the whitelist in alphabetical order.
+func (m *Manager) WhitelistNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := maps.Keys(m.d.Whitelist)
+	slices.Sort(names)
+	return names
+}
+
+// Allow refuses players that are banned, that join from a banned IP address or that are not whitelisted while
+// the whitelist is enabled.
+func (m *Manager) Allow(addr net.Addr, d login.IdentityData, _ login.ClientData) (string, bool) {
+	if b, ok := m.Banned(d.DisplayName); ok {
+		return b.Message(), false
+	}
+	if b, ok := m.IPBanned(addrIP(addr)); ok {
+		return b.Message(), false
+	}
+	if m.WhitelistEnabled() && !m.Whitelisted(d.DisplayName) {
+		return "You are not whitelisted on this server.", false
+	}
+	return "", true
+}
+
+// Close closes the Provider of the Manager.
+func (m *Manager) Close() error {
+	return m.p.Close()
+}
+
+// update calls f with the Data of the Manager and stores the Data in the Provider afterwards.
+func (m *Manager) update(f func(d *Data)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f(&m.d)
+	return m.p.Save(m.d)
+}
+
+// remove removes the Ban with the key passed from the map returned by f. False is returned if the map held no
+// Ban with the key or if it had expired.
+func (m *Manager) remove(f func(d *Data) map[string]Ban, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bans := f(&m.d)
+	b, ok := bans[key]
+	if !ok {
+		return false, nil
+	}
+	delete(bans, key)
+	return !b.Expired(time.Now()), m.p.Save(m.d)
+}
+
+// lookup returns the Ban with the key passed from the map returned by f, if it has not yet expired.
+func (m *Manager) lookup(f func(d *Data) map[string]Ban, key string) (Ban, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := f(&m.d)[key]
+	if !ok || b.Expired(time.Now()) {
+		return Ban{}, false
+	}
+	return b, true
+}
+
+// list returns all bans in the map returned by f that have not yet expired, ordered by their target.
+func (m *Manager) list(f func(d *Data) map[string]Ban) []Ban {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	bans := make([]Ban, 0, len(f(&m.d)))
+	for _, b := range f(&m.d) {
+		if !b.Expired(now) {
+			bans = append(bans, b)
+		}
+	}
+	slices.SortFunc(bans, compareTargets)
+	return bans
+}
+
+// compareTargets compares the targets of two bans case-insensitively, so that bans may be sorted by their
+// target.
+func compareTargets(a, b Ban) int {
+	return strings.Compare(strings.ToLower(a.Target), strings.ToLower(b.Target))
+}
+
+// newBan creates a Ban issued now for the target, reason and source passed that expires after the duration
+// passed. If duration is 0 or lower, the Ban never expires.
+func newBan(target, reason, source string, duration time.Duration) Ban {
+	b := Ban{Target: target, Reason: reason, Source: source, Created: time.Now()}
+	if duration > 0 {
+		b.Expires = b.Created.Add(duration)
+	}
+	return b
+}
+
+// normaliseIP returns the IP address passed in its normalised form, or the string passed if it is not a valid
+// IP address.
+func normaliseIP(ip string) string {
+	if addr := net.ParseIP(ip); addr != nil {
+		return addr.String()
+	}
+	return ip
+}
+
+// addrIP returns the IP address of the net.Addr passed as a string.
+func addrIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return normaliseIP(host)
+	}
+	return normaliseIP(addr.String())
+}
@@ -0,0 +1,135 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Data holds the bans and whitelist of a Manager in a form that may be stored by a Provider.
+type Data struct {
+	// Bans holds the bans of players, indexed by the name of the player in lowercase.
+	Bans map[string]Ban
+	// IPBans holds the bans of IP addresses, indexed by the IP address.
+	IPBans map[string]Ban
+	// WhitelistEnabled specifies if only players on the Whitelist may join.
+	WhitelistEnabled bool
+	// Whitelist holds the names of the players on the whitelist in lowercase.
+	Whitelist map[string]struct{}
+}
+
+// Provider represents a value that may store the Data of a Manager, so that bans and the whitelist persist
+// when the server restarts. Providers may, for example, store the Data in a database shared by the servers of
+// a network.
+type Provider interface {
+	// Load loads the Data stored in the Provider. If no Data was stored yet, empty Data and no error are
+	// returned.
+	Load() (Data, error)
+	// Save stores the Data passed in the Provider. It is called every time the Data of a Manager changes.
+	Save(d Data) error
+	// Closer is used on server close to safely close the Provider.
+	io.Closer
+}
+
+// Compile time check to make sure NopProvider implements Provider.
+var _ Provider = NopProvider{}
+
+// NopProvider is a Provider that does not store any Data. Bans and the whitelist are lost when the server
+// restarts.
+type NopProvider struct{}
+
+func (NopProvider) Load() (Data, error) { return Data{}, nil }
+func (NopProvider) Save(Data) error     { return nil }
+func (NopProvider) Close() error        { return nil }
+
+// JSONProvider is a Provider that stores Data in a JSON file, which may be read and edited by other programs
+// while the server is not running.
+type JSONProvider struct {
+	file string
+}
+
+// NewJSONProvider creates a Provider that stores Data in the JSON file passed. The directory of the file is
+// created if it does not yet exist.
+func NewJSONProvider(file string) (*JSONProvider, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return nil, fmt.Errorf("create moderation directory: %w", err)
+	}
+	return &JSONProvider{file: file}, nil
+}
+
+// jsonData is the JSON representation of Data.
+type jsonData struct {
+	Bans             []Ban
+	IPBans           []Ban
+	WhitelistEnabled bool
+	Whitelist        []string
+}
+
+// Load ...
+func (p *JSONProvider) Load() (Data, error) {
+	b, err := os.ReadFile(p.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return Data{}, nil
+	} else if err != nil {
+		return Data{}, err
+	}
+	var jd jsonData
+	if err := json.Unmarshal(b, &jd); err != nil {
+		return Data{}, err
+	}
+	d := Data{
+		Bans:             make(map[string]Ban, len(jd.Bans)),
+		IPBans:           make(map[string]Ban, len(jd.IPBans)),
+		WhitelistEnabled: jd.WhitelistEnabled,
+		Whitelist:        make(map[string]struct{}, len(jd.Whitelist)),
+	}
+	for _, b := range jd.Bans {
+		d.Bans[strings.ToLower(b.Target)] = b
+	}
+	for _, b := range jd.IPBans {
+		d.IPBans[normaliseIP(b.Target)] = b
+	}
+	for _, name := range jd.Whitelist {
+		d.Whitelist[strings.ToLower(name)] = struct{}{}
+	}
+	return d, nil
+}
+
+// Save ...
+func (p *JSONProvider) Save(d Data) error {
+	jd := jsonData{WhitelistEnabled: d.WhitelistEnabled, Whitelist: make([]string, 0, len(d.Whitelist))}
+	for _, b := range d.Bans {
+		jd.Bans = append(jd.Bans, b)
+	}
+	for _, b := range d.IPBans {
+		jd.IPBans = append(jd.IPBans, b)
+	}
+	for name := range d.Whitelist {
+		jd.Whitelist = append(jd.Whitelist, name)
+	}
+	// Sort the entries so that the file does not change if the Data does not.
+	slices.SortFunc(jd.Bans, compareTargets)
+	slices.SortFunc(jd.IPBans, compareTargets)
+	slices.Sort(jd.Whitelist)
+	b, err := json.MarshalIndent(jd, "", "\t")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that the data is never left half-written if the server stops while
+	// saving.
+	tmp := p.file + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.file)
+}
+
+// Close ...
+func (p *JSONProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/df-mc/dragonfly/server/permission"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/google/uuid"
+)
+
+// ErrPlayerOnline is returned by Server.OfflinePlayer and Server.EditOfflinePlayer if the player whose data is
+// read or edited is connected to the Server.
+var ErrPlayerOnline = errors.New("player is online")
+
+// OfflinePlayer holds the saved data of a player that is not connected to the Server. It is returned by
+// Server.OfflinePlayer and passed to the function of Server.EditOfflinePlayer.
+type OfflinePlayer struct {
+	// Data is the player data saved by the player.Provider of the Server, such as the inventory and the
+	// position of the player.
+	Data player.Data
+	// Permissions holds the permissions saved by the permission.Provider of the Server. If no permissions were
+	// saved for the player, Permissions holds no nodes or groups.
+	Permissions *permission.Holder
+}
+
+// OfflinePlayer reads the saved data and permissions of the player with the UUID passed. ErrPlayerOnline is
+// returned if the player is connected to the Server, as the data saved is not up to date in that case. An
+// error is also returned if no data was saved for the player.
+func (srv *Server) OfflinePlayer(id uuid.UUID) (OfflinePlayer, error) {
+	srv.dataMu.Lock()
+	defer srv.dataMu.Unlock()
+	return srv.loadOfflinePlayer(id)
+}
+
+// EditOfflinePlayer loads the saved data and permissions of the player with the UUID passed, passes them to
+// the function f and saves the OfflinePlayer as changed by f afterwards. Players cannot join the Server while f
+// is running: They are held until f returns and then load the edited data, so f should return quickly.
+// ErrPlayerOnline is returned if the player is connected to the Server. If no data was saved for the player,
+// an error is returned and f is not called.
+func (srv *Server) EditOfflinePlayer(id uuid.UUID, f func(p *OfflinePlayer)) error {
+	srv.dataMu.Lock()
+	defer srv.dataMu.Unlock()
+
+	p, err := srv.loadOfflinePlayer(id)
+	if err != nil {
+		return err
+	}
+	f(&p)
+	if err := srv.conf.PlayerProvider.Save(id, p.Data); err != nil {
+		return fmt.Errorf("edit offline player: save player data: %w", err)
+	}
+	if err := srv.conf.PermissionProvider.Save(id, p.Permissions.Data()); err != nil {
+		return fmt.Errorf("edit offline player: save permissions: %w", err)
+	}
+	return nil
+}
+
+// loadOfflinePlayer loads the OfflinePlayer with the UUID passed. Server.dataMu must be held while calling
+// loadOfflinePlayer.
+func (srv *Server) loadOfflinePlayer(id uuid.UUID) (OfflinePlayer, error) {
+	if srv.online[id] > 0 {
+		return OfflinePlayer{}, ErrPlayerOnline
+	}
+	d, err := srv.conf.PlayerProvider.Load(id, srv.dimension)
+	if err != nil {
+		return OfflinePlayer{}, fmt.Errorf("load offline player: %w", err)
+	}
+	if d.World == nil {
+		d.World = srv.world
+	}
+	p := OfflinePlayer{Data: d, Permissions: permission.NewHolder()}
+	if perms, err := srv.conf.PermissionProvider.Load(id); err == nil {
+		p.Permissions.Load(perms)
+	}
+	return p, nil
+}
+
+// releasePlayerData marks the data of a connection of the player with the UUID passed as saved, so that it
+// may be edited using EditOfflinePlayer once the player has no connections left.
+func (srv *Server) releasePlayerData(id uuid.UUID) {
+	srv.dataMu.Lock()
+	defer srv.dataMu.Unlock()
+	if srv.online[id]--; srv.online[id] <= 0 {
+		delete(srv.online, id)
+	}
+}
@@ -0,0 +1,121 @@
+package permission
+
+import (
+	"strings"
+	"sync"
+)
+
+// Group is a named collection of permission nodes that may be assigned to Holders. A Group may inherit the
+// nodes of other groups, which are only checked if none of the nodes of the Group itself match a permission.
+// Group is safe for concurrent use.
+type Group struct {
+	name    string
+	parents []*Group
+
+	mu    sync.RWMutex
+	nodes nodes
+}
+
+// NewGroup creates a new Group with the name passed that inherits the nodes of the parents passed. Parents
+// that come first take precedence over parents that come later.
+// The Group must be registered using RegisterGroup before it may be looked up using GroupByName.
+func NewGroup(name string, parents ...*Group) *Group {
+	return &Group{name: name, parents: parents, nodes: nodes{}}
+}
+
+// Name returns the name of the Group.
+func (g *Group) Name() string {
+	return g.name
+}
+
+// Parents returns the groups that the Group inherits nodes from.
+func (g *Group) Parents() []*Group {
+	return append([]*Group(nil), g.parents...)
+}
+
+// Grant grants the nodes passed to the Group.
+func (g *Group) Grant(n ...string) {
+	g.set(n, true)
+}
+
+// Deny denies the nodes passed to the Group. Unlike nodes that are unset, denied nodes take precedence over
+// nodes granted by the parents of the Group.
+func (g *Group) Deny(n ...string) {
+	g.set(n, false)
+}
+
+// Unset removes the nodes passed from the Group, regardless of whether they were granted or denied.
+func (g *Group) Unset(n ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, node := range n {
+		delete(g.nodes, normalise(node))
+	}
+}
+
+// Nodes returns all nodes set directly on the Group, mapped to whether they are granted or denied.
+func (g *Group) Nodes() map[string]bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.nodes.clone()
+}
+
+// Has checks if the Group, or any of the groups it inherits from, grants the permission passed.
+func (g *Group) Has(node string) bool {
+	v, _ := g.resolve(normalise(node))
+	return v
+}
+
+// set sets the nodes passed to the value passed.
+func (g *Group) set(n []string, v bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, node := range n {
+		g.nodes[normalise(node)] = v
+	}
+}
+
+// resolve resolves the normalised node passed using the nodes of the Group, falling back to its parents.
+func (g *Group) resolve(node string) (value, ok bool) {
+	g.mu.RLock()
+	value, ok = g.nodes.resolve(node)
+	g.mu.RUnlock()
+	if ok {
+		return value, true
+	}
+	for _, parent := range g.parents {
+		if value, ok = parent.resolve(node); ok {
+			return value, true
+		}
+	}
+	return false, false
+}
+
+// groups holds a list of registered groups indexed by their name.
+var groups sync.Map
+
+// RegisterGroup registers a Group so that it may be looked up using GroupByName, for example when the Data
+// of a Holder is loaded. Any Group with the same name will be overwritten.
+func RegisterGroup(g *Group) {
+	groups.Store(strings.ToLower(g.name), g)
+}
+
+// GroupByName looks up a registered Group by its name. The name is case-insensitive. If found, the Group and
+// true are returned.
+func GroupByName(name string) (*Group, bool) {
+	g, ok := groups.Load(strings.ToLower(name))
+	if !ok {
+		return nil, false
+	}
+	return g.(*Group), true
+}
+
+// Groups returns all registered groups.
+func Groups() []*Group {
+	var all []*Group
+	groups.Range(func(_, value any) bool {
+		all = append(all, value.(*Group))
+		return true
+	})
+	return all
+}
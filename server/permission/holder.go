@@ -0,0 +1,148 @@
+package permission
+
+import (
+	"strings"
+	"sync"
+)
+
+// Holder holds the permissions of a single permissible, such as a player. Nodes may be granted or denied to a
+// Holder directly, overriding the nodes of the groups it is in. Holder is safe for concurrent use.
+type Holder struct {
+	mu     sync.RWMutex
+	nodes  nodes
+	groups []*Group
+}
+
+// NewHolder creates a new Holder in the groups passed. Groups that come first take precedence over groups
+// that come later.
+func NewHolder(groups ...*Group) *Holder {
+	return &Holder{nodes: nodes{}, groups: append([]*Group(nil), groups...)}
+}
+
+// Grant grants the nodes passed to the Holder. Granting the node "*" grants the Holder every permission, like
+// an operator.
+func (h *Holder) Grant(n ...string) {
+	h.set(n, true)
+}
+
+// Deny denies the nodes passed to the Holder, even if they are granted by one of the groups of the Holder.
+func (h *Holder) Deny(n ...string) {
+	h.set(n, false)
+}
+
+// Unset removes the nodes passed from the Holder, regardless of whether they were granted or denied. The
+// groups of the Holder decide whether it has these permissions afterwards.
+func (h *Holder) Unset(n ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, node := range n {
+		delete(h.nodes, normalise(node))
+	}
+}
+
+// Nodes returns all nodes set directly on the Holder, mapped to whether they are granted or denied.
+func (h *Holder) Nodes() map[string]bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.nodes.clone()
+}
+
+// AddGroup adds the Holder to the Group passed. Groups added later have a lower precedence than groups added
+// earlier. Nothing happens if the Holder is already in the Group.
+func (h *Holder) AddGroup(g *Group) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, existing := range h.groups {
+		if existing == g {
+			return
+		}
+	}
+	h.groups = append(h.groups, g)
+}
+
+// RemoveGroup removes the Holder from the Group passed. Nothing happens if the Holder is not in the Group.
+func (h *Holder) RemoveGroup(g *Group) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, existing := range h.groups {
+		if existing == g {
+			h.groups = append(h.groups[:i:i], h.groups[i+1:]...)
+			return
+		}
+	}
+}
+
+// Groups returns the groups that the Holder is in, ordered by precedence.
+func (h *Holder) Groups() []*Group {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]*Group(nil), h.groups...)
+}
+
+// InGroup checks if the Holder is in a Group with the name passed. The name is case-insensitive.
+func (h *Holder) InGroup(name string) bool {
+	for _, g := range h.Groups() {
+		if strings.EqualFold(g.Name(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Has checks if the Holder has the permission passed, either through a node set on the Holder directly or
+// through one of its groups.
+func (h *Holder) Has(node string) bool {
+	node = normalise(node)
+
+	h.mu.RLock()
+	value, ok := h.nodes.resolve(node)
+	groups := h.groups
+	h.mu.RUnlock()
+	if ok {
+		return value
+	}
+	for _, g := range groups {
+		if value, ok = g.resolve(node); ok {
+			return value
+		}
+	}
+	return false
+}
+
+// Data returns the Data of the Holder, which may be stored using a Provider.
+func (h *Holder) Data() Data {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	d := Data{Nodes: h.nodes.clone()}
+	for _, g := range h.groups {
+		d.Groups = append(d.Groups, g.Name())
+	}
+	return d
+}
+
+// Load replaces the nodes and groups of the Holder with those in the Data passed. Groups are looked up using
+// GroupByName. Groups in the Data that are not registered are ignored.
+func (h *Holder) Load(d Data) {
+	n := make(nodes, len(d.Nodes))
+	for node, v := range d.Nodes {
+		n[normalise(node)] = v
+	}
+	var groups []*Group
+	for _, name := range d.Groups {
+		if g, ok := GroupByName(name); ok {
+			groups = append(groups, g)
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nodes, h.groups = n, groups
+}
+
+// set sets the nodes passed to the value passed.
+func (h *Holder) set(n []string, v bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, node := range n {
+		h.nodes[normalise(node)] = v
+	}
+}
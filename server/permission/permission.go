@@ -0,0 +1,63 @@
+// Package permission implements a hierarchical permission system. Permissions are represented by nodes, which
+// are dot separated strings such as "dragonfly.command.gamerule". Nodes may be granted or denied to a Group or
+// directly to a Holder, such as a player. A node ending in ".*" matches all nodes below it, and the node "*"
+// matches every node.
+//
+// When checking if a Holder has a permission, the most specific node matching the permission is used: Nodes
+// granted or denied directly to the Holder are checked first, after which the groups of the Holder are checked
+// in the order they were added. Groups check their own nodes before those of the groups they inherit from.
+// A permission that is not matched by any node is not granted.
+package permission
+
+import "strings"
+
+// nodes is a set of permission nodes, mapping a node to whether it is granted or denied.
+type nodes map[string]bool
+
+// resolve looks up the most specific node in the set that matches the node passed. The value of that node is
+// returned, along with true if any node in the set matched.
+func (n nodes) resolve(node string) (value, ok bool) {
+	best := -1
+	for pattern, v := range n {
+		if s := specificity(pattern, node); s > best {
+			best, value = s, v
+		}
+	}
+	return value, best >= 0
+}
+
+// clone returns a copy of the set of nodes.
+func (n nodes) clone() map[string]bool {
+	m := make(map[string]bool, len(n))
+	for node, v := range n {
+		m[node] = v
+	}
+	return m
+}
+
+// Match checks if the pattern passed matches the node passed. A pattern matches a node if it is equal to it,
+// if the pattern is "*", or if the pattern ends in ".*" and the node starts with the part of the pattern before
+// the "*". Matching is case-insensitive.
+func Match(pattern, node string) bool {
+	return specificity(normalise(pattern), normalise(node)) >= 0
+}
+
+// specificity returns how specific the pattern passed is for the node passed. A higher value means a more
+// specific match, with exact matches being more specific than any wildcard. -1 is returned if the pattern does
+// not match the node.
+func specificity(pattern, node string) int {
+	switch {
+	case pattern == node:
+		return len(pattern) + 1
+	case pattern == "*":
+		return 0
+	case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(node, pattern[:len(pattern)-1]):
+		return len(pattern) - 1
+	}
+	return -1
+}
+
+// normalise normalises the node passed so that it may be compared with other nodes.
+func normalise(node string) string {
+	return strings.ToLower(strings.TrimSpace(node))
+}
@@ -0,0 +1,41 @@
+package permission
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"io"
+)
+
+// Data holds the permissions of a Holder in a form that may be stored by a Provider.
+type Data struct {
+	// Groups holds the names of the groups that the Holder is in, ordered by precedence.
+	Groups []string
+	// Nodes holds the nodes set directly on the Holder, mapped to whether they are granted or denied.
+	Nodes map[string]bool
+}
+
+// Provider represents a value that may store the permissions of players, so that they persist when the player
+// leaves the server.
+type Provider interface {
+	// Save is called when the player leaves the server. The permission Data of the player is passed.
+	Save(uuid uuid.UUID, data Data) error
+	// Load is called when the player joins and passes the UUID of the player. It returns the permission Data of
+	// the player and an error that is nil if the Data could be found. If non-nil, the player keeps the default
+	// permissions it was created with.
+	Load(uuid uuid.UUID) (Data, error)
+	// Closer is used on server close when the server calls Provider.Close() and is used to safely close the Provider.
+	io.Closer
+}
+
+// Compile time check to make sure NopProvider implements Provider.
+var _ Provider = (*NopProvider)(nil)
+
+// NopProvider is a permission provider that won't store any data and instead always returns an error when
+// loading, so that default permissions are used.
+type NopProvider struct{}
+
+func (NopProvider) Save(uuid.UUID, Data) error { return nil }
+func (NopProvider) Load(uuid.UUID) (Data, error) {
+	return Data{}, errors.New("")
+}
+func (NopProvider) Close() error { return nil }
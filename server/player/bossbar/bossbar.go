@@ -11,14 +11,23 @@ type BossBar struct {
 	text   string
 	health float64
 	c      Colour
+	s      Style
 }
 
 // New creates a new boss bar with the text passed. The text is formatted according to the rules of
 // fmt.Sprintln.
 // By default, the boss bar will have a full health bar. To change this, use BossBar.WithHealthPercentage().
 // The default colour of the BossBar is Purple. This can be changed using BossBar.WithColour.
+// The default style of the BossBar is Progress. This can be changed using BossBar.WithStyle.
 func New(text ...any) BossBar {
-	return BossBar{text: format(text), health: 1, c: Purple()}
+	return BossBar{text: format(text), health: 1, c: Purple(), s: Progress()}
+}
+
+// WithText returns a copy of the BossBar with the text passed. The text is formatted according to the rules
+// of fmt.Sprintln.
+func (bar BossBar) WithText(text ...any) BossBar {
+	bar.text = format(text)
+	return bar
 }
 
 // Text returns the text of the boss bar: The text passed when creating the bar using New().
@@ -43,6 +52,12 @@ func (bar BossBar) WithColour(c Colour) BossBar {
 	return bar
 }
 
+// WithStyle returns a copy of the BossBar with the Style passed.
+func (bar BossBar) WithStyle(s Style) BossBar {
+	bar.s = s
+	return bar
+}
+
 // HealthPercentage returns the health percentage of the boss bar. The number returned is a value between 0
 // and 1, with 0 being an empty boss bar and 1 being a full one.
 func (bar BossBar) HealthPercentage() float64 {
@@ -54,6 +69,11 @@ func (bar BossBar) Colour() Colour {
 	return bar.c
 }
 
+// Style returns the Style of the BossBar.
+func (bar BossBar) Style() Style {
+	return bar.s
+}
+
 // format is a utility function to format a list of values to have spaces between them, but no newline at the
 // end, which is typically used for sending messages, popups and tips.
 func format(a []any) string {
@@ -0,0 +1,35 @@
+package bossbar
+
+// Style is the style of a BossBar. It specifies the amount of notches shown on the bar.
+type Style struct{ style }
+
+// Progress is the default style of a boss bar. The bar is shown without any notches.
+func Progress() Style {
+	return Style{style(0)}
+}
+
+// Notched6 is the style of a boss bar with the bar split into 6 segments.
+func Notched6() Style {
+	return Style{style(1)}
+}
+
+// Notched10 is the style of a boss bar with the bar split into 10 segments.
+func Notched10() Style {
+	return Style{style(2)}
+}
+
+// Notched12 is the style of a boss bar with the bar split into 12 segments.
+func Notched12() Style {
+	return Style{style(3)}
+}
+
+// Notched20 is the style of a boss bar with the bar split into 20 segments.
+func Notched20() Style {
+	return Style{style(4)}
+}
+
+type style uint8
+
+func (s style) Uint8() uint8 {
+	return uint8(s)
+}
@@ -0,0 +1,379 @@
+package player
+
+import (
+	"net"
+	"slices"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+)
+
+// handlerBus is a Handler that passes the events of a Player to all Handlers subscribed to it, in the order of
+// their event.Priority. Asynchronous Handlers are passed copies of the values that events pass by pointer.
+type handlerBus struct {
+	event.Bus[Handler]
+}
+
+// HandleMove ...
+func (hb *handlerBus) HandleMove(ctx *event.Context, newPos mgl64.Vec3, newYaw, newPitch float64) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleMove(ctx, newPos, newYaw, newPitch)
+	}, nil)
+}
+
+// HandleMovementViolation ...
+func (hb *handlerBus) HandleMovementViolation(ctx *event.Context, v MovementViolation) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleMovementViolation(ctx, v)
+	}, nil)
+}
+
+// HandleJump ...
+func (hb *handlerBus) HandleJump() {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleJump()
+	}, nil)
+}
+
+// HandleTeleport ...
+func (hb *handlerBus) HandleTeleport(ctx *event.Context, pos mgl64.Vec3) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleTeleport(ctx, pos)
+	}, nil)
+}
+
+// HandleChangeWorld ...
+func (hb *handlerBus) HandleChangeWorld(before, after *world.World) {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleChangeWorld(before, after)
+	}, nil)
+}
+
+// HandleToggleSprint ...
+func (hb *handlerBus) HandleToggleSprint(ctx *event.Context, after bool) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleToggleSprint(ctx, after)
+	}, nil)
+}
+
+// HandleHeldSlotChange ...
+func (hb *handlerBus) HandleHeldSlotChange(ctx *event.Context, from, to int) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleHeldSlotChange(ctx, from, to)
+	}, nil)
+}
+
+// HandleToggleSneak ...
+func (hb *handlerBus) HandleToggleSneak(ctx *event.Context, after bool) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleToggleSneak(ctx, after)
+	}, nil)
+}
+
+// HandleChat ...
+func (hb *handlerBus) HandleChat(ctx *event.Context, message *string) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleChat(ctx, message)
+	}, func() func(Handler, *event.Context) {
+		message := event.Clone(message)
+		return func(h Handler, ctx *event.Context) { h.HandleChat(ctx, event.Clone(message)) }
+	})
+}
+
+// HandleChatMessage ...
+func (hb *handlerBus) HandleChatMessage(ctx *event.Context, msg *chat.Message) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleChatMessage(ctx, msg)
+	}, func() func(Handler, *event.Context) {
+		msg := event.Clone(msg)
+		return func(h Handler, ctx *event.Context) { h.HandleChatMessage(ctx, event.Clone(msg)) }
+	})
+}
+
+// HandleFoodLoss ...
+func (hb *handlerBus) HandleFoodLoss(ctx *event.Context, from int, to *int) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleFoodLoss(ctx, from, to)
+	}, func() func(Handler, *event.Context) {
+		to := event.Clone(to)
+		return func(h Handler, ctx *event.Context) { h.HandleFoodLoss(ctx, from, event.Clone(to)) }
+	})
+}
+
+// HandleHeal ...
+func (hb *handlerBus) HandleHeal(ctx *event.Context, health *float64, src world.HealingSource) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleHeal(ctx, health, src)
+	}, func() func(Handler, *event.Context) {
+		health := event.Clone(health)
+		return func(h Handler, ctx *event.Context) { h.HandleHeal(ctx, event.Clone(health), src) }
+	})
+}
+
+// HandleHurt ...
+func (hb *handlerBus) HandleHurt(ctx *event.Context, damage *float64, attackImmunity *time.Duration, src world.DamageSource) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleHurt(ctx, damage, attackImmunity, src)
+	}, func() func(Handler, *event.Context) {
+		damage := event.Clone(damage)
+		attackImmunity := event.Clone(attackImmunity)
+		return func(h Handler, ctx *event.Context) {
+			h.HandleHurt(ctx, event.Clone(damage), event.Clone(attackImmunity), src)
+		}
+	})
+}
+
+// HandlePreDeath ...
+func (hb *handlerBus) HandlePreDeath(ctx *event.Context, src world.DamageSource) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandlePreDeath(ctx, src)
+	}, nil)
+}
+
+// HandleDeath ...
+func (hb *handlerBus) HandleDeath(src world.DamageSource, keepInv *bool) {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleDeath(src, keepInv)
+	}, func() func(Handler, *event.Context) {
+		keepInv := event.Clone(keepInv)
+		return func(h Handler, _ *event.Context) { h.HandleDeath(src, event.Clone(keepInv)) }
+	})
+}
+
+// HandleDeathMessage ...
+func (hb *handlerBus) HandleDeathMessage(src world.DamageSource, message *string) {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleDeathMessage(src, message)
+	}, func() func(Handler, *event.Context) {
+		message := event.Clone(message)
+		return func(h Handler, _ *event.Context) { h.HandleDeathMessage(src, event.Clone(message)) }
+	})
+}
+
+// HandleRespawn ...
+func (hb *handlerBus) HandleRespawn(pos *mgl64.Vec3, w **world.World) {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleRespawn(pos, w)
+	}, func() func(Handler, *event.Context) {
+		pos := event.Clone(pos)
+		w := event.Clone(w)
+		return func(h Handler, _ *event.Context) { h.HandleRespawn(event.Clone(pos), event.Clone(w)) }
+	})
+}
+
+// HandleRespawnState ...
+func (hb *handlerBus) HandleRespawnState(state *RespawnState) {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleRespawnState(state)
+	}, func() func(Handler, *event.Context) {
+		state := event.Clone(state)
+		return func(h Handler, _ *event.Context) { h.HandleRespawnState(event.Clone(state)) }
+	})
+}
+
+// HandleSkinChange ...
+func (hb *handlerBus) HandleSkinChange(ctx *event.Context, skin *skin.Skin) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleSkinChange(ctx, skin)
+	}, func() func(Handler, *event.Context) {
+		skin := event.Clone(skin)
+		return func(h Handler, ctx *event.Context) { h.HandleSkinChange(ctx, event.Clone(skin)) }
+	})
+}
+
+// HandleEmote ...
+func (hb *handlerBus) HandleEmote(ctx *event.Context, emote uuid.UUID) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleEmote(ctx, emote)
+	}, nil)
+}
+
+// HandleStartBreak ...
+func (hb *handlerBus) HandleStartBreak(ctx *event.Context, pos cube.Pos) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleStartBreak(ctx, pos)
+	}, nil)
+}
+
+// HandleBlockBreak ...
+func (hb *handlerBus) HandleBlockBreak(ctx *event.Context, pos cube.Pos, drops *[]item.Stack, xp *int) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleBlockBreak(ctx, pos, drops, xp)
+	}, func() func(Handler, *event.Context) {
+		drops := cloneSlice(drops)
+		xp := event.Clone(xp)
+		return func(h Handler, ctx *event.Context) { h.HandleBlockBreak(ctx, pos, cloneSlice(drops), event.Clone(xp)) }
+	})
+}
+
+// HandleBlockPlace ...
+func (hb *handlerBus) HandleBlockPlace(ctx *event.Context, pos cube.Pos, b world.Block) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleBlockPlace(ctx, pos, b)
+	}, nil)
+}
+
+// HandleBlockPick ...
+func (hb *handlerBus) HandleBlockPick(ctx *event.Context, pos cube.Pos, b world.Block) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleBlockPick(ctx, pos, b)
+	}, nil)
+}
+
+// HandleItemUse ...
+func (hb *handlerBus) HandleItemUse(ctx *event.Context) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemUse(ctx)
+	}, nil)
+}
+
+// HandleItemUseOnBlock ...
+func (hb *handlerBus) HandleItemUseOnBlock(ctx *event.Context, pos cube.Pos, face cube.Face, clickPos mgl64.Vec3) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemUseOnBlock(ctx, pos, face, clickPos)
+	}, nil)
+}
+
+// HandleItemUseOnEntity ...
+func (hb *handlerBus) HandleItemUseOnEntity(ctx *event.Context, e world.Entity) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemUseOnEntity(ctx, e)
+	}, nil)
+}
+
+// HandleItemConsume ...
+func (hb *handlerBus) HandleItemConsume(ctx *event.Context, item item.Stack) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemConsume(ctx, item)
+	}, nil)
+}
+
+// HandleAttackEntity ...
+func (hb *handlerBus) HandleAttackEntity(ctx *event.Context, e world.Entity, force, height *float64, critical *bool) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleAttackEntity(ctx, e, force, height, critical)
+	}, func() func(Handler, *event.Context) {
+		force := event.Clone(force)
+		height := event.Clone(height)
+		critical := event.Clone(critical)
+		return func(h Handler, ctx *event.Context) {
+			h.HandleAttackEntity(ctx, e, event.Clone(force), event.Clone(height), event.Clone(critical))
+		}
+	})
+}
+
+// HandleExperienceGain ...
+func (hb *handlerBus) HandleExperienceGain(ctx *event.Context, amount *int) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleExperienceGain(ctx, amount)
+	}, func() func(Handler, *event.Context) {
+		amount := event.Clone(amount)
+		return func(h Handler, ctx *event.Context) { h.HandleExperienceGain(ctx, event.Clone(amount)) }
+	})
+}
+
+// HandlePunchAir ...
+func (hb *handlerBus) HandlePunchAir(ctx *event.Context) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandlePunchAir(ctx)
+	}, nil)
+}
+
+// HandleLeftClickAir ...
+func (hb *handlerBus) HandleLeftClickAir(ctx *event.Context, sinceLast time.Duration) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleLeftClickAir(ctx, sinceLast)
+	}, nil)
+}
+
+// HandleRightClickAir ...
+func (hb *handlerBus) HandleRightClickAir(ctx *event.Context, sinceLast time.Duration) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleRightClickAir(ctx, sinceLast)
+	}, nil)
+}
+
+// HandleSignEdit ...
+func (hb *handlerBus) HandleSignEdit(ctx *event.Context, frontSide bool, oldText, newText string) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleSignEdit(ctx, frontSide, oldText, newText)
+	}, nil)
+}
+
+// HandleLecternPageTurn ...
+func (hb *handlerBus) HandleLecternPageTurn(ctx *event.Context, pos cube.Pos, oldPage int, newPage *int) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleLecternPageTurn(ctx, pos, oldPage, newPage)
+	}, func() func(Handler, *event.Context) {
+		newPage := event.Clone(newPage)
+		return func(h Handler, ctx *event.Context) { h.HandleLecternPageTurn(ctx, pos, oldPage, event.Clone(newPage)) }
+	})
+}
+
+// HandleItemDamage ...
+func (hb *handlerBus) HandleItemDamage(ctx *event.Context, i item.Stack, damage int) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemDamage(ctx, i, damage)
+	}, nil)
+}
+
+// HandleItemPickup ...
+func (hb *handlerBus) HandleItemPickup(ctx *event.Context, i *item.Stack) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemPickup(ctx, i)
+	}, func() func(Handler, *event.Context) {
+		i := event.Clone(i)
+		return func(h Handler, ctx *event.Context) { h.HandleItemPickup(ctx, event.Clone(i)) }
+	})
+}
+
+// HandleItemDrop ...
+func (hb *handlerBus) HandleItemDrop(ctx *event.Context, e world.Entity) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleItemDrop(ctx, e)
+	}, nil)
+}
+
+// HandleTransfer ...
+func (hb *handlerBus) HandleTransfer(ctx *event.Context, addr *net.UDPAddr) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleTransfer(ctx, addr)
+	}, func() func(Handler, *event.Context) {
+		addr := event.Clone(addr)
+		return func(h Handler, ctx *event.Context) { h.HandleTransfer(ctx, event.Clone(addr)) }
+	})
+}
+
+// HandleCommandExecution ...
+func (hb *handlerBus) HandleCommandExecution(ctx *event.Context, command cmd.Command, args []string) {
+	hb.Dispatch(ctx, func(h Handler, ctx *event.Context) {
+		h.HandleCommandExecution(ctx, command, args)
+	}, nil)
+}
+
+// HandleQuit ...
+func (hb *handlerBus) HandleQuit() {
+	hb.Dispatch(nil, func(h Handler, _ *event.Context) {
+		h.HandleQuit()
+	}, nil)
+}
+
+// cloneSlice returns a pointer to a copy of the slice that p points to, or nil if p is nil.
+func cloneSlice[T any](p *[]T) *[]T {
+	if p == nil {
+		return nil
+	}
+	s := slices.Clone(*p)
+	return &s
+}
+
+// Compile time check to make sure handlerBus implements Handler.
+var _ Handler = (*handlerBus)(nil)
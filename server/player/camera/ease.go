@@ -0,0 +1,54 @@
+package camera
+
+import (
+	"time"
+)
+
+// Ease is an easing applied to a Set instruction, which makes the camera move to the position and rotation
+// of the instruction gradually over its duration.
+type Ease struct {
+	// Type is the easing function used to move the camera.
+	Type EaseType
+	// Duration is the time that it takes for the camera to reach the position and rotation of the
+	// instruction.
+	Duration time.Duration
+}
+
+// EaseType is an easing function that determines how the camera moves over the duration of an Ease.
+type EaseType uint8
+
+// The easing functions that may be used for an Ease.
+const (
+	EaseLinear EaseType = iota
+	EaseSpring
+	EaseInQuad
+	EaseOutQuad
+	EaseInOutQuad
+	EaseInCubic
+	EaseOutCubic
+	EaseInOutCubic
+	EaseInQuart
+	EaseOutQuart
+	EaseInOutQuart
+	EaseInQuint
+	EaseOutQuint
+	EaseInOutQuint
+	EaseInSine
+	EaseOutSine
+	EaseInOutSine
+	EaseInExpo
+	EaseOutExpo
+	EaseInOutExpo
+	EaseInCirc
+	EaseOutCirc
+	EaseInOutCirc
+	EaseInBounce
+	EaseOutBounce
+	EaseInOutBounce
+	EaseInBack
+	EaseOutBack
+	EaseInOutBack
+	EaseInElastic
+	EaseOutElastic
+	EaseInOutElastic
+)
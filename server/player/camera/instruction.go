@@ -0,0 +1,131 @@
+package camera
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Set is an instruction that sets the camera of a player to a Preset, optionally overriding the position and
+// rotation of the Preset. The camera stays in place until another Set instruction is sent or until the
+// camera is reset.
+type Set struct {
+	preset Preset
+	ease   *Ease
+
+	pos, facing *mgl64.Vec3
+	rot         *cube.Rotation
+}
+
+// NewSet creates a new Set instruction that sets the camera of a player to the Preset passed.
+func NewSet(p Preset) Set {
+	return Set{preset: p}
+}
+
+// Preset returns the Preset that the Set instruction sets the camera to.
+func (s Set) Preset() Preset {
+	return s.preset
+}
+
+// WithEase returns a copy of the Set instruction that moves the camera gradually using the Ease passed,
+// instead of moving it instantly.
+func (s Set) WithEase(e Ease) Set {
+	s.ease = &e
+	return s
+}
+
+// Ease returns the Ease of the Set instruction. False is returned if the camera is moved instantly.
+func (s Set) Ease() (Ease, bool) {
+	if s.ease == nil {
+		return Ease{}, false
+	}
+	return *s.ease, true
+}
+
+// WithPosition returns a copy of the Set instruction that moves the camera to the position passed.
+func (s Set) WithPosition(pos mgl64.Vec3) Set {
+	s.pos = &pos
+	return s
+}
+
+// Position returns the position that the Set instruction moves the camera to. False is returned if the
+// position of the Preset is used.
+func (s Set) Position() (mgl64.Vec3, bool) {
+	if s.pos == nil {
+		return mgl64.Vec3{}, false
+	}
+	return *s.pos, true
+}
+
+// WithRotation returns a copy of the Set instruction that rotates the camera to the rotation passed.
+func (s Set) WithRotation(rot cube.Rotation) Set {
+	s.rot = &rot
+	return s
+}
+
+// Rotation returns the rotation that the Set instruction rotates the camera to. False is returned if the
+// rotation of the Preset is used.
+func (s Set) Rotation() (cube.Rotation, bool) {
+	if s.rot == nil {
+		return cube.Rotation{}, false
+	}
+	return *s.rot, true
+}
+
+// WithFacing returns a copy of the Set instruction that keeps the camera facing towards the position passed,
+// regardless of its rotation.
+func (s Set) WithFacing(pos mgl64.Vec3) Set {
+	s.facing = &pos
+	return s
+}
+
+// Facing returns the position that the camera keeps facing towards. False is returned if the camera does not
+// face a specific position.
+func (s Set) Facing() (mgl64.Vec3, bool) {
+	if s.facing == nil {
+		return mgl64.Vec3{}, false
+	}
+	return *s.facing, true
+}
+
+// Fade is an instruction that fades the screen of a player to a colour and back.
+type Fade struct {
+	fadeIn, wait, fadeOut time.Duration
+	colour                color.RGBA
+}
+
+// NewFade creates a new Fade instruction that fades the screen to black over the fade-in duration, keeps it
+// black for the wait duration and fades back over the fade-out duration.
+func NewFade(fadeIn, wait, fadeOut time.Duration) Fade {
+	return Fade{fadeIn: fadeIn, wait: wait, fadeOut: fadeOut, colour: color.RGBA{A: 0xff}}
+}
+
+// WithColour returns a copy of the Fade instruction that fades the screen to the colour passed instead of
+// black.
+func (f Fade) WithColour(c color.RGBA) Fade {
+	f.colour = c
+	return f
+}
+
+// Colour returns the colour that the screen is faded to. By default, this is black.
+func (f Fade) Colour() color.RGBA {
+	return f.colour
+}
+
+// FadeInDuration returns the duration that the screen takes to fade to the colour of the Fade instruction.
+func (f Fade) FadeInDuration() time.Duration {
+	return f.fadeIn
+}
+
+// WaitDuration returns the duration that the screen stays fully faded.
+func (f Fade) WaitDuration() time.Duration {
+	return f.wait
+}
+
+// FadeOutDuration returns the duration that the screen takes to fade back from the colour of the Fade
+// instruction.
+func (f Fade) FadeOutDuration() time.Duration {
+	return f.fadeOut
+}
@@ -0,0 +1,140 @@
+// Package camera implements instructions that take control of the camera of a player, which may be used to
+// build cutscenes or to fly the camera of a player through a lobby. The camera is moved by sending a Set
+// instruction using a Preset, which may be eased into over time, and the screen may be faded to a colour
+// using a Fade.
+package camera
+
+import (
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Preset is a camera preset that a Set instruction is based on. Next to the presets built into the game, such
+// as Free and FirstPerson, custom presets may be created using NewPreset and registered using Register.
+type Preset struct {
+	name, parent string
+
+	pos *mgl64.Vec3
+	rot *cube.Rotation
+
+	playerAudio, playerEffects bool
+}
+
+var (
+	// Free is a camera preset that detaches the camera from the player, so that it may be moved freely using
+	// Set instructions.
+	Free = Preset{name: "minecraft:free"}
+	// FirstPerson is the camera preset of the regular first person view of the player.
+	FirstPerson = Preset{name: "minecraft:first_person"}
+	// ThirdPerson is the camera preset of the third person view of the player, looking at the back of the
+	// player.
+	ThirdPerson = Preset{name: "minecraft:third_person"}
+	// ThirdPersonFront is the camera preset of the third person view of the player, looking at the front of
+	// the player.
+	ThirdPersonFront = Preset{name: "minecraft:third_person_front"}
+)
+
+// NewPreset creates a new camera preset with the name passed, such as 'lobby:overview', that inherits the
+// properties of the parent Preset passed. The Preset must be registered using Register before players join
+// for it to be usable.
+func NewPreset(name string, parent Preset) Preset {
+	return Preset{name: name, parent: parent.name}
+}
+
+// Name returns the name of the Preset, such as 'minecraft:free'.
+func (p Preset) Name() string {
+	return p.name
+}
+
+// Parent returns the name of the Preset that the Preset inherits its properties from. An empty string is
+// returned for presets built into the game.
+func (p Preset) Parent() string {
+	return p.parent
+}
+
+// WithPosition returns a copy of the Preset with the default position of the camera set to the position
+// passed.
+func (p Preset) WithPosition(pos mgl64.Vec3) Preset {
+	p.pos = &pos
+	return p
+}
+
+// Position returns the default position of the camera of the Preset. False is returned if the Preset has no
+// default position.
+func (p Preset) Position() (mgl64.Vec3, bool) {
+	if p.pos == nil {
+		return mgl64.Vec3{}, false
+	}
+	return *p.pos, true
+}
+
+// WithRotation returns a copy of the Preset with the default rotation of the camera set to the rotation
+// passed.
+func (p Preset) WithRotation(rot cube.Rotation) Preset {
+	p.rot = &rot
+	return p
+}
+
+// Rotation returns the default rotation of the camera of the Preset. False is returned if the Preset has no
+// default rotation.
+func (p Preset) Rotation() (cube.Rotation, bool) {
+	if p.rot == nil {
+		return cube.Rotation{}, false
+	}
+	return *p.rot, true
+}
+
+// WithPlayerAudio returns a copy of the Preset that plays audio from the position of the player instead of
+// the position of the camera.
+func (p Preset) WithPlayerAudio() Preset {
+	p.playerAudio = true
+	return p
+}
+
+// PlayerAudio checks if the Preset plays audio from the position of the player instead of the position of
+// the camera.
+func (p Preset) PlayerAudio() bool {
+	return p.playerAudio
+}
+
+// WithPlayerEffects returns a copy of the Preset that keeps showing the effects applied to the player, such as
+// the screen effects of nausea, while the camera is used.
+func (p Preset) WithPlayerEffects() Preset {
+	p.playerEffects = true
+	return p
+}
+
+// PlayerEffects checks if the Preset keeps showing the effects applied to the player.
+func (p Preset) PlayerEffects() bool {
+	return p.playerEffects
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = []Preset{Free, FirstPerson, ThirdPerson, ThirdPersonFront}
+)
+
+// Register registers a custom Preset so that it is sent to players when they join. A Preset registered
+// earlier with the same name is replaced. Presets registered after a player joined are not available to
+// that player.
+func Register(p Preset) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	for i, other := range presets {
+		if other.name == p.name {
+			presets[i] = p
+			return
+		}
+	}
+	presets = append(presets, p)
+}
+
+// Presets returns all camera presets, starting with the presets built into the game followed by the presets
+// registered using Register, in the order that they were registered.
+func Presets() []Preset {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	return append([]Preset(nil), presets...)
+}
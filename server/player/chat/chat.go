@@ -1,12 +1,22 @@
 package chat
 
 import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
 	"sync"
 )
 
-// Global represents a global chat. Players will write in this chat by default when they send any message in
-// the chat.
-var Global = New()
+var (
+	// Global represents a global chat. Players will write in this chat by default when they send any message in
+	// the chat.
+	Global = New()
+	// Local represents a local chat. Messages sent in the Local chat are only delivered to subscribers of the
+	// Global chat that are within 64 blocks of the sender.
+	Local = NewLocal(Global, 64)
+	// Staff represents a chat for staff members. No subscribers are added to the Staff chat by default: Players
+	// must be subscribed to it explicitly to receive messages sent in it.
+	Staff = New()
+)
 
 // Chat represents the in-game chat. Messages may be written to it to send a message to all subscribers. The
 // zero value of Chat is a chat ready to use.
@@ -16,6 +26,9 @@ var Global = New()
 type Chat struct {
 	m           sync.Mutex
 	subscribers map[Subscriber]struct{}
+
+	parent *Chat
+	radius float64
 }
 
 // New returns a new chat.
@@ -23,6 +36,14 @@ func New() *Chat {
 	return &Chat{subscribers: map[Subscriber]struct{}{}}
 }
 
+// NewLocal returns a new local chat. Messages sent in the local chat by a sender are delivered to the
+// subscribers of both the local chat and the parent chat passed that are in the same world as the sender and
+// within the radius passed. Subscribers without a position, such as a StdoutSubscriber, always receive the
+// messages.
+func NewLocal(parent *Chat, radius float64) *Chat {
+	return &Chat{subscribers: map[Subscriber]struct{}{}, parent: parent, radius: radius}
+}
+
 // Write writes the byte slice p as a string to the chat. It is equivalent to calling
 // Chat.WriteString(string(p)).
 func (chat *Chat) Write(p []byte) (n int, err error) {
@@ -39,6 +60,49 @@ func (chat *Chat) WriteString(s string) (n int, err error) {
 	return len(s), nil
 }
 
+// Subscribers returns all subscribers of the chat.
+func (chat *Chat) Subscribers() []Subscriber {
+	chat.m.Lock()
+	defer chat.m.Unlock()
+	subscribers := make([]Subscriber, 0, len(chat.subscribers))
+	for s := range chat.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	return subscribers
+}
+
+// Recipients returns the subscribers that a message sent in the chat by the sender passed should be delivered
+// to. For chats created using New, these are all subscribers of the chat. For local chats created using
+// NewLocal, only the subscribers within range of the sender are returned.
+func (chat *Chat) Recipients(sender Subscriber) []Subscriber {
+	if chat.parent == nil {
+		return chat.Subscribers()
+	}
+	from, ok := sender.(positioned)
+	candidates := append(chat.Subscribers(), chat.parent.Recipients(sender)...)
+	recipients := make([]Subscriber, 0, len(candidates))
+	seen := make(map[Subscriber]struct{}, len(candidates))
+	for _, s := range candidates {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		if to, positioned := s.(positioned); ok && positioned {
+			if to.World() != from.World() || to.Position().Sub(from.Position()).Len() > chat.radius {
+				continue
+			}
+		}
+		recipients = append(recipients, s)
+	}
+	return recipients
+}
+
+// positioned is a Subscriber that has a position in a world, such as a player.
+type positioned interface {
+	Position() mgl64.Vec3
+	World() *world.World
+}
+
 // Subscribe adds a subscriber to the chat, sending it every message written to the chat. In order to remove
 // it again, use Chat.Unsubscribe().
 func (chat *Chat) Subscribe(s Subscriber) {
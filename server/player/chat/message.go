@@ -0,0 +1,37 @@
+package chat
+
+import "strings"
+
+// DefaultFormat is the format used for chat messages sent by players by default. It shows the name of the
+// sender between angle brackets, followed by the message.
+const DefaultFormat = "<{name}> {message}"
+
+// Message is a chat message sent by a player. It holds the text of the message and the way it is delivered,
+// all of which may be changed before the message is sent.
+type Message struct {
+	// Text is the text of the message as sent by the player.
+	Text string
+	// Format is the template that the message is formatted with before being delivered. The placeholders
+	// {name} and {message} are replaced with the name of the sender and the Text respectively.
+	Format string
+	// Chat is the chat that the message is sent in, such as Global, Local or Staff. Changing Chat does not
+	// change the Recipients of the message: Chat.Recipients may be used to obtain the recipients of another
+	// chat.
+	Chat *Chat
+	// Recipients holds the subscribers that the message is delivered to. It is initially filled with the
+	// recipients of the Chat, but subscribers may be removed or added to filter who receives the message.
+	Recipients []Subscriber
+}
+
+// Formatted returns the message formatted using its Format, with the name of the sender passed.
+func (m Message) Formatted(name string) string {
+	return strings.NewReplacer("{name}", name, "{message}", m.Text).Replace(m.Format)
+}
+
+// Deliver delivers the message, formatted with the name of the sender passed, to all of its Recipients.
+func (m Message) Deliver(name string) {
+	s := m.Formatted(name)
+	for _, r := range m.Recipients {
+		r.Message(s)
+	}
+}
@@ -0,0 +1,113 @@
+package player
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/item/enchantment"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// DeathMessageFunc returns the death message broadcast when the Player passed dies to the world.DamageSource
+// passed.
+type DeathMessageFunc func(p *Player, src world.DamageSource) string
+
+var (
+	deathMessageMu sync.RWMutex
+	deathMessages  = map[reflect.Type]DeathMessageFunc{}
+)
+
+// RegisterDeathMessage registers a DeathMessageFunc that is used for the death message of players dying to a
+// world.DamageSource of the same type as the one passed, replacing the default death message and any
+// DeathMessageFunc registered earlier for that type.
+func RegisterDeathMessage(src world.DamageSource, f DeathMessageFunc) {
+	deathMessageMu.Lock()
+	defer deathMessageMu.Unlock()
+	deathMessages[reflect.TypeOf(src)] = f
+}
+
+// DeathMessage returns the death message of the Player passed dying to the world.DamageSource passed. If a
+// DeathMessageFunc was registered for the type of the source using RegisterDeathMessage, it is used.
+// Otherwise, the default message of the source, as found in vanilla, is returned.
+func DeathMessage(p *Player, src world.DamageSource) string {
+	deathMessageMu.RLock()
+	f, ok := deathMessages[reflect.TypeOf(src)]
+	deathMessageMu.RUnlock()
+	if ok {
+		return f(p, src)
+	}
+	return defaultDeathMessage(p.Name(), src)
+}
+
+// defaultDeathMessage returns the vanilla death message of a player with the name passed dying to the
+// world.DamageSource passed.
+func defaultDeathMessage(name string, src world.DamageSource) string {
+	switch src := src.(type) {
+	case entity.AttackDamageSource:
+		return fmt.Sprintf("%v was slain by %v", name, entityName(src.Attacker))
+	case entity.ProjectileDamageSource:
+		if src.Owner == nil {
+			return fmt.Sprintf("%v was shot by %v", name, entityName(src.Projectile))
+		}
+		return fmt.Sprintf("%v was shot by %v", name, entityName(src.Owner))
+	case entity.SonicBoomDamageSource:
+		return fmt.Sprintf("%v was obliterated by a sonically-charged shriek", name)
+	case enchantment.ThornsDamageSource:
+		return fmt.Sprintf("%v was killed trying to hurt %v", name, entityName(src.Owner))
+	case entity.VoidDamageSource:
+		return fmt.Sprintf("%v fell out of the world", name)
+	case entity.SuffocationDamageSource:
+		return fmt.Sprintf("%v suffocated in a wall", name)
+	case entity.DrowningDamageSource:
+		return fmt.Sprintf("%v drowned", name)
+	case entity.FallDamageSource:
+		return fmt.Sprintf("%v fell from a high place", name)
+	case entity.GlideDamageSource:
+		return fmt.Sprintf("%v experienced kinetic energy", name)
+	case entity.LightningDamageSource:
+		return fmt.Sprintf("%v was struck by lightning", name)
+	case entity.ExplosionDamageSource:
+		return fmt.Sprintf("%v blew up", name)
+	case entity.CrammingDamageSource:
+		return fmt.Sprintf("%v was squished too much", name)
+	case entity.BorderDamageSource:
+		return fmt.Sprintf("%v left the confines of this world", name)
+	case block.DamageSource:
+		return fmt.Sprintf("%v was pricked to death", name)
+	case block.FireDamageSource:
+		return fmt.Sprintf("%v went up in flames", name)
+	case block.FreezeDamageSource:
+		return fmt.Sprintf("%v froze to death", name)
+	case block.LavaDamageSource:
+		return fmt.Sprintf("%v tried to swim in lava", name)
+	case effect.WitherDamageSource:
+		return fmt.Sprintf("%v withered away", name)
+	case effect.InstantDamageSource, effect.PoisonDamageSource:
+		return fmt.Sprintf("%v was killed by magic", name)
+	case StarvationDamageSource:
+		return fmt.Sprintf("%v starved to death", name)
+	}
+	return fmt.Sprintf("%v died", name)
+}
+
+// entityName returns the name of a world.Entity as shown in death messages. For players, this is their name.
+// For other entities, it is their name tag, or the name of their type if they have none.
+func entityName(e world.Entity) string {
+	switch e := e.(type) {
+	case nil:
+		return "magic"
+	case *Player:
+		return e.Name()
+	case interface{ NameTag() string }:
+		if tag := e.NameTag(); tag != "" {
+			return tag
+		}
+	}
+	id := strings.TrimPrefix(e.Type().EncodeEntity(), "minecraft:")
+	return strings.ReplaceAll(id, "_", " ")
+}
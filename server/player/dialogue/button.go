@@ -0,0 +1,57 @@
+package dialogue
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Button is a button shown on a page of a Dialogue. Pressing it either runs a function, executes a command as
+// the Submitter or, for the buttons added to move between pages, moves to another page.
+type Button struct {
+	text    string
+	command string
+	submit  func(s Submitter, e world.Entity)
+
+	// navigation specifies if the button was added by a Dialogue to move to the page with the index page.
+	navigation bool
+	page       int
+}
+
+// NewButton creates a new Button with the text passed that calls the function passed when pressed. The
+// function is called with the Submitter that pressed the Button and the entity showing the Dialogue. The
+// Dialogue is closed before the function is called, so the function may send another Dialogue.
+func NewButton(text string, submit func(s Submitter, e world.Entity)) Button {
+	return Button{text: text, submit: submit}
+}
+
+// NewCommandButton creates a new Button with the text passed that executes the command line passed as the
+// Submitter when pressed, for example '/spawn'.
+func NewCommandButton(text, commandLine string) Button {
+	return Button{text: text, command: commandLine}
+}
+
+// Text returns the text shown on the Button.
+func (b Button) Text() string {
+	return b.text
+}
+
+// Command returns the command line executed when the Button is pressed. An empty string is returned if the
+// Button does not execute a command.
+func (b Button) Command() string {
+	return b.command
+}
+
+// TargetPage returns the index of the page that the Button moves to. False is returned if the Button does not
+// move between pages.
+func (b Button) TargetPage() (int, bool) {
+	return b.page, b.navigation
+}
+
+// Submit runs the function of the Button or executes its command as the Submitter passed.
+func (b Button) Submit(s Submitter, e world.Entity) {
+	if b.submit != nil {
+		b.submit(s, e)
+	}
+	if b.command != "" {
+		s.ExecuteCommand(b.command)
+	}
+}
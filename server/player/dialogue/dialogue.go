@@ -0,0 +1,133 @@
+// Package dialogue implements the dialogue screen of NPCs, which shows the text of an NPC alongside buttons
+// that run server-side functions or commands when pressed. Dialogues may span multiple pages, through which
+// the Submitter may move using buttons added automatically.
+package dialogue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Dialogue is a dialogue that may be sent to a Submitter. It holds one or more pages of text, of which the
+// last shows the buttons of the Dialogue.
+type Dialogue struct {
+	title   string
+	pages   []string
+	buttons []Button
+	closer  func(s Submitter, e world.Entity)
+}
+
+// New creates a new Dialogue with the title passed. The title is formatted according to the rules of
+// fmt.Sprintln, but without the newline at the end. The title is shown above the text of the Dialogue.
+func New(title ...any) Dialogue {
+	return Dialogue{title: format(title)}
+}
+
+// Title returns the title of the Dialogue, as passed to New.
+func (d Dialogue) Title() string {
+	return d.title
+}
+
+// WithPages returns a copy of the Dialogue with the pages of text passed. Each page is shown separately, with
+// buttons to move to the next and previous pages. The buttons of the Dialogue are shown on the last page.
+func (d Dialogue) WithPages(pages ...string) Dialogue {
+	d.pages = pages
+	return d
+}
+
+// Pages returns the pages of text of the Dialogue. If no pages were set, Pages returns a single empty page.
+func (d Dialogue) Pages() []string {
+	if len(d.pages) == 0 {
+		return []string{""}
+	}
+	return d.pages
+}
+
+// WithButtons returns a copy of the Dialogue with the buttons passed, which are shown on the last page of the
+// Dialogue.
+func (d Dialogue) WithButtons(buttons ...Button) Dialogue {
+	d.buttons = buttons
+	return d
+}
+
+// Buttons returns the buttons of the Dialogue shown on its last page.
+func (d Dialogue) Buttons() []Button {
+	return d.buttons
+}
+
+// WithCloser returns a copy of the Dialogue that calls the function passed when the Submitter closes the
+// Dialogue without pressing one of its buttons.
+func (d Dialogue) WithCloser(f func(s Submitter, e world.Entity)) Dialogue {
+	d.closer = f
+	return d
+}
+
+// Close is called when the Submitter closes the Dialogue shown by the entity passed without pressing one of
+// its buttons.
+func (d Dialogue) Close(s Submitter, e world.Entity) {
+	if d.closer != nil {
+		d.closer(s, e)
+	}
+}
+
+// Page returns the page of the Dialogue with the index passed. Pages other than the last hold a button to
+// move to the next page, and pages other than the first a button to move to the previous page. The index
+// passed is clamped to the pages of the Dialogue.
+func (d Dialogue) Page(index int) Page {
+	pages := d.Pages()
+	index = max(0, min(index, len(pages)-1))
+
+	p := Page{Index: index, Text: pages[index]}
+	if index == len(pages)-1 {
+		p.Buttons = append(p.Buttons, d.buttons...)
+	} else {
+		p.Buttons = append(p.Buttons, Button{text: "Next", navigation: true, page: index + 1})
+	}
+	if index > 0 {
+		p.Buttons = append(p.Buttons, Button{text: "Back", navigation: true, page: index - 1})
+	}
+	return p
+}
+
+// Page is a single page of a Dialogue as shown to a Submitter.
+type Page struct {
+	// Index is the index of the page in the Dialogue.
+	Index int
+	// Text is the text shown on the page.
+	Text string
+	// Buttons holds the buttons shown on the page, including the buttons to move between pages.
+	Buttons []Button
+}
+
+// Submitter is an entity that is able to open a Dialogue and press its buttons.
+type Submitter interface {
+	// SendDialogue shows the Dialogue passed to the Submitter, using the world.Entity passed as the NPC
+	// showing the Dialogue.
+	SendDialogue(d Dialogue, e world.Entity)
+	// CloseDialogue closes the Dialogue currently shown to the Submitter.
+	CloseDialogue()
+	// ExecuteCommand executes a command as the Submitter.
+	ExecuteCommand(commandLine string)
+}
+
+// format is a utility function to format a list of values to have spaces between them, but no newline at the
+// end.
+func format(a []any) string {
+	return strings.TrimSuffix(strings.TrimSuffix(fmt.Sprintln(a...), "\n"), "\n")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -5,9 +5,11 @@ import (
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player/chat"
 	"github.com/df-mc/dragonfly/server/player/skin"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
 	"net"
 	"time"
 )
@@ -18,6 +20,10 @@ type Handler interface {
 	// HandleMove handles the movement of a player. ctx.Cancel() may be called to cancel the movement event.
 	// The new position, yaw and pitch are passed.
 	HandleMove(ctx *event.Context, newPos mgl64.Vec3, newYaw, newPitch float64)
+	// HandleMovementViolation handles a movement of the player that failed one of the checks of its
+	// MovementValidation, before HandleMove is called. ctx.Cancel() may be called to prevent the movement from
+	// being corrected.
+	HandleMovementViolation(ctx *event.Context, v MovementViolation)
 	// HandleJump handles the player jumping.
 	HandleJump()
 	// HandleTeleport handles the teleportation of a player. ctx.Cancel() may be called to cancel it.
@@ -27,6 +33,9 @@ type Handler interface {
 	// HandleToggleSprint handles when the player starts or stops sprinting.
 	// After is true if the player is sprinting after toggling (changing their sprinting state).
 	HandleToggleSprint(ctx *event.Context, after bool)
+	// HandleHeldSlotChange handles the player changing the hotbar slot that it holds from one slot to another.
+	// ctx.Cancel() may be called to cancel the change, in which case the player keeps holding the previous slot.
+	HandleHeldSlotChange(ctx *event.Context, from, to int)
 	// HandleToggleSneak handles when the player starts or stops sneaking.
 	// After is true if the player is sneaking after toggling (changing their sneaking state).
 	HandleToggleSneak(ctx *event.Context, after bool)
@@ -34,6 +43,10 @@ type Handler interface {
 	// message being sent in chat.
 	// The message may be changed by assigning to *message.
 	HandleChat(ctx *event.Context, message *string)
+	// HandleChatMessage handles a message sent in the chat by a player, after it was passed to HandleChat.
+	// ctx.Cancel() may be called to cancel the message being sent in chat.
+	// The text, format, chat and recipients of the message may be changed by modifying the fields of msg.
+	HandleChatMessage(ctx *event.Context, msg *chat.Message)
 	// HandleFoodLoss handles the food bar of a player depleting naturally, for example because the player was
 	// sprinting and jumping. ctx.Cancel() may be called to cancel the food points being lost.
 	HandleFoodLoss(ctx *event.Context, from int, to *int)
@@ -45,16 +58,31 @@ type Handler interface {
 	// damage being dealt to the player.
 	// The damage dealt to the player may be changed by assigning to *damage.
 	HandleHurt(ctx *event.Context, damage *float64, attackImmunity *time.Duration, src world.DamageSource)
+	// HandlePreDeath handles the player being about to die to a particular damage cause. ctx.Cancel() may be
+	// called to prevent the player from dying, in which case the damage that would have killed the player is
+	// not dealt.
+	HandlePreDeath(ctx *event.Context, src world.DamageSource)
 	// HandleDeath handles the player dying to a particular damage cause.
 	HandleDeath(src world.DamageSource, keepInv *bool)
+	// HandleDeathMessage handles the death message broadcast to the server after the player died to a
+	// particular damage cause. The message may be changed by assigning to *message. If it is set to an empty
+	// string, no death message is broadcast. HandleDeathMessage is not called if death messages are disabled
+	// using world.GameRuleShowDeathMessages.
+	HandleDeathMessage(src world.DamageSource, message *string)
 	// HandleRespawn handles the respawning of the player in the world. The spawn position passed may be
 	// changed by assigning to *pos. The world.World in which the Player is respawned may be modifying by assigning to
 	// *w. This world may be the world the Player died in, but it might also point to a different world (the overworld)
 	// if the Player died in the nether or end.
 	HandleRespawn(pos *mgl64.Vec3, w **world.World)
+	// HandleRespawnState handles the state that the player respawns with, such as its health and food. It is
+	// called after HandleRespawn. The state may be changed by modifying the fields of state.
+	HandleRespawnState(state *RespawnState)
 	// HandleSkinChange handles the player changing their skin. ctx.Cancel() may be called to cancel the skin
 	// change.
 	HandleSkinChange(ctx *event.Context, skin *skin.Skin)
+	// HandleEmote handles the player performing the emote with the UUID passed, either by using it in-game or
+	// through a call to Player.Emote. ctx.Cancel() may be called to stop the emote from being shown to viewers.
+	HandleEmote(ctx *event.Context, emote uuid.UUID)
 	// HandleStartBreak handles the player starting to break a block at the position passed. ctx.Cancel() may
 	// be called to stop the player from breaking the block completely.
 	HandleStartBreak(ctx *event.Context, pos cube.Pos)
@@ -104,6 +132,16 @@ type Handler interface {
 	HandleExperienceGain(ctx *event.Context, amount *int)
 	// HandlePunchAir handles the player punching air.
 	HandlePunchAir(ctx *event.Context)
+	// HandleLeftClickAir handles the player left-clicking the air. sinceLast is the time passed since the
+	// player last left-clicked the air, which is very large if the player did not left-click the air before.
+	// ctx.Cancel() may be called to cancel the click, in which case HandlePunchAir is not called and the
+	// player does not punch the air.
+	HandleLeftClickAir(ctx *event.Context, sinceLast time.Duration)
+	// HandleRightClickAir handles the player right-clicking the air while holding an item. sinceLast is the
+	// time passed since the player last right-clicked the air, which is very large if the player did not
+	// right-click the air before. ctx.Cancel() may be called to cancel the click, in which case
+	// HandleItemUse is not called and the item held is not used.
+	HandleRightClickAir(ctx *event.Context, sinceLast time.Duration)
 	// HandleSignEdit handles the player editing a sign. It is called for every keystroke while editing a sign and
 	// has both the old text passed and the text after the edit. This typically only has a change of one character.
 	HandleSignEdit(ctx *event.Context, frontSide bool, oldText, newText string)
@@ -143,15 +181,19 @@ var _ Handler = NopHandler{}
 
 func (NopHandler) HandleItemDrop(*event.Context, world.Entity)                                {}
 func (NopHandler) HandleMove(*event.Context, mgl64.Vec3, float64, float64)                    {}
+func (NopHandler) HandleMovementViolation(*event.Context, MovementViolation)                  {}
 func (NopHandler) HandleJump()                                                                {}
 func (NopHandler) HandleTeleport(*event.Context, mgl64.Vec3)                                  {}
 func (NopHandler) HandleChangeWorld(*world.World, *world.World)                               {}
 func (NopHandler) HandleToggleSprint(*event.Context, bool)                                    {}
 func (NopHandler) HandleToggleSneak(*event.Context, bool)                                     {}
+func (NopHandler) HandleHeldSlotChange(*event.Context, int, int)                              {}
 func (NopHandler) HandleCommandExecution(*event.Context, cmd.Command, []string)               {}
 func (NopHandler) HandleTransfer(*event.Context, *net.UDPAddr)                                {}
 func (NopHandler) HandleChat(*event.Context, *string)                                         {}
+func (NopHandler) HandleChatMessage(*event.Context, *chat.Message)                            {}
 func (NopHandler) HandleSkinChange(*event.Context, *skin.Skin)                                {}
+func (NopHandler) HandleEmote(*event.Context, uuid.UUID)                                      {}
 func (NopHandler) HandleStartBreak(*event.Context, cube.Pos)                                  {}
 func (NopHandler) HandleBlockBreak(*event.Context, cube.Pos, *[]item.Stack, *int)             {}
 func (NopHandler) HandleBlockPlace(*event.Context, cube.Pos, world.Block)                     {}
@@ -167,9 +209,14 @@ func (NopHandler) HandleItemDamage(*event.Context, item.Stack, int)
 func (NopHandler) HandleAttackEntity(*event.Context, world.Entity, *float64, *float64, *bool) {}
 func (NopHandler) HandleExperienceGain(*event.Context, *int)                                  {}
 func (NopHandler) HandlePunchAir(*event.Context)                                              {}
+func (NopHandler) HandleLeftClickAir(*event.Context, time.Duration)                           {}
+func (NopHandler) HandleRightClickAir(*event.Context, time.Duration)                          {}
 func (NopHandler) HandleHurt(*event.Context, *float64, *time.Duration, world.DamageSource)    {}
 func (NopHandler) HandleHeal(*event.Context, *float64, world.HealingSource)                   {}
 func (NopHandler) HandleFoodLoss(*event.Context, int, *int)                                   {}
+func (NopHandler) HandlePreDeath(*event.Context, world.DamageSource)                          {}
 func (NopHandler) HandleDeath(world.DamageSource, *bool)                                      {}
+func (NopHandler) HandleDeathMessage(world.DamageSource, *string)                             {}
 func (NopHandler) HandleRespawn(*mgl64.Vec3, **world.World)                                   {}
+func (NopHandler) HandleRespawnState(*RespawnState)                                           {}
 func (NopHandler) HandleQuit()                                                                {}
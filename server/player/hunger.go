@@ -126,7 +126,7 @@ func (m *hungerManager) desaturate() {
 
 // canQuicklyRegenerate checks if the player can quickly regenerate. The function returns true if Food() returns 20
 // and the player still has saturation left.
-// The rate of regeneration is 1/0.5 seconds.
+// The rate of regeneration is controlled by Regeneration.SaturatedInterval.
 func (m *hungerManager) canQuicklyRegenerate() bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -136,7 +136,7 @@ func (m *hungerManager) canQuicklyRegenerate() bool {
 
 // canRegenerate checks if the player with the amount of food levels in the hunger manager can regenerate.
 // The function returns true if Food() returns either 18-20.
-// The rate of regeneration is 1/4 seconds.
+// The rate of regeneration is controlled by Regeneration.Interval.
 func (m *hungerManager) canRegenerate() bool {
 	return m.Food() >= 18
 }
@@ -151,6 +151,34 @@ func (m *hungerManager) starving() bool {
 	return m.Food() == 0
 }
 
+// Regeneration holds the settings of the natural regeneration of a player, which heals the player if its food
+// bar is full enough. Natural regeneration may be disabled entirely using world.GameRuleNaturalRegeneration.
+// A Regeneration may be set for a player using Player.SetRegeneration.
+type Regeneration struct {
+	// SaturatedInterval is the number of ticks between regenerating health while the food bar of the player is
+	// full and the player has saturation left, or while the difficulty is peaceful. If 0 or lower, the player
+	// never regenerates health this way.
+	SaturatedInterval int
+	// Interval is the number of ticks between regenerating health while the food level of the player is 18 or
+	// higher. If 0 or lower, the player never regenerates health this way.
+	Interval int
+	// Amount is the amount of health regenerated every time.
+	Amount float64
+	// Exhaustion is the exhaustion added to the player every time health is regenerated at the rate of Interval.
+	Exhaustion float64
+}
+
+// DefaultRegeneration returns the default Regeneration of players, which matches the regeneration of Bedrock
+// Edition.
+func DefaultRegeneration() Regeneration {
+	return Regeneration{SaturatedInterval: 20, Interval: 80, Amount: 1, Exhaustion: 6}
+}
+
+// due checks if health is regenerated at the interval passed in the tick passed.
+func (r Regeneration) due(interval int, tick int) bool {
+	return interval > 0 && tick%interval == 0
+}
+
 // StarvationDamageSource is the world.DamageSource passed when a player is
 // dealt damage from an empty food bar.
 type StarvationDamageSource struct{}
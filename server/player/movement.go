@@ -0,0 +1,333 @@
+package player
+
+import (
+	"math"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// MovementValidation holds the settings of the server-side validation of the movement of players. Movement
+// sent by the client of a player is checked against the physics of the server, taking into account effects,
+// knockback, elytra gliding and the blocks around the player. Movement failing one of the checks is passed to
+// Handler.HandleMovementViolation, so that servers may implement their own punishments, and is corrected if
+// Correct is true. A MovementValidation may be set for a player using Player.SetMovementValidation. By
+// default, movement is not validated.
+type MovementValidation struct {
+	// Speed specifies if the horizontal distance that players move is checked.
+	Speed bool
+	// Fly specifies if players are checked for moving higher than they could have jumped, or for hovering in
+	// the air.
+	Fly bool
+	// Phase specifies if players are checked for moving into or through solid blocks.
+	Phase bool
+	// Step specifies if players are checked for stepping up higher than a slab without jumping.
+	Step bool
+	// Correct specifies if movement failing one of the checks is corrected by moving the player back to the
+	// position it had before moving. If false, violations are only passed to the Handler of the player.
+	Correct bool
+	// Tolerance is the distance in blocks by which movement may exceed the limits of the checks before
+	// failing them, to account for small differences between the physics of the client and the server.
+	Tolerance float64
+}
+
+// DefaultMovementValidation returns a MovementValidation with all checks enabled that corrects movement
+// failing them.
+func DefaultMovementValidation() MovementValidation {
+	return MovementValidation{Speed: true, Fly: true, Phase: true, Step: true, Correct: true, Tolerance: 0.1}
+}
+
+// enabled checks if any of the checks of the MovementValidation are enabled.
+func (v MovementValidation) enabled() bool {
+	return v.Speed || v.Fly || v.Phase || v.Step
+}
+
+// MovementCheck is one of the checks of a MovementValidation.
+type MovementCheck int
+
+const (
+	// MovementCheckSpeed is failed by players moving further horizontally than they could have.
+	MovementCheckSpeed MovementCheck = iota
+	// MovementCheckFly is failed by players moving higher than they could have jumped, or hovering in the air.
+	MovementCheckFly
+	// MovementCheckPhase is failed by players moving into or through solid blocks.
+	MovementCheckPhase
+	// MovementCheckStep is failed by players stepping up higher than they could have without jumping.
+	MovementCheckStep
+)
+
+// String returns the name of the MovementCheck.
+func (c MovementCheck) String() string {
+	switch c {
+	case MovementCheckSpeed:
+		return "speed"
+	case MovementCheckFly:
+		return "fly"
+	case MovementCheckPhase:
+		return "phase"
+	case MovementCheckStep:
+		return "step"
+	}
+	panic("unknown movement check")
+}
+
+// MovementViolation is a movement of a player that failed one of the checks of its MovementValidation.
+type MovementViolation struct {
+	// Check is the check that the movement failed.
+	Check MovementCheck
+	// From and To are the positions of the player before and after the movement.
+	From, To mgl64.Vec3
+	// Excess is the distance in blocks by which the movement exceeded the limit of the check. For
+	// MovementCheckPhase, it is the distance moved.
+	Excess float64
+}
+
+// movementState holds the state of the movement of a player between movements, used to validate them.
+type movementState struct {
+	mu sync.Mutex
+	// onGround specifies if the player was on the ground after its last movement.
+	onGround bool
+	// horizontal is the horizontal distance that the player moved in its last movement, or more if it was
+	// knocked back since.
+	horizontal float64
+	// friction is the friction applied to the horizontal movement of the player after its last movement.
+	friction float64
+	// velocityY is the vertical velocity given to the player by the server that has not yet run out.
+	velocityY float64
+	// maxY is the highest Y position that the player may reach before landing again.
+	maxY float64
+	// airTicks is the number of movements since the player was last on the ground.
+	airTicks int
+}
+
+// SetMovementValidation changes the MovementValidation of the player, which controls which checks the
+// movement of the player is validated with.
+func (p *Player) SetMovementValidation(v MovementValidation) {
+	p.movementValidation.Store(v)
+	p.resetMovement(p.Position())
+}
+
+// MovementValidation returns the MovementValidation of the player, as set using SetMovementValidation.
+func (p *Player) MovementValidation() MovementValidation {
+	return p.movementValidation.Load()
+}
+
+// resetMovement resets the state used to validate the movement of the player, for example after it was
+// teleported to the position passed.
+func (p *Player) resetMovement(pos mgl64.Vec3) {
+	s := &p.movement
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Assume the player is on the ground of a regular block, which allows for the most movement without a
+	// velocity.
+	s.onGround, s.horizontal, s.friction, s.velocityY, s.airTicks = true, 0, 0.6*0.91, 0, 0
+	s.maxY = pos[1] + jumpHeight(p.jumpVelocity())
+}
+
+// addMovementVelocity allows the player to move further as a result of the velocity passed being sent to it.
+func (p *Player) addMovementVelocity(vel mgl64.Vec3) {
+	s := &p.movement
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.horizontal = max(s.horizontal, math.Hypot(vel[0], vel[2]))
+	if vel[1] > 0 {
+		s.velocityY = max(s.velocityY, vel[1])
+		s.maxY = max(s.maxY, p.Position()[1]+jumpHeight(vel[1]+p.jumpVelocity()))
+	}
+}
+
+// validateMovement validates the movement of the player from one position to another against its
+// MovementValidation. If the movement fails one of the checks, the MovementViolation is returned together
+// with false.
+func (p *Player) validateMovement(w *world.World, from, to mgl64.Vec3) (MovementViolation, bool) {
+	conf := p.MovementValidation()
+	if !conf.enabled() || p.session() == session.Nop {
+		// Players without a session are moved by the server itself, so their movement need not be validated.
+		return MovementViolation{}, true
+	}
+	s := &p.movement
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		delta       = to.Sub(from)
+		horizontal  = math.Hypot(delta[0], delta[2])
+		_, riding   = p.Riding()
+		gm          = p.GameMode()
+		jump        = p.jumpVelocity()
+		box         = p.Type().BBox(p)
+		onGround    = p.onGroundAt(w, to)
+		free        = riding || gm.AllowsFlying() || p.Flying() || p.Gliding() || p.movementExempt(w, from, to)
+		_, levitate = p.Effect(effect.Levitation{})
+		v           = MovementViolation{From: from, To: to}
+		violated    bool
+	)
+
+	speedLimit := s.horizontal * s.friction
+	if s.onGround {
+		speedLimit += p.Speed() * 0.16277136 / (s.friction * s.friction * s.friction)
+		if delta[1] > 0 && p.Sprinting() {
+			// Sprint jumping boosts the horizontal velocity of players.
+			speedLimit += 0.2
+		}
+	} else if p.Sprinting() {
+		speedLimit += 0.026
+	} else {
+		speedLimit += 0.02
+	}
+	stepLimit := max(0.6, jump, s.velocityY)
+
+	switch {
+	case conf.Phase && gm.HasCollision() && !riding && p.phased(w, box, from, to):
+		v.Check, v.Excess, violated = MovementCheckPhase, delta.Len(), true
+	case conf.Speed && !free && horizontal > speedLimit+conf.Tolerance:
+		v.Check, v.Excess, violated = MovementCheckSpeed, horizontal-speedLimit, true
+	case conf.Step && !free && s.onGround && delta[1] > stepLimit+conf.Tolerance:
+		v.Check, v.Excess, violated = MovementCheckStep, delta[1]-stepLimit, true
+	case conf.Fly && !free && !levitate && !onGround && to[1] > s.maxY+conf.Tolerance:
+		v.Check, v.Excess, violated = MovementCheckFly, to[1]-s.maxY, true
+	case conf.Fly && !free && !levitate && !onGround && s.airTicks > 40 && delta[1] > -0.01:
+		// The player has been in the air for two seconds without falling, so it is hovering.
+		v.Check, v.Excess, violated = MovementCheckFly, delta[1]+0.01, true
+	}
+
+	s.horizontal = min(horizontal, speedLimit)
+	s.velocityY = max(0, (s.velocityY-0.08)*0.98)
+	s.friction, s.onGround = 0.91, onGround
+	if onGround {
+		s.friction *= blockFriction(w, to)
+	}
+	switch {
+	case free || onGround || levitate:
+		s.airTicks = 0
+		height := to[1] + jumpHeight(jump)
+		if _, ok := w.Block(cube.PosFromVec3(to).Side(cube.FaceDown)).(block.Slime); ok {
+			// Slime blocks bounce players back up to the height they fell from.
+			height = max(height, s.maxY)
+		}
+		s.maxY = height
+	default:
+		s.airTicks++
+	}
+	return v, !violated
+}
+
+// jumpVelocity returns the initial vertical velocity of a jump of the player, taking into account the jump
+// boost effect.
+func (p *Player) jumpVelocity() float64 {
+	if e, ok := p.Effect(effect.JumpBoost{}); ok {
+		return 0.42 + float64(e.Level())*0.1
+	}
+	return 0.42
+}
+
+// jumpHeight returns the height that a player reaches when moving up with the initial vertical velocity
+// passed.
+func jumpHeight(vel float64) (height float64) {
+	for vel > 0 {
+		height += vel
+		vel = (vel - 0.08) * 0.98
+	}
+	return height
+}
+
+// movementExempt checks if the player moved through liquids or climbable blocks, in which the movement of
+// players is not validated other than for phasing.
+func (p *Player) movementExempt(w *world.World, from, to mgl64.Vec3) bool {
+	for _, pos := range []mgl64.Vec3{from, to} {
+		for _, y := range []float64{0, p.EyeHeight()} {
+			bp := cube.PosFromVec3(pos.Add(mgl64.Vec3{0, y}))
+			if _, ok := w.Liquid(bp); ok {
+				return true
+			}
+			switch w.Block(bp).(type) {
+			case block.Ladder, block.Vines:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// phased checks if the player, with a BBox passed, moved into or through a solid block when moving from one
+// position to another. Blocks that the player was already inside of before moving are ignored, so that
+// players are not stuck when a block is placed inside them.
+func (p *Player) phased(w *world.World, box cube.BBox, from, to mgl64.Vec3) bool {
+	// Shrink the box slightly to account for inaccuracies in positions, and raise its bottom for points along
+	// the path of the movement, so that stepping up on stairs and slabs is not considered phasing.
+	const epsilon = 0.05
+	box = box.Grow(-epsilon)
+	min, max := box.Min(), box.Max()
+	raised := cube.Box(min[0], min[1]+0.6, min[2], max[0], max[1], max[2])
+
+	start := box.Translate(from)
+	blocks := blockBBoxes(w, start.Extend(to.Sub(from)).Grow(1))
+	solid := make([]cube.BBox, 0, len(blocks))
+	for _, b := range blocks {
+		if !b.IntersectsWith(start) {
+			solid = append(solid, b)
+		}
+	}
+
+	delta := to.Sub(from)
+	steps := int(math.Ceil(delta.Len() / 0.25))
+	for i := 1; i <= steps; i++ {
+		check := raised
+		if i == steps {
+			check = box
+		}
+		check = check.Translate(from.Add(delta.Mul(float64(i) / float64(steps))))
+		for _, b := range solid {
+			if b.IntersectsWith(check) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// onGroundAt checks if the player would be considered to be on the ground at the position passed.
+func (p *Player) onGroundAt(w *world.World, pos mgl64.Vec3) bool {
+	box := p.Type().BBox(p).Translate(pos)
+	for _, b := range blockBBoxes(w, box.Grow(1)) {
+		if b.GrowVec3(mgl64.Vec3{0, 0.05}).IntersectsWith(box) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockFriction returns the friction of the block below the position passed.
+func blockFriction(w *world.World, pos mgl64.Vec3) float64 {
+	if f, ok := w.Block(cube.PosFromVec3(pos.Sub(mgl64.Vec3{0, 0.5}))).(block.Frictional); ok {
+		return f.Friction()
+	}
+	return 0.6
+}
+
+// blockBBoxes returns the collision boxes of all blocks within the BBox passed, translated to the positions
+// of the blocks.
+func blockBBoxes(w *world.World, box cube.BBox) []cube.BBox {
+	min, max := box.Min(), box.Max()
+	minX, minY, minZ := int(math.Floor(min[0])), int(math.Floor(min[1])), int(math.Floor(min[2]))
+	maxX, maxY, maxZ := int(math.Ceil(max[0])), int(math.Ceil(max[1])), int(math.Ceil(max[2]))
+
+	// A prediction of one BBox per block, plus an additional 2, in case
+	blocks := make([]cube.BBox, 0, (maxX-minX)*(maxY-minY)*(maxZ-minZ)+2)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			for z := minZ; z <= maxZ; z++ {
+				pos := cube.Pos{x, y, z}
+				for _, b := range w.Block(pos).Model().BBox(pos, w) {
+					blocks = append(blocks, b.Translate(pos.Vec3()))
+				}
+			}
+		}
+	}
+	return blocks
+}
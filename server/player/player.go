@@ -2,6 +2,7 @@ package player
 
 import (
 	"fmt"
+	"image/color"
 	"math"
 	"math/rand"
 	"net"
@@ -15,24 +16,33 @@ import (
 	"github.com/df-mc/dragonfly/server/block/model"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/enchantment"
 	"github.com/df-mc/dragonfly/server/item/inventory"
+	"github.com/df-mc/dragonfly/server/item/loot"
+	"github.com/df-mc/dragonfly/server/lang"
+	"github.com/df-mc/dragonfly/server/permission"
 	"github.com/df-mc/dragonfly/server/player/bossbar"
+	"github.com/df-mc/dragonfly/server/player/camera"
 	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/player/dialogue"
 	"github.com/df-mc/dragonfly/server/player/form"
 	"github.com/df-mc/dragonfly/server/player/scoreboard"
 	"github.com/df-mc/dragonfly/server/player/skin"
 	"github.com/df-mc/dragonfly/server/player/title"
+	"github.com/df-mc/dragonfly/server/player/toast"
+	"github.com/df-mc/dragonfly/server/player/transfer"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/text"
 	"golang.org/x/text/language"
 )
 
@@ -55,8 +65,14 @@ type Player struct {
 	// s holds the session of the player. This field should not be used directly, but instead,
 	// Player.session() should be called.
 	s atomic.Value[*session.Session]
-	// h holds the current Handler of the player. It may be changed at any time by calling the Handle method.
-	h atomic.Value[Handler]
+	// h passes the events of the player to all Handlers subscribed to it using the Handle, Subscribe and
+	// SubscribeAsync methods.
+	h handlerBus
+	// handleMu guards handler, the Handler last passed to the Handle method, and unhandle, which
+	// unsubscribes it from h.
+	handleMu sync.Mutex
+	handler  Handler
+	unhandle func()
 
 	inv, offHand, enderChest *inventory.Inventory
 	armour                   *inventory.Armour
@@ -68,7 +84,10 @@ type Player struct {
 
 	glideTicks   atomic.Int64
 	fireTicks    atomic.Int64
+	freezeTicks  atomic.Int64
+	inPowderSnow atomic.Bool
 	fallDistance atomic.Float64
+	stepDistance atomic.Float64
 
 	breathing         bool
 	airSupplyTicks    atomic.Int64
@@ -79,14 +98,27 @@ type Player struct {
 	// lastTickedWorld holds the world that the player was in, in the last tick.
 	lastTickedWorld *world.World
 
-	speed      atomic.Float64
 	health     *entity.HealthManager
+	attributes *attribute.Manager
 	experience *entity.ExperienceManager
 	effects    *entity.EffectManager
 
 	lastXPPickup  atomic.Value[time.Time]
 	immunityTicks atomic.Int64
 
+	regeneration                      atomic.Value[Regeneration]
+	combat                            atomic.Value[Combat]
+	lastAttack                        atomic.Value[time.Time]
+	knockBackDealt, knockBackReceived atomic.Float64
+
+	keepInventory atomic.Bool
+	vanished      atomic.Bool
+
+	lastPunchAir, lastRightClickAir atomic.Value[time.Time]
+
+	movementValidation atomic.Value[MovementValidation]
+	movement           movementState
+
 	deathMu        sync.Mutex
 	deathPos       *mgl64.Vec3
 	deathDimension world.Dimension
@@ -101,9 +133,24 @@ type Player struct {
 	breakingPos       atomic.Value[cube.Pos]
 	lastBreakDuration time.Duration
 
+	// usedOn holds the block that the player is currently using its held item on, if any.
+	usedOn atomic.Value[world.Block]
+
 	breakParticleCounter atomic.Uint32
 
+	ridden atomic.Value[entity.Rideable]
+	// spectating holds the entity that the player is spectating in spectator mode, if any.
+	spectating atomic.Value[world.Entity]
+
 	hunger *hungerManager
+
+	perms *permission.Holder
+
+	chatChannel atomic.Value[*chat.Chat]
+	mutedUntil  atomic.Value[time.Time]
+
+	tagMu sync.Mutex
+	tags  map[string]struct{}
 }
 
 // New returns a new initialised player. A random UUID is generated for the player, so that it may be
@@ -126,10 +173,9 @@ func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 		experience:        entity.NewExperienceManager(),
 		effects:           entity.NewEffectManager(),
 		gameMode:          *atomic.NewValue[world.GameMode](world.GameModeSurvival),
-		h:                 *atomic.NewValue[Handler](NopHandler{}),
 		name:              name,
 		skin:              *atomic.NewValue(skin),
-		speed:             *atomic.NewFloat64(0.1),
+		attributes:        attribute.NewManager(),
 		nameTag:           *atomic.NewValue(name),
 		heldSlot:          atomic.NewUint32(0),
 		locale:            language.BritishEnglish,
@@ -141,6 +187,13 @@ func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 		pos:               *atomic.NewValue(pos),
 		cooldowns:         make(map[string]time.Time),
 		mc:                &entity.MovementComputer{Gravity: 0.08, Drag: 0.02, DragBeforeGravity: true},
+		perms:             permission.NewHolder(),
+		chatChannel:       *atomic.NewValue(chat.Global),
+		tags:              map[string]struct{}{},
+		regeneration:      *atomic.NewValue(DefaultRegeneration()),
+		combat:            *atomic.NewValue(DefaultCombat()),
+		knockBackDealt:    *atomic.NewFloat64(1),
+		knockBackReceived: *atomic.NewFloat64(1),
 	}
 	return p
 }
@@ -161,6 +214,56 @@ func NewWithSession(name, xuid string, uuid uuid.UUID, skin skin.Skin, s *sessio
 	return p
 }
 
+// SetCombat changes the Combat settings of the player, which control the damage and knockback of attacks
+// made by the player and the duration that the player is immune to attacks after being hurt.
+func (p *Player) SetCombat(c Combat) {
+	p.combat.Store(c)
+}
+
+// Combat returns the Combat settings of the player, as set using SetCombat. By default, the result of
+// DefaultCombat is returned.
+func (p *Player) Combat() Combat {
+	return p.combat.Load()
+}
+
+// SetHitDelay changes the duration that the player is immune to attacks after being hurt. It is equivalent to
+// changing the HitDelay of the Combat of the player, so calling SetCombat afterwards overwrites it.
+func (p *Player) SetHitDelay(d time.Duration) {
+	c := p.Combat()
+	c.HitDelay = d
+	p.SetCombat(c)
+}
+
+// HitDelay returns the duration that the player is immune to attacks after being hurt.
+func (p *Player) HitDelay() time.Duration {
+	return p.Combat().HitDelay
+}
+
+// SetKnockBackDealt sets the multiplier of the knockback that attacks of the player apply to the entities
+// attacked. By default, the multiplier is 1. A multiplier of 0 prevents the player from knocking back
+// entities.
+func (p *Player) SetKnockBackDealt(m float64) {
+	p.knockBackDealt.Store(m)
+}
+
+// KnockBackDealt returns the multiplier of the knockback that attacks of the player apply, as set using
+// SetKnockBackDealt.
+func (p *Player) KnockBackDealt() float64 {
+	return p.knockBackDealt.Load()
+}
+
+// SetKnockBackReceived sets the multiplier of the knockback that the player receives from any source. By
+// default, the multiplier is 1. A multiplier of 0 makes the player immune to knockback.
+func (p *Player) SetKnockBackReceived(m float64) {
+	p.knockBackReceived.Store(m)
+}
+
+// KnockBackReceived returns the multiplier of the knockback that the player receives, as set using
+// SetKnockBackReceived.
+func (p *Player) KnockBackReceived() float64 {
+	return p.knockBackReceived.Load()
+}
+
 // Type returns the world.EntityType for the Player.
 func (p *Player) Type() world.EntityType {
 	return Type{}
@@ -233,13 +336,15 @@ func (p *Player) Skin() skin.Skin {
 }
 
 // SetSkin changes the skin of the player. This skin will be visible to other players that the player
-// is shown to.
+// is shown to, and is sent to all players currently viewing the player immediately. SetSkin may be used to
+// disguise players or apply cosmetics, including custom geometry, capes and animations. Skins created at
+// runtime should be checked using skin.Skin.Validate before being set.
 func (p *Player) SetSkin(skin skin.Skin) {
 	if p.Dead() {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleSkinChange(ctx, &skin); ctx.Cancelled() {
+	if p.h.HandleSkinChange(ctx, &skin); ctx.Cancelled() {
 		p.session().ViewSkin(p)
 		return
 	}
@@ -249,19 +354,119 @@ func (p *Player) SetSkin(skin skin.Skin) {
 	}
 }
 
+// Emote makes the player perform the emote with the UUID passed. The emote is shown to all players viewing
+// the player, including the player itself. Emote may be used to make NPCs perform emotes. Nothing happens if
+// the player is dead or if the emote is cancelled by the Handler of the player.
+func (p *Player) Emote(emote uuid.UUID) {
+	if p.Dead() {
+		return
+	}
+	ctx := event.C()
+	if p.h.HandleEmote(ctx, emote); ctx.Cancelled() {
+		return
+	}
+	for _, v := range p.viewers() {
+		v.ViewEmote(p, emote)
+	}
+}
+
+// Permissions returns the permission.Holder of the player. Nodes may be granted to or denied from the holder, and
+// the holder may be added to groups, to change the permissions of the player at runtime.
+func (p *Player) Permissions() *permission.Holder {
+	return p.perms
+}
+
+// HasPermission checks if the player has the permission node passed, either through a node set on the player
+// directly or through one of the permission groups of the player. Commands with a permission node may only be
+// run by players that have that permission.
+func (p *Player) HasPermission(node string) bool {
+	return p.perms.Has(node)
+}
+
 // Locale returns the language and locale of the Player, as selected in the Player's settings.
 func (p *Player) Locale() language.Tag {
 	return p.locale
 }
 
+// AddTag adds a tag to the player. Tags may be used to group players, for example to select them in commands
+// using a target selector such as @a[tag=red].
+func (p *Player) AddTag(tag string) {
+	p.tagMu.Lock()
+	defer p.tagMu.Unlock()
+	p.tags[tag] = struct{}{}
+}
+
+// RemoveTag removes a tag from the player. Nothing happens if the player does not have the tag.
+func (p *Player) RemoveTag(tag string) {
+	p.tagMu.Lock()
+	defer p.tagMu.Unlock()
+	delete(p.tags, tag)
+}
+
+// HasTag checks if the player has the tag passed.
+func (p *Player) HasTag(tag string) bool {
+	p.tagMu.Lock()
+	defer p.tagMu.Unlock()
+	_, ok := p.tags[tag]
+	return ok
+}
+
+// Tags returns all tags of the player.
+func (p *Player) Tags() []string {
+	p.tagMu.Lock()
+	defer p.tagMu.Unlock()
+	tags := make([]string, 0, len(p.tags))
+	for tag := range p.tags {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// Translate translates the message key passed to the language of the player using lang.Default. If arguments
+// are passed, the message is formatted with them according to the rules of fmt.Sprintf.
+func (p *Player) Translate(key string, a ...any) string {
+	return lang.Translate(p.locale, key, a...)
+}
+
+// TranslateN translates the plural form of the message key passed for the count n to the language of the
+// player using lang.Default.
+func (p *Player) TranslateN(key string, n int, a ...any) string {
+	return lang.TranslateN(p.locale, key, n, a...)
+}
+
 // Handle changes the current Handler of the player. As a result, events called by the player will call
-// handlers of the Handler passed.
-// Handle sets the player's Handler to NopHandler if nil is passed.
+// handlers of the Handler passed. The Handler is subscribed with event.PriorityNormal and replaces the Handler
+// previously passed to Handle, but not Handlers subscribed using Subscribe or SubscribeAsync.
+// Handle removes the player's Handler if nil is passed.
 func (p *Player) Handle(h Handler) {
-	if h == nil {
-		h = NopHandler{}
+	p.handleMu.Lock()
+	defer p.handleMu.Unlock()
+	if p.unhandle != nil {
+		p.unhandle()
+		p.unhandle = nil
+	}
+	p.handler = h
+	if _, nop := h.(NopHandler); h != nil && !nop {
+		p.unhandle = p.h.Subscribe(h, event.PriorityNormal)
 	}
-	p.h.Store(h)
+}
+
+// Subscribe subscribes a Handler to the events of the player with the event.Priority passed. Handlers with a
+// lower priority are called first, so that those with a higher priority have the final say over whether an
+// event is cancelled. Handlers with event.PriorityMonitor are called last and cannot change the outcome of
+// events. The function returned unsubscribes the Handler again. Handlers subscribed after the player quit are
+// not passed any events.
+func (p *Player) Subscribe(h Handler, priority event.Priority) (unsubscribe func()) {
+	return p.h.Subscribe(h, priority)
+}
+
+// SubscribeAsync subscribes a Handler to the events of the player that is called on a separate goroutine once
+// the outcome of an event is final. Events are passed to the Handler in order, but the Handler cannot change
+// their outcome and is passed copies of values that events pass by pointer. SubscribeAsync is useful for
+// Handlers that perform slow operations, such as writing to a database. The function returned unsubscribes the
+// Handler again.
+func (p *Player) SubscribeAsync(h Handler) (unsubscribe func()) {
+	return p.h.SubscribeAsync(h)
 }
 
 // Message sends a formatted message to the player. The message is formatted following the rules of
@@ -301,6 +506,15 @@ func (p *Player) SendToast(title, message string) {
 	p.session().SendToast(title, message)
 }
 
+// ShowToast shows a toast.Toast to the player. Like SendToast, the toast is shown at the top of the screen. If the
+// toast has a sound set, the sound is played to the player alongside it.
+func (p *Player) ShowToast(t toast.Toast) {
+	p.session().SendToast(t.Title(), t.Message())
+	if s := t.Sound(); s != nil {
+		p.PlaySound(s)
+	}
+}
+
 // ResetFallDistance resets the player's fall distance.
 func (p *Player) ResetFallDistance() {
 	p.fallDistance.Store(0)
@@ -341,11 +555,32 @@ func (p *Player) RemoveScoreboard() {
 	p.session().RemoveScoreboard()
 }
 
+// ShowObjective shows a scoreboard.Objective to the player in the scoreboard.DisplaySlot passed, either under
+// the name tags of players or in the player list. The scores of the objective are obtained from its
+// scoreboard.ScoreProvider, so a different provider may be bound for every player. The objective previously
+// shown in the slot is replaced.
+func (p *Player) ShowObjective(slot scoreboard.DisplaySlot, o scoreboard.Objective) {
+	p.session().SendObjective(slot, o)
+}
+
+// HideObjective hides the scoreboard.Objective shown to the player in the scoreboard.DisplaySlot passed, if
+// any.
+func (p *Player) HideObjective(slot scoreboard.DisplaySlot) {
+	p.session().RemoveObjective(slot)
+}
+
+// UpdateObjectives obtains the scores of the scoreboard.Objectives shown to the player from their
+// scoreboard.ScoreProvider again, for example after a player got a kill. Only the scores that changed are sent
+// to the player. Scores of players that join are sent automatically.
+func (p *Player) UpdateObjectives() {
+	p.session().UpdateObjectives()
+}
+
 // SendBossBar sends a boss bar to the player, so that it will be shown indefinitely at the top of the
 // player's screen.
 // The boss bar may be removed by calling Player.RemoveBossBar().
 func (p *Player) SendBossBar(bar bossbar.BossBar) {
-	p.session().SendBossBar(bar.Text(), bar.Colour().Uint8(), bar.HealthPercentage())
+	p.session().SendBossBar(bar.Text(), bar.Colour().Uint8(), bar.Style().Uint8(), bar.HealthPercentage())
 }
 
 // RemoveBossBar removes any boss bar currently active on the player's screen. If no boss bar is currently
@@ -354,15 +589,91 @@ func (p *Player) RemoveBossBar() {
 	p.session().RemoveBossBar()
 }
 
-// Chat writes a message in the global chat (chat.Global). The message is prefixed with the name of the
-// player and is formatted following the rules of fmt.Sprintln.
+// ShowBossBar shows a boss bar with the ID passed to the player. Unlike SendBossBar, multiple boss bars with
+// different IDs may be shown at the same time, stacked at the top of the player's screen. Calling ShowBossBar
+// with the ID of a boss bar that is already shown updates that boss bar, which may be used to update the text
+// or health of a boss bar efficiently, for example for timers.
+// The boss bar may be removed by calling Player.HideBossBar() with the same ID.
+func (p *Player) ShowBossBar(id string, bar bossbar.BossBar) {
+	p.session().ShowBossBar(id, bar.Text(), bar.Colour().Uint8(), bar.Style().Uint8(), bar.HealthPercentage())
+}
+
+// HideBossBar hides the boss bar with the ID passed, previously shown using Player.ShowBossBar(). If no boss
+// bar with the ID is shown, nothing happens.
+func (p *Player) HideBossBar(id string) {
+	p.session().HideBossBar(id)
+}
+
+// SetCamera takes control of the camera of the player using the camera.Set instruction passed. The camera
+// stays in place until another instruction is sent or until ResetCamera is called. Custom camera presets must
+// be registered using camera.Register before the player joined, or the instruction is ignored.
+func (p *Player) SetCamera(set camera.Set) {
+	p.session().SendCameraSet(set)
+}
+
+// FadeCamera fades the screen of the player to a colour and back using the camera.Fade instruction passed.
+func (p *Player) FadeCamera(f camera.Fade) {
+	p.session().SendCameraFade(f)
+}
+
+// ResetCamera clears all camera instructions sent to the player, returning the camera to the player.
+func (p *Player) ResetCamera() {
+	p.session().ClearCamera()
+}
+
+// Chat writes a message in the chat channel of the player, which is chat.Global by default. The message is
+// formatted following the rules of fmt.Sprintln and delivered using chat.DefaultFormat, which prefixes it with
+// the name of the player. The Handler of the player may change the text of the message in HandleChat, and its
+// format, channel and recipients in HandleChatMessage. Nothing happens if the player is muted.
 func (p *Player) Chat(msg ...any) {
+	if p.Muted() {
+		p.Message(text.Colourf("<red>You cannot chat while you are muted.</red>"))
+		return
+	}
 	message := format(msg)
 	ctx := event.C()
-	if p.Handler().HandleChat(ctx, &message); ctx.Cancelled() {
+	if p.h.HandleChat(ctx, &message); ctx.Cancelled() {
+		return
+	}
+	c := p.ChatChannel()
+	m := &chat.Message{Text: message, Format: chat.DefaultFormat, Chat: c, Recipients: c.Recipients(p)}
+	if p.h.HandleChatMessage(ctx, m); ctx.Cancelled() {
+		return
+	}
+	m.Deliver(p.name)
+}
+
+// SetChatChannel changes the chat channel that the player writes messages in when chatting, such as
+// chat.Global, chat.Local or chat.Staff. SetChatChannel does not change the chats that the player receives
+// messages from: Use chat.Chat.Subscribe for that.
+func (p *Player) SetChatChannel(c *chat.Chat) {
+	p.chatChannel.Store(c)
+}
+
+// ChatChannel returns the chat channel that the player writes messages in when chatting. By default, this is
+// chat.Global.
+func (p *Player) ChatChannel() *chat.Chat {
+	return p.chatChannel.Load()
+}
+
+// Mute mutes the player for the duration passed, preventing it from chatting. Commands may still be executed
+// while muted. A negative duration mutes the player until Unmute is called.
+func (p *Player) Mute(d time.Duration) {
+	if d < 0 {
+		p.mutedUntil.Store(time.Unix(1<<62, 0))
 		return
 	}
-	_, _ = fmt.Fprintf(chat.Global, "<%v> %v\n", p.name, message)
+	p.mutedUntil.Store(time.Now().Add(d))
+}
+
+// Unmute unmutes the player, allowing it to chat again.
+func (p *Player) Unmute() {
+	p.mutedUntil.Store(time.Time{})
+}
+
+// Muted checks if the player is currently muted.
+func (p *Player) Muted() bool {
+	return time.Now().Before(p.mutedUntil.Load())
 }
 
 // ExecuteCommand executes a command passed as the player. If the command could not be found, or if the usage
@@ -381,7 +692,7 @@ func (p *Player) ExecuteCommand(commandLine string) {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleCommandExecution(ctx, command, args[1:]); ctx.Cancelled() {
+	if p.h.HandleCommandExecution(ctx, command, args[1:]); ctx.Cancelled() {
 		return
 	}
 	command.Execute(strings.Join(args[1:], " "), p)
@@ -390,17 +701,32 @@ func (p *Player) ExecuteCommand(commandLine string) {
 // Transfer transfers the player to a server at the address passed. If the address could not be resolved, an
 // error is returned. If it is returned, the player is closed and transferred to the server.
 func (p *Player) Transfer(address string) error {
+	return p.TransferWithMetadata(address, nil)
+}
+
+// TransferWithMetadata transfers the player to a server at the address passed, like Transfer, and attaches the
+// transfer.Metadata passed to the transfer. The Metadata is stored in the transfer.Store of the server before
+// the player is transferred, so that the server the player joins may obtain it using Player.TransferMetadata
+// if it shares the same transfer.Store. An error is returned if the address could not be resolved or if the
+// Metadata could not be stored, in which case the player is not transferred.
+func (p *Player) TransferWithMetadata(address string, m transfer.Metadata) error {
 	addr, err := net.ResolveUDPAddr("udp", address)
 	if err != nil {
 		return err
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleTransfer(ctx, addr); ctx.Cancelled() {
+	if p.h.HandleTransfer(ctx, addr); ctx.Cancelled() {
 		return nil
 	}
-	p.session().Transfer(addr.IP, addr.Port)
-	return nil
+	return p.session().Transfer(addr.IP, addr.Port, m)
+}
+
+// TransferMetadata returns the transfer.Metadata attached to the transfer that brought the player to the
+// server, as passed to Player.TransferWithMetadata on the server that the player was transferred from. If the
+// player was not transferred or no Metadata was attached, nil is returned.
+func (p *Player) TransferMetadata() transfer.Metadata {
+	return p.session().TransferMetadata()
 }
 
 // SendCommandOutput sends the output of a command to the player.
@@ -417,6 +743,18 @@ func (p *Player) SendForm(f form.Form) {
 	p.session().SendForm(f)
 }
 
+// SendDialogue shows a dialogue.Dialogue to the player, using the entity passed as the NPC showing the
+// dialogue. The entity should be an entity.NPC visible to the player, or the dialogue is not shown. The page
+// of the dialogue that the player is on is kept track of for the player until the dialogue is closed.
+func (p *Player) SendDialogue(d dialogue.Dialogue, e world.Entity) {
+	p.session().SendDialogue(d, e)
+}
+
+// CloseDialogue closes the dialogue.Dialogue currently shown to the player, if any.
+func (p *Player) CloseDialogue() {
+	p.session().CloseDialogue()
+}
+
 // ShowCoordinates enables the vanilla coordinates for the player.
 func (p *Player) ShowCoordinates() {
 	p.session().EnableCoordinates(true)
@@ -461,17 +799,67 @@ func (p *Player) ScoreTag() string {
 	return p.scoreTag.Load()
 }
 
-// SetSpeed sets the speed of the player. The value passed is the blocks/tick speed that the player will then
-// obtain.
+// SetSpeed sets the base speed of the player. The value passed is the blocks/tick speed that the player will
+// then obtain before attribute modifiers, such as those of sprinting and the speed effect, are applied.
 func (p *Player) SetSpeed(speed float64) {
-	p.speed.Store(speed)
-	p.session().SendSpeed(speed)
+	p.updateAttribute(attribute.MovementSpeed, p.attributes.SetBase(attribute.MovementSpeed, speed))
 }
 
 // Speed returns the speed of the player, returning a value that indicates the blocks/tick speed. The default
 // speed of a player is 0.1.
 func (p *Player) Speed() float64 {
-	return p.speed.Load()
+	return p.attributes.Value(attribute.MovementSpeed)
+}
+
+// Attribute returns the value of an attribute of the player, computed from its base value and all
+// attribute.Modifiers applied to it.
+func (p *Player) Attribute(a attribute.Attribute) float64 {
+	return p.attributes.Value(a)
+}
+
+// AddAttributeModifier adds an attribute.Modifier to an attribute of the player, replacing any
+// attribute.Modifier with the same ID. The new value of the attribute is sent to the client.
+func (p *Player) AddAttributeModifier(a attribute.Attribute, m attribute.Modifier) {
+	p.updateAttribute(a, p.attributes.AddModifier(a, m))
+}
+
+// RemoveAttributeModifier removes the attribute.Modifier with the ID passed from an attribute of the
+// player. The new value of the attribute is sent to the client.
+func (p *Player) RemoveAttributeModifier(a attribute.Attribute, id string) {
+	p.updateAttribute(a, p.attributes.RemoveModifier(a, id))
+}
+
+// updateAttribute updates the state of the player after the value of an attribute changed to v and sends
+// the new value to the client.
+func (p *Player) updateAttribute(a attribute.Attribute, v float64) {
+	if a == attribute.MaxHealth {
+		p.health.SetMaxHealth(v)
+		p.session().SendHealth(p.health)
+		return
+	}
+	p.session().SendAttribute(a, v)
+}
+
+// updateEquipmentModifiers updates the attribute.Modifiers that result from the items held and the armour
+// worn by the player, such as the attack damage of a sword and its sharpness enchantment.
+func (p *Player) updateEquipmentModifiers() {
+	held, _ := p.HeldItems()
+	sharpness := 0.0
+	if s, ok := held.Enchantment(enchantment.Sharpness{}); ok {
+		sharpness = (enchantment.Sharpness{}).Addend(s.Level())
+	}
+	p.updateModifier(attribute.AttackDamage, attribute.Modifier{ID: "item.held", Amount: held.AttackDamage() - 1})
+	p.updateModifier(attribute.AttackDamage, attribute.Modifier{ID: "enchantment.sharpness", Amount: sharpness})
+	p.updateModifier(attribute.KnockBackResistance, attribute.Modifier{ID: "item.armour", Amount: p.armour.KnockBackResistance()})
+}
+
+// updateModifier adds the attribute.Modifier passed to an attribute of the player if it is not yet applied in
+// exactly the same way, so that the client is only updated if the attribute actually changed.
+func (p *Player) updateModifier(a attribute.Attribute, m attribute.Modifier) {
+	if existing, ok := p.attributes.Modifier(a, m.ID); ok && existing == m {
+		return
+	}
+	p.AddAttributeModifier(a, m)
 }
 
 // Health returns the current health of the player. It will always be lower than Player.MaxHealth().
@@ -485,12 +873,11 @@ func (p *Player) MaxHealth() float64 {
 	return p.health.MaxHealth()
 }
 
-// SetMaxHealth sets the maximum health of the player. If the current health of the player is higher than the
-// new maximum health, the health is set to the new maximum.
-// SetMaxHealth panics if the max health passed is 0 or lower.
+// SetMaxHealth sets the base maximum health of the player. Modifiers of the attribute.MaxHealth attribute, such
+// as those of the health boost effect, are applied on top of it. If the current health of the player is higher
+// than the new maximum health, the health is set to the new maximum.
 func (p *Player) SetMaxHealth(health float64) {
-	p.health.SetMaxHealth(health)
-	p.session().SendHealth(p.health)
+	p.updateAttribute(attribute.MaxHealth, p.attributes.SetBase(attribute.MaxHealth, health))
 }
 
 // addHealth adds health to the player's current health.
@@ -509,7 +896,7 @@ func (p *Player) Heal(health float64, source world.HealingSource) {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleHeal(ctx, &health, source); ctx.Cancelled() {
+	if p.h.HandleHeal(ctx, &health, source); ctx.Cancelled() {
 		return
 	}
 	p.addHealth(health)
@@ -566,9 +953,12 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	if _, ok := p.Effect(effect.FireResistance{}); (ok && src.Fire()) || p.Dead() || !p.GameMode().AllowsTakingDamage() {
 		return 0, false
 	}
-	immunity := time.Second / 2
+	if !gameRulesAllowDamage(p.World(), src) {
+		return 0, false
+	}
+	immunity := p.Combat().HitDelay
 	ctx := event.C()
-	if p.Handler().HandleHurt(ctx, &dmg, &immunity, src); ctx.Cancelled() {
+	if p.h.HandleHurt(ctx, &dmg, &immunity, src); ctx.Cancelled() {
 		return 0, false
 	}
 	if dmg < 0 {
@@ -600,6 +990,10 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 			p.SetHeldItems(hand.Grow(-1), offHand)
 			return 0, false
 		}
+		ctx := event.C()
+		if p.h.HandlePreDeath(ctx, src); ctx.Cancelled() {
+			return 0, false
+		}
 	}
 
 	p.addHealth(-damageLeft)
@@ -607,13 +1001,7 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	if src.ReducedByArmour() {
 		p.Exhaust(0.1)
 		p.Armour().Damage(dmg, p.damageItem)
-		var origin world.Entity
-		if s, ok := src.(entity.AttackDamageSource); ok {
-			origin = s.Attacker
-		} else if s, ok := src.(entity.ProjectileDamageSource); ok {
-			origin = s.Owner
-		}
-		if l, ok := origin.(entity.Living); ok {
+		if l, ok := damageOrigin(src).(entity.Living); ok {
 			thornsDmg := p.Armour().ThornsDamage(p.damageItem)
 			if thornsDmg > 0 {
 				l.Hurt(thornsDmg, enchantment.ThornsDamageSource{Owner: p})
@@ -625,10 +1013,11 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	for _, viewer := range p.viewers() {
 		viewer.ViewEntityAction(p, entity.HurtAction{})
 	}
+	w.EmitGameEvent(world.GameEventEntityDamage, pos, damageOrigin(src))
 	if src.Fire() {
-		w.PlaySound(pos, sound.Burning{})
+		p.playSound(pos, sound.Burning{})
 	} else if _, ok := src.(entity.DrowningDamageSource); ok {
-		w.PlaySound(pos, sound.Drowning{})
+		p.playSound(pos, sound.Drowning{})
 	}
 
 	p.SetAttackImmunity(immunity)
@@ -638,6 +1027,26 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	return totalDamage, true
 }
 
+// gameRulesAllowDamage checks if the game rules of the world passed allow a player to take damage from the
+// world.DamageSource passed.
+func gameRulesAllowDamage(w *world.World, src world.DamageSource) bool {
+	if _, ok := damageOrigin(src).(*Player); ok && !world.GameRulePVP.Value(w) {
+		return false
+	}
+	switch src.(type) {
+	case entity.FallDamageSource:
+		return world.GameRuleFallDamage.Value(w)
+	case entity.DrowningDamageSource:
+		return world.GameRuleDrowningDamage.Value(w)
+	case block.FreezeDamageSource:
+		return world.GameRuleFreezeDamage.Value(w)
+	}
+	if src.Fire() {
+		return world.GameRuleFireDamage.Value(w)
+	}
+	return true
+}
+
 // applyTotemEffects is an unexported function that is used to handle totem effects.
 func (p *Player) applyTotemEffects() {
 	p.addHealth(2 - p.Health())
@@ -650,7 +1059,7 @@ func (p *Player) applyTotemEffects() {
 	p.AddEffect(effect.New(effect.FireResistance{}, 1, time.Second*40))
 	p.AddEffect(effect.New(effect.Absorption{}, 2, time.Second*5))
 
-	p.World().PlaySound(p.Position(), sound.Totem{})
+	p.playSound(p.Position(), sound.Totem{})
 
 	for _, viewer := range p.viewers() {
 		viewer.ViewEntityAction(p, entity.TotemUseAction{})
@@ -713,7 +1122,8 @@ func (p *Player) knockBack(src mgl64.Vec3, force, height float64) {
 	}
 	velocity[1] = height
 
-	p.SetVelocity(velocity.Mul(1 - p.Armour().KnockBackResistance()))
+	p.updateEquipmentModifiers()
+	p.SetVelocity(velocity.Mul((1 - p.attributes.Value(attribute.KnockBackResistance)) * p.knockBackReceived.Load()))
 }
 
 // AttackImmune checks if the player is currently immune to entity attacks, meaning it was recently attacked.
@@ -758,6 +1168,18 @@ func (p *Player) Saturate(food int, saturation float64) {
 	p.sendFood()
 }
 
+// SetRegeneration changes the Regeneration settings of the player, which control how quickly the player
+// regenerates health while its food bar is full enough.
+func (p *Player) SetRegeneration(r Regeneration) {
+	p.regeneration.Store(r)
+}
+
+// Regeneration returns the Regeneration settings of the player, as set using SetRegeneration. By default,
+// the result of DefaultRegeneration is returned.
+func (p *Player) Regeneration() Regeneration {
+	return p.regeneration.Load()
+}
+
 // sendFood sends the current food properties to the client.
 func (p *Player) sendFood() {
 	p.hunger.mu.RLock()
@@ -801,7 +1223,7 @@ func (*Player) BeaconAffected() bool {
 // Exhaust exhausts the player by the amount of points passed if the player is in survival mode. If the total
 // exhaustion level exceeds 4, a saturation point, or food point, if saturation is 0, will be subtracted.
 func (p *Player) Exhaust(points float64) {
-	if !p.GameMode().AllowsTakingDamage() || p.World().Difficulty().FoodRegenerates() {
+	if w := p.World(); !p.GameMode().AllowsTakingDamage() || w.Difficulty().FoodRegenerates() || !world.GameRuleHungerDrain.Value(w) {
 		return
 	}
 	before := p.hunger.Food()
@@ -811,7 +1233,7 @@ func (p *Player) Exhaust(points float64) {
 		p.hunger.SetFood(before)
 
 		ctx := event.C()
-		if p.Handler().HandleFoodLoss(ctx, before, &after); ctx.Cancelled() {
+		if p.h.HandleFoodLoss(ctx, before, &after); ctx.Cancelled() {
 			return
 		}
 		p.hunger.SetFood(after)
@@ -848,12 +1270,21 @@ func (p *Player) kill(src world.DamageSource) {
 
 	p.addHealth(-p.MaxHealth())
 
-	keepInv := false
-	p.Handler().HandleDeath(src, &keepInv)
+	w := p.World()
+	keepInv := p.keepInventory.Load() || world.GameRuleKeepInventory.Value(w)
+	p.h.HandleDeath(src, &keepInv)
+	if world.GameRuleShowDeathMessages.Value(w) {
+		msg := DeathMessage(p, src)
+		p.h.HandleDeathMessage(src, &msg)
+		if msg != "" {
+			_, _ = fmt.Fprintln(chat.Global, msg)
+		}
+	}
 	p.StopSneaking()
 	p.StopSprinting()
+	p.Dismount()
 
-	w, pos := p.World(), p.Position()
+	pos := p.Position()
 	if !keepInv {
 		p.dropContents()
 	}
@@ -902,6 +1333,56 @@ func (p *Player) dropContents() {
 	}
 }
 
+// RespawnState holds the state that a Player is given when it respawns. It is passed to Handler.HandleRespawnState,
+// so that it may be changed by handlers.
+type RespawnState struct {
+	// Health is the health that the Player respawns with. By default, it is the maximum health of the Player.
+	Health float64
+	// Food and Saturation are the food and saturation levels that the Player respawns with. By default, they
+	// are 20 and 5 respectively.
+	Food       int
+	Saturation float64
+	// GameMode is the game mode that the Player respawns in. By default, it is the game mode that the Player
+	// died in.
+	GameMode world.GameMode
+	// Effects holds effects that are added to the Player once it respawned. By default, no effects are added.
+	Effects []effect.Effect
+}
+
+// SetKeepInventory changes if the player keeps its items and experience when it dies. If set to true, the
+// player keeps its inventory regardless of the keepInventory game rule of its world.
+func (p *Player) SetKeepInventory(keep bool) {
+	p.keepInventory.Store(keep)
+}
+
+// KeepInventory returns true if the player keeps its items and experience when it dies regardless of the
+// keepInventory game rule, as set using SetKeepInventory.
+func (p *Player) KeepInventory() bool {
+	return p.keepInventory.Load()
+}
+
+// Vanish hides the player from other players. Vanished players are removed from the player list of other
+// players, are not shown to them and do not make sounds they can hear. Players with the
+// session.VanishSeePermission can still see vanished players, so that staff may keep track of each other.
+// Vanished players can otherwise interact with the world as usual.
+func (p *Player) Vanish() {
+	if !p.vanished.Swap(true) {
+		p.session().UpdateVanish()
+	}
+}
+
+// Unvanish shows the player to other players again after it was hidden using Vanish.
+func (p *Player) Unvanish() {
+	if p.vanished.Swap(false) {
+		p.session().UpdateVanish()
+	}
+}
+
+// Vanished returns true if the player is vanished, as set using Vanish.
+func (p *Player) Vanished() bool {
+	return p.vanished.Load()
+}
+
 // Respawn spawns the player after it dies, so that its health is replenished and it is spawned in the world
 // again. Nothing will happen if the player does not have a session connected to it.
 func (p *Player) Respawn() {
@@ -909,9 +1390,6 @@ func (p *Player) Respawn() {
 	if !p.Dead() || w == nil || p.session() == session.Nop {
 		return
 	}
-	p.addHealth(p.MaxHealth())
-	p.hunger.Reset()
-	p.sendFood()
 	p.Extinguish()
 	p.ResetFallDistance()
 
@@ -919,12 +1397,26 @@ func (p *Player) Respawn() {
 	// always bring us back to the overworld.
 	w = w.PortalDestination(w.Dimension())
 	pos := w.PlayerSpawn(p.UUID()).Vec3Middle()
+	state := RespawnState{Health: p.MaxHealth(), Food: 20, Saturation: 5, GameMode: p.GameMode()}
+
+	p.h.HandleRespawn(&pos, &w)
+	p.h.HandleRespawnState(&state)
 
-	p.Handler().HandleRespawn(&pos, &w)
+	p.addHealth(max(state.Health, 1))
+	p.hunger.Reset()
+	p.hunger.SetFood(state.Food)
+	p.hunger.saturate(0, state.Saturation-5)
+	p.sendFood()
 
 	w.AddEntity(p)
 	p.Teleport(pos)
 	p.session().SendRespawn(pos)
+	if state.GameMode != p.GameMode() {
+		p.SetGameMode(state.GameMode)
+	}
+	for _, e := range state.Effects {
+		p.AddEffect(e)
+	}
 
 	p.SetVisible()
 }
@@ -937,14 +1429,14 @@ func (p *Player) StartSprinting() {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleToggleSprint(ctx, true); ctx.Cancelled() {
+	if p.h.HandleToggleSprint(ctx, true); ctx.Cancelled() {
 		return
 	}
 	if !p.sprinting.CAS(false, true) {
 		return
 	}
 	p.StopSneaking()
-	p.SetSpeed(p.Speed() * 1.3)
+	p.AddAttributeModifier(attribute.MovementSpeed, attribute.Modifier{ID: "sprinting", Amount: 0.3, Operation: attribute.OperationMultiplyTotal})
 
 	p.updateState()
 }
@@ -957,13 +1449,13 @@ func (p *Player) Sprinting() bool {
 // StopSprinting makes a player stop sprinting, setting back the speed of the player to its original value.
 func (p *Player) StopSprinting() {
 	ctx := event.C()
-	if p.Handler().HandleToggleSprint(ctx, false); ctx.Cancelled() {
+	if p.h.HandleToggleSprint(ctx, false); ctx.Cancelled() {
 		return
 	}
 	if !p.sprinting.CAS(true, false) {
 		return
 	}
-	p.SetSpeed(p.Speed() / 1.3)
+	p.RemoveAttributeModifier(attribute.MovementSpeed, "sprinting")
 
 	p.updateState()
 }
@@ -973,12 +1465,13 @@ func (p *Player) StopSprinting() {
 // If the player is sprinting while StartSneaking is called, the sprinting is stopped.
 func (p *Player) StartSneaking() {
 	ctx := event.C()
-	if p.Handler().HandleToggleSneak(ctx, true); ctx.Cancelled() {
+	if p.h.HandleToggleSneak(ctx, true); ctx.Cancelled() {
 		return
 	}
 	if !p.sneaking.CAS(false, true) {
 		return
 	}
+	p.StopSpectating()
 	if !p.Flying() {
 		p.StopSprinting()
 	}
@@ -994,7 +1487,7 @@ func (p *Player) Sneaking() bool {
 // will not do anything.
 func (p *Player) StopSneaking() {
 	ctx := event.C()
-	if p.Handler().HandleToggleSneak(ctx, false); ctx.Cancelled() {
+	if p.h.HandleToggleSneak(ctx, false); ctx.Cancelled() {
 		return
 	}
 	if !p.sneaking.CAS(true, false) {
@@ -1081,7 +1574,7 @@ func (p *Player) Jump() {
 		return
 	}
 
-	p.Handler().HandleJump()
+	p.h.HandleJump()
 	if p.OnGround() {
 		jumpVel := 0.42
 		if e, ok := p.Effect(effect.JumpBoost{}); ok {
@@ -1154,6 +1647,62 @@ func (p *Player) FireProof() bool {
 	return !p.GameMode().AllowsTakingDamage()
 }
 
+// maxFreezeTicks is the number of ticks that a player must spend in powder snow to be fully frozen.
+const maxFreezeTicks = 140
+
+// Freeze makes the player freeze further in the current tick, as it does while inside of powder snow. Players
+// that are fully frozen take damage every two seconds. Freeze has no effect if the player is FreezeImmune.
+func (p *Player) Freeze() {
+	p.inPowderSnow.Store(true)
+}
+
+// FreezeProgress returns how far the player has frozen, ranging from 0 (not frozen at all) to 1 (fully frozen).
+func (p *Player) FreezeProgress() float64 {
+	return float64(p.freezeTicks.Load()) / maxFreezeTicks
+}
+
+// FreezeImmune checks if the player is immune to freezing. Players wearing any piece of leather armour and
+// players that cannot take damage are immune to freezing.
+func (p *Player) FreezeImmune() bool {
+	if !p.GameMode().AllowsTakingDamage() {
+		return true
+	}
+	for _, it := range p.armour.Items() {
+		var tier item.ArmourTier
+		switch a := it.Item().(type) {
+		case item.Helmet:
+			tier = a.Tier
+		case item.Chestplate:
+			tier = a.Tier
+		case item.Leggings:
+			tier = a.Tier
+		case item.Boots:
+			tier = a.Tier
+		}
+		if _, ok := tier.(item.ArmourTierLeather); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tickFreezing freezes the player further if it was inside of powder snow during the tick, or thaws it
+// otherwise. Fully frozen players are dealt damage every 40 ticks.
+func (p *Player) tickFreezing(current int64) {
+	before := p.freezeTicks.Load()
+	after := max(before-2, 0)
+	if p.inPowderSnow.Swap(false) && !p.FreezeImmune() {
+		after = min(before+1, maxFreezeTicks)
+	}
+	if after != before {
+		p.freezeTicks.Store(after)
+		p.updateState()
+	}
+	if after >= maxFreezeTicks && current%40 == 0 {
+		p.Hurt(1, block.FreezeDamageSource{})
+	}
+}
+
 // OnFireDuration ...
 func (p *Player) OnFireDuration() time.Duration {
 	return time.Duration(p.fireTicks.Load()) * time.Second / 20
@@ -1203,6 +1752,14 @@ func (p *Player) SetHeldItems(mainHand, offHand item.Stack) {
 	_ = p.offHand.SetItem(0, offHand)
 }
 
+// ChangeHeldSlot handles the player changing the hotbar slot that it holds from one slot to another. It
+// returns false if the change was cancelled, in which case the player should keep holding the previous slot.
+func (p *Player) ChangeHeldSlot(from, to int) bool {
+	ctx := event.C()
+	p.h.HandleHeldSlotChange(ctx, from, to)
+	return !ctx.Cancelled()
+}
+
 // EnderChestInventory returns the player's ender chest inventory. Its accessed by the player when opening
 // ender chests anywhere.
 func (p *Player) EnderChestInventory() *inventory.Inventory {
@@ -1221,6 +1778,9 @@ func (p *Player) SetGameMode(mode world.GameMode) {
 	if !mode.AllowsFlying() {
 		p.StopFlying()
 	}
+	if !spectator(mode) {
+		p.StopSpectating()
+	}
 	if !mode.Visible() {
 		p.SetInvisible()
 	} else if !previous.Visible() {
@@ -1278,10 +1838,16 @@ func (p *Player) UseItem() {
 		w       = p.World()
 		ctx     = event.C()
 	)
+	if !i.Empty() {
+		sinceLast := time.Since(p.lastRightClickAir.Swap(time.Now()))
+		if p.h.HandleRightClickAir(ctx, sinceLast); ctx.Cancelled() {
+			return
+		}
+	}
 	if p.HasCooldown(i.Item()) {
 		return
 	}
-	if p.Handler().HandleItemUse(ctx); ctx.Cancelled() {
+	if p.h.HandleItemUse(ctx); ctx.Cancelled() {
 		return
 	}
 	i, left = p.HeldItems()
@@ -1337,7 +1903,7 @@ func (p *Player) UseItem() {
 		}
 
 		ctx = event.C()
-		if p.Handler().HandleItemConsume(ctx, i); ctx.Cancelled() {
+		if p.h.HandleItemConsume(ctx, i); ctx.Cancelled() {
 			// Consuming was cancelled, but the client will continue consuming the next item.
 			p.usingSince.Store(time.Now().UnixNano())
 			return
@@ -1347,7 +1913,7 @@ func (p *Player) UseItem() {
 		useCtx := p.useContext()
 		useCtx.NewItem = usable.Consume(w, p)
 		p.addNewItem(useCtx)
-		w.PlaySound(p.Position().Add(mgl64.Vec3{0, 1.5}), sound.Burp{})
+		p.playSound(p.Position().Add(mgl64.Vec3{0, 1.5}), sound.Burp{})
 	}
 }
 
@@ -1427,7 +1993,7 @@ func (p *Player) UseItemOnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Vec
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemUseOnBlock(ctx, pos, face, clickPos); ctx.Cancelled() {
+	if p.h.HandleItemUseOnBlock(ctx, pos, face, clickPos); ctx.Cancelled() {
 		p.resendBlocks(pos, w, face)
 		return
 	}
@@ -1451,6 +2017,14 @@ func (p *Player) UseItemOnBlock(pos cube.Pos, face cube.Face, clickPos mgl64.Vec
 	if i.Empty() {
 		return
 	}
+	if !p.GameMode().AllowsEditing() && !i.PlaceableOn(b) {
+		// Players in adventure mode may only use items on the blocks that the item may be placed on.
+		p.resendBlocks(pos, w, face)
+		return
+	}
+	p.usedOn.Store(b)
+	defer p.usedOn.Store(nil)
+
 	switch ib := i.Item().(type) {
 	case item.UsableOnBlock:
 		// The item does something when used on a block.
@@ -1486,16 +2060,27 @@ func (p *Player) UseItemOnEntity(e world.Entity) bool {
 		return false
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemUseOnEntity(ctx, e); ctx.Cancelled() {
+	if p.h.HandleItemUseOnEntity(ctx, e); ctx.Cancelled() {
 		return false
 	}
 	i, left := p.HeldItems()
+	if interactable, ok := e.(entity.Interactable); ok {
+		useCtx := p.useContext()
+		if interactable.Interact(p, useCtx) {
+			p.SwingArm()
+			p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
+			p.addNewItem(useCtx)
+			return true
+		}
+	}
 	usable, ok := i.Item().(item.UsableOnEntity)
 	if !ok {
+		p.mountInteracted(e)
 		return true
 	}
 	useCtx := p.useContext()
 	if !usable.UseOnEntity(e, e.World(), p, useCtx) {
+		p.mountInteracted(e)
 		return true
 	}
 	p.SwingArm()
@@ -1504,55 +2089,192 @@ func (p *Player) UseItemOnEntity(e world.Entity) bool {
 	return true
 }
 
+// Mount makes the player ride the entity.Rideable passed, occupying its first free seat. If the player is
+// already riding, or if the entity.Rideable has no free seat, Mount returns false. The player occupying the
+// first seat of an entity.Drivable controls it.
+func (p *Player) Mount(r entity.Rideable) bool {
+	if world.Entity(r) == p || p.Dead() {
+		return false
+	}
+	if _, riding := p.Riding(); riding {
+		return false
+	}
+	seat, ok := r.Seats().Sit(p)
+	if !ok {
+		return false
+	}
+	p.ridden.Store(r)
+	p.StopSneaking()
+	p.StopSprinting()
+	p.pos.Store(entity.SeatPosition(r, seat))
+	p.vel.Store(mgl64.Vec3{})
+
+	for _, v := range p.viewers() {
+		v.ViewEntityMount(p, r, seat == 0)
+		v.ViewEntityState(p)
+	}
+	return true
+}
+
+// Dismount makes the player stop riding the entity.Rideable it is currently riding, if any. The player is
+// placed on top of the entity.Rideable.
+func (p *Player) Dismount() {
+	r := p.ridden.Swap(nil)
+	if r == nil {
+		return
+	}
+	r.Seats().Leave(p)
+	for _, v := range p.viewers() {
+		v.ViewEntityDismount(p, r)
+		v.ViewEntityState(p)
+	}
+	p.teleport(entity.DismountPosition(r))
+}
+
+// Riding returns the entity.Rideable currently ridden by the player. If the player is not riding anything,
+// false is returned.
+func (p *Player) Riding() (entity.Rideable, bool) {
+	r := p.ridden.Load()
+	return r, r != nil
+}
+
+// DriveVehicle forwards the movement input passed to the entity ridden by the player, if the player occupies
+// its first seat and the entity implements entity.Drivable.
+func (p *Player) DriveVehicle(input entity.RiderInput) {
+	r, ok := p.Riding()
+	if !ok {
+		return
+	}
+	if seat, ok := r.Seats().Seat(p); !ok || seat != 0 {
+		return
+	}
+	if d, ok := r.(entity.Drivable); ok {
+		d.Drive(p, input)
+	}
+}
+
+// tickRiding moves the player to its seat on the entity.Rideable passed. If the entity.Rideable is no longer
+// in the same world as the player, the player dismounts it.
+func (p *Player) tickRiding(w *world.World, r entity.Rideable) {
+	seat, ok := r.Seats().Seat(p)
+	if rw, inWorld := world.OfEntity(r); !inWorld || rw != w || !ok {
+		p.Dismount()
+		return
+	}
+	p.pos.Store(entity.SeatPosition(r, seat))
+	p.ResetFallDistance()
+}
+
+// Spectate makes the player spectate the entity passed, moving the player along with it until the player
+// starts sneaking, leaves spectator mode or the entity leaves the world of the player. Spectate does nothing
+// if the player is not in spectator mode or if the entity is not in the same world as the player.
+func (p *Player) Spectate(e world.Entity) {
+	if e == p || !spectator(p.GameMode()) {
+		return
+	}
+	if w, ok := world.OfEntity(e); !ok || w != p.World() {
+		return
+	}
+	p.spectating.Store(e)
+	p.teleport(e.Position())
+}
+
+// StopSpectating stops the player from spectating the entity it is currently spectating, if any.
+func (p *Player) StopSpectating() {
+	p.spectating.Store(nil)
+}
+
+// Spectating returns the entity currently spectated by the player using Spectate. If the player is not
+// spectating any entity, false is returned.
+func (p *Player) Spectating() (world.Entity, bool) {
+	e := p.spectating.Load()
+	return e, e != nil
+}
+
+// tickSpectating moves the player to the entity it is spectating, or stops spectating it if the entity has
+// left the world of the player.
+func (p *Player) tickSpectating(w *world.World, e world.Entity) {
+	if ew, inWorld := world.OfEntity(e); !inWorld || ew != w || !spectator(p.GameMode()) {
+		p.StopSpectating()
+		return
+	}
+	if pos := e.Position(); pos != p.Position() {
+		p.teleport(pos)
+	}
+}
+
+// spectator checks if a world.GameMode is spectator mode, in which players are invisible and have no
+// collision.
+func spectator(mode world.GameMode) bool {
+	return !mode.Visible() && !mode.HasCollision()
+}
+
+// mountInteracted makes the player mount the entity passed after interacting with it, if it is an
+// entity.Rideable.
+func (p *Player) mountInteracted(e world.Entity) {
+	if r, ok := e.(entity.Rideable); ok && p.Mount(r) {
+		p.SwingArm()
+	}
+}
+
 // AttackEntity uses the item held in the main hand of the player to attack the entity passed, provided it is
 // within range of the player.
 // The damage dealt to the entity will depend on the item held by the player and any effects the player may
 // have.
 // If the player cannot reach the entity at its position, the method returns immediately.
 func (p *Player) AttackEntity(e world.Entity) bool {
+	if spectator(p.GameMode()) {
+		// Players in spectator mode cannot attack entities, but instead start spectating the entity attacked.
+		p.Spectate(e)
+		return false
+	}
 	if !p.canReach(e.Position()) {
 		return false
 	}
 	var (
-		force, height  = 0.45, 0.3608
+		c              = p.Combat()
+		strength       = c.strength(time.Since(p.lastAttack.Load()))
+		charged        = strength > 0.9
+		force, height  = c.KnockBackForce, c.KnockBackHeight
 		_, slowFalling = p.Effect(effect.SlowFalling{})
 		_, blind       = p.Effect(effect.Blindness{})
-		critical       = !p.Sprinting() && !p.Flying() && p.FallDistance() > 0 && !slowFalling && !blind
+		critical       = c.Criticals && charged && (c.SprintCriticals || !p.Sprinting()) && !p.Flying() && p.FallDistance() > 0 && !slowFalling && !blind
 	)
+	if p.Sprinting() && charged {
+		force, height = force+c.SprintKnockBackForce, height+c.SprintKnockBackHeight
+	}
+	m := p.knockBackDealt.Load()
+	force, height = force*m, height*m
 
 	ctx := event.C()
-	if p.Handler().HandleAttackEntity(ctx, e, &force, &height, &critical); ctx.Cancelled() {
+	if p.h.HandleAttackEntity(ctx, e, &force, &height, &critical); ctx.Cancelled() {
 		return false
 	}
 	p.SwingArm()
+	p.lastAttack.Store(time.Now())
 
 	i, _ := p.HeldItems()
 	living, ok := e.(entity.Living)
 	if !ok {
+		if a, ok := e.(entity.Attackable); ok {
+			return a.Attack(entity.AttackDamageSource{Attacker: p})
+		}
 		return false
 	}
 	if living.AttackImmune() {
 		return true
 	}
 
-	dmg := i.AttackDamage()
-	if strength, ok := p.Effect(effect.Strength{}); ok {
-		dmg += dmg * effect.Strength{}.Multiplier(strength.Level())
-	}
-	if weakness, ok := p.Effect(effect.Weakness{}); ok {
-		dmg -= dmg * effect.Weakness{}.Multiplier(weakness.Level())
-	}
-	if s, ok := i.Enchantment(enchantment.Sharpness{}); ok {
-		dmg += (enchantment.Sharpness{}).Addend(s.Level())
-	}
+	p.updateEquipmentModifiers()
+	dmg := p.attributes.Value(attribute.AttackDamage) * strength
 	if critical {
-		dmg *= 1.5
+		dmg *= c.CriticalMultiplier
 	}
 
 	n, vulnerable := living.Hurt(dmg, entity.AttackDamageSource{Attacker: p})
 	i, left := p.HeldItems()
 
-	p.World().PlaySound(entity.EyePosition(e), sound.Attack{Damage: !mgl64.FloatEqual(n, 0)})
+	p.playSound(entity.EyePosition(e), sound.Attack{Damage: !mgl64.FloatEqual(n, 0)})
 	if !vulnerable {
 		return true
 	}
@@ -1598,7 +2320,7 @@ func (p *Player) StartBreaking(pos cube.Pos, face cube.Face) {
 	if _, ok := w.Block(pos.Side(face)).(block.Fire); ok {
 		// TODO: Add a way to cancel fire extinguishing. This is currently not possible to handle.
 		w.SetBlock(pos.Side(face), nil, nil)
-		w.PlaySound(pos.Vec3(), sound.FireExtinguish{})
+		p.playSound(pos.Vec3(), sound.FireExtinguish{})
 		return
 	}
 
@@ -1610,9 +2332,12 @@ func (p *Player) StartBreaking(pos cube.Pos, face cube.Face) {
 	// Note: We intentionally store this regardless of whether the breaking proceeds, so that we
 	// can resend the block to the client when it tries to break the block regardless.
 	p.breakingPos.Store(pos)
+	if !p.canDestroy(w.Block(pos)) {
+		return
+	}
 
 	ctx := event.C()
-	if p.Handler().HandleStartBreak(ctx, pos); ctx.Cancelled() {
+	if p.h.HandleStartBreak(ctx, pos); ctx.Cancelled() {
 		return
 	}
 	if punchable, ok := w.Block(pos).(block.Punchable); ok {
@@ -1702,7 +2427,7 @@ func (p *Player) ContinueBreaking(face cube.Face) {
 	if p.breakParticleCounter.Add(1)%5 == 0 {
 		// We send this sound only every so often. Vanilla doesn't send it every tick while breaking
 		// either. Every 5 ticks seems accurate.
-		w.PlaySound(pos.Vec3(), sound.BlockBreaking{Block: w.Block(pos)})
+		p.playSound(pos.Vec3(), sound.BlockBreaking{Block: w.Block(pos)})
 	}
 	breakTime := p.breakTime(pos)
 	if breakTime != p.lastBreakDuration {
@@ -1730,7 +2455,7 @@ func (p *Player) PlaceBlock(pos cube.Pos, b world.Block, ctx *item.UseContext) {
 // of the player. A bool is returned indicating if a block was placed successfully.
 func (p *Player) placeBlock(pos cube.Pos, b world.Block, ignoreBBox bool) bool {
 	w := p.World()
-	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() {
+	if !p.canReach(pos.Vec3Centre()) || !p.canPlace() || !w.Border().WithinPos(pos) {
 		p.resendBlocks(pos, w, cube.Faces()...)
 		return false
 	}
@@ -1740,16 +2465,39 @@ func (p *Player) placeBlock(pos cube.Pos, b world.Block, ignoreBBox bool) bool {
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleBlockPlace(ctx, pos, b); ctx.Cancelled() {
+	if p.h.HandleBlockPlace(ctx, pos, b); ctx.Cancelled() {
 		p.resendBlocks(pos, w, cube.Faces()...)
 		return false
 	}
 	w.SetBlock(pos, b, nil)
-	w.PlaySound(pos.Vec3(), sound.BlockPlace{Block: b})
+	p.playSound(pos.Vec3(), sound.BlockPlace{Block: b})
+	w.EmitGameEvent(world.GameEventBlockPlace, pos.Vec3Centre(), p)
 	p.SwingArm()
 	return true
 }
 
+// canPlace checks if the player is able to place a block in its current game mode. Players in adventure mode
+// may only place blocks using an item that may be placed on the block that the item is used on.
+func (p *Player) canPlace() bool {
+	mode := p.GameMode()
+	if mode.AllowsEditing() {
+		return true
+	}
+	held, _ := p.HeldItems()
+	return mode.AllowsInteraction() && held.PlaceableOn(p.usedOn.Load())
+}
+
+// canDestroy checks if the player is able to break the block passed in its current game mode. Players in
+// adventure mode may only break blocks that the item they are holding may destroy.
+func (p *Player) canDestroy(b world.Block) bool {
+	mode := p.GameMode()
+	if mode.AllowsEditing() {
+		return true
+	}
+	held, _ := p.HeldItems()
+	return mode.AllowsInteraction() && held.Destroys(b)
+}
+
 // obstructedPos checks if the position passed is obstructed if the block passed is attempted to be placed.
 // The function returns true if there is an entity in the way that could prevent the block from being placed.
 func (p *Player) obstructedPos(pos cube.Pos, b world.Block) bool {
@@ -1782,7 +2530,7 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 		// Don't do anything if the position broken is already air.
 		return
 	}
-	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() {
+	if !p.canReach(pos.Vec3Centre()) || !p.canDestroy(b) {
 		p.resendBlocks(pos, w)
 		return
 	}
@@ -1791,15 +2539,17 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 		return
 	}
 	held, _ := p.HeldItems()
-	drops := p.drops(held, b, pos)
-
+	var drops []item.Stack
 	xp := 0
-	if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
-		xp = breakable.BreakInfo().XPDrops.RandomValue()
+	if world.GameRuleDoTileDrops.Value(w) {
+		drops = p.drops(held, b, pos)
+		if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
+			xp = breakable.BreakInfo().XPDrops.RandomValue()
+		}
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleBlockBreak(ctx, pos, &drops, &xp); ctx.Cancelled() {
+	if p.h.HandleBlockBreak(ctx, pos, &drops, &xp); ctx.Cancelled() {
 		p.resendBlocks(pos, w)
 		return
 	}
@@ -1808,6 +2558,7 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 	p.SwingArm()
 	w.SetBlock(pos, nil, nil)
 	w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+	w.EmitGameEvent(world.GameEventBlockDestroy, pos.Vec3Centre(), p)
 
 	if breakable, ok := b.(block.Breakable); ok {
 		info := breakable.BreakInfo()
@@ -1834,6 +2585,18 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 	}
 }
 
+// damageOrigin returns the entity that caused the world.DamageSource passed,
+// such as the attacker or the owner of a projectile. nil is returned if the
+// damage was not caused by an entity.
+func damageOrigin(src world.DamageSource) world.Entity {
+	if s, ok := src.(entity.AttackDamageSource); ok {
+		return s.Attacker
+	} else if s, ok := src.(entity.ProjectileDamageSource); ok {
+		return s.Owner
+	}
+	return nil
+}
+
 // drops returns the drops that the player can get from the block passed using the item held.
 func (p *Player) drops(held item.Stack, b world.Block, pos cube.Pos) []item.Stack {
 	t, ok := held.Item().(item.Tool)
@@ -1878,12 +2641,12 @@ func (p *Player) drops(held item.Stack, b world.Block, pos cube.Pos) []item.Stac
 		}
 		if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
 			if breakable.BreakInfo().Harvestable(t) {
-				drops = append(drops, breakable.BreakInfo().Drops(t, held.Enchantments())...)
+				drops = append(drops, block.BreakDrops(b, t, held.Enchantments(), loot.Context{This: p})...)
 			}
 		}
 	} else if breakable, ok := b.(block.Breakable); ok && !p.GameMode().CreativeInventory() {
 		if breakable.BreakInfo().Harvestable(t) {
-			drops = breakable.BreakInfo().Drops(t, held.Enchantments())
+			drops = block.BreakDrops(b, t, held.Enchantments(), loot.Context{This: p})
 		}
 	} else if it, ok := b.(world.Item); ok && !p.GameMode().CreativeInventory() {
 		drops = []item.Stack{item.NewStack(it, 1)}
@@ -1916,7 +2679,7 @@ func (p *Player) PickBlock(pos cube.Pos) {
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleBlockPick(ctx, pos, b); ctx.Cancelled() {
+	if p.h.HandleBlockPick(ctx, pos, b); ctx.Cancelled() {
 		return
 	}
 	_, offhand := p.HeldItems()
@@ -1945,24 +2708,54 @@ func (p *Player) PickBlock(pos cube.Pos) {
 }
 
 // Teleport teleports the player to a target position in the world. Unlike Move, it immediately changes the
-// position of the player, rather than showing an animation.
+// position of the player, rather than showing an animation. Players cannot be teleported outside the border of
+// the world.
 func (p *Player) Teleport(pos mgl64.Vec3) {
+	if !p.World().Border().Within(pos) {
+		return
+	}
 	ctx := event.C()
-	if p.Handler().HandleTeleport(ctx, pos); ctx.Cancelled() {
+	if p.h.HandleTeleport(ctx, pos); ctx.Cancelled() {
 		return
 	}
+	p.Dismount()
+	p.teleport(pos)
+}
+
+// TeleportToWorld moves the player to the world passed and teleports it to a target position in that world.
+// If the world is of a different world.Dimension than the current world of the player, the client of the
+// player is moved to the new dimension, including when moving between world.CustomDimensions. If the world
+// passed is the world the player is already in, TeleportToWorld behaves like Teleport.
+func (p *Player) TeleportToWorld(w *world.World, pos mgl64.Vec3) {
+	if w == p.World() {
+		p.Teleport(pos)
+		return
+	}
+	if !w.Border().Within(pos) {
+		return
+	}
+	ctx := event.C()
+	if p.h.HandleTeleport(ctx, pos); ctx.Cancelled() {
+		return
+	}
+	p.Dismount()
+	w.AddTicket(world.TicketTeleport, world.ChunkPos{int32(math.Floor(pos[0])) >> 4, int32(math.Floor(pos[2])) >> 4})
+	w.AddEntity(p)
 	p.teleport(pos)
 }
 
 // teleport teleports the player to a target position in the world. It does not call the Handler of the
 // player.
 func (p *Player) teleport(pos mgl64.Vec3) {
+	// Keep the destination loaded until the player starts loading the chunks around it.
+	p.World().AddTicket(world.TicketTeleport, world.ChunkPos{int32(math.Floor(pos[0])) >> 4, int32(math.Floor(pos[2])) >> 4})
 	for _, v := range p.viewers() {
 		v.ViewEntityTeleport(p, pos)
 	}
 	p.pos.Store(pos)
 	p.vel.Store(mgl64.Vec3{})
 	p.ResetFallDistance()
+	p.resetMovement(pos)
 }
 
 // Move moves the player from one position to another in the world, by adding the delta passed to the current
@@ -1986,8 +2779,15 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 		yaw, pitch            = p.Rotation().Elem()
 		res, resYaw, resPitch = pos.Add(deltaPos), yaw + deltaYaw, pitch + deltaPitch
 	)
+	if v, ok := p.validateMovement(w, pos, res); !ok {
+		ctx := event.C()
+		if p.h.HandleMovementViolation(ctx, v); !ctx.Cancelled() && p.MovementValidation().Correct {
+			p.teleport(pos)
+			return
+		}
+	}
 	ctx := event.C()
-	if p.Handler().HandleMove(ctx, res, resYaw, resPitch); ctx.Cancelled() {
+	if p.h.HandleMove(ctx, res, resYaw, resPitch); ctx.Cancelled() {
 		if p.session() != session.Nop && pos.ApproxEqual(p.Position()) {
 			// The position of the player was changed and the event cancelled. This means we still need to notify the
 			// player of this movement change.
@@ -2016,7 +2816,7 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 		}
 		if p.collidedHorizontally.Load() {
 			if force := horizontalVel.Len()*10.0 - 3.0; force > 0.0 && !p.AttackImmune() {
-				w.PlaySound(p.Position(), sound.Fall{Distance: force})
+				p.playSound(p.Position(), sound.Fall{Distance: force})
 				p.Hurt(force, entity.GlideDamageSource{})
 			}
 		}
@@ -2032,6 +2832,10 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 
 	p.onGround.Store(p.checkOnGround(w))
 	p.updateFallState(deltaPos[1])
+	if p.OnGround() && !p.Sneaking() && p.stepDistance.Add(horizontalVel.Len()) >= 1.5 {
+		p.stepDistance.Store(0)
+		w.EmitGameEvent(world.GameEventStep, res, p)
+	}
 
 	if p.Swimming() {
 		p.Exhaust(0.01 * horizontalVel.Len())
@@ -2064,6 +2868,7 @@ func (p *Player) SetVelocity(velocity mgl64.Vec3) {
 		p.vel.Store(velocity)
 		return
 	}
+	p.addMovementVelocity(velocity)
 	for _, v := range p.viewers() {
 		v.ViewEntityVelocity(p, velocity)
 	}
@@ -2086,7 +2891,7 @@ func (p *Player) Collect(s item.Stack) int {
 		return 0
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemPickup(ctx, &s); ctx.Cancelled() {
+	if p.h.HandleItemPickup(ctx, &s); ctx.Cancelled() {
 		return 0
 	}
 	n, _ := p.Inventory().AddItem(s)
@@ -2111,7 +2916,7 @@ func (p *Player) ResetEnchantmentSeed() {
 // AddExperience adds experience to the player.
 func (p *Player) AddExperience(amount int) int {
 	ctx := event.C()
-	if p.Handler().HandleExperienceGain(ctx, &amount); ctx.Cancelled() {
+	if p.h.HandleExperienceGain(ctx, &amount); ctx.Cancelled() {
 		return 0
 	}
 	before := p.experience.Level()
@@ -2165,10 +2970,12 @@ func (p *Player) CollectExperience(value int) bool {
 	if time.Since(p.lastXPPickup.Load()) < time.Millisecond*100 {
 		return false
 	}
-	value = p.mendItems(value)
+	left := p.mendItems(value)
 	p.lastXPPickup.Store(time.Now())
-	if value > 0 {
-		return p.AddExperience(value) > 0
+	if left > 0 {
+		// If any experience was spent on mending, the orb must be collected regardless of whether the remaining
+		// experience could be added, so that it cannot be used to repair items again.
+		return p.AddExperience(left) > 0 || left != value
 	}
 
 	p.PlaySound(sound.Experience{})
@@ -2176,41 +2983,44 @@ func (p *Player) CollectExperience(value int) bool {
 }
 
 // mendItems handles the mending enchantment when collecting experience, it then returns the leftover experience.
+// Experience is spent on random damaged items with mending until either no experience is left or all items with
+// mending are fully repaired.
 func (p *Player) mendItems(xp int) int {
-	mendingItems := make([]item.Stack, 0, 6)
-	held, offHand := p.HeldItems()
-	if _, ok := offHand.Enchantment(enchantment.Mending{}); ok && offHand.Durability() < offHand.MaxDurability() {
-		mendingItems = append(mendingItems, offHand)
-	}
-	if _, ok := held.Enchantment(enchantment.Mending{}); ok && held.Durability() < held.MaxDurability() {
-		mendingItems = append(mendingItems, held)
-	}
-	for _, i := range p.Armour().Items() {
-		if i.Durability() == i.MaxDurability() {
-			continue
+	for xp > 0 {
+		mendingItems := make([]item.Stack, 0, 6)
+		held, offHand := p.HeldItems()
+		if _, ok := offHand.Enchantment(enchantment.Mending{}); ok && offHand.Durability() < offHand.MaxDurability() {
+			mendingItems = append(mendingItems, offHand)
+		}
+		if _, ok := held.Enchantment(enchantment.Mending{}); ok && held.Durability() < held.MaxDurability() {
+			mendingItems = append(mendingItems, held)
+		}
+		for _, i := range p.Armour().Items() {
+			if i.Durability() == i.MaxDurability() {
+				continue
+			}
+			if _, ok := i.Enchantment(enchantment.Mending{}); ok {
+				mendingItems = append(mendingItems, i)
+			}
 		}
-		if _, ok := i.Enchantment(enchantment.Mending{}); ok {
-			mendingItems = append(mendingItems, i)
+		length := len(mendingItems)
+		if length == 0 {
+			return xp
 		}
-	}
-	length := len(mendingItems)
-	if length == 0 {
-		return xp
-	}
-	foundItem := mendingItems[rand.Intn(length)]
-	repairAmount := math.Min(float64(foundItem.MaxDurability()-foundItem.Durability()), float64(xp*2))
-	repairedItem := foundItem.WithDurability(foundItem.Durability() + int(repairAmount))
-	if repairAmount >= 2 {
-		// Mending removes 1 experience point for every 2 durability points. If the repaired durability is less than 2,
-		// then no experience is removed.
+		foundItem := mendingItems[rand.Intn(length)]
+		repairAmount := math.Min(float64(foundItem.MaxDurability()-foundItem.Durability()), float64(xp*2))
+		repairedItem := foundItem.WithDurability(foundItem.Durability() + int(repairAmount))
+		// Mending removes 1 experience point for every 2 durability points, rounding up, so that every repair costs
+		// at least 1 experience point.
 		xp -= int(math.Ceil(repairAmount / 2))
-	}
-	if offHand.Equal(foundItem) {
-		p.SetHeldItems(held, repairedItem)
-	} else if held.Equal(foundItem) {
-		p.SetHeldItems(repairedItem, offHand)
-	} else if slot, ok := p.Armour().Inventory().First(foundItem); ok {
-		_ = p.Armour().Inventory().SetItem(slot, repairedItem)
+
+		if offHand.Equal(foundItem) {
+			p.SetHeldItems(held, repairedItem)
+		} else if held.Equal(foundItem) {
+			p.SetHeldItems(repairedItem, offHand)
+		} else if slot, ok := p.Armour().Inventory().First(foundItem); ok {
+			_ = p.Armour().Inventory().SetItem(slot, repairedItem)
+		}
 	}
 	return xp
 }
@@ -2225,7 +3035,7 @@ func (p *Player) Drop(s item.Stack) int {
 	e.SetVelocity(p.Rotation().Vec3().Mul(0.4))
 
 	ctx := event.C()
-	if p.Handler().HandleItemDrop(ctx, e); ctx.Cancelled() {
+	if p.h.HandleItemDrop(ctx, e); ctx.Cancelled() {
 		return 0
 	}
 	p.World().AddEntity(e)
@@ -2274,9 +3084,15 @@ func (p *Player) Tick(w *world.World, current int64) {
 		return
 	}
 	if p.lastTickedWorld != w {
-		p.Handler().HandleChangeWorld(p.lastTickedWorld, w)
+		p.h.HandleChangeWorld(p.lastTickedWorld, w)
 	}
 	p.lastTickedWorld = w
+	if r, ok := p.Riding(); ok {
+		p.tickRiding(w, r)
+	}
+	if e, ok := p.Spectating(); ok {
+		p.tickSpectating(w, e)
+	}
 	if _, ok := w.Liquid(cube.PosFromVec3(p.Position())); !ok {
 		p.StopSwimming()
 		if _, ok := p.Armour().Helmet().Item().(item.TurtleShell); ok {
@@ -2296,6 +3112,7 @@ func (p *Player) Tick(w *world.World, current int64) {
 
 	p.checkBlockCollisions(p.vel.Load(), w)
 	p.onGround.Store(p.checkOnGround(w))
+	p.tickFreezing(current)
 
 	p.effects.Tick(p)
 
@@ -2310,6 +3127,7 @@ func (p *Player) Tick(w *world.World, current int64) {
 	if !p.AttackImmune() && p.insideOfSolid(w) {
 		p.Hurt(1, entity.SuffocationDamageSource{})
 	}
+	p.tickBorder(w, current)
 
 	if p.OnFireDuration() > 0 {
 		p.fireTicks.Sub(1)
@@ -2350,6 +3168,55 @@ func (p *Player) Tick(w *world.World, current int64) {
 	}
 }
 
+// borderRenderRadius is the distance from a player within which the border of a world is shown to it.
+const borderRenderRadius = 6
+
+// tickBorder deals damage to the player if it is too far outside the border of the world passed and shows the
+// border to the player if it is close to it.
+func (p *Player) tickBorder(w *world.World, current int64) {
+	b := w.Border()
+	if current%20 == 0 && p.GameMode().AllowsTakingDamage() && !p.AttackImmune() {
+		if dmg := b.Damage(p.Position()); dmg > 0 {
+			p.Hurt(dmg, entity.BorderDamageSource{})
+		}
+	}
+	if current%10 != 0 || !b.Warning(p.Position()) {
+		return
+	}
+	// Bedrock Edition has no world border of its own, so the border is shown to the player as a wall of
+	// particles around it.
+	colour := color.RGBA{R: 0x20, G: 0xa0, B: 0xff, A: 0xff}
+	if _, resizing := b.Resizing(); resizing {
+		colour = color.RGBA{R: 0x40, G: 0xff, B: 0x80, A: 0xff}
+		if b.TargetSize() < b.Size() {
+			colour = color.RGBA{R: 0xff, G: 0x30, B: 0x30, A: 0xff}
+		}
+	}
+	minimum, maximum := b.Bounds()
+	pos, s := p.Position(), p.session()
+	const radius = borderRenderRadius
+	for _, x := range []float64{minimum[0], maximum[0]} {
+		if math.Abs(pos[0]-x) > radius {
+			continue
+		}
+		for z := math.Max(math.Floor(pos[2])-radius, minimum[1]); z <= math.Min(pos[2]+radius, maximum[1]); z++ {
+			for y := math.Floor(pos[1]) - 2; y <= pos[1]+3; y++ {
+				s.ViewParticle(mgl64.Vec3{x, y, z}, particle.Dust{Colour: colour})
+			}
+		}
+	}
+	for _, z := range []float64{minimum[1], maximum[1]} {
+		if math.Abs(pos[2]-z) > radius {
+			continue
+		}
+		for x := math.Max(math.Floor(pos[0])-radius, minimum[0]); x <= math.Min(pos[0]+radius, maximum[0]); x++ {
+			for y := math.Floor(pos[1]) - 2; y <= pos[1]+3; y++ {
+				s.ViewParticle(mgl64.Vec3{x, y, z}, particle.Dust{Colour: colour})
+			}
+		}
+	}
+}
+
 // tickAirSupply tick's the player's air supply, consuming it when underwater, and replenishing it when out of water.
 func (p *Player) tickAirSupply(w *world.World) {
 	if !p.canBreathe(w) {
@@ -2380,24 +3247,19 @@ func (p *Player) tickAirSupply(w *world.World) {
 // is full enough.
 func (p *Player) tickFood(w *world.World) {
 	p.hunger.foodTick++
-	if p.hunger.foodTick >= 80 {
-		p.hunger.foodTick = 0
-	}
+	tick, r := p.hunger.foodTick, p.Regeneration()
 
-	if p.hunger.foodTick%10 == 0 && (p.hunger.canQuicklyRegenerate() || w.Difficulty().FoodRegenerates()) {
-		if w.Difficulty().FoodRegenerates() {
-			p.AddFood(1)
-		}
-		if p.hunger.foodTick%20 == 0 {
-			p.regenerate(false)
-		}
+	peaceful := w.Difficulty().FoodRegenerates()
+	if peaceful && tick%10 == 0 {
+		p.AddFood(1)
 	}
-	if p.hunger.foodTick == 0 {
-		if p.hunger.canRegenerate() {
-			p.regenerate(true)
-		} else if p.hunger.starving() {
-			p.starve(w)
-		}
+	if (peaceful || p.hunger.canQuicklyRegenerate()) && r.due(r.SaturatedInterval, tick) {
+		p.regenerate(r.Amount, 0)
+	}
+	if p.hunger.canRegenerate() && r.due(r.Interval, tick) {
+		p.regenerate(r.Amount, r.Exhaustion)
+	} else if p.hunger.starving() && tick%80 == 0 {
+		p.starve(w)
 	}
 
 	if !p.hunger.canSprint() {
@@ -2405,14 +3267,15 @@ func (p *Player) tickFood(w *world.World) {
 	}
 }
 
-// regenerate attempts to regenerate half a heart of health, typically caused by a full food bar.
-func (p *Player) regenerate(exhaust bool) {
-	if p.Health() == p.MaxHealth() {
+// regenerate attempts to regenerate health, typically caused by a full food bar, and exhausts the player by
+// the amount passed.
+func (p *Player) regenerate(health, exhaustion float64) {
+	if p.Health() == p.MaxHealth() || !world.GameRuleNaturalRegeneration.Value(p.World()) {
 		return
 	}
-	p.Heal(1, entity.FoodHealingSource{})
-	if exhaust {
-		p.Exhaust(6)
+	p.Heal(health, entity.FoodHealingSource{})
+	if exhaustion > 0 {
+		p.Exhaust(exhaustion)
 	}
 }
 
@@ -2503,27 +3366,14 @@ func (p *Player) checkBlockCollisions(vel mgl64.Vec3, w *world.World) {
 	entityBBox := p.Type().BBox(p).Translate(p.Position())
 	deltaX, deltaY, deltaZ := vel[0], vel[1], vel[2]
 
-	p.checkEntityInsiders(w, entityBBox)
-
-	grown := entityBBox.Extend(vel).Grow(0.25)
-	min, max := grown.Min(), grown.Max()
-	minX, minY, minZ := int(math.Floor(min[0])), int(math.Floor(min[1])), int(math.Floor(min[2]))
-	maxX, maxY, maxZ := int(math.Ceil(max[0])), int(math.Ceil(max[1])), int(math.Ceil(max[2]))
-
-	// A prediction of one BBox per block, plus an additional 2, in case
-	blocks := make([]cube.BBox, 0, (maxX-minX)*(maxY-minY)*(maxZ-minZ)+2)
-	for y := minY; y <= maxY; y++ {
-		for x := minX; x <= maxX; x++ {
-			for z := minZ; z <= maxZ; z++ {
-				pos := cube.Pos{x, y, z}
-				boxes := w.Block(pos).Model().BBox(pos, w)
-				for _, box := range boxes {
-					blocks = append(blocks, box.Translate(pos.Vec3()))
-				}
-			}
-		}
+	if p.GameMode().HasCollision() {
+		// Players without collision, such as those in spectator mode, do not trigger blocks like pressure
+		// plates that they are inside of.
+		p.checkEntityInsiders(w, entityBBox)
 	}
 
+	blocks := blockBBoxes(w, entityBBox.Extend(vel).Grow(0.25))
+
 	// epsilon is the epsilon used for thresholds for change used for change in position and velocity.
 	const epsilon = 0.001
 
@@ -2581,25 +3431,7 @@ func (p *Player) checkEntityInsiders(w *world.World, entityBBox cube.BBox) {
 
 // checkOnGround checks if the player is currently considered to be on the ground.
 func (p *Player) checkOnGround(w *world.World) bool {
-	box := p.Type().BBox(p).Translate(p.Position())
-
-	b := box.Grow(1)
-
-	min, max := cube.PosFromVec3(b.Min()), cube.PosFromVec3(b.Max())
-	for x := min[0]; x <= max[0]; x++ {
-		for z := min[2]; z <= max[2]; z++ {
-			for y := min[1]; y < max[1]; y++ {
-				pos := cube.Pos{x, y, z}
-				boxList := w.Block(pos).Model().BBox(pos, w)
-				for _, bb := range boxList {
-					if bb.GrowVec3(mgl64.Vec3{0, 0.05}).Translate(pos.Vec3()).IntersectsWith(box) {
-						return true
-					}
-				}
-			}
-		}
-	}
-	return false
+	return p.onGroundAt(w, p.Position())
 }
 
 // Scale returns the scale modifier of the Player. The default value for a normal scale is 1. A scale of 0
@@ -2637,6 +3469,25 @@ func (p *Player) PlaySound(sound world.Sound) {
 	p.session().PlaySound(sound)
 }
 
+// playSound plays a world.Sound caused by the player at a position in its world. If the player is vanished,
+// the sound is only played to viewers that can see the player.
+func (p *Player) playSound(pos mgl64.Vec3, s world.Sound) {
+	w := p.World()
+	if !p.Vanished() {
+		p.playSound(pos, s)
+		return
+	}
+	ctx := event.C()
+	if w.Handler().HandleSound(ctx, s, pos); ctx.Cancelled() {
+		return
+	}
+	for _, viewer := range w.Viewers(pos) {
+		if v, ok := viewer.(*session.Session); ok && v.CanSee(p) {
+			v.ViewSound(pos, s)
+		}
+	}
+}
+
 // ShowParticle shows a particle that only this Player can see. Unlike World.AddParticle, it is not broadcast
 // to players around it.
 func (p *Player) ShowParticle(pos mgl64.Vec3, particle world.Particle) {
@@ -2666,14 +3517,14 @@ func (p *Player) EditSign(pos cube.Pos, frontText, backText string) error {
 
 	ctx := event.C()
 	if frontText != sign.Front.Text {
-		if p.Handler().HandleSignEdit(ctx, true, sign.Front.Text, frontText); ctx.Cancelled() {
+		if p.h.HandleSignEdit(ctx, true, sign.Front.Text, frontText); ctx.Cancelled() {
 			p.resendBlock(pos, w)
 			return nil
 		}
 		sign.Front.Text = frontText
 		sign.Front.Owner = p.XUID()
 	} else {
-		if p.Handler().HandleSignEdit(ctx, false, sign.Back.Text, backText); ctx.Cancelled() {
+		if p.h.HandleSignEdit(ctx, false, sign.Back.Text, backText); ctx.Cancelled() {
 			p.resendBlock(pos, w)
 			return nil
 		}
@@ -2694,7 +3545,7 @@ func (p *Player) TurnLecternPage(pos cube.Pos, page int) error {
 	}
 
 	ctx := event.C()
-	if p.Handler().HandleLecternPageTurn(ctx, pos, lectern.Page, &page); ctx.Cancelled() {
+	if p.h.HandleLecternPageTurn(ctx, pos, lectern.Page, &page); ctx.Cancelled() {
 		return nil
 	}
 
@@ -2703,6 +3554,50 @@ func (p *Player) TurnLecternPage(pos cube.Pos, page int) error {
 	return nil
 }
 
+// UpdateStructureBlock replaces the structure block at the cube.Pos passed with the structure block passed, as a
+// result of the player editing it. If trigger is true, the structure block saves or loads its structure
+// afterwards. If no structure block is present, an error is returned. Players that are not in creative mode
+// cannot edit structure blocks.
+func (p *Player) UpdateStructureBlock(pos cube.Pos, s block.StructureBlock, trigger bool) error {
+	w := p.World()
+	if _, ok := w.Block(pos).(block.StructureBlock); !ok {
+		return fmt.Errorf("update structure block: no structure block at position %v", pos)
+	}
+	if !p.GameMode().CreativeInventory() {
+		p.resendBlock(pos, w)
+		return nil
+	}
+	w.SetBlock(pos, s, nil)
+	if trigger {
+		if err := s.Trigger(pos, w); err != nil {
+			p.Message(text.Colourf("<red>%v</red>", err))
+		}
+	}
+	return nil
+}
+
+// UpdateCommandBlock replaces the command block at the cube.Pos passed with the command block passed, as a
+// result of the player editing it. Impulse and repeating command blocks that become active as a result are
+// triggered. If no command block is present, an error is returned. Players that are not in creative mode or
+// that do not have the block.CommandBlockPermission cannot edit command blocks.
+func (p *Player) UpdateCommandBlock(pos cube.Pos, c block.CommandBlock) error {
+	w := p.World()
+	old, ok := w.Block(pos).(block.CommandBlock)
+	if !ok {
+		return fmt.Errorf("update command block: no command block at position %v", pos)
+	}
+	if !p.GameMode().CreativeInventory() || !p.HasPermission(block.CommandBlockPermission) {
+		p.resendBlock(pos, w)
+		return nil
+	}
+	c.Facing, c.Powered, c.SuccessCount = old.Facing, old.Powered, old.SuccessCount
+	w.SetBlock(pos, c, nil)
+	if c.Active() && (!old.Active() || c.Type != old.Type) {
+		c.Trigger(pos, w)
+	}
+	return nil
+}
+
 // updateState updates the state of the player to all viewers of the player.
 func (p *Player) updateState() {
 	for _, v := range p.viewers() {
@@ -2736,11 +3631,15 @@ func (p *Player) PunchAir() {
 		return
 	}
 	ctx := event.C()
-	if p.Handler().HandlePunchAir(ctx); ctx.Cancelled() {
+	sinceLast := time.Since(p.lastPunchAir.Swap(time.Now()))
+	if p.h.HandleLeftClickAir(ctx, sinceLast); ctx.Cancelled() {
+		return
+	}
+	if p.h.HandlePunchAir(ctx); ctx.Cancelled() {
 		return
 	}
 	p.SwingArm()
-	p.World().PlaySound(p.Position(), sound.Attack{})
+	p.playSound(p.Position(), sound.Attack{})
 }
 
 // damageItem damages the item stack passed with the damage passed and returns the new stack. If the item
@@ -2751,14 +3650,14 @@ func (p *Player) damageItem(s item.Stack, d int) item.Stack {
 		return s
 	}
 	ctx := event.C()
-	if p.Handler().HandleItemDamage(ctx, s, d); ctx.Cancelled() {
+	if p.h.HandleItemDamage(ctx, s, d); ctx.Cancelled() {
 		return s
 	}
 	if e, ok := s.Enchantment(enchantment.Unbreaking{}); ok {
 		d = (enchantment.Unbreaking{}).Reduce(s.Item(), e.Level(), d)
 	}
 	if s = s.Damage(d); s.Empty() {
-		p.World().PlaySound(p.Position(), sound.ItemBreak{})
+		p.playSound(p.Position(), sound.ItemBreak{})
 	}
 	return s
 }
@@ -2837,7 +3736,10 @@ func (p *Player) close(msg string) {
 	if p.Dead() && p.session() != nil {
 		p.Respawn()
 	}
-	p.h.Swap(NopHandler{}).HandleQuit()
+	p.h.Close(nil, func(h Handler, _ *event.Context) {
+		h.HandleQuit()
+	}, nil)
+	p.Dismount()
 
 	if s := p.s.Swap(nil); s != nil {
 		s.Disconnect(msg)
@@ -2855,7 +3757,7 @@ func (p *Player) load(data Data) {
 	p.yaw.Store(data.Yaw)
 	p.pitch.Store(data.Pitch)
 
-	p.health.SetMaxHealth(data.MaxHealth)
+	p.health.SetMaxHealth(p.attributes.SetBase(attribute.MaxHealth, data.MaxHealth))
 	p.health.AddHealth(data.Health - p.Health())
 	p.session().SendHealth(p.health)
 
@@ -2914,7 +3816,7 @@ func (p *Player) Data() Data {
 		Yaw:             yaw,
 		Pitch:           pitch,
 		Health:          p.Health(),
-		MaxHealth:       p.MaxHealth(),
+		MaxHealth:       p.attributes.Base(attribute.MaxHealth),
 		Hunger:          p.hunger.foodLevel,
 		Experience:      p.Experience(),
 		EnchantmentSeed: p.EnchantmentSeed(),
@@ -2991,9 +3893,21 @@ func (p *Player) useContext() *item.UseContext {
 	}
 }
 
-// Handler returns the Handler of the player.
+// Handler returns the Handler last passed to Handle, or NopHandler if none was passed. Handlers subscribed
+// using Subscribe or SubscribeAsync are not returned. Use Events to pass events to all Handlers.
 func (p *Player) Handler() Handler {
-	return p.h.Load()
+	p.handleMu.Lock()
+	defer p.handleMu.Unlock()
+	if p.handler == nil {
+		return NopHandler{}
+	}
+	return p.handler
+}
+
+// Events returns a Handler that passes events to all Handlers subscribed to the player, including the one
+// passed to Handle, in the order of their event.Priority.
+func (p *Player) Events() Handler {
+	return &p.h
 }
 
 // broadcastItems broadcasts the items held to viewers.
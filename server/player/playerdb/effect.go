@@ -10,10 +10,12 @@ func effectsToData(effects []effect.Effect) []jsonEffect {
 			continue
 		}
 		data[key] = jsonEffect{
-			ID:       id,
-			Duration: eff.Duration(),
-			Level:    eff.Level(),
-			Ambient:  eff.Ambient(),
+			ID:              id,
+			Duration:        eff.Duration(),
+			Level:           eff.Level(),
+			Ambient:         eff.Ambient(),
+			ParticlesHidden: eff.ParticlesHidden(),
+			Infinite:        eff.Infinite(),
 		}
 	}
 	return data
@@ -28,14 +30,19 @@ func dataToEffects(data []jsonEffect) []effect.Effect {
 		}
 		switch eff := e.(type) {
 		case effect.LastingType:
+			effects[i] = effect.New(eff, d.Level, d.Duration)
+			if d.Infinite {
+				effects[i] = effect.NewInfinite(eff, d.Level)
+			}
 			if d.Ambient {
-				effects[i] = effect.NewAmbient(eff, d.Level, d.Duration)
-				continue
+				effects[i] = effects[i].WithAmbient()
 			}
-			effects[i] = effect.New(eff, d.Level, d.Duration)
 		default:
 			effects[i] = effect.NewInstant(eff, d.Level)
 		}
+		if d.ParticlesHidden {
+			effects[i] = effects[i].WithoutParticles()
+		}
 	}
 	return effects
 }
@@ -9,7 +9,7 @@ import (
 	"time"
 )
 
-func (p *Provider) fromJson(d jsonData, lookupWorld func(world.Dimension) *world.World) player.Data {
+func fromJson(d jsonData, lookupWorld func(world.Dimension) *world.World) player.Data {
 	dim, _ := world.DimensionByID(int(d.Dimension))
 	mode, _ := world.GameModeByID(int(d.GameMode))
 	data := player.Data{
@@ -36,13 +36,15 @@ func (p *Provider) fromJson(d jsonData, lookupWorld func(world.Dimension) *world
 		FallDistance:        d.FallDistance,
 		Inventory:           dataToInv(d.Inventory),
 		EnderChestInventory: make([]item.Stack, 27),
-		World:               lookupWorld(dim),
+	}
+	if lookupWorld != nil {
+		data.World = lookupWorld(dim)
 	}
 	decodeItems(d.EnderChestInventory, data.EnderChestInventory)
 	return data
 }
 
-func (p *Provider) toJson(d player.Data) jsonData {
+func toJson(d player.Data) jsonData {
 	dim, _ := world.DimensionID(d.World.Dimension())
 	mode, _ := world.GameModeID(d.GameMode)
 	return jsonData{
@@ -111,8 +113,10 @@ type jsonSlot struct {
 }
 
 type jsonEffect struct {
-	ID       int
-	Level    int
-	Duration time.Duration
-	Ambient  bool
+	ID              int
+	Level           int
+	Duration        time.Duration
+	Ambient         bool
+	ParticlesHidden bool
+	Infinite        bool
 }
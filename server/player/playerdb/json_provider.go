@@ -0,0 +1,66 @@
+package playerdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/google/uuid"
+	"os"
+	"path/filepath"
+)
+
+// JSONProvider is a player data provider that stores the data of every player in a separate JSON file in a
+// directory. The files are named after the UUID of the player, such as
+// '4a5b2c1d-0000-0000-0000-000000000000.json', and may be read and edited by other programs while the player
+// is not online.
+type JSONProvider struct {
+	dir string
+}
+
+// NewJSONProvider creates a new player data provider that saves and loads data using JSON files in the
+// directory passed. The directory is created if it does not yet exist.
+func NewJSONProvider(dir string) (*JSONProvider, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("create player data directory: %w", err)
+	}
+	return &JSONProvider{dir: dir}, nil
+}
+
+// Save ...
+func (p *JSONProvider) Save(id uuid.UUID, d player.Data) error {
+	b, err := json.MarshalIndent(toJson(d), "", "\t")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that the data is never left half-written if the server stops while
+	// saving.
+	tmp := p.path(id) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path(id))
+}
+
+// Load ...
+func (p *JSONProvider) Load(id uuid.UUID, world func(world.Dimension) *world.World) (player.Data, error) {
+	b, err := os.ReadFile(p.path(id))
+	if err != nil {
+		return player.Data{}, err
+	}
+	var d jsonData
+	if err := json.Unmarshal(b, &d); err != nil {
+		return player.Data{}, err
+	}
+	return fromJson(d, world), nil
+}
+
+// Close ...
+func (p *JSONProvider) Close() error {
+	return nil
+}
+
+// path returns the path of the JSON file holding the data of the player with the UUID passed.
+func (p *JSONProvider) path(id uuid.UUID) string {
+	return filepath.Join(p.dir, id.String()+".json")
+}
@@ -32,7 +32,7 @@ func NewProvider(path string) (*Provider, error) {
 
 // Save ...
 func (p *Provider) Save(id uuid.UUID, d player.Data) error {
-	b, err := json.Marshal(p.toJson(d))
+	b, err := json.Marshal(toJson(d))
 	if err != nil {
 		return err
 	}
@@ -50,7 +50,7 @@ func (p *Provider) Load(id uuid.UUID, world func(world.Dimension) *world.World)
 	if err != nil {
 		return player.Data{}, err
 	}
-	return p.fromJson(d, world), nil
+	return fromJson(d, world), nil
 }
 
 // Close ...
@@ -2,6 +2,7 @@ package player
 
 import (
 	"errors"
+	"fmt"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/google/uuid"
 	"io"
@@ -20,6 +21,23 @@ type Provider interface {
 	io.Closer
 }
 
+// Edit loads the Data of the player with the UUID passed from the Provider, passes it to the function f and
+// saves the Data afterwards. Edit may be used to change the data of players that are not online, such as to
+// clear their inventory. Data edited while the player is online is overwritten when the player leaves. The
+// world function passed is used to look up the World of the player, like Provider.Load. If nil, the World of
+// the Data is left nil and the player's dimension is not preserved.
+func Edit(p Provider, id uuid.UUID, world func(world.Dimension) *world.World, f func(d *Data)) error {
+	d, err := p.Load(id, world)
+	if err != nil {
+		return fmt.Errorf("edit player data: %w", err)
+	}
+	f(&d)
+	if err := p.Save(id, d); err != nil {
+		return fmt.Errorf("edit player data: %w", err)
+	}
+	return nil
+}
+
 // Compile time check to make sure NopProvider implements Provider.
 var _ Provider = (*NopProvider)(nil)
 
@@ -0,0 +1,85 @@
+package scoreboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Objective is a scoreboard objective that shows a score for every player, either under the name tags of
+// players or next to their names in the player list. The scores are obtained from the ScoreProvider of the
+// Objective, which may be different for every player that the Objective is shown to.
+type Objective struct {
+	name     string
+	provider ScoreProvider
+}
+
+// NewObjective returns a new Objective with the display name passed, such as 'Kills', that obtains its scores
+// from the ScoreProvider passed. The name is formatted according to the rules of fmt.Sprintln.
+func NewObjective(provider ScoreProvider, name ...any) Objective {
+	return Objective{name: strings.TrimSuffix(fmt.Sprintln(name...), "\n"), provider: provider}
+}
+
+// Name returns the display name of the Objective, as passed to NewObjective.
+func (o Objective) Name() string {
+	return o.name
+}
+
+// Score returns the score of the player passed. False is returned if the player has no score, in which case
+// no score is shown for it.
+func (o Objective) Score(target world.Entity) (int, bool) {
+	if o.provider == nil {
+		return 0, false
+	}
+	return o.provider.Score(target)
+}
+
+// ScoreProvider provides the scores of the players shown in an Objective.
+type ScoreProvider interface {
+	// Score returns the score of the player passed. False is returned if the player has no score.
+	Score(target world.Entity) (int, bool)
+}
+
+// ScoreProviderFunc is a function that implements ScoreProvider.
+type ScoreProviderFunc func(target world.Entity) (int, bool)
+
+// Score ...
+func (f ScoreProviderFunc) Score(target world.Entity) (int, bool) {
+	return f(target)
+}
+
+// DisplaySlot is a slot in which an Objective may be displayed.
+type DisplaySlot struct {
+	displaySlot
+}
+
+// BelowName returns the display slot under the name tags of players.
+func BelowName() DisplaySlot {
+	return DisplaySlot{0}
+}
+
+// List returns the display slot next to the names of players in the player list.
+func List() DisplaySlot {
+	return DisplaySlot{1}
+}
+
+// DisplaySlots returns all display slots in which an Objective may be displayed.
+func DisplaySlots() []DisplaySlot {
+	return []DisplaySlot{BelowName(), List()}
+}
+
+type displaySlot uint8
+
+// Uint8 returns the display slot as a uint8.
+func (s displaySlot) Uint8() uint8 {
+	return uint8(s)
+}
+
+// String returns the name of the display slot, which is either 'belowname' or 'list'.
+func (s displaySlot) String() string {
+	if s == 0 {
+		return "belowname"
+	}
+	return "list"
+}
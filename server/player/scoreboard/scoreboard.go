@@ -11,15 +11,17 @@ import (
 // Scoreboard implements the io.Writer and io.StringWriter interfaces. fmt.Fprintf and fmt.Fprint may be used
 // to write formatted text to the scoreboard.
 type Scoreboard struct {
-	name    string
-	lines   []string
-	padding bool
+	name         string
+	lines        []string
+	padding      bool
+	placeholders map[string]string
 }
 
 // New returns a new scoreboard with the display name passed. Once returned, lines may be added to the
 // scoreboard to add text to it. The name is formatted according to the rules of fmt.Sprintln.
 // Changing the scoreboard after sending it to a player will not update the scoreboard of the player
-// automatically: Player.SendScoreboard() must be called again to update it.
+// automatically: Player.SendScoreboard() must be called again to update it. Only the lines that changed are
+// sent again when doing so.
 func New(name ...any) *Scoreboard {
 	return &Scoreboard{name: strings.TrimSuffix(fmt.Sprintln(name...), "\n"), padding: true}
 }
@@ -69,6 +71,23 @@ func (board *Scoreboard) Remove(index int) {
 	board.lines = append(board.lines[:index], board.lines[index+1:]...)
 }
 
+// SetPlaceholder sets the value of a placeholder in the scoreboard. Every occurrence of the name of the
+// placeholder between curly braces, such as {kills} for the placeholder "kills", is replaced with the value
+// passed when the lines of the scoreboard are returned. Because only lines that changed are sent again, a
+// scoreboard may be sent again after changing a placeholder to efficiently update the lines holding it.
+func (board *Scoreboard) SetPlaceholder(name, value string) {
+	if board.placeholders == nil {
+		board.placeholders = make(map[string]string)
+	}
+	board.placeholders[name] = value
+}
+
+// RemovePlaceholder removes a placeholder previously set using SetPlaceholder. Occurrences of the placeholder
+// in the scoreboard are no longer replaced.
+func (board *Scoreboard) RemovePlaceholder(name string) {
+	delete(board.placeholders, name)
+}
+
 // RemovePadding removes the padding of one space that is added to the start of every line.
 func (board *Scoreboard) RemovePadding() {
 	board.padding = false
@@ -77,6 +96,16 @@ func (board *Scoreboard) RemovePadding() {
 // Lines returns the data of the Scoreboard as a slice of strings.
 func (board *Scoreboard) Lines() []string {
 	lines := slices.Clone(board.lines)
+	if len(board.placeholders) > 0 {
+		pairs := make([]string, 0, len(board.placeholders)*2)
+		for name, value := range board.placeholders {
+			pairs = append(pairs, "{"+name+"}", value)
+		}
+		r := strings.NewReplacer(pairs...)
+		for i, line := range lines {
+			lines[i] = r.Replace(line)
+		}
+	}
 	if board.padding {
 		for i, line := range lines {
 			if len(board.name)-len(line)-2 <= 0 {
@@ -1,6 +1,8 @@
 package skin
 
 import (
+	"encoding/json"
+	"fmt"
 	"image"
 	"image/color"
 )
@@ -21,9 +23,15 @@ type Skin struct {
 	// ModelConfig specifies how the Model field below should be used to form the total skin.
 	ModelConfig ModelConfig
 	// Model holds the raw JSON data that represents the model of the skin. If empty, it means the skin holds
-	// the standard skin data (geometry.humanoid).
-	// TODO: Write a full API for this. The model should be able to be easily modified or created runtime.
+	// the standard skin data (geometry.humanoid). Custom geometry may be set by setting Model to the JSON of
+	// the geometry and ModelConfig.Default to the identifier of the geometry in it.
 	Model []byte
+	// GeometryVersion is the version of the geometry engine that the Model was made for, such as "1.12.0".
+	// It must be set if Model holds custom geometry in a format newer than the legacy geometry format.
+	GeometryVersion string
+	// ArmSize is the size of the arms of the skin, either "wide" or "slim". If empty, the arm size is
+	// derived from the Model by the client.
+	ArmSize string
 
 	// Cape holds the cape of the skin. By default, an empty cape is set in the skin. Cape.Exists() may be
 	// called to check if the cape actually has any data.
@@ -32,6 +40,9 @@ type Skin struct {
 	// Animations holds a list of all animations that the skin has. These animations must be pointed to in the
 	// ModelConfig, in order to display them on the skin.
 	Animations []Animation
+	// AnimationData holds the raw JSON data of the animations that the Model uses, such as animations
+	// of animated geometry parts.
+	AnimationData []byte
 }
 
 // New creates a new skin using the width and height passed. The dimensions passed must be either 64x32,
@@ -46,6 +57,31 @@ func New(width, height int) Skin {
 	}
 }
 
+// Validate checks if the Skin is valid and may be sent to players. An error is returned if the pixel data
+// of the skin, its cape or its animations do not match their dimensions, or if the Model is not valid JSON.
+// Invalid skins may crash clients, so Validate should be called on skins created at runtime before they are
+// set to a player.
+func (s Skin) Validate() error {
+	if len(s.Pix) != s.w*s.h*4 {
+		return fmt.Errorf("skin data has %v bytes, expected %v for %vx%v skin", len(s.Pix), s.w*s.h*4, s.w, s.h)
+	}
+	if c := s.Cape.Bounds().Max; len(s.Cape.Pix) != c.X*c.Y*4 {
+		return fmt.Errorf("cape data has %v bytes, expected %v for %vx%v cape", len(s.Cape.Pix), c.X*c.Y*4, c.X, c.Y)
+	}
+	for i, a := range s.Animations {
+		if b := a.Bounds().Max; len(a.Pix) != b.X*b.Y*4 {
+			return fmt.Errorf("animation %v data has %v bytes, expected %v for %vx%v animation", i, len(a.Pix), b.X*b.Y*4, b.X, b.Y)
+		}
+	}
+	if len(s.Model) != 0 && !json.Valid(s.Model) {
+		return fmt.Errorf("skin model is not valid JSON")
+	}
+	if len(s.AnimationData) != 0 && !json.Valid(s.AnimationData) {
+		return fmt.Errorf("skin animation data is not valid JSON")
+	}
+	return nil
+}
+
 // Bounds returns the bounds of the skin. These are either 64x32, 64x64 or 128, depending on the bounds of the
 // skin of the player.
 func (s Skin) Bounds() image.Rectangle {
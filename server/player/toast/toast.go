@@ -0,0 +1,62 @@
+package toast
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"strings"
+)
+
+// Toast represents a toast notification that may be sent to a player. Toasts are shown at the top of the
+// screen of the player, in the same way as achievements or the loading of resource packs. Unlike chat
+// messages, toasts are queued by the client and disappear automatically, which makes them suitable for
+// announcing unlocks or progress without spamming the chat.
+type Toast struct {
+	title, message string
+	sound          world.Sound
+}
+
+// New returns a new toast using the title passed. The title is formatted according to the formatting rules
+// of fmt.Sprintln, but with no newline at the end.
+// By default, the toast has no message and plays no sound when shown.
+func New(title ...any) Toast {
+	return Toast{title: format(title)}
+}
+
+// Title returns the title of the toast, as passed to New when created.
+func (t Toast) Title() string {
+	return t.title
+}
+
+// WithMessage sets the message of the toast. The text passed will be formatted according to the formatting
+// rules of fmt.Sprintln, but without the newline.
+// The message is shown under the title of the toast.
+// The new Toast with the message is returned.
+func (t Toast) WithMessage(text ...any) Toast {
+	t.message = format(text)
+	return t
+}
+
+// Message returns the message of the toast, as passed to WithMessage. Message returns an empty string if no
+// message was previously set.
+func (t Toast) Message() string {
+	return t.message
+}
+
+// WithSound sets the sound played to the player when the toast is shown, such as sound.LevelUp for an
+// achievement-style popup. Passing nil removes the sound from the toast.
+// The new Toast with the sound is returned.
+func (t Toast) WithSound(s world.Sound) Toast {
+	t.sound = s
+	return t
+}
+
+// Sound returns the sound played when the toast is shown. Sound returns nil if no sound was set.
+func (t Toast) Sound() world.Sound {
+	return t.sound
+}
+
+// format is a utility function to format a list of values to have spaces between them, but no newline at the
+// end.
+func format(a []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+}
@@ -0,0 +1,78 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileStore is a Store that keeps Metadata in JSON files in a directory. It may be shared by servers that run
+// on the same machine or that have access to the same file system. Metadata that is not taken within the
+// expiry duration of the FileStore is discarded.
+type FileStore struct {
+	dir    string
+	expiry time.Duration
+}
+
+// NewFileStore creates a FileStore that stores Metadata in the directory passed and discards it once it has
+// been stored for longer than the expiry duration passed. The directory is created if it does not yet exist.
+func NewFileStore(dir string, expiry time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("create transfer store directory: %w", err)
+	}
+	return &FileStore{dir: dir, expiry: expiry}, nil
+}
+
+// Put ...
+func (s *FileStore) Put(id uuid.UUID, m Metadata) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("put transfer metadata: %w", err)
+	}
+	// Write to a temporary file first, so that a server taking the Metadata never reads a half-written file.
+	tmp := s.path(id) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("put transfer metadata: %w", err)
+	}
+	if err := os.Rename(tmp, s.path(id)); err != nil {
+		return fmt.Errorf("put transfer metadata: %w", err)
+	}
+	return nil
+}
+
+// Take ...
+func (s *FileStore) Take(id uuid.UUID) (Metadata, error) {
+	path := s.path(id)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("take transfer metadata: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("take transfer metadata: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("take transfer metadata: %w", err)
+	}
+	if time.Since(info.ModTime()) > s.expiry {
+		return nil, nil
+	}
+	var m Metadata
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("take transfer metadata: %w", err)
+	}
+	return m, nil
+}
+
+// path returns the path of the file holding the Metadata of the player with the UUID passed.
+func (s *FileStore) path(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String()+".json")
+}
@@ -0,0 +1,57 @@
+package transfer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore is a Store that keeps Metadata in memory. It may be shared by servers that run in the same
+// process. Metadata that is not taken within the expiry duration of the MemoryStore is discarded.
+type MemoryStore struct {
+	expiry time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]memoryEntry
+}
+
+// memoryEntry is Metadata stored in a MemoryStore along with the time at which it expires.
+type memoryEntry struct {
+	m       Metadata
+	expires time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore that discards Metadata once it has been stored for longer than
+// the expiry duration passed.
+func NewMemoryStore(expiry time.Duration) *MemoryStore {
+	return &MemoryStore{expiry: expiry, entries: make(map[uuid.UUID]memoryEntry)}
+}
+
+// Put ...
+func (s *MemoryStore) Put(id uuid.UUID, m Metadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for other, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, other)
+		}
+	}
+	s.entries[id] = memoryEntry{m: m, expires: now.Add(s.expiry)}
+	return nil
+}
+
+// Take ...
+func (s *MemoryStore) Take(id uuid.UUID) (Metadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	delete(s.entries, id)
+	if !ok || time.Now().After(e.expires) {
+		return nil, nil
+	}
+	return e.m, nil
+}
@@ -0,0 +1,33 @@
+// Package transfer implements metadata that may be attached to the transfer of a player to another server,
+// such as the party that the player is in or a position to teleport the player to. Metadata is stored in a
+// Store shared by the servers of a network, from which the server that the player is transferred to retrieves
+// it when the player joins.
+package transfer
+
+import (
+	"github.com/google/uuid"
+)
+
+// Metadata holds arbitrary data attached to the transfer of a player, mapped by keys.
+type Metadata map[string]string
+
+// Store stores the Metadata of transfers until the player joins the server it was transferred to. To deliver
+// Metadata across servers, the Store must be shared by the servers, for example by storing the Metadata in a
+// database that all servers of a network use.
+type Store interface {
+	// Put stores the Metadata of a transfer of the player with the UUID passed. Metadata stored earlier for the
+	// same player is replaced.
+	Put(id uuid.UUID, m Metadata) error
+	// Take returns the Metadata stored for the player with the UUID passed and removes it from the Store. If no
+	// Metadata was stored, Take returns nil and no error.
+	Take(id uuid.UUID) (Metadata, error)
+}
+
+// Compile time check to make sure NopStore implements Store.
+var _ Store = NopStore{}
+
+// NopStore is a Store that does not store any Metadata. Metadata put in a NopStore is discarded.
+type NopStore struct{}
+
+func (NopStore) Put(uuid.UUID, Metadata) error    { return nil }
+func (NopStore) Take(uuid.UUID) (Metadata, error) { return nil, nil }
@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// API is the surface through which a Plugin interacts with the server. Every
+// Plugin is passed its own API when it is enabled. Commands and player
+// handlers registered through the API are removed when the Plugin is
+// disabled.
+type API struct {
+	l  *Loader
+	pl *loaded
+
+	mu       sync.Mutex
+	commands []cmd.Command
+	handlers []func(p *player.Player) player.Handler
+}
+
+// Server returns the server.Server that the Plugin was loaded into.
+func (a *API) Server() *server.Server {
+	return a.l.srv
+}
+
+// Worlds returns the overworld, nether and end of the server. Worlds of
+// other dimensions may be obtained using server.Server.Dimension.
+func (a *API) Worlds() []*world.World {
+	return []*world.World{a.l.srv.World(), a.l.srv.Nether(), a.l.srv.End()}
+}
+
+// Log returns a slog.Logger that adds the name of the Plugin to all records
+// logged.
+func (a *API) Log() *slog.Logger {
+	return a.l.log.With("plugin", a.pl.info.Name)
+}
+
+// RegisterCommand registers a cmd.Command using cmd.Register. The command is
+// unregistered when the Plugin is disabled.
+func (a *API) RegisterCommand(c cmd.Command) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cmd.Register(c)
+	a.commands = append(a.commands, c)
+}
+
+// HandlePlayers registers a function that is called for every player that
+// joins after the Plugin was enabled, returning the player.Handler that
+// handles the events of the player for the Plugin. The handlers of all
+// plugins are called in the order that the plugins were enabled in, even if
+// the event was cancelled by one of them: ctx.Cancelled() may be checked to
+// find out if this is the case. f may return nil if events of a player need
+// not be handled.
+func (a *API) HandlePlayers(f func(p *player.Player) player.Handler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.handlers = append(a.handlers, f)
+}
+
+// Go runs f in a new goroutine. If f panics, the Plugin is disabled instead of
+// the server crashing.
+func (a *API) Go(f func()) {
+	go a.l.call(a.pl, f)
+}
+
+// playerHandlers returns the player.Handlers of the Plugin for the player
+// passed.
+func (a *API) playerHandlers(p *player.Player) []player.Handler {
+	a.mu.Lock()
+	fs := a.handlers
+	a.mu.Unlock()
+
+	handlers := make([]player.Handler, 0, len(fs))
+	for _, f := range fs {
+		var h player.Handler
+		if !a.l.call(a.pl, func() { h = f(p) }) || h == nil {
+			continue
+		}
+		handlers = append(handlers, h)
+	}
+	return handlers
+}
+
+// unregister unregisters all commands and player handlers registered through
+// the API.
+func (a *API) unregister() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, c := range a.commands {
+		cmd.Unregister(c)
+	}
+	a.commands, a.handlers = nil, nil
+}
@@ -0,0 +1,250 @@
+package plugin
+
+import (
+	"net"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+)
+
+// handler is a player.Handler that passes the events of a player to the
+// handlers of all plugins that registered one for the player.
+type handler struct {
+	l        *Loader
+	handlers []pluginHandler
+}
+
+// pluginHandler is a player.Handler registered by a plugin.
+type pluginHandler struct {
+	pl      *loaded
+	h       player.Handler
+	enables int64
+}
+
+// Compile time check to make sure handler implements player.Handler.
+var _ player.Handler = (*handler)(nil)
+
+// each calls f for the handlers of all plugins that were not disabled since
+// the handlers were registered, in the order that the plugins were enabled in.
+// A plugin whose handler panics is disabled.
+func (h *handler) each(f func(ph player.Handler)) {
+	for _, ph := range h.handlers {
+		if ph.pl.enabled.Load() && ph.pl.enables.Load() == ph.enables {
+			h.l.call(ph.pl, func() { f(ph.h) })
+		}
+	}
+}
+
+// HandleMove ...
+func (h *handler) HandleMove(ctx *event.Context, newPos mgl64.Vec3, newYaw, newPitch float64) {
+	h.each(func(ph player.Handler) { ph.HandleMove(ctx, newPos, newYaw, newPitch) })
+}
+
+// HandleMovementViolation ...
+func (h *handler) HandleMovementViolation(ctx *event.Context, v player.MovementViolation) {
+	h.each(func(ph player.Handler) { ph.HandleMovementViolation(ctx, v) })
+}
+
+// HandleJump ...
+func (h *handler) HandleJump() {
+	h.each(func(ph player.Handler) { ph.HandleJump() })
+}
+
+// HandleTeleport ...
+func (h *handler) HandleTeleport(ctx *event.Context, pos mgl64.Vec3) {
+	h.each(func(ph player.Handler) { ph.HandleTeleport(ctx, pos) })
+}
+
+// HandleChangeWorld ...
+func (h *handler) HandleChangeWorld(before, after *world.World) {
+	h.each(func(ph player.Handler) { ph.HandleChangeWorld(before, after) })
+}
+
+// HandleToggleSprint ...
+func (h *handler) HandleToggleSprint(ctx *event.Context, after bool) {
+	h.each(func(ph player.Handler) { ph.HandleToggleSprint(ctx, after) })
+}
+
+// HandleHeldSlotChange ...
+func (h *handler) HandleHeldSlotChange(ctx *event.Context, from, to int) {
+	h.each(func(ph player.Handler) { ph.HandleHeldSlotChange(ctx, from, to) })
+}
+
+// HandleToggleSneak ...
+func (h *handler) HandleToggleSneak(ctx *event.Context, after bool) {
+	h.each(func(ph player.Handler) { ph.HandleToggleSneak(ctx, after) })
+}
+
+// HandleChat ...
+func (h *handler) HandleChat(ctx *event.Context, message *string) {
+	h.each(func(ph player.Handler) { ph.HandleChat(ctx, message) })
+}
+
+// HandleChatMessage ...
+func (h *handler) HandleChatMessage(ctx *event.Context, msg *chat.Message) {
+	h.each(func(ph player.Handler) { ph.HandleChatMessage(ctx, msg) })
+}
+
+// HandleFoodLoss ...
+func (h *handler) HandleFoodLoss(ctx *event.Context, from int, to *int) {
+	h.each(func(ph player.Handler) { ph.HandleFoodLoss(ctx, from, to) })
+}
+
+// HandleHeal ...
+func (h *handler) HandleHeal(ctx *event.Context, health *float64, src world.HealingSource) {
+	h.each(func(ph player.Handler) { ph.HandleHeal(ctx, health, src) })
+}
+
+// HandleHurt ...
+func (h *handler) HandleHurt(ctx *event.Context, damage *float64, attackImmunity *time.Duration, src world.DamageSource) {
+	h.each(func(ph player.Handler) { ph.HandleHurt(ctx, damage, attackImmunity, src) })
+}
+
+// HandlePreDeath ...
+func (h *handler) HandlePreDeath(ctx *event.Context, src world.DamageSource) {
+	h.each(func(ph player.Handler) { ph.HandlePreDeath(ctx, src) })
+}
+
+// HandleDeath ...
+func (h *handler) HandleDeath(src world.DamageSource, keepInv *bool) {
+	h.each(func(ph player.Handler) { ph.HandleDeath(src, keepInv) })
+}
+
+// HandleDeathMessage ...
+func (h *handler) HandleDeathMessage(src world.DamageSource, message *string) {
+	h.each(func(ph player.Handler) { ph.HandleDeathMessage(src, message) })
+}
+
+// HandleRespawn ...
+func (h *handler) HandleRespawn(pos *mgl64.Vec3, w **world.World) {
+	h.each(func(ph player.Handler) { ph.HandleRespawn(pos, w) })
+}
+
+// HandleRespawnState ...
+func (h *handler) HandleRespawnState(state *player.RespawnState) {
+	h.each(func(ph player.Handler) { ph.HandleRespawnState(state) })
+}
+
+// HandleSkinChange ...
+func (h *handler) HandleSkinChange(ctx *event.Context, skin *skin.Skin) {
+	h.each(func(ph player.Handler) { ph.HandleSkinChange(ctx, skin) })
+}
+
+// HandleEmote ...
+func (h *handler) HandleEmote(ctx *event.Context, emote uuid.UUID) {
+	h.each(func(ph player.Handler) { ph.HandleEmote(ctx, emote) })
+}
+
+// HandleStartBreak ...
+func (h *handler) HandleStartBreak(ctx *event.Context, pos cube.Pos) {
+	h.each(func(ph player.Handler) { ph.HandleStartBreak(ctx, pos) })
+}
+
+// HandleBlockBreak ...
+func (h *handler) HandleBlockBreak(ctx *event.Context, pos cube.Pos, drops *[]item.Stack, xp *int) {
+	h.each(func(ph player.Handler) { ph.HandleBlockBreak(ctx, pos, drops, xp) })
+}
+
+// HandleBlockPlace ...
+func (h *handler) HandleBlockPlace(ctx *event.Context, pos cube.Pos, b world.Block) {
+	h.each(func(ph player.Handler) { ph.HandleBlockPlace(ctx, pos, b) })
+}
+
+// HandleBlockPick ...
+func (h *handler) HandleBlockPick(ctx *event.Context, pos cube.Pos, b world.Block) {
+	h.each(func(ph player.Handler) { ph.HandleBlockPick(ctx, pos, b) })
+}
+
+// HandleItemUse ...
+func (h *handler) HandleItemUse(ctx *event.Context) {
+	h.each(func(ph player.Handler) { ph.HandleItemUse(ctx) })
+}
+
+// HandleItemUseOnBlock ...
+func (h *handler) HandleItemUseOnBlock(ctx *event.Context, pos cube.Pos, face cube.Face, clickPos mgl64.Vec3) {
+	h.each(func(ph player.Handler) { ph.HandleItemUseOnBlock(ctx, pos, face, clickPos) })
+}
+
+// HandleItemUseOnEntity ...
+func (h *handler) HandleItemUseOnEntity(ctx *event.Context, e world.Entity) {
+	h.each(func(ph player.Handler) { ph.HandleItemUseOnEntity(ctx, e) })
+}
+
+// HandleItemConsume ...
+func (h *handler) HandleItemConsume(ctx *event.Context, item item.Stack) {
+	h.each(func(ph player.Handler) { ph.HandleItemConsume(ctx, item) })
+}
+
+// HandleAttackEntity ...
+func (h *handler) HandleAttackEntity(ctx *event.Context, e world.Entity, force, height *float64, critical *bool) {
+	h.each(func(ph player.Handler) { ph.HandleAttackEntity(ctx, e, force, height, critical) })
+}
+
+// HandleExperienceGain ...
+func (h *handler) HandleExperienceGain(ctx *event.Context, amount *int) {
+	h.each(func(ph player.Handler) { ph.HandleExperienceGain(ctx, amount) })
+}
+
+// HandlePunchAir ...
+func (h *handler) HandlePunchAir(ctx *event.Context) {
+	h.each(func(ph player.Handler) { ph.HandlePunchAir(ctx) })
+}
+
+// HandleLeftClickAir ...
+func (h *handler) HandleLeftClickAir(ctx *event.Context, sinceLast time.Duration) {
+	h.each(func(ph player.Handler) { ph.HandleLeftClickAir(ctx, sinceLast) })
+}
+
+// HandleRightClickAir ...
+func (h *handler) HandleRightClickAir(ctx *event.Context, sinceLast time.Duration) {
+	h.each(func(ph player.Handler) { ph.HandleRightClickAir(ctx, sinceLast) })
+}
+
+// HandleSignEdit ...
+func (h *handler) HandleSignEdit(ctx *event.Context, frontSide bool, oldText, newText string) {
+	h.each(func(ph player.Handler) { ph.HandleSignEdit(ctx, frontSide, oldText, newText) })
+}
+
+// HandleLecternPageTurn ...
+func (h *handler) HandleLecternPageTurn(ctx *event.Context, pos cube.Pos, oldPage int, newPage *int) {
+	h.each(func(ph player.Handler) { ph.HandleLecternPageTurn(ctx, pos, oldPage, newPage) })
+}
+
+// HandleItemDamage ...
+func (h *handler) HandleItemDamage(ctx *event.Context, i item.Stack, damage int) {
+	h.each(func(ph player.Handler) { ph.HandleItemDamage(ctx, i, damage) })
+}
+
+// HandleItemPickup ...
+func (h *handler) HandleItemPickup(ctx *event.Context, i *item.Stack) {
+	h.each(func(ph player.Handler) { ph.HandleItemPickup(ctx, i) })
+}
+
+// HandleItemDrop ...
+func (h *handler) HandleItemDrop(ctx *event.Context, e world.Entity) {
+	h.each(func(ph player.Handler) { ph.HandleItemDrop(ctx, e) })
+}
+
+// HandleTransfer ...
+func (h *handler) HandleTransfer(ctx *event.Context, addr *net.UDPAddr) {
+	h.each(func(ph player.Handler) { ph.HandleTransfer(ctx, addr) })
+}
+
+// HandleCommandExecution ...
+func (h *handler) HandleCommandExecution(ctx *event.Context, command cmd.Command, args []string) {
+	h.each(func(ph player.Handler) { ph.HandleCommandExecution(ctx, command, args) })
+}
+
+// HandleQuit ...
+func (h *handler) HandleQuit() {
+	h.each(func(ph player.Handler) { ph.HandleQuit() })
+}
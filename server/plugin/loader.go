@@ -0,0 +1,303 @@
+package plugin
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	goplugin "plugin"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+)
+
+// Loader loads plugins into a server.Server and enables and disables them.
+// A Loader is safe for concurrent use.
+type Loader struct {
+	srv *server.Server
+	log *slog.Logger
+
+	mu sync.Mutex
+	// plugins holds all plugins added to the Loader in the order that they
+	// were added in.
+	plugins []*loaded
+	// enabled holds the plugins currently enabled in the order that they were
+	// enabled in.
+	enabled []*loaded
+}
+
+// loaded is a Plugin added to a Loader.
+type loaded struct {
+	p       Plugin
+	info    Info
+	api     *API
+	enabled atomic.Bool
+	// enables is the number of times that the plugin was enabled. Player
+	// handlers registered while the plugin was enabled previously are no
+	// longer called if it is enabled again.
+	enables atomic.Int64
+}
+
+// NewLoader creates a Loader that loads plugins into the server.Server passed.
+// Errors and panics of plugins are logged to log.
+func NewLoader(srv *server.Server, log *slog.Logger) *Loader {
+	return &Loader{srv: srv, log: log}
+}
+
+// LoadDir discovers all Go plugins with the .so extension in the directory
+// passed and adds them to the Loader in alphabetical order of their file
+// names. The directory is created if it does not yet exist. Plugins that
+// cannot be opened are logged and skipped. LoadDir does not enable the
+// plugins: Enable must be called afterwards.
+func (l *Loader) LoadDir(dir string) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return fmt.Errorf("load plugins: create directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("load plugins: read directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+		p, err := open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			l.log.Error("Error loading plugin.", "file", entry.Name(), "err", err)
+			continue
+		}
+		if err := l.Add(p); err != nil {
+			l.log.Error("Error loading plugin.", "file", entry.Name(), "err", err)
+		}
+	}
+	return nil
+}
+
+// open opens the Go plugin at the path passed and returns the Plugin returned
+// by its NewPlugin function.
+func open(path string) (p Plugin, err error) {
+	plug, err := goplugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := plug.Lookup("NewPlugin")
+	if err != nil {
+		return nil, err
+	}
+	newPlugin, ok := sym.(func() Plugin)
+	if !ok {
+		return nil, fmt.Errorf("NewPlugin has type %T, expected func() plugin.Plugin", sym)
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("NewPlugin panicked: %v", v)
+		}
+	}()
+	if p = newPlugin(); p == nil {
+		return nil, fmt.Errorf("NewPlugin returned nil")
+	}
+	return p, nil
+}
+
+// Add adds a Plugin to the Loader, such as a plugin compiled into the program
+// itself. An error is returned if the Loader already holds a Plugin with the
+// same name. Add does not enable the Plugin: Enable must be called afterwards.
+func (l *Loader) Add(p Plugin) error {
+	info := p.Info()
+	if info.Name == "" {
+		return fmt.Errorf("add plugin: plugin has no name")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := byName(l.plugins, info.Name); ok {
+		return fmt.Errorf("add plugin: plugin %v already added", info.Name)
+	}
+	pl := &loaded{p: p, info: info}
+	pl.api = &API{l: l, pl: pl}
+	l.plugins = append(l.plugins, pl)
+	return nil
+}
+
+// Plugins returns the Info of all plugins added to the Loader in the order
+// that they were added in.
+func (l *Loader) Plugins() []Info {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	infos := make([]Info, 0, len(l.plugins))
+	for _, pl := range l.plugins {
+		infos = append(infos, pl.info)
+	}
+	return infos
+}
+
+// Enabled checks if the plugin with the name passed is currently enabled.
+func (l *Loader) Enabled(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := byName(l.enabled, name)
+	return ok
+}
+
+// Enable enables all plugins added to the Loader that are not yet enabled.
+// Plugins are enabled after the plugins they depend on and otherwise in the
+// order that they were added in. Plugins of which a dependency is missing or
+// could not be enabled are not enabled. Failures are logged.
+func (l *Loader) Enable() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pending := slices.DeleteFunc(slices.Clone(l.plugins), func(pl *loaded) bool { return pl.enabled.Load() })
+	for len(pending) > 0 {
+		progress := false
+		for i := 0; i < len(pending); i++ {
+			pl := pending[i]
+			ready, missing := l.dependenciesEnabled(pl, pending)
+			if !ready && missing == "" {
+				// Some of the dependencies are still pending.
+				continue
+			}
+			pending, progress = slices.Delete(pending, i, i+1), true
+			i--
+
+			if missing != "" {
+				l.log.Error("Could not enable plugin: dependency is not enabled.", "plugin", pl.info.Name, "dependency", missing)
+				continue
+			}
+			l.enable(pl)
+		}
+		if !progress {
+			for _, pl := range pending {
+				l.log.Error("Could not enable plugin: circular dependency.", "plugin", pl.info.Name)
+			}
+			return
+		}
+	}
+}
+
+// dependenciesEnabled checks if all dependencies of a plugin are enabled. If
+// not, the name of a dependency that is neither enabled nor pending is
+// returned, or an empty string if the remaining dependencies are pending.
+func (l *Loader) dependenciesEnabled(pl *loaded, pending []*loaded) (bool, string) {
+	ready := true
+	for _, name := range pl.info.Depends {
+		if _, ok := byName(l.enabled, name); ok {
+			continue
+		}
+		if _, ok := byName(pending, name); !ok {
+			return false, name
+		}
+		ready = false
+	}
+	return ready, ""
+}
+
+// enable enables a plugin, logging any error or panic returned by it.
+func (l *Loader) enable(pl *loaded) {
+	var err error
+	if !l.call(pl, func() { err = pl.p.Enable(pl.api) }) {
+		pl.api.unregister()
+		return
+	}
+	if err != nil {
+		pl.api.unregister()
+		l.log.Error("Could not enable plugin.", "plugin", pl.info.Name, "err", err)
+		return
+	}
+	pl.enables.Add(1)
+	pl.enabled.Store(true)
+	l.enabled = append(l.enabled, pl)
+	if pl.info.Version == "" {
+		l.log.Info("Enabled plugin.", "plugin", pl.info.Name)
+		return
+	}
+	l.log.Info("Enabled plugin.", "plugin", pl.info.Name, "version", pl.info.Version)
+}
+
+// Disable disables all enabled plugins in the reverse order of which they were
+// enabled. Failures are logged.
+func (l *Loader) Disable() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	enabled := slices.Clone(l.enabled)
+	for i := len(enabled) - 1; i >= 0; i-- {
+		l.disable(enabled[i])
+	}
+}
+
+// disable disables a plugin and all enabled plugins that depend on it. The
+// Loader must be locked when calling disable.
+func (l *Loader) disable(pl *loaded) {
+	if !pl.enabled.CompareAndSwap(true, false) {
+		return
+	}
+	enabled := slices.Clone(l.enabled)
+	for i := len(enabled) - 1; i >= 0; i-- {
+		if dep := enabled[i]; slices.ContainsFunc(dep.info.Depends, func(name string) bool { return strings.EqualFold(name, pl.info.Name) }) {
+			l.disable(dep)
+		}
+	}
+	l.enabled = slices.DeleteFunc(l.enabled, func(e *loaded) bool { return e == pl })
+	pl.api.unregister()
+
+	var err error
+	l.call(pl, func() { err = pl.p.Disable() })
+	if err != nil {
+		l.log.Error("Error disabling plugin.", "plugin", pl.info.Name, "err", err)
+	}
+	l.log.Info("Disabled plugin.", "plugin", pl.info.Name)
+}
+
+// call calls f, which runs code of the plugin passed. If f panics, the panic
+// is logged and the plugin is disabled. call returns false if f panicked.
+func (l *Loader) call(pl *loaded, f func()) (ok bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			ok = false
+			l.log.Error("Plugin panicked.", "plugin", pl.info.Name, "panic", v, "stack", string(debug.Stack()))
+			if pl.enabled.Load() {
+				// Disabling the plugin locks the Loader, which may already be
+				// locked if the panic happened while enabling or disabling.
+				go func() {
+					l.mu.Lock()
+					defer l.mu.Unlock()
+					l.disable(pl)
+				}()
+			}
+		}
+	}()
+	f()
+	return true
+}
+
+// byName looks up a plugin by its name in the plugins passed.
+func byName(plugins []*loaded, name string) (*loaded, bool) {
+	i := slices.IndexFunc(plugins, func(pl *loaded) bool { return strings.EqualFold(pl.info.Name, name) })
+	if i == -1 {
+		return nil, false
+	}
+	return plugins[i], true
+}
+
+// HandlePlayer subscribes a player.Handler to the player passed that passes
+// its events to the handlers registered by enabled plugins using
+// API.HandlePlayers. Handlers set using player.Player.Handle are not
+// replaced. HandlePlayer may be passed to server.Server.Accept.
+func (l *Loader) HandlePlayer(p *player.Player) {
+	l.mu.Lock()
+	enabled := slices.Clone(l.enabled)
+	l.mu.Unlock()
+
+	h := &handler{l: l}
+	for _, pl := range enabled {
+		for _, ph := range pl.api.playerHandlers(p) {
+			h.handlers = append(h.handlers, pluginHandler{pl: pl, h: ph, enables: pl.enables.Load()})
+		}
+	}
+	p.Subscribe(h, event.PriorityNormal)
+}
@@ -0,0 +1,46 @@
+// Package plugin implements loading plugins into a server.Server. Plugins are Go
+// plugins compiled using `go build -buildmode=plugin` that are discovered in a
+// folder when the server starts, or plugins compiled into the program itself
+// that are added to a Loader directly.
+//
+// A plugin compiled as Go plugin must export a function named NewPlugin that
+// returns its Plugin:
+//
+//	func NewPlugin() plugin.Plugin {
+//		return &MyPlugin{}
+//	}
+//
+// Plugins are enabled in the order of their dependencies and disabled in the
+// reverse order. A plugin that panics while being enabled or disabled, while
+// handling an event or in a goroutine started using API.Go is disabled
+// without affecting the server or other plugins.
+package plugin
+
+// Plugin is a plugin that may be loaded by a Loader. Plugins interact with the
+// server through the API passed when they are enabled.
+type Plugin interface {
+	// Info returns the Info of the Plugin, such as its name and the plugins
+	// it depends on.
+	Info() Info
+	// Enable enables the Plugin. It is called after the plugins it depends
+	// on were enabled. If Enable returns an error, the Plugin is not enabled
+	// and neither are the plugins that depend on it.
+	Enable(api *API) error
+	// Disable disables the Plugin. It is called before the plugins it depends
+	// on are disabled. Commands registered using the API are unregistered
+	// and player events are no longer passed to the Plugin afterwards.
+	Disable() error
+}
+
+// Info holds information about a Plugin.
+type Info struct {
+	// Name is the name of the Plugin. It must be unique among all plugins of
+	// a Loader.
+	Name string
+	// Version is the version of the Plugin. It is only used for display
+	// purposes.
+	Version string
+	// Depends holds the names of the plugins that the Plugin depends on. The
+	// Plugin is only enabled if all of them were enabled before it.
+	Depends []string
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// The claims in the client data of a login that hold the data forwarded by a proxy. The XUID and IP claims
+// are the same as those set by WaterdogPE.
+const (
+	proxyXUIDClaim   = "Waterdog_XUID"
+	proxyIPClaim     = "Waterdog_IP"
+	proxySecretClaim = "Proxy_Secret"
+)
+
+// forwardedLoginExpiry is the duration after which forwarded data of a login is discarded if the connection
+// was not accepted.
+const forwardedLoginExpiry = time.Minute
+
+// forwardedLogin holds the data forwarded by a proxy in the login of a connection.
+type forwardedLogin struct {
+	xuid, ip, secret string
+	received         time.Time
+}
+
+// forwarding collects the data forwarded by a proxy in the logins of connections, so that it may be applied to
+// the connections once they are accepted.
+type forwarding struct {
+	secret string
+
+	mu     sync.Mutex
+	logins map[string]forwardedLogin
+}
+
+// handlePacket reads the data forwarded in the Login packet of a connection. It is passed to
+// minecraft.ListenConfig as PacketFunc.
+func (f *forwarding) handlePacket(header packet.Header, payload []byte, src, _ net.Addr) {
+	if header.PacketID != packet.IDLogin {
+		return
+	}
+	claims, err := clientDataClaims(payload)
+	if err != nil {
+		// The login is invalid and will be rejected by the listener itself.
+		return
+	}
+	l := forwardedLogin{received: time.Now()}
+	l.xuid, _ = claims[proxyXUIDClaim].(string)
+	l.ip, _ = claims[proxyIPClaim].(string)
+	l.secret, _ = claims[proxySecretClaim].(string)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for addr, other := range f.logins {
+		if time.Since(other.received) > forwardedLoginExpiry {
+			delete(f.logins, addr)
+		}
+	}
+	f.logins[src.String()] = l
+}
+
+// take returns and removes the forwarded data of the login of a connection with the address passed. False is
+// returned if the login of the connection held no forwarded data or if it did not hold the secret of the
+// proxy.
+func (f *forwarding) take(addr net.Addr) (forwardedLogin, bool) {
+	f.mu.Lock()
+	l, ok := f.logins[addr.String()]
+	delete(f.logins, addr.String())
+	f.mu.Unlock()
+
+	if !ok || l.ip == "" {
+		return l, false
+	}
+	return l, f.secret != "" && subtle.ConstantTimeCompare([]byte(l.secret), []byte(f.secret)) == 1
+}
+
+// proxyListener is a Listener that accepts connections forwarded by a proxy. The XUID and IP address forwarded
+// by the proxy are used for the connections instead of their own. Connections that were not forwarded by the
+// proxy are disconnected.
+type proxyListener struct {
+	listener
+	f *forwarding
+}
+
+// Accept blocks until the next connection forwarded by the proxy is established and returns it. An error is
+// returned if the Listener was closed using Close.
+func (l proxyListener) Accept() (session.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		raw := conn.(*minecraft.Conn)
+		fl, ok := l.f.take(raw.RemoteAddr())
+		if !ok {
+			_ = l.Listener.Disconnect(raw, "Connections must be made through the proxy of the server.")
+			continue
+		}
+		c := proxiedConn{Conn: raw, identity: raw.IdentityData(), addr: raw.RemoteAddr()}
+		if fl.xuid != "" {
+			c.identity.XUID = fl.xuid
+		}
+		if addr, err := parseForwardedAddr(fl.ip); err == nil {
+			c.addr = addr
+		}
+		// Flood limits are enforced for the address forwarded by the proxy, so that a single player can't get
+		// the proxy itself blocked.
+		if l.admit(c, raw) {
+			return l.track(c, raw), nil
+		}
+	}
+}
+
+// proxiedConn is a connection forwarded by a proxy. It reports the XUID and address forwarded by the proxy
+// instead of those of the proxy itself.
+type proxiedConn struct {
+	*minecraft.Conn
+	identity login.IdentityData
+	addr     net.Addr
+}
+
+// IdentityData returns the identity data of the connection with the XUID forwarded by the proxy.
+func (c proxiedConn) IdentityData() login.IdentityData {
+	return c.identity
+}
+
+// RemoteAddr returns the address of the client as forwarded by the proxy.
+func (c proxiedConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// parseForwardedAddr parses an address forwarded by a proxy, which may or may not have a port.
+func parseForwardedAddr(s string) (net.Addr, error) {
+	host, port := s, "0"
+	if h, p, err := net.SplitHostPort(s); err == nil {
+		host, port = h, p
+	}
+	return net.ResolveUDPAddr("udp", net.JoinHostPort(host, port))
+}
+
+// clientDataClaims returns the unverified claims of the client data in the payload of a Login packet. The
+// claims are verified by the listener when it handles the Login packet.
+func clientDataClaims(payload []byte) (map[string]any, error) {
+	buf := bytes.NewBuffer(payload)
+	// Skip the protocol version, which is encoded as a big endian int32.
+	if buf.Next(4); buf.Len() == 0 {
+		return nil, fmt.Errorf("login packet too short")
+	}
+	n, err := binary.ReadUvarint(buf)
+	if err != nil || uint64(buf.Len()) < n {
+		return nil, fmt.Errorf("invalid connection request length")
+	}
+	request := bytes.NewBuffer(buf.Next(int(n)))
+
+	var chainLength, tokenLength int32
+	if err := binary.Read(request, binary.LittleEndian, &chainLength); err != nil || chainLength < 0 {
+		return nil, fmt.Errorf("invalid chain length")
+	}
+	request.Next(int(chainLength))
+	if err := binary.Read(request, binary.LittleEndian, &tokenLength); err != nil || tokenLength < 0 {
+		return nil, fmt.Errorf("invalid token length")
+	}
+	parts := strings.Split(string(request.Next(int(tokenLength))), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("client data is not a JWT")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decode client data: %w", err)
+	}
+	claims := make(map[string]any)
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, fmt.Errorf("decode client data: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// loginPayload returns the payload of a Login packet with client data holding the claims passed.
+func loginPayload(t *testing.T, claims map[string]any) []byte {
+	t.Helper()
+	b, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("encode claims: %v", err)
+	}
+	token := "e30." + base64.RawURLEncoding.EncodeToString(b) + ".c2ln"
+
+	request := new(bytes.Buffer)
+	_ = binary.Write(request, binary.LittleEndian, int32(2))
+	request.WriteString("{}")
+	_ = binary.Write(request, binary.LittleEndian, int32(len(token)))
+	request.WriteString(token)
+
+	payload := new(bytes.Buffer)
+	_ = binary.Write(payload, binary.BigEndian, int32(748))
+	payload.Write(binary.AppendUvarint(nil, uint64(request.Len())))
+	payload.Write(request.Bytes())
+	return payload.Bytes()
+}
+
+func TestForwardingTake(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 19132}
+	tests := []struct {
+		name   string
+		secret string
+		claims map[string]any
+		ok     bool
+	}{
+		{"valid", "s3cret", map[string]any{proxyIPClaim: "1.2.3.4:5678", proxyXUIDClaim: "123", proxySecretClaim: "s3cret"}, true},
+		{"wrong secret", "s3cret", map[string]any{proxyIPClaim: "1.2.3.4:5678", proxySecretClaim: "other"}, false},
+		{"missing secret", "s3cret", map[string]any{proxyIPClaim: "1.2.3.4:5678"}, false},
+		{"missing ip", "s3cret", map[string]any{proxySecretClaim: "s3cret"}, false},
+		{"empty server secret", "", map[string]any{proxyIPClaim: "1.2.3.4:5678", proxySecretClaim: ""}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := &forwarding{secret: test.secret, logins: make(map[string]forwardedLogin)}
+			f.handlePacket(packet.Header{PacketID: packet.IDLogin}, loginPayload(t, test.claims), addr, nil)
+
+			l, ok := f.take(addr)
+			if ok != test.ok {
+				t.Fatalf("take: got %v, want %v", ok, test.ok)
+			}
+			if ok && (l.ip != "1.2.3.4:5678" || l.xuid != "123") {
+				t.Errorf("take: got ip %q and xuid %q", l.ip, l.xuid)
+			}
+			if _, ok := f.take(addr); ok {
+				t.Errorf("take: forwarded login was not removed")
+			}
+		})
+	}
+}
+
+func TestForwardingIgnoresOtherPackets(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 19132}
+	f := &forwarding{secret: "s3cret", logins: make(map[string]forwardedLogin)}
+	payload := loginPayload(t, map[string]any{proxyIPClaim: "1.2.3.4", proxySecretClaim: "s3cret"})
+	f.handlePacket(packet.Header{PacketID: packet.IDText}, payload, addr, nil)
+	if _, ok := f.take(addr); ok {
+		t.Errorf("take: got forwarded login from a packet other than Login")
+	}
+}
+
+func TestUserConfigProxyRequiresSecret(t *testing.T) {
+	uc := DefaultConfig()
+	uc.World.SaveData, uc.Players.SaveData = false, false
+	uc.Proxy.Enabled = true
+	if _, err := uc.Config(nil); err == nil {
+		t.Errorf("Config: expected error for proxy without secret")
+	}
+}
+
+func TestParseForwardedAddr(t *testing.T) {
+	for in, want := range map[string]string{"1.2.3.4:5678": "1.2.3.4:5678", "1.2.3.4": "1.2.3.4:0"} {
+		addr, err := parseForwardedAddr(in)
+		if err != nil {
+			t.Fatalf("parseForwardedAddr(%q): %v", in, err)
+		}
+		if addr.String() != want {
+			t.Errorf("parseForwardedAddr(%q): got %v, want %v", in, addr, want)
+		}
+	}
+}
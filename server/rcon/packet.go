@@ -0,0 +1,84 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// typeResponse is the type of packets holding the output of a command.
+	// Clients also send empty packets of this type after a command to find
+	// the end of its output, which are sent back as they are.
+	typeResponse int32 = 0
+	// typeCommand is the type of packets holding a command to run. Responses
+	// to authentication have the same type.
+	typeCommand int32 = 2
+	// typeAuth is the type of packets holding the password of a client.
+	typeAuth int32 = 3
+	// typeAuthResponse is the type of responses to authentication.
+	typeAuthResponse = typeCommand
+)
+
+const (
+	// maxBody is the maximum length of the body of a packet sent by either
+	// side. Longer command output is split over several packets.
+	maxBody = 4096
+	// headerSize is the size of the ID and type of a packet, as well as the
+	// two null bytes following its body.
+	headerSize = 10
+)
+
+// packet is a packet of the RCON protocol. Packets are prefixed with their
+// length and consist of an ID chosen by the client, a type and a body.
+type packet struct {
+	id, typ int32
+	body    string
+}
+
+// readPacket reads a single packet from the io.Reader passed.
+func readPacket(r io.Reader) (packet, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return packet{}, err
+	}
+	if size < headerSize || size > maxBody+headerSize {
+		return packet{}, fmt.Errorf("invalid packet size %v", size)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return packet{}, err
+	}
+	body, _, _ := bytes.Cut(b[8:], []byte{0})
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(b)),
+		typ:  int32(binary.LittleEndian.Uint32(b[4:])),
+		body: string(body),
+	}, nil
+}
+
+// writePacket writes a single packet to the io.Writer passed.
+func writePacket(w io.Writer, pk packet) error {
+	b := make([]byte, 4, 4+headerSize+len(pk.body))
+	binary.LittleEndian.PutUint32(b, uint32(headerSize+len(pk.body)))
+	b = binary.LittleEndian.AppendUint32(b, uint32(pk.id))
+	b = binary.LittleEndian.AppendUint32(b, uint32(pk.typ))
+	b = append(append(b, pk.body...), 0, 0)
+	_, err := w.Write(b)
+	return err
+}
+
+// writeResponse writes the output of a command to the io.Writer passed, split
+// over several packets with the ID passed if it is longer than maxBody.
+func writeResponse(w io.Writer, id int32, out string) error {
+	for {
+		n := min(len(out), maxBody)
+		if err := writePacket(w, packet{id: id, typ: typeResponse, body: out[:n]}); err != nil {
+			return err
+		}
+		if out = out[n:]; out == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestPacketRoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	want := packet{id: 42, typ: typeCommand, body: "list"}
+	if err := writePacket(buf, want); err != nil {
+		t.Fatalf("writePacket: %v", err)
+	}
+	if size := binary.LittleEndian.Uint32(buf.Bytes()); size != uint32(headerSize+len(want.body)) {
+		t.Errorf("writePacket: got size %v, want %v", size, headerSize+len(want.body))
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte{0, 0}) {
+		t.Errorf("writePacket: body not followed by two null bytes")
+	}
+	got, err := readPacket(buf)
+	if err != nil {
+		t.Fatalf("readPacket: %v", err)
+	}
+	if got != want {
+		t.Errorf("readPacket: got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadPacketInvalidSize(t *testing.T) {
+	for _, size := range []int32{0, headerSize - 1, maxBody + headerSize + 1, -1} {
+		b := binary.LittleEndian.AppendUint32(nil, uint32(size))
+		b = append(b, make([]byte, 32)...)
+		if _, err := readPacket(bytes.NewReader(b)); err == nil {
+			t.Errorf("readPacket: expected error for size %v", size)
+		}
+	}
+}
+
+func TestReadPacketTruncated(t *testing.T) {
+	buf := new(bytes.Buffer)
+	_ = writePacket(buf, packet{id: 1, typ: typeAuth, body: "password"})
+	if _, err := readPacket(bytes.NewReader(buf.Bytes()[:buf.Len()-3])); err == nil {
+		t.Errorf("readPacket: expected error for truncated packet")
+	}
+}
+
+func TestWriteResponseSplits(t *testing.T) {
+	for _, n := range []int{0, 10, maxBody, maxBody + 1, maxBody*2 + 7} {
+		out := strings.Repeat("a", n)
+		buf := new(bytes.Buffer)
+		if err := writeResponse(buf, 7, out); err != nil {
+			t.Fatalf("writeResponse: %v", err)
+		}
+		var bodies []string
+		for buf.Len() > 0 {
+			pk, err := readPacket(buf)
+			if err != nil {
+				t.Fatalf("readPacket: %v", err)
+			}
+			if pk.id != 7 || pk.typ != typeResponse {
+				t.Errorf("writeResponse: got packet with id %v and type %v", pk.id, pk.typ)
+			}
+			if len(pk.body) > maxBody {
+				t.Errorf("writeResponse: body of %v bytes exceeds maximum", len(pk.body))
+			}
+			bodies = append(bodies, pk.body)
+		}
+		if want := max(1, (n+maxBody-1)/maxBody); len(bodies) != want {
+			t.Errorf("writeResponse(%v bytes): got %v packets, want %v", n, len(bodies), want)
+		}
+		if strings.Join(bodies, "") != out {
+			t.Errorf("writeResponse(%v bytes): output was not preserved", n)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Package rcon implements a remote console using the RCON protocol, which is
+// supported by most hosting panels and server tooling. Clients authenticate
+// with a password, after which every command they send is run by the console
+// of a server.Server and its output is sent back.
+package rcon
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/logging"
+)
+
+// Config holds the settings of a Server.
+type Config struct {
+	// Address is the address that the Server listens on, such as ":25575".
+	Address string
+	// Password is the password that clients must authenticate with. A Server
+	// cannot be started without a Password.
+	Password string
+	// Log is the Logger that connections and commands are logged with.
+	Log *slog.Logger
+}
+
+// Server is a TCP server that runs commands received over the RCON protocol
+// as the console of a server.Server.
+type Server struct {
+	conf Config
+	srv  *server.Server
+
+	mu     sync.Mutex
+	l      net.Listener
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// New creates a Server that runs commands as the console of the
+// server.Server passed. The Server starts accepting connections once Listen
+// is called.
+func (conf Config) New(srv *server.Server) *Server {
+	return &Server{conf: conf, srv: srv, conns: make(map[net.Conn]struct{})}
+}
+
+// Listen starts listening on the Address of the Config of the Server and
+// accepts connections on a separate goroutine. An error is returned if no
+// Password is set or if the Server could not listen on its Address.
+func (s *Server) Listen() error {
+	if s.conf.Password == "" {
+		return errors.New("rcon: no password set")
+	}
+	l, err := net.Listen("tcp", s.conf.Address)
+	if err != nil {
+		return fmt.Errorf("rcon: listen: %w", err)
+	}
+	s.mu.Lock()
+	s.l = l
+	s.mu.Unlock()
+
+	go s.accept()
+	s.conf.Log.Info("Serving RCON.", "addr", l.Addr().String())
+	return nil
+}
+
+// Close stops the Server and closes all connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	if s.l == nil {
+		return nil
+	}
+	return s.l.Close()
+}
+
+// accept accepts connections until the Server is closed.
+func (s *Server) accept() {
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.conf.Log.Error("Error accepting RCON connection.", "err", err)
+			}
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+// handle handles a connection until it is closed. Clients must authenticate
+// before sending commands and are disconnected if they fail to do so.
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	authenticated := false
+	for {
+		if !authenticated {
+			// Clients that do not authenticate in time are disconnected, so
+			// that idle connections are not held open.
+			_ = conn.SetReadDeadline(time.Now().Add(time.Second * 10))
+		} else {
+			_ = conn.SetReadDeadline(time.Time{})
+		}
+		pk, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		switch {
+		case pk.typ == typeAuth:
+			if subtle.ConstantTimeCompare([]byte(pk.body), []byte(s.conf.Password)) != 1 {
+				s.conf.Log.Warn("RCON client failed to authenticate.", "raddr", conn.RemoteAddr().String())
+				// Delay the response to slow down guessing the password.
+				time.Sleep(time.Second)
+				_ = writePacket(w, packet{id: -1, typ: typeAuthResponse})
+				_ = w.Flush()
+				return
+			}
+			authenticated = true
+			s.conf.Log.Info("RCON client authenticated.", "raddr", conn.RemoteAddr().String())
+			err = writePacket(w, packet{id: pk.id, typ: typeResponse})
+			if err == nil {
+				err = writePacket(w, packet{id: pk.id, typ: typeAuthResponse})
+			}
+		case !authenticated:
+			return
+		case pk.typ == typeCommand:
+			err = s.command(w, pk)
+		default:
+			// Sent by clients after a command to find the end of its output.
+			err = writePacket(w, packet{id: pk.id, typ: typeResponse, body: pk.body})
+		}
+		if err != nil || w.Flush() != nil {
+			return
+		}
+	}
+}
+
+// command runs the command of a packet and writes its output, split over
+// several packets if it is too long for one.
+func (s *Server) command(w *bufio.Writer, pk packet) error {
+	logging.With(s.conf.Log, logging.Commands).Info("Ran command.", "source", "RCON", "command", strings.TrimPrefix(strings.TrimSpace(pk.body), "/"))
+	var lines []string
+	if o, err := s.srv.RunConsoleCommand(pk.body); err != nil {
+		lines = append(lines, err.Error())
+	} else {
+		lines = append(lines, o.Messages()...)
+		for _, err := range o.Errors() {
+			lines = append(lines, err.Error())
+		}
+	}
+	return writeResponse(w, pk.id, strings.Join(lines, "\n"))
+}
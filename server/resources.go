@@ -0,0 +1,169 @@
+package server
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// ResourceSelector may be implemented to select the resource packs that a
+// player is asked to download when joining a Server, by setting the
+// ResourceSelector through a Config. It may be used to give players in
+// different game modes different packs, or to leave out optional packs for
+// players that do not want them. Packs are only sent when joining, so a player
+// that is online is given its new packs by transferring it to the server
+// again, for example using player.Player.Transfer.
+type ResourceSelector interface {
+	// SelectResources returns the packs out of the packs passed that the player
+	// with the identity passed is asked to download, in the order that they are
+	// stacked. Packs returned that are not part of the packs passed are
+	// ignored.
+	SelectResources(d login.IdentityData, packs []*resource.Pack) []*resource.Pack
+}
+
+// selectionExpiry is the duration after which the packs selected for a
+// connection are forgotten if the connection never got to stacking them.
+const selectionExpiry = time.Minute
+
+// packProtocol is a minecraft.Protocol that changes the resource packs offered
+// to and stacked for each connection according to a ResourceSelector and the
+// packs that are optional.
+type packProtocol struct {
+	minecraft.Protocol
+	selector ResourceSelector
+	required bool
+	optional []string
+
+	mu       sync.Mutex
+	selected map[*minecraft.Conn]packSelection
+}
+
+// packSelection holds the packs selected for a connection and when they were
+// selected.
+type packSelection struct {
+	packs []*resource.Pack
+	at    time.Time
+}
+
+// newPackProtocol returns a packProtocol wrapping the minecraft.Protocol
+// passed. The resource packs offered are required if required is true and at
+// least one of them has a UUID not found in optional.
+func newPackProtocol(p minecraft.Protocol, selector ResourceSelector, required bool, optional []string) *packProtocol {
+	return &packProtocol{Protocol: p, selector: selector, required: required, optional: optional, selected: make(map[*minecraft.Conn]packSelection)}
+}
+
+// ConvertFromLatest changes the ResourcePacksInfo and ResourcePackStack
+// packets sent to the connection passed before converting them using the
+// minecraft.Protocol wrapped.
+func (p *packProtocol) ConvertFromLatest(pk packet.Packet, conn *minecraft.Conn) []packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.ResourcePacksInfo:
+		return p.Protocol.ConvertFromLatest(p.info(pk, p.selectPacks(conn)), conn)
+	case *packet.ResourcePackStack:
+		if packs, ok := p.selection(conn); ok {
+			return p.Protocol.ConvertFromLatest(stackPacks(pk, packs, conn.ResourcePacks()), conn)
+		}
+	}
+	return p.Protocol.ConvertFromLatest(pk, conn)
+}
+
+// info returns a copy of the ResourcePacksInfo passed that only offers the
+// packs passed and only requires them to be downloaded if one of them is not
+// optional.
+func (p *packProtocol) info(pk *packet.ResourcePacksInfo, packs []*resource.Pack) *packet.ResourcePacksInfo {
+	offered := func(uuid string) bool {
+		return slices.ContainsFunc(packs, func(pack *resource.Pack) bool { return pack.UUID() == uuid })
+	}
+	info := *pk
+	info.TexturePackRequired, info.HasScripts = false, false
+	for _, pack := range packs {
+		if p.required && !slices.Contains(p.optional, pack.UUID()) {
+			info.TexturePackRequired = true
+		}
+	}
+	info.BehaviourPacks = slices.DeleteFunc(slices.Clone(pk.BehaviourPacks), func(b protocol.BehaviourPackInfo) bool {
+		return !offered(b.UUID)
+	})
+	for _, b := range info.BehaviourPacks {
+		info.HasScripts = info.HasScripts || b.HasScripts
+	}
+	info.TexturePacks = slices.DeleteFunc(slices.Clone(pk.TexturePacks), func(t protocol.TexturePackInfo) bool {
+		return !offered(t.UUID)
+	})
+	info.PackURLs = slices.DeleteFunc(slices.Clone(pk.PackURLs), func(u protocol.PackURL) bool {
+		return !slices.ContainsFunc(packs, func(pack *resource.Pack) bool { return u.UUIDVersion == pack.UUID()+"_"+pack.Version() })
+	})
+	return &info
+}
+
+// stackPacks returns a copy of the ResourcePackStack passed that stacks the packs
+// passed in their order. Packs in the stack that are not found in held, such
+// as the packs that are applied by the client without downloading them, are
+// kept.
+func stackPacks(pk *packet.ResourcePackStack, packs, held []*resource.Pack) *packet.ResourcePackStack {
+	isHeld := func(uuid string) bool {
+		return slices.ContainsFunc(held, func(pack *resource.Pack) bool { return pack.UUID() == uuid })
+	}
+	res := *pk
+	res.BehaviourPacks, res.TexturePacks = nil, nil
+	for _, pack := range packs {
+		s := protocol.StackResourcePack{UUID: pack.UUID(), Version: pack.Version()}
+		if pack.HasBehaviours() {
+			res.BehaviourPacks = append(res.BehaviourPacks, s)
+			continue
+		}
+		res.TexturePacks = append(res.TexturePacks, s)
+	}
+	for _, s := range pk.BehaviourPacks {
+		if !isHeld(s.UUID) {
+			res.BehaviourPacks = append(res.BehaviourPacks, s)
+		}
+	}
+	for _, s := range pk.TexturePacks {
+		if !isHeld(s.UUID) {
+			res.TexturePacks = append(res.TexturePacks, s)
+		}
+	}
+	return &res
+}
+
+// selectPacks selects the packs for the connection passed using the
+// ResourceSelector and stores them so that they can be stacked once the
+// connection has downloaded them. If the packProtocol has no
+// ResourceSelector, all packs held by the connection are returned.
+func (p *packProtocol) selectPacks(conn *minecraft.Conn) []*resource.Pack {
+	held := conn.ResourcePacks()
+	if p.selector == nil {
+		return held
+	}
+	packs := slices.DeleteFunc(slices.Clone(p.selector.SelectResources(conn.IdentityData(), slices.Clone(held))), func(pack *resource.Pack) bool {
+		return pack == nil || !slices.Contains(held, pack)
+	})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for c, s := range p.selected {
+		if now.Sub(s.at) > selectionExpiry {
+			delete(p.selected, c)
+		}
+	}
+	p.selected[conn] = packSelection{packs: packs, at: now}
+	return packs
+}
+
+// selection returns the packs selected for the connection passed and forgets
+// them. False is returned if no packs were selected for the connection.
+func (p *packProtocol) selection(conn *minecraft.Conn) ([]*resource.Pack, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.selected[conn]
+	delete(p.selected, conn)
+	return s.packs, ok
+}
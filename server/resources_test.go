@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// testPack creates a resource pack with a module of the type passed, such as "resources" or "data".
+func testPack(t *testing.T, module string) *resource.Pack {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := fmt.Sprintf(`{"format_version": 2, "header": {"name": "test", "uuid": %q, "version": [1, 0, 0], "min_engine_version": [1, 20, 0]}, "modules": [{"type": %q, "uuid": %q, "version": [1, 0, 0]}]}`, uuid.New(), module, uuid.New())
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	pack, err := resource.ReadPath(dir)
+	if err != nil {
+		t.Fatalf("read pack: %v", err)
+	}
+	return pack
+}
+
+// packsInfo returns the ResourcePacksInfo that offers all packs passed.
+func packsInfo(packs []*resource.Pack) *packet.ResourcePacksInfo {
+	pk := &packet.ResourcePacksInfo{TexturePackRequired: true}
+	for _, pack := range packs {
+		if pack.HasBehaviours() {
+			pk.BehaviourPacks = append(pk.BehaviourPacks, protocol.BehaviourPackInfo{UUID: pack.UUID(), Version: pack.Version()})
+			continue
+		}
+		pk.TexturePacks = append(pk.TexturePacks, protocol.TexturePackInfo{UUID: pack.UUID(), Version: pack.Version()})
+	}
+	return pk
+}
+
+func TestPackProtocolInfoRequired(t *testing.T) {
+	a, b := testPack(t, "resources"), testPack(t, "resources")
+	tests := []struct {
+		name     string
+		required bool
+		optional []string
+		packs    []*resource.Pack
+		want     bool
+	}{
+		{"not required", false, nil, []*resource.Pack{a, b}, false},
+		{"required", true, nil, []*resource.Pack{a, b}, true},
+		{"required and optional pack", true, []string{b.UUID()}, []*resource.Pack{a, b}, true},
+		{"only optional packs", true, []string{b.UUID()}, []*resource.Pack{b}, false},
+		{"no packs", true, nil, nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newPackProtocol(minecraft.DefaultProtocol, nil, test.required, test.optional)
+			info := p.info(packsInfo([]*resource.Pack{a, b}), test.packs)
+			if info.TexturePackRequired != test.want {
+				t.Errorf("info: got TexturePackRequired %v, want %v", info.TexturePackRequired, test.want)
+			}
+			if len(info.TexturePacks) != len(test.packs) {
+				t.Errorf("info: got %v texture packs, want %v", len(info.TexturePacks), len(test.packs))
+			}
+		})
+	}
+}
+
+func TestPackProtocolInfoLeavesOutPacks(t *testing.T) {
+	a, b, c := testPack(t, "resources"), testPack(t, "data"), testPack(t, "resources")
+	p := newPackProtocol(minecraft.DefaultProtocol, nil, false, nil)
+	pk := packsInfo([]*resource.Pack{a, b, c})
+	info := p.info(pk, []*resource.Pack{c})
+	if len(info.BehaviourPacks) != 0 || len(info.TexturePacks) != 1 || info.TexturePacks[0].UUID != c.UUID() {
+		t.Errorf("info: got behaviour packs %v and texture packs %v, want only %v", info.BehaviourPacks, info.TexturePacks, c.UUID())
+	}
+	if len(pk.BehaviourPacks) != 1 || len(pk.TexturePacks) != 2 {
+		t.Errorf("info: ResourcePacksInfo passed was changed")
+	}
+}
+
+func TestStackPacks(t *testing.T) {
+	a, b, c := testPack(t, "resources"), testPack(t, "data"), testPack(t, "resources")
+	held := []*resource.Pack{a, b, c}
+	pk := &packet.ResourcePackStack{
+		BehaviourPacks: []protocol.StackResourcePack{{UUID: b.UUID(), Version: b.Version()}},
+		TexturePacks: []protocol.StackResourcePack{
+			{UUID: a.UUID(), Version: a.Version()},
+			{UUID: c.UUID(), Version: c.Version()},
+			{UUID: "exempted", Version: "1.0.0"},
+		},
+	}
+	stack := stackPacks(pk, []*resource.Pack{c, a}, held)
+	if len(stack.BehaviourPacks) != 0 {
+		t.Errorf("stackPacks: got behaviour packs %v, want none", stack.BehaviourPacks)
+	}
+	var got []string
+	for _, s := range stack.TexturePacks {
+		got = append(got, s.UUID)
+	}
+	if want := []string{c.UUID(), a.UUID(), "exempted"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("stackPacks: got texture packs %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"time"
+
+	"github.com/df-mc/dragonfly/server/internal/crash"
+	"github.com/df-mc/dragonfly/server/player"
+)
+
+// startSaving starts saving the worlds and online players of the Server every
+// AutoSaveInterval of its Config, if it is non-zero, and makes sure they are
+// saved once more if a goroutine of the Server panics.
+func (srv *Server) startSaving() {
+	srv.saveStop = make(chan struct{})
+	srv.removeCrashSave = crash.Handle(func() {
+		srv.conf.Log.Error("Server crashed, saving worlds and players...")
+		srv.save()
+	})
+	if srv.conf.AutoSaveInterval <= 0 {
+		return
+	}
+	srv.saving.Add(1)
+	go func() {
+		defer srv.saving.Done()
+		t := time.NewTicker(srv.conf.AutoSaveInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				start := time.Now()
+				srv.save()
+				srv.conf.Log.Debug("Saved worlds and players.", "duration", time.Since(start).Round(time.Millisecond))
+			case <-srv.saveStop:
+				return
+			}
+		}
+	}()
+}
+
+// stopSaving stops saving the Server periodically and waits for a save that
+// is in progress to finish.
+func (srv *Server) stopSaving() {
+	srv.removeCrashSave()
+	close(srv.saveStop)
+	srv.saving.Wait()
+}
+
+// save saves the data and permissions of all online players and the chunks and
+// settings of all worlds of the Server, without disconnecting players or
+// unloading chunks.
+func (srv *Server) save() {
+	for _, p := range srv.Players() {
+		srv.savePlayer(p)
+	}
+	for _, w := range append(srv.customWorlds(), srv.end, srv.nether, srv.world) {
+		w.Save()
+	}
+}
+
+// savePlayer saves the data and permissions of an online player. Nothing is
+// saved if the player already quit and its data was saved, so that data saved
+// afterwards, such as by EditOfflinePlayer, is not overwritten.
+func (srv *Server) savePlayer(p *player.Player) {
+	srv.dataMu.Lock()
+	defer srv.dataMu.Unlock()
+	if srv.online[p.UUID()] == 0 {
+		return
+	}
+	if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
+		srv.conf.Log.Error("Error while saving data.", "err", err)
+	}
+	if err := srv.conf.PermissionProvider.Save(p.UUID(), p.Permissions().Data()); err != nil {
+		srv.conf.Log.Error("Error while saving permissions.", "err", err)
+	}
+}
@@ -0,0 +1,477 @@
+package script
+
+import (
+	"strings"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Names of the metatables of the userdata types exposed to scripts.
+const (
+	playerType = "player"
+	worldType  = "world"
+)
+
+// bind exposes the server, its players and worlds, and the functions to
+// register event handlers, commands and timers to the Lua state of the
+// script.
+func (s *script) bind() {
+	l := s.l
+	l.SetGlobal("print", l.NewFunction(s.luaPrint))
+	l.SetGlobal("on", l.NewFunction(s.luaOn))
+	l.SetGlobal("command", l.NewFunction(s.luaCommand))
+	l.SetGlobal("after", l.NewFunction(func(l *lua.LState) int { return s.luaSchedule(l, false) }))
+	l.SetGlobal("every", l.NewFunction(func(l *lua.LState) int { return s.luaSchedule(l, true) }))
+	l.SetGlobal("cancel", l.NewFunction(s.luaCancel))
+	l.SetGlobal("server", l.SetFuncs(l.NewTable(), map[string]lua.LGFunction{
+		"players":   s.luaPlayers,
+		"player":    s.luaPlayer,
+		"broadcast": s.luaBroadcast,
+		"world":     s.luaWorld,
+	}))
+
+	mt := l.NewTypeMetatable(playerType)
+	l.SetField(mt, "__index", l.SetFuncs(l.NewTable(), map[string]lua.LGFunction{
+		"name":          playerName,
+		"uuid":          playerUUID,
+		"message":       playerMessage,
+		"tip":           playerTip,
+		"popup":         playerPopup,
+		"position":      playerPosition,
+		"teleport":      playerTeleport,
+		"health":        playerHealth,
+		"heal":          playerHeal,
+		"game_mode":     playerGameMode,
+		"set_game_mode": playerSetGameMode,
+		"give":          playerGive,
+		"held_item":     playerHeldItem,
+		"world":         playerWorld,
+		"disconnect":    playerDisconnect,
+	}))
+	mt = l.NewTypeMetatable(worldType)
+	l.SetField(mt, "__index", l.SetFuncs(l.NewTable(), map[string]lua.LGFunction{
+		"name":      worldName,
+		"block":     worldBlock,
+		"set_block": worldSetBlock,
+		"time":      worldTime,
+		"set_time":  worldSetTime,
+	}))
+}
+
+// luaPrint implements print(...), logging the values passed.
+func (s *script) luaPrint(l *lua.LState) int {
+	parts := make([]string, 0, l.GetTop())
+	for i := 1; i <= l.GetTop(); i++ {
+		parts = append(parts, l.ToStringMeta(l.Get(i)).String())
+	}
+	s.e.log.Info(strings.Join(parts, " "), "script", s.name)
+	return 0
+}
+
+// luaOn implements on(event, fn), registering fn to be called when the event
+// passed happens.
+func (s *script) luaOn(l *lua.LState) int {
+	event, fn := l.CheckString(1), l.CheckFunction(2)
+	if !validEvent(event) {
+		l.ArgError(1, "unknown event "+event)
+	}
+	s.on(event, fn)
+	return 0
+}
+
+// luaCommand implements command(name, description, fn), registering a command
+// that calls fn with the player running it, or nil if it was not run by a
+// player, and a table holding its arguments. A string returned by fn is sent
+// to the source of the command.
+func (s *script) luaCommand(l *lua.LState) int {
+	name, description, fn := l.CheckString(1), l.CheckString(2), l.CheckFunction(3)
+	c := cmd.New(name, description, nil, command{s: s, fn: fn})
+	s.commands = append(s.commands, c)
+	cmd.Register(c)
+	return 0
+}
+
+// luaSchedule implements after(seconds, fn) and every(seconds, fn), returning
+// the ID of the timer created.
+func (s *script) luaSchedule(l *lua.LState, repeat bool) int {
+	seconds, fn := float64(l.CheckNumber(1)), l.CheckFunction(2)
+	if seconds <= 0 && repeat {
+		l.ArgError(1, "interval must be positive")
+	}
+	l.Push(lua.LNumber(s.schedule(time.Duration(seconds*float64(time.Second)), repeat, fn)))
+	return 1
+}
+
+// luaCancel implements cancel(id), stopping a timer created using after or
+// every.
+func (s *script) luaCancel(l *lua.LState) int {
+	s.cancel(l.CheckInt(1))
+	return 0
+}
+
+// luaPlayers implements server.players(), returning a table of all online
+// players.
+func (s *script) luaPlayers(l *lua.LState) int {
+	t := l.NewTable()
+	for _, p := range s.e.api.Server().Players() {
+		t.Append(newPlayer(l, p))
+	}
+	l.Push(t)
+	return 1
+}
+
+// luaPlayer implements server.player(name), returning the online player with
+// the name passed or nil.
+func (s *script) luaPlayer(l *lua.LState) int {
+	if p, ok := s.e.api.Server().PlayerByName(l.CheckString(1)); ok {
+		l.Push(newPlayer(l, p))
+		return 1
+	}
+	l.Push(lua.LNil)
+	return 1
+}
+
+// luaBroadcast implements server.broadcast(msg), sending a message to all
+// online players.
+func (s *script) luaBroadcast(l *lua.LState) int {
+	msg := l.CheckString(1)
+	for _, p := range s.e.api.Server().Players() {
+		p.Message(msg)
+	}
+	return 0
+}
+
+// luaWorld implements server.world(name), returning the "overworld",
+// "nether" or "end". The overworld is returned if no name is passed.
+func (s *script) luaWorld(l *lua.LState) int {
+	srv := s.e.api.Server()
+	switch name := l.OptString(1, "overworld"); name {
+	case "overworld":
+		l.Push(newWorld(l, srv.World()))
+	case "nether":
+		l.Push(newWorld(l, srv.Nether()))
+	case "end":
+		l.Push(newWorld(l, srv.End()))
+	default:
+		l.ArgError(1, "unknown world "+name)
+	}
+	return 1
+}
+
+// newPlayer returns a Lua userdata value for the player passed.
+func newPlayer(l *lua.LState, p *player.Player) *lua.LUserData {
+	ud := l.NewUserData()
+	ud.Value = p
+	l.SetMetatable(ud, l.GetTypeMetatable(playerType))
+	return ud
+}
+
+// checkPlayer returns the player passed as the first argument.
+func checkPlayer(l *lua.LState) *player.Player {
+	if p, ok := l.CheckUserData(1).Value.(*player.Player); ok {
+		return p
+	}
+	l.ArgError(1, "player expected")
+	return nil
+}
+
+// playerName implements player:name(), returning the name of a player.
+func playerName(l *lua.LState) int {
+	l.Push(lua.LString(checkPlayer(l).Name()))
+	return 1
+}
+
+// playerUUID implements player:uuid(), returning the UUID of a player as a string.
+func playerUUID(l *lua.LState) int {
+	l.Push(lua.LString(checkPlayer(l).UUID().String()))
+	return 1
+}
+
+// playerMessage implements player:message(text), sending a chat message to a player.
+func playerMessage(l *lua.LState) int {
+	checkPlayer(l).Message(l.CheckString(2))
+	return 0
+}
+
+// playerTip implements player:tip(text), showing a tip to a player.
+func playerTip(l *lua.LState) int {
+	checkPlayer(l).SendTip(l.CheckString(2))
+	return 0
+}
+
+// playerPopup implements player:popup(text), showing a popup to a player.
+func playerPopup(l *lua.LState) int {
+	checkPlayer(l).SendPopup(l.CheckString(2))
+	return 0
+}
+
+// playerPosition implements player:position(), returning the x, y and z coordinates of a player.
+func playerPosition(l *lua.LState) int {
+	pos := checkPlayer(l).Position()
+	l.Push(lua.LNumber(pos[0]))
+	l.Push(lua.LNumber(pos[1]))
+	l.Push(lua.LNumber(pos[2]))
+	return 3
+}
+
+// playerTeleport implements player:teleport(x, y, z), teleporting a player to a position.
+func playerTeleport(l *lua.LState) int {
+	checkPlayer(l).Teleport(mgl64.Vec3{float64(l.CheckNumber(2)), float64(l.CheckNumber(3)), float64(l.CheckNumber(4))})
+	return 0
+}
+
+// playerHealth implements player:health(), returning the health of a player.
+func playerHealth(l *lua.LState) int {
+	l.Push(lua.LNumber(checkPlayer(l).Health()))
+	return 1
+}
+
+// playerHeal implements player:heal(amount), healing a player.
+func playerHeal(l *lua.LState) int {
+	checkPlayer(l).Heal(float64(l.CheckNumber(2)), healingSource{})
+	return 0
+}
+
+// gameModes holds the game modes that may be set by scripts, indexed by name.
+var gameModes = map[string]world.GameMode{
+	"survival":  world.GameModeSurvival,
+	"creative":  world.GameModeCreative,
+	"adventure": world.GameModeAdventure,
+	"spectator": world.GameModeSpectator,
+}
+
+// playerGameMode implements player:game_mode(), returning the name of the game mode of a player.
+func playerGameMode(l *lua.LState) int {
+	mode := checkPlayer(l).GameMode()
+	for name, m := range gameModes {
+		if m == mode {
+			l.Push(lua.LString(name))
+			return 1
+		}
+	}
+	l.Push(lua.LNil)
+	return 1
+}
+
+// playerSetGameMode implements player:set_game_mode(name), changing the game mode of a player.
+func playerSetGameMode(l *lua.LState) int {
+	p, name := checkPlayer(l), l.CheckString(2)
+	mode, ok := gameModes[name]
+	if !ok {
+		l.ArgError(2, "unknown game mode "+name)
+	}
+	p.SetGameMode(mode)
+	return 0
+}
+
+// playerGive implements player:give(name, count), adding items to the
+// inventory of a player. The number of items added is returned.
+func playerGive(l *lua.LState) int {
+	p, name, count := checkPlayer(l), identifier(l.CheckString(2)), l.OptInt(3, 1)
+	it, ok := world.ItemByName(name, 0)
+	if !ok {
+		l.ArgError(2, "unknown item "+name)
+	}
+	n, _ := p.Inventory().AddItem(item.NewStack(it, count))
+	l.Push(lua.LNumber(n))
+	return 1
+}
+
+// playerHeldItem implements player:held_item(), returning the name and count
+// of the item in the main hand of a player, or nil if it holds nothing.
+func playerHeldItem(l *lua.LState) int {
+	held, _ := checkPlayer(l).HeldItems()
+	if held.Empty() {
+		l.Push(lua.LNil)
+		return 1
+	}
+	name, _ := held.Item().EncodeItem()
+	l.Push(lua.LString(name))
+	l.Push(lua.LNumber(held.Count()))
+	return 2
+}
+
+// playerWorld implements player:world(), returning the world that a player is in.
+func playerWorld(l *lua.LState) int {
+	l.Push(newWorld(l, checkPlayer(l).World()))
+	return 1
+}
+
+// playerDisconnect implements player:disconnect(msg), disconnecting a player with an optional message.
+func playerDisconnect(l *lua.LState) int {
+	p, msg := checkPlayer(l), l.OptString(2, "")
+	// Disconnecting calls the quit handlers of scripts, which must not happen
+	// while this script is running.
+	go p.Disconnect(msg)
+	return 0
+}
+
+// newWorld returns a Lua userdata value for the world passed.
+func newWorld(l *lua.LState, w *world.World) *lua.LUserData {
+	ud := l.NewUserData()
+	ud.Value = w
+	l.SetMetatable(ud, l.GetTypeMetatable(worldType))
+	return ud
+}
+
+// checkWorld returns the world passed as the first argument.
+func checkWorld(l *lua.LState) *world.World {
+	if w, ok := l.CheckUserData(1).Value.(*world.World); ok {
+		return w
+	}
+	l.ArgError(1, "world expected")
+	return nil
+}
+
+// checkPos returns the block position passed as the arguments starting at n.
+func checkPos(l *lua.LState, n int) cube.Pos {
+	return cube.Pos{l.CheckInt(n), l.CheckInt(n + 1), l.CheckInt(n + 2)}
+}
+
+// worldName implements world:name(), returning the name of a world.
+func worldName(l *lua.LState) int {
+	l.Push(lua.LString(checkWorld(l).Name()))
+	return 1
+}
+
+// worldBlock implements world:block(x, y, z), returning the name of the block
+// at a position and a table holding its properties.
+func worldBlock(l *lua.LState) int {
+	name, properties := checkWorld(l).Block(checkPos(l, 2)).EncodeBlock()
+	l.Push(lua.LString(name))
+	t := l.NewTable()
+	for k, v := range properties {
+		t.RawSetString(k, toLua(v))
+	}
+	l.Push(t)
+	return 2
+}
+
+// worldSetBlock implements world:set_block(x, y, z, name, properties),
+// setting the block with the name and optional properties passed at a
+// position.
+func worldSetBlock(l *lua.LState) int {
+	w, pos, name := checkWorld(l), checkPos(l, 2), identifier(l.CheckString(5))
+	properties := map[string]any{}
+	if t := l.OptTable(6, nil); t != nil {
+		t.ForEach(func(k, v lua.LValue) {
+			properties[k.String()] = fromLua(v)
+		})
+	}
+	b, ok := world.BlockByName(name, properties)
+	if !ok && len(properties) == 0 {
+		// Most blocks have properties: Use the default state of the block if none were passed.
+		b, ok = defaultBlock(name)
+	}
+	if !ok {
+		l.ArgError(5, "unknown block "+name)
+	}
+	w.SetBlock(pos, b, nil)
+	return 0
+}
+
+// worldTime implements world:time(), returning the time of a world.
+func worldTime(l *lua.LState) int {
+	l.Push(lua.LNumber(checkWorld(l).Time()))
+	return 1
+}
+
+// worldSetTime implements world:set_time(time), changing the time of a world.
+func worldSetTime(l *lua.LState) int {
+	checkWorld(l).SetTime(l.CheckInt(2))
+	return 0
+}
+
+// defaultBlock returns the default state of the block with the name passed,
+// which is the block placed by the item with the same name.
+func defaultBlock(name string) (world.Block, bool) {
+	it, ok := world.ItemByName(name, 0)
+	if !ok {
+		return nil, false
+	}
+	b, ok := it.(world.Block)
+	return b, ok
+}
+
+// identifier returns the name passed prefixed with the minecraft namespace if
+// it does not yet have a namespace.
+func identifier(name string) string {
+	if !strings.Contains(name, ":") {
+		return "minecraft:" + name
+	}
+	return name
+}
+
+// toLua converts a block property value to a Lua value.
+func toLua(v any) lua.LValue {
+	switch v := v.(type) {
+	case bool:
+		return lua.LBool(v)
+	case uint8:
+		return lua.LBool(v != 0)
+	case int32:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	}
+	return lua.LNil
+}
+
+// fromLua converts a Lua value to a block property value.
+func fromLua(v lua.LValue) any {
+	switch v := v.(type) {
+	case lua.LBool:
+		return bool(v)
+	case lua.LNumber:
+		return int32(v)
+	}
+	return v.String()
+}
+
+// healingSource is the world.HealingSource of players healed by scripts.
+type healingSource struct{}
+
+// HealingSource ...
+func (healingSource) HealingSource() {}
+
+// command is a cmd.Runnable that runs a command registered by a script.
+type command struct {
+	Args cmd.Optional[cmd.Varargs] `cmd:"args"`
+
+	s  *script
+	fn *lua.LFunction
+}
+
+// Run ...
+func (c command) Run(src cmd.Source, o *cmd.Output) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	if c.s.closed {
+		return
+	}
+	l := c.s.l
+	var sender lua.LValue = lua.LNil
+	if p, ok := src.(*player.Player); ok {
+		sender = newPlayer(l, p)
+	}
+	args := l.NewTable()
+	for _, arg := range strings.Fields(string(c.Args.LoadOr(""))) {
+		args.Append(lua.LString(arg))
+	}
+	if err := c.s.callLocked(c.fn, 1, sender, args); err != nil {
+		o.Errorf("Could not run command: %v", err)
+		c.s.e.log.Error("Error running command.", "script", c.s.name, "err", err)
+		return
+	}
+	if msg := l.Get(-1); msg != lua.LNil {
+		o.Print(msg.String())
+	}
+	l.Pop(1)
+}
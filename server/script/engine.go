@@ -0,0 +1,181 @@
+// Package script implements an engine that runs Lua scripts, so that gameplay
+// logic may be written without recompiling the server. Scripts are .lua files
+// in a folder, each of which runs in its own sandboxed Lua state without
+// access to the file system. Scripts are reloaded automatically when their
+// files change.
+//
+// Scripts interact with the server using the following globals:
+//
+//	on(event, fn)                     -- Handles an event: join, quit, chat, block_break, block_place, item_use, hurt or death.
+//	command(name, description, fn)    -- Registers a command, calling fn(player, args).
+//	after(seconds, fn), every(...)    -- Calls fn once or repeatedly, returning an ID that may be passed to cancel(id).
+//	server.players(), server.player(name), server.broadcast(msg), server.world(name)
+//
+// Players and worlds are passed to scripts as values with methods, such as
+// player:message(text), player:teleport(x, y, z), player:give(item, count),
+// world:block(x, y, z) and world:set_block(x, y, z, block).
+//
+// An Engine is a plugin.Plugin, which must be added to a plugin.Loader to run.
+package script
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/plugin"
+	"golang.org/x/exp/maps"
+)
+
+// Engine loads and runs the Lua scripts in a folder. It reloads scripts when
+// their files are changed, added or removed.
+type Engine struct {
+	dir string
+	api *plugin.API
+	log *slog.Logger
+
+	mu sync.Mutex
+	// s holds the scripts currently loaded, indexed by the path of their file.
+	s map[string]*script
+	// failed holds the modification times of the files of scripts that failed
+	// to load, so that they are not loaded again until they are changed.
+	failed map[string]time.Time
+	stop   chan struct{}
+}
+
+// New creates an Engine that runs the Lua scripts in the folder passed. The
+// folder is created if it does not yet exist.
+func New(dir string) *Engine {
+	return &Engine{dir: dir}
+}
+
+// Info ...
+func (e *Engine) Info() plugin.Info {
+	return plugin.Info{Name: "scripts"}
+}
+
+// Enable loads all scripts and starts watching their folder for changes.
+func (e *Engine) Enable(api *plugin.API) error {
+	if err := os.MkdirAll(e.dir, 0777); err != nil {
+		return err
+	}
+	e.api, e.log = api, api.Log()
+	e.s, e.failed, e.stop = make(map[string]*script), make(map[string]time.Time), make(chan struct{})
+	e.reload()
+	api.HandlePlayers(func(p *player.Player) player.Handler {
+		h := &handler{e: e, p: p}
+		h.fire(nil, "join", nil, nil)
+		return h
+	})
+	go e.watch()
+	return nil
+}
+
+// Disable stops watching the folder of the scripts and closes all scripts.
+func (e *Engine) Disable() error {
+	close(e.stop)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for path, s := range e.s {
+		s.close()
+		delete(e.s, path)
+	}
+	return nil
+}
+
+// watch reloads scripts every second until the Engine is disabled.
+func (e *Engine) watch() {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			e.reload()
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// reload loads the scripts in the folder of the Engine that were added or
+// changed since they were last loaded and closes those that were removed. If
+// a changed script fails to load, the previous version keeps running.
+func (e *Engine) reload() {
+	files := map[string]time.Time{}
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		e.log.Error("Error reading scripts.", "err", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lua") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files[filepath.Join(e.dir, entry.Name())] = info.ModTime()
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	select {
+	case <-e.stop:
+		// The Engine was disabled while reading the folder.
+		return
+	default:
+	}
+	for path, s := range e.s {
+		if _, ok := files[path]; !ok {
+			s.close()
+			delete(e.s, path)
+			e.log.Info("Unloaded script.", "script", s.name)
+		}
+	}
+	paths := maps.Keys(files)
+	slices.Sort(paths)
+	for _, path := range paths {
+		old, ok := e.s[path]
+		if mod, failed := e.failed[path]; (ok && old.mod.Equal(files[path])) || (failed && mod.Equal(files[path])) {
+			continue
+		}
+		s, err := loadScript(e, path, files[path])
+		if err != nil {
+			e.failed[path] = files[path]
+			e.log.Error("Error loading script.", "file", filepath.Base(path), "err", err)
+			continue
+		}
+		delete(e.failed, path)
+		if ok {
+			old.close()
+			// Closing the old script unregistered commands with the same
+			// names as those of the new script.
+			s.registerCommands()
+			e.log.Info("Reloaded script.", "script", s.name)
+		} else {
+			e.log.Info("Loaded script.", "script", s.name)
+		}
+		e.s[path] = s
+	}
+}
+
+// scripts returns all scripts currently loaded in the order of their file
+// names.
+func (e *Engine) scripts() []*script {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	paths := maps.Keys(e.s)
+	slices.Sort(paths)
+	scripts := make([]*script, 0, len(paths))
+	for _, path := range paths {
+		scripts = append(scripts, e.s[path])
+	}
+	return scripts
+}
+
+// Compile time check to make sure Engine implements plugin.Plugin.
+var _ plugin.Plugin = (*Engine)(nil)
@@ -0,0 +1,130 @@
+package script
+
+import (
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// events holds the names of all events that scripts may handle using on.
+var events = map[string]struct{}{
+	"join": {}, "quit": {}, "chat": {}, "block_break": {}, "block_place": {}, "item_use": {}, "hurt": {}, "death": {},
+}
+
+// validEvent checks if scripts may handle the event with the name passed.
+func validEvent(name string) bool {
+	_, ok := events[name]
+	return ok
+}
+
+// handler is a player.Handler that passes the events of a player to the
+// scripts of an Engine. Every event is passed to scripts as a table holding
+// the player and other fields of the event. Events that may be cancelled have
+// a cancel function, as in `ev:cancel()`.
+type handler struct {
+	player.NopHandler
+	e *Engine
+	p *player.Player
+}
+
+// HandleChat passes the "chat" event to scripts. The message field may be
+// changed to change the text of the message.
+func (h *handler) HandleChat(ctx *event.Context, message *string) {
+	h.fire(ctx, "chat", func(ev *lua.LTable) {
+		ev.RawSetString("message", lua.LString(*message))
+	}, func(ev *lua.LTable) {
+		*message = ev.RawGetString("message").String()
+	})
+}
+
+// HandleBlockBreak passes the "block_break" event to scripts.
+func (h *handler) HandleBlockBreak(ctx *event.Context, pos cube.Pos, _ *[]item.Stack, _ *int) {
+	h.fire(ctx, "block_break", func(ev *lua.LTable) {
+		setBlock(ev, pos, h.p.World().Block(pos))
+	}, nil)
+}
+
+// HandleBlockPlace passes the "block_place" event to scripts.
+func (h *handler) HandleBlockPlace(ctx *event.Context, pos cube.Pos, b world.Block) {
+	h.fire(ctx, "block_place", func(ev *lua.LTable) {
+		setBlock(ev, pos, b)
+	}, nil)
+}
+
+// HandleItemUse passes the "item_use" event to scripts.
+func (h *handler) HandleItemUse(ctx *event.Context) {
+	h.fire(ctx, "item_use", func(ev *lua.LTable) {
+		if held, _ := h.p.HeldItems(); !held.Empty() {
+			name, _ := held.Item().EncodeItem()
+			ev.RawSetString("item", lua.LString(name))
+		}
+	}, nil)
+}
+
+// HandleHurt passes the "hurt" event to scripts. The damage field may be
+// changed to change the damage dealt.
+func (h *handler) HandleHurt(ctx *event.Context, damage *float64, _ *time.Duration, _ world.DamageSource) {
+	h.fire(ctx, "hurt", func(ev *lua.LTable) {
+		ev.RawSetString("damage", lua.LNumber(*damage))
+	}, func(ev *lua.LTable) {
+		if d, ok := ev.RawGetString("damage").(lua.LNumber); ok {
+			*damage = float64(d)
+		}
+	})
+}
+
+// HandleDeath passes the "death" event to scripts.
+func (h *handler) HandleDeath(world.DamageSource, *bool) {
+	h.fire(nil, "death", nil, nil)
+}
+
+// HandleQuit passes the "quit" event to scripts.
+func (h *handler) HandleQuit() {
+	h.fire(nil, "quit", nil, nil)
+}
+
+// fire passes an event to all scripts. fill is called to add the fields of
+// the event to its table, after which apply is called with the table once a
+// script handled the event, so that changes made by the script are applied.
+// If ctx is non-nil, the event may be cancelled by scripts.
+func (h *handler) fire(ctx *event.Context, name string, fill, apply func(ev *lua.LTable)) {
+	for _, s := range h.e.scripts() {
+		var ev *lua.LTable
+		s.fire(name, func(l *lua.LState) lua.LValue {
+			ev = l.NewTable()
+			ev.RawSetString("player", newPlayer(l, h.p))
+			if ctx != nil {
+				ev.RawSetString("cancelled", lua.LBool(ctx.Cancelled()))
+				ev.RawSetString("cancel", l.NewFunction(func(l *lua.LState) int {
+					l.CheckTable(1).RawSetString("cancelled", lua.LTrue)
+					return 0
+				}))
+			}
+			if fill != nil {
+				fill(ev)
+			}
+			return ev
+		}, func() {
+			if ctx != nil && lua.LVAsBool(ev.RawGetString("cancelled")) {
+				ctx.Cancel()
+			}
+			if apply != nil {
+				apply(ev)
+			}
+		})
+	}
+}
+
+// setBlock sets the position and name of a block to the table of an event.
+func setBlock(ev *lua.LTable, pos cube.Pos, b world.Block) {
+	name, _ := b.EncodeBlock()
+	ev.RawSetString("x", lua.LNumber(pos[0]))
+	ev.RawSetString("y", lua.LNumber(pos[1]))
+	ev.RawSetString("z", lua.LNumber(pos[2]))
+	ev.RawSetString("block", lua.LString(name))
+}
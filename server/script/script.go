@@ -0,0 +1,200 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// callTimeout is the maximum duration that a single call into a script, such
+// as running its file or calling one of its event handlers, may take. Scripts
+// exceeding it, for example because of an infinite loop, have the call
+// aborted with an error.
+const callTimeout = time.Millisecond * 200
+
+// script is a single Lua script loaded from a file. Every script runs in its
+// own sandboxed Lua state, which has no access to the file system or other
+// parts of the operating system. A script is safe for concurrent use: Calls
+// into the Lua state are serialised.
+type script struct {
+	e    *Engine
+	name string
+	mod  time.Time
+
+	mu       sync.Mutex
+	l        *lua.LState
+	closed   bool
+	handlers map[string][]*lua.LFunction
+	commands []cmd.Command
+	timers   map[int]*time.Timer
+	timerID  int
+}
+
+// loadScript loads and runs the Lua file at the path passed, which was last
+// modified at mod.
+func loadScript(e *Engine, path string, mod time.Time) (*script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open script: %w", err)
+	}
+	defer f.Close()
+	s := &script{
+		e:        e,
+		name:     strings.TrimSuffix(filepath.Base(path), ".lua"),
+		mod:      mod,
+		l:        lua.NewState(lua.Options{SkipOpenLibs: true}),
+		handlers: make(map[string][]*lua.LFunction),
+		timers:   make(map[int]*time.Timer),
+	}
+	s.openLibs()
+	s.bind()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn, err := s.l.Load(f, filepath.Base(path))
+	if err != nil {
+		s.closeLocked()
+		return nil, err
+	}
+	if err := s.callLocked(fn, 0); err != nil {
+		s.closeLocked()
+		return nil, err
+	}
+	return s, nil
+}
+
+// openLibs opens the Lua libraries that scripts may use and removes all
+// functions that access the file system or load other code.
+func (s *script) openLibs() {
+	for _, lib := range []struct {
+		name string
+		f    lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		s.l.Push(s.l.NewFunction(lib.f))
+		s.l.Push(lua.LString(lib.name))
+		s.l.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile", "require", "module"} {
+		s.l.SetGlobal(name, lua.LNil)
+	}
+}
+
+// on registers a function called when the event with the name passed happens.
+// The script must be locked when calling on.
+func (s *script) on(event string, fn *lua.LFunction) {
+	s.handlers[event] = append(s.handlers[event], fn)
+}
+
+// fire calls all functions that the script registered for the event passed,
+// with the Lua value returned by arg as argument. arg is called with the Lua
+// state of the script, and done is called once all functions returned. Neither
+// is called if the script does not handle the event.
+func (s *script) fire(event string, arg func(l *lua.LState) lua.LValue, done func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || len(s.handlers[event]) == 0 {
+		return
+	}
+	v := arg(s.l)
+	for _, fn := range s.handlers[event] {
+		if err := s.callLocked(fn, 0, v); err != nil {
+			s.e.log.Error("Error handling event.", "script", s.name, "event", event, "err", err)
+		}
+	}
+	done()
+}
+
+// callLocked calls a Lua function of the script, aborting it if it takes
+// longer than callTimeout. The nret values returned by the function are left
+// on the stack of the Lua state. The script must be locked when calling
+// callLocked.
+func (s *script) callLocked(fn *lua.LFunction, nret int, args ...lua.LValue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	s.l.SetContext(ctx)
+	defer s.l.RemoveContext()
+	return s.l.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...)
+}
+
+// schedule calls fn after the delay passed, repeating it every delay if
+// repeat is true. The ID of the timer that may be passed to cancel is
+// returned. The script must be locked when calling schedule.
+func (s *script) schedule(delay time.Duration, repeat bool, fn *lua.LFunction) int {
+	s.timerID++
+	id := s.timerID
+	var run func()
+	run = func() {
+		s.mu.Lock()
+		if _, ok := s.timers[id]; !ok || s.closed {
+			s.mu.Unlock()
+			return
+		}
+		if repeat {
+			s.timers[id] = time.AfterFunc(delay, run)
+		} else {
+			delete(s.timers, id)
+		}
+		err := s.callLocked(fn, 0)
+		s.mu.Unlock()
+		if err != nil {
+			s.e.log.Error("Error running timer.", "script", s.name, "err", err)
+		}
+	}
+	s.timers[id] = time.AfterFunc(delay, run)
+	return id
+}
+
+// cancel stops the timer with the ID passed. The script must be locked when
+// calling cancel.
+func (s *script) cancel(id int) {
+	if t, ok := s.timers[id]; ok {
+		t.Stop()
+		delete(s.timers, id)
+	}
+}
+
+// registerCommands registers all commands of the script again, for example
+// after they were unregistered by a previous version of the script.
+func (s *script) registerCommands() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.commands {
+		cmd.Register(c)
+	}
+}
+
+// close stops all timers of the script, unregisters its commands and closes
+// its Lua state.
+func (s *script) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+// closeLocked closes the script. The script must be locked when calling
+// closeLocked.
+func (s *script) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for id := range s.timers {
+		s.cancel(id)
+	}
+	for _, c := range s.commands {
+		cmd.Unregister(c)
+	}
+	s.l.Close()
+}
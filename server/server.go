@@ -7,11 +7,13 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/account"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/internal/blockinternal"
 	"github.com/df-mc/dragonfly/server/internal/iteminternal"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	_ "github.com/df-mc/dragonfly/server/item" // Imported for maintaining correct initialisation order.
+	"github.com/df-mc/dragonfly/server/logging"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/skin"
 	"github.com/df-mc/dragonfly/server/session"
@@ -25,8 +27,8 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/sandertv/gophertunnel/minecraft/text"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -41,16 +43,22 @@ import (
 type Server struct {
 	conf Config
 
-	once    sync.Once
-	started atomic.Bool
+	once            sync.Once
+	started, closed atomic.Bool
 
 	world, nether, end *world.World
+	// dimensions holds the worlds created for the custom dimensions of the
+	// Config. It is not modified after the Server is created.
+	dimensions map[world.Dimension]*world.World
 
 	customBlocks []protocol.BlockEntry
 	customItems  []protocol.ItemComponentEntry
 
 	listeners []Listener
 	incoming  chan *session.Session
+	// metricsSrv is the HTTP server exporting the metrics of the Server. It
+	// is nil if the Config has no MetricsAddress.
+	metricsSrv *http.Server
 
 	pmu sync.RWMutex
 	// p holds a map of all players currently connected to the server. When they
@@ -59,9 +67,23 @@ type Server struct {
 	// pwg is a sync.WaitGroup used to wait for all players to be disconnected
 	// before server shutdown, so that their data is saved properly.
 	pwg sync.WaitGroup
+	// dataMu guards the saved data of players against being edited by
+	// EditOfflinePlayer while the players are joining or connected.
+	dataMu sync.Mutex
+	// online holds, for every player whose data was loaded to join the
+	// server, the number of connections that have not yet saved the data.
+	online map[uuid.UUID]int
 	// wg is used to wait for all Listeners to be closed and their respective
 	// goroutines to be finished.
 	wg sync.WaitGroup
+
+	// saveStop is closed when the Server is closed to stop saving it every
+	// Config.AutoSaveInterval. saving is used to wait for a save in progress.
+	saveStop chan struct{}
+	saving   sync.WaitGroup
+	// removeCrashSave stops the Server from being saved when a goroutine
+	// panics.
+	removeCrashSave func()
 }
 
 // HandleFunc is a function that may be passed to Server.Accept(). It can be
@@ -85,8 +107,10 @@ func (srv *Server) Listen() {
 		panic("start server: already started")
 	}
 
-	srv.conf.Log.Infof("Starting Dragonfly for Minecraft v%v...", protocol.CurrentVersion)
+	srv.conf.Log.Info("Starting Dragonfly for Minecraft v" + protocol.CurrentVersion + "...")
 	srv.startListening()
+	srv.serveMetrics()
+	srv.startSaving()
 	go srv.wait()
 }
 
@@ -133,6 +157,34 @@ func (srv *Server) End() *world.World {
 	return srv.end
 }
 
+// Dimension returns the world of the server created for the world.Dimension
+// passed. Worlds are created for the Overworld, Nether, End and every
+// dimension in the Dimensions field of the Config. If no world exists for the
+// dimension, false is returned.
+func (srv *Server) Dimension(dim world.Dimension) (*world.World, bool) {
+	switch dim {
+	case world.Overworld:
+		return srv.world, true
+	case world.Nether:
+		return srv.nether, true
+	case world.End:
+		return srv.end, true
+	}
+	w, ok := srv.dimensions[dim]
+	return w, ok
+}
+
+// Worlds returns the worlds of all dimensions of the server: The overworld,
+// nether and end, followed by the worlds of the dimensions in the Dimensions
+// field of the Config in the same order.
+func (srv *Server) Worlds() []*world.World {
+	worlds := []*world.World{srv.world, srv.nether, srv.end}
+	for _, dim := range srv.conf.Dimensions {
+		worlds = append(worlds, srv.dimensions[dim])
+	}
+	return worlds
+}
+
 // MaxPlayerCount returns the maximum amount of players that are allowed to
 // play on the server at the same time. Players trying to join when the server
 // is full will be refused to enter. If the config has a maximum player count
@@ -189,7 +241,7 @@ func (srv *Server) CloseOnProgramEnd() {
 	go func() {
 		<-c
 		if err := srv.Close(); err != nil {
-			srv.conf.Log.Errorf("close server: %v", err)
+			srv.conf.Log.Error("Error closing server.", "err", err)
 		}
 	}()
 }
@@ -206,31 +258,56 @@ func (srv *Server) Close() error {
 // close stops the server, storing player and world data to disk when
 // necessary.
 func (srv *Server) close() {
-	srv.conf.Log.Infof("Server shutting down...")
-	defer srv.conf.Log.Infof("Server stopped.")
+	srv.closed.Store(true)
+	srv.conf.Log.Info("Server shutting down...")
+	defer srv.conf.Log.Info("Server stopped.")
+	srv.stopSaving()
 
-	srv.conf.Log.Debugf("Disconnecting players...")
+	srv.conf.Log.Debug("Disconnecting players...")
 	for _, p := range srv.Players() {
 		p.Disconnect(text.Colourf("<yellow>%v</yellow>", srv.conf.ShutdownMessage))
 	}
 	srv.pwg.Wait()
 
-	srv.conf.Log.Debugf("Closing player provider...")
+	srv.conf.Log.Debug("Closing player provider...")
 	if err := srv.conf.PlayerProvider.Close(); err != nil {
-		srv.conf.Log.Errorf("Error while closing player provider: %v", err)
+		srv.conf.Log.Error("Error while closing player provider.", "err", err)
+	}
+	if srv.conf.Moderation != nil {
+		srv.conf.Log.Debug("Closing moderation provider...")
+		if err := srv.conf.Moderation.Close(); err != nil {
+			srv.conf.Log.Error("Error while closing moderation provider.", "err", err)
+		}
+	}
+	if srv.conf.Accounts != nil {
+		srv.conf.Log.Debug("Closing account provider...")
+		if err := srv.conf.Accounts.Close(); err != nil {
+			srv.conf.Log.Error("Error while closing account provider.", "err", err)
+		}
+	}
+	srv.conf.Log.Debug("Closing permission provider...")
+	if err := srv.conf.PermissionProvider.Close(); err != nil {
+		srv.conf.Log.Error("Error while closing permission provider.", "err", err)
 	}
 
-	srv.conf.Log.Debugf("Closing worlds...")
-	for _, w := range []*world.World{srv.end, srv.nether, srv.world} {
+	srv.conf.Log.Debug("Closing worlds...")
+	for _, w := range append(srv.customWorlds(), srv.end, srv.nether, srv.world) {
 		if err := w.Close(); err != nil {
-			srv.conf.Log.Errorf("Error closing %v: %v", w.Dimension(), err)
+			srv.conf.Log.Error("Error closing world.", "dimension", w.Dimension(), "err", err)
 		}
 	}
 
-	srv.conf.Log.Debugf("Closing listeners...")
+	if srv.metricsSrv != nil {
+		srv.conf.Log.Debug("Closing metrics server...")
+		if err := srv.metricsSrv.Close(); err != nil {
+			srv.conf.Log.Error("Error closing metrics server.", "err", err)
+		}
+	}
+
+	srv.conf.Log.Debug("Closing listeners...")
 	for _, l := range srv.listeners {
 		if err := l.Close(); err != nil {
-			srv.conf.Log.Errorf("Error closing listener: %v", err)
+			srv.conf.Log.Error("Error closing listener.", "err", err)
 		}
 	}
 }
@@ -280,7 +357,8 @@ func (srv *Server) startListening() {
 	for _, lf := range srv.conf.Listeners {
 		l, err := lf(srv.conf)
 		if err != nil {
-			srv.conf.Log.Fatalf("create listener: %v", err)
+			srv.conf.Log.Error("Error creating listener.", "err", err)
+			os.Exit(1)
 		}
 		srv.listeners = append(srv.listeners, l)
 		go srv.listen(l)
@@ -332,22 +410,26 @@ func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listen
 	data := srv.defaultGameData()
 
 	var playerData *player.Data
-	if d, err := srv.conf.PlayerProvider.Load(id, srv.dimension); err == nil {
+	srv.dataMu.Lock()
+	srv.online[id]++
+	d, err := srv.conf.PlayerProvider.Load(id, srv.dimension)
+	srv.dataMu.Unlock()
+	if err == nil {
 		if d.World == nil {
 			d.World = srv.world
 		}
 		data.PlayerPosition = vec64To32(d.Position).Add(mgl32.Vec3{0, 1.62})
-		dim, _ := world.DimensionID(d.World.Dimension())
-		data.Dimension = int32(dim)
+		data.Dimension = int32(world.NetworkDimensionID(d.World.Dimension()))
 		data.Yaw, data.Pitch = float32(d.Yaw), float32(d.Pitch)
 
 		playerData = &d
 	}
 
 	if err := conn.StartGameContext(ctx, data); err != nil {
+		srv.releasePlayerData(id)
 		_ = l.Disconnect(conn, "Connection timeout.")
 
-		srv.conf.Log.Debugf("connection %v failed spawning: %v\n", conn.RemoteAddr(), err)
+		logging.With(srv.conf.Log, logging.Network).Debug("Connection failed spawning.", "raddr", conn.RemoteAddr().String(), "err", err)
 		return
 	}
 	_ = conn.WritePacket(&packet.ItemComponent{Items: srv.customItems})
@@ -390,14 +472,10 @@ func (srv *Server) defaultGameData() minecraft.GameData {
 
 // dimension returns a world by a dimension passed.
 func (srv *Server) dimension(dimension world.Dimension) *world.World {
-	switch dimension {
-	default:
-		return srv.world
-	case world.Nether:
-		return srv.nether
-	case world.End:
-		return srv.end
+	if w, ok := srv.Dimension(dimension); ok {
+		return w
 	}
+	return srv.world
 }
 
 // checkNetIsolation checks if a loopback exempt is in place to allow the
@@ -413,7 +491,7 @@ func (srv *Server) checkNetIsolation() {
 		return
 	}
 	const loopbackExemptCmd = `CheckNetIsolation LoopbackExempt -a -n="Microsoft.MinecraftUWP_8wekyb3d8bbwe"`
-	srv.conf.Log.Infof("You are currently unable to join the server on this machine. Run %v in an admin PowerShell session to resolve.\n", loopbackExemptCmd)
+	srv.conf.Log.Info("You are currently unable to join the server on this machine. Run " + loopbackExemptCmd + " in an admin PowerShell session to resolve.")
 }
 
 // handleSessionClose handles the closing of a session. It removes the player
@@ -423,6 +501,7 @@ func (srv *Server) handleSessionClose(c session.Controllable) {
 	p, ok := srv.p[c.UUID()]
 	delete(srv.p, c.UUID())
 	srv.pmu.Unlock()
+	defer srv.releasePlayerData(c.UUID())
 	if !ok {
 		// When a player disconnects immediately after a session is started, it might not be added to the players map
 		// yet. This is expected, but we need to be careful not to crash when this happens.
@@ -430,7 +509,10 @@ func (srv *Server) handleSessionClose(c session.Controllable) {
 	}
 
 	if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
-		srv.conf.Log.Errorf("Error while saving data: %v", err)
+		srv.conf.Log.Error("Error while saving data.", "err", err)
+	}
+	if err := srv.conf.PermissionProvider.Save(p.UUID(), p.Permissions().Data()); err != nil {
+		srv.conf.Log.Error("Error while saving permissions.", "err", err)
 	}
 	srv.pwg.Done()
 }
@@ -438,14 +520,29 @@ func (srv *Server) handleSessionClose(c session.Controllable) {
 // createPlayer creates a new player instance using the UUID and connection
 // passed.
 func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Data) *session.Session {
-	w, gm, pos := srv.world, srv.world.DefaultGameMode(), srv.world.Spawn().Vec3Middle()
+	w, gm, pos := srv.world, srv.world.DefaultGameMode(), srv.world.PlayerSpawn(id).Vec3Middle()
 	if data != nil {
 		w, gm, pos = data.World, data.GameMode, data.Position
+		if w.ForceGameMode() {
+			gm = w.DefaultGameMode()
+		}
+	}
+	metadata, err := srv.conf.TransferStore.Take(id)
+	if err != nil {
+		srv.conf.Log.Error("Error while loading transfer metadata.", "err", err)
 	}
-	s := session.New(conn, srv.conf.MaxChunkRadius, srv.conf.Log, srv.conf.JoinMessage, srv.conf.QuitMessage)
+	s := session.New(conn, srv.conf.MaxChunkRadius, logging.With(srv.conf.Log, logging.Network), srv.conf.JoinMessage, srv.conf.QuitMessage, srv.conf.TransferStore, metadata)
 	p := player.NewWithSession(conn.IdentityData().DisplayName, conn.IdentityData().XUID, id, srv.parseSkin(conn.ClientData()), s, pos, data)
+	p.SetCombat(srv.conf.Combat)
+	p.SetMovementValidation(srv.conf.MovementValidation)
+	if perms, err := srv.conf.PermissionProvider.Load(id); err == nil {
+		p.Permissions().Load(perms)
+	}
 
 	s.Spawn(p, pos, w, gm, srv.handleSessionClose)
+	if m := srv.conf.Accounts; m != nil && m.Mode() == account.ModePassword && !m.LoggedIn(id) {
+		srv.guardAccount(p, m)
+	}
 	srv.pwg.Add(1)
 	return s
 }
@@ -454,16 +551,11 @@ func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Da
 // the program if the world could not be loaded. The layers passed are used to
 // create a generator.Flat that is used as generator for the world.
 func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *world.World {
-	logger := srv.conf.Log
-	if v, ok := logger.(interface {
-		WithField(key string, field any) *logrus.Entry
-	}); ok {
-		// Add a dimension field to be able to distinguish between the different
-		// dimensions in the log. Dimensions implement fmt.Stringer so we can
-		// just fmt.Sprint them for a readable name.
-		logger = v.WithField("dimension", strings.ToLower(fmt.Sprint(dim)))
-	}
-	logger.Debugf("Loading world...")
+	// Add a dimension attribute to be able to distinguish between the
+	// different dimensions in the log. Dimensions implement fmt.Stringer so we
+	// can just fmt.Sprint them for a readable name.
+	logger := logging.With(srv.conf.Log, logging.World).With("dimension", strings.ToLower(fmt.Sprint(dim)))
+	logger.Debug("Loading world...")
 
 	conf := world.Config{
 		Log:             logger,
@@ -483,7 +575,7 @@ func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *
 		},
 	}
 	w := conf.New()
-	logger.Infof(`Opened world "%v".`, w.Name())
+	logger.Info("Opened world.", "name", w.Name())
 	return w
 }
 
@@ -591,6 +683,14 @@ func biomes() map[string]any {
 			blueSpores, redSpores := s.Spores()
 			definition["blue_spores"], definition["red_spores"] = float32(blueSpores), float32(redSpores)
 		}
+		if c, ok := b.(world.ColouredBiome); ok {
+			col := c.WaterColour()
+			definition["waterColorR"], definition["waterColorG"] = float32(col.R)/255, float32(col.G)/255
+			definition["waterColorB"], definition["waterColorA"] = float32(col.B)/255, float32(col.A)/255
+		}
+		if t, ok := b.(world.TaggedBiome); ok {
+			definition["tags"] = t.Tags()
+		}
 		definitions[b.String()] = definition
 	}
 	return definitions
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"github.com/df-mc/dragonfly/server/player/camera"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// sendCameraPresets sends all camera presets registered in the camera package to the client, so that they
+// may be used in camera instructions.
+func (s *Session) sendCameraPresets() {
+	presets := camera.Presets()
+	s.cameraPresets = make(map[string]uint32, len(presets))
+
+	pk := &packet.CameraPresets{Presets: make([]protocol.CameraPreset, 0, len(presets))}
+	for i, p := range presets {
+		s.cameraPresets[p.Name()] = uint32(i)
+
+		preset := protocol.CameraPreset{Name: p.Name(), Parent: p.Parent()}
+		if pos, ok := p.Position(); ok {
+			preset.PosX = protocol.Option(float32(pos[0]))
+			preset.PosY = protocol.Option(float32(pos[1]))
+			preset.PosZ = protocol.Option(float32(pos[2]))
+		}
+		if rot, ok := p.Rotation(); ok {
+			preset.RotX = protocol.Option(float32(rot.Pitch()))
+			preset.RotY = protocol.Option(float32(rot.Yaw()))
+		}
+		if p.PlayerAudio() {
+			preset.AudioListener = protocol.Option(byte(protocol.AudioListenerPlayer))
+		}
+		if p.PlayerEffects() {
+			preset.PlayerEffects = protocol.Option(true)
+		}
+		pk.Presets = append(pk.Presets, preset)
+	}
+	s.writePacket(pk)
+}
+
+// SendCameraSet sends a camera.Set instruction to the client. Nothing happens if the camera.Preset of the
+// instruction was not registered when the client joined.
+func (s *Session) SendCameraSet(set camera.Set) {
+	if s == Nop {
+		return
+	}
+	index, ok := s.cameraPresets[set.Preset().Name()]
+	if !ok {
+		return
+	}
+	instruction := protocol.CameraInstructionSet{Preset: index}
+	if e, ok := set.Ease(); ok {
+		instruction.Ease = protocol.Option(protocol.CameraEase{Type: uint8(e.Type), Duration: float32(e.Duration.Seconds())})
+	}
+	if pos, ok := set.Position(); ok {
+		instruction.Position = protocol.Option(vec64To32(pos))
+	}
+	if rot, ok := set.Rotation(); ok {
+		instruction.Rotation = protocol.Option(mgl32.Vec2{float32(rot.Pitch()), float32(rot.Yaw())})
+	}
+	if facing, ok := set.Facing(); ok {
+		instruction.Facing = protocol.Option(vec64To32(facing))
+	}
+	s.writePacket(&packet.CameraInstruction{Set: protocol.Option(instruction)})
+}
+
+// SendCameraFade sends a camera.Fade instruction to the client.
+func (s *Session) SendCameraFade(f camera.Fade) {
+	if s == Nop {
+		return
+	}
+	s.writePacket(&packet.CameraInstruction{Fade: protocol.Option(protocol.CameraInstructionFade{
+		TimeData: protocol.Option(protocol.CameraFadeTimeData{
+			FadeInDuration:  float32(f.FadeInDuration().Seconds()),
+			WaitDuration:    float32(f.WaitDuration().Seconds()),
+			FadeOutDuration: float32(f.FadeOutDuration().Seconds()),
+		}),
+		Colour: protocol.Option(f.Colour()),
+	})})
+}
+
+// ClearCamera clears all camera instructions sent to the client, returning the camera to the player.
+func (s *Session) ClearCamera() {
+	if s == Nop {
+		return
+	}
+	s.writePacket(&packet.CameraInstruction{Clear: protocol.Option(true)})
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"bytes"
+	"slices"
 
 	"github.com/cespare/xxhash/v2"
 	"github.com/df-mc/dragonfly/server/block/cube"
@@ -38,16 +39,14 @@ func (s *Session) ViewSubChunks(center world.SubChunkPos, offsets []protocol.Sub
 			entries = append(entries, protocol.SubChunkEntry{Result: protocol.SubChunkResultIndexOutOfBounds, Offset: offset})
 			continue
 		}
-		col, ok := s.chunkLoader.Chunk(world.ChunkPos{
-			center.X() + int32(offset[0]),
-			center.Z() + int32(offset[2]),
-		})
+		pos := world.ChunkPos{center.X() + int32(offset[0]), center.Z() + int32(offset[2])}
+		col, ok := s.chunkLoader.Chunk(pos)
 		if !ok {
 			entries = append(entries, protocol.SubChunkEntry{Result: protocol.SubChunkResultChunkNotFound, Offset: offset})
 			continue
 		}
 		col.Lock()
-		entries = append(entries, s.subChunkEntry(offset, ind, col, transaction))
+		entries = append(entries, s.subChunkEntry(w, pos, offset, ind, col, transaction))
 		col.Unlock()
 	}
 	if s.conn.ClientCacheEnabled() && len(transaction) > 0 {
@@ -55,7 +54,7 @@ func (s *Session) ViewSubChunks(center world.SubChunkPos, offsets []protocol.Sub
 		s.openChunkTransactions = append(s.openChunkTransactions, transaction)
 		s.blobMu.Unlock()
 	}
-	dim, _ := world.DimensionID(w.Dimension())
+	dim := world.NetworkDimensionID(w.Dimension())
 	s.writePacket(&packet.SubChunk{
 		Dimension:       int32(dim),
 		Position:        protocol.SubChunkPos(center),
@@ -64,7 +63,7 @@ func (s *Session) ViewSubChunks(center world.SubChunkPos, offsets []protocol.Sub
 	})
 }
 
-func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *world.Column, transaction map[uint64]struct{}) protocol.SubChunkEntry {
+func (s *Session) subChunkEntry(w *world.World, pos world.ChunkPos, offset protocol.SubChunkOffset, ind int16, col *world.Column, transaction map[uint64]struct{}) protocol.SubChunkEntry {
 	chunkMap := col.Chunk.HeightMap()
 	subMapType, subMap := byte(protocol.HeightMapDataHasData), make([]int8, 256)
 	higher, lower := true, true
@@ -101,7 +100,7 @@ func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *
 		}
 	}
 
-	serialisedSubChunk := chunk.EncodeSubChunk(col.Chunk, chunk.NetworkEncoding, int(ind))
+	serialisedSubChunk := w.NetworkSubChunk(pos, col, ind)
 
 	blockEntityBuf := bytes.NewBuffer(nil)
 	enc := nbt.NewEncoderWithEncoding(blockEntityBuf, nbt.NetworkLittleEndian)
@@ -115,7 +114,7 @@ func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *
 
 	entry := protocol.SubChunkEntry{
 		Result:        protocol.SubChunkResultSuccess,
-		RawPayload:    append(serialisedSubChunk, blockEntityBuf.Bytes()...),
+		RawPayload:    slices.Concat(serialisedSubChunk, blockEntityBuf.Bytes()),
 		HeightMapType: subMapType,
 		HeightMapData: subMap,
 		Offset:        offset,
@@ -131,17 +130,33 @@ func (s *Session) subChunkEntry(offset protocol.SubChunkOffset, ind int16, col *
 	return entry
 }
 
-// dimensionID  returns the dimension ID of the world that the session is in.
+// dimensionID  returns the dimension ID of the world that the session is in, as known by the client.
 func (s *Session) dimensionID() int32 {
-	d, _ := world.DimensionID(s.c.World().Dimension())
-	return int32(d)
+	return int32(world.NetworkDimensionID(s.c.World().Dimension()))
+}
+
+// encodeBiomes encodes the biomes of a chunk to be sent to the client.
+func (s *Session) encodeBiomes(c *chunk.Chunk) []byte {
+	return s.padBiomes(chunk.EncodeBiomes(c, chunk.NetworkEncoding), c)
+}
+
+// padBiomes pads the network encoded biomes of a chunk passed, so that the client receives biomes for every
+// sub chunk of the dimension it is in. The chunks of a world.CustomDimension may be lower than the dimension
+// known by the client, in which case the top-most biomes of the chunk are repeated.
+func (s *Session) padBiomes(biomes []byte, c *chunk.Chunk) []byte {
+	dim, _ := world.DimensionByID(int(s.dimensionID()))
+	for n := (dim.Range().Height() - c.Range().Height()) >> 4; n > 0; n-- {
+		// A paletted storage header of 0xff means the storage is a copy of the previous one.
+		biomes = append(biomes, 0x7f<<1|1)
+	}
+	return biomes
 }
 
 // sendBlobHashes sends chunk blob hashes of the data of the chunk and stores the data in a map of blobs. Only
 // data that the client doesn't yet have will be sent over the network.
 func (s *Session) sendBlobHashes(pos world.ChunkPos, c *chunk.Chunk, blockEntities map[cube.Pos]world.Block) {
 	if subChunkRequests {
-		biomes := chunk.EncodeBiomes(c, chunk.NetworkEncoding)
+		biomes := s.encodeBiomes(c)
 		if hash := xxhash.Sum64(biomes); s.trackBlob(hash, biomes) {
 			s.writePacket(&packet.LevelChunk{
 				Dimension:       s.dimensionID(),
@@ -159,7 +174,7 @@ func (s *Session) sendBlobHashes(pos world.ChunkPos, c *chunk.Chunk, blockEntiti
 	var (
 		data   = chunk.Encode(c, chunk.NetworkEncoding)
 		count  = uint32(len(data.SubChunks))
-		blobs  = append(data.SubChunks, data.Biomes)
+		blobs  = append(data.SubChunks, s.padBiomes(data.Biomes, c))
 		hashes = make([]uint64, len(blobs))
 		m      = make(map[uint64]struct{}, len(blobs))
 	)
@@ -172,7 +187,7 @@ func (s *Session) sendBlobHashes(pos world.ChunkPos, c *chunk.Chunk, blockEntiti
 	s.openChunkTransactions = append(s.openChunkTransactions, m)
 	if l := len(s.blobs); l > 4096 {
 		s.blobMu.Unlock()
-		s.log.Errorf("player %v has too many blobs pending %v: disconnecting", s.c.Name(), l)
+		s.log.Error("Too many blobs pending: disconnecting.", "blobs", l)
 		_ = s.c.Close()
 		return
 	}
@@ -210,7 +225,7 @@ func (s *Session) sendNetworkChunk(pos world.ChunkPos, c *chunk.Chunk, blockEnti
 			SubChunkCount:   protocol.SubChunkRequestModeLimited,
 			Position:        protocol.ChunkPos(pos),
 			HighestSubChunk: c.HighestFilledSubChunk(),
-			RawPayload:      append(chunk.EncodeBiomes(c, chunk.NetworkEncoding), 0),
+			RawPayload:      append(s.encodeBiomes(c), 0),
 		})
 		return
 	}
@@ -220,7 +235,7 @@ func (s *Session) sendNetworkChunk(pos world.ChunkPos, c *chunk.Chunk, blockEnti
 	for _, s := range data.SubChunks {
 		_, _ = chunkBuf.Write(s)
 	}
-	_, _ = chunkBuf.Write(data.Biomes)
+	_, _ = chunkBuf.Write(s.padBiomes(data.Biomes, c))
 
 	// Length of 1 byte for the border block count.
 	chunkBuf.WriteByte(0)
@@ -248,7 +263,7 @@ func (s *Session) trackBlob(hash uint64, blob []byte) bool {
 	s.blobMu.Lock()
 	if l := len(s.blobs); l > 4096 {
 		s.blobMu.Unlock()
-		s.log.Errorf("player %v has too many blobs pending %v: disconnecting", s.c.Name(), l)
+		s.log.Error("Too many blobs pending: disconnecting.", "blobs", l)
 		_ = s.c.Close()
 		return false
 	}
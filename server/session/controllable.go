@@ -1,12 +1,16 @@
 package session
 
 import (
+	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/inventory"
 	"github.com/df-mc/dragonfly/server/player/chat"
+	"github.com/df-mc/dragonfly/server/player/dialogue"
 	"github.com/df-mc/dragonfly/server/player/form"
 	"github.com/df-mc/dragonfly/server/player/skin"
 	"github.com/df-mc/dragonfly/server/world"
@@ -23,15 +27,21 @@ type Controllable interface {
 	world.Entity
 	item.User
 	form.Submitter
+	dialogue.Submitter
 	cmd.Source
 	chat.Subscriber
 
 	Locale() language.Tag
 
 	SetHeldItems(right, left item.Stack)
+	ChangeHeldSlot(from, to int) bool
 
 	Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64)
+	Riding() (entity.Rideable, bool)
+	Dismount()
+	DriveVehicle(input entity.RiderInput)
 	Speed() float64
+	Attribute(a attribute.Attribute) float64
 
 	Chat(msg ...any)
 	ExecuteCommand(commandLine string)
@@ -86,6 +96,8 @@ type Controllable interface {
 	OpenSign(pos cube.Pos, frontSide bool)
 	EditSign(pos cube.Pos, frontText, backText string) error
 	TurnLecternPage(pos cube.Pos, page int) error
+	UpdateStructureBlock(pos cube.Pos, s block.StructureBlock, trigger bool) error
+	UpdateCommandBlock(pos cube.Pos, c block.CommandBlock) error
 
 	EnderChestInventory() *inventory.Inventory
 
@@ -99,4 +111,6 @@ type Controllable interface {
 	// entity looks in the world.
 	Skin() skin.Skin
 	SetSkin(skin.Skin)
+	// Emote makes the controllable perform the emote with the UUID passed, showing it to all viewers.
+	Emote(emote uuid.UUID)
 }
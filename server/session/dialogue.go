@@ -0,0 +1,84 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/df-mc/dragonfly/server/player/dialogue"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// openDialogue holds the state of a dialogue.Dialogue shown to a Session.
+type openDialogue struct {
+	d     dialogue.Dialogue
+	e     world.Entity
+	page  dialogue.Page
+	scene string
+}
+
+// dialogueAction is the JSON representation of a button on a page of a dialogue, as sent to the client.
+type dialogueAction struct {
+	ButtonName string     `json:"button_name"`
+	Data       []struct{} `json:"data"`
+	Mode       int        `json:"mode"`
+	Text       string     `json:"text"`
+	Type       int        `json:"type"`
+}
+
+// SendDialogue shows a dialogue.Dialogue to the client, using the world.Entity passed as the NPC showing the
+// dialogue. Nothing happens if the entity is not visible to the client.
+func (s *Session) SendDialogue(d dialogue.Dialogue, e world.Entity) {
+	if s == Nop {
+		return
+	}
+	s.dialogueMu.Lock()
+	defer s.dialogueMu.Unlock()
+	s.sendDialoguePage(d, e, d.Page(0))
+}
+
+// CloseDialogue closes the dialogue.Dialogue currently shown to the client, if any.
+func (s *Session) CloseDialogue() {
+	if s == Nop {
+		return
+	}
+	s.dialogueMu.Lock()
+	od := s.openDialogue
+	s.openDialogue = nil
+	s.dialogueMu.Unlock()
+
+	if od != nil {
+		s.writePacket(&packet.NPCDialogue{
+			EntityUniqueID: s.entityRuntimeID(od.e),
+			ActionType:     packet.NPCDialogueActionClose,
+			SceneName:      od.scene,
+		})
+	}
+}
+
+// sendDialoguePage sends a page of a dialogue.Dialogue to the client. Session.dialogueMu must be held when
+// calling sendDialoguePage.
+func (s *Session) sendDialoguePage(d dialogue.Dialogue, e world.Entity, page dialogue.Page) {
+	id := s.entityRuntimeID(e)
+	if id == 0 {
+		return
+	}
+	actions := make([]dialogueAction, 0, len(page.Buttons))
+	for _, b := range page.Buttons {
+		actions = append(actions, dialogueAction{ButtonName: b.Text(), Data: []struct{}{}, Type: 1})
+	}
+	b, _ := json.Marshal(actions)
+
+	s.dialogueScene++
+	od := &openDialogue{d: d, e: e, page: page, scene: fmt.Sprintf("dragonfly:dialogue_%v", s.dialogueScene)}
+	s.openDialogue = od
+
+	s.writePacket(&packet.NPCDialogue{
+		EntityUniqueID: id,
+		ActionType:     packet.NPCDialogueActionOpen,
+		Dialogue:       page.Text,
+		SceneName:      od.scene,
+		NPCName:        d.Title(),
+		ActionJSON:     string(b),
+	})
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
@@ -37,6 +38,9 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 	if ent, ok := e.(*entity.Ent); ok {
 		s.addSpecificMetadata(ent.Behaviour(), m)
 	}
+	if mob, ok := e.(*entity.Mob); ok {
+		s.addSpecificMetadata(mob.Behaviour(), m)
+	}
 	return m
 }
 
@@ -69,6 +73,9 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 	if o, ok := e.(onFire); ok && o.OnFireDuration() > 0 {
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagOnFire)
 	}
+	if f, ok := e.(freezing); ok && f.FreezeProgress() > 0 {
+		m[protocol.EntityDataKeyFreezingEffectStrength] = float32(f.FreezeProgress())
+	}
 	if u, ok := e.(using); ok && u.UsingItem() {
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagUsingItem)
 	}
@@ -97,14 +104,55 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 	if sc, ok := e.(scaled); ok {
 		m[protocol.EntityDataKeyScale] = float32(sc.Scale())
 	}
+	if b, ok := e.(baby); ok && b.Baby() {
+		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagBaby)
+	}
+	if w, ok := e.(wither); ok {
+		m[protocol.EntityDataKeyInventory] = int32(w.InvulnerableDuration().Milliseconds() / 50)
+		keys := []uint32{protocol.EntityDataKeyTargetA, protocol.EntityDataKeyTargetB, protocol.EntityDataKeyTargetC}
+		for i, t := range w.HeadTargets() {
+			m[keys[i]] = int64(0)
+			if t != nil {
+				m[keys[i]] = int64(s.entityRuntimeID(t))
+			}
+		}
+	}
+	if w, ok := e.(warden); ok {
+		if w.Emerging() {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagEmerging)
+		}
+		if w.Digging() {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagDigging)
+		}
+		if w.Sniffing() {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSniffing)
+		}
+		if w.ChargingSonicBoom() {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSonicBoom)
+		}
+	}
+	if d, ok := e.(dancer); ok && d.Dancing() {
+		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagDancing)
+	}
+	if c, ok := e.(enderCrystal); ok {
+		if c.ShowBase() {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagShowBottom)
+		}
+		if target, beam := c.BeamTarget(); beam {
+			m[protocol.EntityDataKeyBlockTarget] = protocol.BlockPos{int32(target[0]), int32(target[1]), int32(target[2])}
+		}
+	}
 	if t, ok := e.(tnt); ok {
 		m[protocol.EntityDataKeyFuseTime] = int32(t.Fuse().Milliseconds() / 50)
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagIgnited)
 	}
 	if n, ok := e.(named); ok {
 		m[protocol.EntityDataKeyName] = n.NameTag()
-		m[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagAlwaysShowName)
+		m[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(0)
+		if v, ok := e.(nameVisibility); !ok || v.NameTagAlwaysVisible() {
+			m[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagAlwaysShowName)
+		}
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagShowName)
 	}
 	if sc, ok := e.(scoreTag); ok {
@@ -129,7 +177,7 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 	if l, ok := e.(living); ok && s.c == e {
 		deathPos, deathDimension, died := l.DeathPosition()
 		if died {
-			dim, _ := world.DimensionID(deathDimension)
+			dim := world.NetworkDimensionID(deathDimension)
 			m[protocol.EntityDataKeyPlayerLastDeathPosition] = vec64To32(deathPos)
 			m[protocol.EntityDataKeyPlayerLastDeathDimension] = int32(dim)
 		}
@@ -158,12 +206,36 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 			}
 		}
 	}
+	if l, ok := e.(entity.Leashable); ok {
+		m[protocol.EntityDataKeyLeashHolder] = int64(-1)
+		if holder, leashed := l.LeashHolder(); leashed {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagLeashed)
+			m[protocol.EntityDataKeyLeashHolder] = int64(s.entityRuntimeID(holder))
+		}
+	}
+	if r, ok := e.(entity.Rider); ok {
+		if ridden, riding := r.Riding(); riding {
+			seat, _ := ridden.Seats().Seat(r)
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagRiding)
+			m[protocol.EntityDataKeySeatOffset] = vec64To32(ridden.Seats().Offset(seat))
+		}
+	}
+	if r, ok := e.(entity.Rideable); ok && r.Seats().Len() > 0 {
+		m[protocol.EntityDataKeyControllingSeatIndex] = int32(0)
+	}
 	if v, ok := e.(variable); ok {
 		m[protocol.EntityDataKeyVariant] = v.Variant()
 	}
 	if mv, ok := e.(markVariable); ok {
 		m[protocol.EntityDataKeyMarkVariant] = mv.MarkVariant()
 	}
+	if p, ok := e.(peeker); ok {
+		m[protocol.EntityDataKeyPeekID] = p.Peek()
+	}
+	if n, ok := e.(npc); ok {
+		m[protocol.EntityDataKeyHasNPC] = uint8(1)
+		m[protocol.EntityDataKeySkinID] = int32(n.SkinIndex())
+	}
 }
 
 type sneaker interface {
@@ -200,6 +272,10 @@ type scaled interface {
 	Scale() float64
 }
 
+type baby interface {
+	Baby() bool
+}
+
 type owned interface {
 	Owner() world.Entity
 }
@@ -208,6 +284,10 @@ type named interface {
 	NameTag() string
 }
 
+type nameVisibility interface {
+	NameTagAlwaysVisible() bool
+}
+
 type scoreTag interface {
 	ScoreTag() string
 }
@@ -229,6 +309,10 @@ type onFire interface {
 	OnFireDuration() time.Duration
 }
 
+type freezing interface {
+	FreezeProgress() float64
+}
+
 type effectBearer interface {
 	Effects() []effect.Effect
 }
@@ -254,10 +338,35 @@ type gameMode interface {
 	GameMode() world.GameMode
 }
 
+type vanisher interface {
+	Vanished() bool
+}
+
 type tnt interface {
 	Fuse() time.Duration
 }
 
+type wither interface {
+	InvulnerableDuration() time.Duration
+	HeadTargets() []world.Entity
+}
+
+type warden interface {
+	Emerging() bool
+	Digging() bool
+	Sniffing() bool
+	ChargingSonicBoom() bool
+}
+
+type dancer interface {
+	Dancing() bool
+}
+
+type enderCrystal interface {
+	ShowBase() bool
+	BeamTarget() (cube.Pos, bool)
+}
+
 type living interface {
 	DeathPosition() (mgl64.Vec3, world.Dimension, bool)
 }
@@ -269,3 +378,11 @@ type variable interface {
 type markVariable interface {
 	MarkVariant() int32
 }
+
+type npc interface {
+	SkinIndex() int
+}
+
+type peeker interface {
+	Peek() uint8
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// CommandBlockUpdateHandler handles the CommandBlockUpdate packet, sent when a player edits a command block
+// using its interface.
+type CommandBlockUpdateHandler struct{}
+
+// Handle ...
+func (CommandBlockUpdateHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.CommandBlockUpdate)
+	if !pk.Block {
+		// Command block minecarts are not supported.
+		return nil
+	}
+	pos := blockPosFromProtocol(pk.Position)
+	if !canReach(s.c, pos.Vec3Middle()) {
+		return fmt.Errorf("block at %v is not within reach", pos)
+	}
+	b, ok := s.c.World().Block(pos).(block.CommandBlock)
+	if !ok {
+		s.log.Debug("Command block update for position without command block.", "pos", pos)
+		return nil
+	}
+	for _, t := range block.CommandBlockTypes() {
+		if uint32(t.Uint8()) == pk.Mode {
+			b.Type = t
+		}
+	}
+	b.Conditional, b.AlwaysActive = pk.Conditional, !pk.NeedsRedstone
+	b.Command, b.CustomName, b.TrackOutput = pk.Command, pk.Name, pk.ShouldTrackOutput
+	if !b.TrackOutput {
+		b.LastOutput = ""
+	}
+	b.TickDelay, b.ExecuteOnFirstTick = max(int(pk.TickDelay), 0), pk.ExecuteOnFirstTick
+	return s.c.UpdateCommandBlock(pos, b)
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
@@ -17,6 +18,14 @@ func (h *InteractHandler) Handle(p packet.Packet, s *Session) error {
 	switch pk.ActionType {
 	case packet.InteractActionMouseOverEntity:
 		// We don't need this action.
+	case packet.InteractActionLeaveVehicle:
+		s.c.Dismount()
+	case packet.InteractActionNPCOpen:
+		if e, ok := s.entityFromRuntimeID(pk.TargetEntityRuntimeID); ok {
+			if n, ok := e.(*entity.NPC); ok {
+				s.c.UseItemOnEntity(n)
+			}
+		}
 	case packet.InteractActionOpenInventory:
 		if s.invOpened {
 			// When there is latency, this might end up being sent multiple times. If we send a ContainerOpen
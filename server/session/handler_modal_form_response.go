@@ -35,7 +35,12 @@ func (h *ModalFormResponseHandler) Handle(p packet.Packet, s *Session) error {
 		resp = nil
 	}
 	if !ok {
-		return fmt.Errorf("no form with ID %v currently opened", pk.FormID)
+		if pk.FormID <= h.currentID.Load() {
+			// The form was sent before, but was either already submitted or dropped because too many forms were
+			// opened at once. The response is ignored so that forms cannot be submitted twice.
+			return nil
+		}
+		return fmt.Errorf("no form with ID %v was ever sent", pk.FormID)
 	}
 	if err := f.SubmitJSON(resp, s.c); err != nil {
 		return fmt.Errorf("error submitting form data: %w", err)
@@ -0,0 +1,47 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// NPCRequestHandler handles the NPCRequest packet.
+type NPCRequestHandler struct{}
+
+// Handle ...
+func (h *NPCRequestHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.NPCRequest)
+
+	s.dialogueMu.Lock()
+	od := s.openDialogue
+	if od == nil || od.scene != pk.SceneName || s.entityRuntimeID(od.e) != pk.EntityRuntimeID {
+		// The request is for a dialogue that is no longer shown, for example because the client closed the
+		// previous page of a dialogue after the next page was sent.
+		s.dialogueMu.Unlock()
+		return nil
+	}
+	switch pk.RequestType {
+	case packet.NPCRequestActionExecuteAction:
+		if int(pk.ActionType) >= len(od.page.Buttons) {
+			s.dialogueMu.Unlock()
+			return nil
+		}
+		b := od.page.Buttons[pk.ActionType]
+		if page, ok := b.TargetPage(); ok {
+			s.sendDialoguePage(od.d, od.e, od.d.Page(page))
+			s.dialogueMu.Unlock()
+			return nil
+		}
+		s.dialogueMu.Unlock()
+
+		s.CloseDialogue()
+		b.Submit(s.c, od.e)
+	case packet.NPCRequestActionExecuteClosingCommands:
+		s.openDialogue = nil
+		s.dialogueMu.Unlock()
+
+		od.d.Close(s.c, od.e)
+	default:
+		s.dialogueMu.Unlock()
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package session
 import (
 	"fmt"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
@@ -19,9 +20,27 @@ func (h PlayerAuthInputHandler) Handle(p packet.Packet, s *Session) error {
 	if err := h.handleMovement(pk, s); err != nil {
 		return err
 	}
+	h.handleRiderInput(pk, s)
 	return h.handleActions(pk, s)
 }
 
+// handleRiderInput forwards the movement input in the packet.PlayerAuthInput to the entity ridden by the
+// player, if any.
+func (h PlayerAuthInputHandler) handleRiderInput(pk *packet.PlayerAuthInput, s *Session) {
+	if _, riding := s.c.Riding(); !riding {
+		return
+	}
+	yaw, pitch := s.c.Rotation().Elem()
+	s.c.DriveVehicle(entity.RiderInput{
+		Forward:  float64(pk.MoveVector.Y()),
+		Strafe:   float64(pk.MoveVector.X()),
+		Jumping:  pk.InputData&packet.InputFlagJumping != 0,
+		Sneaking: pk.InputData&packet.InputFlagSneaking != 0,
+		Yaw:      yaw,
+		Pitch:    pitch,
+	})
+}
+
 // handleMovement handles the movement part of the packet.PlayerAuthInput.
 func (h PlayerAuthInputHandler) handleMovement(pk *packet.PlayerAuthInput, s *Session) error {
 	yaw, pitch := s.c.Rotation().Elem()
@@ -35,7 +54,7 @@ func (h PlayerAuthInputHandler) handleMovement(pk *packet.PlayerAuthInput, s *Se
 			// world), see #425. For this reason, we don't actually return an error if this happens, because this will
 			// result in the player being kicked. Just log it and replace the NaN value with the one we have tracked
 			// server-side.
-			s.log.Debugf("failed processing packet from %v (%v): %T: must not have nan/inf values, but got %v (%v, %v, %v). assuming server-side values\n", s.conn.RemoteAddr(), s.c.Name(), pk, pk.Position, pk.Pitch, pk.Yaw, pk.HeadYaw)
+			s.log.Debug("PlayerAuthInput must not have NaN/Inf values: assuming server-side values.", "pos", pk.Position, "pitch", pk.Pitch, "yaw", pk.Yaw, "head-yaw", pk.HeadYaw)
 			*v = float32(reference[i])
 		}
 	}
@@ -87,7 +106,7 @@ func (h PlayerAuthInputHandler) handleActions(pk *packet.PlayerAuthInput, s *Ses
 		if err := sh.handleRequest(pk.ItemStackRequest, s); err != nil {
 			// Item stacks being out of sync isn't uncommon, so don't error. Just debug the error and let the
 			// revert do its work.
-			s.log.Debugf("failed processing packet from %v (%v): PlayerAuthInput: error resolving item stack request: %v", s.conn.RemoteAddr(), s.c.Name(), err)
+			s.log.Debug("PlayerAuthInput: error resolving item stack request.", "err", err)
 		}
 	}
 	return nil
@@ -136,7 +155,7 @@ func (h PlayerAuthInputHandler) handleUseItemData(data protocol.UseItemTransacti
 
 	held, _ := s.c.HeldItems()
 	if !held.Equal(stackToItem(data.HeldItem.Stack)) {
-		s.log.Debugf("failed processing item interaction from %v (%v): PlayerAuthInput: actual held and client held item mismatch", s.conn.RemoteAddr(), s.c.Name())
+		s.log.Debug("PlayerAuthInput: actual held and client held item mismatch.")
 		return nil
 	}
 	pos := cube.Pos{int(data.BlockPosition[0]), int(data.BlockPosition[1]), int(data.BlockPosition[2])}
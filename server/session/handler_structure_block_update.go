@@ -0,0 +1,55 @@
+package session
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// StructureBlockUpdateHandler handles the StructureBlockUpdate packet, sent when a player edits a structure block
+// using its interface.
+type StructureBlockUpdateHandler struct{}
+
+// Handle ...
+func (StructureBlockUpdateHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.StructureBlockUpdate)
+	pos := blockPosFromProtocol(pk.Position)
+	if !canReach(s.c, pos.Vec3Middle()) {
+		return fmt.Errorf("block at %v is not within reach", pos)
+	}
+	b, ok := s.c.World().Block(pos).(block.StructureBlock)
+	if !ok {
+		s.log.Debug("Structure block update for position without structure block.", "pos", pos)
+		return nil
+	}
+	for _, m := range block.StructureBlockModes() {
+		if int32(m.Uint8()) == pk.StructureBlockType {
+			b.Mode = m
+		}
+	}
+	b.Name, b.DataField, b.ShowBoundingBox = pk.StructureName, pk.DataField, pk.ShowBoundingBox
+	applyStructureSettings(&b, pk.Settings)
+	return s.c.UpdateStructureBlock(pos, b, pk.ShouldTrigger)
+}
+
+// maxStructureSize is the maximum size of a structure along each axis that may be set in the interface of a
+// structure block.
+const maxStructureSize = 64
+
+// applyStructureSettings applies the protocol.StructureSettings passed to the structure block passed.
+func applyStructureSettings(b *block.StructureBlock, settings protocol.StructureSettings) {
+	b.Offset, b.Size = blockPosFromProtocol(settings.Offset), blockPosFromProtocol(settings.Size)
+	for i := range b.Size {
+		b.Size[i] = max(min(b.Size[i], maxStructureSize), 0)
+	}
+	b.IncludeEntities, b.RemoveBlocks = !settings.IgnoreEntities, settings.IgnoreBlocks
+	b.Rotation = int(settings.Rotation % 4)
+	b.MirrorX, b.MirrorZ = settings.Mirror&1 != 0, settings.Mirror&2 != 0
+	b.Integrity = float64(settings.Integrity)
+	if b.Integrity > 1 {
+		// The integrity is sometimes sent as a percentage.
+		b.Integrity /= 100
+	}
+	b.Seed = int64(settings.Seed)
+}
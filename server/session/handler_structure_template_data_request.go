@@ -0,0 +1,49 @@
+package session
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// StructureTemplateDataRequestHandler handles the StructureTemplateDataRequest packet, sent by the client to
+// preview a structure loaded by a structure block or to export the structure of a structure block to a file.
+type StructureTemplateDataRequestHandler struct{}
+
+// Handle ...
+func (StructureTemplateDataRequestHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.StructureTemplateDataRequest)
+	pos := blockPosFromProtocol(pk.Position)
+	if !canReach(s.c, pos.Vec3Middle()) {
+		return fmt.Errorf("block at %v is not within reach", pos)
+	}
+	resp := &packet.StructureTemplateDataResponse{StructureName: pk.StructureName}
+	defer s.writePacket(resp)
+
+	b, ok := s.c.World().Block(pos).(block.StructureBlock)
+	if !ok || !s.c.GameMode().CreativeInventory() {
+		return nil
+	}
+	applyStructureSettings(&b, pk.Settings)
+
+	var t *world.StructureTemplate
+	switch pk.RequestType {
+	case packet.StructureTemplateRequestExportFromSave:
+		resp.ResponseType = packet.StructureTemplateResponseExport
+		t = s.c.World().CaptureStructure(pos.Add(b.Offset), [3]int{b.Size[0], b.Size[1], b.Size[2]}, b.IncludeEntities)
+	case packet.StructureTemplateRequestQuerySavedStructure:
+		resp.ResponseType = packet.StructureTemplateResponseQuery
+		var err error
+		if t, ok, err = s.c.World().LoadStructure(pk.StructureName); err != nil {
+			s.log.Error("Failed querying structure.", "name", pk.StructureName, "err", err)
+			return nil
+		} else if !ok {
+			return nil
+		}
+	default:
+		return nil
+	}
+	resp.Success, resp.StructureTemplate = true, t.EncodeNBT()
+	return nil
+}
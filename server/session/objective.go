@@ -0,0 +1,149 @@
+package session
+
+import (
+	"github.com/df-mc/dragonfly/server/player/scoreboard"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// shownObjective is a scoreboard.Objective shown to a Session in a display slot, along with the scores last sent
+// for it, indexed by the runtime IDs of the players they belong to.
+type shownObjective struct {
+	o      scoreboard.Objective
+	scores map[uint64]int32
+}
+
+// SendObjective shows a scoreboard.Objective in the display slot passed, replacing the objective that was shown
+// in the slot before. The scores of all players online are sent along with it.
+func (s *Session) SendObjective(slot scoreboard.DisplaySlot, o scoreboard.Objective) {
+	if s == Nop {
+		return
+	}
+	s.RemoveObjective(slot)
+
+	s.objectiveMu.Lock()
+	s.objectives[slot.Uint8()] = &shownObjective{o: o, scores: map[uint64]int32{}}
+	s.objectiveMu.Unlock()
+
+	s.writePacket(&packet.SetDisplayObjective{
+		DisplaySlot:   slot.String(),
+		ObjectiveName: objectiveName(slot),
+		DisplayName:   o.Name(),
+		CriteriaName:  "dummy",
+		SortOrder:     1,
+	})
+	s.sendObjectiveScores(onlinePlayers())
+}
+
+// RemoveObjective removes the scoreboard.Objective shown in the display slot passed, if any.
+func (s *Session) RemoveObjective(slot scoreboard.DisplaySlot) {
+	if s == Nop {
+		return
+	}
+	s.objectiveMu.Lock()
+	shown := s.objectives[slot.Uint8()] != nil
+	s.objectives[slot.Uint8()] = nil
+	s.objectiveMu.Unlock()
+
+	if shown {
+		s.writePacket(&packet.RemoveObjective{ObjectiveName: objectiveName(slot)})
+	}
+}
+
+// UpdateObjectives obtains the scores of all players online from the scoreboard.Objectives shown to the
+// Session again. Only the scores that changed since they were last sent are sent again.
+func (s *Session) UpdateObjectives() {
+	if s == Nop {
+		return
+	}
+	s.sendObjectiveScores(onlinePlayers())
+}
+
+// sendObjectiveScores sends the scores of the players passed in all scoreboard.Objectives shown to the Session,
+// if they changed since they were last sent. The scores of players that no longer have a score are removed.
+func (s *Session) sendObjectiveScores(targets []world.Entity) {
+	s.objectiveMu.Lock()
+	defer s.objectiveMu.Unlock()
+
+	modify := &packet.SetScore{ActionType: packet.ScoreboardActionModify}
+	remove := &packet.SetScore{ActionType: packet.ScoreboardActionRemove}
+	for _, slot := range scoreboard.DisplaySlots() {
+		shown := s.objectives[slot.Uint8()]
+		if shown == nil {
+			continue
+		}
+		for _, target := range targets {
+			id := s.entityRuntimeID(target)
+			if id == 0 {
+				continue
+			}
+			entry := protocol.ScoreboardEntry{EntryID: objectiveEntryID(slot, id), ObjectiveName: objectiveName(slot)}
+			current, sent := shown.scores[id]
+			score, ok := shown.o.Score(target)
+			switch {
+			case ok && (!sent || current != int32(score)):
+				shown.scores[id] = int32(score)
+				entry.Score = int32(score)
+				entry.IdentityType = protocol.ScoreboardIdentityPlayer
+				entry.EntityUniqueID = int64(id)
+				modify.Entries = append(modify.Entries, entry)
+			case !ok && sent:
+				delete(shown.scores, id)
+				remove.Entries = append(remove.Entries, entry)
+			}
+		}
+	}
+	if len(remove.Entries) > 0 {
+		s.writePacket(remove)
+	}
+	if len(modify.Entries) > 0 {
+		s.writePacket(modify)
+	}
+}
+
+// removeObjectiveScores removes the scores of the player with the runtime ID passed from all
+// scoreboard.Objectives shown to the Session.
+func (s *Session) removeObjectiveScores(id uint64) {
+	s.objectiveMu.Lock()
+	defer s.objectiveMu.Unlock()
+
+	remove := &packet.SetScore{ActionType: packet.ScoreboardActionRemove}
+	for _, slot := range scoreboard.DisplaySlots() {
+		shown := s.objectives[slot.Uint8()]
+		if shown == nil {
+			continue
+		}
+		if _, ok := shown.scores[id]; ok {
+			delete(shown.scores, id)
+			remove.Entries = append(remove.Entries, protocol.ScoreboardEntry{EntryID: objectiveEntryID(slot, id), ObjectiveName: objectiveName(slot)})
+		}
+	}
+	if len(remove.Entries) > 0 {
+		s.writePacket(remove)
+	}
+}
+
+// onlinePlayers returns the Controllables of all sessions currently open.
+func onlinePlayers() []world.Entity {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	players := make([]world.Entity, 0, len(sessions))
+	for _, s := range sessions {
+		players = append(players, s.c)
+	}
+	return players
+}
+
+// objectiveName returns the name of the objective shown in the scoreboard.DisplaySlot passed. The name is
+// different from that of the sidebar scoreboard, so that both may be shown at the same time.
+func objectiveName(slot scoreboard.DisplaySlot) string {
+	return "dragonfly:" + slot.String()
+}
+
+// objectiveEntryID returns the ID of the scoreboard entry of the player with the runtime ID passed in the
+// objective shown in the scoreboard.DisplaySlot passed. The IDs never overlap with those of the lines of the
+// sidebar scoreboard or with those of other display slots.
+func objectiveEntryID(slot scoreboard.DisplaySlot, id uint64) int64 {
+	return int64(slot.Uint8()+1)<<32 | int64(id)
+}
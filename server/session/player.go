@@ -6,6 +6,7 @@ import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
@@ -14,6 +15,7 @@ import (
 	"github.com/df-mc/dragonfly/server/item/recipe"
 	"github.com/df-mc/dragonfly/server/player/form"
 	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/player/transfer"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
@@ -308,17 +310,19 @@ func (s *Session) Disconnect(message string) {
 	}
 }
 
-// SendSpeed sends the speed of the player in an UpdateAttributes packet, so that it is updated client-side.
-func (s *Session) SendSpeed(speed float64) {
+// SendAttribute sends the value of an attribute of the player in an UpdateAttributes packet, so that it is
+// updated client-side.
+func (s *Session) SendAttribute(a attribute.Attribute, v float64) {
 	s.writePacket(&packet.UpdateAttributes{
 		EntityRuntimeID: selfEntityRuntimeID,
 		Attributes: []protocol.Attribute{{
 			AttributeValue: protocol.AttributeValue{
-				Name:  "minecraft:movement",
-				Value: float32(speed),
-				Max:   math.MaxFloat32,
+				Name:  a.Name(),
+				Value: float32(v),
+				Min:   float32(a.Min()),
+				Max:   float32(a.Max()),
 			},
-			Default: 0.1,
+			Default: float32(a.Default()),
 		}},
 	})
 }
@@ -365,11 +369,14 @@ func (s *Session) SendForm(f form.Form) {
 
 	h.mu.Lock()
 	if len(h.forms) > 10 {
-		s.log.Debugf("SendForm %v: more than 10 active forms: dropping an existing one.", s.c.Name())
+		s.log.Debug("More than 10 active forms: dropping the oldest one.")
+		oldest := id
 		for k := range h.forms {
-			delete(h.forms, k)
-			break
+			if k < oldest {
+				oldest = k
+			}
 		}
+		delete(h.forms, oldest)
 	}
 	h.forms[id] = f
 	h.mu.Unlock()
@@ -380,12 +387,32 @@ func (s *Session) SendForm(f form.Form) {
 	})
 }
 
-// Transfer transfers the player to a server with the IP and port passed.
-func (s *Session) Transfer(ip net.IP, port int) {
+// Transfer transfers the player to a server with the IP and port passed. If the transfer.Metadata passed is not
+// nil, it is stored in the transfer.Store of the session before the player is transferred. If storing it fails,
+// the player is not transferred and an error is returned.
+func (s *Session) Transfer(ip net.IP, port int, m transfer.Metadata) error {
+	if s == Nop {
+		return nil
+	}
+	if m != nil {
+		if err := s.transferStore.Put(s.c.UUID(), m); err != nil {
+			return fmt.Errorf("transfer: %w", err)
+		}
+	}
 	s.writePacket(&packet.Transfer{
 		Address: ip.String(),
 		Port:    uint16(port),
 	})
+	return nil
+}
+
+// TransferMetadata returns the transfer.Metadata attached to the transfer that brought the player to the
+// server. If the player was not transferred or no metadata was attached, nil is returned.
+func (s *Session) TransferMetadata() transfer.Metadata {
+	if s == Nop {
+		return nil
+	}
+	return s.transferMetadata
 }
 
 // SendGameMode sends the game mode of the Controllable entity of the session to the client. It makes sure the right
@@ -396,6 +423,7 @@ func (s *Session) SendGameMode(mode world.GameMode) {
 	}
 	s.writePacket(&packet.SetPlayerGameType{GameType: gameTypeFromMode(mode)})
 	s.sendAbilities()
+	s.updatePlayersInView()
 }
 
 // sendAbilities sends the abilities of the Controllable entity of the session to the client.
@@ -475,13 +503,18 @@ func (s *Session) SendAbsorption(value float64) {
 func (s *Session) SendEffect(e effect.Effect) {
 	s.SendEffectRemoval(e.Type())
 	id, _ := effect.ID(e.Type())
+	dur := int32(e.Duration() / (time.Second / 20))
+	if e.Infinite() {
+		// The client shows effects with a duration of -1 as infinite.
+		dur = -1
+	}
 	s.writePacket(&packet.MobEffect{
 		EntityRuntimeID: selfEntityRuntimeID,
 		Operation:       packet.MobEffectAdd,
 		EffectType:      int32(id),
 		Amplifier:       int32(e.Level() - 1),
 		Particles:       !e.ParticlesHidden(),
-		Duration:        int32(e.Duration() / (time.Second / 20)),
+		Duration:        dur,
 	})
 }
 
@@ -541,6 +574,7 @@ func (s *Session) addToPlayerList(session *Session) {
 			Skin:           skinToProtocol(c.Skin()),
 		}},
 	})
+	s.sendObjectiveScores([]world.Entity{c})
 }
 
 // skinToProtocol converts a skin to its protocol representation.
@@ -566,22 +600,25 @@ func skinToProtocol(s skin.Skin) protocol.Skin {
 	}
 
 	return protocol.Skin{
-		PlayFabID:          s.PlayFabID,
-		SkinID:             uuid.New().String(),
-		SkinResourcePatch:  s.ModelConfig.Encode(),
-		SkinImageWidth:     uint32(s.Bounds().Max.X),
-		SkinImageHeight:    uint32(s.Bounds().Max.Y),
-		SkinData:           s.Pix,
-		CapeImageWidth:     uint32(s.Cape.Bounds().Max.X),
-		CapeImageHeight:    uint32(s.Cape.Bounds().Max.Y),
-		CapeData:           s.Cape.Pix,
-		SkinGeometry:       s.Model,
-		PersonaSkin:        s.Persona,
-		CapeID:             uuid.New().String(),
-		FullID:             uuid.New().String(),
-		Animations:         animations,
-		Trusted:            true,
-		OverrideAppearance: true,
+		PlayFabID:                 s.PlayFabID,
+		SkinID:                    uuid.New().String(),
+		SkinResourcePatch:         s.ModelConfig.Encode(),
+		SkinImageWidth:            uint32(s.Bounds().Max.X),
+		SkinImageHeight:           uint32(s.Bounds().Max.Y),
+		SkinData:                  s.Pix,
+		CapeImageWidth:            uint32(s.Cape.Bounds().Max.X),
+		CapeImageHeight:           uint32(s.Cape.Bounds().Max.Y),
+		CapeData:                  s.Cape.Pix,
+		SkinGeometry:              s.Model,
+		AnimationData:             s.AnimationData,
+		GeometryDataEngineVersion: []byte(s.GeometryVersion),
+		ArmSize:                   s.ArmSize,
+		PersonaSkin:               s.Persona,
+		CapeID:                    uuid.New().String(),
+		FullID:                    uuid.New().String(),
+		Animations:                animations,
+		Trusted:                   true,
+		OverrideAppearance:        true,
 	}
 }
 
@@ -591,9 +628,11 @@ func (s *Session) removeFromPlayerList(session *Session) {
 	c := session.c
 
 	s.entityMutex.Lock()
-	delete(s.entities, s.entityRuntimeIDs[c])
+	id := s.entityRuntimeIDs[c]
+	delete(s.entities, id)
 	delete(s.entityRuntimeIDs, c)
 	s.entityMutex.Unlock()
+	s.removeObjectiveScores(id)
 
 	s.writePacket(&packet.PlayerList{
 		ActionType: packet.PlayerListActionRemove,
@@ -694,10 +733,15 @@ func (s *Session) UpdateHeldSlot(slot int, expected item.Stack) error {
 	if slot > 8 {
 		return fmt.Errorf("new held slot exceeds hotbar range 0-8: slot is %v", slot)
 	}
-	if s.heldSlot.Load() == uint32(slot) {
+	from := int(s.heldSlot.Load())
+	if from == slot {
 		// Old slot was the same as new slot, so don't do anything.
 		return nil
 	}
+	if !s.c.ChangeHeldSlot(from, slot) {
+		// The change was cancelled, so make the client hold the previous slot again.
+		return s.SetHeldSlot(from)
+	}
 	// The user swapped changed held slots so stop using item right away.
 	s.c.ReleaseItem()
 
@@ -710,7 +754,7 @@ func (s *Session) UpdateHeldSlot(slot int, expected item.Stack) error {
 	if !clientSideItem.Equal(actual) {
 		// Only ever debug these as they are frequent and expected to happen whenever client and server get
 		// out of sync.
-		s.log.Debugf("failed processing packet from %v (%v): failed changing held slot: client-side item must be identical to server-side item, but got differences: client: %v vs server: %v", s.conn.RemoteAddr(), s.c.Name(), clientSideItem, actual)
+		s.log.Debug("Failed changing held slot: client-side item must be identical to server-side item.", "client", clientSideItem, "server", actual)
 	}
 	for _, viewer := range s.c.World().Viewers(s.c.Position()) {
 		viewer.ViewEntityItems(s.c)
@@ -764,6 +808,8 @@ func stackFromItem(it item.Stack) protocol.ItemStack {
 		Count:          uint16(it.Count()),
 		BlockRuntimeID: int32(blockRuntimeID),
 		NBTData:        nbtconv.WriteItem(it, false),
+		CanBePlacedOn:  it.CanPlaceOn(),
+		CanBreak:       it.CanDestroy(),
 	}
 }
 
@@ -786,7 +832,7 @@ func stackToItem(it protocol.ItemStack) item.Stack {
 	if nbter, ok := t.(world.NBTer); ok && len(it.NBTData) != 0 {
 		t = nbter.DecodeNBT(it.NBTData).(world.Item)
 	}
-	s := item.NewStack(t, int(it.Count))
+	s := item.NewStack(t, int(it.Count)).WithCanPlaceOn(it.CanBePlacedOn...).WithCanDestroy(it.CanBreak...)
 	return nbtconv.Item(it.NBTData, &s)
 }
 
@@ -868,6 +914,9 @@ func protocolToSkin(sk protocol.Skin) (s skin.Skin, err error) {
 	s.Persona = sk.PersonaSkin
 	s.Pix = sk.SkinData
 	s.Model = sk.SkinGeometry
+	s.GeometryVersion = string(sk.GeometryDataEngineVersion)
+	s.ArmSize = sk.ArmSize
+	s.AnimationData = sk.AnimationData
 	s.PlayFabID = sk.PlayFabID
 
 	s.Cape = skin.NewCape(int(sk.CapeImageWidth), int(sk.CapeImageHeight))
@@ -901,6 +950,9 @@ func protocolToSkin(sk protocol.Skin) (s skin.Skin, err error) {
 
 		s.Animations = append(s.Animations, animation)
 	}
+	if err = s.Validate(); err != nil {
+		return skin.Skin{}, err
+	}
 	return
 }
 
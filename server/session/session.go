@@ -7,12 +7,16 @@ import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity/attribute"
+	"github.com/df-mc/dragonfly/server/internal/crash"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/inventory"
 	"github.com/df-mc/dragonfly/server/item/recipe"
+	"github.com/df-mc/dragonfly/server/metrics"
 	"github.com/df-mc/dragonfly/server/player/chat"
 	"github.com/df-mc/dragonfly/server/player/form"
+	"github.com/df-mc/dragonfly/server/player/transfer"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/sandertv/gophertunnel/minecraft"
@@ -22,6 +26,7 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/sandertv/gophertunnel/minecraft/text"
 	"io"
+	"log/slog"
 	"net"
 	"sync"
 	"time"
@@ -30,7 +35,7 @@ import (
 // Session handles incoming packets from connections and sends outgoing packets by providing a thin layer
 // of abstraction over direct packets. A Session basically 'controls' an entity.
 type Session struct {
-	log            Logger
+	log            *slog.Logger
 	once, connOnce sync.Once
 
 	c        Controllable
@@ -57,6 +62,27 @@ type Session struct {
 	entityRuntimeIDs map[world.Entity]uint64
 	entities         map[uint64]world.Entity
 	hiddenEntities   map[world.Entity]struct{}
+	// hiddenPlayers holds the players in view of the session that are not shown to it because they are in
+	// spectator mode or vanished.
+	hiddenPlayers map[world.Entity]struct{}
+
+	dialogueMu sync.Mutex
+	// openDialogue holds the dialogue currently shown to the session, if any. dialogueScene is incremented for
+	// every page of a dialogue sent, so that every page has a unique scene name.
+	openDialogue  *openDialogue
+	dialogueScene uint64
+
+	objectiveMu sync.Mutex
+	// objectives holds the scoreboard objectives shown in the display slots of the session, indexed by the
+	// display slot.
+	objectives [2]*shownObjective
+
+	// cameraPresets holds the indices of the camera presets sent to the client, indexed by their name.
+	cameraPresets map[string]uint32
+
+	bossBarMu sync.Mutex
+	// bossBars holds the boss bars shown to the session using ShowBossBar, indexed by their ID.
+	bossBars map[string]bossBar
 
 	// heldSlot is the slot in the inventory that the controllable is holding.
 	heldSlot                     *atomic.Uint32
@@ -81,6 +107,11 @@ type Session struct {
 
 	joinMessage, quitMessage string
 
+	// transferStore is the transfer.Store that the metadata of transfers of the player is stored in, and
+	// transferMetadata is the transfer.Metadata attached to the transfer that brought the player to the server.
+	transferStore    transfer.Store
+	transferMetadata transfer.Metadata
+
 	closeBackground chan struct{}
 }
 
@@ -114,12 +145,6 @@ type Conn interface {
 	StartGameContext(ctx context.Context, data minecraft.GameData) error
 }
 
-// Logger is used to write debug messages to. These messages are sent whenever handling of a packet of a client fails.
-type Logger interface {
-	Errorf(format string, a ...any)
-	Debugf(format string, a ...any)
-}
-
 // Nop represents a no-operation session. It does not do anything when sending a packet to it.
 var Nop = &Session{}
 
@@ -139,7 +164,7 @@ var errSelfRuntimeID = errors.New("invalid entity runtime ID: runtime ID for sel
 // packets that it receives.
 // New takes the connection from which to accept packets. It will start handling these packets after a call to
 // Session.Spawn().
-func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage string) *Session {
+func New(conn Conn, maxChunkRadius int, log *slog.Logger, joinMessage, quitMessage string, transferStore transfer.Store, transferMetadata transfer.Metadata) *Session {
 	r := conn.ChunkRadius()
 	if r > maxChunkRadius {
 		r = maxChunkRadius
@@ -155,15 +180,19 @@ func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage str
 		entityRuntimeIDs:       map[world.Entity]uint64{},
 		entities:               map[uint64]world.Entity{},
 		hiddenEntities:         map[world.Entity]struct{}{},
+		hiddenPlayers:          map[world.Entity]struct{}{},
+		bossBars:               map[string]bossBar{},
 		blobs:                  map[uint64][]byte{},
 		chunkRadius:            int32(r),
 		maxChunkRadius:         int32(maxChunkRadius),
 		conn:                   conn,
-		log:                    log,
+		log:                    log.With("name", conn.IdentityData().DisplayName, "raddr", conn.RemoteAddr().String()),
 		currentEntityRuntimeID: 1,
 		heldSlot:               atomic.NewUint32(0),
 		joinMessage:            joinMessage,
 		quitMessage:            quitMessage,
+		transferStore:          transferStore,
+		transferMetadata:       transferMetadata,
 		openedWindow:           *atomic.NewValue(inventory.New(1, nil)),
 	}
 
@@ -193,7 +222,12 @@ func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world
 
 	world_add(c, w)
 	s.c.SetGameMode(gm)
-	s.SendSpeed(0.1)
+	for _, a := range attribute.Attributes() {
+		if a != attribute.MaxHealth {
+			// The maximum health is sent along with the health of the player.
+			s.SendAttribute(a, s.c.Attribute(a))
+		}
+	}
 	for _, e := range s.c.Effects() {
 		s.SendEffect(e)
 	}
@@ -210,6 +244,7 @@ func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world
 	s.writePacket(&packet.CreativeContent{Items: creativeItems()})
 	s.sendRecipes()
 	s.sendArmourTrimData()
+	s.sendCameraPresets()
 }
 
 // Start makes the session start handling incoming packets from the client.
@@ -293,6 +328,7 @@ func (s *Session) ClientData() login.ClientData {
 // handlePackets continuously handles incoming packets from the connection. It processes them accordingly.
 // Once the connection is closed, handlePackets will return.
 func (s *Session) handlePackets() {
+	defer crash.Recover()
 	go s.background()
 
 	defer func() {
@@ -310,10 +346,11 @@ func (s *Session) handlePackets() {
 		if err != nil {
 			return
 		}
+		metrics.PacketsReceived.Inc()
 		if err := s.handlePacket(pk); err != nil {
 			// An error occurred during the handling of a packet. Print the error and stop handling any more
 			// packets.
-			s.log.Debugf("failed processing packet from %v (%v): %v\n", s.conn.RemoteAddr(), s.c.Name(), err)
+			s.log.Debug("Failed processing packet.", "err", err)
 			return
 		}
 	}
@@ -322,6 +359,7 @@ func (s *Session) handlePackets() {
 // background performs background tasks of the Session. This includes chunk sending and automatic command updating.
 // background returns when the Session's connection is closed using CloseConnection.
 func (s *Session) background() {
+	defer crash.Recover()
 	var (
 		t                 = time.NewTicker(time.Second / 20)
 		r                 = s.sendAvailableCommands()
@@ -387,13 +425,22 @@ func (s *Session) handleWorldSwitch(w *world.World) {
 		s.blobMu.Unlock()
 	}
 
-	dim, _ := world.DimensionID(w.Dimension())
-	same := w.Dimension() == s.chunkLoader.World().Dimension()
-	if !same {
+	dim, prev := world.NetworkDimensionID(w.Dimension()), world.NetworkDimensionID(s.chunkLoader.World().Dimension())
+	if w.ForceGameMode() {
+		s.c.SetGameMode(w.DefaultGameMode())
+	}
+	if w.Dimension() != s.chunkLoader.World().Dimension() {
+		if dim == prev {
+			// The client does not clear its chunks when changing to the dimension it is already in, which
+			// happens when moving between dimensions that share the same sky. Briefly move the client to a
+			// different dimension first.
+			s.changeDimension(int32((dim+1)%3), true)
+		}
 		s.changeDimension(int32(dim), false)
 	}
 	s.ViewEntityTeleport(s.c, s.c.Position())
 	s.chunkLoader.ChangeWorld(w)
+	s.resendBossBars()
 }
 
 // changeDimension changes the dimension of the client. If silent is set to true, the portal noise will be stopped
@@ -416,7 +463,7 @@ func (s *Session) changeDimension(dim int32, silent bool) {
 func (s *Session) handlePacket(pk packet.Packet) error {
 	handler, ok := s.handlers[pk.ID()]
 	if !ok {
-		s.log.Debugf("unhandled packet %T%v from %v\n", pk, fmt.Sprintf("%+v", pk)[1:], s.conn.RemoteAddr())
+		s.log.Debug("Unhandled packet.", "packet", fmt.Sprintf("%T%v", pk, fmt.Sprintf("%+v", pk)[1:]))
 		return nil
 	}
 	if handler == nil {
@@ -432,37 +479,41 @@ func (s *Session) handlePacket(pk packet.Packet) error {
 // registerHandlers registers all packet handlers found in the packetHandler package.
 func (s *Session) registerHandlers() {
 	s.handlers = map[uint32]packetHandler{
-		packet.IDActorEvent:                nil,
-		packet.IDAdventureSettings:         nil, // Deprecated, the client still sends this though.
-		packet.IDAnimate:                   nil,
-		packet.IDAnvilDamage:               nil,
-		packet.IDBlockActorData:            &BlockActorDataHandler{},
-		packet.IDBlockPickRequest:          &BlockPickRequestHandler{},
-		packet.IDBookEdit:                  &BookEditHandler{},
-		packet.IDBossEvent:                 nil,
-		packet.IDClientCacheBlobStatus:     &ClientCacheBlobStatusHandler{},
-		packet.IDCommandRequest:            &CommandRequestHandler{},
-		packet.IDContainerClose:            &ContainerCloseHandler{},
-		packet.IDEmote:                     &EmoteHandler{},
-		packet.IDEmoteList:                 nil,
-		packet.IDFilterText:                nil,
-		packet.IDInteract:                  &InteractHandler{},
-		packet.IDInventoryTransaction:      &InventoryTransactionHandler{},
-		packet.IDItemStackRequest:          &ItemStackRequestHandler{changes: map[byte]map[byte]changeInfo{}, responseChanges: map[int32]map[*inventory.Inventory]map[byte]responseChange{}},
-		packet.IDLecternUpdate:             &LecternUpdateHandler{},
-		packet.IDMobEquipment:              &MobEquipmentHandler{},
-		packet.IDModalFormResponse:         &ModalFormResponseHandler{forms: make(map[uint32]form.Form)},
-		packet.IDMovePlayer:                nil,
-		packet.IDPlayerAction:              &PlayerActionHandler{},
-		packet.IDPlayerAuthInput:           &PlayerAuthInputHandler{},
-		packet.IDPlayerSkin:                &PlayerSkinHandler{},
-		packet.IDRequestAbility:            &RequestAbilityHandler{},
-		packet.IDRequestChunkRadius:        &RequestChunkRadiusHandler{},
-		packet.IDRespawn:                   &RespawnHandler{},
-		packet.IDSetPlayerInventoryOptions: nil,
-		packet.IDSubChunkRequest:           &SubChunkRequestHandler{},
-		packet.IDText:                      &TextHandler{},
-		packet.IDTickSync:                  nil,
+		packet.IDActorEvent:                   nil,
+		packet.IDAdventureSettings:            nil, // Deprecated, the client still sends this though.
+		packet.IDAnimate:                      nil,
+		packet.IDAnvilDamage:                  nil,
+		packet.IDBlockActorData:               &BlockActorDataHandler{},
+		packet.IDBlockPickRequest:             &BlockPickRequestHandler{},
+		packet.IDBookEdit:                     &BookEditHandler{},
+		packet.IDBossEvent:                    nil,
+		packet.IDClientCacheBlobStatus:        &ClientCacheBlobStatusHandler{},
+		packet.IDCommandBlockUpdate:           &CommandBlockUpdateHandler{},
+		packet.IDCommandRequest:               &CommandRequestHandler{},
+		packet.IDContainerClose:               &ContainerCloseHandler{},
+		packet.IDEmote:                        &EmoteHandler{},
+		packet.IDEmoteList:                    nil,
+		packet.IDFilterText:                   nil,
+		packet.IDInteract:                     &InteractHandler{},
+		packet.IDInventoryTransaction:         &InventoryTransactionHandler{},
+		packet.IDItemStackRequest:             &ItemStackRequestHandler{changes: map[byte]map[byte]changeInfo{}, responseChanges: map[int32]map[*inventory.Inventory]map[byte]responseChange{}},
+		packet.IDLecternUpdate:                &LecternUpdateHandler{},
+		packet.IDMobEquipment:                 &MobEquipmentHandler{},
+		packet.IDModalFormResponse:            &ModalFormResponseHandler{forms: make(map[uint32]form.Form)},
+		packet.IDMovePlayer:                   nil,
+		packet.IDNPCRequest:                   &NPCRequestHandler{},
+		packet.IDPlayerAction:                 &PlayerActionHandler{},
+		packet.IDPlayerAuthInput:              &PlayerAuthInputHandler{},
+		packet.IDPlayerSkin:                   &PlayerSkinHandler{},
+		packet.IDRequestAbility:               &RequestAbilityHandler{},
+		packet.IDRequestChunkRadius:           &RequestChunkRadiusHandler{},
+		packet.IDRespawn:                      &RespawnHandler{},
+		packet.IDSetPlayerInventoryOptions:    nil,
+		packet.IDStructureBlockUpdate:         &StructureBlockUpdateHandler{},
+		packet.IDStructureTemplateDataRequest: &StructureTemplateDataRequestHandler{},
+		packet.IDSubChunkRequest:              &SubChunkRequestHandler{},
+		packet.IDText:                         &TextHandler{},
+		packet.IDTickSync:                     nil,
 	}
 }
 
@@ -483,6 +534,7 @@ func (s *Session) writePacket(pk packet.Packet) {
 	if s == Nop {
 		return
 	}
+	metrics.PacketsSent.Inc()
 	_ = s.conn.WritePacket(pk)
 }
 
@@ -493,9 +545,12 @@ func (s *Session) initPlayerList() {
 	sessions = append(sessions, s)
 	for _, session := range sessions {
 		// AddStack the player of the session to all sessions currently open, and add the players of all sessions
-		// currently open to the player list of the new session.
-		session.addToPlayerList(s)
-		if s != session {
+		// currently open to the player list of the new session. Vanished players are only added to the player
+		// lists of sessions that can see them.
+		if !session.vanishHidden(s.c) {
+			session.addToPlayerList(s)
+		}
+		if s != session && !s.vanishHidden(session.c) {
 			s.addToPlayerList(session)
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"github.com/df-mc/dragonfly/server/player/scoreboard"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"strings"
 	"time"
 )
 
@@ -61,6 +62,13 @@ func (s *Session) SendScoreboard(sb *scoreboard.Scoreboard) {
 		return
 	}
 	currentName, currentLines := s.currentScoreboard.Load(), s.currentLines.Load()
+	lines := sb.Lines()
+	for k, line := range lines {
+		if len(line) == 0 {
+			// Empty lines must be unique, or the client only shows one of them.
+			lines[k] = emptyScoreboardLine(k)
+		}
+	}
 
 	if currentName != sb.Name() {
 		s.RemoveScoreboard()
@@ -71,27 +79,31 @@ func (s *Session) SendScoreboard(sb *scoreboard.Scoreboard) {
 			CriteriaName:  "dummy",
 		})
 		s.currentScoreboard.Store(sb.Name())
-		s.currentLines.Store(append([]string(nil), sb.Lines()...))
-	} else {
-		// Remove all current lines from the scoreboard. We can't replace them without removing them.
-		pk := &packet.SetScore{ActionType: packet.ScoreboardActionRemove}
-		for i := range currentLines {
-			pk.Entries = append(pk.Entries, protocol.ScoreboardEntry{
-				EntryID:       int64(i),
-				ObjectiveName: currentName,
-				Score:         int32(i),
+		currentLines = nil
+	}
+	s.currentLines.Store(lines)
+
+	// Only lines that changed are removed and sent again, so that updating a single line of a scoreboard does
+	// not resend the full scoreboard.
+	remove := &packet.SetScore{ActionType: packet.ScoreboardActionRemove}
+	for k, line := range currentLines {
+		if k >= len(lines) || lines[k] != line {
+			remove.Entries = append(remove.Entries, protocol.ScoreboardEntry{
+				EntryID:       int64(k),
+				ObjectiveName: sb.Name(),
+				Score:         int32(k),
 			})
 		}
-		if len(pk.Entries) > 0 {
-			s.writePacket(pk)
-		}
 	}
-	pk := &packet.SetScore{ActionType: packet.ScoreboardActionModify}
-	for k, line := range sb.Lines() {
-		if len(line) == 0 {
-			line = "§" + colours[k]
+	if len(remove.Entries) > 0 {
+		s.writePacket(remove)
+	}
+	modify := &packet.SetScore{ActionType: packet.ScoreboardActionModify}
+	for k, line := range lines {
+		if k < len(currentLines) && currentLines[k] == line {
+			continue
 		}
-		pk.Entries = append(pk.Entries, protocol.ScoreboardEntry{
+		modify.Entries = append(modify.Entries, protocol.ScoreboardEntry{
 			EntryID:       int64(k),
 			ObjectiveName: sb.Name(),
 			Score:         int32(k),
@@ -99,11 +111,17 @@ func (s *Session) SendScoreboard(sb *scoreboard.Scoreboard) {
 			DisplayName:   line,
 		})
 	}
-	if len(pk.Entries) > 0 {
-		s.writePacket(pk)
+	if len(modify.Entries) > 0 {
+		s.writePacket(modify)
 	}
 }
 
+// emptyScoreboardLine returns the text shown for an empty line of a scoreboard at the index passed. The text
+// is unique for every index.
+func emptyScoreboardLine(index int) string {
+	return strings.Repeat("§r", index/len(colours)) + "§" + colours[index%len(colours)]
+}
+
 // colours holds a list of colour codes to be filled out for empty lines in a scoreboard.
 var colours = [15]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "a", "b", "c", "d", "e", "f"}
 
@@ -116,7 +134,7 @@ func (s *Session) RemoveScoreboard() {
 
 // SendBossBar sends a boss bar to the player with the text passed and the health percentage of the bar.
 // SendBossBar removes any boss bar that might be active before sending the new one.
-func (s *Session) SendBossBar(text string, colour uint8, healthPercentage float64) {
+func (s *Session) SendBossBar(text string, colour, style uint8, healthPercentage float64) {
 	s.RemoveBossBar()
 	s.writePacket(&packet.BossEvent{
 		BossEntityUniqueID: selfEntityRuntimeID,
@@ -124,6 +142,7 @@ func (s *Session) SendBossBar(text string, colour uint8, healthPercentage float6
 		BossBarTitle:       text,
 		HealthPercentage:   float32(healthPercentage),
 		Colour:             uint32(colour),
+		Overlay:            uint32(style),
 	})
 }
 
@@ -135,6 +154,123 @@ func (s *Session) RemoveBossBar() {
 	})
 }
 
+// bossBar is a boss bar shown to the session using ShowBossBar. Every bossBar is attached to an invisible
+// entity spawned only to the session, so that multiple boss bars may be shown at the same time.
+type bossBar struct {
+	runtimeID     uint64
+	text          string
+	colour, style uint8
+	health        float64
+}
+
+// ShowBossBar shows a boss bar with the ID passed to the player, alongside any other boss bars shown. If a boss
+// bar with the same ID is already shown, only the properties of the boss bar that changed are updated.
+func (s *Session) ShowBossBar(id, text string, colour, style uint8, healthPercentage float64) {
+	if s == Nop {
+		return
+	}
+	s.bossBarMu.Lock()
+	prev, ok := s.bossBars[id]
+	bar := bossBar{runtimeID: prev.runtimeID, text: text, colour: colour, style: style, health: healthPercentage}
+	if !ok {
+		s.entityMutex.Lock()
+		s.currentEntityRuntimeID += 1
+		bar.runtimeID = s.currentEntityRuntimeID
+		s.entityMutex.Unlock()
+	}
+	s.bossBars[id] = bar
+	s.bossBarMu.Unlock()
+
+	if !ok {
+		s.spawnBossBar(bar)
+		return
+	}
+	if prev.text != bar.text {
+		s.writePacket(&packet.BossEvent{
+			BossEntityUniqueID: int64(bar.runtimeID),
+			EventType:          packet.BossEventTitle,
+			BossBarTitle:       bar.text,
+		})
+	}
+	if prev.health != bar.health {
+		s.writePacket(&packet.BossEvent{
+			BossEntityUniqueID: int64(bar.runtimeID),
+			EventType:          packet.BossEventHealthPercentage,
+			HealthPercentage:   float32(bar.health),
+		})
+	}
+	if prev.colour != bar.colour || prev.style != bar.style {
+		s.writePacket(&packet.BossEvent{
+			BossEntityUniqueID: int64(bar.runtimeID),
+			EventType:          packet.BossEventAppearanceProperties,
+			Colour:             uint32(bar.colour),
+			Overlay:            uint32(bar.style),
+		})
+	}
+}
+
+// HideBossBar hides the boss bar with the ID passed. If no boss bar with the ID is shown, nothing happens.
+func (s *Session) HideBossBar(id string) {
+	s.bossBarMu.Lock()
+	bar, ok := s.bossBars[id]
+	delete(s.bossBars, id)
+	s.bossBarMu.Unlock()
+
+	if ok {
+		s.despawnBossBar(bar)
+	}
+}
+
+// resendBossBars respawns all boss bars shown to the session. It is called when the session changes worlds,
+// as the client removes the entities that the boss bars are attached to.
+func (s *Session) resendBossBars() {
+	s.bossBarMu.Lock()
+	bars := make([]bossBar, 0, len(s.bossBars))
+	for _, bar := range s.bossBars {
+		bars = append(bars, bar)
+	}
+	s.bossBarMu.Unlock()
+
+	for _, bar := range bars {
+		s.despawnBossBar(bar)
+		s.spawnBossBar(bar)
+	}
+}
+
+// spawnBossBar spawns the invisible entity that the bossBar passed is attached to and shows the bossBar.
+func (s *Session) spawnBossBar(bar bossBar) {
+	m := protocol.NewEntityMetadata()
+	m[protocol.EntityDataKeyScale] = float32(0)
+	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSilent)
+	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+
+	s.writePacket(&packet.AddActor{
+		EntityUniqueID:  int64(bar.runtimeID),
+		EntityRuntimeID: bar.runtimeID,
+		EntityType:      "minecraft:slime",
+		EntityMetadata:  m,
+		Position:        vec64To32(s.c.Position()),
+	})
+	s.writePacket(&packet.BossEvent{
+		BossEntityUniqueID: int64(bar.runtimeID),
+		EventType:          packet.BossEventShow,
+		BossBarTitle:       bar.text,
+		HealthPercentage:   float32(bar.health),
+		Colour:             uint32(bar.colour),
+		Overlay:            uint32(bar.style),
+	})
+}
+
+// despawnBossBar hides the bossBar passed and removes the entity it is attached to.
+func (s *Session) despawnBossBar(bar bossBar) {
+	s.writePacket(&packet.BossEvent{
+		BossEntityUniqueID: int64(bar.runtimeID),
+		EventType:          packet.BossEventHide,
+	})
+	s.writePacket(&packet.RemoveActor{EntityUniqueID: int64(bar.runtimeID)})
+}
+
 const tickLength = time.Second / 20
 
 // SetTitleDurations ...
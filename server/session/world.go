@@ -9,6 +9,7 @@ import (
 
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
 	"github.com/df-mc/dragonfly/server/item"
@@ -38,12 +39,146 @@ type OffsetEntity interface {
 	NetworkOffset() float64
 }
 
-// entityHidden checks if a world.Entity is being explicitly hidden from the Session.
+// entityHidden checks if a world.Entity is being explicitly hidden from the Session or if it is a player in
+// spectator mode or a vanished player that the Session cannot see.
 func (s *Session) entityHidden(e world.Entity) bool {
 	s.entityMutex.RLock()
 	_, ok := s.hiddenEntities[e]
 	s.entityMutex.RUnlock()
-	return ok
+	return ok || s.playerHidden(e)
+}
+
+// playerHidden checks if a world.Entity is a player that is hidden from the Session because it is in spectator
+// mode or because it is vanished.
+func (s *Session) playerHidden(e world.Entity) bool {
+	return s.spectatorHidden(e) || s.vanishHidden(e)
+}
+
+// spectatorHidden checks if a world.Entity is a player in spectator mode that is hidden from the Session.
+// Players in spectator mode are only visible to other players in spectator mode.
+func (s *Session) spectatorHidden(e world.Entity) bool {
+	g, ok := e.(gameMode)
+	if !ok || s.c == nil || g.GameMode().Visible() {
+		return false
+	}
+	return s.c.GameMode().Visible()
+}
+
+// VanishSeePermission is the permission node that a player must have to see vanished players.
+const VanishSeePermission = "dragonfly.vanish.see"
+
+// vanishHidden checks if a world.Entity is a vanished player that is hidden from the Session. Vanished players
+// are only visible to themselves and to players with the VanishSeePermission.
+func (s *Session) vanishHidden(e world.Entity) bool {
+	v, ok := e.(vanisher)
+	if !ok || s.c == nil || s.c == e || !v.Vanished() {
+		return false
+	}
+	p, ok := s.c.(cmd.Permissible)
+	return !ok || !p.HasPermission(VanishSeePermission)
+}
+
+// CanSee checks if the world.Entity passed is shown to the Session, meaning it is not hidden explicitly and is
+// not a player in spectator mode or a vanished player that the Session cannot see.
+func (s *Session) CanSee(e world.Entity) bool {
+	return s != Nop && !s.entityHidden(e)
+}
+
+// updatePlayerVisibility hides or shows a world.Entity in view of the Session if it started or stopped
+// being hidden from the Session because of its game mode, because it vanished or because of the game mode of
+// the Session's own player.
+func (s *Session) updatePlayerVisibility(e world.Entity) {
+	if s.entityRuntimeID(e) == selfEntityRuntimeID {
+		return
+	}
+	hidden := s.playerHidden(e)
+	s.entityMutex.RLock()
+	_, wasHidden := s.hiddenPlayers[e]
+	s.entityMutex.RUnlock()
+
+	switch {
+	case hidden && !wasHidden:
+		s.HideEntity(e)
+		s.entityMutex.Lock()
+		s.hiddenPlayers[e] = struct{}{}
+		s.entityMutex.Unlock()
+	case !hidden && wasHidden:
+		s.entityMutex.Lock()
+		delete(s.hiddenPlayers, e)
+		s.entityMutex.Unlock()
+		if s.entityHidden(e) {
+			return
+		}
+		s.ViewEntity(e)
+		s.ViewEntityState(e)
+		s.ViewEntityItems(e)
+		s.ViewEntityArmour(e)
+	}
+}
+
+// updatePlayersInView updates the visibility of all players in view of the Session after the game mode of
+// the Session's own player changed.
+func (s *Session) updatePlayersInView() {
+	w := s.c.World()
+	if w == nil {
+		return
+	}
+	s.entityMutex.RLock()
+	entities := make([]world.Entity, 0, len(s.hiddenPlayers))
+	for e := range s.hiddenPlayers {
+		entities = append(entities, e)
+	}
+	s.entityMutex.RUnlock()
+
+	r := float64(s.chunkRadius << 4)
+	pos := s.c.Position()
+	box := cube.Box(pos[0]-r, float64(w.Range()[0]), pos[2]-r, pos[0]+r, float64(w.Range()[1]), pos[2]+r)
+	for _, e := range w.EntitiesWithin(box, func(e world.Entity) bool {
+		_, ok := e.(gameMode)
+		return !ok || e == s.c
+	}) {
+		entities = append(entities, e)
+	}
+	for _, e := range entities {
+		s.updatePlayerVisibility(e)
+	}
+}
+
+// UpdateVanish updates the visibility of the Session's own player to all other sessions after it vanished or
+// reappeared. The player is removed from the player lists of sessions that can no longer see it, and added to
+// the player lists of sessions that can see it again.
+func (s *Session) UpdateVanish() {
+	if s == Nop {
+		return
+	}
+	inView := make(map[*Session]struct{})
+	for _, v := range s.c.World().Viewers(s.c.Position()) {
+		if other, ok := v.(*Session); ok {
+			inView[other] = struct{}{}
+		}
+	}
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	for _, other := range sessions {
+		if other == s {
+			continue
+		}
+		_, viewing := inView[other]
+		if other.vanishHidden(s.c) {
+			if viewing {
+				other.updatePlayerVisibility(s.c)
+			}
+			other.removeFromPlayerList(s)
+			continue
+		}
+		if other.entityRuntimeID(s.c) == 0 {
+			// The player was removed from the player list of the session when it vanished.
+			other.addToPlayerList(s)
+		}
+		if viewing {
+			other.updatePlayerVisibility(s.c)
+		}
+	}
 }
 
 // ViewEntity ...
@@ -52,9 +187,16 @@ func (s *Session) ViewEntity(e world.Entity) {
 		s.ViewEntityState(e)
 		return
 	}
+	if s.playerHidden(e) {
+		s.entityMutex.Lock()
+		s.hiddenPlayers[e] = struct{}{}
+		s.entityMutex.Unlock()
+		return
+	}
 	if s.entityHidden(e) {
 		return
 	}
+	defer s.viewEntityLinks(e)
 	var runtimeID uint64
 
 	_, controllable := e.(Controllable)
@@ -161,6 +303,7 @@ func (s *Session) ViewEntity(e world.Entity) {
 
 // ViewEntityGameMode ...
 func (s *Session) ViewEntityGameMode(e world.Entity) {
+	s.updatePlayerVisibility(e)
 	if s.entityHidden(e) {
 		return
 	}
@@ -181,6 +324,7 @@ func (s *Session) HideEntity(e world.Entity) {
 	}
 
 	s.entityMutex.Lock()
+	delete(s.hiddenPlayers, e)
 	id, ok := s.entityRuntimeIDs[e]
 	if _, controllable := e.(Controllable); !controllable {
 		delete(s.entityRuntimeIDs, e)
@@ -385,11 +529,21 @@ func (s *Session) ViewParticle(pos mgl64.Vec3, p world.Particle) {
 			Position:  vec64To32(pos),
 			EventData: int32(world.BlockRuntimeID(pa.Block)) | (int32(pa.Face) << 24),
 		})
+	case particle.EyeOfEnderDeath:
+		s.writePacket(&packet.LevelEvent{
+			EventType: packet.LevelEventParticlesEyeOfEnderDeath,
+			Position:  vec64To32(pos),
+		})
 	case particle.EndermanTeleport:
 		s.writePacket(&packet.LevelEvent{
 			EventType: packet.LevelEventParticlesTeleport,
 			Position:  vec64To32(pos),
 		})
+	case particle.SonicExplosion:
+		s.writePacket(&packet.LevelEvent{
+			EventType: packet.LevelEventSonicExplosion,
+			Position:  vec64To32(pos),
+		})
 	case particle.Flame:
 		if pa.Colour != (color.RGBA{}) {
 			s.writePacket(&packet.LevelEvent{
@@ -693,12 +847,20 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 			pk.SoundType = packet.SoundEventAttackNoDamage
 		}
 	case sound.BucketFill:
+		if _, powderSnow := so.Block.(block.PowderSnow); powderSnow {
+			pk.SoundType = packet.SoundEventBucketFillPowderSnow
+			break
+		}
 		if _, water := so.Liquid.(block.Water); water {
 			pk.SoundType = packet.SoundEventBucketFillWater
 			break
 		}
 		pk.SoundType = packet.SoundEventBucketFillLava
 	case sound.BucketEmpty:
+		if _, powderSnow := so.Block.(block.PowderSnow); powderSnow {
+			pk.SoundType = packet.SoundEventBucketEmptyPowderSnow
+			break
+		}
 		if _, water := so.Liquid.(block.Water); water {
 			pk.SoundType = packet.SoundEventBucketEmptyWater
 			break
@@ -775,6 +937,10 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType = packet.SoundEventComposterFillLayer
 	case sound.ComposterReady:
 		pk.SoundType = packet.SoundEventComposterReady
+	case sound.EnderEyePlace:
+		pk.SoundType = packet.SoundEventEnderEyePlaced
+	case sound.EndPortalCreate:
+		pk.SoundType = packet.SoundEventEndPortalCreated
 	case sound.LecternBookPlace:
 		pk.SoundType = packet.SoundEventLecternBookPlace
 	case sound.Totem:
@@ -930,6 +1096,11 @@ func (s *Session) ViewEntityAction(e world.Entity, a world.EntityAction) {
 			EntityRuntimeID: s.entityRuntimeID(e),
 			EventType:       packet.ActorEventTalismanActivate,
 		})
+	case entity.LoveAction:
+		s.writePacket(&packet.ActorEvent{
+			EntityRuntimeID: s.entityRuntimeID(e),
+			EventType:       packet.ActorEventLoveHearts,
+		})
 	}
 }
 
@@ -941,6 +1112,53 @@ func (s *Session) ViewEntityState(e world.Entity) {
 	})
 }
 
+// ViewEntityMount ...
+func (s *Session) ViewEntityMount(rider, ridden world.Entity, driver bool) {
+	linkType := byte(protocol.EntityLinkPassenger)
+	if driver {
+		linkType = protocol.EntityLinkRider
+	}
+	s.viewEntityLink(rider, ridden, linkType)
+}
+
+// ViewEntityDismount ...
+func (s *Session) ViewEntityDismount(rider, ridden world.Entity) {
+	s.viewEntityLink(rider, ridden, protocol.EntityLinkRemove)
+}
+
+// viewEntityLinks views the link of the entity passed with the entity it rides, if any, and the links with the
+// entities riding it. It is called when an entity is spawned for the session, so that it is shown riding
+// regardless of whether the rider or the ridden entity is spawned first.
+func (s *Session) viewEntityLinks(e world.Entity) {
+	if r, ok := e.(entity.Rider); ok {
+		if ridden, riding := r.Riding(); riding {
+			seat, _ := ridden.Seats().Seat(r)
+			s.ViewEntityMount(r, ridden, seat == 0)
+		}
+	}
+	if r, ok := e.(entity.Rideable); ok {
+		for _, rider := range r.Seats().Riders() {
+			seat, _ := r.Seats().Seat(rider)
+			s.ViewEntityMount(rider, r, seat == 0)
+		}
+	}
+}
+
+// viewEntityLink sends a link of the type passed between a rider and the entity it rides. Nothing is sent if
+// either of the entities is not currently shown to the session.
+func (s *Session) viewEntityLink(rider, ridden world.Entity, linkType byte) {
+	riderID, riddenID := s.entityRuntimeID(rider), s.entityRuntimeID(ridden)
+	if riderID == 0 || riddenID == 0 || s.entityHidden(rider) || s.entityHidden(ridden) {
+		return
+	}
+	s.writePacket(&packet.SetActorLink{EntityLink: protocol.EntityLink{
+		RiddenEntityUniqueID: int64(riddenID),
+		RiderEntityUniqueID:  int64(riderID),
+		Type:                 linkType,
+		RiderInitiated:       true,
+	}})
+}
+
 // ViewEntityAnimation ...
 func (s *Session) ViewEntityAnimation(e world.Entity, animationName string) {
 	s.writePacket(&packet.AnimateEntity{
@@ -988,6 +1206,10 @@ func (s *Session) OpenBlockContainer(pos cube.Pos) {
 		containerType = protocol.ContainerTypeStonecutter
 	case block.SmithingTable:
 		containerType = protocol.ContainerTypeSmithingTable
+	case block.StructureBlock:
+		containerType = protocol.ContainerTypeStructureEditor
+	case block.CommandBlock:
+		containerType = protocol.ContainerTypeCommandBlock
 	case block.EnderChest:
 		b.AddViewer(w, pos)
 
@@ -1090,6 +1312,9 @@ func (s *Session) ViewBlockAction(pos cube.Pos, a world.BlockAction) {
 
 // ViewEmote ...
 func (s *Session) ViewEmote(player world.Entity, emote uuid.UUID) {
+	if s.entityHidden(player) {
+		return
+	}
 	s.writePacket(&packet.Emote{
 		EntityRuntimeID: s.entityRuntimeID(player),
 		EmoteID:         emote.String(),
@@ -1099,6 +1324,9 @@ func (s *Session) ViewEmote(player world.Entity, emote uuid.UUID) {
 
 // ViewSkin ...
 func (s *Session) ViewSkin(e world.Entity) {
+	if s.entityHidden(e) {
+		return
+	}
 	switch v := e.(type) {
 	case Controllable:
 		s.writePacket(&packet.PlayerSkin{
@@ -1119,6 +1347,12 @@ func (s *Session) ViewWorldSpawn(pos cube.Pos) {
 	})
 }
 
+// ViewDifficulty ...
+func (s *Session) ViewDifficulty(d world.Difficulty) {
+	id, _ := world.DifficultyID(d)
+	s.writePacket(&packet.SetDifficulty{Difficulty: uint32(id)})
+}
+
 // ViewWeather ...
 func (s *Session) ViewWeather(raining, thunder bool) {
 	pk := &packet.LevelEvent{
@@ -20,3 +20,14 @@ func (s statusProvider) ServerStatus(playerCount, maxPlayers int) minecraft.Serv
 		MaxPlayers:  maxPlayers,
 	}
 }
+
+// StatusProviderFunc is a function that implements
+// minecraft.ServerStatusProvider. It is called with the actual player count
+// and maximum player count of the server and returns the status to show in the
+// server list.
+type StatusProviderFunc func(playerCount, maxPlayers int) minecraft.ServerStatus
+
+// ServerStatus calls the StatusProviderFunc.
+func (f StatusProviderFunc) ServerStatus(playerCount, maxPlayers int) minecraft.ServerStatus {
+	return f(playerCount, maxPlayers)
+}
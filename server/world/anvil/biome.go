@@ -0,0 +1,32 @@
+package anvil
+
+// biomeRenames maps the names of Java Edition biomes to the names of their Bedrock Edition equivalents for
+// biomes of which the name differs between the two editions.
+var biomeRenames = map[string]string{
+	"badlands":                 "mesa",
+	"eroded_badlands":          "mesa_bryce",
+	"wooded_badlands":          "mesa_plateau_stone",
+	"snowy_plains":             "ice_plains",
+	"ice_spikes":               "ice_plains_spikes",
+	"snowy_taiga":              "cold_taiga",
+	"snowy_beach":              "cold_beach",
+	"old_growth_birch_forest":  "birch_forest_mutated",
+	"old_growth_pine_taiga":    "mega_taiga",
+	"old_growth_spruce_taiga":  "redwood_taiga_mutated",
+	"dark_forest":              "roofed_forest",
+	"swamp":                    "swampland",
+	"windswept_hills":          "extreme_hills",
+	"windswept_forest":         "extreme_hills_plus_trees",
+	"windswept_gravelly_hills": "extreme_hills_mutated",
+	"windswept_savanna":        "savanna_mutated",
+	"sparse_jungle":            "jungle_edge",
+	"stony_shore":              "stone_beach",
+	"mushroom_fields":          "mushroom_island",
+	"nether_wastes":            "hell",
+	"soul_sand_valley":         "soulsand_valley",
+	"end_highlands":            "the_end",
+	"end_midlands":             "the_end",
+	"end_barrens":              "the_end",
+	"small_end_islands":        "the_end",
+	"the_void":                 "plains",
+}
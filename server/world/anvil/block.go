@@ -0,0 +1,477 @@
+package anvil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// translateBlock translates the name and properties of a Java Edition block state to the name of the
+// equivalent Bedrock Edition block and candidate properties for it. The candidate properties may hold
+// multiple encodings of the same Java Edition property, as Bedrock Edition encodes the same property
+// differently for different blocks. closestState picks the state that matches the candidates best.
+func translateBlock(name string, p map[string]string) (string, map[string]any) {
+	name = strings.TrimPrefix(name, "minecraft:")
+	c := translateProperties(name, p)
+	return "minecraft:" + translateName(name, p, c), c
+}
+
+// blockRenames maps the names of Java Edition blocks to the names of their Bedrock Edition equivalents for
+// blocks of which only the name differs between the two editions.
+var blockRenames = map[string]string{
+	"grass":                        "short_grass",
+	"dirt_path":                    "grass_path",
+	"sugar_cane":                   "reeds",
+	"snow":                         "snow_layer",
+	"snow_block":                   "snow",
+	"melon":                        "melon_block",
+	"spawner":                      "mob_spawner",
+	"note_block":                   "noteblock",
+	"slime_block":                  "slime",
+	"magma_block":                  "magma",
+	"terracotta":                   "hardened_clay",
+	"light_gray_glazed_terracotta": "silver_glazed_terracotta",
+	"bricks":                       "brick_block",
+	"nether_bricks":                "nether_brick",
+	"red_nether_bricks":            "red_nether_brick",
+	"end_stone_bricks":             "end_bricks",
+	"dead_bush":                    "deadbush",
+	"lily_pad":                     "waterlily",
+	"cobweb":                       "web",
+	"jack_o_lantern":               "lit_pumpkin",
+	"dandelion":                    "yellow_flower",
+	"beetroots":                    "beetroot",
+	"rooted_dirt":                  "dirt_with_roots",
+	"nether_quartz_ore":            "quartz_ore",
+	"shulker_box":                  "undyed_shulker_box",
+	"nether_portal":                "portal",
+	"moving_piston":                "moving_block",
+	"tripwire":                     "trip_wire",
+	"stonecutter":                  "stonecutter_block",
+	"light":                        "light_block",
+	"cave_air":                     "air",
+	"void_air":                     "air",
+	"kelp_plant":                   "kelp",
+	"weeping_vines_plant":          "weeping_vines",
+	"twisting_vines_plant":         "twisting_vines",
+	"attached_melon_stem":          "melon_stem",
+	"attached_pumpkin_stem":        "pumpkin_stem",
+	"oak_door":                     "wooden_door",
+	"oak_trapdoor":                 "trapdoor",
+	"oak_fence_gate":               "fence_gate",
+	"oak_button":                   "wooden_button",
+	"oak_pressure_plate":           "wooden_pressure_plate",
+	"oak_sign":                     "standing_sign",
+	"oak_wall_sign":                "wall_sign",
+	"dark_oak_sign":                "darkoak_standing_sign",
+	"dark_oak_wall_sign":           "darkoak_wall_sign",
+	"stone_stairs":                 "normal_stone_stairs",
+	"cobblestone_stairs":           "stone_stairs",
+	"end_stone_brick_stairs":       "end_brick_stairs",
+	"prismarine_brick_stairs":      "prismarine_bricks_stairs",
+	"mushroom_stem":                "brown_mushroom_block",
+	"glow_item_frame":              "glow_frame",
+	"waxed_copper_block":           "waxed_copper",
+	"big_dripleaf_stem":            "big_dripleaf",
+	"small_dripleaf":               "small_dripleaf_block",
+	"flowering_azalea_leaves":      "azalea_leaves_flowered",
+	"frogspawn":                    "frog_spawn",
+	"redstone_wall_torch":          "redstone_torch",
+	"wall_torch":                   "torch",
+	"soul_wall_torch":              "soul_torch",
+	"piston_head":                  "piston_arm_collision",
+}
+
+// variantBlocks maps the names of Java Edition blocks that are a variant of a single Bedrock Edition block to
+// that block and the property and value that select the variant.
+var variantBlocks = map[string]struct{ name, prop, value string }{
+	"stone_bricks":                   {"stonebrick", "stone_brick_type", "default"},
+	"mossy_stone_bricks":             {"stonebrick", "stone_brick_type", "mossy"},
+	"cracked_stone_bricks":           {"stonebrick", "stone_brick_type", "cracked"},
+	"chiseled_stone_bricks":          {"stonebrick", "stone_brick_type", "chiseled"},
+	"red_sand":                       {"sand", "sand_type", "red"},
+	"coarse_dirt":                    {"dirt", "dirt_type", "coarse"},
+	"wet_sponge":                     {"sponge", "sponge_type", "wet"},
+	"chiseled_sandstone":             {"sandstone", "sand_stone_type", "heiroglyphs"},
+	"cut_sandstone":                  {"sandstone", "sand_stone_type", "cut"},
+	"smooth_sandstone":               {"sandstone", "sand_stone_type", "smooth"},
+	"chiseled_red_sandstone":         {"red_sandstone", "sand_stone_type", "heiroglyphs"},
+	"cut_red_sandstone":              {"red_sandstone", "sand_stone_type", "cut"},
+	"smooth_red_sandstone":           {"red_sandstone", "sand_stone_type", "smooth"},
+	"prismarine_bricks":              {"prismarine", "prismarine_block_type", "bricks"},
+	"dark_prismarine":                {"prismarine", "prismarine_block_type", "dark"},
+	"purpur_pillar":                  {"purpur_block", "chisel_type", "lines"},
+	"quartz_pillar":                  {"quartz_block", "chisel_type", "lines"},
+	"chiseled_quartz_block":          {"quartz_block", "chisel_type", "chiseled"},
+	"smooth_quartz":                  {"quartz_block", "chisel_type", "smooth"},
+	"infested_stone":                 {"monster_egg", "monster_egg_stone_type", "stone"},
+	"infested_cobblestone":           {"monster_egg", "monster_egg_stone_type", "cobblestone"},
+	"infested_stone_bricks":          {"monster_egg", "monster_egg_stone_type", "stone_brick"},
+	"infested_mossy_stone_bricks":    {"monster_egg", "monster_egg_stone_type", "mossy_stone_brick"},
+	"infested_cracked_stone_bricks":  {"monster_egg", "monster_egg_stone_type", "cracked_stone_brick"},
+	"infested_chiseled_stone_bricks": {"monster_egg", "monster_egg_stone_type", "chiseled_stone_brick"},
+	"chipped_anvil":                  {"anvil", "damage", "slightly_damaged"},
+	"damaged_anvil":                  {"anvil", "damage", "very_damaged"},
+	"mossy_cobblestone_wall":         {"cobblestone_wall", "wall_block_type", "mossy_cobblestone"},
+	"stone_brick_wall":               {"cobblestone_wall", "wall_block_type", "stone_brick"},
+	"mossy_stone_brick_wall":         {"cobblestone_wall", "wall_block_type", "mossy_stone_brick"},
+	"brick_wall":                     {"cobblestone_wall", "wall_block_type", "brick"},
+	"andesite_wall":                  {"cobblestone_wall", "wall_block_type", "andesite"},
+	"granite_wall":                   {"cobblestone_wall", "wall_block_type", "granite"},
+	"diorite_wall":                   {"cobblestone_wall", "wall_block_type", "diorite"},
+	"sandstone_wall":                 {"cobblestone_wall", "wall_block_type", "sandstone"},
+	"red_sandstone_wall":             {"cobblestone_wall", "wall_block_type", "red_sandstone"},
+	"prismarine_wall":                {"cobblestone_wall", "wall_block_type", "prismarine"},
+	"nether_brick_wall":              {"cobblestone_wall", "wall_block_type", "nether_brick"},
+	"red_nether_brick_wall":          {"cobblestone_wall", "wall_block_type", "red_nether_brick"},
+	"end_stone_brick_wall":           {"cobblestone_wall", "wall_block_type", "end_brick"},
+	"cobblestone_wall":               {"cobblestone_wall", "wall_block_type", "cobblestone"},
+}
+
+// slabVariants maps the names of Java Edition slabs that are a variant of one of the stone slab blocks of
+// Bedrock Edition to the single slab block, the property selecting the variant and its value.
+var slabVariants = map[string]struct{ name, prop, value string }{
+	"red_sandstone_slab":        {"stone_block_slab2", "stone_slab_type_2", "red_sandstone"},
+	"purpur_slab":               {"stone_block_slab2", "stone_slab_type_2", "purpur"},
+	"prismarine_slab":           {"stone_block_slab2", "stone_slab_type_2", "prismarine_rough"},
+	"dark_prismarine_slab":      {"stone_block_slab2", "stone_slab_type_2", "prismarine_dark"},
+	"prismarine_brick_slab":     {"stone_block_slab2", "stone_slab_type_2", "prismarine_brick"},
+	"mossy_cobblestone_slab":    {"stone_block_slab2", "stone_slab_type_2", "mossy_cobblestone"},
+	"smooth_sandstone_slab":     {"stone_block_slab2", "stone_slab_type_2", "smooth_sandstone"},
+	"red_nether_brick_slab":     {"stone_block_slab2", "stone_slab_type_2", "red_nether_brick"},
+	"end_stone_brick_slab":      {"stone_block_slab3", "stone_slab_type_3", "end_stone_brick"},
+	"smooth_red_sandstone_slab": {"stone_block_slab3", "stone_slab_type_3", "smooth_red_sandstone"},
+	"polished_andesite_slab":    {"stone_block_slab3", "stone_slab_type_3", "polished_andesite"},
+	"andesite_slab":             {"stone_block_slab3", "stone_slab_type_3", "andesite"},
+	"diorite_slab":              {"stone_block_slab3", "stone_slab_type_3", "diorite"},
+	"polished_diorite_slab":     {"stone_block_slab3", "stone_slab_type_3", "polished_diorite"},
+	"granite_slab":              {"stone_block_slab3", "stone_slab_type_3", "granite"},
+	"polished_granite_slab":     {"stone_block_slab3", "stone_slab_type_3", "polished_granite"},
+	"mossy_stone_brick_slab":    {"stone_block_slab4", "stone_slab_type_4", "mossy_stone_brick"},
+	"smooth_quartz_slab":        {"stone_block_slab4", "stone_slab_type_4", "smooth_quartz"},
+	"stone_slab":                {"stone_block_slab4", "stone_slab_type_4", "stone"},
+	"cut_sandstone_slab":        {"stone_block_slab4", "stone_slab_type_4", "cut_sandstone"},
+	"cut_red_sandstone_slab":    {"stone_block_slab4", "stone_slab_type_4", "cut_red_sandstone"},
+}
+
+// translateName returns the name of the Bedrock Edition block equivalent to the Java Edition block with the
+// name and properties passed, without the minecraft: prefix. Candidate properties that depend on the block
+// rather than on a single property are added to c.
+func translateName(name string, p map[string]string, c map[string]any) string {
+	if v, ok := slabVariants[name]; ok {
+		c[v.prop] = v.value
+		if p["type"] == "double" {
+			return "double_" + v.name
+		}
+		return v.name
+	}
+	if v, ok := variantBlocks[name]; ok {
+		c[v.prop] = v.value
+		name = v.name
+	}
+
+	switch {
+	case name == "furnace" || name == "smoker" || name == "blast_furnace" || name == "redstone_lamp" ||
+		name == "redstone_ore" || name == "deepslate_redstone_ore":
+		if p["lit"] == "true" {
+			return "lit_" + name
+		}
+		return name
+	case name == "redstone_torch" || name == "redstone_wall_torch":
+		if p["lit"] == "false" {
+			return "unlit_redstone_torch"
+		}
+	case name == "repeater" || name == "comparator":
+		if p["powered"] == "true" {
+			return "powered_" + name
+		}
+		return "unpowered_" + name
+	case name == "daylight_detector" && p["inverted"] == "true":
+		return "daylight_detector_inverted"
+	case name == "cave_vines" || name == "cave_vines_plant":
+		if p["berries"] == "true" {
+			if name == "cave_vines" {
+				return "cave_vines_head_with_berries"
+			}
+			return "cave_vines_body_with_berries"
+		}
+		return "cave_vines"
+	case name == "piston_head" && p["type"] == "sticky":
+		return "sticky_piston_arm_collision"
+	case name == "water_cauldron" || name == "powder_snow_cauldron":
+		c["cauldron_liquid"] = strings.TrimSuffix(name, "_cauldron")
+		if level, err := strconv.Atoi(p["level"]); err == nil {
+			c["fill_level"] = int64(level * 2)
+		}
+		return "cauldron"
+	case name == "lava_cauldron":
+		c["cauldron_liquid"], c["fill_level"] = "lava", int64(6)
+		return "cauldron"
+	case name == "brown_mushroom_block" || name == "red_mushroom_block":
+		c["huge_mushroom_bits"] = int64(14)
+	case name == "mushroom_stem":
+		c["huge_mushroom_bits"] = int64(15)
+	case name == "attached_melon_stem" || name == "attached_pumpkin_stem":
+		c["growth"] = int64(7)
+	case name == "tall_seagrass":
+		c["sea_grass_type"] = "double_bot"
+		if p["half"] == "upper" {
+			c["sea_grass_type"] = "double_top"
+		}
+	case strings.HasPrefix(name, "potted_"):
+		return "flower_pot"
+	case strings.HasSuffix(name, "_wall_banner"):
+		return "wall_banner"
+	case strings.HasSuffix(name, "_banner"):
+		return "standing_banner"
+	case strings.HasSuffix(name, "_bed"):
+		return "bed"
+	case strings.HasSuffix(name, "_wall_head") || strings.HasSuffix(name, "_wall_skull"):
+		return "skull"
+	case strings.HasSuffix(name, "_head") || strings.HasSuffix(name, "_skull"):
+		c["facing_direction"] = int64(1)
+		return "skull"
+	case strings.HasSuffix(name, "_wall_hanging_sign"):
+		c["hanging"] = int64(0)
+		return strings.TrimSuffix(name, "_wall_hanging_sign") + "_hanging_sign"
+	case strings.HasSuffix(name, "_hanging_sign"):
+		c["hanging"] = int64(1)
+	case strings.HasSuffix(name, "_wall_sign") || strings.HasSuffix(name, "_sign"):
+		if renamed, ok := blockRenames[name]; ok {
+			return renamed
+		}
+		if strings.HasSuffix(name, "_wall_sign") {
+			return name
+		}
+		return strings.TrimSuffix(name, "_sign") + "_standing_sign"
+	case strings.HasSuffix(name, "_slab") && p["type"] == "double":
+		if double := strings.TrimSuffix(name, "_slab") + "_double_slab"; stateExists("minecraft:" + double) {
+			return double
+		}
+		if double := "double_" + name; stateExists("minecraft:" + double) {
+			return double
+		}
+		switch name {
+		case "smooth_stone_slab", "sandstone_slab", "cobblestone_slab", "brick_slab", "stone_brick_slab",
+			"quartz_slab", "nether_brick_slab", "petrified_oak_slab":
+			c["stone_slab_type"] = strings.TrimSuffix(strings.TrimPrefix(name, "petrified_"), "_slab")
+			return "double_stone_block_slab"
+		}
+	}
+	if renamed, ok := blockRenames[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// stateExists checks if a Bedrock Edition block with the name passed exists.
+func stateExists(name string) bool {
+	_, ok := closestState(name, nil)
+	return ok
+}
+
+// Maps of Java Edition directions to the different encodings of directions in Bedrock Edition block states.
+var (
+	facingDirections     = map[string]int64{"down": 0, "up": 1, "north": 2, "south": 3, "west": 4, "east": 5}
+	horizontalDirections = map[string]int64{"south": 0, "west": 1, "north": 2, "east": 3}
+	doorDirections       = map[string]int64{"east": 0, "south": 1, "west": 2, "north": 3}
+	weirdoDirections     = map[string]int64{"east": 0, "west": 1, "south": 2, "north": 3}
+	oppositeDirections   = map[string]string{"north": "south", "south": "north", "east": "west", "west": "east"}
+	railDirections       = map[string]int64{
+		"north_south": 0, "east_west": 1, "ascending_east": 2, "ascending_west": 3, "ascending_north": 4,
+		"ascending_south": 5, "south_east": 6, "south_west": 7, "north_west": 8, "north_east": 9,
+	}
+	wallConnections = map[string]string{"none": "none", "low": "short", "tall": "tall"}
+	turtleEggCounts = []string{"one_egg", "two_egg", "three_egg", "four_egg"}
+	crackedStates   = []string{"no_cracks", "cracked", "max_cracked"}
+	vineBits        = map[string]int64{"south": 1, "west": 2, "north": 4, "east": 8}
+	multiFaceBits   = map[string]int64{"down": 1, "up": 2, "south": 4, "west": 8, "north": 16, "east": 32}
+)
+
+// translateProperties translates the properties of a Java Edition block state to candidate properties of
+// the equivalent Bedrock Edition block state.
+func translateProperties(name string, p map[string]string) map[string]any {
+	c := make(map[string]any, len(p)*2)
+	boolean := func(k string, v string) {
+		if v == "true" {
+			c[k] = int64(1)
+		} else {
+			c[k] = int64(0)
+		}
+	}
+	integer := func(k string, v string, offset int64) {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			c[k] = n + offset
+		}
+	}
+	var vine, multiFace int64
+	for k, v := range p {
+		switch k {
+		case "facing":
+			c["facing_direction"] = facingDirections[v]
+			c["minecraft:cardinal_direction"] = v
+			c["minecraft:facing_direction"] = v
+			c["minecraft:block_face"] = v
+			c["direction"] = horizontalDirections[v]
+			c["weirdo_direction"] = weirdoDirections[v]
+			c["coral_direction"] = horizontalDirections[v]
+			if strings.HasSuffix(name, "_door") {
+				c["direction"] = doorDirections[v]
+			} else if strings.HasSuffix(name, "trapdoor") {
+				c["direction"] = weirdoDirections[v]
+			}
+			if strings.HasSuffix(name, "wall_torch") {
+				c["torch_facing_direction"] = oppositeDirections[v]
+			}
+		case "axis":
+			c["pillar_axis"], c["axis"], c["portal_axis"] = v, v, v
+		case "half":
+			switch v {
+			case "top", "bottom":
+				boolean("upside_down_bit", strconv.FormatBool(v == "top"))
+				c["minecraft:vertical_half"] = v
+			case "upper", "lower":
+				boolean("upper_block_bit", strconv.FormatBool(v == "upper"))
+			}
+		case "type":
+			if v == "top" || v == "bottom" {
+				c["minecraft:vertical_half"] = v
+			}
+		case "face":
+			switch v {
+			case "floor":
+				c["facing_direction"] = int64(1)
+				c["attachment"] = "standing"
+			case "ceiling":
+				c["facing_direction"] = int64(0)
+				c["attachment"] = "hanging"
+			case "wall":
+				c["attachment"] = "side"
+			}
+		case "attachment":
+			c["attachment"] = map[string]string{"floor": "standing", "ceiling": "hanging", "single_wall": "side", "double_wall": "multiple"}[v]
+		case "open":
+			boolean("open_bit", v)
+		case "in_wall":
+			boolean("in_wall_bit", v)
+		case "hinge":
+			boolean("door_hinge_bit", strconv.FormatBool(v == "right"))
+		case "occupied":
+			boolean("occupied_bit", v)
+		case "part":
+			boolean("head_piece_bit", strconv.FormatBool(v == "head"))
+		case "persistent":
+			boolean("persistent_bit", v)
+		case "powered":
+			boolean("powered_bit", v)
+			boolean("button_pressed_bit", v)
+			boolean("rail_data_bit", v)
+		case "triggered":
+			boolean("triggered_bit", v)
+		case "enabled":
+			boolean("toggle_bit", strconv.FormatBool(v != "true"))
+		case "eye":
+			boolean("end_portal_eye_bit", v)
+		case "drag":
+			boolean("drag_down", v)
+		case "attached":
+			boolean("attached_bit", v)
+		case "disarmed":
+			boolean("disarmed_bit", v)
+		case "conditional":
+			boolean("conditional_bit", v)
+		case "lit":
+			boolean("lit", v)
+			boolean("extinguished", strconv.FormatBool(v != "true"))
+		case "hanging":
+			boolean("hanging", v)
+		case "vertical_direction":
+			boolean("hanging", strconv.FormatBool(v == "down"))
+		case "stage":
+			boolean("age_bit", strconv.FormatBool(v == "1"))
+		case "shrieking":
+			boolean("active", v)
+		case "can_summon":
+			boolean("can_summon", v)
+		case "bloom":
+			boolean("bloom", v)
+		case "age":
+			integer("age", v, 0)
+			integer("growth", v, 0)
+			integer("kelp_age", v, 0)
+			integer("weeping_vines_age", v, 0)
+			integer("twisting_vines_age", v, 0)
+			integer("growing_plant_age", v, 0)
+		case "power":
+			integer("redstone_signal", v, 0)
+		case "rotation":
+			integer("ground_sign_direction", v, 0)
+		case "layers":
+			integer("height", v, -1)
+		case "bites":
+			integer("bite_counter", v, 0)
+		case "moisture":
+			integer("moisturized_amount", v, 0)
+		case "candles":
+			integer("candles", v, -1)
+		case "level":
+			integer("liquid_depth", v, 0)
+			integer("composter_fill_level", v, 0)
+			integer("block_light_level", v, 0)
+		case "delay":
+			integer("repeater_delay", v, -1)
+		case "honey_level":
+			integer("honey_level", v, 0)
+		case "charges":
+			integer("respawn_anchor_charge", v, 0)
+		case "pickles":
+			integer("cluster_count", v, -1)
+		case "distance":
+			integer("stability", v, 0)
+		case "flower_amount":
+			integer("growth", v, -1)
+		case "dusted":
+			integer("brushed_progress", v, 0)
+		case "mode":
+			boolean("output_subtract_bit", strconv.FormatBool(v == "subtract"))
+		case "shape":
+			if d, ok := railDirections[v]; ok {
+				c["rail_direction"] = d
+			}
+		case "thickness":
+			c["dripstone_thickness"] = strings.Replace(v, "tip_merge", "merge", 1)
+		case "tilt":
+			c["big_dripleaf_tilt"] = map[string]string{"none": "none", "unstable": "unstable", "partial": "partial_tilt", "full": "full_tilt"}[v]
+		case "sculk_sensor_phase":
+			c["sculk_sensor_phase"] = map[string]int64{"inactive": 0, "active": 1, "cooldown": 2}[v]
+		case "eggs":
+			if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= len(turtleEggCounts) {
+				c["turtle_egg_count"] = turtleEggCounts[n-1]
+			}
+		case "hatch":
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 && n < len(crackedStates) {
+				c["cracked_state"] = crackedStates[n]
+			}
+		case "orientation":
+			c["orientation"] = v
+		case "up":
+			boolean("wall_post_bit", v)
+			if v == "true" {
+				multiFace |= multiFaceBits[k]
+			}
+		case "north", "east", "south", "west", "down":
+			if connection, ok := wallConnections[v]; ok {
+				c["wall_connection_type_"+k] = connection
+			}
+			if v == "true" {
+				vine |= vineBits[k]
+				multiFace |= multiFaceBits[k]
+			}
+		}
+	}
+	c["vine_direction_bits"] = vine
+	c["multi_face_direction_bits"] = multiFace
+	if name == "torch" || name == "soul_torch" || name == "redstone_torch" {
+		c["torch_facing_direction"] = "top"
+	}
+	return c
+}
@@ -0,0 +1,138 @@
+package anvil
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// blockEntityIDs maps the IDs of Java Edition block entities to the IDs of their Bedrock Edition equivalents
+// for block entities of which the ID is not simply the Java Edition ID in PascalCase.
+var blockEntityIDs = map[string]string{
+	"trapped_chest":    "Chest",
+	"enchanting_table": "EnchantTable",
+	"mob_spawner":      "MobSpawner",
+	"spawner":          "MobSpawner",
+	"sign":             "Sign",
+	"hanging_sign":     "HangingSign",
+	"skull":            "Skull",
+	"jigsaw":           "JigsawBlock",
+}
+
+// blockEntity translates the NBT of a Java Edition block entity so that it may be decoded by the Bedrock
+// Edition block at its position. Properties that are stored the same way by both editions are kept, while
+// the ID, custom name, inventory and sign text are translated.
+func (t *translator) blockEntity(m map[string]any) map[string]any {
+	data := maps.Clone(m)
+	id := strings.TrimPrefix(stringValue(m, "id"), "minecraft:")
+	if bedrockID, ok := blockEntityIDs[id]; ok {
+		data["id"] = bedrockID
+	} else {
+		var pascal strings.Builder
+		for _, part := range strings.Split(id, "_") {
+			if part != "" {
+				pascal.WriteString(strings.ToUpper(part[:1]) + part[1:])
+			}
+		}
+		data["id"] = pascal.String()
+	}
+	if name, ok := m["CustomName"].(string); ok {
+		data["CustomName"] = plainText(name)
+	}
+	if items, ok := m["Items"].([]any); ok {
+		translated := make([]any, 0, len(items))
+		for _, it := range items {
+			if it, ok := it.(map[string]any); ok {
+				translated = append(translated, translateItem(it))
+			}
+		}
+		data["Items"] = translated
+	}
+	if front := compound(m, "front_text"); front != nil {
+		data["FrontText"], data["BackText"] = signText(front), signText(compound(m, "back_text"))
+	} else if _, ok := m["Text1"]; ok {
+		// Signs from before Java Edition 1.20 only had text on the front, stored in a tag per line.
+		data["FrontText"] = signText(map[string]any{
+			"messages":         []any{m["Text1"], m["Text2"], m["Text3"], m["Text4"]},
+			"has_glowing_text": m["GlowingText"],
+		})
+	}
+	return data
+}
+
+// translateItem translates the NBT of a Java Edition item stack in an inventory to the NBT of a Bedrock
+// Edition item stack.
+func translateItem(m map[string]any) map[string]any {
+	name := stringValue(m, "id")
+	if _, ok := world.ItemByName(name, 0); !ok {
+		if renamed, ok := blockRenames[strings.TrimPrefix(name, "minecraft:")]; ok {
+			name = "minecraft:" + renamed
+		}
+	}
+	count := intValue(m, "Count")
+	if count == 0 {
+		// Java Edition 1.20.5 changed the name of the count tag and the type of its value.
+		count = intValue(m, "count")
+	}
+	tag := make(map[string]any)
+	if damage := intValue(compound(m, "tag"), "Damage"); damage != 0 {
+		tag["Damage"] = int16(damage)
+	} else if damage = intValue(compound(m, "components"), "minecraft:damage"); damage != 0 {
+		tag["Damage"] = int16(damage)
+	}
+	return map[string]any{
+		"Slot":  uint8(intValue(m, "Slot")),
+		"Name":  name,
+		"Count": uint8(count),
+		"tag":   tag,
+	}
+}
+
+// signText translates the text on one side of a Java Edition sign to the NBT of the text on one side of a
+// Bedrock Edition sign.
+func signText(m map[string]any) map[string]any {
+	messages, _ := m["messages"].([]any)
+	lines := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		str, _ := msg.(string)
+		lines = append(lines, plainText(str))
+	}
+	glowing, _ := m["has_glowing_text"].(uint8)
+	return map[string]any{
+		"Text":        strings.TrimRight(strings.Join(lines, "\n"), "\n"),
+		"GlowingText": glowing,
+	}
+}
+
+// plainText returns the plain text of a Java Edition JSON text component. If the string passed is not valid
+// JSON, it is returned as is.
+func plainText(s string) string {
+	var component any
+	if err := json.Unmarshal([]byte(s), &component); err != nil {
+		return s
+	}
+	var b strings.Builder
+	writeText(&b, component)
+	return b.String()
+}
+
+// writeText writes the text held by a decoded JSON text component and all of its children to b.
+func writeText(b *strings.Builder, component any) {
+	switch c := component.(type) {
+	case string:
+		b.WriteString(c)
+	case []any:
+		for _, child := range c {
+			writeText(b, child)
+		}
+	case map[string]any:
+		if text, ok := c["text"].(string); ok {
+			b.WriteString(text)
+		}
+		if extra, ok := c["extra"].([]any); ok {
+			writeText(b, extra)
+		}
+	}
+}
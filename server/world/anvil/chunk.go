@@ -0,0 +1,203 @@
+package anvil
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/goleveldb/leveldb"
+)
+
+// Data versions of Java Edition at which the format of chunks changed.
+const (
+	// dataVersionFlattening is the data version of Java Edition 1.13, in which numeric block IDs were
+	// replaced with block states with a name and properties.
+	dataVersionFlattening = 1519
+	// dataVersionPaddedStates is the data version of snapshot 20w17a, from which entries in block state
+	// and biome arrays no longer span multiple longs.
+	dataVersionPaddedStates = 2529
+	// dataVersionCavesAndCliffs is the data version of snapshot 21w43a (Java Edition 1.18), in which the
+	// Level compound of chunks was removed and biomes moved into the sections of a chunk.
+	dataVersionCavesAndCliffs = 2844
+)
+
+// column translates the NBT data of a Java Edition chunk to a world.Column for the dimension passed.
+func (t *translator) column(data map[string]any, dim world.Dimension) (*world.Column, error) {
+	ver := intValue(data, "DataVersion")
+	if ver < dataVersionFlattening {
+		return nil, fmt.Errorf("chunks from before Java Edition 1.13 (data version %v) are not supported", ver)
+	}
+	level, sectionsName, blockEntitiesName := data, "sections", "block_entities"
+	if ver < dataVersionCavesAndCliffs {
+		level, sectionsName, blockEntitiesName = compound(data, "Level"), "Sections", "TileEntities"
+	}
+	switch strings.TrimPrefix(stringValue(level, "Status"), "minecraft:") {
+	case "full", "postprocessed", "fullchunk":
+	default:
+		// The chunk was not fully generated by Java Edition, so we act as if it does not exist and let the
+		// World generate it instead.
+		return nil, leveldb.ErrNotFound
+	}
+
+	r := dim.Range()
+	c := chunk.New(t.air(), r)
+	padded := ver >= dataVersionPaddedStates
+	for _, section := range compounds(level, sectionsName) {
+		baseY := int(intValue(section, "Y")) << 4
+		if baseY < r.Min() || baseY > r.Max() {
+			continue
+		}
+		if ver >= dataVersionCavesAndCliffs {
+			states := compound(section, "block_states")
+			t.sectionBlocks(c, baseY, compounds(states, "palette"), longArray(states, "data"), padded)
+
+			biomes := compound(section, "biomes")
+			palette, _ := biomes["palette"].([]any)
+			t.sectionBiomes(c, baseY, palette, longArray(biomes, "data"))
+			continue
+		}
+		t.sectionBlocks(c, baseY, compounds(section, "Palette"), longArray(section, "BlockStates"), padded)
+	}
+	if ver < dataVersionCavesAndCliffs {
+		t.legacyBiomes(c, intArray(level, "Biomes"))
+	}
+
+	col := &world.Column{Chunk: c, BlockEntities: make(map[cube.Pos]world.Block)}
+	for _, m := range compounds(level, blockEntitiesName) {
+		pos := cube.Pos{int(intValue(m, "x")), int(intValue(m, "y")), int(intValue(m, "z"))}
+		if pos.OutOfBounds(r) {
+			continue
+		}
+		b, ok := world.BlockByRuntimeID(c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0))
+		if !ok {
+			continue
+		}
+		if _, ok := world.EncodeBlockNBT(b); ok {
+			col.BlockEntities[pos] = world.DecodeBlockNBT(b, t.blockEntity(m))
+		}
+	}
+	return col, nil
+}
+
+// sectionBlocks translates the blocks of a 16x16x16 section of a Java Edition chunk starting at baseY and
+// sets them to the chunk.Chunk passed. Blocks that are waterlogged in Java Edition have water set on their
+// second layer.
+func (t *translator) sectionBlocks(c *chunk.Chunk, baseY int, palette []map[string]any, data []int64, padded bool) {
+	if len(palette) == 0 {
+		return
+	}
+	rids, waterlogged := make([]uint32, len(palette)), make([]bool, len(palette))
+	for i, entry := range palette {
+		props := make(map[string]string)
+		for k, v := range compound(entry, "Properties") {
+			props[k], _ = v.(string)
+		}
+		rids[i], waterlogged[i] = t.block(stringValue(entry, "Name"), props)
+	}
+	r, air, water := c.Range(), t.air(), t.water()
+	for i, index := range unpack(data, len(palette), 4096, 4, padded) {
+		if int(index) >= len(palette) {
+			continue
+		}
+		x, y, z := uint8(i&15), int16(baseY+i>>8), uint8((i>>4)&15)
+		if int(y) > r.Max() {
+			break
+		}
+		if rid := rids[index]; rid != air {
+			c.SetBlock(x, y, z, 0, rid)
+		}
+		if waterlogged[index] {
+			c.SetBlock(x, y, z, 1, water)
+		}
+	}
+}
+
+// sectionBiomes translates the biomes of a section of a Java Edition chunk starting at baseY and sets them to
+// the chunk.Chunk passed. Java Edition stores a biome for every 4x4x4 cell of a section.
+func (t *translator) sectionBiomes(c *chunk.Chunk, baseY int, palette []any, data []int64) {
+	if len(palette) == 0 {
+		return
+	}
+	ids := make([]uint32, len(palette))
+	for i, name := range palette {
+		str, _ := name.(string)
+		ids[i] = t.biome(str)
+	}
+	for i, index := range unpack(data, len(palette), 64, 0, true) {
+		if int(index) < len(ids) {
+			fillBiomeCell(c, i&3, baseY>>2+i>>4, (i>>2)&3, ids[index])
+		}
+	}
+}
+
+// legacyBiomes sets the biomes stored in a chunk from before Java Edition 1.18 to the chunk.Chunk passed.
+// These are either stored per column of blocks, or, since Java Edition 1.15, per 4x4x4 cell for a chunk with
+// a height of 256 blocks.
+func (t *translator) legacyBiomes(c *chunk.Chunk, ids []int32) {
+	switch len(ids) {
+	case 256:
+		r := c.Range()
+		for i, id := range ids {
+			biome := t.legacyBiome(id)
+			for y := r.Min(); y <= r.Max(); y++ {
+				c.SetBiome(uint8(i&15), int16(y), uint8(i>>4), biome)
+			}
+		}
+	case 1024:
+		for i, id := range ids {
+			fillBiomeCell(c, i&3, i>>4, (i>>2)&3, t.legacyBiome(id))
+		}
+	}
+}
+
+// fillBiomeCell sets the biome passed to all blocks in the 4x4x4 cell at the cell coordinates passed.
+func fillBiomeCell(c *chunk.Chunk, cellX, cellY, cellZ int, biome uint32) {
+	r := c.Range()
+	for y := cellY << 2; y < cellY<<2+4; y++ {
+		if y < r.Min() || y > r.Max() {
+			continue
+		}
+		for x := cellX << 2; x < cellX<<2+4; x++ {
+			for z := cellZ << 2; z < cellZ<<2+4; z++ {
+				c.SetBiome(uint8(x), int16(y), uint8(z), biome)
+			}
+		}
+	}
+}
+
+// unpack unpacks n palette indices from the packed long array passed. Every index takes up as many bits as
+// needed to index a palette of paletteSize entries, with a minimum of minBits. If padded is true, indices do
+// not span multiple longs, and the remaining bits of each long are left unused.
+func unpack(data []int64, paletteSize, n, minBits int, padded bool) []uint16 {
+	indices := make([]uint16, n)
+	if paletteSize <= 1 || len(data) == 0 {
+		return indices
+	}
+	width := max(bits.Len(uint(paletteSize-1)), minBits)
+	mask := uint64(1)<<width - 1
+	perLong := 64 / width
+	for i := range indices {
+		if padded {
+			l := i / perLong
+			if l >= len(data) {
+				break
+			}
+			indices[i] = uint16(uint64(data[l]) >> ((i % perLong) * width) & mask)
+			continue
+		}
+		bit := i * width
+		l, offset := bit/64, bit%64
+		if l >= len(data) {
+			break
+		}
+		v := uint64(data[l]) >> offset
+		if offset+width > 64 && l+1 < len(data) {
+			v |= uint64(data[l+1]) << (64 - offset)
+		}
+		indices[i] = uint16(v & mask)
+	}
+	return indices
+}
@@ -0,0 +1,45 @@
+package anvil
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Config holds the optional parameters of a Provider.
+type Config struct {
+	// Log is the Logger that will be used to log errors and debug messages to, such as those for Java blocks
+	// and biomes that have no Bedrock Edition equivalent. If set to nil, slog.Default() is used.
+	Log *slog.Logger
+}
+
+// Open opens the Java Edition world in the directory passed. The directory must hold the level.dat of the
+// world. The Provider returned reads the region files of the world lazily as chunks are loaded.
+func (conf Config) Open(dir string) (*Provider, error) {
+	if conf.Log == nil {
+		conf.Log = slog.Default()
+	}
+	set, err := readLevelDat(filepath.Join(dir, "level.dat"))
+	if err != nil {
+		return nil, fmt.Errorf("open anvil world: %w", err)
+	}
+	p := &Provider{
+		conf:    conf,
+		dir:     dir,
+		set:     set,
+		regions: make(map[regionKey]*os.File),
+		t:       newTranslator(conf.Log),
+	}
+	return p, nil
+}
+
+// dimensionDirs holds the directories holding the region files of each dimension, relative to the directory
+// of a world.
+var dimensionDirs = map[world.Dimension]string{
+	world.Overworld: "region",
+	world.Nether:    filepath.Join("DIM-1", "region"),
+	world.End:       filepath.Join("DIM1", "region"),
+}
@@ -0,0 +1,67 @@
+package anvil
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// readLevelDat reads the gzip compressed level.dat of a Java Edition world at the path passed and returns the
+// world.Settings held by it.
+func readLevelDat(path string) (*world.Settings, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read level.dat: %w", err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read level.dat: %w", err)
+	}
+	var root map[string]any
+	if err := nbt.NewDecoderWithEncoding(r, nbt.BigEndian).Decode(&root); err != nil {
+		return nil, fmt.Errorf("decode level.dat: %w", err)
+	}
+	data := compound(root, "Data")
+	if data == nil {
+		return nil, fmt.Errorf("decode level.dat: no Data compound")
+	}
+
+	mode, _ := world.GameModeByID(int(intValue(data, "GameType")))
+	difficulty, ok := world.DifficultyByID(int(intValue(data, "Difficulty")))
+	if !ok {
+		difficulty = world.DifficultyNormal
+	}
+	s := &world.Settings{
+		Name:              stringValue(data, "LevelName"),
+		Spawn:             cube.Pos{int(intValue(data, "SpawnX")), int(intValue(data, "SpawnY")), int(intValue(data, "SpawnZ"))},
+		Time:              intValue(data, "DayTime"),
+		TimeCycle:         true,
+		RainTime:          intValue(data, "rainTime"),
+		Raining:           intValue(data, "raining") != 0,
+		ThunderTime:       intValue(data, "thunderTime"),
+		Thundering:        intValue(data, "thundering") != 0,
+		WeatherCycle:      true,
+		CurrentTick:       intValue(data, "Time"),
+		DefaultGameMode:   mode,
+		Difficulty:        difficulty,
+		TickRange:         6,
+		MaxEntityCramming: 24,
+	}
+	for name, v := range compound(data, "GameRules") {
+		str, _ := v.(string)
+		r, ok := world.GameRuleByName(name)
+		if !ok {
+			continue
+		}
+		// Java Edition stores all game rules as strings, so they are parsed like values passed to /gamerule.
+		if val, err := r.Parse(str); err == nil {
+			s.SetGameRule(r, val)
+		}
+	}
+	return s, nil
+}
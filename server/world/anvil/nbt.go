@@ -0,0 +1,97 @@
+package anvil
+
+import (
+	"encoding/binary"
+	"reflect"
+)
+
+// compound returns the compound tag with the name passed in the map m, or nil if m does not have a compound
+// tag with that name.
+func compound(m map[string]any, name string) map[string]any {
+	v, _ := m[name].(map[string]any)
+	return v
+}
+
+// compounds returns the compound tags in the list tag with the name passed in the map m. Values in the list
+// that are not compound tags are skipped.
+func compounds(m map[string]any, name string) []map[string]any {
+	list, _ := m[name].([]any)
+	c := make([]map[string]any, 0, len(list))
+	for _, v := range list {
+		if v, ok := v.(map[string]any); ok {
+			c = append(c, v)
+		}
+	}
+	return c
+}
+
+// stringValue returns the string tag with the name passed in the map m, or an empty string if m does not have
+// a string tag with that name.
+func stringValue(m map[string]any, name string) string {
+	v, _ := m[name].(string)
+	return v
+}
+
+// intValue returns the value of the integer tag with the name passed in the map m, regardless of its size. If
+// m does not have an integer tag with that name, 0 is returned.
+func intValue(m map[string]any, name string) int64 {
+	switch v := m[name].(type) {
+	case uint8:
+		return int64(int8(v))
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+// longArray returns the long array tag with the name passed in the map m as a slice. The NBT decoder decodes
+// array tags into Go arrays of the exact length of the tag, so reflection is used to turn them into a slice.
+func longArray(m map[string]any, name string) []int64 {
+	return unscrambleLongs(arrayOf[int64](m[name]))
+}
+
+// unscrambleLongs restores the values of a long array decoded by the big endian NBT decoder. The decoder
+// reverses the bytes of every long in place, but does so at offsets of 4 bytes rather than 8, scrambling the
+// bytes of all longs. The swaps are undone in reverse order to recover the original big endian bytes.
+func unscrambleLongs(s []int64) []int64 {
+	b := make([]byte, len(s)*8)
+	for i, v := range s {
+		binary.NativeEndian.PutUint64(b[i*8:], uint64(v))
+	}
+	for i := len(s) - 1; i >= 0; i-- {
+		off := i * 4
+		b[off], b[off+7] = b[off+7], b[off]
+		b[off+1], b[off+6] = b[off+6], b[off+1]
+		b[off+2], b[off+5] = b[off+5], b[off+2]
+		b[off+3], b[off+4] = b[off+4], b[off+3]
+	}
+	longs := make([]int64, len(s))
+	for i := range longs {
+		longs[i] = int64(binary.BigEndian.Uint64(b[i*8:]))
+	}
+	return longs
+}
+
+// intArray returns the int array tag with the name passed in the map m as a slice.
+func intArray(m map[string]any, name string) []int32 {
+	return arrayOf[int32](m[name])
+}
+
+// arrayOf turns a Go array or slice with elements of type T into a slice of T. If v is neither, nil is
+// returned.
+func arrayOf[T any](v any) []T {
+	if s, ok := v.([]T); ok {
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Array || rv.Type().Elem() != reflect.TypeOf(*new(T)) {
+		return nil
+	}
+	s := make([]T, rv.Len())
+	reflect.Copy(reflect.ValueOf(s), rv)
+	return s
+}
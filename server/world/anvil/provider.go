@@ -0,0 +1,114 @@
+package anvil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/google/uuid"
+)
+
+// Provider implements a world.Provider that reads a Java Edition world stored in the Anvil format. Blocks,
+// block entities and biomes are translated to their Bedrock Edition equivalents as chunks are loaded, so that
+// Java Edition maps may be loaded directly by a World.
+// Provider is read-only: Changes made to the World are not written back to the region files. Convert may be
+// used to copy the world into a Provider that is able to store it, such as an mcdb.DB.
+type Provider struct {
+	conf Config
+	dir  string
+	set  *world.Settings
+	t    *translator
+
+	mu      sync.Mutex
+	regions map[regionKey]*os.File
+}
+
+// Compile time check to make sure Provider implements world.Provider.
+var _ world.Provider = (*Provider)(nil)
+
+// Open opens the Java Edition world in the directory passed using default options. The directory must hold
+// the level.dat of the world.
+func Open(dir string) (*Provider, error) {
+	var conf Config
+	return conf.Open(dir)
+}
+
+// Settings returns the world.Settings read from the level.dat of the world.
+func (p *Provider) Settings() *world.Settings {
+	return p.set
+}
+
+// SaveSettings does nothing: The level.dat of the world is never written to.
+func (p *Provider) SaveSettings(*world.Settings) {}
+
+// LoadPlayerSpawnPosition always returns false, as Java Edition stores the spawn points of players in their
+// player data, which is not read by the Provider.
+func (p *Provider) LoadPlayerSpawnPosition(uuid.UUID) (cube.Pos, bool, error) {
+	return cube.Pos{}, false, nil
+}
+
+// SavePlayerSpawnPosition does nothing: The Provider is read-only.
+func (p *Provider) SavePlayerSpawnPosition(uuid.UUID, cube.Pos) error {
+	return nil
+}
+
+// LoadColumn reads the chunk at the position passed from the region files of the dimension passed and
+// translates it to a world.Column. If the chunk does not exist or was not fully generated by Java Edition,
+// errors.Is(err, leveldb.ErrNotFound) equals true.
+func (p *Provider) LoadColumn(pos world.ChunkPos, dim world.Dimension) (*world.Column, error) {
+	data, err := p.chunkData(pos, dim)
+	if err != nil {
+		return nil, fmt.Errorf("load column %v (%v): %w", pos, dim, err)
+	}
+	col, err := p.t.column(data, dim)
+	if err != nil {
+		return nil, fmt.Errorf("load column %v (%v): %w", pos, dim, err)
+	}
+	return col, nil
+}
+
+// StoreColumn does nothing: The Provider is read-only.
+func (p *Provider) StoreColumn(world.ChunkPos, world.Dimension, *world.Column) error {
+	return nil
+}
+
+// Convert translates all chunks of all dimensions of the world and stores them in the world.Provider passed,
+// together with the world.Settings of the world. Chunks that cannot be translated are logged and skipped.
+func (p *Provider) Convert(dst world.Provider) error {
+	for dim := range dimensionDirs {
+		positions, err := p.chunkPositions(dim)
+		if err != nil {
+			return fmt.Errorf("convert %v: %w", dim, err)
+		}
+		for _, pos := range positions {
+			col, err := p.LoadColumn(pos, dim)
+			if errors.Is(err, leveldb.ErrNotFound) {
+				continue
+			} else if err != nil {
+				p.conf.Log.Error("Failed converting chunk.", "pos", pos, "err", err)
+				continue
+			}
+			if err := dst.StoreColumn(pos, dim, col); err != nil {
+				return fmt.Errorf("convert %v: %w", dim, err)
+			}
+		}
+	}
+	dst.SaveSettings(p.set)
+	return nil
+}
+
+// Close closes all region files opened by the Provider.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var err error
+	for k, f := range p.regions {
+		err = errors.Join(err, f.Close())
+		delete(p.regions, k)
+	}
+	return err
+}
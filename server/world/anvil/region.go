@@ -0,0 +1,174 @@
+package anvil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+const (
+	// sectorSize is the size of a single sector in a region file. The header of a region file and all chunks
+	// stored in it are aligned to sectors.
+	sectorSize = 4096
+	// regionSize is the number of chunks on both the X and Z axis of a region file.
+	regionSize = 32
+)
+
+// Compression types that may be used to compress chunks in a region file.
+const (
+	compressionGzip         = 1
+	compressionZlib         = 2
+	compressionNone         = 3
+	compressionLZ4          = 4
+	compressionExternalFlag = 128
+)
+
+// regionKey identifies a region file by the position of the region and the dimension it is in.
+type regionKey struct {
+	x, z int32
+	dim  world.Dimension
+}
+
+// regionPos returns the regionKey of the region file that holds the chunk at the position passed.
+func regionPos(pos world.ChunkPos, dim world.Dimension) regionKey {
+	return regionKey{x: pos[0] >> 5, z: pos[1] >> 5, dim: dim}
+}
+
+// path returns the path of the region file relative to the directory of the world.
+func (k regionKey) path() string {
+	return filepath.Join(dimensionDirs[k.dim], fmt.Sprintf("r.%d.%d.mca", k.x, k.z))
+}
+
+// region returns the region file for the regionKey passed, opening it if it was not yet opened. If the region
+// file does not exist, an error for which errors.Is(err, leveldb.ErrNotFound) is true is returned.
+func (p *Provider) region(k regionKey) (*os.File, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f, ok := p.regions[k]; ok {
+		return f, nil
+	}
+	f, err := os.Open(filepath.Join(p.dir, k.path()))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, leveldb.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	p.regions[k] = f
+	return f, nil
+}
+
+// chunkData reads and decompresses the NBT data of the chunk at the position passed.
+func (p *Provider) chunkData(pos world.ChunkPos, dim world.Dimension) (map[string]any, error) {
+	k := regionPos(pos, dim)
+	f, err := p.region(k)
+	if err != nil {
+		return nil, err
+	}
+	var loc [4]byte
+	if _, err := f.ReadAt(loc[:], int64(4*((pos[0]&(regionSize-1))+(pos[1]&(regionSize-1))*regionSize))); err != nil {
+		if errors.Is(err, io.EOF) {
+			// The region file is truncated or empty, so it holds no chunks.
+			return nil, leveldb.ErrNotFound
+		}
+		return nil, fmt.Errorf("read chunk location: %w", err)
+	}
+	offset := int64(loc[0])<<16 | int64(loc[1])<<8 | int64(loc[2])
+	if offset == 0 || loc[3] == 0 {
+		return nil, leveldb.ErrNotFound
+	}
+
+	var header [5]byte
+	if _, err := f.ReadAt(header[:], offset*sectorSize); err != nil {
+		return nil, fmt.Errorf("read chunk header: %w", err)
+	}
+	length, compression := int64(binary.BigEndian.Uint32(header[:4])), header[4]
+	if length > int64(loc[3])*sectorSize {
+		return nil, fmt.Errorf("chunk length %v exceeds %v allocated sectors", length, loc[3])
+	}
+
+	var compressed []byte
+	if compression&compressionExternalFlag != 0 {
+		// The chunk was too large to fit in the region file and is stored in a separate file instead.
+		compression &^= compressionExternalFlag
+		compressed, err = os.ReadFile(filepath.Join(p.dir, dimensionDirs[dim], fmt.Sprintf("c.%d.%d.mcc", pos[0], pos[1])))
+		if err != nil {
+			return nil, fmt.Errorf("read external chunk: %w", err)
+		}
+	} else {
+		compressed = make([]byte, max(length-1, 0))
+		if _, err := f.ReadAt(compressed, offset*sectorSize+int64(len(header))); err != nil {
+			return nil, fmt.Errorf("read chunk: %w", err)
+		}
+	}
+
+	var r io.Reader = bytes.NewReader(compressed)
+	switch compression {
+	case compressionGzip:
+		if r, err = gzip.NewReader(r); err != nil {
+			return nil, fmt.Errorf("decompress chunk: %w", err)
+		}
+	case compressionZlib:
+		if r, err = zlib.NewReader(r); err != nil {
+			return nil, fmt.Errorf("decompress chunk: %w", err)
+		}
+	case compressionNone:
+	case compressionLZ4:
+		return nil, fmt.Errorf("decompress chunk: lz4 compression is not supported")
+	default:
+		return nil, fmt.Errorf("decompress chunk: unknown compression type %v", compression)
+	}
+	var m map[string]any
+	if err := nbt.NewDecoderWithEncoding(r, nbt.BigEndian).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode chunk nbt: %w", err)
+	}
+	return m, nil
+}
+
+// regionFileName matches the names of region files, capturing the X and Z position of the region.
+var regionFileName = regexp.MustCompile(`^r\.(-?\d+)\.(-?\d+)\.mca$`)
+
+// chunkPositions returns the positions of all chunks stored in the region files of the dimension passed.
+func (p *Provider) chunkPositions(dim world.Dimension) ([]world.ChunkPos, error) {
+	entries, err := os.ReadDir(filepath.Join(p.dir, dimensionDirs[dim]))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var positions []world.ChunkPos
+	for _, entry := range entries {
+		match := regionFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		x, _ := strconv.Atoi(match[1])
+		z, _ := strconv.Atoi(match[2])
+		f, err := p.region(regionKey{x: int32(x), z: int32(z), dim: dim})
+		if err != nil {
+			return nil, err
+		}
+		locations := make([]byte, sectorSize)
+		if _, err := f.ReadAt(locations, 0); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read region %v: %w", entry.Name(), err)
+		}
+		for i := 0; i < regionSize*regionSize; i++ {
+			if binary.BigEndian.Uint32(locations[i*4:]) == 0 {
+				continue
+			}
+			positions = append(positions, world.ChunkPos{int32(x*regionSize + i%regionSize), int32(z*regionSize + i/regionSize)})
+		}
+	}
+	return positions, nil
+}
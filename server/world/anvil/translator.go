@@ -0,0 +1,178 @@
+package anvil
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/exp/maps"
+)
+
+// translator translates Java Edition block states, biomes and block entities to their Bedrock Edition
+// equivalents. Translated block states are cached, as the same states are found in the palettes of nearly
+// every chunk.
+type translator struct {
+	log *slog.Logger
+
+	mu      sync.Mutex
+	blocks  map[string]translatedBlock
+	unknown map[string]struct{}
+}
+
+// translatedBlock is the result of translating a Java Edition block state.
+type translatedBlock struct {
+	rid         uint32
+	waterlogged bool
+}
+
+// newTranslator returns a translator that logs blocks and biomes without Bedrock Edition equivalent to the
+// Logger passed.
+func newTranslator(log *slog.Logger) *translator {
+	return &translator{log: log, blocks: make(map[string]translatedBlock), unknown: make(map[string]struct{})}
+}
+
+// block returns the runtime ID of the Bedrock Edition block state that is equivalent to the Java Edition
+// block state passed, and whether the block is waterlogged. If no equivalent exists, air is returned.
+func (t *translator) block(name string, props map[string]string) (uint32, bool) {
+	keys := maps.Keys(props)
+	slices.Sort(keys)
+	var key strings.Builder
+	key.WriteString(name)
+	for _, k := range keys {
+		key.WriteString("," + k + "=" + props[k])
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b, ok := t.blocks[key.String()]; ok {
+		return b.rid, b.waterlogged
+	}
+	bedrockName, candidates := translateBlock(name, props)
+	rid, ok := closestState(bedrockName, candidates)
+	if !ok {
+		t.logUnknown("block", name)
+		rid = t.air()
+	}
+	b := translatedBlock{rid: rid, waterlogged: props["waterlogged"] == "true"}
+	t.blocks[key.String()] = b
+	return b.rid, b.waterlogged
+}
+
+// biome returns the ID of the Bedrock Edition biome equivalent to the Java Edition biome with the name
+// passed. If no equivalent exists, plains is returned.
+func (t *translator) biome(name string) uint32 {
+	name = strings.TrimPrefix(name, "minecraft:")
+	if renamed, ok := biomeRenames[name]; ok {
+		name = renamed
+	}
+	b, ok := world.BiomeByName(name)
+	if !ok {
+		t.mu.Lock()
+		t.logUnknown("biome", name)
+		t.mu.Unlock()
+		return plainsID
+	}
+	return uint32(b.EncodeBiome())
+}
+
+// legacyBiome returns the ID of the Bedrock Edition biome equivalent to the numeric Java Edition biome ID
+// passed, as used before Java Edition 1.18. Numeric biome IDs are the same for both editions.
+func (t *translator) legacyBiome(id int32) uint32 {
+	if _, ok := world.BiomeByID(int(id)); ok {
+		return uint32(id)
+	}
+	return plainsID
+}
+
+// plainsID is the ID of the plains biome, which is used for biomes without Bedrock Edition equivalent.
+const plainsID = 1
+
+// logUnknown logs that a block or biome with the name passed has no Bedrock Edition equivalent. Every name is
+// logged only once. t.mu must be held when calling logUnknown.
+func (t *translator) logUnknown(kind, name string) {
+	if _, ok := t.unknown[kind+name]; ok {
+		return
+	}
+	t.unknown[kind+name] = struct{}{}
+	t.log.Debug("Java Edition "+kind+" has no Bedrock Edition equivalent.", "name", name)
+}
+
+// air returns the runtime ID of air.
+func (t *translator) air() uint32 {
+	rid, _ := closestState("minecraft:air", nil)
+	return rid
+}
+
+// water returns the runtime ID of a still water source block.
+func (t *translator) water() uint32 {
+	rid, _ := closestState("minecraft:water", map[string]any{"liquid_depth": int64(0)})
+	return rid
+}
+
+// bedrockState is a Bedrock Edition block state with its runtime ID.
+type bedrockState struct {
+	rid   uint32
+	props map[string]any
+}
+
+var (
+	statesOnce sync.Once
+	// statesByName holds all Bedrock Edition block states indexed by their name.
+	statesByName map[string][]bedrockState
+)
+
+// closestState returns the runtime ID of the Bedrock Edition block state with the name passed that matches
+// the most of the candidate properties passed. Candidate properties that the block does not have are ignored,
+// so candidates may hold multiple encodings of the same Java Edition property. False is returned if no block
+// with the name exists.
+func closestState(name string, candidates map[string]any) (uint32, bool) {
+	statesOnce.Do(func() {
+		statesByName = make(map[string][]bedrockState)
+		for rid := uint32(0); ; rid++ {
+			b, ok := world.BlockByRuntimeID(rid)
+			if !ok {
+				break
+			}
+			n, props := b.EncodeBlock()
+			statesByName[n] = append(statesByName[n], bedrockState{rid: rid, props: props})
+		}
+	})
+	states, ok := statesByName[name]
+	if !ok {
+		return 0, false
+	}
+	best, bestScore := states[0].rid, -1
+	for _, s := range states {
+		score := 0
+		for k, v := range candidates {
+			if sv, ok := s.props[k]; ok && normalise(sv) == v {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = s.rid, score
+		}
+	}
+	return best, true
+}
+
+// normalise normalises a Bedrock Edition block property value so that it may be compared with a candidate
+// property value. Booleans and integers of all sizes are turned into an int64.
+func normalise(v any) any {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case uint8:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	}
+	return v
+}
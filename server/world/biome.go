@@ -1,5 +1,10 @@
 package world
 
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"image/color"
+)
+
 // Biome is a region in a world with distinct geographical features, flora, temperatures, humidity ratings,
 // and sky, water, grass and foliage colors.
 type Biome interface {
@@ -13,18 +18,71 @@ type Biome interface {
 	EncodeBiome() int
 }
 
+// ColouredBiome is a Biome with a custom water colour. The colour is announced to clients in the biome
+// definitions sent when joining. Biomes that do not implement ColouredBiome use the default water colour
+// of the client.
+type ColouredBiome interface {
+	Biome
+	// WaterColour returns the colour of water in the biome.
+	WaterColour() color.RGBA
+}
+
+// TaggedBiome is a Biome with a list of tags, such as "overworld", "forest" or "cold". The tags are announced
+// to clients in the biome definitions and are used by the client for features such as fog and ambient sounds.
+type TaggedBiome interface {
+	Biome
+	// Tags returns the tags of the biome.
+	Tags() []string
+}
+
+// SurfaceBiome is a Biome with custom surface blocks. Generators that support it place the blocks returned
+// at the surface of the terrain of the biome instead of the blocks they would otherwise use.
+type SurfaceBiome interface {
+	Biome
+	// Surface returns the top-most block of the surface of the biome, the top-most block if it is under
+	// water and the block placed in the layers below the top-most block.
+	Surface() (top, underwater, filler Block)
+}
+
+// SpawningBiome is a Biome with a pool of entities that spawn in it. If GameRuleDoMobSpawning is enabled,
+// groups of entities selected from the pool are spawned naturally on the surface of the biome in chunks
+// that are entity ticking.
+type SpawningBiome interface {
+	Biome
+	// Spawns returns the entities that may spawn in the biome.
+	Spawns() []BiomeSpawn
+}
+
+// BiomeSpawn is an entry in the spawn pool of a SpawningBiome.
+type BiomeSpawn struct {
+	// Type is the EntityType of the entities spawned.
+	Type EntityType
+	// Weight is the weight of the entry relative to the other entries of the pool. Entries with a higher
+	// weight are selected more often.
+	Weight int
+	// MinGroup and MaxGroup are the minimum and maximum number of entities spawned at once.
+	MinGroup, MaxGroup int
+	// New creates a new entity of the Type at the position passed. Entries with a nil New are never spawned.
+	New func(pos mgl64.Vec3) Entity
+}
+
 // biomes holds a map of id => Biome to be used for looking up the biome by an ID. It is registered
 // to when calling RegisterBiome.
 var biomes = map[int]Biome{}
 
 var biomeByName = map[string]Biome{}
 
-// RegisterBiome registers a biome to the map so that it can be saved and loaded with the world.
+// RegisterBiome registers a biome to the map so that it can be saved and loaded with the world. Custom biomes
+// may be registered by plugins as long as both their ID and name are unique. Biomes must be registered before
+// the server starts listening, so that they are included in the biome definitions sent to clients.
 func RegisterBiome(b Biome) {
 	id := b.EncodeBiome()
 	if _, ok := biomes[id]; ok {
 		panic("cannot register the same biome (" + b.String() + ") twice")
 	}
+	if _, ok := biomeByName[b.String()]; ok {
+		panic("cannot register biome " + b.String() + ": a biome with the same name is already registered")
+	}
 	biomes[id] = b
 	biomeByName[b.String()] = b
 }
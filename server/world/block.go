@@ -116,7 +116,7 @@ func RegisterBlock(b Block) {
 	if emitter, ok := b.(lightEmitter); ok {
 		chunk.LightBlocks[rid] = emitter.LightEmissionLevel()
 	}
-	if _, ok := b.(NBTer); ok {
+	if hasBlockEntity(b) {
 		nbtBlocks[rid] = true
 	}
 	if _, ok := b.(RandomTicker); ok {
@@ -128,6 +128,9 @@ func RegisterBlock(b Block) {
 	if _, ok := b.(LiquidDisplacer); ok {
 		liquidDisplacingBlocks[rid] = true
 	}
+	if _, ok := b.(LightningAttractor); ok {
+		lightningAttractorBlocks[rid] = true
+	}
 	if c, ok := b.(CustomBlock); ok {
 		if _, ok := customBlocks[name]; !ok {
 			customBlocks[name] = c
@@ -224,6 +227,15 @@ type NeighbourUpdateTicker interface {
 	NeighbourUpdateTick(pos, changedNeighbour cube.Pos, w *World)
 }
 
+// LightningAttractor represents a block that attracts lightning, such as a lightning rod. Lightning that
+// would strike close to a LightningAttractor that is the highest block at its position strikes the
+// LightningAttractor instead.
+type LightningAttractor interface {
+	Block
+	// StrikeLightning is called when lightning strikes the LightningAttractor at the position passed.
+	StrikeLightning(pos cube.Pos, w *World)
+}
+
 // NBTer represents either an item or a block which may decode NBT data and encode to NBT data. Typically,
 // this is done to store additional data.
 type NBTer interface {
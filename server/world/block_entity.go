@@ -0,0 +1,91 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+// BlockEntityData holds the state of the block entity of a BlockEntityHolder, such as the inventory or the
+// progress of a custom machine. BlockEntityData is typically implemented by a pointer type, so that the state
+// of a block may be changed without setting the block again.
+type BlockEntityData interface {
+	// EncodeNBT encodes the BlockEntityData into a map which can then be encoded as NBT to be written.
+	EncodeNBT() map[string]any
+	// DecodeNBT decodes the NBT data passed into the BlockEntityData.
+	DecodeNBT(data map[string]any)
+}
+
+// TickingBlockEntityData is BlockEntityData with an additional Tick method that is called on every world tick
+// for loaded blocks holding the TickingBlockEntityData.
+type TickingBlockEntityData interface {
+	BlockEntityData
+	// Tick is called every world tick with the position of the block holding the TickingBlockEntityData.
+	Tick(currentTick int64, pos cube.Pos, w *World)
+}
+
+// BlockEntityHolder is a Block, typically one defined by a plugin, that holds a block entity with arbitrary
+// BlockEntityData. Unlike blocks implementing NBTer, a BlockEntityHolder does not need to encode and decode
+// itself, nor set itself again to change its state: The World creates, saves and loads the BlockEntityData of
+// the block and calls the Tick method of TickingBlockEntityData.
+//
+// The Hash of a BlockEntityHolder should not depend on its BlockEntityData.
+type BlockEntityHolder interface {
+	Block
+	// BlockEntityData returns the BlockEntityData held by the block, or nil if the block does not yet hold
+	// any.
+	BlockEntityData() BlockEntityData
+	// NewBlockEntityData returns new BlockEntityData for the block, holding the default state of the block
+	// entity.
+	NewBlockEntityData() BlockEntityData
+	// WithBlockEntityData returns a copy of the block that holds the BlockEntityData passed.
+	WithBlockEntityData(data BlockEntityData) Block
+}
+
+// EncodeBlockNBT encodes the block entity of the Block passed to NBT data. Both blocks implementing NBTer and
+// BlockEntityHolders are supported. If the Block has no block entity, false is returned.
+func EncodeBlockNBT(b Block) (map[string]any, bool) {
+	switch v := b.(type) {
+	case NBTer:
+		return v.EncodeNBT(), true
+	case BlockEntityHolder:
+		data := v.BlockEntityData()
+		if data == nil {
+			data = v.NewBlockEntityData()
+		}
+		return data.EncodeNBT(), true
+	}
+	return nil, false
+}
+
+// DecodeBlockNBT decodes the NBT data passed into the block entity of the Block passed and returns the
+// resulting Block. Both blocks implementing NBTer and BlockEntityHolders are supported. If the Block has no
+// block entity, it is returned as is.
+func DecodeBlockNBT(b Block, data map[string]any) Block {
+	switch v := b.(type) {
+	case NBTer:
+		return v.DecodeNBT(data).(Block)
+	case BlockEntityHolder:
+		d := v.NewBlockEntityData()
+		d.DecodeNBT(data)
+		return v.WithBlockEntityData(d)
+	}
+	return b
+}
+
+// hasBlockEntity checks if the Block passed has a block entity, either by implementing NBTer or
+// BlockEntityHolder.
+func hasBlockEntity(b Block) bool {
+	switch b.(type) {
+	case NBTer, BlockEntityHolder:
+		return true
+	}
+	return false
+}
+
+// withBlockEntity returns the Block passed with new BlockEntityData if it is a BlockEntityHolder without any.
+// Other blocks are returned as is.
+func withBlockEntity(b Block) Block {
+	if h, ok := b.(BlockEntityHolder); ok && h.BlockEntityData() == nil {
+		return h.WithBlockEntityData(h.NewBlockEntityData())
+	}
+	return b
+}
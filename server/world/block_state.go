@@ -38,6 +38,9 @@ var (
 	// liquidDisplacingBlocks holds a list of LiquidDisplacer implementations for blocks registered that implement the LiquidDisplacer interface.
 	// These are indexed by their runtime IDs. Blocks that do not implement LiquidDisplacer have a false value in this slice.
 	liquidDisplacingBlocks []bool
+	// lightningAttractorBlocks holds a list of LightningAttractor implementations for blocks registered that implement the LightningAttractor interface.
+	// These are indexed by their runtime IDs. Blocks that do not implement LightningAttractor have a false value in this slice.
+	lightningAttractorBlocks []bool
 	// airRID is the runtime ID of an air block.
 	airRID uint32
 )
@@ -112,6 +115,7 @@ func registerBlockState(s blockState, order bool) {
 	randomTickBlocks = slices.Insert(randomTickBlocks, int(rid), false)
 	liquidBlocks = slices.Insert(liquidBlocks, int(rid), false)
 	liquidDisplacingBlocks = slices.Insert(liquidDisplacingBlocks, int(rid), false)
+	lightningAttractorBlocks = slices.Insert(lightningAttractorBlocks, int(rid), false)
 	chunk.FilteringBlocks = slices.Insert(chunk.FilteringBlocks, int(rid), 15)
 	chunk.LightBlocks = slices.Insert(chunk.LightBlocks, int(rid), 0)
 	stateRuntimeIDs[h] = rid
@@ -0,0 +1,234 @@
+package world
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+const (
+	// DefaultBorderSize is the size of a Border that was not resized. It is the size of the world border in
+	// Java Edition, which spans close to the full width of a world.
+	DefaultBorderSize = 59999968
+	// MinBorderSize is the smallest size that a Border can have.
+	MinBorderSize = 1.0
+)
+
+// Border is the border of a World. It is a square with a centre and a size on the X and Z axis, outside of
+// which players cannot place blocks or teleport and take damage. A Border may be resized over time, in which
+// case its size is interpolated linearly until the target size is reached. A Border is safe for concurrent use.
+type Border struct {
+	mu sync.Mutex
+
+	centre mgl64.Vec2
+
+	from, to        float64
+	start, end      time.Time
+	damageBuffer    float64
+	damagePerBlock  float64
+	warningDistance int
+	warningTime     time.Duration
+}
+
+// newBorder returns a new Border with the default size and settings, centred at 0, 0.
+func newBorder() *Border {
+	return &Border{
+		from:            DefaultBorderSize,
+		to:              DefaultBorderSize,
+		damageBuffer:    5,
+		damagePerBlock:  0.2,
+		warningDistance: 5,
+		warningTime:     time.Second * 15,
+	}
+}
+
+// Centre returns the centre of the Border on the X and Z axis.
+func (b *Border) Centre() mgl64.Vec2 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.centre
+}
+
+// SetCentre moves the centre of the Border to the X and Z coordinates passed.
+func (b *Border) SetCentre(centre mgl64.Vec2) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.centre = centre
+}
+
+// Size returns the current size of the Border, which is the length of each of its sides. If the Border is
+// being resized, the size returned lies between the size it had when the resize started and its target size.
+func (b *Border) Size() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size(time.Now())
+}
+
+// TargetSize returns the size that the Border is being resized to. If the Border is not being resized,
+// TargetSize returns the same value as Size.
+func (b *Border) TargetSize() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.to
+}
+
+// SetSize changes the size of the Border immediately, stopping any resize in progress. The size is clamped
+// between MinBorderSize and DefaultBorderSize.
+func (b *Border) SetSize(size float64) {
+	b.ResizeTo(size, 0)
+}
+
+// ResizeTo resizes the Border from its current size to the size passed over the duration passed. If the
+// duration is 0 or less, the Border is resized immediately. The size is clamped between MinBorderSize and
+// DefaultBorderSize.
+func (b *Border) ResizeTo(size float64, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.from, b.to = b.size(now), math.Max(math.Min(size, DefaultBorderSize), MinBorderSize)
+	b.start, b.end = now, now.Add(d)
+	if d <= 0 {
+		b.from = b.to
+	}
+}
+
+// Resizing checks if the Border is currently being resized. If so, the remaining time of the resize is
+// returned.
+func (b *Border) Resizing() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	remaining := time.Until(b.end)
+	if remaining <= 0 || b.from == b.to {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// size returns the size of the Border at the time passed. b.mu must be held when calling size.
+func (b *Border) size(t time.Time) float64 {
+	if b.from == b.to || !t.Before(b.end) {
+		return b.to
+	}
+	progress := float64(t.Sub(b.start)) / float64(b.end.Sub(b.start))
+	return b.from + (b.to-b.from)*progress
+}
+
+// DamageBuffer returns the distance outside the Border within which entities do not take damage.
+func (b *Border) DamageBuffer() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.damageBuffer
+}
+
+// SetDamageBuffer changes the distance outside the Border within which entities do not take damage.
+func (b *Border) SetDamageBuffer(buffer float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.damageBuffer = math.Max(buffer, 0)
+}
+
+// DamagePerBlock returns the damage dealt to an entity every second for each block that it is beyond the
+// DamageBuffer of the Border.
+func (b *Border) DamagePerBlock() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.damagePerBlock
+}
+
+// SetDamagePerBlock changes the damage dealt to an entity every second for each block that it is beyond the
+// DamageBuffer of the Border. If set to 0, the Border does not deal damage.
+func (b *Border) SetDamagePerBlock(damage float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.damagePerBlock = math.Max(damage, 0)
+}
+
+// WarningDistance returns the distance from the Border within which players are warned that they are close
+// to it.
+func (b *Border) WarningDistance() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.warningDistance
+}
+
+// SetWarningDistance changes the distance from the Border within which players are warned that they are close
+// to it.
+func (b *Border) SetWarningDistance(distance int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.warningDistance = distance
+}
+
+// WarningTime returns the time within which a shrinking Border must reach a player for the player to be
+// warned about it.
+func (b *Border) WarningTime() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.warningTime
+}
+
+// SetWarningTime changes the time within which a shrinking Border must reach a player for the player to be
+// warned about it.
+func (b *Border) SetWarningTime(t time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.warningTime = t
+}
+
+// Bounds returns the minimum and maximum X and Z coordinates of the Border at its current size.
+func (b *Border) Bounds() (minimum, maximum mgl64.Vec2) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	half := b.size(time.Now()) / 2
+	return b.centre.Sub(mgl64.Vec2{half, half}), b.centre.Add(mgl64.Vec2{half, half})
+}
+
+// Distance returns the distance from the position passed to the closest edge of the Border on the X and Z
+// axis. The distance is positive if the position is within the Border and negative if it is outside of it.
+func (b *Border) Distance(pos mgl64.Vec3) float64 {
+	minimum, maximum := b.Bounds()
+	return math.Min(math.Min(pos[0]-minimum[0], maximum[0]-pos[0]), math.Min(pos[2]-minimum[1], maximum[1]-pos[2]))
+}
+
+// Within checks if the position passed lies within the Border.
+func (b *Border) Within(pos mgl64.Vec3) bool {
+	return b.Distance(pos) >= 0
+}
+
+// WithinPos checks if the block at the block position passed lies fully within the Border.
+func (b *Border) WithinPos(pos cube.Pos) bool {
+	minimum, maximum := b.Bounds()
+	return float64(pos[0]) >= minimum[0] && float64(pos[0]+1) <= maximum[0] &&
+		float64(pos[2]) >= minimum[1] && float64(pos[2]+1) <= maximum[1]
+}
+
+// Damage returns the damage that should be dealt every second to an entity at the position passed. If the
+// entity is within the Border or its DamageBuffer, 0 is returned.
+func (b *Border) Damage(pos mgl64.Vec3) float64 {
+	beyond, perBlock := -b.Distance(pos)-b.DamageBuffer(), b.DamagePerBlock()
+	if beyond <= 0 || perBlock == 0 {
+		return 0
+	}
+	return math.Max(1, math.Floor(beyond*perBlock))
+}
+
+// Warning checks if a player at the position passed should be warned about the Border. This is the case if
+// the player is within the WarningDistance of the Border, or if the Border is shrinking and will reach the
+// player within the WarningTime.
+func (b *Border) Warning(pos mgl64.Vec3) bool {
+	dist := b.Distance(pos)
+	if dist < float64(b.WarningDistance()) {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.to >= b.from || !now.Before(b.end) {
+		return false
+	}
+	speed := (b.from - b.to) / 2 / b.end.Sub(b.start).Seconds()
+	return time.Duration(dist/speed*float64(time.Second)) < b.warningTime
+}
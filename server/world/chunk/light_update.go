@@ -0,0 +1,109 @@
+package chunk
+
+import (
+	"container/list"
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+// Relight incrementally updates the sky and block light in the lightArea after the blocks at the positions
+// passed were changed. Unlike Fill and Spread, only the light around the positions passed is recalculated: Light
+// that originated from or passed through the changed blocks is first removed, after which the light of the
+// surrounding blocks and any new light sources is spread again.
+// The positions passed must be in the centre Chunk of the lightArea, so that all light changed as a result can
+// be found within the lightArea.
+func (a *lightArea) Relight(positions []cube.Pos) {
+	a.relight(positions, BlockLight)
+	a.relight(positions, SkyLight)
+}
+
+// relight incrementally updates the light of the light type passed at the positions passed.
+func (a *lightArea) relight(positions []cube.Pos, lt light) {
+	removal, addition := list.New(), list.New()
+	for _, pos := range positions {
+		if level := a.light(pos, lt); level > 0 {
+			a.setLight(pos, lt, 0)
+			removal.PushBack(node(pos, level, lt))
+		}
+	}
+	for removal.Len() != 0 {
+		a.remove(removal, addition)
+	}
+	for _, pos := range positions {
+		if level := a.source(pos, lt); level > 0 && a.light(pos, lt) < level {
+			a.setLight(pos, lt, level)
+			addition.PushBack(node(pos, level, lt))
+		}
+		// A changed position without light, such as an opaque block that was broken, is not found by remove, so
+		// the light of its neighbours is spread again to make sure it reaches the position.
+		for _, neighbour := range a.neighbours(node(pos, 0, lt)) {
+			if level := a.light(neighbour.pos, lt); level > 0 {
+				addition.PushBack(node(neighbour.pos, level, lt))
+			}
+		}
+	}
+	for addition.Len() != 0 {
+		a.spreadNode(addition)
+	}
+}
+
+// remove removes the light of the next light node in the removal queue from the neighbours of the node, if the
+// light of those neighbours originated from the node. Neighbours with light from another origin are added to the
+// addition queue, so that their light may be spread into the area where light was removed.
+func (a *lightArea) remove(removal, addition *list.List) {
+	n := removal.Remove(removal.Front()).(lightNode)
+	for _, neighbour := range a.neighbours(n) {
+		level := a.light(neighbour.pos, n.lt)
+		if level == 0 {
+			continue
+		}
+		// Skylight travels down without losing any of its strength, so a neighbour below with full skylight must
+		// have gotten it from this node.
+		straightDown := n.lt == SkyLight && n.level == 15 && level == 15 && neighbour.pos[1] == n.pos[1]-1
+		if level < n.level || straightDown {
+			a.setLight(neighbour.pos, n.lt, 0)
+			removal.PushBack(node(neighbour.pos, level, n.lt))
+			continue
+		}
+		addition.PushBack(node(neighbour.pos, level, n.lt))
+	}
+}
+
+// spreadNode spreads the light of the next light node in the addition queue to its neighbours. Unlike propagate,
+// the light of a node is set when it is added to the queue, so that nodes that already have the correct light
+// level, such as those added by remove, may be spread from as well.
+func (a *lightArea) spreadNode(addition *list.List) {
+	n := addition.Remove(addition.Front()).(lightNode)
+	if a.light(n.pos, n.lt) != n.level {
+		// The light of this node was changed after it was added, so another node will take care of it.
+		return
+	}
+	for _, neighbour := range a.neighbours(n) {
+		filter := a.highest(neighbour.pos, FilteringBlocks)
+		var level uint8
+		switch {
+		case n.lt == SkyLight && n.level == 15 && filter == 0 && neighbour.pos[1] == n.pos[1]-1:
+			level = 15
+		case n.level > filter+1:
+			level = n.level - filter - 1
+		default:
+			continue
+		}
+		if a.light(neighbour.pos, n.lt) < level {
+			a.setLight(neighbour.pos, n.lt, level)
+			addition.PushBack(node(neighbour.pos, level, n.lt))
+		}
+	}
+}
+
+// source returns the light level of the light type passed that a block at a position emits by itself. For block
+// light, this is the light emitted by the block. For skylight, this is 15 for transparent blocks at the top of
+// the world, and 0 for any other block.
+func (a *lightArea) source(pos cube.Pos, lt light) uint8 {
+	if lt == BlockLight {
+		return a.highest(pos, LightBlocks)
+	}
+	if pos[1] == a.r.Max() && a.highest(pos, FilteringBlocks) == 0 {
+		return 15
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package world
+
+import (
+	"container/list"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"sync"
+	"sync/atomic"
+)
+
+// columnVersion is the last version assigned to a Column. Every Column added to a World and every change to a
+// Column is assigned a new, unique version, so that cached data of a Column can be checked for staleness.
+var columnVersion atomic.Uint64
+
+// markModified marks the Column as modified, so that it is saved when unloaded and so that the network encoded
+// sub chunks cached for it are no longer used. The Column must be locked when calling markModified.
+func (c *Column) markModified() {
+	c.modified = true
+	c.version = columnVersion.Add(1)
+}
+
+// chunkCache is a least recently used cache of network encoded sub chunks. Players joining a World generally
+// request the same sub chunks around the spawn, so caching their encoded form saves re-encoding them for every
+// player. Entries are never invalidated explicitly: An entry is only used if the version of its Column has not
+// changed since it was added.
+type chunkCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[subChunkKey]*list.Element
+	order    *list.List
+}
+
+// subChunkKey identifies a single sub chunk in a chunkCache.
+type subChunkKey struct {
+	pos   ChunkPos
+	index int16
+}
+
+// cachedSubChunk is an entry of a chunkCache.
+type cachedSubChunk struct {
+	key     subChunkKey
+	version uint64
+	data    []byte
+}
+
+// newChunkCache returns a chunkCache holding up to capacity sub chunks. If capacity is 0 or lower, nothing is
+// cached.
+func newChunkCache(capacity int) *chunkCache {
+	return &chunkCache{capacity: capacity, entries: make(map[subChunkKey]*list.Element), order: list.New()}
+}
+
+// get returns the cached sub chunk with the key passed if it was cached with the version passed.
+func (cache *chunkCache) get(k subChunkKey, version uint64) ([]byte, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	e, ok := cache.entries[k]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*cachedSubChunk)
+	if entry.version != version {
+		cache.order.Remove(e)
+		delete(cache.entries, k)
+		return nil, false
+	}
+	cache.order.MoveToFront(e)
+	return entry.data, true
+}
+
+// put adds a sub chunk to the cache, evicting the least recently used sub chunk if the cache is full.
+func (cache *chunkCache) put(k subChunkKey, version uint64, data []byte) {
+	if cache.capacity <= 0 {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if e, ok := cache.entries[k]; ok {
+		e.Value = &cachedSubChunk{key: k, version: version, data: data}
+		cache.order.MoveToFront(e)
+		return
+	}
+	cache.entries[k] = cache.order.PushFront(&cachedSubChunk{key: k, version: version, data: data})
+	if cache.order.Len() > cache.capacity {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cachedSubChunk).key)
+	}
+}
+
+// NetworkSubChunk returns the network encoded sub chunk with the index passed of the Column at the position
+// passed. Encoded sub chunks are cached by the World until the Column is changed, so that the same sub chunk
+// is not encoded again for every viewer. The Column must be locked when calling NetworkSubChunk and must not
+// be modified by the caller. The data returned must not be modified.
+func (w *World) NetworkSubChunk(pos ChunkPos, col *Column, index int16) []byte {
+	k := subChunkKey{pos: pos, index: index}
+	if col.version != 0 {
+		if data, ok := w.chunkCache.get(k, col.version); ok {
+			return data
+		}
+	}
+	data := chunk.EncodeSubChunk(col.Chunk, chunk.NetworkEncoding, int(index))
+	if col.version != 0 {
+		w.chunkCache.put(k, col.version, data)
+	}
+	return data
+}
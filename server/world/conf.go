@@ -2,17 +2,19 @@ package world
 
 import (
 	"github.com/df-mc/atomic"
-	"github.com/df-mc/dragonfly/server/block/cube"
-	"github.com/sirupsen/logrus"
+	"github.com/df-mc/dragonfly/server/logging"
+	"log/slog"
 	"math/rand"
+	"runtime"
 	"time"
 )
 
 // Config may be used to create a new World. It holds a variety of fields that influence the World.
 type Config struct {
-	// Log is the Logger that will be used to log errors and debug messages to. If set to nil, a Logrus logger will be
-	// used.
-	Log Logger
+	// Log is the Logger that will be used to log errors and debug messages to. If set to nil, slog.Default() is used.
+	// Errors that occur while loading or generating chunks are logged with the logging.ChunkGen subsystem and those of
+	// entities with the logging.Entities subsystem.
+	Log *slog.Logger
 	// Dim is the Dimension of the World. If set to nil, the World will use Overworld as its dimension. The dimension
 	// set here influences, among others, the sky colour, weather/time and liquid behaviour in that World.
 	Dim Dimension
@@ -24,13 +26,16 @@ type Config struct {
 	// be NopProvider, which does not store any data to disk.
 	Provider Provider
 	// Generator is the Generator implementation used to generate new areas of the World. If set to nil, the Generator
-	// used will be NopGenerator, which generates completely empty chunks.
+	// used will be the Generator of Dim if it is a *CustomDimension, or NopGenerator, which generates completely empty
+	// chunks, otherwise.
 	Generator Generator
 	// ReadOnly specifies if the World should be read-only, meaning no new data will be written to the Provider.
 	ReadOnly bool
 	// RandomTickSpeed specifies the rate at which blocks should be ticked in the World. By default, each sub chunk has
 	// 3 blocks randomly ticked per sub chunk, so the default value is 3. Setting this value to -1 or lower will stop
-	// random ticking altogether, while setting it higher results in faster ticking.
+	// random ticking altogether, while setting it higher results in faster ticking. If left as 0, the value of the
+	// GameRuleRandomTickSpeed game rule of the World is used. The speed may be changed after the World is created
+	// using World.SetRandomTickSpeed.
 	RandomTickSpeed int
 	// RandSource is the rand.Source used for generation of random numbers in a World, such as when selecting blocks to
 	// tick or when deciding where to strike lightning. If set to nil, `rand.NewSource(time.Now().Unix())` will be used
@@ -39,20 +44,20 @@ type Config struct {
 	// Entities is an EntityRegistry with all entity types registered that may
 	// be added to the World.
 	Entities EntityRegistry
-}
-
-// Logger is a logger implementation that may be passed to the Log field of Config. World will send errors and debug
-// messages to this Logger when appropriate.
-type Logger interface {
-	Errorf(format string, a ...any)
-	Debugf(format string, a ...any)
+	// GenerationWorkers is the amount of goroutines that load and generate chunks in the background. If left as 0,
+	// runtime.NumCPU() workers are used.
+	GenerationWorkers int
+	// ChunkCacheSize is the maximum amount of network encoded sub chunks cached by the World, so that sub
+	// chunks sent to many players are only encoded once. If left as 0, up to 8192 sub chunks are cached. If set
+	// to a negative value, no sub chunks are cached.
+	ChunkCacheSize int
 }
 
 // New creates a new World using the Config conf. The World returned will start ticking as soon as a viewer is added
 // to it and is otherwise ready for use.
 func (conf Config) New() *World {
 	if conf.Log == nil {
-		conf.Log = logrus.New()
+		conf.Log = slog.Default()
 	}
 	if conf.Dim == nil {
 		conf.Dim = Overworld
@@ -60,32 +65,51 @@ func (conf Config) New() *World {
 	if conf.Provider == nil {
 		conf.Provider = NopProvider{}
 	}
+	if c, ok := conf.Dim.(*CustomDimension); ok && conf.Generator == nil {
+		conf.Generator = c.Generator
+	}
 	if conf.Generator == nil {
 		conf.Generator = NopGenerator{}
 	}
-	if conf.RandomTickSpeed == 0 {
-		conf.RandomTickSpeed = 3
+	if conf.ChunkCacheSize == 0 {
+		conf.ChunkCacheSize = 8192
+	}
+	if conf.GenerationWorkers <= 0 {
+		conf.GenerationWorkers = runtime.NumCPU()
 	}
 	if conf.RandSource == nil {
 		conf.RandSource = rand.NewSource(time.Now().Unix())
 	}
 	s := conf.Provider.Settings()
 	w := &World{
-		scheduledUpdates: make(map[cube.Pos]int64),
-		entities:         make(map[Entity]ChunkPos),
-		viewers:          make(map[*Loader]Viewer),
-		chunks:           make(map[ChunkPos]*Column),
-		closing:          make(chan struct{}),
-		handler:          *atomic.NewValue[Handler](NopHandler{}),
-		r:                rand.New(conf.RandSource),
-		advance:          s.ref.Inc() == 1,
-		conf:             conf,
-		ra:               conf.Dim.Range(),
-		set:              s,
+		scheduledUpdates:       newScheduledUpdates(),
+		queuedNeighbourUpdates: make(map[neighbourUpdate]struct{}),
+		entities:               make(map[Entity]ChunkPos),
+		viewers:                make(map[*Loader]Viewer),
+		chunks:                 make(map[ChunkPos]*Column),
+		structures:             make(map[string]*StructureTemplate),
+		border:                 newBorder(),
+		light:                  newLightQueue(),
+		gen:                    newGenerationPool(),
+		genLog:                 logging.With(conf.Log, logging.ChunkGen),
+		tickets:                newTickets(),
+		chunkCache:             newChunkCache(conf.ChunkCacheSize),
+		closing:                make(chan struct{}),
+		handler:                *atomic.NewValue[Handler](NopHandler{}),
+		r:                      rand.New(conf.RandSource),
+		advance:                s.ref.Inc() == 1,
+		conf:                   conf,
+		ra:                     conf.Dim.Range(),
+		set:                    s,
 	}
 	w.weather, w.ticker = weather{w: w}, ticker{w: w}
+	w.randomTickSpeed.Store(int64(conf.RandomTickSpeed))
 
 	go w.tickLoop()
+	go w.lightWorker()
+	for i := 0; i < conf.GenerationWorkers; i++ {
+		go w.generationWorker()
+	}
 	go w.chunkCacheJanitor()
 	return w
 }
@@ -1,6 +1,7 @@
 package world
 
 import (
+	"fmt"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"math"
 	"time"
@@ -38,6 +39,30 @@ func DimensionID(dim Dimension) (int, bool) {
 	return dimensionReg.LookupID(dim)
 }
 
+// RegisterDimension registers a Dimension under the ID passed, so that worlds
+// of the Dimension may be saved and loaded and players may be moved to them.
+// The IDs 0, 1 and 2 are taken by Overworld, Nether and End. RegisterDimension
+// panics if the ID or Dimension passed is already registered, or if the
+// Dimension is a *CustomDimension with an invalid Height. Dimensions must be
+// registered before any World of the Dimension is created.
+func RegisterDimension(id int, dim Dimension) {
+	if c, ok := dim.(*CustomDimension); ok {
+		c.validate()
+	}
+	dimensionReg.register(id, dim)
+}
+
+// NetworkDimensionID returns the ID of the Dimension as it is known by the
+// client. The client only knows Overworld, Nether and End, so a
+// *CustomDimension is sent as the Dimension set as its Sky.
+func NetworkDimensionID(dim Dimension) int {
+	if c, ok := dim.(*CustomDimension); ok {
+		dim = c.Sky
+	}
+	id, _ := DimensionID(dim)
+	return id
+}
+
 type dimensionRegistry struct {
 	dimensions map[int]Dimension
 	ids        map[Dimension]int
@@ -63,6 +88,18 @@ func (reg *dimensionRegistry) Lookup(id int) (Dimension, bool) {
 	return dim, ok
 }
 
+// register registers the Dimension passed under the ID passed. It panics if
+// the ID or the Dimension is already registered.
+func (reg *dimensionRegistry) register(id int, dim Dimension) {
+	if _, ok := reg.dimensions[id]; ok {
+		panic(fmt.Sprintf("cannot register dimension %v: ID %v is already registered", dim, id))
+	}
+	if _, ok := reg.ids[dim]; ok {
+		panic(fmt.Sprintf("cannot register dimension %v twice", dim))
+	}
+	reg.dimensions[id], reg.ids[dim] = dim, id
+}
+
 // LookupID looks up the ID that a Dimension was registered with. If not found,
 // false is returned.
 func (reg *dimensionRegistry) LookupID(dim Dimension) (int, bool) {
@@ -116,3 +153,62 @@ func (nopDim) LavaSpreadDuration() time.Duration { return math.MaxInt64 }
 func (nopDim) WeatherCycle() bool                { return false }
 func (nopDim) TimeCycle() bool                   { return false }
 func (nopDim) String() string                    { return "" }
+
+// CustomDimension is a Dimension with properties chosen by the user. It may be
+// registered using RegisterDimension to add dimensions beyond the Overworld,
+// Nether and End, such as a mining or event dimension. CustomDimensions must
+// be used as a pointer.
+type CustomDimension struct {
+	// Name is the name of the dimension, returned by String.
+	Name string
+	// Height is the building range of the dimension. Its minimum must be
+	// equal to that of Sky and its maximum must be aligned to sub chunks and
+	// may not exceed that of Sky, as the client only renders blocks within
+	// the building range of Sky.
+	Height cube.Range
+	// Sky is the vanilla Dimension (Overworld, Nether or End) that the client
+	// is told it is in when in the dimension. It determines the sky, fog and
+	// ambient light rendered by the client. If nil, Overworld is used.
+	Sky Dimension
+	// Generator is the Generator used for worlds of the dimension for which
+	// no Generator is set in their Config.
+	Generator Generator
+	// EvaporatesWater specifies if water placed in the dimension evaporates,
+	// like it does in the Nether.
+	EvaporatesWater bool
+	// LavaSpread is the time it takes for lava to spread by one block. If 0,
+	// the duration of the Overworld is used.
+	LavaSpread time.Duration
+	// Weather and DayCycle specify if the dimension has a weather and time
+	// cycle respectively.
+	Weather, DayCycle bool
+}
+
+// validate checks if the CustomDimension is valid, filling out default values
+// for its unset fields. It panics if the Height of the CustomDimension is not
+// valid.
+func (c *CustomDimension) validate() {
+	if c.Sky == nil {
+		c.Sky = Overworld
+	}
+	if c.Sky != Overworld && c.Sky != Nether && c.Sky != End {
+		panic(fmt.Sprintf("custom dimension %v: sky must be the Overworld, Nether or End", c.Name))
+	}
+	r, sky := c.Height, c.Sky.Range()
+	if (r.Max()+1)&15 != 0 || r.Max() < r.Min() {
+		panic(fmt.Sprintf("custom dimension %v: height %v is not aligned to sub chunks", c.Name, r))
+	}
+	if r.Min() != sky.Min() || r.Max() > sky.Max() {
+		panic(fmt.Sprintf("custom dimension %v: height %v does not fit height %v of %v", c.Name, r, sky, c.Sky))
+	}
+	if c.LavaSpread == 0 {
+		c.LavaSpread = Overworld.LavaSpreadDuration()
+	}
+}
+
+func (c *CustomDimension) Range() cube.Range                 { return c.Height }
+func (c *CustomDimension) WaterEvaporates() bool             { return c.EvaporatesWater }
+func (c *CustomDimension) LavaSpreadDuration() time.Duration { return c.LavaSpread }
+func (c *CustomDimension) WeatherCycle() bool                { return c.Weather }
+func (c *CustomDimension) TimeCycle() bool                   { return c.DayCycle }
+func (c *CustomDimension) String() string                    { return c.Name }
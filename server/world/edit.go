@@ -0,0 +1,160 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"slices"
+	"sync"
+)
+
+// Edit is a batch of block changes to a World. Changes made to an Edit are not visible in the World until
+// Commit is called, after which they are applied one chunk at a time. Unlike World.SetBlock, an Edit does not
+// update neighbouring blocks, does not displace liquids and does not recalculate light for every block changed.
+// Instead, the light of every chunk changed is recalculated once and viewers are sent every changed chunk once,
+// which makes an Edit suitable for changing millions of blocks at once.
+//
+// An Edit is created using World.BeginEdit. It is safe for concurrent use.
+type Edit struct {
+	w *World
+
+	mu      sync.Mutex
+	n       int
+	changes map[ChunkPos]*chunkEdit
+}
+
+// chunkEdit holds the pending changes of an Edit within a single chunk.
+type chunkEdit struct {
+	// blocks holds the blocks set in the chunk in the order they were set, so that later changes to the same
+	// position override earlier ones.
+	blocks []editBlock
+	// blockEntities holds the blocks set in the chunk that have a block entity, by their position.
+	blockEntities map[cube.Pos]Block
+}
+
+// editBlock is a single block set in an Edit, holding the position of the block relative to its chunk.
+type editBlock struct {
+	x, z uint8
+	y    int16
+	rid  uint32
+}
+
+// BeginEdit creates a new Edit that may be used to change many blocks of the World at once. None of the
+// changes made are applied to the World until Edit.Commit is called.
+func (w *World) BeginEdit() *Edit {
+	return &Edit{w: w, changes: make(map[ChunkPos]*chunkEdit)}
+}
+
+// SetBlock sets the block at the position passed once the Edit is committed. Any liquid present in the second
+// layer at the position is removed. Positions outside the range of the World are ignored.
+func (e *Edit) SetBlock(pos cube.Pos, b Block) {
+	if e.w == nil || pos.OutOfBounds(e.w.Range()) {
+		return
+	}
+	if b == nil {
+		b = air()
+	}
+	rid := BlockRuntimeID(b)
+	chunkPos := chunkPosFromBlockPos(pos)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.changes[chunkPos]
+	if !ok {
+		c = &chunkEdit{}
+		e.changes[chunkPos] = c
+	}
+	c.blocks = append(c.blocks, editBlock{x: uint8(pos[0]), y: int16(pos[1]), z: uint8(pos[2]), rid: rid})
+	if nbtBlocks[rid] {
+		if c.blockEntities == nil {
+			c.blockEntities = make(map[cube.Pos]Block)
+		}
+		c.blockEntities[pos] = withBlockEntity(b)
+	}
+	e.n++
+}
+
+// Fill sets all blocks within the box spanned by the positions a and b passed, inclusive, to the block
+// passed once the Edit is committed.
+func (e *Edit) Fill(a, b cube.Pos, bl Block) {
+	if e.w == nil {
+		return
+	}
+	r := e.w.Range()
+	minX, maxX := min(a[0], b[0]), max(a[0], b[0])
+	minY, maxY := max(min(a[1], b[1]), r[0]), min(max(a[1], b[1]), r[1])
+	minZ, maxZ := min(a[2], b[2]), max(a[2], b[2])
+	for x := minX; x <= maxX; x++ {
+		for z := minZ; z <= maxZ; z++ {
+			for y := minY; y <= maxY; y++ {
+				e.SetBlock(cube.Pos{x, y, z}, bl)
+			}
+		}
+	}
+}
+
+// Len returns the number of block changes held by the Edit that were not yet committed.
+func (e *Edit) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.n
+}
+
+// Chunks returns the positions of all chunks changed by the Edit that were not yet committed.
+func (e *Edit) Chunks() []ChunkPos {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	positions := make([]ChunkPos, 0, len(e.changes))
+	for pos := range e.changes {
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
+// Discard discards all changes held by the Edit without applying them to the World.
+func (e *Edit) Discard() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.changes, e.n = make(map[ChunkPos]*chunkEdit), 0
+}
+
+// Commit applies all changes held by the Edit to the World. Chunks that are not loaded are loaded or generated
+// first. Every chunk changed is sent to its viewers once and its light is recalculated in the background. No
+// block updates are done for the blocks changed. After Commit returns, the Edit is empty and may be reused.
+func (e *Edit) Commit() {
+	e.mu.Lock()
+	changes := e.changes
+	e.changes, e.n = make(map[ChunkPos]*chunkEdit), 0
+	e.mu.Unlock()
+
+	if e.w == nil {
+		return
+	}
+	for pos, edit := range changes {
+		e.w.commitChunkEdit(pos, edit)
+	}
+}
+
+// commitChunkEdit applies the changes of a chunkEdit to the chunk at the position passed and sends the chunk
+// to its viewers.
+func (w *World) commitChunkEdit(pos ChunkPos, edit *chunkEdit) {
+	c := w.chunk(pos)
+	base := cube.Pos{int(pos[0]) << 4, 0, int(pos[1]) << 4}
+	for _, b := range edit.blocks {
+		c.SetBlock(b.x, b.y, b.z, 0, b.rid)
+		c.SetBlock(b.x, b.y, b.z, 1, airRID)
+
+		blockPos := cube.Pos{base[0] + int(b.x), int(b.y), base[2] + int(b.z)}
+		if nbtBlocks[b.rid] {
+			c.BlockEntities[blockPos] = edit.blockEntities[blockPos]
+		} else {
+			delete(c.BlockEntities, blockPos)
+		}
+	}
+	c.markModified()
+	w.light.addChunk(pos)
+
+	viewers := slices.Clone(c.viewers)
+	for _, viewer := range viewers {
+		viewer.ViewChunk(pos, c.Chunk, c.BlockEntities)
+	}
+	c.Unlock()
+}
@@ -106,11 +106,15 @@ type EntityRegistryConfig struct {
 	Arrow              func(pos, vel mgl64.Vec3, rot cube.Rotation, damage float64, owner Entity, critical, disallowPickup, obtainArrowOnPickup bool, punchLevel int, tip any) Entity
 	Egg                func(pos, vel mgl64.Vec3, owner Entity) Entity
 	EnderPearl         func(pos, vel mgl64.Vec3, owner Entity) Entity
+	EyeOfEnder         func(pos, target mgl64.Vec3) Entity
 	Firework           func(pos mgl64.Vec3, rot cube.Rotation, attached bool, firework Item, owner Entity) Entity
 	LingeringPotion    func(pos, vel mgl64.Vec3, t any, owner Entity) Entity
 	Snowball           func(pos, vel mgl64.Vec3, owner Entity) Entity
 	SplashPotion       func(pos, vel mgl64.Vec3, t any, owner Entity) Entity
 	Lightning          func(pos mgl64.Vec3) Entity
+	Wither             func(pos mgl64.Vec3) Entity
+	EnderCrystal       func(pos mgl64.Vec3, showBase bool) Entity
+	LeashKnot          func(pos cube.Pos) Entity
 }
 
 // New creates an EntityRegistry using conf and the EntityTypes passed.
@@ -0,0 +1,61 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"math"
+)
+
+// entityGridCellSize is the size in blocks of the cells of an entityGrid.
+const entityGridCellSize = 4
+
+// entityGrid is a spatial index of entities. It divides the world into cubic
+// cells of entityGridCellSize blocks so that entities close to a position may
+// be found without iterating over all entities in the chunks around it.
+type entityGrid struct {
+	cells map[[3]int][]Entity
+}
+
+// newEntityGrid creates an entityGrid holding the entities passed, indexed by
+// their current position.
+func newEntityGrid[E Entity](entities []E) *entityGrid {
+	g := &entityGrid{cells: make(map[[3]int][]Entity, len(entities))}
+	for _, e := range entities {
+		cell := entityGridCell(e.Position()[0], e.Position()[1], e.Position()[2])
+		g.cells[cell] = append(g.cells[cell], e)
+	}
+	return g
+}
+
+// within returns all entities in the entityGrid that are currently positioned
+// within the box passed.
+func (g *entityGrid) within(box cube.BBox, ignored func(Entity) bool) []Entity {
+	// Entities may have moved slightly since the entityGrid was created, so we
+	// also look at the cells directly surrounding the box.
+	minCell := entityGridCell(box.Min()[0]-1, box.Min()[1]-1, box.Min()[2]-1)
+	maxCell := entityGridCell(box.Max()[0]+1, box.Max()[1]+1, box.Max()[2]+1)
+
+	var m []Entity
+	for x := minCell[0]; x <= maxCell[0]; x++ {
+		for y := minCell[1]; y <= maxCell[1]; y++ {
+			for z := minCell[2]; z <= maxCell[2]; z++ {
+				for _, e := range g.cells[[3]int{x, y, z}] {
+					if !box.Vec3Within(e.Position()) || (ignored != nil && ignored(e)) {
+						continue
+					}
+					m = append(m, e)
+				}
+			}
+		}
+	}
+	return m
+}
+
+// entityGridCell returns the cell of an entityGrid that the coordinates passed
+// are in.
+func entityGridCell(x, y, z float64) [3]int {
+	return [3]int{
+		int(math.Floor(x / entityGridCellSize)),
+		int(math.Floor(y / entityGridCellSize)),
+		int(math.Floor(z / entityGridCellSize)),
+	}
+}
@@ -0,0 +1,61 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// GameEvent is an event that happens in a world and that may be perceived by
+// entities nearby, such as a note block being played. Wardens perceive
+// GameEvents as vibrations. GameEvents are emitted using World.EmitGameEvent.
+type GameEvent int
+
+const (
+	// GameEventNoteBlockPlay is emitted when a note block is played.
+	GameEventNoteBlockPlay GameEvent = iota
+	// GameEventJukeboxPlay is emitted when a jukebox starts playing a music
+	// disc.
+	GameEventJukeboxPlay
+	// GameEventJukeboxStopPlay is emitted when a jukebox stops playing a
+	// music disc.
+	GameEventJukeboxStopPlay
+	// GameEventStep is emitted when an entity takes a step on the ground
+	// without sneaking.
+	GameEventStep
+	// GameEventBlockPlace is emitted when a block is placed.
+	GameEventBlockPlace
+	// GameEventBlockDestroy is emitted when a block is broken.
+	GameEventBlockDestroy
+	// GameEventProjectileLand is emitted when a projectile hits a block or
+	// an entity.
+	GameEventProjectileLand
+	// GameEventEntityDamage is emitted when an entity takes damage. The source
+	// of the GameEvent is the entity that dealt the damage, if any.
+	GameEventEntityDamage
+)
+
+// gameEventRange is the range in blocks within which GameEventListeners are
+// notified of a GameEvent.
+const gameEventRange = 16
+
+// GameEventListener is an Entity that listens to GameEvents emitted near it.
+type GameEventListener interface {
+	Entity
+	// HandleGameEvent is called when a GameEvent is emitted at a position
+	// within 16 blocks of the GameEventListener. src is the Entity that
+	// caused the GameEvent, or nil if it was not caused by an Entity.
+	HandleGameEvent(e GameEvent, pos mgl64.Vec3, src Entity)
+}
+
+// EmitGameEvent emits a GameEvent at the position passed, notifying all
+// GameEventListeners within 16 blocks of it. src is the Entity that caused the
+// GameEvent and may be nil.
+func (w *World) EmitGameEvent(e GameEvent, pos mgl64.Vec3, src Entity) {
+	box := cube.Box(-gameEventRange, -gameEventRange, -gameEventRange, gameEventRange, gameEventRange, gameEventRange).Translate(pos)
+	for _, ent := range w.EntitiesWithin(box, func(ent Entity) bool {
+		_, ok := ent.(GameEventListener)
+		return !ok || ent.Position().Sub(pos).Len() > gameEventRange
+	}) {
+		ent.(GameEventListener).HandleGameEvent(e, pos, src)
+	}
+}
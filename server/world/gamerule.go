@@ -0,0 +1,281 @@
+package world
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GameRule is a rule of a World that changes the behaviour of one of its systems. GameRule is implemented by
+// BoolGameRule and IntGameRule, which hold values of the type matching their name. The values of game rules
+// are stored in the Settings of a World.
+type GameRule interface {
+	// Name returns the name of the GameRule, such as 'keepInventory'.
+	Name() string
+	// Default returns the value that the GameRule has if it was never changed.
+	Default() any
+	// Parse parses a value of the GameRule from a string, such as one passed to the /gamerule command.
+	Parse(s string) (any, error)
+
+	value(s *Settings) any
+	set(s *Settings, v any) bool
+}
+
+// BoolGameRule is a GameRule with a bool value.
+type BoolGameRule struct {
+	name string
+	def  bool
+	// field returns the field in the Settings that holds the value of the rule. If nil, the value is stored in
+	// Settings.GameRules.
+	field func(s *Settings) *bool
+}
+
+// Name ...
+func (r BoolGameRule) Name() string {
+	return r.name
+}
+
+// Default ...
+func (r BoolGameRule) Default() any {
+	return r.def
+}
+
+// Parse ...
+func (r BoolGameRule) Parse(s string) (any, error) {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %v for game rule %v: expected true or false", s, r.name)
+	}
+	return v, nil
+}
+
+// Value returns the value of the BoolGameRule in the World passed.
+func (r BoolGameRule) Value(w *World) bool {
+	if w == nil {
+		return r.def
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return r.value(w.set).(bool)
+}
+
+// Set changes the value of the BoolGameRule in the World passed.
+func (r BoolGameRule) Set(w *World, v bool) {
+	if w == nil {
+		return
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	r.set(w.set, v)
+}
+
+// value ...
+func (r BoolGameRule) value(s *Settings) any {
+	if r.field != nil {
+		return *r.field(s)
+	}
+	if v, ok := s.GameRules[r.name].(bool); ok {
+		return v
+	}
+	return r.def
+}
+
+// set ...
+func (r BoolGameRule) set(s *Settings, v any) bool {
+	b, ok := v.(bool)
+	if !ok {
+		return false
+	}
+	if r.field != nil {
+		*r.field(s) = b
+		return true
+	}
+	if s.GameRules == nil {
+		s.GameRules = make(map[string]any)
+	}
+	s.GameRules[r.name] = b
+	return true
+}
+
+// IntGameRule is a GameRule with an int value.
+type IntGameRule struct {
+	name string
+	def  int
+}
+
+// Name ...
+func (r IntGameRule) Name() string {
+	return r.name
+}
+
+// Default ...
+func (r IntGameRule) Default() any {
+	return r.def
+}
+
+// Parse ...
+func (r IntGameRule) Parse(s string) (any, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %v for game rule %v: expected an integer", s, r.name)
+	}
+	return v, nil
+}
+
+// Value returns the value of the IntGameRule in the World passed.
+func (r IntGameRule) Value(w *World) int {
+	if w == nil {
+		return r.def
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return r.value(w.set).(int)
+}
+
+// Set changes the value of the IntGameRule in the World passed.
+func (r IntGameRule) Set(w *World, v int) {
+	if w == nil {
+		return
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	r.set(w.set, v)
+}
+
+// value ...
+func (r IntGameRule) value(s *Settings) any {
+	if v, ok := s.GameRules[r.name].(int); ok {
+		return v
+	}
+	return r.def
+}
+
+// set ...
+func (r IntGameRule) set(s *Settings, v any) bool {
+	i, ok := v.(int)
+	if !ok {
+		return false
+	}
+	if s.GameRules == nil {
+		s.GameRules = make(map[string]any)
+	}
+	s.GameRules[r.name] = i
+	return true
+}
+
+var (
+	// GameRuleDoDaylightCycle specifies if the time of a World advances. It is the same as Settings.TimeCycle.
+	GameRuleDoDaylightCycle = BoolGameRule{name: "doDaylightCycle", def: true, field: func(s *Settings) *bool { return &s.TimeCycle }}
+	// GameRuleDoWeatherCycle specifies if the weather of a World changes. It is the same as
+	// Settings.WeatherCycle.
+	GameRuleDoWeatherCycle = BoolGameRule{name: "doWeatherCycle", def: true, field: func(s *Settings) *bool { return &s.WeatherCycle }}
+	// GameRuleDoFireTick specifies if fire spreads and burns out.
+	GameRuleDoFireTick = BoolGameRule{name: "doFireTick", def: true}
+	// GameRuleDoMobSpawning specifies if entities spawn naturally in biomes implementing SpawningBiome.
+	GameRuleDoMobSpawning = BoolGameRule{name: "doMobSpawning", def: true}
+	// GameRuleDoMobLoot specifies if mobs drop items when they are killed.
+	GameRuleDoMobLoot = BoolGameRule{name: "doMobLoot", def: true}
+	// GameRuleDoTileDrops specifies if blocks drop items and experience when broken by players.
+	GameRuleDoTileDrops = BoolGameRule{name: "doTileDrops", def: true}
+	// GameRuleDrowningDamage specifies if players take damage from drowning.
+	GameRuleDrowningDamage = BoolGameRule{name: "drowningDamage", def: true}
+	// GameRuleFallDamage specifies if players take damage from falling.
+	GameRuleFallDamage = BoolGameRule{name: "fallDamage", def: true}
+	// GameRuleFireDamage specifies if players take damage from fire and lava.
+	GameRuleFireDamage = BoolGameRule{name: "fireDamage", def: true}
+	// GameRuleFreezeDamage specifies if players take damage from being frozen in powder snow.
+	GameRuleFreezeDamage = BoolGameRule{name: "freezeDamage", def: true}
+	// GameRuleHungerDrain specifies if the food bar of players is drained by exhaustion, such as by sprinting
+	// or jumping.
+	GameRuleHungerDrain = BoolGameRule{name: "hungerDrain", def: true}
+	// GameRuleKeepInventory specifies if players keep their items and experience when they die.
+	GameRuleKeepInventory = BoolGameRule{name: "keepInventory"}
+	// GameRuleMobGriefing specifies if mobs are able to change blocks in the world.
+	GameRuleMobGriefing = BoolGameRule{name: "mobGriefing", def: true}
+	// GameRuleNaturalRegeneration specifies if players regenerate health when their food bar is full enough.
+	GameRuleNaturalRegeneration = BoolGameRule{name: "naturalRegeneration", def: true}
+	// GameRulePVP specifies if players are able to attack each other.
+	GameRulePVP = BoolGameRule{name: "pvp", def: true}
+	// GameRuleShowDeathMessages specifies if a message is broadcast to all players when a player dies.
+	GameRuleShowDeathMessages = BoolGameRule{name: "showDeathMessages", def: true}
+	// GameRuleTNTExplodes specifies if TNT can be ignited.
+	GameRuleTNTExplodes = BoolGameRule{name: "tntExplodes", def: true}
+	// GameRuleRandomTickSpeed is the speed at which blocks are ticked randomly. Like in Bedrock Edition, it is
+	// 1 by default, which results in 3 blocks in each sub chunk being ticked every tick. If set to 0 or lower,
+	// blocks are not ticked randomly. If Config.RandomTickSpeed is not 0, it is used instead.
+	GameRuleRandomTickSpeed = IntGameRule{name: "randomTickSpeed", def: 1}
+	// GameRuleSpawnRadius is the radius in blocks around the spawn of a World within which players without a
+	// spawn position of their own are spawned. It is 0 by default, so that players spawn exactly at the spawn
+	// of the World.
+	GameRuleSpawnRadius = IntGameRule{name: "spawnRadius", def: 0}
+	// GameRuleSnowAccumulationHeight is the maximum number of snow layers that may accumulate on top of a block
+	// during snowfall. If set to 0 or lower, no snow accumulates at all.
+	GameRuleSnowAccumulationHeight = IntGameRule{name: "snowAccumulationHeight", def: 1}
+)
+
+// gameRules holds all game rules indexed by their lowercase name.
+var gameRules = map[string]GameRule{}
+
+func init() {
+	for _, r := range []GameRule{
+		GameRuleDoDaylightCycle, GameRuleDoWeatherCycle, GameRuleDoFireTick, GameRuleDoMobSpawning, GameRuleDoMobLoot,
+		GameRuleDoTileDrops, GameRuleDrowningDamage, GameRuleFallDamage, GameRuleFireDamage, GameRuleFreezeDamage,
+		GameRuleHungerDrain, GameRuleKeepInventory, GameRuleMobGriefing, GameRuleNaturalRegeneration, GameRulePVP,
+		GameRuleShowDeathMessages, GameRuleTNTExplodes, GameRuleRandomTickSpeed, GameRuleSpawnRadius,
+		GameRuleSnowAccumulationHeight,
+	} {
+		gameRules[strings.ToLower(r.Name())] = r
+	}
+}
+
+// GameRules returns all game rules that exist.
+func GameRules() []GameRule {
+	rules := make([]GameRule, 0, len(gameRules))
+	for _, r := range gameRules {
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// GameRuleByName looks up a GameRule by its name. The name is case-insensitive. If no GameRule with the name
+// exists, false is returned.
+func GameRuleByName(name string) (GameRule, bool) {
+	r, ok := gameRules[strings.ToLower(name)]
+	return r, ok
+}
+
+// GameRule returns the value of the GameRule passed in the World.
+func (w *World) GameRule(r GameRule) any {
+	if w == nil {
+		return r.Default()
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return r.value(w.set)
+}
+
+// SetGameRule changes the value of the GameRule passed in the World. An error is returned if the value is not
+// of the type of the GameRule.
+func (w *World) SetGameRule(r GameRule, v any) error {
+	if w == nil {
+		return nil
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	if !r.set(w.set, v) {
+		return fmt.Errorf("invalid value %v for game rule %v", v, r.Name())
+	}
+	return nil
+}
+
+// GameRule returns the value of the GameRule passed in the Settings. The Settings must be locked by the caller
+// if they are in use by a World.
+func (s *Settings) GameRule(r GameRule) any {
+	return r.value(s)
+}
+
+// SetGameRule changes the value of the GameRule passed in the Settings. The Settings must be locked by the
+// caller if they are in use by a World. False is returned if the value is not of the type of the GameRule.
+func (s *Settings) SetGameRule(r GameRule, v any) bool {
+	return r.set(s, v)
+}
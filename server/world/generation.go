@@ -0,0 +1,195 @@
+package world
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/metrics"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/goleveldb/leveldb"
+)
+
+// futureExpiry is the time after which a columnFuture that was not requested again is dropped from the
+// generationPool, whether its Column was prepared or not.
+const futureExpiry = time.Second * 30
+
+// columnFuture is the result of loading or generating a Column in the background. done is closed once col and
+// err are set.
+type columnFuture struct {
+	pos  ChunkPos
+	done chan struct{}
+
+	// requested is the last time at which the Column was requested, and urgent specifies if a caller is
+	// waiting for it. Both are protected by the mutex of the generationPool.
+	requested time.Time
+	urgent    bool
+
+	col *Column
+	err error
+}
+
+// ready checks if the Column of the columnFuture has been loaded or generated.
+func (f *columnFuture) ready() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// generationPool is a bounded pool of workers that load Columns from the Provider of a World and generate
+// them using its Generator if they do not exist. Columns are prepared outside the World until they are
+// finished, so that the ticking of the World is never stalled by new terrain being generated.
+type generationPool struct {
+	mu sync.Mutex
+	// futures holds the columnFutures of all Columns requested that were not yet added to the World, by the
+	// position of the Column.
+	futures map[ChunkPos]*columnFuture
+	// queue holds the columnFutures of which the Column is not yet being prepared by a worker.
+	queue []*columnFuture
+	// signal is sent a value when new columnFutures are added to queue.
+	signal chan struct{}
+}
+
+// newGenerationPool returns an empty generationPool.
+func newGenerationPool() *generationPool {
+	return &generationPool{futures: make(map[ChunkPos]*columnFuture), signal: make(chan struct{}, 1)}
+}
+
+// request returns the columnFuture of the Column at the position passed, queueing the Column to be prepared
+// if it was not requested before. If urgent is true, the Column is prepared before all Columns that were
+// queued earlier, as the caller is waiting for it.
+func (p *generationPool) request(pos ChunkPos, urgent bool) *columnFuture {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f, ok := p.futures[pos]; ok {
+		f.requested = time.Now()
+		if urgent {
+			f.urgent = true
+			p.prioritise(f)
+		}
+		return f
+	}
+	f := &columnFuture{pos: pos, done: make(chan struct{}), requested: time.Now(), urgent: urgent}
+	p.futures[pos] = f
+	if urgent {
+		p.queue = append([]*columnFuture{f}, p.queue...)
+	} else {
+		p.queue = append(p.queue, f)
+	}
+	p.wake()
+	return f
+}
+
+// prioritise moves the columnFuture passed to the front of the queue if it is still queued. p.mu must be held
+// when calling prioritise.
+func (p *generationPool) prioritise(f *columnFuture) {
+	for i, queued := range p.queue {
+		if queued == f {
+			copy(p.queue[1:i+1], p.queue[:i])
+			p.queue[0] = f
+			return
+		}
+	}
+}
+
+// remove removes the columnFuture at the position passed from the generationPool once its Column is added to
+// the World.
+func (p *generationPool) remove(pos ChunkPos) {
+	p.mu.Lock()
+	delete(p.futures, pos)
+	p.mu.Unlock()
+}
+
+// expire drops the columnFutures that were not requested within the futureExpiry, unless a caller is waiting
+// for them. This happens when the Loaders that requested a Column move away or are closed before the Column is
+// ready. Columns of dropped columnFutures that are still queued are not prepared at all.
+func (p *generationPool) expire(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for pos, f := range p.futures {
+		if !f.urgent && now.Sub(f.requested) >= futureExpiry {
+			delete(p.futures, pos)
+		}
+	}
+	p.queue = slices.DeleteFunc(p.queue, func(f *columnFuture) bool {
+		return p.futures[f.pos] != f
+	})
+}
+
+// take takes the next columnFuture from the queue, or returns nil if the queue is empty.
+func (p *generationPool) take() *columnFuture {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.queue) == 0 {
+		return nil
+	}
+	f := p.queue[0]
+	p.queue = p.queue[1:]
+	if len(p.queue) != 0 {
+		// Make sure other workers wake up to handle the remaining columns.
+		p.wake()
+	}
+	return f
+}
+
+// wake wakes up a worker if not all workers are already awake.
+func (p *generationPool) wake() {
+	select {
+	case p.signal <- struct{}{}:
+	default:
+	}
+}
+
+// generationWorker prepares the Columns requested from the generationPool of the World until the World is
+// closed.
+func (w *World) generationWorker() {
+	w.running.Add(1)
+	defer w.running.Done()
+	for {
+		select {
+		case <-w.closing:
+			return
+		case <-w.gen.signal:
+			for f := w.gen.take(); f != nil; f = w.gen.take() {
+				f.col, f.err = w.prepareColumn(f.pos)
+				close(f.done)
+			}
+		}
+	}
+}
+
+// prepareColumn loads the Column at the position passed from the Provider of the World, or generates it if
+// the Provider does not have it.
+func (w *World) prepareColumn(pos ChunkPos) (*Column, error) {
+	start := time.Now()
+	col, err := w.provider().LoadColumn(pos, w.conf.Dim)
+	metrics.ProviderDuration.Observe("load", time.Since(start).Seconds())
+	if err == nil {
+		return col, nil
+	} else if !errors.Is(err, leveldb.ErrNotFound) {
+		return nil, err
+	}
+	// The provider doesn't have a chunk saved at this position, so we generate a new one.
+	col = newColumn(chunk.New(airRID, w.Range()))
+	if g, ok := w.conf.Generator.(ColumnGenerator); ok {
+		g.GenerateColumn(pos, col)
+	} else {
+		w.conf.Generator.GenerateChunk(pos, col.Chunk)
+	}
+	return col, nil
+}
+
+// chunkReady checks if the chunk at the position passed may be obtained without waiting for it to be loaded or
+// generated. If not, the chunk is requested to be prepared in the background.
+func (w *World) chunkReady(pos ChunkPos) bool {
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
+	if _, ok := w.chunks[pos]; ok {
+		return true
+	}
+	return w.gen.request(pos, false).ready()
+}
@@ -1,6 +1,7 @@
 package world
 
 import (
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/world/chunk"
 )
 
@@ -12,6 +13,28 @@ type Generator interface {
 	GenerateChunk(pos ChunkPos, chunk *chunk.Chunk)
 }
 
+// ColumnGenerator is a Generator that, besides the blocks of a chunk, also
+// generates its block entities and entities, such as the chests and villagers
+// of generated structures. If the Generator of a World implements
+// ColumnGenerator, GenerateColumn is called instead of GenerateChunk.
+type ColumnGenerator interface {
+	Generator
+	// GenerateColumn generates the chunk of the Column passed at the chunk
+	// position passed. Block entities and entities generated are added to the
+	// BlockEntities and Entities of the Column.
+	GenerateColumn(pos ChunkPos, col *Column)
+}
+
+// StructureLocator is a Generator that is able to locate the structures that it
+// generates, such as strongholds.
+type StructureLocator interface {
+	Generator
+	// LocateStructure returns the position of the structure with the name
+	// passed that is nearest to the position passed. If the Generator does not
+	// generate structures with this name, false is returned.
+	LocateStructure(name string, pos cube.Pos) (cube.Pos, bool)
+}
+
 // NopGenerator is the default generator a world. It places no blocks in the world which results in a void
 // world.
 type NopGenerator struct{}
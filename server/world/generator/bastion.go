@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+// assembleBastion assembles a bastion starting at the position passed, which
+// is on the floor of a cavern. Bastions are built from a keep holding the
+// treasure of the bastion, surrounded by ramparts, stables and towers of
+// blackstone.
+func assembleBastion(pos cube.Pos, r cube.Range, rnd *rand.Rand) *structure {
+	start := newPiece(bastionKeep, rnd.Intn(4), rnd.Uint64())
+	start.x, start.y, start.z = pos[0]-start.sizeX/2, pos[1]-1, pos[2]-start.sizeZ/2
+
+	j := jigsaw{pools: bastionPools, maxDepth: 5, maxDistance: netherStructureReach / 2, place: func(p piece, target cube.Pos) (int, bool) {
+		return target[1], target[1]+p.t.height < r.Max()-4
+	}}
+	return &structure{pieces: j.assemble(start, rnd), palette: bastionPalette{}}
+}
+
+// bastionPalette is the palette of bastions.
+type bastionPalette struct{}
+
+// block ...
+func (bastionPalette) block(ch byte, p piece, pos cube.Pos) (world.Block, bool) {
+	h := positionHash(int64(p.seed), pos[0], pos[1], pos[2])
+	switch ch {
+	case ' ':
+		return nil, false
+	case '.':
+		return nil, true
+	case 'B':
+		// Polished blackstone bricks of bastions are often cracked.
+		return block.PolishedBlackstoneBrick{Cracked: h%4 == 0}, true
+	case 'b':
+		return block.Blackstone{}, true
+	case 'G':
+		if h%4 == 0 {
+			return block.Blackstone{Type: block.GildedBlackstone()}, true
+		}
+		return block.Blackstone{}, true
+	case 'P':
+		return block.Basalt{Polished: true, Axis: cube.Y}, true
+	case 'g':
+		return block.Gold{}, true
+	case 'L':
+		return block.Lava{Still: true, Depth: 8}, true
+	case 'C', 'c':
+		c := block.NewChest()
+		c.Facing, c.LootTable = cube.South, "chests/bastion_other"
+		if ch == 'C' {
+			c.LootTable = "chests/bastion_treasure"
+		}
+		return c, true
+	}
+	return nil, false
+}
+
+// entity ...
+func (bastionPalette) entity(byte, piece, cube.Pos) (world.Entity, bool) {
+	return nil, false
+}
+
+// foundation ...
+func (bastionPalette) foundation() world.Block {
+	return block.Blackstone{}
+}
+
+// bastionRoom creates a template of a room of a bastion, with a floor of
+// blackstone, walls of polished blackstone bricks and a roof of blackstone,
+// and an opening of 3x3 blocks for every connector passed. decorate is called
+// to place further blocks in the room.
+func bastionRoom(name string, weight, limit, width, height, depth int, decorate func(set func(x, y, z int, ch byte)), connectors ...connector) *template {
+	t := buildTemplate(name, weight, width, height, depth, '.', func(set func(x, y, z int, ch byte)) {
+		fillBox(set, 0, 0, 0, width-1, 0, depth-1, 'G')
+		fillBox(set, 0, height-1, 0, width-1, height-1, depth-1, 'b')
+		fillBox(set, 0, 1, 0, 0, height-2, depth-1, 'B')
+		fillBox(set, width-1, 1, 0, width-1, height-2, depth-1, 'B')
+		fillBox(set, 0, 1, 0, width-1, height-2, 0, 'B')
+		fillBox(set, 0, 1, depth-1, width-1, height-2, depth-1, 'B')
+		for _, corner := range [][2]int{{0, 0}, {width - 1, 0}, {0, depth - 1}, {width - 1, depth - 1}} {
+			fillBox(set, corner[0], 1, corner[1], corner[0], height-2, corner[1], 'P')
+		}
+		for _, c := range connectors {
+			if c.facing == cube.North || c.facing == cube.South {
+				fillBox(set, c.x-1, c.y, c.z, c.x+1, c.y+2, c.z, '.')
+			} else {
+				fillBox(set, c.x, c.y, c.z-1, c.x, c.y+2, c.z+1, '.')
+			}
+		}
+		if decorate != nil {
+			decorate(set)
+		}
+	}, connectors...)
+	t.limit = limit
+	return t
+}
+
+// bastionKeep is the keep that every bastion starts from. The treasure of the
+// bastion is kept on a platform of gold blocks surrounded by lava on its
+// ground floor.
+var bastionKeep = bastionRoom("keep", 1, 1, 15, 14, 15, func(set func(x, y, z int, ch byte)) {
+	// An upper floor with an opening in its centre divides the keep.
+	fillBox(set, 1, 7, 1, 13, 7, 13, 'b')
+	fillBox(set, 4, 7, 4, 10, 7, 10, '.')
+	fillBox(set, 5, 0, 5, 9, 0, 9, 'L')
+	fillBox(set, 6, 0, 6, 8, 0, 8, 'g')
+	set(7, 1, 7, 'C')
+	for _, corner := range [][2]int{{2, 2}, {12, 2}, {2, 12}, {12, 12}} {
+		set(corner[0], 8, corner[1], 'g')
+	}
+},
+	connector{x: 7, y: 1, z: 0, facing: cube.North, pool: "ramparts"},
+	connector{x: 7, y: 1, z: 14, facing: cube.South, pool: "ramparts"},
+	connector{x: 0, y: 1, z: 7, facing: cube.West, pool: "ramparts"},
+	connector{x: 14, y: 1, z: 7, facing: cube.East, pool: "ramparts"},
+)
+
+// bastionPools holds the pools of templates that bastions are assembled from.
+var bastionPools = map[string][]*template{
+	"ramparts": {
+		bastionRoom("rampart", 6, 0, 7, 8, 13, func(set func(x, y, z int, ch byte)) {
+			set(1, 1, 6, 'c')
+		},
+			connector{x: 3, y: 1, z: 0, facing: cube.North, pool: "ramparts"},
+			connector{x: 3, y: 1, z: 12, facing: cube.South, pool: "ramparts"},
+		),
+		bastionRoom("rampart_corner", 3, 0, 7, 8, 7, nil,
+			connector{x: 3, y: 1, z: 6, facing: cube.South, pool: "ramparts"},
+			connector{x: 0, y: 1, z: 3, facing: cube.West, pool: "ramparts"},
+		),
+		bastionRoom("stable", 2, 2, 11, 7, 11, func(set func(x, y, z int, ch byte)) {
+			fillBox(set, 2, 1, 2, 8, 1, 2, 'b')
+			set(5, 1, 8, 'c')
+			set(1, 1, 1, 'g')
+			set(9, 1, 1, 'g')
+		}, connector{x: 5, y: 1, z: 10, facing: cube.South, pool: "ramparts"}),
+		bastionRoom("tower", 2, 0, 7, 12, 7, func(set func(x, y, z int, ch byte)) {
+			fillBox(set, 1, 5, 1, 5, 5, 5, 'b')
+			set(3, 5, 3, '.')
+			set(3, 6, 5, 'c')
+		}, connector{x: 3, y: 1, z: 6, facing: cube.South, pool: "ramparts"}),
+	},
+}
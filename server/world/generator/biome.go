@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+)
+
+// selectBiome selects the world.Biome of a column based on its terrain and
+// climate parameters.
+func selectBiome(col column) world.Biome {
+	t, h := col.temperature, col.humidity
+	switch {
+	case col.height < seaLevel-4 && col.continentalness < -0.18:
+		return oceanBiome(t, col.continentalness < -0.45)
+	case col.river > 0.6:
+		if t < -0.45 {
+			return biome.FrozenRiver{}
+		}
+		return biome.River{}
+	case col.height < seaLevel+3 && col.continentalness < -0.05:
+		switch {
+		case col.mountains > 0.3:
+			return biome.StonyShore{}
+		case t < -0.45:
+			return biome.SnowyBeach{}
+		case t > 0.55:
+			return biome.Desert{}
+		}
+		return biome.Beach{}
+	case col.height > 160:
+		switch {
+		case t < -0.15:
+			return biome.FrozenPeaks{}
+		case t < 0.2:
+			return biome.JaggedPeaks{}
+		}
+		return biome.StonyPeaks{}
+	case col.height > 120:
+		switch {
+		case t < -0.15:
+			return biome.SnowySlopes{}
+		case t < 0.2:
+			return biome.Grove{}
+		case h > 0.3:
+			return biome.CherryGrove{}
+		}
+		return biome.Meadow{}
+	}
+	switch {
+	case t < -0.45:
+		if h > 0 {
+			return biome.SnowyTaiga{}
+		}
+		return biome.SnowyPlains{}
+	case t < -0.15:
+		if h > -0.2 {
+			return biome.Taiga{}
+		}
+		return biome.Plains{}
+	case t < 0.2:
+		switch {
+		case h > 0.5:
+			return biome.DarkForest{}
+		case h > 0.2:
+			return biome.BirchForest{}
+		case h > -0.1:
+			return biome.Forest{}
+		case h > -0.3:
+			return biome.FlowerForest{}
+		}
+		return biome.Plains{}
+	case t < 0.55:
+		switch {
+		case h > 0.5:
+			return biome.Swamp{}
+		case h > 0.2:
+			return biome.Jungle{}
+		case h > -0.2:
+			return biome.Forest{}
+		}
+		return biome.Savanna{}
+	}
+	if h > 0.3 {
+		return biome.Badlands{}
+	}
+	return biome.Desert{}
+}
+
+// oceanBiome returns the ocean world.Biome for the temperature passed.
+func oceanBiome(t float64, deep bool) world.Biome {
+	switch {
+	case t < -0.45:
+		if deep {
+			return biome.DeepFrozenOcean{}
+		}
+		return biome.FrozenOcean{}
+	case t < -0.15:
+		if deep {
+			return biome.DeepColdOcean{}
+		}
+		return biome.ColdOcean{}
+	case t < 0.2:
+		if deep {
+			return biome.DeepOcean{}
+		}
+		return biome.Ocean{}
+	case t < 0.55:
+		if deep {
+			return biome.DeepLukewarmOcean{}
+		}
+		return biome.LukewarmOcean{}
+	}
+	if deep {
+		return biome.DeepWarmOcean{}
+	}
+	return biome.WarmOcean{}
+}
+
+// surfaceRule holds the blocks that make up the surface of a biome.
+type surfaceRule struct {
+	// top is the top-most block of the surface if it is not under water.
+	top uint32
+	// underwater is the top-most block of the surface if it is under water.
+	underwater uint32
+	// filler is the block placed below the top-most block.
+	filler uint32
+	// sub is an optional block placed below the filler, such as sandstone
+	// below the sand of deserts.
+	sub uint32
+}
+
+var (
+	grassRID      = world.BlockRuntimeID(block.Grass{})
+	dirtRID       = world.BlockRuntimeID(block.Dirt{})
+	sandRID       = world.BlockRuntimeID(block.Sand{})
+	redSandRID    = world.BlockRuntimeID(block.Sand{Red: true})
+	sandstoneRID  = world.BlockRuntimeID(block.Sandstone{})
+	gravelRID     = world.BlockRuntimeID(block.Gravel{})
+	snowRID       = world.BlockRuntimeID(block.Snow{})
+	terracottaRID = world.BlockRuntimeID(block.Terracotta{})
+	mudRID        = world.BlockRuntimeID(block.Mud{})
+
+	defaultSurface = surfaceRule{top: grassRID, underwater: dirtRID, filler: dirtRID}
+	sandSurface    = surfaceRule{top: sandRID, underwater: sandRID, filler: sandRID, sub: sandstoneRID}
+	stoneSurface   = surfaceRule{top: stoneRID, underwater: gravelRID, filler: stoneRID}
+)
+
+// surfaceRuleOf returns the surfaceRule of the world.Biome passed. The blocks
+// of biomes implementing world.SurfaceBiome take precedence over the default
+// rules.
+func surfaceRuleOf(b world.Biome) surfaceRule {
+	if s, ok := b.(world.SurfaceBiome); ok {
+		top, underwater, filler := s.Surface()
+		return surfaceRule{top: world.BlockRuntimeID(top), underwater: world.BlockRuntimeID(underwater), filler: world.BlockRuntimeID(filler)}
+	}
+	switch b.(type) {
+	case biome.Desert, biome.Beach, biome.SnowyBeach, biome.WarmOcean, biome.DeepWarmOcean, biome.LukewarmOcean, biome.DeepLukewarmOcean:
+		return sandSurface
+	case biome.Badlands:
+		return surfaceRule{top: redSandRID, underwater: redSandRID, filler: terracottaRID}
+	case biome.StonyShore, biome.StonyPeaks:
+		return stoneSurface
+	case biome.FrozenPeaks, biome.JaggedPeaks, biome.SnowySlopes:
+		return surfaceRule{top: snowRID, underwater: gravelRID, filler: snowRID}
+	case biome.Ocean, biome.DeepOcean, biome.ColdOcean, biome.DeepColdOcean, biome.FrozenOcean, biome.DeepFrozenOcean:
+		return surfaceRule{top: gravelRID, underwater: gravelRID, filler: gravelRID}
+	case biome.River, biome.FrozenRiver:
+		return surfaceRule{top: grassRID, underwater: sandRID, filler: dirtRID}
+	case biome.Swamp, biome.MangroveSwamp:
+		return surfaceRule{top: grassRID, underwater: mudRID, filler: dirtRID}
+	}
+	return defaultSurface
+}
+
+// mountainBiomes holds all mountain biomes selected by selectBiome.
+var mountainBiomes = []world.Biome{
+	biome.FrozenPeaks{}, biome.JaggedPeaks{}, biome.StonyPeaks{}, biome.SnowySlopes{}, biome.Grove{}, biome.Meadow{}, biome.CherryGrove{},
+}
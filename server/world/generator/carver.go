@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"math"
+	"math/rand"
+)
+
+// carver carves empty space, such as caves and ravines, into the terrain of a
+// chunk. Carvers run as a second pass after the terrain of a chunk has been
+// generated.
+type carver interface {
+	// carve carves into the chunk at the position passed.
+	carve(pos world.ChunkPos, c *chunk.Chunk)
+}
+
+// carvable is a set of the runtime IDs of all blocks that may be carved out
+// by a carver.
+var carvable = map[uint32]bool{
+	stoneRID: true, deepslateRID: true, dirtRID: true, grassRID: true, gravelRID: true, sandRID: true,
+	redSandRID: true, sandstoneRID: true, terracottaRID: true, snowRID: true, mudRID: true,
+}
+
+// carveBlock carves out the block at the chunk relative coordinates passed
+// and returns true if successful. Blocks that are not carvable or that are
+// below water are never carved, so that carvers do not drain the sea. Carved
+// out space is filled by the aquifer passed. If the carved out block was the
+// top block of the surface, the surface is moved down to the block below.
+func carveBlock(c *chunk.Chunk, a *aquifer, x uint8, y int16, z uint8, wx, wz int) bool {
+	if y <= int16(c.Range().Min()) || y >= int16(c.Range().Max()) {
+		return false
+	}
+	rid := c.Block(x, y, z, 0)
+	if !carvable[rid] || c.Block(x, y+1, z, 0) == waterRID {
+		return false
+	}
+	if fluid, ok := a.fluidAt(wx, int(y), wz); ok {
+		c.SetBlock(x, y, z, 0, fluid)
+	} else {
+		c.SetBlock(x, y, z, 0, airRID)
+	}
+	if rid == grassRID && c.Block(x, y-1, z, 0) == dirtRID {
+		c.SetBlock(x, y-1, z, 0, grassRID)
+	}
+	return true
+}
+
+// noiseCaves is a carver that carves caves using 3D noise. Large, open cheese
+// caves are carved where the cheese noise is high, while long and narrow
+// spaghetti caves are carved where two noise values are both close to 0.
+type noiseCaves struct {
+	a                              *aquifer
+	cheese, spaghettiA, spaghettiB *OctaveNoise
+}
+
+// newNoiseCaves creates noiseCaves using the random source and aquifer passed.
+func newNoiseCaves(r *rand.Rand, a *aquifer) noiseCaves {
+	return noiseCaves{
+		a:          a,
+		cheese:     NewOctaveNoise(r, 2, 90),
+		spaghettiA: NewOctaveNoise(r, 2, 70),
+		spaghettiB: NewOctaveNoise(r, 2, 70),
+	}
+}
+
+// carve ...
+func (n noiseCaves) carve(pos world.ChunkPos, c *chunk.Chunk) {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	cheese := sampleGrid(n.cheese, baseX, baseZ, c.Range())
+	spaghettiA := sampleGrid(n.spaghettiA, baseX, baseZ, c.Range())
+	spaghettiB := sampleGrid(n.spaghettiB, baseX, baseZ, c.Range())
+
+	minY := int16(c.Range().Min()) + 6
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			// Cheese caves stay well below the surface, while spaghetti caves
+			// may break through it to form cave entrances.
+			surface := c.HighestBlock(x, z)
+			for y := surface; y >= minY; y-- {
+				if c.Block(x, y, z, 0) == airRID {
+					continue
+				}
+				open := y < surface-12 && cheese.at(int(x), int(y), int(z)) > 0.42
+				if !open {
+					a, b := spaghettiA.at(int(x), int(y), int(z)), spaghettiB.at(int(x), int(y), int(z))
+					open = a*a+b*b < 0.0025
+				}
+				if open {
+					carveBlock(c, n.a, x, y, z, baseX+int(x), baseZ+int(z))
+				}
+			}
+		}
+	}
+}
+
+// ravines is a carver that carves deep, narrow ravines. Ravines start in a
+// random chunk and may span up to ravineRange chunks in every direction.
+type ravines struct {
+	seed int64
+	a    *aquifer
+}
+
+// ravineRange is the maximum distance in chunks from its starting chunk that
+// a ravine may reach.
+const ravineRange = 8
+
+// carve ...
+func (rv ravines) carve(pos world.ChunkPos, c *chunk.Chunk) {
+	for cx := pos[0] - ravineRange; cx <= pos[0]+ravineRange; cx++ {
+		for cz := pos[1] - ravineRange; cz <= pos[1]+ravineRange; cz++ {
+			h := positionHash(rv.seed, int(cx), 0x5eed, int(cz))
+			if h%50 != 0 {
+				continue
+			}
+			rv.carveRavine(newRand(h), cx, cz, pos, c)
+		}
+	}
+}
+
+// carveRavine carves the part of the ravine starting in chunk cx, cz that lies
+// within the chunk at the position passed.
+func (rv ravines) carveRavine(r *rand.Rand, cx, cz int32, pos world.ChunkPos, c *chunk.Chunk) {
+	x := float64(cx<<4) + r.Float64()*16
+	y := 10 + r.Float64()*60
+	z := float64(cz<<4) + r.Float64()*16
+	yaw, pitch := r.Float64()*math.Pi*2, (r.Float64()-0.5)*0.25
+	width := 1.5 + r.Float64()*2.5
+	length := 80 + r.Intn(40)
+
+	baseX, baseZ := float64(pos[0]<<4), float64(pos[1]<<4)
+	for step := 0; step < length; step++ {
+		// Ravines are widest in the middle and narrow towards both ends.
+		radius := 1.5 + math.Sin(float64(step)*math.Pi/float64(length))*width
+		radiusY := radius * 3
+
+		sinPitch, cosPitch := math.Sincos(pitch)
+		sinYaw, cosYaw := math.Sincos(yaw)
+		x, y, z = x+cosYaw*cosPitch, y+sinPitch, z+sinYaw*cosPitch
+		yaw += (r.Float64() - 0.5) * 0.2
+		pitch = pitch*0.7 + (r.Float64()-0.5)*0.1
+
+		if x+radius < baseX || x-radius >= baseX+16 || z+radius < baseZ || z-radius >= baseZ+16 {
+			continue
+		}
+		rv.carveEllipsoid(c, x-baseX, y, z-baseZ, radius, radiusY, int(baseX), int(baseZ))
+	}
+}
+
+// carveEllipsoid carves an ellipsoid with the horizontal and vertical radius
+// passed at the chunk relative centre passed.
+func (rv ravines) carveEllipsoid(c *chunk.Chunk, cx, cy, cz, radius, radiusY float64, baseX, baseZ int) {
+	minX, maxX := int(math.Max(0, math.Floor(cx-radius))), int(math.Min(15, math.Ceil(cx+radius)))
+	minZ, maxZ := int(math.Max(0, math.Floor(cz-radius))), int(math.Min(15, math.Ceil(cz+radius)))
+	minY, maxY := int(math.Floor(cy-radiusY)), int(math.Ceil(cy+radiusY))
+	for x := minX; x <= maxX; x++ {
+		for z := minZ; z <= maxZ; z++ {
+			dx, dz := (float64(x)+0.5-cx)/radius, (float64(z)+0.5-cz)/radius
+			if dx*dx+dz*dz >= 1 {
+				continue
+			}
+			for y := maxY; y >= minY; y-- {
+				dy := (float64(y) + 0.5 - cy) / radiusY
+				if dx*dx+dy*dy+dz*dz < 1 {
+					carveBlock(c, rv.a, uint8(x), int16(y), uint8(z), baseX+x, baseZ+z)
+				}
+			}
+		}
+	}
+}
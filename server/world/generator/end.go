@@ -0,0 +1,343 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+const (
+	// endIslandCell is the size in blocks of the cells in which the height
+	// value of the islands of the End is computed.
+	endIslandCell = 8
+	// endOuterIslandDistance is the distance in chunks from the centre of the
+	// End beyond which outer islands are generated.
+	endOuterIslandDistance = 64
+	// endOuterIslandReach is the distance in chunks from its centre within
+	// which an outer island may raise the terrain.
+	endOuterIslandReach = 12
+	// endOuterIslandThreshold is the value of the island noise below which a
+	// chunk holds the centre of an outer island.
+	endOuterIslandThreshold = -0.56
+	// endIslandLevel is the Y level around which the islands of the End are
+	// generated.
+	endIslandLevel = 56
+)
+
+// End is a noise based generator of End terrain. The main island of end stone
+// in the centre of the End holds the obsidian pillars with end crystals and
+// the exit portal, and the ender dragon is spawned above it. Beyond 1024
+// blocks from the centre, outer islands covered in chorus plants are
+// generated, some of which hold end cities. End may be constructed by calling
+// NewEnd.
+type End struct {
+	seed int64
+
+	terrain *OctaveNoise
+	islands *Noise
+	spikes  []endSpike
+
+	portalOnce sync.Once
+	portalY    int
+
+	featureSet
+	structureCache
+}
+
+// NewEnd creates a new End generator using the seed passed. The same seed
+// always produces the same terrain.
+func NewEnd(seed int64) *End {
+	r := rand.New(rand.NewSource(seed ^ 0xe4d))
+	g := &End{
+		seed:    seed,
+		terrain: NewOctaveNoise(r, 3, 24),
+		islands: NewNoise(r),
+		spikes:  endSpikes(seed),
+	}
+	g.registerAll(endFeatures())
+	return g
+}
+
+// Seed returns the seed of the End generator.
+func (g *End) Seed() int64 {
+	return g.seed
+}
+
+// GenerateChunk generates the terrain of the chunk at the position passed.
+// Structures are generated without their chests and shulkers, and no end
+// crystals or ender dragon are spawned. GenerateColumn should be used to
+// generate these as well.
+func (g *End) GenerateChunk(pos world.ChunkPos, c *chunk.Chunk) {
+	g.generate(pos, c, nil)
+}
+
+// GenerateColumn generates the terrain of the chunk of the world.Column at the
+// position passed. The end crystals on top of the obsidian pillars, the chests
+// and shulkers of end cities and the ender dragon are added to the
+// world.Column.
+func (g *End) GenerateColumn(pos world.ChunkPos, col *world.Column) {
+	g.generate(pos, col.Chunk, col)
+}
+
+// generate generates the terrain of the chunk at the position passed. If col
+// is not nil, block entities and entities generated are added to it.
+func (g *End) generate(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	detail := g.detailGrid(pos, c.Range())
+	id := uint32(biome.End{}.EncodeBiome())
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			for y := int16(c.Range().Min()); y <= int16(c.Range().Max()); y++ {
+				c.SetBiome(x, y, z, id)
+			}
+			g.generateColumn(c, x, z, detail)
+		}
+	}
+	g.decorate(g.seed, g, pos, c)
+	g.structures(pos, c, col)
+
+	for _, s := range g.spikes {
+		s.place(pos, c, col)
+	}
+	g.exitPortal(pos, c)
+	if col != nil && pos == (world.ChunkPos{}) {
+		col.Entities = append(col.Entities, entity.NewEnderDragon(mgl64.Vec3{0.5, 128, 0.5}))
+	}
+}
+
+var endStoneRID = world.BlockRuntimeID(block.EndStone{})
+
+// generateColumn generates the blocks of a single column of a chunk, which
+// are end stone wherever the density of the terrain is positive.
+func (g *End) generateColumn(c *chunk.Chunk, x, z uint8, detail noiseGrid) {
+	r := c.Range()
+	for y := int16(r.Max()); y >= int16(r.Min()); y-- {
+		if detail.at(int(x), int(y), int(z)) > 0 {
+			c.SetBlock(x, y, z, 0, endStoneRID)
+		}
+	}
+}
+
+// endDensity returns the density of the terrain of the End at the Y level
+// passed, using the value of the terrain noise and the island height value at
+// that position. The terrain is solid where the density is positive. Islands
+// have a flat top slightly above endIslandLevel and taper off towards their
+// bottom.
+func endDensity(y int, noise, island float64) float64 {
+	d := (island-8)/16 + noise*0.8
+	if y > endIslandLevel {
+		return d - float64(y-endIslandLevel)/2
+	}
+	return d - float64(endIslandLevel-y)/10
+}
+
+// islandHeight returns the island height value at the X and Z coordinates
+// passed, which ranges from -100 to 80. The value is highest in the centre of
+// the main island and the outer islands passed and falls off with the distance
+// from their centres.
+func islandHeight(x, z float64, islands []endIsland) float64 {
+	fx, fz := x/endIslandCell, z/endIslandCell
+	h := clamp(100-math.Sqrt(fx*fx+fz*fz)*8, -100, 80)
+	for _, i := range islands {
+		dx, dz := (x-float64(i.x))/endIslandCell, (z-float64(i.z))/endIslandCell
+		h = max(h, clamp(100-math.Sqrt(dx*dx+dz*dz)*i.size, -100, 80))
+	}
+	return h
+}
+
+// endIsland is the centre of an outer island of the End.
+type endIsland struct {
+	x, z int
+	// size is the rate at which the island height value falls off with the
+	// distance from the centre of the island. Smaller values produce larger
+	// islands.
+	size float64
+}
+
+// islandsNear returns the outer islands that may raise the terrain of the
+// chunk at the position passed. Every chunk far enough from the centre of the
+// End for which the island noise is low enough holds the centre of an outer
+// island.
+func (g *End) islandsNear(pos world.ChunkPos) []endIsland {
+	cx, cz := int(pos[0]), int(pos[1])
+	if near := endOuterIslandDistance - endOuterIslandReach - 1; cx*cx+cz*cz < near*near {
+		// No outer island reaches this close to the centre of the End.
+		return nil
+	}
+	var islands []endIsland
+	for m := cx - endOuterIslandReach - 1; m <= cx+endOuterIslandReach+1; m++ {
+		for n := cz - endOuterIslandReach - 1; n <= cz+endOuterIslandReach+1; n++ {
+			if m*m+n*n <= endOuterIslandDistance*endOuterIslandDistance || g.islands.Sample2D(float64(m), float64(n)) >= endOuterIslandThreshold {
+				continue
+			}
+			// The size of every outer island depends on the position of its
+			// centre.
+			islands = append(islands, endIsland{x: m<<4 + 8, z: n<<4 + 8, size: float64((abs(m)*3439+abs(n)*147)%13 + 9)})
+		}
+	}
+	return islands
+}
+
+// detailGrid samples the density of the terrain at the corners of all cells
+// of the chunk at the position passed. Unlike the grids of other generators,
+// the values of the grid are densities rather than raw noise values.
+func (g *End) detailGrid(pos world.ChunkPos, r cube.Range) noiseGrid {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	grid := noiseGrid{minY: r.Min(), height: r.Height()/gridCellHeight + 1}
+	grid.values = make([]float64, gridWidth*gridWidth*grid.height)
+	islands := g.islandsNear(pos)
+	for cx := 0; cx < gridWidth; cx++ {
+		for cz := 0; cz < gridWidth; cz++ {
+			x, z := baseX+cx*gridCellWidth, baseZ+cz*gridCellWidth
+			island := islandHeight(float64(x), float64(z), islands)
+			for cy := 0; cy < grid.height; cy++ {
+				y := grid.minY + cy*gridCellHeight
+				if island < -20 {
+					// The density is far below zero, regardless of the noise.
+					grid.values[(cx*gridWidth+cz)*grid.height+cy] = -10
+					continue
+				}
+				grid.values[(cx*gridWidth+cz)*grid.height+cy] = endDensity(y, g.terrain.Sample3D(float64(x), float64(y), float64(z)), island)
+			}
+		}
+	}
+	return grid
+}
+
+// biomeAt ...
+func (g *End) biomeAt(int, int) world.Biome {
+	return biome.End{}
+}
+
+// terrainColumn ...
+func (g *End) terrainColumn(c *chunk.Chunk, x, z int, detail noiseGrid) {
+	g.generateColumn(c, uint8(x&15), uint8(z&15), detail)
+}
+
+// columnSurface ...
+func (g *End) columnSurface(x, z int, detail noiseGrid, r cube.Range) int {
+	for y := r.Max(); y > r.Min(); y-- {
+		if detail.at(x&15, y, z&15) > 0 {
+			return y
+		}
+	}
+	return r.Min()
+}
+
+// exitPortal places the part of the unlit exit portal that lies within the
+// chunk at the position passed. The exit portal is placed on top of the main
+// island in the centre of the End and is lit once the ender dragon is killed.
+func (g *End) exitPortal(pos world.ChunkPos, c *chunk.Chunk) {
+	if pos[0] < -1 || pos[0] > 0 || pos[1] < -1 || pos[1] > 0 {
+		return
+	}
+	g.portalOnce.Do(func() {
+		g.portalY = g.columnSurface(0, 0, g.detailGrid(world.ChunkPos{}, c.Range()), c.Range()) + 1
+	})
+	set := func(x, y, z int, b world.Block) {
+		if int32(x>>4) == pos[0] && int32(z>>4) == pos[1] {
+			c.SetBlock(uint8(x&15), int16(y), uint8(z&15), 0, world.BlockRuntimeID(b))
+		}
+	}
+	y := g.portalY
+	for x := -4; x <= 4; x++ {
+		for z := -4; z <= 4; z++ {
+			dist := math.Sqrt(float64(x*x + z*z))
+			switch {
+			case dist <= 2.5:
+				set(x, y-1, z, block.Bedrock{})
+				set(x, y, z, block.Air{})
+			case dist <= 3.5:
+				set(x, y-1, z, block.EndStone{})
+				set(x, y, z, block.Bedrock{})
+			}
+		}
+	}
+	for dy := 0; dy < 4; dy++ {
+		set(0, y+dy, 0, block.Bedrock{})
+	}
+	for _, face := range cube.HorizontalFaces() {
+		p := cube.Pos{0, y + 2, 0}.Side(face)
+		set(p[0], p[1], p[2], block.Torch{Facing: face.Opposite(), Type: block.NormalFire()})
+	}
+}
+
+// endSpikeCount is the number of obsidian pillars on the main island.
+const endSpikeCount = 10
+
+// endSpike is an obsidian pillar on the main island of the End, with an end
+// crystal on top of it.
+type endSpike struct {
+	x, z    int
+	height  int
+	radius  int
+	guarded bool
+}
+
+// endSpikes returns the obsidian pillars of the End generated with the seed
+// passed. The pillars form a ring around the centre of the main island, with
+// their heights shuffled.
+func endSpikes(seed int64) []endSpike {
+	sizes := rand.New(rand.NewSource(seed)).Perm(endSpikeCount)
+	spikes := make([]endSpike, endSpikeCount)
+	for i, l := range sizes {
+		angle := 2 * (-math.Pi + math.Pi/endSpikeCount*float64(i))
+		spikes[i] = endSpike{
+			x:      int(math.Floor(42 * math.Cos(angle))),
+			z:      int(math.Floor(42 * math.Sin(angle))),
+			height: 76 + l*3,
+			radius: 2 + l/3,
+			// The two smallest pillars but one have their end crystals
+			// guarded by a cage of iron bars.
+			guarded: l == 1 || l == 2,
+		}
+	}
+	return spikes
+}
+
+// place places the part of the endSpike that lies within the chunk at the
+// position passed. If col is not nil and the chunk holds the centre of the
+// endSpike, its end crystal is added to col.
+func (s endSpike) place(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	minX, minZ := int(pos[0])<<4, int(pos[1])<<4
+	if s.x+s.radius < minX || s.x-s.radius >= minX+16 || s.z+s.radius < minZ || s.z-s.radius >= minZ+16 {
+		return
+	}
+	r := c.Range()
+	obsidian, bars := world.BlockRuntimeID(block.Obsidian{}), world.BlockRuntimeID(block.IronBars{})
+	for x := max(s.x-s.radius, minX); x <= min(s.x+s.radius, minX+15); x++ {
+		for z := max(s.z-s.radius, minZ); z <= min(s.z+s.radius, minZ+15); z++ {
+			dx, dz := x-s.x, z-s.z
+			if dx*dx+dz*dz > s.radius*s.radius+1 {
+				continue
+			}
+			for y := r.Min(); y < s.height; y++ {
+				c.SetBlock(uint8(x&15), int16(y), uint8(z&15), 0, obsidian)
+			}
+		}
+	}
+	if s.guarded {
+		for x := max(s.x-2, minX); x <= min(s.x+2, minX+15); x++ {
+			for z := max(s.z-2, minZ); z <= min(s.z+2, minZ+15); z++ {
+				for dy := 0; dy <= 3; dy++ {
+					if abs(x-s.x) == 2 || abs(z-s.z) == 2 || dy == 3 {
+						c.SetBlock(uint8(x&15), int16(s.height+dy), uint8(z&15), 0, bars)
+					}
+				}
+			}
+		}
+	}
+	if s.x>>4 != int(pos[0]) || s.z>>4 != int(pos[1]) {
+		return
+	}
+	c.SetBlock(uint8(s.x&15), int16(s.height), uint8(s.z&15), 0, bedrockRID)
+	if col != nil {
+		col.Entities = append(col.Entities, entity.NewEnderCrystal(mgl64.Vec3{float64(s.x) + 0.5, float64(s.height + 1), float64(s.z) + 0.5}, true))
+	}
+}
@@ -0,0 +1,282 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"math/rand"
+)
+
+const (
+	// endCitySpacing is the size in chunks of the regions in which at most one
+	// end city is placed.
+	endCitySpacing = 20
+	// endCitySeparation is the minimum distance in chunks between the starts
+	// of end cities in neighbouring regions.
+	endCitySeparation = 11
+	// endCityReach is the maximum horizontal distance in blocks from its start
+	// that any piece of an end city reaches.
+	endCityReach = 72
+	// endCityMinSurface is the lowest Y level of the surface of an outer
+	// island that an end city is placed on.
+	endCityMinSurface = 60
+	// maxEndCitySearchRadius is the maximum distance in regions from a
+	// position that is searched for end cities when locating the nearest one.
+	maxEndCitySearchRadius = 16
+)
+
+// structures places the parts of all end cities that intersect with the chunk
+// at the position passed. If col is not nil, the chests and shulkers of the
+// end cities are added to it.
+func (g *End) structures(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	reach := int32(endCityReach>>4 + 1)
+	minX, maxX := floorDiv(int(pos[0]-reach), endCitySpacing), floorDiv(int(pos[0]+reach), endCitySpacing)
+	minZ, maxZ := floorDiv(int(pos[1]-reach), endCitySpacing), floorDiv(int(pos[1]+reach), endCitySpacing)
+	for rx := minX; rx <= maxX; rx++ {
+		for rz := minZ; rz <= maxZ; rz++ {
+			if s := g.endCity(rx, rz, c.Range()); s != nil {
+				s.place(pos, c, col)
+			}
+		}
+	}
+}
+
+// endCity returns the end city in the region passed, or nil if the region
+// does not have one.
+func (g *End) endCity(rx, rz int, r cube.Range) *structure {
+	return g.cachedStructure(structureKey{name: "end_city", pos: world.ChunkPos{int32(rx), int32(rz)}}, func() *structure {
+		pos, ok := g.endCityStart(rx, rz, r)
+		if !ok {
+			return nil
+		}
+		return assembleEndCity(pos, r, newRand(positionHash(g.seed, rx, 0xe4dc, rz)))
+	})
+}
+
+// endCityStart returns the position of the start of the end city in the region
+// passed. End cities are only placed on the outer islands, where the surface
+// is high enough.
+func (g *End) endCityStart(rx, rz int, r cube.Range) (cube.Pos, bool) {
+	h := positionHash(g.seed, rx, 0xe4dc, rz)
+	cx := rx*endCitySpacing + int(h%(endCitySpacing-endCitySeparation))
+	cz := rz*endCitySpacing + int((h>>16)%(endCitySpacing-endCitySeparation))
+	if cx*cx+cz*cz <= endOuterIslandDistance*endOuterIslandDistance {
+		return cube.Pos{}, false
+	}
+	x, z := cx<<4+8, cz<<4+8
+	y := g.columnSurface(x, z, g.detailGrid(world.ChunkPos{int32(cx), int32(cz)}, r), r)
+	return cube.Pos{x, y + 1, z}, y >= endCityMinSurface
+}
+
+// LocateStructure returns the position of the start of the structure with the
+// name passed that is nearest to the position passed. End generates structures
+// named "end_city".
+func (g *End) LocateStructure(name string, pos cube.Pos) (cube.Pos, bool) {
+	if name != "end_city" {
+		return cube.Pos{}, false
+	}
+	return locateInRegions(pos, endCitySpacing, maxEndCitySearchRadius, func(rx, rz int) (cube.Pos, bool) {
+		return g.endCityStart(rx, rz, world.End.Range())
+	})
+}
+
+// assembleEndCity assembles an end city starting at the position passed, which
+// is on the surface of an outer island. End cities are built from towers of
+// purpur connected by bridges of end stone bricks, which lead further out to
+// more towers and, sometimes, to an end ship.
+func assembleEndCity(pos cube.Pos, r cube.Range, rnd *rand.Rand) *structure {
+	start := newPiece(endCityBase, rnd.Intn(4), rnd.Uint64())
+	start.x, start.y, start.z = pos[0]-start.sizeX/2, pos[1]-1, pos[2]-start.sizeZ/2
+
+	j := jigsaw{pools: endCityPools, maxDepth: 6, maxDistance: endCityReach, place: func(p piece, target cube.Pos) (int, bool) {
+		return target[1], target[1]+p.t.height < r.Max()
+	}}
+	return &structure{pieces: j.assemble(start, rnd), palette: endCityPalette{}}
+}
+
+// endCityPalette is the palette of end cities.
+type endCityPalette struct{}
+
+// block ...
+func (endCityPalette) block(ch byte, _ piece, _ cube.Pos) (world.Block, bool) {
+	switch ch {
+	case ' ':
+		return nil, false
+	case '.', 'S':
+		return nil, true
+	case 'P':
+		return block.Purpur{}, true
+	case 'p':
+		return block.PurpurPillar{Axis: cube.Y}, true
+	case 'E':
+		return block.EndBricks{}, true
+	case 'G':
+		return block.StainedGlass{Colour: item.ColourMagenta()}, true
+	case 'C':
+		c := block.NewChest()
+		c.Facing, c.LootTable = cube.South, "chests/end_city_treasure"
+		return c, true
+	case 'F':
+		return block.ItemFrame{Facing: cube.FaceNorth, Item: item.NewStack(item.Elytra{}, 1), DropChance: 1}, true
+	case 'D':
+		return block.Skull{Type: block.DragonHead(), Attach: block.WallAttachment(cube.North)}, true
+	}
+	return nil, false
+}
+
+// entity ...
+func (endCityPalette) entity(ch byte, _ piece, pos cube.Pos) (world.Entity, bool) {
+	if ch == 'S' {
+		return entity.NewShulker(pos.Vec3Middle()), true
+	}
+	return nil, false
+}
+
+// foundation returns nil, as end cities float above the outer islands rather
+// than being supported by a foundation.
+func (endCityPalette) foundation() world.Block {
+	return nil
+}
+
+// endCityRoom creates a template of a room of an end city, with a floor of
+// end stone bricks, walls of purpur with pillars in its corners, a roof of
+// purpur and an opening of 3x3 blocks for every connector passed. decorate is
+// called to place further blocks in the room.
+func endCityRoom(name string, weight, limit, width, height, depth int, decorate func(set func(x, y, z int, ch byte)), connectors ...connector) *template {
+	t := buildTemplate(name, weight, width, height, depth, '.', func(set func(x, y, z int, ch byte)) {
+		fillBox(set, 0, 0, 0, width-1, 0, depth-1, 'E')
+		fillBox(set, 0, height-1, 0, width-1, height-1, depth-1, 'P')
+		fillBox(set, 0, 1, 0, 0, height-2, depth-1, 'P')
+		fillBox(set, width-1, 1, 0, width-1, height-2, depth-1, 'P')
+		fillBox(set, 0, 1, 0, width-1, height-2, 0, 'P')
+		fillBox(set, 0, 1, depth-1, width-1, height-2, depth-1, 'P')
+		for _, corner := range [][2]int{{0, 0}, {width - 1, 0}, {0, depth - 1}, {width - 1, depth - 1}} {
+			fillBox(set, corner[0], 0, corner[1], corner[0], height-1, corner[1], 'p')
+		}
+		// Windows of magenta stained glass are placed in the middle of every
+		// wall, below the roof.
+		set(width/2, height-3, 0, 'G')
+		set(width/2, height-3, depth-1, 'G')
+		set(0, height-3, depth/2, 'G')
+		set(width-1, height-3, depth/2, 'G')
+		for _, c := range connectors {
+			if c.facing == cube.North || c.facing == cube.South {
+				fillBox(set, c.x-1, c.y, c.z, c.x+1, c.y+2, c.z, '.')
+			} else {
+				fillBox(set, c.x, c.y, c.z-1, c.x, c.y+2, c.z+1, '.')
+			}
+		}
+		if decorate != nil {
+			decorate(set)
+		}
+	}, connectors...)
+	t.limit = limit
+	return t
+}
+
+// endCityBase is the tower that every end city starts from. It stands on the
+// surface of an outer island and has an entrance on its ground floor, with
+// bridges leading away from its top floor.
+var endCityBase = endCityRoom("base", 1, 1, 9, 16, 9, func(set func(x, y, z int, ch byte)) {
+	fillBox(set, 1, 5, 1, 7, 5, 7, 'E')
+	fillBox(set, 1, 10, 1, 7, 10, 7, 'E')
+	// Openings in the floors allow climbing up the tower.
+	set(4, 5, 4, '.')
+	set(4, 10, 4, '.')
+	fillBox(set, 3, 1, 0, 5, 3, 0, '.')
+	set(1, 6, 1, 'S')
+	set(7, 11, 7, 'S')
+},
+	connector{x: 4, y: 11, z: 0, facing: cube.North, pool: "bridges"},
+	connector{x: 4, y: 11, z: 8, facing: cube.South, pool: "bridges"},
+	connector{x: 0, y: 11, z: 4, facing: cube.West, pool: "bridges"},
+	connector{x: 8, y: 11, z: 4, facing: cube.East, pool: "bridges"},
+)
+
+// endCityBridge creates a template of a bridge of end stone bricks with
+// railings of purpur, which leads from one tower of an end city to another.
+func endCityBridge(name string, weight, length int) *template {
+	return buildTemplate(name, weight, 5, 4, length, ' ', func(set func(x, y, z int, ch byte)) {
+		fillBox(set, 0, 0, 0, 4, 0, length-1, 'E')
+		fillBox(set, 1, 1, 0, 3, 3, length-1, '.')
+		fillBox(set, 0, 1, 0, 0, 1, length-1, 'P')
+		fillBox(set, 4, 1, 0, 4, 1, length-1, 'P')
+	},
+		connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "towers"},
+		connector{x: 2, y: 1, z: length - 1, facing: cube.South, pool: "towers"},
+	)
+}
+
+// endCityShip creates the template of the end ship that may be moored at an
+// end city. Its hold keeps two chests and an elytra hanging in an item frame,
+// and a dragon head is mounted on its bow. At most one end ship is moored at
+// every end city.
+func endCityShip() *template {
+	t := buildTemplate("ship", 2, 9, 12, 25, ' ', func(set func(x, y, z int, ch byte)) {
+		for z := 0; z < 25; z++ {
+			// The hull of the ship narrows towards its bow and stern.
+			half := min(4, min(z, 24-z)/2+1)
+			fillBox(set, 4-half, 1, z, 4+half, 4, z, 'P')
+			fillBox(set, 4-half+1, 0, z, 4+half-1, 0, z, 'P')
+			if half > 1 {
+				fillBox(set, 4-half+1, 2, z, 4+half-1, 3, z, '.')
+				fillBox(set, 4-half+1, 4, z, 4+half-1, 4, z, 'E')
+				fillBox(set, 4-half+1, 5, z, 4+half-1, 7, z, '.')
+			}
+		}
+		// The deck has a railing and a mast of purpur pillars.
+		fillBox(set, 0, 5, 6, 0, 5, 18, 'P')
+		fillBox(set, 8, 5, 6, 8, 5, 18, 'P')
+		fillBox(set, 0, 5, 11, 0, 7, 13, '.')
+		fillBox(set, 4, 5, 12, 4, 11, 12, 'p')
+
+		set(3, 2, 16, 'C')
+		set(5, 2, 16, 'C')
+		set(4, 2, 19, 'S')
+		set(4, 5, 20, 'S')
+		fillBox(set, 3, 2, 5, 5, 3, 5, 'P')
+		set(4, 3, 6, 'F')
+		set(4, 4, 0, 'D')
+	}, connector{x: 0, y: 5, z: 12, facing: cube.West, pool: "towers"})
+	t.limit = 1
+	return t
+}
+
+// endCityPools holds the pools of templates that end cities are assembled
+// from.
+var endCityPools = map[string][]*template{
+	"bridges": {
+		endCityBridge("bridge", 3, 9),
+		endCityBridge("long_bridge", 2, 15),
+	},
+	"towers": {
+		endCityRoom("tower", 4, 0, 9, 12, 9, func(set func(x, y, z int, ch byte)) {
+			set(1, 1, 1, 'S')
+			set(7, 5, 7, 'S')
+		},
+			connector{x: 4, y: 1, z: 0, facing: cube.North, pool: "bridges"},
+			connector{x: 4, y: 1, z: 8, facing: cube.South, pool: "bridges"},
+			connector{x: 0, y: 1, z: 4, facing: cube.West, pool: "bridges"},
+			connector{x: 8, y: 1, z: 4, facing: cube.East, pool: "bridges"},
+		),
+		endCityRoom("fat_tower", 2, 2, 13, 14, 13, func(set func(x, y, z int, ch byte)) {
+			fillBox(set, 1, 7, 1, 11, 7, 11, 'E')
+			fillBox(set, 5, 7, 5, 7, 7, 7, '.')
+			set(2, 1, 10, 'C')
+			set(10, 8, 2, 'C')
+			set(10, 1, 10, 'S')
+			set(2, 8, 2, 'S')
+		},
+			connector{x: 6, y: 1, z: 0, facing: cube.North, pool: "bridges"},
+			connector{x: 6, y: 1, z: 12, facing: cube.South, pool: "bridges"},
+			connector{x: 12, y: 1, z: 6, facing: cube.East, pool: "bridges"},
+		),
+		endCityRoom("tower_top", 3, 0, 7, 8, 7, func(set func(x, y, z int, ch byte)) {
+			set(3, 1, 5, 'S')
+		}, connector{x: 3, y: 1, z: 0, facing: cube.North, pool: "bridges"}),
+		endCityShip(),
+	},
+}
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"math/rand"
+)
+
+// ChorusPlantFeature is a Feature that places a chorus plant on end stone.
+// Chorus plants are only placed on the outer islands of the End, never on its
+// main island.
+type ChorusPlantFeature struct{}
+
+const (
+	// chorusPlantReach is the maximum horizontal distance from its origin
+	// that any chorus plant placed by a ChorusPlantFeature reaches.
+	chorusPlantReach = 8
+	// chorusPlantMinDistance is the minimum distance from the centre of the
+	// End at which chorus plants are placed.
+	chorusPlantMinDistance = (endOuterIslandDistance - endOuterIslandReach) << 4
+)
+
+// Place ...
+func (f ChorusPlantFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, chorusPlantReach) || pos[0]*pos[0]+pos[2]*pos[2] < chorusPlantMinDistance*chorusPlantMinDistance {
+		return false
+	}
+	if r.blockRID(pos) != airRID || r.blockRID(pos.Side(cube.FaceDown)) != endStoneRID {
+		return false
+	}
+	r.SetBlock(pos, block.ChorusPlant{})
+	f.grow(r, pos, pos, rnd, 0)
+	return true
+}
+
+// grow grows a branch of the chorus plant from the position passed, which
+// already holds a chorus plant block. root is the position of the bottom of
+// the chorus plant and depth the number of branches between the root and the
+// branch grown.
+func (f ChorusPlantFeature) grow(r *Region, pos, root cube.Pos, rnd *rand.Rand, depth int) {
+	height := rnd.Intn(4) + 1
+	if depth == 0 {
+		height++
+	}
+	for i := 0; i < height; i++ {
+		p := pos.Add(cube.Pos{0, i + 1})
+		if !f.surroundedByAir(r, p, -1) {
+			return
+		}
+		r.SetBlock(p, block.ChorusPlant{})
+	}
+	top, branched := pos.Add(cube.Pos{0, height}), false
+	if depth < 4 {
+		branches := rnd.Intn(4)
+		if depth == 0 {
+			branches++
+		}
+		for i := 0; i < branches; i++ {
+			face := cube.HorizontalFaces()[rnd.Intn(4)]
+			p := top.Side(face)
+			if abs(p[0]-root[0]) >= chorusPlantReach || abs(p[2]-root[2]) >= chorusPlantReach {
+				continue
+			}
+			if r.blockRID(p) != airRID || r.blockRID(p.Side(cube.FaceDown)) != airRID || !f.surroundedByAir(r, p, face.Opposite()) {
+				continue
+			}
+			branched = true
+			r.SetBlock(p, block.ChorusPlant{})
+			f.grow(r, p, root, rnd, depth+1)
+		}
+	}
+	if !branched {
+		r.SetBlock(top.Side(cube.FaceUp), block.ChorusFlower{Age: 5})
+	}
+}
+
+// surroundedByAir checks if all horizontal neighbours of the position passed
+// are air, except for the neighbour on the face passed. If -1 is passed, all
+// horizontal neighbours are checked.
+func (ChorusPlantFeature) surroundedByAir(r *Region, pos cube.Pos, except cube.Face) bool {
+	for _, face := range cube.HorizontalFaces() {
+		if face != except && r.blockRID(pos.Side(face)) != airRID {
+			return false
+		}
+	}
+	return true
+}
+
+// endFeatures returns the features registered to a new End.
+func endFeatures() map[string]FeaturePlacement {
+	return map[string]FeaturePlacement{
+		"chorus_plant": {
+			Feature: ChorusPlantFeature{},
+			Stage:   StageVegetation,
+			Count:   4,
+		},
+	}
+}
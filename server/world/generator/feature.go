@@ -0,0 +1,351 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"golang.org/x/exp/maps"
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// Feature is a part of the decoration of generated terrain, such as an ore
+// vein, a spring or a tree. Features are placed after the terrain of a chunk
+// has been generated and carved.
+type Feature interface {
+	// Place places the Feature in the Region passed with its origin at the
+	// position passed. Place returns false if the Feature could not be placed
+	// at that position. The rand.Rand passed must be used for all randomness
+	// of the Feature, so that the Feature is placed in the same way for every
+	// chunk that it spans.
+	Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool
+}
+
+// DecorationStage is a stage of decoration. Features of earlier stages are
+// placed before those of later stages.
+type DecorationStage int
+
+const (
+	// StageUndergroundOres is the stage in which ores and blobs of blocks such
+	// as dirt and gravel are placed.
+	StageUndergroundOres DecorationStage = iota
+	// StageUndergroundDecoration is the stage in which features such as
+	// springs are placed.
+	StageUndergroundDecoration
+	// StageVegetation is the stage in which trees and plants are placed on the
+	// surface.
+	StageVegetation
+)
+
+// FeaturePlacement specifies where and how often a Feature is placed during
+// the decoration of a chunk.
+type FeaturePlacement struct {
+	// Feature is the Feature placed.
+	Feature Feature
+	// Stage is the DecorationStage in which the Feature is placed.
+	Stage DecorationStage
+	// Count is the number of attempts made to place the Feature in every
+	// chunk.
+	Count int
+	// Rarity specifies that the Feature is only attempted to be placed in one
+	// out of Rarity chunks. If 0 or 1, the Feature is attempted to be placed
+	// in every chunk.
+	Rarity int
+	// Height is the HeightProvider that provides the Y level of the origin of
+	// every attempt. If nil, the origin is placed directly on top of the
+	// surface.
+	Height HeightProvider
+	// Biomes holds the biomes in which the Feature is placed. If empty, the
+	// Feature is placed in all biomes.
+	Biomes []world.Biome
+}
+
+// namedFeature is a FeaturePlacement registered with a name.
+type namedFeature struct {
+	name string
+	FeaturePlacement
+}
+
+// featureSet holds the features registered to a generator.
+type featureSet struct {
+	featureMu sync.Mutex
+	features  []namedFeature
+}
+
+// RegisterFeature registers a FeaturePlacement under the name passed, so that
+// its Feature is placed in every chunk generated afterwards. If a feature with
+// the same name was already registered, it is replaced.
+func (s *featureSet) RegisterFeature(name string, p FeaturePlacement) {
+	s.featureMu.Lock()
+	defer s.featureMu.Unlock()
+	if i := slices.IndexFunc(s.features, func(f namedFeature) bool { return f.name == name }); i != -1 {
+		s.features[i].FeaturePlacement = p
+		return
+	}
+	s.features = append(s.features, namedFeature{name: name, FeaturePlacement: p})
+}
+
+// UnregisterFeature removes the feature registered under the name passed, so
+// that it is no longer placed in chunks generated afterwards.
+func (s *featureSet) UnregisterFeature(name string) {
+	s.featureMu.Lock()
+	defer s.featureMu.Unlock()
+	s.features = slices.DeleteFunc(s.features, func(f namedFeature) bool { return f.name == name })
+}
+
+// Feature returns the FeaturePlacement registered under the name passed. If no
+// feature with that name is registered, false is returned.
+func (s *featureSet) Feature(name string) (FeaturePlacement, bool) {
+	s.featureMu.Lock()
+	defer s.featureMu.Unlock()
+	if i := slices.IndexFunc(s.features, func(f namedFeature) bool { return f.name == name }); i != -1 {
+		return s.features[i].FeaturePlacement, true
+	}
+	return FeaturePlacement{}, false
+}
+
+// registerAll registers all features of the map passed, sorted by their names
+// so that they are always placed in the same order.
+func (s *featureSet) registerAll(features map[string]FeaturePlacement) {
+	names := maps.Keys(features)
+	slices.Sort(names)
+	for _, name := range names {
+		s.RegisterFeature(name, features[name])
+	}
+}
+
+// terrain is the terrain generator of a Region. It generates the terrain of
+// chunks neighbouring the chunk being decorated, so that features crossing
+// chunk borders are placed the same way in every chunk.
+type terrain interface {
+	// biomeAt returns the world.Biome of the column at the X and Z coordinates
+	// passed.
+	biomeAt(x, z int) world.Biome
+	// detailGrid samples the noiseGrid used to generate the terrain of the
+	// chunk at the position passed.
+	detailGrid(pos world.ChunkPos, r cube.Range) noiseGrid
+	// terrainColumn generates the blocks of the column at the X and Z
+	// coordinates passed, without setting its biomes.
+	terrainColumn(c *chunk.Chunk, x, z int, detail noiseGrid)
+	// columnSurface returns the Y level of the highest block of the column at
+	// the X and Z coordinates passed, without generating the column.
+	columnSurface(x, z int, detail noiseGrid, r cube.Range) int
+}
+
+// decorate places all registered features in the chunk at the position
+// passed. Features originating in neighbouring chunks are placed too, so that
+// features such as trees may cross chunk borders.
+func (s *featureSet) decorate(seed int64, t terrain, pos world.ChunkPos, c *chunk.Chunk) {
+	s.featureMu.Lock()
+	features := slices.Clone(s.features)
+	s.featureMu.Unlock()
+	slices.SortStableFunc(features, func(a, b namedFeature) int {
+		return int(a.Stage - b.Stage)
+	})
+
+	r := &Region{pos: pos, c: c, t: t, neighbours: make(map[world.ChunkPos]*neighbour)}
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			r.surface[int(x)<<4|int(z)] = c.HighestBlock(x, z)
+		}
+	}
+	for i, f := range features {
+		for cx := pos[0] - 1; cx <= pos[0]+1; cx++ {
+			for cz := pos[1] - 1; cz <= pos[1]+1; cz++ {
+				placeFeature(seed, r, f.FeaturePlacement, i, world.ChunkPos{cx, cz})
+			}
+		}
+	}
+}
+
+// placeFeature places the FeaturePlacement passed with origins in the chunk at
+// the position passed. Every attempt receives its own rand.Rand, so that
+// features may return early without affecting the placement of others.
+func placeFeature(seed int64, r *Region, f FeaturePlacement, index int, origin world.ChunkPos) {
+	rnd := newRand(positionHash(seed, int(origin[0]), index, int(origin[1])))
+	if f.Rarity > 1 && rnd.Intn(f.Rarity) != 0 {
+		return
+	}
+	for i := 0; i < f.Count; i++ {
+		x, z := int(origin[0])<<4+rnd.Intn(16), int(origin[1])<<4+rnd.Intn(16)
+		seed := rnd.Uint64()
+		var y int
+		if f.Height != nil {
+			y = f.Height.Height(rnd)
+		} else {
+			y = r.surfaceHeight(x, z) + 1
+		}
+		if len(f.Biomes) > 0 {
+			b := r.Biome(cube.Pos{x, y, z})
+			if !slices.ContainsFunc(f.Biomes, func(other world.Biome) bool { return other.EncodeBiome() == b.EncodeBiome() }) {
+				continue
+			}
+		}
+		f.Feature.Place(r, cube.Pos{x, y, z}, newRand(seed))
+	}
+}
+
+// Region is the area in which a Feature is placed. Blocks may be read
+// anywhere in the Region, but only blocks within the chunk being decorated
+// are changed. Features that span multiple chunks are placed once for every
+// chunk, so that each chunk receives its own part of the Feature.
+type Region struct {
+	pos        world.ChunkPos
+	c          *chunk.Chunk
+	t          terrain
+	neighbours map[world.ChunkPos]*neighbour
+	// surface holds the height of the surface of the chunk being decorated
+	// before any features were placed.
+	surface [256]int16
+}
+
+// neighbour holds the terrain of a chunk neighbouring a chunk being decorated.
+// Its columns are generated only once they are read.
+type neighbour struct {
+	c         *chunk.Chunk
+	detail    noiseGrid
+	generated [256]bool
+}
+
+// Range returns the vertical range of the Region.
+func (r *Region) Range() cube.Range {
+	return r.c.Range()
+}
+
+// Contains checks if the position passed is within the chunk being decorated,
+// which means SetBlock will change the block at that position.
+func (r *Region) Contains(pos cube.Pos) bool {
+	return int32(pos[0]>>4) == r.pos[0] && int32(pos[2]>>4) == r.pos[1] && !pos.OutOfBounds(r.c.Range())
+}
+
+// Near checks if the position passed is within the horizontal distance
+// passed from the chunk being decorated. Features may use Near to return early
+// if they cannot reach the chunk being decorated from their origin.
+func (r *Region) Near(pos cube.Pos, distance int) bool {
+	minX, minZ := int(r.pos[0])<<4, int(r.pos[1])<<4
+	return pos[0] >= minX-distance && pos[0] < minX+16+distance && pos[2] >= minZ-distance && pos[2] < minZ+16+distance
+}
+
+// Block returns the block at the position passed. Outside the chunk being
+// decorated, the block returned is that of the terrain before it was carved
+// and decorated.
+func (r *Region) Block(pos cube.Pos) world.Block {
+	b, _ := world.BlockByRuntimeID(r.blockRID(pos))
+	return b
+}
+
+// SetBlock sets the block at the position passed. SetBlock has no effect if
+// the position is outside the chunk being decorated.
+func (r *Region) SetBlock(pos cube.Pos, b world.Block) {
+	r.setBlockRID(pos, world.BlockRuntimeID(b))
+}
+
+// setBlockRID sets the block with the runtime ID passed at the position
+// passed. setBlockRID has no effect if the position is outside the chunk being
+// decorated.
+func (r *Region) setBlockRID(pos cube.Pos, rid uint32) {
+	if r.Contains(pos) {
+		r.c.SetBlock(uint8(pos[0]&15), int16(pos[1]), uint8(pos[2]&15), 0, rid)
+	}
+}
+
+// HighestBlock returns the Y level of the highest block that is not air in
+// the column at the X and Z coordinates passed.
+func (r *Region) HighestBlock(x, z int) int {
+	c := r.column(x, z)
+	return int(c.HighestBlock(uint8(x&15), uint8(z&15)))
+}
+
+// surfaceHeight returns the Y level of the highest block in the column at the
+// X and Z coordinates passed, ignoring all features placed. Feature origins
+// are placed on top of it, so that the origin of a feature is the same for
+// every chunk that the feature spans.
+func (r *Region) surfaceHeight(x, z int) int {
+	if int32(x>>4) == r.pos[0] && int32(z>>4) == r.pos[1] {
+		return int(r.surface[(x&15)<<4|z&15])
+	}
+	n := r.neighbour(world.ChunkPos{int32(x >> 4), int32(z >> 4)})
+	if n.generated[(x&15)<<4|z&15] {
+		return int(n.c.HighestBlock(uint8(x&15), uint8(z&15)))
+	}
+	return r.t.columnSurface(x, z, n.detail, r.Range())
+}
+
+// Biome returns the world.Biome at the position passed.
+func (r *Region) Biome(pos cube.Pos) world.Biome {
+	if int32(pos[0]>>4) != r.pos[0] || int32(pos[2]>>4) != r.pos[1] {
+		return r.t.biomeAt(pos[0], pos[2])
+	}
+	y := max(r.Range().Min(), min(r.Range().Max(), pos[1]))
+	b, _ := world.BiomeByID(int(r.c.Biome(uint8(pos[0]&15), int16(y), uint8(pos[2]&15))))
+	return b
+}
+
+// blockRID returns the runtime ID of the block at the position passed.
+func (r *Region) blockRID(pos cube.Pos) uint32 {
+	if pos.OutOfBounds(r.Range()) {
+		return airRID
+	}
+	return r.column(pos[0], pos[2]).Block(uint8(pos[0]&15), int16(pos[1]), uint8(pos[2]&15), 0)
+}
+
+// column returns the chunk holding the column at the X and Z coordinates
+// passed, generating the terrain of that column if it is in a neighbouring
+// chunk and was not yet generated.
+func (r *Region) column(x, z int) *chunk.Chunk {
+	pos := world.ChunkPos{int32(x >> 4), int32(z >> 4)}
+	if pos == r.pos {
+		return r.c
+	}
+	n := r.neighbour(pos)
+	if i := (x&15)<<4 | z&15; !n.generated[i] {
+		n.generated[i] = true
+		r.t.terrainColumn(n.c, x, z, n.detail)
+	}
+	return n.c
+}
+
+// neighbour returns the neighbour at the chunk position passed, creating it if
+// it did not yet exist.
+func (r *Region) neighbour(pos world.ChunkPos) *neighbour {
+	n, ok := r.neighbours[pos]
+	if !ok {
+		n = &neighbour{c: chunk.New(airRID, r.Range())}
+		n.detail = r.t.detailGrid(pos, r.Range())
+		r.neighbours[pos] = n
+	}
+	return n
+}
+
+// HeightProvider provides the Y level at which a Feature is attempted to be
+// placed.
+type HeightProvider interface {
+	// Height returns a Y level using the rand.Rand passed.
+	Height(r *rand.Rand) int
+}
+
+// UniformHeight is a HeightProvider that provides Y levels between Min and
+// Max, with every Y level being equally likely.
+type UniformHeight struct {
+	Min, Max int
+}
+
+// Height ...
+func (h UniformHeight) Height(r *rand.Rand) int {
+	return h.Min + r.Intn(h.Max-h.Min+1)
+}
+
+// TriangleHeight is a HeightProvider that provides Y levels between Min and
+// Max, with Y levels in the middle being the most likely. Min and Max may be
+// outside the world's range to make Y levels at its bottom or top the most
+// likely. Y levels outside the range of the world are never placed.
+type TriangleHeight struct {
+	Min, Max int
+}
+
+// Height ...
+func (h TriangleHeight) Height(r *rand.Rand) int {
+	half := (h.Max - h.Min) / 2
+	return h.Min + r.Intn(half+1) + r.Intn(h.Max-h.Min-half+1)
+}
@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+// fortressPillarDepth is the number of layers of the pillars below the deck of
+// the bridges of a fortress. Pillars that do not reach the ground are
+// extended by the foundation of the fortress.
+const fortressPillarDepth = 8
+
+// assembleFortress assembles a nether fortress starting at the position
+// passed. Fortresses are built from bridges spanning the caverns of the Nether
+// and enclosed corridors that cut through its netherrack.
+func assembleFortress(pos cube.Pos, r cube.Range, rnd *rand.Rand) *structure {
+	start := newPiece(fortressCrossing, rnd.Intn(4), rnd.Uint64())
+	start.x, start.y, start.z = pos[0]-start.sizeX/2, pos[1]-fortressPillarDepth-1, pos[2]-start.sizeZ/2
+
+	j := jigsaw{pools: fortressPools, maxDepth: 10, maxDistance: netherStructureReach, place: func(p piece, target cube.Pos) (int, bool) {
+		return target[1], target[1] > r.Min()+fortressPillarDepth && target[1]+p.t.height < r.Max()-4
+	}}
+	return &structure{pieces: j.assemble(start, rnd), palette: fortressPalette{}}
+}
+
+// fortressPalette is the palette of nether fortresses.
+type fortressPalette struct{}
+
+// block ...
+func (fortressPalette) block(ch byte, p piece, pos cube.Pos) (world.Block, bool) {
+	switch ch {
+	case ' ':
+		return nil, false
+	case '.':
+		return nil, true
+	case 'N':
+		return block.NetherBricks{}, true
+	case 'F':
+		return block.NetherBrickFence{}, true
+	case 'S':
+		return block.SoulSand{}, true
+	case 'W':
+		return block.NetherWart{Age: int(positionHash(int64(p.seed), pos[0], pos[1], pos[2]) % 4)}, true
+	case 'C':
+		c := block.NewChest()
+		c.Facing, c.LootTable = cube.South, "chests/nether_bridge"
+		return c, true
+	}
+	return nil, false
+}
+
+// entity ...
+func (fortressPalette) entity(byte, piece, cube.Pos) (world.Entity, bool) {
+	return nil, false
+}
+
+// foundation ...
+func (fortressPalette) foundation() world.Block {
+	return block.NetherBricks{}
+}
+
+// fortressBridge creates a template of a bridge of a fortress. deck returns
+// if the deck of the bridge covers a column, and pillar returns if a pillar is
+// placed below the deck of a column. Fences are placed along the edges of the
+// deck, except for the openings of the connectors passed.
+func fortressBridge(name string, weight, width, depth int, deck, pillar func(x, z int) bool, connectors ...connector) *template {
+	const d = fortressPillarDepth
+	return buildTemplate(name, weight, width, d+5, depth, ' ', func(set func(x, y, z int, ch byte)) {
+		for x := 0; x < width; x++ {
+			for z := 0; z < depth; z++ {
+				if !deck(x, z) {
+					continue
+				}
+				set(x, d, z, 'N')
+				fillBox(set, x, d+1, z, x, d+4, z, '.')
+				for _, n := range [][2]int{{x - 1, z}, {x + 1, z}, {x, z - 1}, {x, z + 1}} {
+					if !deck(n[0], n[1]) {
+						set(x, d+1, z, 'F')
+					}
+				}
+				if pillar(x, z) {
+					fillBox(set, x, 0, z, x, d-1, z, 'N')
+				} else if pillar(x, z-1) || pillar(x, z+1) || pillar(x-1, z) || pillar(x+1, z) {
+					// Arches next to pillars support the deck.
+					fillBox(set, x, d-2, z, x, d-1, z, 'N')
+				}
+			}
+		}
+		for _, c := range connectors {
+			if c.facing == cube.North || c.facing == cube.South {
+				fillBox(set, c.x-1, c.y, c.z, c.x+1, c.y, c.z, '.')
+			} else {
+				fillBox(set, c.x, c.y, c.z-1, c.x, c.y, c.z+1, '.')
+			}
+		}
+	}, connectors...)
+}
+
+// fortressCorridor creates a template of an enclosed corridor of a fortress,
+// with a floor, walls and a roof of nether bricks and openings for every
+// connector passed. Windows with fences are placed in the walls. decorate is
+// called to place further blocks in the corridor.
+func fortressCorridor(name string, weight, limit, width, depth int, decorate func(set func(x, y, z int, ch byte)), connectors ...connector) *template {
+	t := buildTemplate(name, weight, width, 6, depth, '.', func(set func(x, y, z int, ch byte)) {
+		fillBox(set, 0, 0, 0, width-1, 0, depth-1, 'N')
+		fillBox(set, 0, 5, 0, width-1, 5, depth-1, 'N')
+		for x := 0; x < width; x++ {
+			for z := 0; z < depth; z++ {
+				if x != 0 && z != 0 && x != width-1 && z != depth-1 {
+					continue
+				}
+				ch := byte('N')
+				if (x+z)%2 == 1 && x != z && x != width-1-z {
+					ch = 'F'
+				}
+				fillBox(set, x, 1, z, x, 4, z, 'N')
+				fillBox(set, x, 2, z, x, 3, z, ch)
+			}
+		}
+		for _, c := range connectors {
+			if c.facing == cube.North || c.facing == cube.South {
+				fillBox(set, c.x-1, c.y, c.z, c.x+1, c.y+2, c.z, '.')
+			} else {
+				fillBox(set, c.x, c.y, c.z-1, c.x, c.y+2, c.z+1, '.')
+			}
+		}
+		if decorate != nil {
+			decorate(set)
+		}
+	}, connectors...)
+	t.limit = limit
+	return t
+}
+
+// within returns a function that checks if X and Z coordinates lie within
+// the rectangle passed.
+func within(x0, z0, x1, z1 int) func(x, z int) bool {
+	return func(x, z int) bool {
+		return x >= x0 && x <= x1 && z >= z0 && z <= z1
+	}
+}
+
+// fortressCrossing is the crossing of two bridges that every fortress starts
+// from.
+var fortressCrossing = fortressBridge("bridge_crossing", 2, 15, 15, func(x, z int) bool {
+	return within(5, 0, 9, 14)(x, z) || within(0, 5, 14, 9)(x, z)
+}, within(6, 6, 8, 8),
+	connector{x: 7, y: fortressPillarDepth + 1, z: 0, facing: cube.North, pool: "bridges"},
+	connector{x: 7, y: fortressPillarDepth + 1, z: 14, facing: cube.South, pool: "bridges"},
+	connector{x: 0, y: fortressPillarDepth + 1, z: 7, facing: cube.West, pool: "bridges"},
+	connector{x: 14, y: fortressPillarDepth + 1, z: 7, facing: cube.East, pool: "bridges"},
+)
+
+// fortressPools holds the pools of templates that fortresses are assembled
+// from.
+var fortressPools = map[string][]*template{
+	"bridges": {
+		fortressBridge("bridge", 8, 5, 15, within(0, 0, 4, 14), within(1, 6, 3, 8),
+			connector{x: 2, y: fortressPillarDepth + 1, z: 0, facing: cube.North, pool: "bridges"},
+			connector{x: 2, y: fortressPillarDepth + 1, z: 14, facing: cube.South, pool: "bridges"},
+		),
+		fortressCrossing,
+		fortressBridge("bridge_end", 1, 5, 4, within(0, 0, 4, 3), within(1, 1, 3, 2),
+			connector{x: 2, y: fortressPillarDepth + 1, z: 3, facing: cube.South, pool: "bridges"},
+		),
+		fortressCorridor("corridor_entrance", 3, 0, 5, 9, nil,
+			connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 2, y: 1, z: 8, facing: cube.South, pool: "bridges"},
+		),
+	},
+	"corridors": {
+		fortressCorridor("corridor", 6, 0, 5, 5, nil,
+			connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 2, y: 1, z: 4, facing: cube.South, pool: "corridors"},
+		),
+		fortressCorridor("corridor_turn", 3, 0, 5, 5, nil,
+			connector{x: 2, y: 1, z: 4, facing: cube.South, pool: "corridors"},
+			connector{x: 0, y: 1, z: 2, facing: cube.West, pool: "corridors"},
+		),
+		fortressCorridor("corridor_crossing", 2, 0, 5, 5, nil,
+			connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 2, y: 1, z: 4, facing: cube.South, pool: "corridors"},
+			connector{x: 0, y: 1, z: 2, facing: cube.West, pool: "corridors"},
+			connector{x: 4, y: 1, z: 2, facing: cube.East, pool: "corridors"},
+		),
+		fortressCorridor("chest_corridor", 2, 3, 5, 5, func(set func(x, y, z int, ch byte)) {
+			set(1, 1, 2, 'C')
+		},
+			connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 2, y: 1, z: 4, facing: cube.South, pool: "corridors"},
+		),
+		fortressCorridor("corridor_exit", 2, 0, 5, 9, nil,
+			connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 2, y: 1, z: 8, facing: cube.South, pool: "bridges"},
+		),
+		fortressCorridor("nether_wart_room", 4, 1, 13, 13, func(set func(x, y, z int, ch byte)) {
+			// Nether wart grows on beds of soul sand along the walls of the
+			// room.
+			for _, z := range []int{1, 11} {
+				fillBox(set, 1, 0, z, 11, 0, z, 'S')
+				fillBox(set, 1, 1, z, 11, 1, z, 'W')
+				set(6, 1, z, '.')
+			}
+			set(11, 1, 6, 'C')
+		},
+			connector{x: 6, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 6, y: 1, z: 12, facing: cube.South, pool: "corridors"},
+			connector{x: 0, y: 1, z: 6, facing: cube.West, pool: "corridors"},
+		),
+	},
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+const (
+	// gridCellWidth and gridCellHeight are the horizontal and vertical sizes of
+	// the cells of a noiseGrid.
+	gridCellWidth, gridCellHeight = 4, 8
+	// gridWidth is the number of cell corners of a noiseGrid along the X and Z
+	// axes of a chunk.
+	gridWidth = 16/gridCellWidth + 1
+)
+
+// noiseGrid holds 3D noise sampled at the corners of cells of a chunk. Values
+// in between the corners are interpolated, which is far cheaper than sampling
+// the noise for every block.
+type noiseGrid struct {
+	minY   int
+	height int
+	values []float64
+}
+
+// sampleGrid samples the OctaveNoise passed at the corners of all cells of the
+// chunk at the base coordinates passed.
+func sampleGrid(n *OctaveNoise, baseX, baseZ int, r cube.Range) noiseGrid {
+	g := noiseGrid{minY: r.Min(), height: r.Height()/gridCellHeight + 1}
+	g.values = make([]float64, gridWidth*gridWidth*g.height)
+	for cx := 0; cx < gridWidth; cx++ {
+		for cz := 0; cz < gridWidth; cz++ {
+			for cy := 0; cy < g.height; cy++ {
+				x, y, z := baseX+cx*gridCellWidth, g.minY+cy*gridCellHeight, baseZ+cz*gridCellWidth
+				g.values[(cx*gridWidth+cz)*g.height+cy] = n.Sample3D(float64(x), float64(y), float64(z))
+			}
+		}
+	}
+	return g
+}
+
+// at returns the interpolated noise value at the chunk relative coordinates
+// passed.
+func (g noiseGrid) at(x, y, z int) float64 {
+	y -= g.minY
+	cx, cy, cz := x/gridCellWidth, y/gridCellHeight, z/gridCellWidth
+	if cy >= g.height-1 {
+		cy = g.height - 2
+	}
+	tx := float64(x%gridCellWidth) / gridCellWidth
+	ty := float64(y-cy*gridCellHeight) / gridCellHeight
+	tz := float64(z%gridCellWidth) / gridCellWidth
+
+	v := func(ox, oy, oz int) float64 {
+		return g.values[((cx+ox)*gridWidth+cz+oz)*g.height+cy+oy]
+	}
+	return lerp(tx,
+		lerp(tz, lerp(ty, v(0, 0, 0), v(0, 1, 0)), lerp(ty, v(0, 0, 1), v(0, 1, 1))),
+		lerp(tz, lerp(ty, v(1, 0, 0), v(1, 1, 0)), lerp(ty, v(1, 0, 1), v(1, 1, 1))),
+	)
+}
+
+// sampleAt returns the value of the OctaveNoise passed at the position passed,
+// interpolated between the corners of its cell in the same way as the value
+// returned by a noiseGrid sampled for the chunk holding that position. Unlike
+// sampleGrid, sampleAt only samples the corners of a single cell.
+func sampleAt(n *OctaveNoise, x, y, z int, r cube.Range) float64 {
+	height := r.Height()/gridCellHeight + 1
+	cx, cz := floorDiv(x, gridCellWidth), floorDiv(z, gridCellWidth)
+	cy := (y - r.Min()) / gridCellHeight
+	if cy >= height-1 {
+		cy = height - 2
+	}
+	tx := float64(x-cx*gridCellWidth) / gridCellWidth
+	ty := float64(y-r.Min()-cy*gridCellHeight) / gridCellHeight
+	tz := float64(z-cz*gridCellWidth) / gridCellWidth
+
+	v := func(ox, oy, oz int) float64 {
+		return n.Sample3D(float64((cx+ox)*gridCellWidth), float64(r.Min()+(cy+oy)*gridCellHeight), float64((cz+oz)*gridCellWidth))
+	}
+	return lerp(tx,
+		lerp(tz, lerp(ty, v(0, 0, 0), v(0, 1, 0)), lerp(ty, v(0, 0, 1), v(0, 1, 1))),
+		lerp(tz, lerp(ty, v(1, 0, 0), v(1, 1, 0)), lerp(ty, v(1, 0, 1), v(1, 1, 1))),
+	)
+}
+
+// floorDiv divides a by b, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
@@ -0,0 +1,256 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"math"
+	"math/rand"
+)
+
+// netherLavaLevel is the Y level up to which empty space in the Nether is
+// filled with lava.
+const netherLavaLevel = 31
+
+// Nether is a noise based generator of Nether terrain. Large caverns are
+// carved out of netherrack using 3D noise, with a sea of lava filling their
+// bottom. The Nether has crimson and warped forests, basalt deltas, soul sand
+// valleys and nether wastes, and holds nether fortresses and bastions. Nether
+// may be constructed by calling NewNether.
+type Nether struct {
+	seed int64
+
+	terrain               *OctaveNoise
+	temperature, humidity *OctaveNoise
+	surface               *Noise
+
+	featureSet
+	structureCache
+}
+
+// NewNether creates a new Nether generator using the seed passed. The same
+// seed always produces the same terrain.
+func NewNether(seed int64) *Nether {
+	r := rand.New(rand.NewSource(seed ^ 0x4e7e))
+	g := &Nether{
+		seed:        seed,
+		terrain:     NewOctaveNoise(r, 4, 56),
+		temperature: NewOctaveNoise(r, 3, 320),
+		humidity:    NewOctaveNoise(r, 3, 320),
+		surface:     NewNoise(r),
+	}
+	g.registerAll(netherFeatures())
+	return g
+}
+
+// Seed returns the seed of the Nether generator.
+func (g *Nether) Seed() int64 {
+	return g.seed
+}
+
+// GenerateChunk generates the terrain of the chunk at the position passed.
+// Structures are generated without their chests. GenerateColumn should be used
+// to generate these as well.
+func (g *Nether) GenerateChunk(pos world.ChunkPos, c *chunk.Chunk) {
+	g.generate(pos, c, nil)
+}
+
+// GenerateColumn generates the terrain of the chunk of the world.Column at the
+// position passed. The chests of fortresses and bastions are added to the
+// world.Column.
+func (g *Nether) GenerateColumn(pos world.ChunkPos, col *world.Column) {
+	g.generate(pos, col.Chunk, col)
+}
+
+// generate generates the terrain of the chunk at the position passed. If col
+// is not nil, block entities generated are added to it.
+func (g *Nether) generate(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	detail := g.detailGrid(pos, c.Range())
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			wx, wz := int(pos[0])<<4+int(x), int(pos[1])<<4+int(z)
+			g.generateColumn(c, x, z, wx, wz, g.biomeAt(wx, wz), detail, true)
+		}
+	}
+	g.decorate(g.seed, g, pos, c)
+	g.structures(pos, c, col)
+}
+
+var (
+	netherrackRID    = world.BlockRuntimeID(block.Netherrack{})
+	soulSandRID      = world.BlockRuntimeID(block.SoulSand{})
+	soulSoilRID      = world.BlockRuntimeID(block.SoulSoil{})
+	basaltRID        = world.BlockRuntimeID(block.Basalt{Axis: cube.Y})
+	blackstoneRID    = world.BlockRuntimeID(block.Blackstone{})
+	crimsonNyliumRID = world.BlockRuntimeID(block.Nylium{})
+	warpedNyliumRID  = world.BlockRuntimeID(block.Nylium{Warped: true})
+)
+
+// netherGround is a set of the runtime IDs of the blocks that the terrain of
+// the Nether is made of.
+var netherGround = map[uint32]bool{
+	netherrackRID: true, soulSandRID: true, soulSoilRID: true, basaltRID: true, blackstoneRID: true,
+	crimsonNyliumRID: true, warpedNyliumRID: true, gravelRID: true,
+}
+
+// generateColumn generates the blocks of a single column of a chunk, from the
+// top of the chunk down to the bottom. The biomes of the column are only set
+// if biomes is true.
+func (g *Nether) generateColumn(c *chunk.Chunk, x, z uint8, wx, wz int, b world.Biome, detail noiseGrid, biomes bool) {
+	r := c.Range()
+	id := uint32(b.EncodeBiome())
+	surface := g.surface.Sample2D(float64(wx)/12, float64(wz)/12)
+
+	// open is true if the block above is air, in which case a solid block is
+	// part of the floor of a cavern.
+	open, fillerLeft := false, 0
+	for y := int16(r.Max()); y >= int16(r.Min()); y-- {
+		if biomes {
+			c.SetBiome(x, y, z, id)
+		}
+		h := positionHash(g.seed, wx, int(y), wz)
+		if depth := int(y) - r.Min(); depth < 5 && h%5 >= uint64(depth) {
+			c.SetBlock(x, y, z, 0, bedrockRID)
+			open = false
+			continue
+		}
+		if depth := r.Max() - int(y); depth < 5 && h>>8%5 >= uint64(depth) {
+			c.SetBlock(x, y, z, 0, bedrockRID)
+			open = false
+			continue
+		}
+		if netherDensity(int(y), detail.at(int(x), int(y), int(z))) <= 0 {
+			if y <= netherLavaLevel {
+				c.SetBlock(x, y, z, 0, lavaRID)
+			} else {
+				open = true
+			}
+			continue
+		}
+		rid := netherrackRID
+		switch {
+		case open:
+			open, fillerLeft = false, 2+int(h>>16%2)
+			rid = netherSurface(b, int(y), surface, h, true)
+		case fillerLeft > 0:
+			fillerLeft--
+			rid = netherSurface(b, int(y), surface, h, false)
+		}
+		c.SetBlock(x, y, z, 0, rid)
+	}
+}
+
+// netherDensity returns the density of the terrain of the Nether at the Y
+// level passed, using the value of the terrain noise at that position. The
+// terrain is solid where the density is positive. The density rises towards
+// the bottom and the top of the Nether, so that caverns are enclosed by a
+// floor and a ceiling.
+func netherDensity(y int, noise float64) float64 {
+	d := noise*2.2 - 0.25
+	if y < 24 {
+		d += float64(24-y) / 10
+	}
+	if y > 96 {
+		d += float64(y-96) / 14
+	}
+	return d
+}
+
+// netherSurface returns the runtime ID of the block at the Y level passed near
+// the floor of a cavern in the biome passed. top is true for the top block of
+// the floor. surface is the value of the surface noise of the column and h is
+// a hash of the position of the block.
+func netherSurface(b world.Biome, y int, surface float64, h uint64, top bool) uint32 {
+	switch b.(type) {
+	case biome.CrimsonForest:
+		if top && y > netherLavaLevel {
+			return crimsonNyliumRID
+		}
+	case biome.WarpedForest:
+		if top && y > netherLavaLevel {
+			return warpedNyliumRID
+		}
+	case biome.SoulSandValley:
+		if surface+float64(h>>24%16)/40 > 0.1 {
+			return soulSoilRID
+		}
+		return soulSandRID
+	case biome.BasaltDeltas:
+		if surface+float64(h>>24%16)/40 > 0.25 {
+			return blackstoneRID
+		}
+		return basaltRID
+	case biome.NetherWastes:
+		// Patches of gravel and soul sand are found on the shores of the
+		// lava sea.
+		if top && y >= netherLavaLevel-2 && y <= netherLavaLevel+3 {
+			switch {
+			case surface > 0.3:
+				return gravelRID
+			case surface < -0.35:
+				return soulSandRID
+			}
+		}
+	}
+	return netherrackRID
+}
+
+// netherBiome is a biome of the Nether with the climate parameters for which
+// it is selected.
+type netherBiome struct {
+	temperature, humidity float64
+	biome                 world.Biome
+}
+
+// netherBiomes holds all biomes of the Nether. The biome of a column is the
+// one whose climate parameters lie closest to those of the column.
+var netherBiomes = []netherBiome{
+	{temperature: 0, humidity: 0, biome: biome.NetherWastes{}},
+	{temperature: 0, humidity: -0.5, biome: biome.SoulSandValley{}},
+	{temperature: 0.4, humidity: 0, biome: biome.CrimsonForest{}},
+	{temperature: 0, humidity: 0.5, biome: biome.WarpedForest{}},
+	{temperature: -0.5, humidity: 0, biome: biome.BasaltDeltas{}},
+}
+
+// biomeAt ...
+func (g *Nether) biomeAt(x, z int) world.Biome {
+	t := clamp(g.temperature.Sample2D(float64(x), float64(z))*1.8, -1, 1)
+	h := clamp(g.humidity.Sample2D(float64(x), float64(z))*1.8, -1, 1)
+	nearest, best := netherBiomes[0].biome, math.MaxFloat64
+	for _, b := range netherBiomes {
+		if dist := (t-b.temperature)*(t-b.temperature) + (h-b.humidity)*(h-b.humidity); dist < best {
+			nearest, best = b.biome, dist
+		}
+	}
+	return nearest
+}
+
+// detailGrid ...
+func (g *Nether) detailGrid(pos world.ChunkPos, r cube.Range) noiseGrid {
+	return sampleGrid(g.terrain, int(pos[0])<<4, int(pos[1])<<4, r)
+}
+
+// terrainColumn ...
+func (g *Nether) terrainColumn(c *chunk.Chunk, x, z int, detail noiseGrid) {
+	g.generateColumn(c, uint8(x&15), uint8(z&15), x, z, g.biomeAt(x, z), detail, false)
+}
+
+// columnSurface returns the Y level of the highest block of the column, which
+// in the Nether is always part of the bedrock ceiling.
+func (g *Nether) columnSurface(_, _ int, _ noiseGrid, r cube.Range) int {
+	return r.Max()
+}
+
+// floorAt returns the Y level of the highest floor of a cavern at or below the
+// Y level passed at the X and Z coordinates passed, before the terrain is
+// decorated. If no floor above the lava sea is found, false is returned.
+func (g *Nether) floorAt(x, y, z int, r cube.Range) (int, bool) {
+	for ; y > netherLavaLevel; y-- {
+		if netherDensity(y, sampleAt(g.terrain, x, y, z, r)) > 0 && netherDensity(y+1, sampleAt(g.terrain, x, y+1, z, r)) <= 0 {
+			return y, true
+		}
+	}
+	return 0, false
+}
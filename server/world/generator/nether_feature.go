@@ -0,0 +1,229 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"math/rand"
+)
+
+// GlowstoneFeature is a Feature that places a cluster of glowstone hanging
+// from the netherrack ceiling of a cavern.
+type GlowstoneFeature struct{}
+
+// glowstoneReach is the maximum horizontal distance from its origin that any
+// cluster placed by a GlowstoneFeature reaches.
+const glowstoneReach = 8
+
+// Place ...
+func (GlowstoneFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, glowstoneReach) || r.blockRID(pos) != airRID || r.blockRID(pos.Side(cube.FaceUp)) != netherrackRID {
+		return false
+	}
+	// The blocks of the cluster are kept track of separately, as the blocks
+	// placed in neighbouring chunks cannot be read from the Region.
+	cluster := map[cube.Pos]bool{pos: true}
+	r.SetBlock(pos, block.Glowstone{})
+	for i := 0; i < 1500; i++ {
+		p := pos.Add(cube.Pos{rnd.Intn(glowstoneReach) - rnd.Intn(glowstoneReach), -rnd.Intn(12), rnd.Intn(glowstoneReach) - rnd.Intn(glowstoneReach)})
+		if cluster[p] || r.blockRID(p) != airRID {
+			continue
+		}
+		neighbours := 0
+		for _, face := range cube.Faces() {
+			if cluster[p.Side(face)] {
+				neighbours++
+			}
+		}
+		if neighbours == 1 {
+			cluster[p] = true
+			r.SetBlock(p, block.Glowstone{})
+		}
+	}
+	return true
+}
+
+// HugeFungusFeature is a Feature that places a huge fungus, which is the tree
+// of crimson and warped forests. HugeFungusFeature also implements
+// block.TreeGrower, so that it grows huge fungi from fungi.
+type HugeFungusFeature struct {
+	// Warped specifies if the huge fungus is a warped fungus. If false, the
+	// huge fungus is a crimson fungus.
+	Warped bool
+}
+
+// hugeFungusReach is the maximum horizontal distance from its origin that any
+// huge fungus placed by a HugeFungusFeature reaches.
+const hugeFungusReach = 3
+
+// Place ...
+func (f HugeFungusFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, hugeFungusReach) {
+		return false
+	}
+	at, ok := floorBelow(r, pos, 16)
+	if !ok {
+		return false
+	}
+	if n, ok := r.Block(at.Side(cube.FaceDown)).(block.Nylium); !ok || n.Warped != f.Warped {
+		return false
+	}
+	return f.grow(r, at, rnd, false)
+}
+
+// GrowTree ...
+func (f HugeFungusFeature) GrowTree(pos cube.Pos, w *world.World, r *rand.Rand) bool {
+	return f.grow(worldSource{w: w}, pos, r, true)
+}
+
+// grow grows the huge fungus at the position passed. If strict is true, the
+// huge fungus is only grown if there is enough free space for its stem.
+func (f HugeFungusFeature) grow(s blockSource, pos cube.Pos, rnd *rand.Rand, strict bool) bool {
+	height := 4 + rnd.Intn(10)
+	if rnd.Intn(12) == 0 {
+		height *= 2
+	}
+	if pos[1]+height+1 > s.Range().Max() {
+		return false
+	}
+	if strict {
+		for y := 1; y <= height; y++ {
+			if !fungusReplaceable(s.Block(pos.Add(cube.Pos{0, y, 0}))) {
+				return false
+			}
+		}
+	}
+	wood := block.CrimsonWood()
+	if f.Warped {
+		wood = block.WarpedWood()
+	}
+	for y := 0; y < height; y++ {
+		s.SetBlock(pos.Add(cube.Pos{0, y, 0}), block.Log{Wood: wood, Axis: cube.Y})
+	}
+
+	// The cap of the huge fungus is a hollow hat of wart blocks with a flat
+	// top, dotted with shroomlights.
+	capHeight := min(height-1, 2+rnd.Intn(3))
+	for i := 0; i <= capHeight; i++ {
+		y := pos[1] + height - capHeight + i
+		radius := 2
+		if i == capHeight {
+			radius = 1
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				edge := abs(dx) == radius || abs(dz) == radius
+				if abs(dx) == radius && abs(dz) == radius && rnd.Intn(2) == 0 {
+					continue
+				}
+				if !edge && i != capHeight {
+					continue
+				}
+				p := cube.Pos{pos[0] + dx, y, pos[2] + dz}
+				if !fungusReplaceable(s.Block(p)) {
+					continue
+				}
+				if rnd.Intn(12) == 0 {
+					s.SetBlock(p, block.Shroomlight{})
+				} else {
+					s.SetBlock(p, block.NetherWartBlock{Warped: f.Warped})
+				}
+			}
+		}
+	}
+	return true
+}
+
+// fungusReplaceable checks if the block passed may be replaced by a part of a
+// huge fungus.
+func fungusReplaceable(b world.Block) bool {
+	switch b.(type) {
+	case block.Air, block.Fungus, block.Roots, block.NetherSprouts:
+		return true
+	}
+	return false
+}
+
+// DeltaFeature is a Feature that places a shallow pool of lava in the floor
+// of a cavern, surrounded by a rim of basalt, as found in basalt deltas.
+type DeltaFeature struct{}
+
+// deltaReach is the maximum horizontal distance from its origin that any pool
+// placed by a DeltaFeature reaches.
+const deltaReach = 7
+
+// Place ...
+func (DeltaFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, deltaReach) {
+		return false
+	}
+	at, ok := floorBelow(r, pos, 16)
+	if !ok || at[1] <= netherLavaLevel+1 {
+		return false
+	}
+	floor := at[1] - 1
+	radius := 3 + rnd.Intn(deltaReach-3)
+	// Lava is only placed where it is enclosed by solid blocks, so that it
+	// does not flow out of the pool.
+	solid := func(x, y, z int) bool {
+		return netherGround[r.blockRID(cube.Pos{x, y, z})]
+	}
+	for x := pos[0] - radius; x <= pos[0]+radius; x++ {
+		for z := pos[2] - radius; z <= pos[2]+radius; z++ {
+			dx, dz := x-pos[0], z-pos[2]
+			if dx*dx+dz*dz > radius*radius || !solid(x, floor, z) || r.blockRID(cube.Pos{x, floor + 1, z}) != airRID {
+				continue
+			}
+			enclosed := solid(x, floor-1, z) && solid(x-1, floor, z) && solid(x+1, floor, z) && solid(x, floor, z-1) && solid(x, floor, z+1)
+			if enclosed && dx*dx+dz*dz < (radius-1)*(radius-1) {
+				r.SetBlock(cube.Pos{x, floor, z}, block.Lava{Still: true, Depth: 8})
+			} else {
+				r.SetBlock(cube.Pos{x, floor, z}, block.Basalt{Axis: cube.Y})
+			}
+		}
+	}
+	return true
+}
+
+// netherFeatures returns the features registered to a new Nether.
+func netherFeatures() map[string]FeaturePlacement {
+	ore := func(b world.Block, size, count int, height HeightProvider, biomes ...world.Biome) FeaturePlacement {
+		return FeaturePlacement{Feature: OreFeature{Block: b, Target: block.Netherrack{}, Size: size}, Stage: StageUndergroundOres, Count: count, Height: height, Biomes: biomes}
+	}
+	floor := func(f Feature, count int, biomes ...world.Biome) FeaturePlacement {
+		return FeaturePlacement{Feature: f, Stage: StageVegetation, Count: count, Height: UniformHeight{Min: netherLavaLevel + 1, Max: 120}, Biomes: biomes}
+	}
+	patch := func(plants []world.Block, count int, biomes ...world.Biome) FeaturePlacement {
+		return floor(PatchFeature{Plants: plants, Tries: 64, Spread: 7, Floor: true}, count, biomes...)
+	}
+	crimson, warped := biome.CrimsonForest{}, biome.WarpedForest{}
+
+	return map[string]FeaturePlacement{
+		"quartz_ore":           ore(block.NetherQuartzOre{}, 14, 16, UniformHeight{Min: 10, Max: 117}),
+		"nether_gold_ore":      ore(block.NetherGoldOre{}, 10, 10, UniformHeight{Min: 10, Max: 117}),
+		"ancient_debris":       ore(block.AncientDebris{}, 3, 1, TriangleHeight{Min: 8, Max: 24}),
+		"ancient_debris_small": ore(block.AncientDebris{}, 2, 1, UniformHeight{Min: 8, Max: 119}),
+		"gravel":               ore(block.Gravel{}, 33, 2, UniformHeight{Min: 5, Max: 41}),
+		"blackstone":           ore(block.Blackstone{}, 33, 2, UniformHeight{Min: 5, Max: 31}),
+		"basalt_blobs":         ore(block.Basalt{Axis: cube.Y}, 33, 8, UniformHeight{Min: 5, Max: 120}, biome.BasaltDeltas{}),
+		"lava_spring": {
+			Feature: SpringFeature{Fluid: block.Lava{Depth: 8}, Wall: block.Netherrack{}},
+			Stage:   StageUndergroundDecoration,
+			Count:   16,
+			Height:  UniformHeight{Min: 4, Max: 123},
+		},
+		"glowstone": {
+			Feature: GlowstoneFeature{},
+			Stage:   StageUndergroundDecoration,
+			Count:   10,
+			Height:  UniformHeight{Min: 4, Max: 123},
+		},
+		"deltas":         floor(DeltaFeature{}, 24, biome.BasaltDeltas{}),
+		"crimson_fungi":  floor(HugeFungusFeature{}, 16, crimson),
+		"warped_fungi":   floor(HugeFungusFeature{Warped: true}, 16, warped),
+		"crimson_roots":  patch([]world.Block{block.Roots{}, block.Roots{}, block.Roots{}, block.Fungus{}, block.Fungus{Warped: true}}, 6, crimson),
+		"warped_roots":   patch([]world.Block{block.Roots{Warped: true}, block.Roots{Warped: true}, block.Fungus{Warped: true}, block.Fungus{}}, 6, warped),
+		"nether_sprouts": patch([]world.Block{block.NetherSprouts{}}, 4, warped),
+	}
+}
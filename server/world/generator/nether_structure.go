@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+)
+
+const (
+	// netherStructureSpacing is the size in chunks of the regions in which at
+	// most one fortress or bastion is placed.
+	netherStructureSpacing = 27
+	// netherStructureSeparation is the minimum distance in chunks between the
+	// starts of structures in neighbouring regions.
+	netherStructureSeparation = 4
+	// netherStructureReach is the maximum horizontal distance in blocks from
+	// its start that any piece of a fortress or bastion reaches.
+	netherStructureReach = 96
+	// maxNetherStructureSearchRadius is the maximum distance in regions from a
+	// position that is searched for structures when locating the nearest one.
+	maxNetherStructureSearchRadius = 16
+)
+
+// structures places the parts of all fortresses and bastions that intersect
+// with the chunk at the position passed. If col is not nil, the chests of the
+// structures are added to it.
+func (g *Nether) structures(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	reach := int32(netherStructureReach>>4 + 1)
+	minX, maxX := floorDiv(int(pos[0]-reach), netherStructureSpacing), floorDiv(int(pos[0]+reach), netherStructureSpacing)
+	minZ, maxZ := floorDiv(int(pos[1]-reach), netherStructureSpacing), floorDiv(int(pos[1]+reach), netherStructureSpacing)
+	for rx := minX; rx <= maxX; rx++ {
+		for rz := minZ; rz <= maxZ; rz++ {
+			if s := g.structure(rx, rz, c.Range()); s != nil {
+				s.place(pos, c, col)
+			}
+		}
+	}
+}
+
+// structure returns the fortress or bastion in the region passed, or nil if
+// the region does not have a structure.
+func (g *Nether) structure(rx, rz int, r cube.Range) *structure {
+	return g.cachedStructure(structureKey{name: "nether", pos: world.ChunkPos{int32(rx), int32(rz)}}, func() *structure {
+		name, pos, ok := g.structureStart(rx, rz, r)
+		if !ok {
+			return nil
+		}
+		rnd := newRand(positionHash(g.seed, rx, 0xf047, rz))
+		if name == "fortress" {
+			return assembleFortress(pos, r, rnd)
+		}
+		return assembleBastion(pos, r, rnd)
+	})
+}
+
+// structureStart returns the name and the position of the start of the
+// structure in the region passed. Two in five regions hold a fortress and the
+// others a bastion. Bastions are never placed in basalt deltas, and need a
+// cavern floor above the lava sea to be placed on.
+func (g *Nether) structureStart(rx, rz int, r cube.Range) (string, cube.Pos, bool) {
+	h := positionHash(g.seed, rx, 0xf047, rz)
+	cx := rx*netherStructureSpacing + int(h%(netherStructureSpacing-netherStructureSeparation))
+	cz := rz*netherStructureSpacing + int((h>>16)%(netherStructureSpacing-netherStructureSeparation))
+	x, z := cx<<4+8, cz<<4+8
+
+	if h>>32%5 < 2 {
+		return "fortress", cube.Pos{x, 48 + int(h>>40%23), z}, true
+	}
+	if _, ok := g.biomeAt(x, z).(biome.BasaltDeltas); ok {
+		return "", cube.Pos{}, false
+	}
+	y, ok := g.floorAt(x, 90, z, r)
+	return "bastion", cube.Pos{x, y + 1, z}, ok
+}
+
+// LocateStructure returns the position of the start of the structure with the
+// name passed that is nearest to the position passed. Nether generates
+// structures named "fortress" and "bastion".
+func (g *Nether) LocateStructure(name string, pos cube.Pos) (cube.Pos, bool) {
+	if name != "fortress" && name != "bastion" {
+		return cube.Pos{}, false
+	}
+	return locateInRegions(pos, netherStructureSpacing, maxNetherStructureSearchRadius, func(rx, rz int) (cube.Pos, bool) {
+		n, start, ok := g.structureStart(rx, rz, world.Nether.Range())
+		return start, ok && n == name
+	})
+}
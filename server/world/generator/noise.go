@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise is a seeded gradient noise source based on improved Perlin noise. The
+// values produced are continuous and range from roughly -1 to 1. Noise is
+// safe for concurrent use once created.
+type Noise struct {
+	perm             [512]uint8
+	offX, offY, offZ float64
+}
+
+// NewNoise creates a new Noise source using the random source passed to
+// shuffle its permutation table.
+func NewNoise(r *rand.Rand) *Noise {
+	n := &Noise{offX: r.Float64() * 256, offY: r.Float64() * 256, offZ: r.Float64() * 256}
+	for i := 0; i < 256; i++ {
+		n.perm[i] = uint8(i)
+	}
+	r.Shuffle(256, func(i, j int) {
+		n.perm[i], n.perm[j] = n.perm[j], n.perm[i]
+	})
+	copy(n.perm[256:], n.perm[:256])
+	return n
+}
+
+// Sample2D returns the noise value at the 2D coordinates passed.
+func (n *Noise) Sample2D(x, z float64) float64 {
+	return n.Sample3D(x, 0, z)
+}
+
+// Sample3D returns the noise value at the 3D coordinates passed.
+func (n *Noise) Sample3D(x, y, z float64) float64 {
+	x, y, z = x+n.offX, y+n.offY, z+n.offZ
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	p := &n.perm
+	a, b := int(p[xi])+yi, int(p[xi+1])+yi
+	aa, ab, ba, bb := int(p[a])+zi, int(p[a+1])+zi, int(p[b])+zi, int(p[b+1])+zi
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p[aa], x, y, z), grad(p[ba], x-1, y, z)),
+			lerp(u, grad(p[ab], x, y-1, z), grad(p[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(p[aa+1], x, y, z-1), grad(p[ba+1], x-1, y, z-1)),
+			lerp(u, grad(p[ab+1], x, y-1, z-1), grad(p[bb+1], x-1, y-1, z-1))),
+	)
+}
+
+// OctaveNoise combines multiple octaves of Noise with increasing frequency
+// and decreasing amplitude to produce noise with detail at multiple scales.
+type OctaveNoise struct {
+	octaves []*Noise
+	scale   float64
+}
+
+// NewOctaveNoise creates an OctaveNoise with the number of octaves passed.
+// The coordinates passed to its Sample methods are divided by scale before
+// sampling the first octave.
+func NewOctaveNoise(r *rand.Rand, octaves int, scale float64) *OctaveNoise {
+	o := &OctaveNoise{octaves: make([]*Noise, octaves), scale: scale}
+	for i := range o.octaves {
+		o.octaves[i] = NewNoise(r)
+	}
+	return o
+}
+
+// Sample2D returns the noise value at the 2D coordinates passed, normalised to
+// roughly the range -1 to 1.
+func (o *OctaveNoise) Sample2D(x, z float64) float64 {
+	return o.Sample3D(x, 0, z)
+}
+
+// Sample3D returns the noise value at the 3D coordinates passed, normalised to
+// roughly the range -1 to 1.
+func (o *OctaveNoise) Sample3D(x, y, z float64) float64 {
+	var v, total float64
+	freq, amp := 1/o.scale, 1.0
+	for _, n := range o.octaves {
+		v += n.Sample3D(x*freq, y*freq, z*freq) * amp
+		total += amp
+		freq, amp = freq*2, amp/2
+	}
+	return v / total
+}
+
+// fade is the quintic interpolation curve of improved Perlin noise.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp linearly interpolates between a and b using t.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad returns the dot product of the gradient selected by the hash passed and
+// the distance vector x, y, z.
+func grad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u, v := y, x
+	if h < 8 {
+		u = x
+	}
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	} else {
+		v = z
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// positionHash returns a pseudo-random value for the seed and coordinates
+// passed. The same input always produces the same value.
+func positionHash(seed int64, x, y, z int) uint64 {
+	h := uint64(seed) ^ uint64(x)*0x9e3779b97f4a7c15 ^ uint64(y)*0xbf58476d1ce4e5b9 ^ uint64(z)*0x94d049bb133111eb
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// splitMix is a rand.Source64 based on the SplitMix64 algorithm. Unlike the
+// default source of the rand package, it is cheap to create, which makes it
+// suitable for creating a new rand.Rand for every feature placed.
+type splitMix struct {
+	state uint64
+}
+
+// newRand creates a new rand.Rand backed by a splitMix source with the seed
+// passed.
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(&splitMix{state: seed})
+}
+
+// Seed ...
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Uint64 ...
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// Int63 ...
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// clamp clamps v between min and max.
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
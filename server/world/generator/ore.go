@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+	"math"
+	"math/rand"
+)
+
+// OreFeature is a Feature that places a vein of a block, replacing stone and
+// deepslate. Besides ores, OreFeature is also used to place blobs of blocks
+// such as dirt, gravel and granite.
+type OreFeature struct {
+	// Block is the block that replaces stone.
+	Block world.Block
+	// Deepslate is the block that replaces deepslate. If nil, deepslate is
+	// replaced with Block.
+	Deepslate world.Block
+	// Target is the block that is replaced by Block instead of stone and
+	// deepslate, such as netherrack for the ores of the Nether. If nil, stone
+	// and deepslate are replaced.
+	Target world.Block
+	// Size is the maximum number of blocks in the vein.
+	Size int
+}
+
+// Place ...
+func (o OreFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, o.Size/4+2) {
+		return false
+	}
+	deepslate := o.Deepslate
+	if deepslate == nil {
+		deepslate = o.Block
+	}
+	size := float64(o.Size)
+	sin, cos := math.Sincos(rnd.Float64() * math.Pi)
+	spread := size / 8
+	start := mgl64.Vec3{float64(pos[0]) + sin*spread, float64(pos[1] + rnd.Intn(3) - 2), float64(pos[2]) + cos*spread}
+	end := mgl64.Vec3{float64(pos[0]) - sin*spread, float64(pos[1] + rnd.Intn(3) - 2), float64(pos[2]) - cos*spread}
+
+	oreRID, deepslateOreRID := world.BlockRuntimeID(o.Block), world.BlockRuntimeID(deepslate)
+	stone, deepslateStone := stoneRID, deepslateRID
+	if o.Target != nil {
+		stone, deepslateStone = world.BlockRuntimeID(o.Target), world.BlockRuntimeID(o.Target)
+	}
+	minX, minZ := int(r.pos[0])<<4, int(r.pos[1])<<4
+
+	placed := false
+	for i := 0; i < o.Size; i++ {
+		t := float64(i) / size
+		cx, cy, cz := lerp(t, start[0], end[0]), lerp(t, start[1], end[1]), lerp(t, start[2], end[2])
+		radius := ((math.Sin(math.Pi*t)+1)*rnd.Float64()*size/16 + 1) / 2
+
+		// Only the part of the sphere within the chunk being decorated is
+		// iterated over, as no blocks can be placed outside of it.
+		for x := max(int(math.Floor(cx-radius)), minX); x <= min(int(math.Floor(cx+radius)), minX+15); x++ {
+			for z := max(int(math.Floor(cz-radius)), minZ); z <= min(int(math.Floor(cz+radius)), minZ+15); z++ {
+				for y := int(math.Floor(cy - radius)); y <= int(math.Floor(cy+radius)); y++ {
+					dx, dy, dz := float64(x)+0.5-cx, float64(y)+0.5-cy, float64(z)+0.5-cz
+					p := cube.Pos{x, y, z}
+					if dx*dx+dy*dy+dz*dz >= radius*radius || p.OutOfBounds(r.Range()) {
+						continue
+					}
+					switch r.blockRID(p) {
+					case stone:
+						r.setBlockRID(p, oreRID)
+						placed = true
+					case deepslateStone:
+						r.setBlockRID(p, deepslateOreRID)
+						placed = true
+					}
+				}
+			}
+		}
+	}
+	return placed
+}
+
+// SpringFeature is a Feature that places a single source block of a fluid in
+// a wall of stone, so that the fluid flows out of the wall.
+type SpringFeature struct {
+	// Fluid is the fluid placed, such as water or lava.
+	Fluid world.Block
+	// Wall is the block that the wall around the spring must be made of. If
+	// nil, the wall must be made of stone or deepslate.
+	Wall world.Block
+}
+
+// Place ...
+func (s SpringFeature) Place(r *Region, pos cube.Pos, _ *rand.Rand) bool {
+	if !r.Contains(pos) || !r.Contains(pos.Add(cube.Pos{-1, 0, -1})) || !r.Contains(pos.Add(cube.Pos{1, 0, 1})) {
+		return false
+	}
+	wall := springWall
+	if s.Wall != nil {
+		wallRID := world.BlockRuntimeID(s.Wall)
+		wall = func(rid uint32) bool { return rid == wallRID }
+	}
+	if !wall(r.blockRID(pos)) || !wall(r.blockRID(pos.Side(cube.FaceUp))) || !wall(r.blockRID(pos.Side(cube.FaceDown))) {
+		return false
+	}
+	walls, open := 0, 0
+	for _, face := range cube.HorizontalFaces() {
+		switch rid := r.blockRID(pos.Side(face)); {
+		case wall(rid):
+			walls++
+		case rid == airRID:
+			open++
+		}
+	}
+	if walls != 3 || open != 1 {
+		return false
+	}
+	r.SetBlock(pos, s.Fluid)
+	return true
+}
+
+// springWall checks if the block with the runtime ID passed may surround a
+// spring.
+func springWall(rid uint32) bool {
+	return rid == stoneRID || rid == deepslateRID
+}
+
+// undergroundFeatures returns the ores, blobs and springs registered to a new
+// Overworld.
+func undergroundFeatures() map[string]FeaturePlacement {
+	ore := func(b func(t block.OreType) world.Block, size, count int, height HeightProvider) FeaturePlacement {
+		return FeaturePlacement{
+			Feature: OreFeature{Block: b(block.StoneOre()), Deepslate: b(block.DeepslateOre()), Size: size},
+			Stage:   StageUndergroundOres,
+			Count:   count,
+			Height:  height,
+		}
+	}
+	blob := func(b world.Block, size, count int, height HeightProvider) FeaturePlacement {
+		return FeaturePlacement{Feature: OreFeature{Block: b, Size: size}, Stage: StageUndergroundOres, Count: count, Height: height}
+	}
+	coal := func(t block.OreType) world.Block { return block.CoalOre{Type: t} }
+	iron := func(t block.OreType) world.Block { return block.IronOre{Type: t} }
+	copper := func(t block.OreType) world.Block { return block.CopperOre{Type: t} }
+	gold := func(t block.OreType) world.Block { return block.GoldOre{Type: t} }
+	lapis := func(t block.OreType) world.Block { return block.LapisOre{Type: t} }
+	diamond := func(t block.OreType) world.Block { return block.DiamondOre{Type: t} }
+	emerald := func(t block.OreType) world.Block { return block.EmeraldOre{Type: t} }
+
+	emeraldOre := ore(emerald, 3, 50, TriangleHeight{Min: -16, Max: 480})
+	emeraldOre.Biomes = mountainBiomes
+
+	return map[string]FeaturePlacement{
+		"dirt":          blob(block.Dirt{}, 33, 7, UniformHeight{Min: 0, Max: 160}),
+		"gravel":        blob(block.Gravel{}, 33, 14, UniformHeight{Min: -64, Max: 320}),
+		"granite":       blob(block.Granite{}, 64, 2, UniformHeight{Min: 0, Max: 60}),
+		"diorite":       blob(block.Diorite{}, 64, 2, UniformHeight{Min: 0, Max: 60}),
+		"andesite":      blob(block.Andesite{}, 64, 2, UniformHeight{Min: 0, Max: 60}),
+		"tuff":          blob(block.Tuff{}, 64, 2, UniformHeight{Min: -64, Max: 0}),
+		"coal_ore":      ore(coal, 17, 20, TriangleHeight{Min: 0, Max: 192}),
+		"coal_ore_high": ore(coal, 17, 30, UniformHeight{Min: 136, Max: 319}),
+		"iron_ore":      ore(iron, 9, 10, TriangleHeight{Min: -24, Max: 56}),
+		"iron_ore_high": ore(iron, 9, 90, TriangleHeight{Min: 80, Max: 384}),
+		"iron_ore_low":  ore(iron, 4, 10, UniformHeight{Min: -64, Max: 72}),
+		"copper_ore":    ore(copper, 10, 16, TriangleHeight{Min: -16, Max: 112}),
+		"gold_ore":      ore(gold, 9, 4, TriangleHeight{Min: -64, Max: 32}),
+		"lapis_ore":     ore(lapis, 7, 2, TriangleHeight{Min: -32, Max: 32}),
+		"lapis_ore_low": ore(lapis, 7, 4, UniformHeight{Min: -64, Max: 64}),
+		"diamond_ore":   ore(diamond, 4, 7, TriangleHeight{Min: -144, Max: 16}),
+		"emerald_ore":   emeraldOre,
+		"water_spring": {
+			Feature: SpringFeature{Fluid: block.Water{Depth: 8}},
+			Stage:   StageUndergroundDecoration,
+			Count:   25,
+			Height:  UniformHeight{Min: -64, Max: 192},
+		},
+		"lava_spring": {
+			Feature: SpringFeature{Fluid: block.Lava{Depth: 8}},
+			Stage:   StageUndergroundDecoration,
+			Count:   10,
+			Height:  UniformHeight{Min: -64, Max: 0},
+		},
+	}
+}
@@ -0,0 +1,334 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"golang.org/x/exp/maps"
+	"math"
+	"math/rand"
+)
+
+// seaLevel is the Y level up to which oceans and rivers are filled with water.
+const seaLevel = 63
+
+// Overworld is a noise based generator of overworld terrain. Continents,
+// oceans, mountains and rivers are shaped using multi-octave noise for the
+// continentalness, erosion and weirdness of the terrain. Each biome has its
+// own surface blocks and caves below sea level may be flooded by aquifers.
+// Overworld may be constructed by calling NewOverworld.
+type Overworld struct {
+	seed int64
+
+	continentalness, erosion, weirdness *OctaveNoise
+	temperature, humidity               *OctaveNoise
+	detail                              *OctaveNoise
+	surfaceDepth                        *Noise
+
+	aquifer *aquifer
+	carvers []carver
+
+	featureSet
+	structureCache
+	// strongholdStarts holds the chunk positions of the starts of all
+	// strongholds.
+	strongholdStarts []world.ChunkPos
+}
+
+// NewOverworld creates a new Overworld generator using the seed passed. The
+// same seed always produces the same terrain.
+func NewOverworld(seed int64) *Overworld {
+	r := rand.New(rand.NewSource(seed))
+	g := &Overworld{
+		seed:             seed,
+		continentalness:  NewOctaveNoise(r, 5, 1200),
+		erosion:          NewOctaveNoise(r, 4, 700),
+		weirdness:        NewOctaveNoise(r, 4, 400),
+		temperature:      NewOctaveNoise(r, 3, 1600),
+		humidity:         NewOctaveNoise(r, 3, 1300),
+		detail:           NewOctaveNoise(r, 3, 48),
+		surfaceDepth:     NewNoise(r),
+		aquifer:          newAquifer(seed),
+		strongholdStarts: strongholdPositions(seed),
+	}
+	g.carvers = []carver{newNoiseCaves(r, g.aquifer), ravines{seed: seed, a: g.aquifer}}
+
+	features := undergroundFeatures()
+	maps.Copy(features, vegetationFeatures())
+	g.registerAll(features)
+	return g
+}
+
+// Seed returns the seed of the Overworld generator.
+func (g *Overworld) Seed() int64 {
+	return g.seed
+}
+
+// GenerateChunk generates the terrain of the chunk at the position passed.
+// Structures are generated without their chests and villagers. GenerateColumn
+// should be used to generate these as well.
+func (g *Overworld) GenerateChunk(pos world.ChunkPos, c *chunk.Chunk) {
+	g.generate(pos, c, nil)
+}
+
+// GenerateColumn generates the terrain of the chunk of the world.Column at the
+// position passed. The chests and villagers of villages are added to the
+// world.Column.
+func (g *Overworld) GenerateColumn(pos world.ChunkPos, col *world.Column) {
+	g.generate(pos, col.Chunk, col)
+}
+
+// generate generates the terrain of the chunk at the position passed. If col
+// is not nil, block entities and entities generated are added to it.
+func (g *Overworld) generate(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	detail := sampleGrid(g.detail, baseX, baseZ, c.Range())
+
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			wx, wz := baseX+int(x), baseZ+int(z)
+			col := g.column(float64(wx), float64(wz))
+			g.generateColumn(c, x, z, wx, wz, col, detail, true)
+		}
+	}
+	for _, carver := range g.carvers {
+		carver.carve(pos, c)
+	}
+	g.decorate(g.seed, g, pos, c)
+	g.strongholds(pos, c, col)
+	g.villages(pos, c, col)
+}
+
+// column holds the terrain parameters of a single block column.
+type column struct {
+	// height is the approximate height of the terrain surface.
+	height float64
+	// continentalness, erosion and peaks are the terrain parameters sampled
+	// from noise. peaks ranges from -1 (valleys) to 1 (peaks).
+	continentalness, erosion, peaks float64
+	// mountains ranges from 0 to 1 and specifies how mountainous the terrain
+	// is. river ranges from 0 to 1 and specifies how close the column is to
+	// the centre of a river.
+	mountains, river float64
+	// temperature and humidity are the climate parameters of the column.
+	temperature, humidity float64
+	// biome is the world.Biome of the column.
+	biome world.Biome
+}
+
+// column computes the terrain parameters of the block column at the
+// coordinates passed.
+func (g *Overworld) column(x, z float64) column {
+	c := clamp(g.continentalness.Sample2D(x, z)*1.8, -1, 1)
+	e := clamp(g.erosion.Sample2D(x, z)*1.8, -1, 1)
+	w := clamp(g.weirdness.Sample2D(x, z)*1.8, -1, 1)
+	// Peaks and valleys are derived by folding the weirdness, so that values
+	// close to 0 form valleys and rivers and values around ±0.67 form peaks.
+	pv := 1 - math.Abs(3*math.Abs(w)-2)
+
+	var base float64
+	switch {
+	case c < -0.45:
+		base = 32 + (c+1)/0.55*12
+	case c < -0.18:
+		base = 44 + (c+0.45)/0.27*14
+	case c < -0.05:
+		base = 58 + (c+0.18)/0.13*6
+	default:
+		base = 64 + (c+0.05)*30
+	}
+	inland := clamp((c+0.05)/0.3, 0, 1)
+	mountains := clamp((0.1-e)/0.9, 0, 1)
+	mountains *= mountains * inland
+	peaks := (pv + 1) / 2
+
+	height := base + mountains*peaks*150 + inland*peaks*14*(1-mountains)
+
+	river := clamp(1-math.Abs(w)/0.07, 0, 1) * clamp((c+0.15)/0.1, 0, 1) * (1 - mountains*0.8)
+	height = lerp(river, height, seaLevel-4)
+
+	col := column{
+		height:          height,
+		continentalness: c,
+		erosion:         e,
+		peaks:           pv,
+		mountains:       mountains,
+		river:           river,
+		temperature:     clamp(g.temperature.Sample2D(x, z)*1.8, -1, 1),
+		humidity:        clamp(g.humidity.Sample2D(x, z)*1.8, -1, 1),
+	}
+	col.biome = selectBiome(col)
+	return col
+}
+
+var (
+	airRID       = world.BlockRuntimeID(block.Air{})
+	stoneRID     = world.BlockRuntimeID(block.Stone{})
+	deepslateRID = world.BlockRuntimeID(block.Deepslate{})
+	bedrockRID   = world.BlockRuntimeID(block.Bedrock{})
+	waterRID     = world.BlockRuntimeID(block.Water{Still: true, Depth: 8})
+	lavaRID      = world.BlockRuntimeID(block.Lava{Still: true, Depth: 8})
+)
+
+// generateColumn generates the blocks of a single column of a chunk, from the
+// top of the chunk down to the bottom. The biomes of the column are only set
+// if biomes is true.
+func (g *Overworld) generateColumn(c *chunk.Chunk, x, z uint8, wx, wz int, col column, detail noiseGrid, biomes bool) {
+	r := c.Range()
+	biome := uint32(col.biome.EncodeBiome())
+	rule := surfaceRuleOf(col.biome)
+	depth := 3 + int(g.surfaceDepth.Sample2D(float64(wx)/8, float64(wz)/8)*2+1)
+
+	squash, amplitude, top := terrainShape(col)
+
+	// covered is true once a solid block was placed in the column, after which
+	// empty space is underground and filled by aquifers instead of the sea.
+	covered, underwater := false, false
+	fillerLeft, subLeft := 0, 0
+	for y := int16(r.Max()); y >= int16(r.Min()); y-- {
+		if biomes {
+			c.SetBiome(x, y, z, biome)
+		}
+		if int(y) > top && int(y) > seaLevel {
+			continue
+		}
+		if bedrockDepth := int(y) - r.Min(); bedrockDepth < 5 && positionHash(g.seed, wx, int(y), wz)%5 >= uint64(bedrockDepth) {
+			c.SetBlock(x, y, z, 0, bedrockRID)
+			continue
+		}
+		density := (col.height-float64(y))/squash + detail.at(int(x), int(y), int(z))*amplitude
+		if density <= 0 {
+			if !covered && int(y) <= seaLevel {
+				c.SetBlock(x, y, z, 0, waterRID)
+				underwater = true
+			} else if covered {
+				if rid, ok := g.aquifer.fluidAt(wx, int(y), wz); ok {
+					c.SetBlock(x, y, z, 0, rid)
+				}
+			}
+			continue
+		}
+		switch {
+		case !covered:
+			covered, fillerLeft = true, depth-1
+			if rule.sub != 0 {
+				subLeft = depth
+			}
+			if underwater {
+				c.SetBlock(x, y, z, 0, rule.underwater)
+			} else {
+				c.SetBlock(x, y, z, 0, rule.top)
+			}
+		case fillerLeft > 0:
+			fillerLeft--
+			c.SetBlock(x, y, z, 0, rule.filler)
+		case subLeft > 0:
+			subLeft--
+			c.SetBlock(x, y, z, 0, rule.sub)
+		case y < 0 || (y < 8 && positionHash(g.seed, wx, int(y), wz)%8 >= uint64(y)):
+			c.SetBlock(x, y, z, 0, deepslateRID)
+		default:
+			c.SetBlock(x, y, z, 0, stoneRID)
+		}
+	}
+}
+
+// terrainShape returns the vertical squash and the amplitude of the detail
+// noise of the terrain of a column, and the Y level above which the column
+// holds no solid blocks.
+func terrainShape(col column) (squash, amplitude float64, top int) {
+	squash = 8 + col.mountains*24
+	amplitude = 0.35 + col.mountains*0.65
+	return squash, amplitude, int(math.Ceil(col.height + squash*amplitude))
+}
+
+// surfaceLevel returns the Y level of the highest block that is not air in a
+// column, as it would be generated by generateColumn, without generating the
+// column. detail returns the detail noise of the column at a Y level.
+func surfaceLevel(col column, detail func(y int) float64, r cube.Range) int {
+	squash, amplitude, top := terrainShape(col)
+	for y := min(top, r.Max()); y > seaLevel; y-- {
+		if (col.height-float64(y))/squash+detail(y)*amplitude > 0 {
+			return y
+		}
+	}
+	return seaLevel
+}
+
+// surfaceAt returns the Y level of the highest block that is not air at the X
+// and Z coordinates passed, before the terrain is carved and decorated. Unlike
+// the height of a generated chunk, surfaceAt may be used for any position
+// without generating the chunk holding it.
+func (g *Overworld) surfaceAt(x, z int, r cube.Range) int {
+	return surfaceLevel(g.column(float64(x), float64(z)), func(y int) float64 {
+		return sampleAt(g.detail, x, y, z, r)
+	}, r)
+}
+
+// biomeAt ...
+func (g *Overworld) biomeAt(x, z int) world.Biome {
+	return g.column(float64(x), float64(z)).biome
+}
+
+// detailGrid ...
+func (g *Overworld) detailGrid(pos world.ChunkPos, r cube.Range) noiseGrid {
+	return sampleGrid(g.detail, int(pos[0])<<4, int(pos[1])<<4, r)
+}
+
+// terrainColumn ...
+func (g *Overworld) terrainColumn(c *chunk.Chunk, x, z int, detail noiseGrid) {
+	g.generateColumn(c, uint8(x&15), uint8(z&15), x, z, g.column(float64(x), float64(z)), detail, false)
+}
+
+// columnSurface ...
+func (g *Overworld) columnSurface(x, z int, detail noiseGrid, r cube.Range) int {
+	return surfaceLevel(g.column(float64(x), float64(z)), func(y int) float64 {
+		return detail.at(x&15, y, z&15)
+	}, r)
+}
+
+// aquifer determines the fluid that fills empty underground space. The world
+// is divided into cells that each have their own fluid level, so that caves
+// below sea level may be dry, flooded with water or, deep underground,
+// flooded with lava.
+type aquifer struct {
+	seed int64
+}
+
+const (
+	// aquiferCellWidth and aquiferCellHeight are the horizontal and vertical
+	// sizes of the cells of an aquifer.
+	aquiferCellWidth, aquiferCellHeight = 16, 12
+	// lavaLevel is the Y level at and below which all empty underground space
+	// is filled with lava.
+	lavaLevel = -54
+)
+
+// newAquifer creates a new aquifer using the seed passed.
+func newAquifer(seed int64) *aquifer {
+	return &aquifer{seed: seed}
+}
+
+// fluidAt returns the runtime ID of the fluid at the position passed, if any.
+func (a *aquifer) fluidAt(x, y, z int) (uint32, bool) {
+	if y <= lavaLevel {
+		return lavaRID, true
+	}
+	cx := int64(math.Floor(float64(x) / aquiferCellWidth))
+	cy := int64(math.Floor(float64(y) / aquiferCellHeight))
+	cz := int64(math.Floor(float64(z) / aquiferCellWidth))
+	h := positionHash(a.seed, int(cx), int(cy), int(cz))
+	if h%3 != 0 {
+		// Most aquifer cells are dry.
+		return 0, false
+	}
+	level := int(cy)*aquiferCellHeight + int(h>>8%aquiferCellHeight)
+	if y > level {
+		return 0, false
+	}
+	if level < -20 && h>>16%4 == 0 {
+		return lavaRID, true
+	}
+	return waterRID, true
+}
@@ -0,0 +1,318 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"math"
+	"strings"
+)
+
+const (
+	// strongholdCount is the number of strongholds in a world.
+	strongholdCount = 128
+	// strongholdDistance is the distance in chunks between the rings in
+	// which strongholds are placed.
+	strongholdDistance = 32
+	// strongholdReach is the maximum horizontal distance in blocks from its
+	// start that any piece of a stronghold reaches.
+	strongholdReach = 112
+	// strongholdAttempts is the number of attempts made to assemble a
+	// stronghold that includes a portal room.
+	strongholdAttempts = 10
+)
+
+// strongholdPositions returns the chunk positions of the starts of all
+// strongholds of a world with the seed passed. Strongholds are placed in rings
+// around the centre of the world. The first ring holds three strongholds, and
+// every next ring lies further away and holds more strongholds.
+func strongholdPositions(seed int64) []world.ChunkPos {
+	rnd := newRand(positionHash(seed, 0, 0x57a0, 0))
+	positions := make([]world.ChunkPos, 0, strongholdCount)
+
+	angle := rnd.Float64() * math.Pi * 2
+	ring, spread, placed := 0, 3, 0
+	for i := 0; i < strongholdCount; i++ {
+		dist := float64(4*strongholdDistance+strongholdDistance*ring*6) + (rnd.Float64()-0.5)*strongholdDistance*2.5
+		positions = append(positions, world.ChunkPos{int32(math.Round(math.Cos(angle) * dist)), int32(math.Round(math.Sin(angle) * dist))})
+
+		angle += math.Pi * 2 / float64(spread)
+		if placed++; placed == spread {
+			ring, placed = ring+1, 0
+			spread = min(spread+2*spread/(ring+1), strongholdCount-i-1)
+			angle += rnd.Float64() * math.Pi * 2
+		}
+	}
+	return positions
+}
+
+// strongholds places the parts of all strongholds that intersect with the
+// chunk at the position passed. If col is not nil, the chests of the
+// strongholds are added to it.
+func (g *Overworld) strongholds(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	reach := int32(strongholdReach>>4 + 1)
+	for i, start := range g.strongholdStarts {
+		if start[0] < pos[0]-reach || start[0] > pos[0]+reach || start[1] < pos[1]-reach || start[1] > pos[1]+reach {
+			continue
+		}
+		g.stronghold(i, c.Range()).place(pos, c, col)
+	}
+}
+
+// stronghold returns the stronghold with the index passed.
+func (g *Overworld) stronghold(index int, r cube.Range) *structure {
+	start := g.strongholdStarts[index]
+	return g.cachedStructure(structureKey{name: "stronghold", pos: start}, func() *structure {
+		return g.assembleStronghold(start, r)
+	})
+}
+
+// assembleStronghold assembles the stronghold with its start in the chunk
+// passed. The stronghold is placed underground, below the surface of the
+// terrain at its start.
+func (g *Overworld) assembleStronghold(pos world.ChunkPos, r cube.Range) *structure {
+	x, z := int(pos[0])<<4+8, int(pos[1])<<4+8
+	rnd := newRand(positionHash(g.seed, int(pos[0]), 0x57a0, int(pos[1])))
+	y := min(10+rnd.Intn(30), g.surfaceAt(x, z, r)-strongholdCrossing.height-8)
+
+	j := jigsaw{pools: strongholdPools, maxDepth: 8, maxDistance: strongholdReach, place: func(p piece, target cube.Pos) (int, bool) {
+		// Pieces must stay buried below the surface and above the bedrock at
+		// the bottom of the world.
+		ok := target[1]-1 > r.Min()+4 && target[1]+p.t.height < g.surfaceAt(target[0], target[2], r)-4
+		return target[1], ok
+	}}
+	var pieces []piece
+	for i := 0; i < strongholdAttempts; i++ {
+		start := newPiece(strongholdCrossing, rnd.Intn(4), rnd.Uint64())
+		start.x, start.y, start.z = x-start.sizeX/2, y, z-start.sizeZ/2
+		if pieces = j.assemble(start, rnd); count(pieces, strongholdPortalRoom) > 0 {
+			break
+		}
+	}
+	return &structure{pieces: pieces, palette: strongholdPalette{}}
+}
+
+// locateStronghold returns the position of the start of the stronghold
+// nearest to the position passed.
+func (g *Overworld) locateStronghold(pos cube.Pos, r cube.Range) (cube.Pos, bool) {
+	nearest := -1
+	for i, start := range g.strongholdStarts {
+		if nearest == -1 || horizontalDistSq(pos, strongholdCentre(start)) < horizontalDistSq(pos, strongholdCentre(g.strongholdStarts[nearest])) {
+			nearest = i
+		}
+	}
+	if nearest == -1 {
+		return cube.Pos{}, false
+	}
+	centre := strongholdCentre(g.strongholdStarts[nearest])
+	centre[1] = g.stronghold(nearest, r).pieces[0].y
+	return centre, true
+}
+
+// strongholdCentre returns the horizontal centre of the start of a stronghold
+// in the chunk passed.
+func strongholdCentre(pos world.ChunkPos) cube.Pos {
+	return cube.Pos{int(pos[0])<<4 + 8, 0, int(pos[1])<<4 + 8}
+}
+
+// strongholdPalette is the palette of strongholds.
+type strongholdPalette struct{}
+
+// block ...
+func (strongholdPalette) block(ch byte, p piece, pos cube.Pos) (world.Block, bool) {
+	switch ch {
+	case ' ':
+		return nil, false
+	case '.':
+		return nil, true
+	case 'B':
+		// Stone bricks of strongholds are randomly mossy or cracked.
+		switch positionHash(int64(p.seed), pos[0], pos[1], pos[2]) % 10 {
+		case 0, 1:
+			return block.StoneBricks{Type: block.MossyStoneBricks()}, true
+		case 2, 3:
+			return block.StoneBricks{Type: block.CrackedStoneBricks()}, true
+		}
+		return block.StoneBricks{}, true
+	case 'b':
+		return block.Bookshelf{}, true
+	case 'I':
+		return block.IronBars{}, true
+	case 'T':
+		return block.Torch{Facing: cube.FaceDown, Type: block.NormalFire()}, true
+	case 'l':
+		return block.Lava{Still: true, Depth: 8}, true
+	case 'C':
+		c := block.NewChest()
+		c.Facing, c.LootTable = cube.South, "chests/stronghold_"+strings.TrimPrefix(p.t.name, "chest_")
+		return c, true
+	case '^', 'v', '<', '>':
+		// About one in ten end portal frames already holds an eye of ender.
+		eye := positionHash(int64(p.seed), pos[0], pos[1], pos[2])%10 == 0
+		return block.EndPortalFrame{Facing: map[byte]cube.Direction{'^': cube.North, 'v': cube.South, '<': cube.West, '>': cube.East}[ch], Eye: eye}, true
+	}
+	return nil, false
+}
+
+// entity ...
+func (strongholdPalette) entity(byte, piece, cube.Pos) (world.Entity, bool) {
+	return nil, false
+}
+
+// foundation ...
+func (strongholdPalette) foundation() world.Block {
+	return nil
+}
+
+// strongholdRoom creates a template of a stronghold room with a floor, walls
+// and a ceiling of stone bricks around an empty interior. An opening of 3x3
+// blocks is made in the walls for every connector passed. decorate is called
+// to place further blocks in the room.
+func strongholdRoom(name string, weight, limit, width, height, depth int, decorate func(set func(x, y, z int, ch byte)), connectors ...connector) *template {
+	t := buildTemplate(name, weight, width, height, depth, '.', func(set func(x, y, z int, ch byte)) {
+		fillBox(set, 0, 0, 0, width-1, 0, depth-1, 'B')
+		fillBox(set, 0, height-1, 0, width-1, height-1, depth-1, 'B')
+		fillBox(set, 0, 0, 0, 0, height-1, depth-1, 'B')
+		fillBox(set, width-1, 0, 0, width-1, height-1, depth-1, 'B')
+		fillBox(set, 0, 0, 0, width-1, height-1, 0, 'B')
+		fillBox(set, 0, 0, depth-1, width-1, height-1, depth-1, 'B')
+		for _, c := range connectors {
+			if c.facing == cube.North || c.facing == cube.South {
+				fillBox(set, c.x-1, c.y, c.z, c.x+1, c.y+2, c.z, '.')
+			} else {
+				fillBox(set, c.x, c.y, c.z-1, c.x, c.y+2, c.z+1, '.')
+			}
+		}
+		if decorate != nil {
+			decorate(set)
+		}
+	}, connectors...)
+	t.limit = limit
+	return t
+}
+
+// corridor returns the two connectors of a corridor of the depth passed that
+// leads from north to south through a room of the width passed.
+func corridor(width, depth int) []connector {
+	return []connector{
+		{x: width / 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+		{x: width / 2, y: 1, z: depth - 1, facing: cube.South, pool: "corridors"},
+	}
+}
+
+// strongholdCrossing is the crossing that every stronghold starts from, with
+// corridors leading in all four directions.
+var strongholdCrossing = strongholdRoom("crossing", 3, 0, 11, 7, 11, func(set func(x, y, z int, ch byte)) {
+	for y := 1; y < 6; y++ {
+		set(5, y, 5, 'B')
+	}
+	set(1, 1, 1, 'T')
+	set(9, 1, 9, 'T')
+	set(1, 1, 9, 'T')
+	set(9, 1, 1, 'T')
+},
+	connector{x: 5, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+	connector{x: 5, y: 1, z: 10, facing: cube.South, pool: "corridors"},
+	connector{x: 0, y: 1, z: 5, facing: cube.West, pool: "corridors"},
+	connector{x: 10, y: 1, z: 5, facing: cube.East, pool: "corridors"},
+)
+
+// strongholdPortalRoom is the room holding the end portal, which is found
+// once in every stronghold.
+var strongholdPortalRoom = strongholdRoom("portal_room", 2, 1, 11, 8, 16, func(set func(x, y, z int, ch byte)) {
+	// The portal lies on a raised platform, with a pool of lava below it.
+	for x := 3; x <= 7; x++ {
+		for z := 2; z <= 6; z++ {
+			set(x, 1, z, 'B')
+		}
+	}
+	for x := 4; x <= 6; x++ {
+		for z := 3; z <= 5; z++ {
+			set(x, 1, z, 'l')
+		}
+		set(x, 2, 2, 'v')
+		set(x, 2, 6, '^')
+	}
+	for z := 3; z <= 5; z++ {
+		set(3, 2, z, '>')
+		set(7, 2, z, '<')
+	}
+	// Lava pools along the walls and barred windows light up the room.
+	for z := 8; z <= 12; z++ {
+		set(1, 1, z, 'l')
+		set(9, 1, z, 'l')
+	}
+	for _, z := range []int{4, 10} {
+		for y := 3; y <= 4; y++ {
+			set(0, y, z, 'I')
+			set(10, y, z, 'I')
+		}
+	}
+	set(2, 1, 13, 'T')
+	set(8, 1, 13, 'T')
+}, connector{x: 5, y: 1, z: 15, facing: cube.South, pool: "corridors"})
+
+// strongholdPools holds the pools of templates that strongholds are assembled
+// from.
+var strongholdPools = map[string][]*template{
+	"corridors": {
+		strongholdRoom("corridor", 8, 0, 5, 5, 7, nil, corridor(5, 7)...),
+		strongholdRoom("chest_corridor", 2, 0, 5, 5, 7, func(set func(x, y, z int, ch byte)) {
+			set(1, 1, 3, 'C')
+		}, corridor(5, 7)...),
+		strongholdRoom("turn", 3, 0, 5, 5, 5, nil,
+			connector{x: 2, y: 1, z: 4, facing: cube.South, pool: "corridors"},
+			connector{x: 0, y: 1, z: 2, facing: cube.West, pool: "corridors"},
+		),
+		strongholdCrossing,
+		strongholdRoom("stairs", 3, 0, 5, 11, 8, func(set func(x, y, z int, ch byte)) {
+			// The stairs lead down from the entrance on the south to the
+			// exit on the north, one block for every block travelled.
+			for x := 1; x <= 3; x++ {
+				for z := 1; z <= 6; z++ {
+					for y := 1; y < z; y++ {
+						set(x, y, z, 'B')
+					}
+				}
+			}
+		},
+			connector{x: 2, y: 1, z: 0, facing: cube.North, pool: "corridors"},
+			connector{x: 2, y: 6, z: 7, facing: cube.South, pool: "corridors"},
+		),
+		strongholdRoom("prison", 2, 0, 9, 5, 11, func(set func(x, y, z int, ch byte)) {
+			for z := 1; z <= 9; z++ {
+				for y := 1; y <= 3; y++ {
+					set(3, y, z, 'I')
+					set(5, y, z, 'I')
+					if z == 5 {
+						set(1, y, z, 'B')
+						set(2, y, z, 'B')
+						set(6, y, z, 'B')
+						set(7, y, z, 'B')
+					}
+				}
+			}
+		}, corridor(9, 11)...),
+		strongholdRoom("library", 1, 2, 13, 9, 15, func(set func(x, y, z int, ch byte)) {
+			for y := 1; y <= 5; y++ {
+				for i := 1; i <= 11; i++ {
+					set(i, y, 1, 'b')
+				}
+				for z := 1; z <= 11; z++ {
+					set(1, y, z, 'b')
+					set(11, y, z, 'b')
+				}
+			}
+			for z := 3; z <= 9; z++ {
+				for y := 1; y <= 3; y++ {
+					set(4, y, z, 'b')
+					set(8, y, z, 'b')
+				}
+			}
+			set(6, 1, 2, 'C')
+			set(6, 1, 11, 'T')
+		}, connector{x: 6, y: 1, z: 14, facing: cube.South, pool: "corridors"}),
+		strongholdPortalRoom,
+	},
+}
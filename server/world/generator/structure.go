@@ -0,0 +1,628 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"math/rand"
+	"sync"
+)
+
+// template is a piece of a structure, such as a house or a street. Templates
+// are defined as layers of characters, from the bottom layer to the top. Every
+// layer holds rows from north to south, and every row holds characters from
+// west to east. The characters are mapped to blocks by a palette once the
+// template is placed, so that the same template may be used for structures
+// built from different blocks.
+type template struct {
+	name                 string
+	weight               int
+	width, height, depth int
+	cells                []byte
+	connectors           []connector
+	// limit is the maximum number of pieces of the template in a single
+	// structure. If 0, the number of pieces is not limited.
+	limit int
+	// terrain specifies if the template follows the terrain. The bottom layer
+	// of such a template replaces the surface of every column, regardless of
+	// the Y level of the piece, which is used for templates such as streets.
+	terrain bool
+}
+
+// connector is a point on the edge of a template to which other templates
+// may be attached.
+type connector struct {
+	x, y, z int
+	// facing is the direction in which the connector faces, away from the
+	// template.
+	facing cube.Direction
+	// pool is the name of the pool of templates that may be attached.
+	pool string
+}
+
+// newTemplate creates a new template from the layers passed. newTemplate
+// panics if not all layers and rows have the same size.
+func newTemplate(name string, weight int, layers [][]string, connectors ...connector) *template {
+	t := &template{name: name, weight: weight, height: len(layers), depth: len(layers[0]), width: len(layers[0][0]), connectors: connectors}
+	t.cells = make([]byte, 0, t.width*t.height*t.depth)
+	for _, layer := range layers {
+		if len(layer) != t.depth {
+			panic(fmt.Sprintf("template %v: layer has %v rows, expected %v", name, len(layer), t.depth))
+		}
+		for _, row := range layer {
+			if len(row) != t.width {
+				panic(fmt.Sprintf("template %v: row %q has %v columns, expected %v", name, row, len(row), t.width))
+			}
+			t.cells = append(t.cells, row...)
+		}
+	}
+	return t
+}
+
+// buildTemplate creates a template of the size passed with all of its cells
+// set to the character passed. build is called to set the characters of the
+// template. The Y level passed to set is that of the layer, counted from the
+// bottom of the template.
+func buildTemplate(name string, weight, width, height, depth int, fill byte, build func(set func(x, y, z int, ch byte)), connectors ...connector) *template {
+	cells := make([][][]byte, height)
+	for y := range cells {
+		cells[y] = make([][]byte, depth)
+		for z := range cells[y] {
+			cells[y][z] = bytes.Repeat([]byte{fill}, width)
+		}
+	}
+	if build != nil {
+		build(func(x, y, z int, ch byte) {
+			cells[y][z][x] = ch
+		})
+	}
+	layers := make([][]string, height)
+	for y := range cells {
+		for _, row := range cells[y] {
+			layers[y] = append(layers[y], string(row))
+		}
+	}
+	return newTemplate(name, weight, layers, connectors...)
+}
+
+// fillBox sets all cells in the box between the two corners passed to the
+// character passed, using the set function passed to the build function of
+// buildTemplate.
+func fillBox(set func(x, y, z int, ch byte), x0, y0, z0, x1, y1, z1 int, ch byte) {
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			for z := z0; z <= z1; z++ {
+				set(x, y, z, ch)
+			}
+		}
+	}
+}
+
+// at returns the character at the template relative coordinates passed.
+func (t *template) at(x, y, z int) byte {
+	return t.cells[(y*t.depth+z)*t.width+x]
+}
+
+// piece is a template placed in the world with a rotation.
+type piece struct {
+	t *template
+	// turns is the number of clockwise quarter turns that the template is
+	// rotated by.
+	turns int
+	// x, y and z are the world coordinates of the minimum corner of the piece.
+	x, y, z int
+	// sizeX and sizeZ are the sizes of the rotated piece.
+	sizeX, sizeZ int
+	// seed is used for all randomness in the blocks of the piece.
+	seed uint64
+}
+
+// newPiece creates a piece of the template passed, rotated by the number of
+// clockwise quarter turns passed.
+func newPiece(t *template, turns int, seed uint64) piece {
+	p := piece{t: t, turns: turns & 3, sizeX: t.width, sizeZ: t.depth, seed: seed}
+	if p.turns%2 == 1 {
+		p.sizeX, p.sizeZ = t.depth, t.width
+	}
+	return p
+}
+
+// rotate converts template relative X and Z coordinates to offsets from the
+// minimum corner of the piece.
+func (p piece) rotate(x, z int) (int, int) {
+	switch p.turns {
+	case 1:
+		return p.t.depth - 1 - z, x
+	case 2:
+		return p.t.width - 1 - x, p.t.depth - 1 - z
+	case 3:
+		return z, p.t.width - 1 - x
+	}
+	return x, z
+}
+
+// local converts offsets from the minimum corner of the piece to template
+// relative X and Z coordinates. local is the inverse of rotate.
+func (p piece) local(dx, dz int) (int, int) {
+	switch p.turns {
+	case 1:
+		return dz, p.t.depth - 1 - dx
+	case 2:
+		return p.t.width - 1 - dx, p.t.depth - 1 - dz
+	case 3:
+		return p.t.width - 1 - dz, dx
+	}
+	return dx, dz
+}
+
+// facing rotates the template relative direction passed by the rotation of the
+// piece.
+func (p piece) facing(d cube.Direction) cube.Direction {
+	for i := 0; i < p.turns; i++ {
+		d = d.RotateRight()
+	}
+	return d
+}
+
+// connectorPos returns the world position of the connector passed.
+func (p piece) connectorPos(c connector) cube.Pos {
+	dx, dz := p.rotate(c.x, c.z)
+	return cube.Pos{p.x + dx, p.y + c.y, p.z + dz}
+}
+
+// overlaps checks if the horizontal area of the piece overlaps with that of
+// the piece passed.
+func (p piece) overlaps(o piece) bool {
+	return p.x < o.x+o.sizeX && o.x < p.x+p.sizeX && p.z < o.z+o.sizeZ && o.z < p.z+p.sizeZ
+}
+
+// intersects checks if the piece intersects with the chunk at the position
+// passed.
+func (p piece) intersects(pos world.ChunkPos) bool {
+	minX, minZ := int(pos[0])<<4, int(pos[1])<<4
+	return p.x < minX+16 && minX < p.x+p.sizeX && p.z < minZ+16 && minZ < p.z+p.sizeZ
+}
+
+// structure is a structure assembled out of pieces, such as a village.
+type structure struct {
+	pieces  []piece
+	palette palette
+}
+
+// place places the parts of the structure that intersect with the chunk at
+// the position passed. If col is not nil, block entities and entities of the
+// structure are added to it.
+func (s *structure) place(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	for _, p := range s.pieces {
+		if p.intersects(pos) {
+			placePiece(pos, c, col, p, s.palette)
+		}
+	}
+}
+
+// LocateStructure returns the position of the start of the structure with the
+// name passed that is nearest to the position passed. Overworld generates
+// structures named "village" and "stronghold".
+func (g *Overworld) LocateStructure(name string, pos cube.Pos) (cube.Pos, bool) {
+	switch name {
+	case "village":
+		return g.locateVillage(pos, world.Overworld.Range())
+	case "stronghold":
+		return g.locateStronghold(pos, world.Overworld.Range())
+	}
+	return cube.Pos{}, false
+}
+
+// structureKey identifies a structure in a structureCache.
+type structureKey struct {
+	name string
+	pos  world.ChunkPos
+}
+
+// maxCachedStructures is the maximum number of structures cached by a
+// structureCache before it is cleared.
+const maxCachedStructures = 1024
+
+// structureCache caches the structures of a generator, so that they are only
+// assembled once for all chunks that they intersect with.
+type structureCache struct {
+	structureMu sync.Mutex
+	cached      map[structureKey]*structure
+}
+
+// cachedStructure returns the structure with the key passed. If the structure
+// is not yet cached, it is assembled by calling the function passed, which may
+// return nil if there is no structure.
+func (s *structureCache) cachedStructure(key structureKey, assemble func() *structure) *structure {
+	s.structureMu.Lock()
+	defer s.structureMu.Unlock()
+	if st, ok := s.cached[key]; ok {
+		return st
+	}
+	if s.cached == nil || len(s.cached) >= maxCachedStructures {
+		s.cached = make(map[structureKey]*structure)
+	}
+	st := assemble()
+	s.cached[key] = st
+	return st
+}
+
+// locateInRegions returns the position of the start of the structure nearest
+// to the position passed, for structures of which at most one is placed in
+// every region of spacing by spacing chunks. Rings of regions around the
+// position are searched up to the radius passed. start returns the position
+// of the start of the structure in a region, if the region has one.
+func locateInRegions(pos cube.Pos, spacing, maxRadius int, start func(rx, rz int) (cube.Pos, bool)) (cube.Pos, bool) {
+	rx, rz := floorDiv(pos[0]>>4, spacing), floorDiv(pos[2]>>4, spacing)
+	found, foundRadius, nearest := false, 0, cube.Pos{}
+	for radius := 0; radius <= maxRadius; radius++ {
+		// A structure in the ring of regions after the one in which the first
+		// structure was found may still be closer, but structures further
+		// away never are.
+		if found && radius > foundRadius+1 {
+			break
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				if max(abs(dx), abs(dz)) != radius {
+					continue
+				}
+				p, ok := start(rx+dx, rz+dz)
+				if ok && (!found || horizontalDistSq(pos, p) < horizontalDistSq(pos, nearest)) {
+					if !found {
+						foundRadius = radius
+					}
+					found, nearest = true, p
+				}
+			}
+		}
+	}
+	return nearest, found
+}
+
+// horizontalDistSq returns the squared horizontal distance between two
+// positions.
+func horizontalDistSq(a, b cube.Pos) int {
+	dx, dz := a[0]-b[0], a[2]-b[2]
+	return dx*dx + dz*dz
+}
+
+// jigsaw assembles structures out of templates. Starting with a single piece,
+// templates from pools are attached to the open connectors of the pieces
+// placed, until no connectors are left open or the maximum depth is reached.
+type jigsaw struct {
+	// pools holds the pools of templates by their names.
+	pools map[string][]*template
+	// maxDepth is the maximum number of pieces between the start piece and
+	// any other piece. Once reached, only templates without further
+	// connectors are attached.
+	maxDepth int
+	// maxDistance is the maximum horizontal distance from the start piece
+	// that any piece may reach.
+	maxDistance int
+	// place returns the Y level of a piece whose connector is attached at the
+	// position passed and checks if the piece may be placed there. The Y
+	// level of the position passed is that of the connector it is attached
+	// to.
+	place func(p piece, target cube.Pos) (int, bool)
+}
+
+// assemble assembles a structure from the start piece passed.
+func (j jigsaw) assemble(start piece, rnd *rand.Rand) []piece {
+	type open struct {
+		piece int
+		c     connector
+		depth int
+	}
+	pieces := []piece{start}
+	var queue []open
+	for _, c := range start.t.connectors {
+		queue = append(queue, open{c: c})
+	}
+	centreX, centreZ := start.x+start.sizeX/2, start.z+start.sizeZ/2
+
+	for len(queue) > 0 {
+		o := queue[0]
+		queue = queue[1:]
+		parent := pieces[o.piece]
+		facing := parent.facing(o.c.facing)
+		target := parent.connectorPos(o.c).Side(facing.Face())
+
+	attach:
+		for _, t := range j.shuffle(j.pools[o.c.pool], rnd) {
+			if o.depth+1 >= j.maxDepth && len(t.connectors) > 1 {
+				continue
+			}
+			if t.limit > 0 && count(pieces, t) >= t.limit {
+				continue
+			}
+			first := rnd.Intn(4)
+			for i := 0; i < 4; i++ {
+				child := newPiece(t, first+i, rnd.Uint64())
+				for ci, c := range t.connectors {
+					if child.facing(c.facing) != facing.Opposite() {
+						continue
+					}
+					dx, dz := child.rotate(c.x, c.z)
+					child.x, child.z = target[0]-dx, target[2]-dz
+					if !j.fits(child, pieces, centreX, centreZ) {
+						continue
+					}
+					y, ok := j.place(child, target)
+					if !ok {
+						continue
+					}
+					child.y = y - c.y
+
+					pieces = append(pieces, child)
+					for oi, other := range t.connectors {
+						if oi != ci {
+							queue = append(queue, open{piece: len(pieces) - 1, c: other, depth: o.depth + 1})
+						}
+					}
+					break attach
+				}
+			}
+		}
+	}
+	return pieces
+}
+
+// count returns the number of pieces of the template passed.
+func count(pieces []piece, t *template) (n int) {
+	for _, p := range pieces {
+		if p.t == t {
+			n++
+		}
+	}
+	return n
+}
+
+// fits checks if the piece passed does not overlap with any of the pieces
+// already placed and lies within the maximum distance from the centre passed.
+func (j jigsaw) fits(p piece, pieces []piece, centreX, centreZ int) bool {
+	if abs(p.x-centreX) > j.maxDistance || abs(p.x+p.sizeX-centreX) > j.maxDistance ||
+		abs(p.z-centreZ) > j.maxDistance || abs(p.z+p.sizeZ-centreZ) > j.maxDistance {
+		return false
+	}
+	for _, other := range pieces {
+		if p.overlaps(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// shuffle returns the templates passed in a random order, in which templates
+// with a higher weight are more likely to come first.
+func (j jigsaw) shuffle(templates []*template, rnd *rand.Rand) []*template {
+	left := append([]*template(nil), templates...)
+	ordered := make([]*template, 0, len(left))
+	for len(left) > 0 {
+		total := 0
+		for _, t := range left {
+			total += t.weight
+		}
+		n := rnd.Intn(total)
+		for i, t := range left {
+			if n -= t.weight; n < 0 {
+				ordered = append(ordered, t)
+				left = append(left[:i], left[i+1:]...)
+				break
+			}
+		}
+	}
+	return ordered
+}
+
+// palette maps the characters of templates to the blocks and entities placed.
+type palette interface {
+	// block returns the block placed for the character passed at the world
+	// position passed. If false is returned, the block at that position is
+	// left unchanged. A nil block is placed as air.
+	block(ch byte, p piece, pos cube.Pos) (world.Block, bool)
+	// entity returns the entity spawned for the character passed at the world
+	// position passed, if any.
+	entity(ch byte, p piece, pos cube.Pos) (world.Entity, bool)
+	// foundation returns the block placed below a piece to fill the space
+	// between its bottom layer and the terrain. If nil, no foundation is
+	// placed.
+	foundation() world.Block
+}
+
+// maxFoundationDepth is the maximum number of blocks filled below a piece to
+// connect it to the terrain.
+const maxFoundationDepth = 12
+
+// placePiece places the part of the piece passed that lies within the chunk
+// at the position passed. If col is not nil, block entities and entities of
+// the piece are added to it.
+func placePiece(pos world.ChunkPos, c *chunk.Chunk, col *world.Column, p piece, pal palette) {
+	minX, minZ := int(pos[0])<<4, int(pos[1])<<4
+	for x := max(p.x, minX); x < min(p.x+p.sizeX, minX+16); x++ {
+		for z := max(p.z, minZ); z < min(p.z+p.sizeZ, minZ+16); z++ {
+			lx, lz := p.local(x-p.x, z-p.z)
+			if p.t.terrain {
+				placeTerrainColumn(c, x, z, p, p.t.at(lx, 0, lz), pal)
+				continue
+			}
+			placeColumn(c, col, x, z, lx, lz, p, pal)
+		}
+	}
+}
+
+// placeColumn places a single column of a piece that does not follow the
+// terrain.
+func placeColumn(c *chunk.Chunk, col *world.Column, x, z, lx, lz int, p piece, pal palette) {
+	r := c.Range()
+	cx, cz := uint8(x&15), uint8(z&15)
+	if ch := p.t.at(lx, 0, lz); ch != ' ' && ch != '.' && pal.foundation() != nil {
+		foundation := world.BlockRuntimeID(pal.foundation())
+		for y := p.y - 1; y > max(p.y-maxFoundationDepth, r.Min()) && !solidGround(c.Block(cx, int16(y), cz, 0)); y-- {
+			c.SetBlock(cx, int16(y), cz, 0, foundation)
+		}
+	}
+	for ly := 0; ly < p.t.height; ly++ {
+		pos := cube.Pos{x, p.y + ly, z}
+		if pos.OutOfBounds(r) {
+			continue
+		}
+		ch := p.t.at(lx, ly, lz)
+		b, ok := pal.block(ch, p, pos)
+		if !ok {
+			continue
+		}
+		if b == nil {
+			b = block.Air{}
+		}
+		b = rotateBlock(b, p.turns)
+		c.SetBlock(cx, int16(pos[1]), cz, 0, world.BlockRuntimeID(b))
+		if col == nil {
+			continue
+		}
+		if _, ok := world.EncodeBlockNBT(b); ok {
+			col.BlockEntities[pos] = b
+		}
+		if e, ok := pal.entity(ch, p, pos); ok {
+			col.Entities = append(col.Entities, e)
+		}
+	}
+	// Vegetation above the piece, such as the leaves of trees, is removed so
+	// that it does not cover the piece.
+	for y := p.y + p.t.height; y < min(p.y+p.t.height+8, r.Max()); y++ {
+		if b, _ := world.BlockByRuntimeID(c.Block(cx, int16(y), cz, 0)); vegetation(b) {
+			c.SetBlock(cx, int16(y), cz, 0, airRID)
+		}
+	}
+}
+
+// placeTerrainColumn places the block of the character passed on the surface
+// of a column, which is used for pieces that follow the terrain. Plants on
+// top of the surface are removed.
+func placeTerrainColumn(c *chunk.Chunk, x, z int, p piece, ch byte, pal palette) {
+	cx, cz := uint8(x&15), uint8(z&15)
+	y := c.HighestBlock(cx, cz)
+	for ; y > int16(c.Range().Min()); y-- {
+		if b, _ := world.BlockByRuntimeID(c.Block(cx, y, cz, 0)); !vegetation(b) {
+			break
+		}
+	}
+	if rid := c.Block(cx, y, cz, 0); !solidGround(rid) {
+		// Pieces that follow the terrain are never placed on water.
+		return
+	}
+	b, ok := pal.block(ch, p, cube.Pos{x, int(y), z})
+	if !ok {
+		return
+	}
+	c.SetBlock(cx, y, cz, 0, world.BlockRuntimeID(b))
+	for above := y + 1; above < int16(c.Range().Max()); above++ {
+		if b, _ := world.BlockByRuntimeID(c.Block(cx, above, cz, 0)); !plant(b) {
+			break
+		}
+		c.SetBlock(cx, above, cz, 0, airRID)
+	}
+}
+
+// solidGround checks if the block with the runtime ID passed is part of the
+// natural terrain that structures may be placed on.
+func solidGround(rid uint32) bool {
+	return carvable[rid] || netherGround[rid] || rid == bedrockRID || rid == endStoneRID
+}
+
+// vegetation checks if the block passed is a plant or a part of a tree.
+func vegetation(b world.Block) bool {
+	if _, ok := b.(block.Log); ok {
+		return true
+	}
+	return plant(b) || treeReplaceable(b, true)
+}
+
+// plant checks if the block passed is a plant that is placed on the surface,
+// such as grass or flowers.
+func plant(b world.Block) bool {
+	if _, ok := b.(block.Air); ok {
+		return false
+	}
+	return treeReplaceable(b, false)
+}
+
+// rotateBlock rotates the block passed by the number of clockwise quarter
+// turns passed. Blocks that do not have a direction are returned unchanged.
+func rotateBlock(b world.Block, turns int) world.Block {
+	if turns == 0 {
+		return b
+	}
+	rotate := func(d cube.Direction) cube.Direction {
+		for i := 0; i < turns; i++ {
+			d = d.RotateRight()
+		}
+		return d
+	}
+	switch v := b.(type) {
+	case block.Log:
+		if turns%2 == 1 && v.Axis != cube.Y {
+			v.Axis = cube.Axis(2 - v.Axis)
+		}
+		return v
+	case block.HayBale:
+		if turns%2 == 1 && v.Axis != cube.Y {
+			v.Axis = cube.Axis(2 - v.Axis)
+		}
+		return v
+	case block.PurpurPillar:
+		if turns%2 == 1 && v.Axis != cube.Y {
+			v.Axis = cube.Axis(2 - v.Axis)
+		}
+		return v
+	case block.WoodDoor:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Stairs:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Chest:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Lectern:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Loom:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Smoker:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.BlastFurnace:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Grindstone:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Stonecutter:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.EndPortalFrame:
+		v.Facing = rotate(v.Facing)
+		return v
+	case block.Torch:
+		if v.Facing != cube.FaceDown && v.Facing != cube.FaceUp {
+			v.Facing = rotate(v.Facing.Direction()).Face()
+		}
+		return v
+	case block.ItemFrame:
+		if v.Facing != cube.FaceDown && v.Facing != cube.FaceUp {
+			v.Facing = rotate(v.Facing.Direction()).Face()
+		}
+		return v
+	case block.Skull:
+		for i := 0; i < turns; i++ {
+			v.Attach = v.Attach.RotateRight()
+		}
+		return v
+	}
+	return b
+}
@@ -0,0 +1,348 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"math/rand"
+)
+
+func init() {
+	for _, wood := range block.SaplingWoodTypes() {
+		block.RegisterTreeGrower(wood, TreeFeature{Wood: wood})
+	}
+	block.RegisterTreeGrower(block.CrimsonWood(), HugeFungusFeature{})
+	block.RegisterTreeGrower(block.WarpedWood(), HugeFungusFeature{Warped: true})
+}
+
+// blockSource is an area in which a tree may be grown. It is implemented by
+// Region during generation and by worldSource when growing a tree from a
+// sapling.
+type blockSource interface {
+	// Range returns the vertical range of the area.
+	Range() cube.Range
+	// Block returns the block at the position passed.
+	Block(pos cube.Pos) world.Block
+	// SetBlock sets the block at the position passed.
+	SetBlock(pos cube.Pos, b world.Block)
+}
+
+// worldSource is a blockSource backed by a world.World.
+type worldSource struct {
+	w *world.World
+}
+
+// Range ...
+func (s worldSource) Range() cube.Range {
+	return s.w.Range()
+}
+
+// Block ...
+func (s worldSource) Block(pos cube.Pos) world.Block {
+	return s.w.Block(pos)
+}
+
+// SetBlock ...
+func (s worldSource) SetBlock(pos cube.Pos, b world.Block) {
+	s.w.SetBlock(pos, b, nil)
+}
+
+// TreeFeature is a Feature that places a tree of a wood type. The shape of the
+// tree depends on its wood type: oak, birch and jungle trees have a round
+// canopy, spruce trees are cone shaped, acacia trees have a bent trunk, dark
+// oak trees have a trunk of 2x2 logs and cherry trees have a wide, flat
+// canopy. TreeFeature also implements block.TreeGrower, so that it grows the
+// trees of saplings.
+type TreeFeature struct {
+	// Wood is the wood type of the tree. It must be one of the wood types
+	// returned by block.SaplingWoodTypes.
+	Wood block.WoodType
+}
+
+// treeReach is the maximum horizontal distance from its origin that any tree
+// placed by a TreeFeature reaches.
+const treeReach = 8
+
+// Place ...
+func (t TreeFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, treeReach) {
+		return false
+	}
+	return t.grow(r, pos, rnd, false)
+}
+
+// GrowTree ...
+func (t TreeFeature) GrowTree(pos cube.Pos, w *world.World, r *rand.Rand) bool {
+	if t.Wood == block.DarkOakWood() {
+		// Dark oak trees only grow from four saplings placed in a square.
+		base, ok := t.saplingSquare(pos, w)
+		if !ok {
+			return false
+		}
+		pos = base
+	}
+	return t.grow(worldSource{w: w}, pos, r, true)
+}
+
+// saplingSquare looks for a square of 2x2 saplings of the TreeFeature's wood
+// type that includes the position passed. If found, the north-western corner of
+// the square is returned.
+func (t TreeFeature) saplingSquare(pos cube.Pos, w *world.World) (cube.Pos, bool) {
+	for _, offset := range []cube.Pos{{0, 0, 0}, {-1, 0, 0}, {0, 0, -1}, {-1, 0, -1}} {
+		base, found := pos.Add(offset), true
+		for _, p := range trunkSquare(pos.Add(offset)) {
+			if s, ok := w.Block(p).(block.Sapling); !ok || s.Wood != t.Wood {
+				found = false
+				break
+			}
+		}
+		if found {
+			return base, true
+		}
+	}
+	return cube.Pos{}, false
+}
+
+// grow grows the tree at the position passed. If strict is true, the tree is
+// only grown if there is enough free space for its trunk, which is the case
+// when growing a tree from a sapling. During generation, trees are placed
+// regardless of what was placed before them, so that their shape does not
+// depend on the order in which chunks are decorated.
+func (t TreeFeature) grow(s blockSource, pos cube.Pos, rnd *rand.Rand, strict bool) bool {
+	var (
+		height int
+		trunk  []cube.Pos
+		leaves func(s blockSource, top cube.Pos, rnd *rand.Rand)
+	)
+	switch t.Wood {
+	case block.OakWood():
+		height, leaves = 4+rnd.Intn(3), blobCanopy
+	case block.BirchWood():
+		height, leaves = 5+rnd.Intn(3), blobCanopy
+	case block.JungleWood():
+		height, leaves = 4+rnd.Intn(7), blobCanopy
+	case block.SpruceWood():
+		height, leaves = 6+rnd.Intn(4), coneCanopy
+	case block.AcaciaWood():
+		height, leaves = 5+rnd.Intn(3), flatCanopy
+	case block.DarkOakWood():
+		height, leaves = 6+rnd.Intn(3), wideCanopy
+	case block.Cherry():
+		height, leaves = 4+rnd.Intn(3), cherryCanopy
+	default:
+		return false
+	}
+	ground := []cube.Pos{pos}
+	if t.Wood == block.DarkOakWood() {
+		ground = trunkSquare(pos)
+	}
+	for _, p := range ground {
+		if !treeSoil(s.Block(p.Side(cube.FaceDown))) {
+			return false
+		}
+	}
+	if pos[1]+height+2 > s.Range().Max() {
+		return false
+	}
+
+	top := pos
+	if t.Wood == block.AcaciaWood() {
+		trunk, top = acaciaTrunk(pos, height, rnd)
+	} else {
+		for y := 0; y < height; y++ {
+			for _, p := range ground {
+				trunk = append(trunk, p.Add(cube.Pos{0, y}))
+			}
+		}
+		top = pos.Add(cube.Pos{0, height - 1})
+	}
+	if strict {
+		for _, p := range trunk {
+			if !treeReplaceable(s.Block(p), true) {
+				return false
+			}
+		}
+	}
+
+	for _, p := range ground {
+		if _, ok := s.Block(p.Side(cube.FaceDown)).(block.Grass); ok {
+			s.SetBlock(p.Side(cube.FaceDown), block.Dirt{})
+		}
+	}
+	log := block.Log{Wood: t.Wood, Axis: cube.Y}
+	for _, p := range trunk {
+		if treeReplaceable(s.Block(p), true) {
+			s.SetBlock(p, log)
+		}
+	}
+	leaves(treeLeaves{blockSource: s, leaves: block.Leaves{Wood: t.Wood}}, top, rnd)
+	return true
+}
+
+// treeLeaves wraps around a blockSource to only set leaves where they do not
+// replace other blocks than air and plants.
+type treeLeaves struct {
+	blockSource
+	leaves block.Leaves
+}
+
+// SetBlock sets leaves at the position passed if the block at that position
+// may be replaced by leaves. The block passed is ignored.
+func (l treeLeaves) SetBlock(pos cube.Pos, _ world.Block) {
+	if !pos.OutOfBounds(l.Range()) && treeReplaceable(l.Block(pos), false) {
+		l.blockSource.SetBlock(pos, l.leaves)
+	}
+}
+
+// blobCanopy places the round canopy of oak, birch and jungle trees, with its
+// top layer directly above the top of the trunk.
+func blobCanopy(s blockSource, top cube.Pos, rnd *rand.Rand) {
+	for dy := -2; dy <= 1; dy++ {
+		radius := 2
+		if dy >= 0 {
+			radius = 1
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				corner := abs(dx) == radius && abs(dz) == radius
+				// Corners of the top layer are always left out, while those of
+				// the other layers are left out randomly.
+				if corner && (rnd.Intn(2) == 0 || dy == 1) {
+					continue
+				}
+				s.SetBlock(top.Add(cube.Pos{dx, dy, dz}), nil)
+			}
+		}
+	}
+}
+
+// coneCanopy places the cone shaped canopy of spruce trees, with layers that
+// grow wider towards the bottom and a single leaf block on top.
+func coneCanopy(s blockSource, top cube.Pos, rnd *rand.Rand) {
+	s.SetBlock(top.Side(cube.FaceUp), nil)
+	bottom := 2 + rnd.Intn(2)
+	radius, maxRadius := 0, 1
+	for y := top[1]; y >= top[1]-bottom-3; y-- {
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				if radius > 0 && abs(dx) == radius && abs(dz) == radius {
+					continue
+				}
+				s.SetBlock(cube.Pos{top[0] + dx, y, top[2] + dz}, nil)
+			}
+		}
+		if radius >= maxRadius {
+			radius, maxRadius = 1, min(maxRadius+1, 3)
+		} else {
+			radius++
+		}
+	}
+}
+
+// flatCanopy places the flat canopy of acacia trees.
+func flatCanopy(s blockSource, top cube.Pos, _ *rand.Rand) {
+	for dx := -3; dx <= 3; dx++ {
+		for dz := -3; dz <= 3; dz++ {
+			if abs(dx)+abs(dz) <= 4 && !(abs(dx) == 3 && abs(dz) == 3) {
+				s.SetBlock(top.Add(cube.Pos{dx, 0, dz}), nil)
+			}
+		}
+	}
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			s.SetBlock(top.Add(cube.Pos{dx, 1, dz}), nil)
+		}
+	}
+}
+
+// wideCanopy places the wide canopy of dark oak trees around a trunk of 2x2
+// logs, of which top is the north-western log at the top.
+func wideCanopy(s blockSource, top cube.Pos, rnd *rand.Rand) {
+	for dy := -2; dy <= 1; dy++ {
+		radius := 3
+		if dy == -2 || dy == 1 {
+			radius = 2
+		}
+		for dx := -radius; dx <= radius+1; dx++ {
+			for dz := -radius; dz <= radius+1; dz++ {
+				// The distance is measured from the edge of the trunk, which
+				// is two blocks wide.
+				ex, ez := max(-dx, dx-1), max(-dz, dz-1)
+				if ex == radius && ez == radius && (dy == 1 || rnd.Intn(2) == 0) {
+					continue
+				}
+				s.SetBlock(top.Add(cube.Pos{dx, dy, dz}), nil)
+			}
+		}
+	}
+}
+
+// cherryCanopy places the wide, flattened canopy of cherry trees. The edge of
+// the canopy is left ragged randomly.
+func cherryCanopy(s blockSource, top cube.Pos, rnd *rand.Rand) {
+	for dy := -1; dy <= 2; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			for dz := -4; dz <= 4; dz++ {
+				fy := float64(dy) - 0.5
+				d := float64(dx*dx+dz*dz)/16 + fy*fy/4
+				if d > 1 || (d > 0.7 && rnd.Intn(3) == 0) {
+					continue
+				}
+				s.SetBlock(top.Add(cube.Pos{dx, dy, dz}), nil)
+			}
+		}
+	}
+}
+
+// acaciaTrunk returns the logs of the trunk of an acacia tree with the height
+// passed. The upper part of the trunk bends in a random horizontal direction.
+// The position of the top log of the trunk is returned as well.
+func acaciaTrunk(pos cube.Pos, height int, rnd *rand.Rand) ([]cube.Pos, cube.Pos) {
+	face := cube.HorizontalFaces()[rnd.Intn(4)]
+	bendStart, bend := height-rnd.Intn(4)-1, 3-rnd.Intn(3)
+
+	trunk := make([]cube.Pos, 0, height)
+	p := pos
+	for y := 0; y < height; y++ {
+		if y >= bendStart && bend > 0 {
+			p, bend = p.Side(face), bend-1
+		}
+		trunk = append(trunk, cube.Pos{p[0], pos[1] + y, p[2]})
+	}
+	return trunk, trunk[len(trunk)-1]
+}
+
+// trunkSquare returns the four positions of a trunk of 2x2 logs with its
+// north-western corner at the position passed.
+func trunkSquare(pos cube.Pos) []cube.Pos {
+	return []cube.Pos{pos, pos.Add(cube.Pos{1, 0, 0}), pos.Add(cube.Pos{0, 0, 1}), pos.Add(cube.Pos{1, 0, 1})}
+}
+
+// treeSoil checks if a tree may grow on the block passed.
+func treeSoil(b world.Block) bool {
+	switch b.(type) {
+	case block.Grass, block.Dirt, block.Podzol:
+		return true
+	}
+	return false
+}
+
+// treeReplaceable checks if the block passed may be replaced by a part of a
+// tree. Logs may replace leaves, while leaves may only replace air and plants.
+func treeReplaceable(b world.Block, log bool) bool {
+	switch b.(type) {
+	case block.Air, block.Sapling, block.ShortGrass, block.Fern, block.Flower, block.DoubleFlower, block.DoubleTallGrass, block.DeadBush, block.Mushroom:
+		return true
+	case block.Leaves:
+		return log
+	}
+	return false
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"math/rand"
+)
+
+// PatchFeature is a Feature that scatters plants, such as grass, flowers and
+// mushrooms, on the surface around its origin.
+type PatchFeature struct {
+	// Plants holds the plants placed. Every attempt picks one of the plants
+	// with an equal chance, so a plant may be included multiple times to make
+	// it more common.
+	Plants []world.Block
+	// Tries is the number of attempts made to place a plant.
+	Tries int
+	// Spread is the maximum horizontal distance from the origin at which
+	// plants are placed.
+	Spread int
+	// Floor specifies if plants are placed on the first floor below the origin
+	// instead of on top of the highest block of a column. Floor is used for
+	// patches in the Nether, where the highest block is part of the ceiling.
+	Floor bool
+}
+
+// Place ...
+func (p PatchFeature) Place(r *Region, pos cube.Pos, rnd *rand.Rand) bool {
+	if !r.Near(pos, p.Spread) || len(p.Plants) == 0 {
+		return false
+	}
+	placed := false
+	for i := 0; i < p.Tries; i++ {
+		plant := p.Plants[rnd.Intn(len(p.Plants))]
+		x, z := pos[0]+rnd.Intn(p.Spread*2+1)-p.Spread, pos[2]+rnd.Intn(p.Spread*2+1)-p.Spread
+		if !r.Near(cube.Pos{x, 0, z}, 0) {
+			continue
+		}
+		at := cube.Pos{x, r.HighestBlock(x, z) + 1, z}
+		if p.Floor {
+			var ok bool
+			if at, ok = floorBelow(r, cube.Pos{x, pos[1] + rnd.Intn(5) - 2, z}, 8); !ok {
+				continue
+			}
+		}
+		if !r.Contains(at) || r.blockRID(at) != airRID || !plantSupported(plant, r.Block(at.Side(cube.FaceDown))) {
+			continue
+		}
+		r.SetBlock(at, plant)
+		placed = true
+	}
+	return placed
+}
+
+// floorBelow returns the position of the first air block above a block that is
+// not air, searching down from the position passed by at most the distance
+// passed.
+func floorBelow(r *Region, pos cube.Pos, distance int) (cube.Pos, bool) {
+	for y := pos[1]; y > pos[1]-distance && y > r.Range().Min(); y-- {
+		at := cube.Pos{pos[0], y, pos[2]}
+		if r.blockRID(at) == airRID && r.blockRID(at.Side(cube.FaceDown)) != airRID {
+			return at, true
+		}
+	}
+	return cube.Pos{}, false
+}
+
+// plantSupported checks if the plant passed may be placed on top of the block
+// passed.
+func plantSupported(plant, below world.Block) bool {
+	if _, ok := plant.(block.Mushroom); ok {
+		switch below.(type) {
+		case block.Grass, block.Dirt, block.Podzol, block.Stone:
+			return true
+		}
+		return false
+	}
+	soil, ok := below.(block.Soil)
+	return ok && soil.SoilFor(plant)
+}
+
+// vegetationFeatures returns the trees and plants registered to a new
+// Overworld.
+func vegetationFeatures() map[string]FeaturePlacement {
+	trees := func(wood block.WoodType, count, rarity int, biomes ...world.Biome) FeaturePlacement {
+		return FeaturePlacement{Feature: TreeFeature{Wood: wood}, Stage: StageVegetation, Count: count, Rarity: rarity, Biomes: biomes}
+	}
+	patch := func(plants []world.Block, tries, spread, count int, biomes ...world.Biome) FeaturePlacement {
+		return FeaturePlacement{Feature: PatchFeature{Plants: plants, Tries: tries, Spread: spread}, Stage: StageVegetation, Count: count, Biomes: biomes}
+	}
+	flowers := func(types ...block.FlowerType) (plants []world.Block) {
+		for _, t := range types {
+			plants = append(plants, block.Flower{Type: t})
+		}
+		return plants
+	}
+	grass := []world.Block{block.ShortGrass{}}
+	taigaGrass := []world.Block{block.ShortGrass{}, block.Fern{}, block.Fern{}, block.Fern{}}
+	jungleGrass := []world.Block{block.ShortGrass{}, block.ShortGrass{}, block.ShortGrass{}, block.Fern{}}
+	mushrooms := []world.Block{block.Mushroom{}, block.Mushroom{}, block.Mushroom{Red: true}}
+
+	mushroom := patch(mushrooms, 16, 4, 1)
+	mushroom.Rarity = 4
+
+	return map[string]FeaturePlacement{
+		"oak_trees":             trees(block.OakWood(), 8, 0, biome.Forest{}, biome.FlowerForest{}),
+		"oak_trees_sparse":      trees(block.OakWood(), 1, 3, biome.Plains{}, biome.Meadow{}, biome.River{}),
+		"oak_trees_swamp":       trees(block.OakWood(), 2, 0, biome.Swamp{}),
+		"oak_trees_savanna":     trees(block.OakWood(), 1, 2, biome.Savanna{}),
+		"birch_trees":           trees(block.BirchWood(), 10, 0, biome.BirchForest{}),
+		"birch_trees_forest":    trees(block.BirchWood(), 2, 0, biome.Forest{}, biome.FlowerForest{}),
+		"spruce_trees":          trees(block.SpruceWood(), 10, 0, biome.Taiga{}, biome.Grove{}),
+		"spruce_trees_snowy":    trees(block.SpruceWood(), 4, 0, biome.SnowyTaiga{}),
+		"spruce_trees_sparse":   trees(block.SpruceWood(), 1, 4, biome.SnowyPlains{}),
+		"jungle_trees":          trees(block.JungleWood(), 20, 0, biome.Jungle{}),
+		"acacia_trees":          trees(block.AcaciaWood(), 2, 0, biome.Savanna{}),
+		"dark_oak_trees":        trees(block.DarkOakWood(), 12, 0, biome.DarkForest{}),
+		"cherry_trees":          trees(block.Cherry(), 6, 0, biome.CherryGrove{}),
+		"grass":                 patch(grass, 32, 7, 2),
+		"grass_plains":          patch(grass, 32, 7, 8, biome.Plains{}, biome.Meadow{}, biome.Savanna{}),
+		"grass_taiga":           patch(taigaGrass, 32, 7, 6, biome.Taiga{}, biome.SnowyTaiga{}, biome.Grove{}),
+		"grass_jungle":          patch(jungleGrass, 32, 7, 20, biome.Jungle{}),
+		"flowers":               patch(flowers(block.Dandelion(), block.Poppy()), 64, 7, 1),
+		"flowers_plains":        patch(flowers(block.Dandelion(), block.Poppy(), block.AzureBluet(), block.OxeyeDaisy(), block.Cornflower(), block.RedTulip(), block.OrangeTulip(), block.WhiteTulip(), block.PinkTulip()), 64, 7, 3, biome.Plains{}, biome.Meadow{}),
+		"flowers_flower_forest": patch(flowers(block.Dandelion(), block.Poppy(), block.Allium(), block.AzureBluet(), block.RedTulip(), block.OrangeTulip(), block.WhiteTulip(), block.PinkTulip(), block.OxeyeDaisy(), block.Cornflower(), block.LilyOfTheValley()), 64, 7, 10, biome.FlowerForest{}),
+		"flowers_swamp":         patch(flowers(block.BlueOrchid()), 64, 7, 1, biome.Swamp{}),
+		"dead_bushes":           patch([]world.Block{block.DeadBush{}}, 4, 7, 2, biome.Desert{}, biome.Badlands{}),
+		"mushrooms":             mushroom,
+		"mushrooms_dark":        patch(mushrooms, 16, 4, 2, biome.DarkForest{}, biome.Swamp{}, biome.Taiga{}),
+	}
+}
@@ -0,0 +1,317 @@
+package generator
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/biome"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+)
+
+const (
+	// villageSpacing is the size in chunks of the regions in which at most one
+	// village is placed.
+	villageSpacing = 34
+	// villageSeparation is the minimum distance in chunks between the starts
+	// of villages in neighbouring regions.
+	villageSeparation = 8
+	// villageReach is the maximum horizontal distance in blocks from its start
+	// that any piece of a village reaches.
+	villageReach = 80
+	// maxVillageSearchRadius is the maximum distance in regions from a
+	// position that is searched for villages when locating the nearest one.
+	maxVillageSearchRadius = 16
+)
+
+// villages places the parts of all villages that intersect with the chunk at
+// the position passed. If col is not nil, the chests and villagers of the
+// villages are added to it.
+func (g *Overworld) villages(pos world.ChunkPos, c *chunk.Chunk, col *world.Column) {
+	reach := int32(villageReach>>4 + 1)
+	minX, maxX := floorDiv(int(pos[0]-reach), villageSpacing), floorDiv(int(pos[0]+reach), villageSpacing)
+	minZ, maxZ := floorDiv(int(pos[1]-reach), villageSpacing), floorDiv(int(pos[1]+reach), villageSpacing)
+	for rx := minX; rx <= maxX; rx++ {
+		for rz := minZ; rz <= maxZ; rz++ {
+			if v := g.village(rx, rz, c.Range()); v != nil {
+				v.place(pos, c, col)
+			}
+		}
+	}
+}
+
+// village returns the village in the region passed, or nil if the region does
+// not have a village.
+func (g *Overworld) village(rx, rz int, r cube.Range) *structure {
+	return g.cachedStructure(structureKey{name: "village", pos: world.ChunkPos{int32(rx), int32(rz)}}, func() *structure {
+		return g.assembleVillage(rx, rz, r)
+	})
+}
+
+// villageStart returns the position of the start of the village in the region
+// passed and the villagePalette that it is built from. If the start is not in
+// a biome that villages generate in, or if it lies below sea level, false is
+// returned.
+func (g *Overworld) villageStart(rx, rz int, r cube.Range) (cube.Pos, *villagePalette, bool) {
+	h := positionHash(g.seed, rx, 0x7111a6e, rz)
+	cx := rx*villageSpacing + int(h%(villageSpacing-villageSeparation))
+	cz := rz*villageSpacing + int((h>>16)%(villageSpacing-villageSeparation))
+	x, z := cx<<4+8, cz<<4+8
+
+	pal, ok := villagePaletteFor(g.column(float64(x), float64(z)).biome)
+	if !ok {
+		return cube.Pos{}, nil, false
+	}
+	y := g.surfaceAt(x, z, r)
+	return cube.Pos{x, y, z}, pal, y > seaLevel
+}
+
+// assembleVillage assembles the village in the region passed. If the region
+// does not have a village, nil is returned.
+func (g *Overworld) assembleVillage(rx, rz int, r cube.Range) *structure {
+	pos, pal, ok := g.villageStart(rx, rz, r)
+	if !ok {
+		return nil
+	}
+	rnd := newRand(positionHash(g.seed, rx, 0x7111a6e, rz))
+	start := newPiece(villageStart, rnd.Intn(4), rnd.Uint64())
+	start.x, start.y, start.z = pos[0]-start.sizeX/2, pos[1], pos[2]-start.sizeZ/2
+
+	j := jigsaw{pools: villagePools, maxDepth: 6, maxDistance: villageReach, place: func(p piece, target cube.Pos) (int, bool) {
+		y := g.surfaceAt(target[0], target[2], r)
+		if y <= seaLevel {
+			return 0, false
+		}
+		if p.t.terrain {
+			return y, true
+		}
+		// Buildings are only placed on terrain that is mostly flat, so that
+		// they are not buried in hills or left floating above valleys.
+		for _, corner := range [][2]int{{p.x, p.z}, {p.x + p.sizeX - 1, p.z}, {p.x, p.z + p.sizeZ - 1}, {p.x + p.sizeX - 1, p.z + p.sizeZ - 1}} {
+			if other := g.surfaceAt(corner[0], corner[1], r); other <= seaLevel || abs(other-y) > 3 {
+				return 0, false
+			}
+		}
+		return y, true
+	}}
+	return &structure{pieces: j.assemble(start, rnd), palette: pal}
+}
+
+// locateVillage returns the position of the start of the village nearest to
+// the position passed, searching regions up to a limited distance.
+func (g *Overworld) locateVillage(pos cube.Pos, r cube.Range) (cube.Pos, bool) {
+	return locateInRegions(pos, villageSpacing, maxVillageSearchRadius, func(rx, rz int) (cube.Pos, bool) {
+		start, _, ok := g.villageStart(rx, rz, r)
+		return start, ok
+	})
+}
+
+// villagePalette holds the blocks that a village is built from, which depend
+// on the biome that the village is placed in.
+type villagePalette struct {
+	name                           string
+	biome                          world.Biome
+	base, wall, pillar, roof, path world.Block
+	wood                           block.WoodType
+	// crops holds the names of the crops planted on farms.
+	crops []string
+}
+
+// villagePaletteFor returns the villagePalette of villages in the world.Biome
+// passed. If villages do not generate in the biome, false is returned.
+func villagePaletteFor(b world.Biome) (*villagePalette, bool) {
+	crops := []string{"minecraft:wheat", "minecraft:wheat", "minecraft:carrots", "minecraft:potatoes", "minecraft:beetroot"}
+	switch b.(type) {
+	case biome.Plains, biome.Meadow:
+		return &villagePalette{name: "plains", biome: b, base: block.Cobblestone{}, wall: block.Planks{Wood: block.OakWood()}, pillar: block.Log{Wood: block.OakWood(), Axis: cube.Y}, roof: block.Planks{Wood: block.SpruceWood()}, path: block.DirtPath{}, wood: block.OakWood(), crops: crops}, true
+	case biome.Desert:
+		return &villagePalette{name: "desert", biome: b, base: block.Sandstone{}, wall: block.Sandstone{Type: block.CutSandstone()}, pillar: block.Sandstone{Type: block.SmoothSandstone()}, roof: block.Sandstone{Type: block.SmoothSandstone()}, path: block.Sandstone{Type: block.SmoothSandstone()}, wood: block.JungleWood(), crops: []string{"minecraft:wheat", "minecraft:beetroot"}}, true
+	case biome.Savanna:
+		return &villagePalette{name: "savanna", biome: b, base: block.Cobblestone{}, wall: block.Planks{Wood: block.AcaciaWood()}, pillar: block.Log{Wood: block.AcaciaWood(), Axis: cube.Y}, roof: block.Terracotta{}, path: block.DirtPath{}, wood: block.AcaciaWood(), crops: crops}, true
+	case biome.Taiga:
+		return &villagePalette{name: "taiga", biome: b, base: block.Cobblestone{Mossy: true}, wall: block.Planks{Wood: block.SpruceWood()}, pillar: block.Log{Wood: block.SpruceWood(), Axis: cube.Y}, roof: block.Cobblestone{}, path: block.DirtPath{}, wood: block.SpruceWood(), crops: []string{"minecraft:wheat", "minecraft:potatoes", "minecraft:carrots"}}, true
+	case biome.SnowyPlains:
+		return &villagePalette{name: "snowy", biome: b, base: block.Cobblestone{}, wall: block.Planks{Wood: block.SpruceWood()}, pillar: block.Log{Wood: block.SpruceWood(), Axis: cube.Y}, roof: block.Snow{}, path: block.DirtPath{}, wood: block.SpruceWood(), crops: []string{"minecraft:beetroot", "minecraft:potatoes"}}, true
+	}
+	return nil, false
+}
+
+// block ...
+func (v *villagePalette) block(ch byte, p piece, pos cube.Pos) (world.Block, bool) {
+	switch ch {
+	case ' ':
+		return nil, false
+	case '.', 'V', 'v':
+		return nil, true
+	case 'F':
+		return v.base, true
+	case 'P':
+		return v.wall, true
+	case 'L':
+		return v.pillar, true
+	case 'R':
+		return v.roof, true
+	case 'G':
+		return block.GlassPane{}, true
+	case 'D', 'd':
+		return block.WoodDoor{Wood: v.wood, Facing: cube.South, Top: ch == 'd'}, true
+	case 'S':
+		return v.path, true
+	case 'T':
+		return block.Torch{Facing: cube.FaceDown, Type: block.NormalFire()}, true
+	case 'f':
+		return block.WoodFence{Wood: v.wood}, true
+	case 'w':
+		return block.Water{Still: true, Depth: 8}, true
+	case 'm':
+		return block.Farmland{Hydration: 7}, true
+	case 'c':
+		growth := int32(positionHash(int64(p.seed), pos[0], pos[1], pos[2]) % 8)
+		return world.BlockByName(v.crops[p.seed%uint64(len(v.crops))], map[string]any{"growth": growth})
+	case 'C':
+		c := block.NewChest()
+		c.Facing, c.LootTable = cube.South, "chests/village/village_"+v.name+"_house"
+		return c, true
+	case 'W':
+		return workstation(villageProfession(p)), true
+	}
+	return nil, false
+}
+
+// entity ...
+func (v *villagePalette) entity(ch byte, p piece, pos cube.Pos) (world.Entity, bool) {
+	switch ch {
+	case 'V':
+		return entity.NewVillager(pos.Vec3Middle(), villageProfession(p), v.biome), true
+	case 'v':
+		return entity.NewVillager(pos.Vec3Middle(), entity.ProfessionNone(), v.biome), true
+	}
+	return nil, false
+}
+
+// foundation ...
+func (v *villagePalette) foundation() world.Block {
+	return v.base
+}
+
+// villageProfessions holds the professions of villagers that work at the
+// workstations of village houses.
+var villageProfessions = []entity.VillagerProfession{
+	entity.ProfessionFisherman(), entity.ProfessionShepherd(), entity.ProfessionFletcher(),
+	entity.ProfessionLibrarian(), entity.ProfessionArmourer(), entity.ProfessionWeaponsmith(),
+	entity.ProfessionToolsmith(), entity.ProfessionButcher(), entity.ProfessionMason(),
+}
+
+// villageProfession returns the profession of the villager working in the
+// piece passed. Villagers of farms are always farmers.
+func villageProfession(p piece) entity.VillagerProfession {
+	if p.t == villageFarm {
+		return entity.ProfessionFarmer()
+	}
+	return villageProfessions[(p.seed>>8)%uint64(len(villageProfessions))]
+}
+
+// workstation returns the workstation block of the profession passed.
+func workstation(p entity.VillagerProfession) world.Block {
+	switch p {
+	case entity.ProfessionFisherman():
+		b := block.NewBarrel()
+		b.Facing = cube.FaceUp
+		return b
+	case entity.ProfessionShepherd():
+		return block.Loom{Facing: cube.South}
+	case entity.ProfessionFletcher():
+		return block.FletchingTable{}
+	case entity.ProfessionLibrarian():
+		return block.Lectern{Facing: cube.South}
+	case entity.ProfessionArmourer():
+		return block.NewBlastFurnace(cube.South)
+	case entity.ProfessionWeaponsmith():
+		return block.Grindstone{Attach: block.StandingGrindstoneAttachment(), Facing: cube.South}
+	case entity.ProfessionToolsmith():
+		return block.SmithingTable{}
+	case entity.ProfessionButcher():
+		return block.NewSmoker(cube.South)
+	case entity.ProfessionMason():
+		return block.Stonecutter{Facing: cube.South}
+	}
+	return block.Composter{}
+}
+
+// villageStart is the well in the centre of every village, from which streets
+// lead in all four directions.
+var villageStart = newTemplate("well", 1, [][]string{
+	{"SSSSS", "SFFFS", "SFwFS", "SFFFS", "SSSSS"},
+	{".....", ".FFF.", ".F.F.", ".FFF.", "....."},
+	{".....", ".f.f.", ".....", ".f.f.", "....."},
+	{".....", ".FFF.", ".FFF.", ".FFF.", "....."},
+	{".....", ".....", "..T..", ".....", "....."},
+},
+	connector{x: 2, z: 0, facing: cube.North, pool: "streets"},
+	connector{x: 2, z: 4, facing: cube.South, pool: "streets"},
+	connector{x: 0, z: 2, facing: cube.West, pool: "streets"},
+	connector{x: 4, z: 2, facing: cube.East, pool: "streets"},
+)
+
+// villageFarm is a field of crops with a channel of water in the middle,
+// worked at by a farmer.
+var villageFarm = newTemplate("farm", 2, [][]string{
+	{"LLLLLLLLL", "LmmmwmmmL", "LmmmwmmmL", "LmmmwmmmL", "LmmmwmmmL", "LLLLLLLLL", "    S    "},
+	{"W........", ".ccc.ccc.", ".ccc.ccc.", ".ccc.ccc.", ".ccc.ccc.", ".V.......", "    .    "},
+}, connector{x: 4, z: 6, facing: cube.South, pool: "streets"})
+
+// villagePools holds the pools of templates that villages are assembled from.
+var villagePools = map[string][]*template{
+	"streets": {
+		newTemplate("street_straight", 6, [][]string{{"SSS", "SSS", "SSS", "SSS", "SSS", "SSS", "SSS", "SSS", "SSS", "SSS", "SSS"}},
+			connector{x: 1, z: 0, facing: cube.North, pool: "streets"},
+			connector{x: 1, z: 10, facing: cube.South, pool: "streets"},
+			connector{x: 0, z: 2, facing: cube.West, pool: "houses"},
+			connector{x: 2, z: 2, facing: cube.East, pool: "houses"},
+			connector{x: 0, z: 8, facing: cube.West, pool: "houses"},
+			connector{x: 2, z: 8, facing: cube.East, pool: "houses"},
+		).terrainPiece(),
+		newTemplate("street_short", 3, [][]string{{"SSS", "SSS", "SSS", "SSS", "SSS", "SSS", "SSS"}},
+			connector{x: 1, z: 0, facing: cube.North, pool: "streets"},
+			connector{x: 1, z: 6, facing: cube.South, pool: "streets"},
+			connector{x: 0, z: 3, facing: cube.West, pool: "houses"},
+			connector{x: 2, z: 3, facing: cube.East, pool: "houses"},
+		).terrainPiece(),
+		newTemplate("street_crossing", 2, [][]string{{"SSS", "SSS", "SSS"}},
+			connector{x: 1, z: 0, facing: cube.North, pool: "streets"},
+			connector{x: 1, z: 2, facing: cube.South, pool: "streets"},
+			connector{x: 0, z: 1, facing: cube.West, pool: "streets"},
+			connector{x: 2, z: 1, facing: cube.East, pool: "streets"},
+		).terrainPiece(),
+		newTemplate("street_turn", 2, [][]string{{"SSS", "SSS", "SSS"}},
+			connector{x: 1, z: 0, facing: cube.North, pool: "streets"},
+			connector{x: 2, z: 1, facing: cube.East, pool: "streets"},
+		).terrainPiece(),
+	},
+	"houses": {
+		newTemplate("small_house", 4, [][]string{
+			{"FFFFF", "FFFFF", "FFFFF", "FFFFF", "FFFFF", "  S  "},
+			{"LPPPL", "PCTVP", "G...G", "P..WP", "LPDPL", "  .  "},
+			{"LPGPL", "P...P", "G...G", "P...P", "LPdPL", "  .  "},
+			{"LPPPL", "P...P", "P...P", "P...P", "LPPPL", "     "},
+			{"RRRRR", "RRRRR", "RRRRR", "RRRRR", "RRRRR", "     "},
+		}, connector{x: 2, z: 5, facing: cube.South, pool: "streets"}),
+		newTemplate("medium_house", 2, [][]string{
+			{"FFFFFFF", "FFFFFFF", "FFFFFFF", "FFFFFFF", "FFFFFFF", "FFFFFFF", "   S   "},
+			{"LPPPPPL", "PC..WTP", "P.V...P", "G.....G", "P..v..P", "LPPDPPL", "   .   "},
+			{"LPGPGPL", "P.....P", "P.....P", "G.....G", "P.....P", "LPPdPPL", "   .   "},
+			{"LPPPPPL", "P.....P", "P.....P", "P.....P", "P.....P", "LPPPPPL", "       "},
+			{"RRRRRRR", "R.....R", "R.....R", "R.....R", "R.....R", "RRRRRRR", "       "},
+			{".......", ".RRRRR.", ".RRRRR.", ".RRRRR.", ".RRRRR.", ".......", "       "},
+		}, connector{x: 3, z: 6, facing: cube.South, pool: "streets"}),
+		villageFarm,
+		newTemplate("lamp", 1, [][]string{{"F"}, {"f"}, {"f"}, {"F"}, {"T"}},
+			connector{x: 0, z: 0, facing: cube.South, pool: "streets"}),
+	},
+}
+
+// terrainPiece marks the template as following the terrain and returns it.
+func (t *template) terrainPiece() *template {
+	t.terrain = true
+	return t
+}
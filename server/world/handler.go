@@ -35,6 +35,17 @@ type Handler interface {
 	// wood, that can be broken by fire. HandleBlockBurn is often succeeded by HandleFireSpread, when fire spreads to
 	// the position of the original block and the event.Context is not cancelled in HandleBlockBurn.
 	HandleBlockBurn(ctx *event.Context, pos cube.Pos)
+	// HandleExplosion handles an explosion occurring at a position in the World. The entities and blocks affected by
+	// the explosion may be altered by changing the slices that the entities and blocks pointers point to. The chance
+	// that destroyed blocks drop their items and whether the explosion starts fires may be changed similarly.
+	// ctx.Cancel() may be called to prevent the explosion from affecting any entities or blocks.
+	HandleExplosion(ctx *event.Context, position mgl64.Vec3, entities *[]Entity, blocks *[]cube.Pos, itemDropChance *float64, spawnFire *bool)
+	// HandleDawn handles the sun starting to rise in the World, which happens when the time of day reaches
+	// TimeSunrise while the time is cycling.
+	HandleDawn()
+	// HandleDusk handles the sun starting to set in the World, which happens when the time of day reaches
+	// TimeSunset while the time is cycling.
+	HandleDusk()
 	// HandleEntitySpawn handles an entity being spawned into a World through a call to World.AddEntity.
 	HandleEntitySpawn(e Entity)
 	// HandleEntityDespawn handles an entity being despawned from a World through a call to World.RemoveEntity.
@@ -59,6 +70,10 @@ func (NopHandler) HandleLiquidHarden(*event.Context, cube.Pos, Block, Block, Blo
 func (NopHandler) HandleSound(*event.Context, Sound, mgl64.Vec3)                      {}
 func (NopHandler) HandleFireSpread(*event.Context, cube.Pos, cube.Pos)                {}
 func (NopHandler) HandleBlockBurn(*event.Context, cube.Pos)                           {}
+func (NopHandler) HandleDawn()                                                        {}
+func (NopHandler) HandleDusk()                                                        {}
 func (NopHandler) HandleEntitySpawn(Entity)                                           {}
 func (NopHandler) HandleEntityDespawn(Entity)                                         {}
 func (NopHandler) HandleClose()                                                       {}
+func (NopHandler) HandleExplosion(*event.Context, mgl64.Vec3, *[]Entity, *[]cube.Pos, *float64, *bool) {
+}
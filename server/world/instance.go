@@ -0,0 +1,91 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/google/uuid"
+	"sync"
+)
+
+// Compile time check to make sure Template implements Provider.
+var _ Provider = (*Template)(nil)
+
+// Template is a read-only Provider that serves as the source of many instances of the same world, such as the
+// arena of a duels or skywars server. Chunks are read from the Provider of the Template only once and are then
+// kept in memory in encoded form, so that all instances share the same immutable chunk data while every
+// instance gets its own copy of the chunks it loads.
+//
+// Instances of a Template are created using Config.CloneFrom. A Template is safe for concurrent use by all of
+// its instances.
+type Template struct {
+	p        Provider
+	entities EntityRegistry
+
+	mu      sync.Mutex
+	columns map[memoryKey]memoryColumn
+}
+
+// NewTemplate creates a Template that reads its chunks from the Provider passed. Entities stored in the chunks
+// of the Provider are decoded using the EntityRegistry passed. The Provider is never written to.
+func NewTemplate(p Provider, entities EntityRegistry) *Template {
+	return &Template{p: p, entities: entities, columns: make(map[memoryKey]memoryColumn)}
+}
+
+// Settings returns the Settings of the Provider of the Template. Instances created using Config.CloneFrom
+// each use a copy of these Settings.
+func (t *Template) Settings() *Settings {
+	return t.p.Settings()
+}
+
+// SaveSettings does nothing, as a Template is read-only.
+func (t *Template) SaveSettings(*Settings) {}
+
+// LoadPlayerSpawnPosition loads the spawn position of a player from the Provider of the Template.
+func (t *Template) LoadPlayerSpawnPosition(id uuid.UUID) (cube.Pos, bool, error) {
+	return t.p.LoadPlayerSpawnPosition(id)
+}
+
+// SavePlayerSpawnPosition does nothing, as a Template is read-only.
+func (t *Template) SavePlayerSpawnPosition(uuid.UUID, cube.Pos) error { return nil }
+
+// LoadColumn returns a new copy of the Column at a position and dimension in the Template. The Column is read
+// from the Provider of the Template the first time it is loaded and from memory afterwards. If no column
+// exists at the position, errors.Is(err, leveldb.ErrNotFound) equals true.
+func (t *Template) LoadColumn(pos ChunkPos, dim Dimension) (*Column, error) {
+	k := memoryKey{pos: pos, dim: dim}
+	t.mu.Lock()
+	stored, ok := t.columns[k]
+	t.mu.Unlock()
+	if !ok {
+		col, err := t.p.LoadColumn(pos, dim)
+		if err != nil {
+			return nil, err
+		}
+		stored = encodeMemoryColumn(col)
+		t.mu.Lock()
+		t.columns[k] = stored
+		t.mu.Unlock()
+	}
+	return stored.decode(dim, t.entities)
+}
+
+// StoreColumn does nothing, as a Template is read-only.
+func (t *Template) StoreColumn(ChunkPos, Dimension, *Column) error { return nil }
+
+// Close closes the Provider of the Template. Close should only be called once all instances of the Template
+// have been closed.
+func (t *Template) Close() error {
+	t.mu.Lock()
+	t.columns = nil
+	t.mu.Unlock()
+	return t.p.Close()
+}
+
+// CloneFrom creates a new World that is an instance of the Template passed. The World starts out with the
+// chunks and Settings of the Template, but all changes made to it, including the chunks generated by the
+// Generator of the Config, are kept in memory and discarded once the World is closed. The Provider and
+// ReadOnly fields of the Config are ignored.
+func (conf Config) CloneFrom(t *Template) *World {
+	conf.Provider = &OverlayProvider{Base: t, MemoryProvider: MemoryProvider{Entities: conf.Entities}}
+	conf.ReadOnly = false
+	return conf.New()
+}
@@ -0,0 +1,168 @@
+package world
+
+import (
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"golang.org/x/exp/maps"
+)
+
+// lightQueue holds the positions of blocks of which the light needs to be updated. The light is updated in the
+// background by World.lightWorker, so that edits of many blocks do not stall the ticking of the World.
+type lightQueue struct {
+	mu sync.Mutex
+	// pending holds the positions of blocks changed since the light was last updated, grouped by chunk.
+	pending map[ChunkPos]map[cube.Pos]struct{}
+	// full holds the chunks of which the light should be recalculated entirely, for example because too
+	// many blocks in it were changed to update the light of each of them individually.
+	full map[ChunkPos]struct{}
+	// signal is sent a value when new positions are added to pending.
+	signal chan struct{}
+}
+
+// newLightQueue returns an empty lightQueue.
+func newLightQueue() *lightQueue {
+	return &lightQueue{
+		pending: make(map[ChunkPos]map[cube.Pos]struct{}),
+		full:    make(map[ChunkPos]struct{}),
+		signal:  make(chan struct{}, 1),
+	}
+}
+
+// add adds a position to the lightQueue and wakes up the light worker.
+func (q *lightQueue) add(pos cube.Pos) {
+	chunkPos := chunkPosFromBlockPos(pos)
+
+	q.mu.Lock()
+	positions, ok := q.pending[chunkPos]
+	if !ok {
+		positions = make(map[cube.Pos]struct{})
+		q.pending[chunkPos] = positions
+	}
+	positions[pos] = struct{}{}
+	q.mu.Unlock()
+	q.wake()
+}
+
+// addChunk adds a chunk of which the light should be recalculated entirely to the lightQueue and wakes up the
+// light worker.
+func (q *lightQueue) addChunk(pos ChunkPos) {
+	q.mu.Lock()
+	q.full[pos] = struct{}{}
+	delete(q.pending, pos)
+	q.mu.Unlock()
+	q.wake()
+}
+
+// wake wakes up the light worker if it is not already awake.
+func (q *lightQueue) wake() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// take removes and returns all chunks and positions pending in the lightQueue.
+func (q *lightQueue) take() (full map[ChunkPos]struct{}, pending map[ChunkPos]map[cube.Pos]struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	full, pending = q.full, q.pending
+	q.full, q.pending = make(map[ChunkPos]struct{}), make(map[ChunkPos]map[cube.Pos]struct{})
+	for pos := range full {
+		delete(pending, pos)
+	}
+	return full, pending
+}
+
+// lightChanged checks if the light emitted or filtered at a position in a Column changes from changing the
+// blocks at that position from the runtime IDs before to the runtime IDs after. before and after hold the
+// runtime IDs of the first and second layer.
+func lightChanged(before, after [2]uint32) bool {
+	for i := range before {
+		if chunk.LightBlocks[before[i]] != chunk.LightBlocks[after[i]] || chunk.FilteringBlocks[before[i]] != chunk.FilteringBlocks[after[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// layers returns the runtime IDs of the blocks on the first and second layer at a position in the Column.
+func (c *Column) layers(pos cube.Pos) [2]uint32 {
+	x, y, z := uint8(pos[0]), int16(pos[1]), uint8(pos[2])
+	return [2]uint32{c.Block(x, y, z, 0), c.Block(x, y, z, 1)}
+}
+
+// lightWorker updates the light of blocks queued in the lightQueue of the World until the World is closed.
+func (w *World) lightWorker() {
+	w.running.Add(1)
+	for {
+		select {
+		case <-w.light.signal:
+			full, pending := w.light.take()
+			for pos := range full {
+				w.relightChunk(pos)
+			}
+			for pos, positions := range pending {
+				w.relight(pos, maps.Keys(positions))
+			}
+		case <-w.closing:
+			w.running.Done()
+			return
+		}
+	}
+}
+
+// relightChunk recalculates the light of the chunk at the ChunkPos passed entirely and spreads it into the
+// chunks surrounding it. If the chunk is no longer loaded, nothing happens.
+func (w *World) relightChunk(pos ChunkPos) {
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
+	c, ok := w.chunks[pos]
+	if !ok {
+		return
+	}
+	c.Lock()
+	chunk.LightArea([]*chunk.Chunk{c.Chunk}, int(pos[0]), int(pos[1])).Fill()
+	c.Unlock()
+	w.calculateLight(pos)
+}
+
+// relight incrementally updates the light around the positions passed, which must all be in the chunk at the
+// ChunkPos passed. If the chunk is no longer loaded, nothing happens: Its light is recalculated when it is loaded
+// again. If any of the chunks surrounding it are not loaded, light is only updated within the chunk itself.
+func (w *World) relight(pos ChunkPos, positions []cube.Pos) {
+	w.chunkMu.Lock()
+	if _, ok := w.chunks[pos]; !ok {
+		w.chunkMu.Unlock()
+		return
+	}
+	columns, complete := make([]*Column, 0, 9), true
+	for z := int32(-1); z <= 1; z++ {
+		for x := int32(-1); x <= 1; x++ {
+			neighbour, ok := w.chunks[ChunkPos{pos[0] + x, pos[1] + z}]
+			complete = complete && ok
+			columns = append(columns, neighbour)
+		}
+	}
+	if !complete {
+		columns = []*Column{w.chunks[pos]}
+	}
+	for _, c := range columns {
+		c.Lock()
+	}
+	w.chunkMu.Unlock()
+
+	c := make([]*chunk.Chunk, len(columns))
+	for i := range columns {
+		c[i] = columns[i].Chunk
+	}
+	if len(c) == 1 {
+		chunk.LightArea(c, int(pos[0]), int(pos[1])).Relight(positions)
+	} else {
+		chunk.LightArea(c, int(pos[0])-1, int(pos[1])-1).Relight(positions)
+	}
+	for _, c := range columns {
+		c.Unlock()
+	}
+}
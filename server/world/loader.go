@@ -75,7 +75,8 @@ func (l *Loader) Move(pos mgl64.Vec3) {
 
 // Load loads n chunks around the centre of the chunk, starting with the middle and working outwards. For
 // every chunk loaded, the Viewer passed through construction in New has its ViewChunk method called.
-// Load does nothing for n <= 0.
+// Chunks that are still being loaded or generated in the background are skipped and loaded during a later
+// call to Load, so Load may load fewer than n chunks. Load does nothing for n <= 0.
 func (l *Loader) Load(n int) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -83,12 +84,14 @@ func (l *Loader) Load(n int) {
 	if l.closed || l.w == nil {
 		return
 	}
-	for i := 0; i < n; i++ {
-		if len(l.loadQueue) == 0 {
-			break
+	for i, loaded := 0, 0; loaded < n && i < len(l.loadQueue); {
+		pos := l.loadQueue[i]
+		if !l.w.chunkReady(pos) {
+			// The chunk is still being loaded or generated in the background. Rather than waiting for it, we
+			// move on to the next chunk and try again during the next call to Load.
+			i++
+			continue
 		}
-
-		pos := l.loadQueue[0]
 		c := l.w.chunk(pos)
 
 		l.viewer.ViewChunk(pos, c.Chunk, c.BlockEntities)
@@ -96,9 +99,9 @@ func (l *Loader) Load(n int) {
 
 		l.loaded[pos] = c
 
-		// Shift the first element from the load queue off so that we can take a new one during the next
-		// iteration.
-		l.loadQueue = l.loadQueue[1:]
+		// Remove the chunk from the load queue so that we can take a new one during the next iteration.
+		l.loadQueue = append(l.loadQueue[:i], l.loadQueue[i+1:]...)
+		loaded++
 	}
 }
 
@@ -0,0 +1,102 @@
+package mcdb
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"sync"
+)
+
+// SubChunkCompression is a compression applied to the sub chunk payloads of a
+// DB before they are written to the database. It is applied on top of the
+// block compression of the database (Config.Compression) and generally leads
+// to much smaller worlds, as sub chunks are compressed as a whole rather than
+// in blocks.
+type SubChunkCompression int
+
+const (
+	// SubChunkCompressionNone stores sub chunk payloads as is. Worlds stored
+	// using SubChunkCompressionNone may be opened by Minecraft.
+	SubChunkCompressionNone SubChunkCompression = iota
+	// SubChunkCompressionZstd compresses sub chunk payloads using zstd. Worlds
+	// with sub chunks compressed using zstd may only be opened by Dragonfly.
+	SubChunkCompressionZstd
+)
+
+// String returns the name of the SubChunkCompression.
+func (c SubChunkCompression) String() string {
+	switch c {
+	case SubChunkCompressionNone:
+		return "none"
+	case SubChunkCompressionZstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("SubChunkCompression(%v)", int(c))
+}
+
+// zstdMagic is the magic number found at the start of every zstd frame. Sub
+// chunk payloads always start with their version, which is never equal to the
+// first byte of zstdMagic, so compressed payloads can be told apart from
+// uncompressed ones regardless of the SubChunkCompression used to write them.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// zstdDecoder is used to decompress all sub chunks compressed using zstd. It
+// is safe for concurrent use when using DecodeAll.
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+
+// subChunkCompressor compresses sub chunk payloads before they are written to
+// the database.
+type subChunkCompressor struct {
+	c   SubChunkCompression
+	enc *zstd.Encoder
+	// mu protects buf, which is reused by compress to reduce allocations.
+	mu  sync.Mutex
+	buf []byte
+}
+
+// newSubChunkCompressor returns a subChunkCompressor that compresses using the
+// SubChunkCompression passed at the level passed. Level 0 selects the default
+// level of the compression.
+func newSubChunkCompressor(c SubChunkCompression, level int) (*subChunkCompressor, error) {
+	comp := &subChunkCompressor{c: c}
+	switch c {
+	case SubChunkCompressionNone:
+	case SubChunkCompressionZstd:
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		enc, err := zstd.NewWriter(nil, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create zstd encoder: %w", err)
+		}
+		comp.enc = enc
+	default:
+		return nil, fmt.Errorf("unknown sub chunk compression %v", c)
+	}
+	return comp, nil
+}
+
+// compress compresses the sub chunk payload passed.
+func (comp *subChunkCompressor) compress(sub []byte) []byte {
+	if comp.enc == nil {
+		return sub
+	}
+	comp.mu.Lock()
+	defer comp.mu.Unlock()
+	comp.buf = comp.enc.EncodeAll(sub, comp.buf[:0])
+	return bytes.Clone(comp.buf)
+}
+
+// decompressSubChunk decompresses the sub chunk payload passed if it was
+// compressed. Uncompressed payloads are returned as is.
+func decompressSubChunk(sub []byte) ([]byte, error) {
+	if !bytes.HasPrefix(sub, zstdMagic) {
+		return sub, nil
+	}
+	data, err := zstdDecoder.DecodeAll(sub, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decompress zstd: %w", err)
+	}
+	return data, nil
+}
@@ -7,23 +7,16 @@ import (
 	"github.com/df-mc/dragonfly/server/world/mcdb/leveldat"
 	"github.com/df-mc/goleveldb/leveldb"
 	"github.com/df-mc/goleveldb/leveldb/opt"
-	"github.com/sirupsen/logrus"
+	"log/slog"
 	"os"
 	"path/filepath"
 )
 
-// Logger is a logger implementation that may be passed to the Log field of Config. World will send errors and debug
-// messages to this Logger when appropriate.
-type Logger interface {
-	Errorf(format string, a ...any)
-	Debugf(format string, a ...any)
-}
-
 // Config holds the optional parameters of a DB.
 type Config struct {
 	// Log is the Logger that will be used to log errors and debug messages to.
-	// If set to nil, a Logrus logger will be used.
-	Log Logger
+	// If set to nil, slog.Default() is used.
+	Log *slog.Logger
 	// Compression specifies the compression to use for compressing new data in
 	// the database. Decompression of the database will happen based on IDs
 	// found in the compressed blocks and is therefore uninfluenced by this
@@ -34,6 +27,17 @@ type Config struct {
 	// lead to better compression ratios at the expense of slightly higher
 	// memory usage while (de)compressing.
 	BlockSize int
+	// SubChunkCompression specifies an additional compression applied to the
+	// sub chunks of the world before they are written to the database. Sub
+	// chunks are read regardless of the SubChunkCompression they were written
+	// with. If left empty, SubChunkCompressionNone is used, so that the world
+	// may still be opened by Minecraft.
+	SubChunkCompression SubChunkCompression
+	// CompressionLevel specifies the level of the SubChunkCompression, such
+	// as 1-22 for zstd. Higher levels lead to better compression ratios at the
+	// expense of more CPU time spent compressing. If left empty, the default
+	// level of the SubChunkCompression is used.
+	CompressionLevel int
 	// ReadOnly opens the DB in read-only mode. This will leave the data in the
 	// database unedited.
 	ReadOnly bool
@@ -49,7 +53,7 @@ type Config struct {
 // returned.
 func (conf Config) Open(dir string) (*DB, error) {
 	if conf.Log == nil {
-		conf.Log = logrus.New()
+		conf.Log = slog.Default()
 	}
 	if conf.BlockSize == 0 {
 		conf.BlockSize = 16 * opt.KiB
@@ -57,9 +61,13 @@ func (conf Config) Open(dir string) (*DB, error) {
 	if len(conf.Entities.Types()) == 0 {
 		conf.Entities = entity.DefaultRegistry
 	}
+	comp, err := newSubChunkCompressor(conf.SubChunkCompression, conf.CompressionLevel)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
 	_ = os.MkdirAll(filepath.Join(dir, "db"), 0777)
 
-	db := &DB{conf: conf, dir: dir, ldat: &leveldat.Data{}}
+	db := &DB{conf: conf, dir: dir, ldat: &leveldat.Data{}, comp: comp}
 	if _, err := os.Stat(filepath.Join(dir, "level.dat")); os.IsNotExist(err) {
 		// A level.dat was not currently present for the world.
 		db.ldat.FillDefault()
@@ -26,6 +26,7 @@ type DB struct {
 	dir  string
 	ldat *leveldat.Data
 	set  *world.Settings
+	comp *subChunkCompressor
 }
 
 // Open creates a new provider reading and writing from/to files under the path
@@ -42,9 +43,13 @@ func (db *DB) Settings() *world.Settings {
 	return db.set
 }
 
-// SaveSettings saves the world.Settings passed to the level.dat.
+// SaveSettings saves the world.Settings passed to the level.dat, which is
+// written to disk immediately.
 func (db *DB) SaveSettings(s *world.Settings) {
 	db.ldat.PutSettings(s)
+	if err := db.writeLevelDat(); err != nil {
+		db.conf.Log.Error("Failed saving settings.", "err", err)
+	}
 }
 
 // playerData holds the fields that indicate where player data is stored for a player with a specific UUID.
@@ -130,6 +135,30 @@ func (db *DB) SavePlayerSpawnPosition(id uuid.UUID, pos cube.Pos) error {
 	return nil
 }
 
+// LoadStructure loads the world.StructureTemplate stored under the name passed.
+// If no structure template with the name exists, errors.Is(err,
+// leveldb.ErrNotFound) equals true.
+func (db *DB) LoadStructure(name string) (*world.StructureTemplate, error) {
+	data, err := db.ldb.Get([]byte(keyStructureTemplate+name), nil)
+	if err != nil {
+		return nil, err
+	}
+	return world.DecodeStructureTemplate(bytes.NewReader(data))
+}
+
+// StoreStructure stores the world.StructureTemplate passed under the name
+// passed.
+func (db *DB) StoreStructure(name string, s *world.StructureTemplate) error {
+	buf := bytes.NewBuffer(nil)
+	if err := s.Encode(buf); err != nil {
+		return err
+	}
+	if err := db.ldb.Put([]byte(keyStructureTemplate+name), buf.Bytes(), nil); err != nil {
+		return fmt.Errorf("error writing structure template %v: %w", name, err)
+	}
+	return nil
+}
+
 // LoadColumn reads a world.Column from the DB at a position and dimension in
 // the DB. If no column at that position exists, errors.Is(err,
 // leveldb.ErrNotFound) equals true.
@@ -153,7 +182,7 @@ func (db *DB) column(k dbKey) (*world.Column, error) {
 		return nil, fmt.Errorf("read version: %w", err)
 	}
 	if ver != chunkVersion {
-		db.conf.Log.Debugf("column %v (%v): unsupported chunk version %v, trying to load anyway", k.pos, k.dim, ver)
+		db.conf.Log.Debug("Unsupported chunk version, trying to load anyway.", "pos", k.pos, "dimension", k.dim, "version", ver)
 	}
 	cdata.Biomes, err = db.biomes(k)
 	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
@@ -230,44 +259,11 @@ func (db *DB) subChunks(k dbKey) ([][]byte, error) {
 		} else if err != nil {
 			return nil, fmt.Errorf("sub chunk %v: %w", int8(i), err)
 		}
-	}
-	return sub, nil
-}
-
-func (db *DB) entities(k dbKey) ([]world.Entity, error) {
-	data, err := db.ldb.Get(k.Sum(keyEntities), nil)
-	if err != nil {
-		return nil, err
-	}
-	var entities []world.Entity
-
-	buf := bytes.NewBuffer(data)
-	dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
-
-	var m map[string]any
-	for buf.Len() != 0 {
-		maps.Clear(m)
-		if err := dec.Decode(&m); err != nil {
-			return nil, fmt.Errorf("decode nbt: %w", err)
-		}
-		id, ok := m["identifier"]
-		if !ok {
-			db.conf.Log.Errorf("missing identifier field in %v", m)
-			continue
-		}
-		name, _ := id.(string)
-		t, ok := db.conf.Entities.Lookup(name)
-		if !ok {
-			db.conf.Log.Errorf("entity %v was not registered (%v)", name, m)
-			continue
-		}
-		if s, ok := t.(world.SaveableEntityType); ok {
-			if v := s.DecodeNBT(m); v != nil {
-				entities = append(entities, v)
-			}
+		if sub[i], err = decompressSubChunk(sub[i]); err != nil {
+			return nil, fmt.Errorf("sub chunk %v: %w", int8(i), err)
 		}
 	}
-	return entities, nil
+	return sub, nil
 }
 
 func (db *DB) blockEntities(k dbKey, c *chunk.Chunk) (map[cube.Pos]world.Block, error) {
@@ -292,15 +288,14 @@ func (db *DB) blockEntities(k dbKey, c *chunk.Chunk) (map[cube.Pos]world.Block,
 		id := c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0)
 		b, ok := world.BlockByRuntimeID(id)
 		if !ok {
-			db.conf.Log.Errorf("no block registered with runtime id %v", id)
+			db.conf.Log.Error("No block registered with runtime ID.", "id", id)
 			continue
 		}
-		nbter, ok := b.(world.NBTer)
-		if !ok {
-			db.conf.Log.Errorf("block %#v has nbt but does not implement world.nbter", b)
+		if _, ok := world.EncodeBlockNBT(b); !ok {
+			db.conf.Log.Error("Block has NBT but does not have a block entity.", "block", fmt.Sprintf("%#v", b))
 			continue
 		}
-		blockEntities[pos] = nbter.DecodeNBT(m).(world.Block)
+		blockEntities[pos] = world.DecodeBlockNBT(b, m)
 	}
 	return blockEntities, nil
 }
@@ -342,7 +337,7 @@ func (db *DB) storeBiomes(batch *leveldb.Batch, k dbKey, biomes []byte) {
 
 func (db *DB) storeSubChunks(batch *leveldb.Batch, k dbKey, subChunks [][]byte, r cube.Range) {
 	for i, sub := range subChunks {
-		batch.Put(k.Sum(keySubChunkData, byte(i+(r[0]>>4))), sub)
+		batch.Put(k.Sum(keySubChunkData, byte(i+(r[0]>>4))), db.comp.compress(sub))
 	}
 }
 
@@ -352,28 +347,6 @@ func (db *DB) storeFinalisation(batch *leveldb.Batch, k dbKey, finalisation uint
 	batch.Put(k.Sum(keyFinalisation), p)
 }
 
-func (db *DB) storeEntities(batch *leveldb.Batch, k dbKey, entities []world.Entity) {
-	if len(entities) == 0 {
-		batch.Delete(k.Sum(keyEntities))
-		return
-	}
-
-	buf := bytes.NewBuffer(nil)
-	enc := nbt.NewEncoderWithEncoding(buf, nbt.LittleEndian)
-	for _, e := range entities {
-		t, ok := e.Type().(world.SaveableEntityType)
-		if !ok {
-			continue
-		}
-		x := t.EncodeNBT(e)
-		x["identifier"] = t.EncodeEntity()
-		if err := enc.Encode(x); err != nil {
-			db.conf.Log.Errorf("store entities: error encoding NBT: %w", err)
-		}
-	}
-	batch.Put(k.Sum(keyEntities), buf.Bytes())
-}
-
 func (db *DB) storeBlockEntities(batch *leveldb.Batch, k dbKey, blockEntities map[cube.Pos]world.Block) {
 	if len(blockEntities) == 0 {
 		batch.Delete(k.Sum(keyBlockEntities))
@@ -383,14 +356,13 @@ func (db *DB) storeBlockEntities(batch *leveldb.Batch, k dbKey, blockEntities ma
 	buf := bytes.NewBuffer(nil)
 	enc := nbt.NewEncoderWithEncoding(buf, nbt.LittleEndian)
 	for pos, b := range blockEntities {
-		n, ok := b.(world.NBTer)
+		data, ok := world.EncodeBlockNBT(b)
 		if !ok {
 			continue
 		}
-		data := n.EncodeNBT()
 		data["x"], data["y"], data["z"] = int32(pos[0]), int32(pos[1]), int32(pos[2])
 		if err := enc.Encode(data); err != nil {
-			db.conf.Log.Errorf("store block entities: error encoding NBT: %w", err)
+			db.conf.Log.Error("Failed encoding NBT of block entity.", "err", err)
 		}
 	}
 	batch.Put(k.Sum(keyBlockEntities), buf.Bytes())
@@ -409,19 +381,28 @@ func (db *DB) NewColumnIterator(r *IteratorRange) *ColumnIterator {
 
 // Close closes the provider, saving any file that might need to be saved, such as the level.dat.
 func (db *DB) Close() error {
+	if err := db.writeLevelDat(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	return db.ldb.Close()
+}
+
+// writeLevelDat writes the level.dat and levelname.txt files of the DB to
+// disk.
+func (db *DB) writeLevelDat() error {
 	db.ldat.LastPlayed = time.Now().Unix()
 
 	var ldat leveldat.LevelDat
 	if err := ldat.Marshal(*db.ldat); err != nil {
-		return fmt.Errorf("close: %w", err)
+		return err
 	}
 	if err := ldat.WriteFile(filepath.Join(db.dir, "level.dat")); err != nil {
-		return fmt.Errorf("close: %w", err)
+		return err
 	}
 	if err := os.WriteFile(filepath.Join(db.dir, "levelname.txt"), []byte(db.ldat.LevelName), 0644); err != nil {
-		return fmt.Errorf("close: write levelname.txt: %w", err)
+		return fmt.Errorf("write levelname.txt: %w", err)
 	}
-	return db.ldb.Close()
+	return nil
 }
 
 // dbKey holds a position and dimension.
@@ -0,0 +1,184 @@
+package mcdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/logging"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"math/rand"
+)
+
+// entityVersion is the version of the entity NBT format written by the DB. It
+// is stored in the NBT of every entity so that data written by older versions
+// may be upgraded when it is read.
+const entityVersion = 1
+
+// entityVersionKey is the NBT key under which the entityVersion is stored.
+const entityVersionKey = "DragonflyVersion"
+
+// entityUpgraders holds the functions used to upgrade entity NBT. The function
+// at index i upgrades the NBT of an entity from version i to version i+1.
+var entityUpgraders = []func(m map[string]any){
+	// Version 0 is the format of entities without a version, such as those
+	// written by vanilla or by older versions of Dragonfly. These already hold
+	// all fields read by version 1.
+	func(map[string]any) {},
+}
+
+// encodeEntity encodes a world.Entity to a map of NBT data that includes its
+// identifier, entity version and the unique ID passed. If the entity cannot be
+// saved, false is returned.
+func encodeEntity(e world.Entity, id int64) (map[string]any, bool) {
+	t, ok := e.Type().(world.SaveableEntityType)
+	if !ok {
+		return nil, false
+	}
+	m := t.EncodeNBT(e)
+	if m == nil {
+		return nil, false
+	}
+	m["identifier"] = t.EncodeEntity()
+	m["UniqueID"] = id
+	m[entityVersionKey] = int32(entityVersion)
+	return m, true
+}
+
+// decodeEntity decodes a world.Entity from the NBT data passed, upgrading the
+// data to the current entityVersion first. If the entity type is not
+// registered or cannot be loaded from NBT, nil is returned.
+func (db *DB) decodeEntity(m map[string]any) (world.Entity, error) {
+	ver, _ := m[entityVersionKey].(int32)
+	if ver > entityVersion {
+		return nil, fmt.Errorf("entity version %v is newer than supported version %v", ver, entityVersion)
+	}
+	for _, upgrade := range entityUpgraders[ver:] {
+		upgrade(m)
+	}
+	name, ok := m["identifier"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing identifier field in %v", m)
+	}
+	t, ok := db.conf.Entities.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("entity %v was not registered (%v)", name, m)
+	}
+	if s, ok := t.(world.SaveableEntityType); ok {
+		return s.DecodeNBT(m), nil
+	}
+	return nil, nil
+}
+
+// entities reads all entities stored in a chunk. Entities are read from both
+// the legacy entity key holding all entities of the chunk and the actor keys
+// listed under the chunk's digest key.
+func (db *DB) entities(k dbKey) ([]world.Entity, error) {
+	var entities []world.Entity
+
+	read := func(data []byte) error {
+		buf := bytes.NewBuffer(data)
+		dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
+		for buf.Len() != 0 {
+			var m map[string]any
+			if err := dec.Decode(&m); err != nil {
+				return fmt.Errorf("decode nbt: %w", err)
+			}
+			e, err := db.decodeEntity(m)
+			if err != nil {
+				logging.With(db.conf.Log, logging.Entities).Error("Failed reading entity.", "err", err)
+				continue
+			}
+			if e != nil {
+				entities = append(entities, e)
+			}
+		}
+		return nil
+	}
+
+	legacy, err := db.ldb.Get(k.Sum(keyEntities), nil)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return nil, err
+	} else if err == nil {
+		if err := read(legacy); err != nil {
+			return nil, err
+		}
+	}
+	ids, err := db.actorIDs(k)
+	if err != nil {
+		return entities, err
+	}
+	for _, id := range ids {
+		data, err := db.ldb.Get(actorKey(id), nil)
+		if errors.Is(err, leveldb.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("actor %v: %w", id, err)
+		}
+		if err := read(data); err != nil {
+			return nil, fmt.Errorf("actor %v: %w", id, err)
+		}
+	}
+	return entities, nil
+}
+
+// actorIDs reads the unique IDs of the actors listed under the digest key of
+// a chunk.
+func (db *DB) actorIDs(k dbKey) ([]int64, error) {
+	data, err := db.ldb.Get(digestKey(k), nil)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(data)/8)
+	for i := 0; i+8 <= len(data); i += 8 {
+		ids = append(ids, int64(binary.LittleEndian.Uint64(data[i:])))
+	}
+	return ids, nil
+}
+
+// storeEntities stores the entities passed under their own actor keys and
+// lists their unique IDs under the digest key of the chunk. Actors previously
+// stored for the chunk and the legacy entity key are removed.
+func (db *DB) storeEntities(batch *leveldb.Batch, k dbKey, entities []world.Entity) {
+	if ids, err := db.actorIDs(k); err == nil {
+		for _, id := range ids {
+			batch.Delete(actorKey(id))
+		}
+	}
+	batch.Delete(k.Sum(keyEntities))
+
+	digest := make([]byte, 0, len(entities)*8)
+	for _, e := range entities {
+		id := rand.Int63()
+		m, ok := encodeEntity(e, id)
+		if !ok {
+			continue
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := nbt.NewEncoderWithEncoding(buf, nbt.LittleEndian).Encode(m); err != nil {
+			logging.With(db.conf.Log, logging.Entities).Error("Failed encoding NBT of entity.", "err", err)
+			continue
+		}
+		batch.Put(actorKey(id), buf.Bytes())
+		digest = binary.LittleEndian.AppendUint64(digest, uint64(id))
+	}
+	if len(digest) == 0 {
+		batch.Delete(digestKey(k))
+		return
+	}
+	batch.Put(digestKey(k), digest)
+}
+
+// digestKey returns the key under which the unique IDs of the actors in the
+// chunk of the dbKey passed are stored.
+func digestKey(k dbKey) []byte {
+	return append([]byte(keyActorDigest), index(k.pos, k.dim)...)
+}
+
+// actorKey returns the key under which the NBT of the actor with the unique ID
+// passed is stored.
+func actorKey(id int64) []byte {
+	return binary.LittleEndian.AppendUint64([]byte(keyActorPrefix), uint64(id))
+}
@@ -42,6 +42,18 @@ const (
 	keyBiomeData          = "BiomeData"
 	keyScoreboard         = "scoreboard"
 	keyLocalPlayer        = "~local_player"
+	// keyStructureTemplate is followed by the name of a structure template saved using a structure block, such
+	// as 'mystructure:house', and holds the structure template in the .mcstructure format.
+	keyStructureTemplate = "structuretemplate_"
+)
+
+// Keys used for storing actors. These are not prefixed by chunk coordinates.
+const (
+	// keyActorDigest is followed by the chunk index and holds the unique IDs of all actors in the chunk, each
+	// written as 8 bytes.
+	keyActorDigest = "digp"
+	// keyActorPrefix is followed by the 8 byte unique ID of an actor and holds the NBT compound tag of the actor.
+	keyActorPrefix = "actorprefix"
 )
 
 const (
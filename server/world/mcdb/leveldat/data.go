@@ -5,6 +5,8 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"math"
+	"reflect"
+	"strings"
 	"time"
 )
 
@@ -223,7 +225,7 @@ func (d *Data) Settings() *world.Settings {
 	d.WorldStartCount += 1
 	difficulty, _ := world.DifficultyByID(int(d.Difficulty))
 	mode, _ := world.GameModeByID(int(d.GameType))
-	return &world.Settings{
+	s := &world.Settings{
 		Name:            d.LevelName,
 		Spawn:           cube.Pos{int(d.SpawnX), int(d.SpawnY), int(d.SpawnZ)},
 		Time:            d.Time,
@@ -235,9 +237,38 @@ func (d *Data) Settings() *world.Settings {
 		WeatherCycle:    d.DoWeatherCycle,
 		CurrentTick:     d.CurrentTick,
 		DefaultGameMode: mode,
+		ForceGameMode:   d.ForceGameType,
 		Difficulty:      difficulty,
 		TickRange:       d.ServerChunkTickRange,
+		// Bedrock Edition has no maxEntityCramming game rule, so it is not
+		// stored in the level.dat.
+		MaxEntityCramming: 24,
 	}
+	for _, r := range world.GameRules() {
+		if f, ok := d.gameRuleField(r); ok {
+			switch f.Kind() {
+			case reflect.Bool:
+				s.SetGameRule(r, f.Bool())
+			case reflect.Int32:
+				s.SetGameRule(r, int(f.Int()))
+			}
+		}
+	}
+	return s
+}
+
+// gameRuleField returns the field of d that holds the value of the
+// world.GameRule passed. Game rules are stored in fields with an NBT name
+// equal to the lowercase name of the game rule.
+func (d *Data) gameRuleField(r world.GameRule) (reflect.Value, bool) {
+	name := strings.ToLower(r.Name())
+	v, t := reflect.ValueOf(d).Elem(), reflect.TypeOf(d).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("nbt"), ","); tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
 }
 
 // PutSettings updates d with the Settings stored in s.
@@ -260,6 +291,17 @@ func (d *Data) PutSettings(s *world.Settings) {
 	d.ServerChunkTickRange = s.TickRange
 	mode, _ := world.GameModeID(s.DefaultGameMode)
 	d.GameType = int32(mode)
+	d.ForceGameType = s.ForceGameMode
 	difficulty, _ := world.DifficultyID(s.Difficulty)
 	d.Difficulty = int32(difficulty)
+	for _, r := range world.GameRules() {
+		if f, ok := d.gameRuleField(r); ok {
+			switch v := s.GameRule(r).(type) {
+			case bool:
+				f.SetBool(v)
+			case int:
+				f.SetInt(int64(v))
+			}
+		}
+	}
 }
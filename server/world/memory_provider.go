@@ -0,0 +1,278 @@
+package world
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/google/uuid"
+	"maps"
+	"sync"
+)
+
+// Compile time checks to make sure MemoryProvider and OverlayProvider implement Provider and StructureProvider.
+var (
+	_ Provider          = (*MemoryProvider)(nil)
+	_ StructureProvider = (*MemoryProvider)(nil)
+	_ Provider          = (*OverlayProvider)(nil)
+	_ StructureProvider = (*OverlayProvider)(nil)
+)
+
+// MemoryProvider implements a Provider that keeps all world data in memory and never writes anything to disk.
+// Chunks unloaded by a World are kept by the MemoryProvider and returned when they are loaded again, but all
+// data is lost once the MemoryProvider is no longer used. It is typically used for temporary worlds, such as
+// the arenas of minigames, that should not leave any data behind.
+//
+// The zero value of MemoryProvider is ready for use. A MemoryProvider must not be copied after first use.
+type MemoryProvider struct {
+	// Set holds the Settings returned by Settings. If nil, default Settings are used.
+	Set *Settings
+	// Entities is the EntityRegistry used to restore the entities of chunks that are loaded again after being
+	// unloaded. Entities of which the type is not registered in Entities are discarded when their chunk is
+	// unloaded.
+	Entities EntityRegistry
+
+	mu         sync.Mutex
+	columns    map[memoryKey]memoryColumn
+	spawns     map[uuid.UUID]cube.Pos
+	structures map[string]*StructureTemplate
+}
+
+// memoryKey is the key under which a memoryColumn is stored in a MemoryProvider.
+type memoryKey struct {
+	pos ChunkPos
+	dim Dimension
+}
+
+// memoryColumn holds the encoded data of a Column stored in a MemoryProvider. Columns are encoded rather than
+// stored as is, so that the World that stored them is free to keep modifying them.
+type memoryColumn struct {
+	data          chunk.SerialisedData
+	blockEntities map[cube.Pos]map[string]any
+	entities      []map[string]any
+}
+
+// encodeMemoryColumn encodes the Column passed to a memoryColumn. Entities that cannot be saved are discarded.
+func encodeMemoryColumn(col *Column) memoryColumn {
+	stored := memoryColumn{
+		data:          chunk.Encode(col.Chunk, chunk.DiskEncoding),
+		blockEntities: make(map[cube.Pos]map[string]any, len(col.BlockEntities)),
+	}
+	for pos, b := range col.BlockEntities {
+		if data, ok := EncodeBlockNBT(b); ok {
+			stored.blockEntities[pos] = data
+		}
+	}
+	for _, e := range col.Entities {
+		t, ok := e.Type().(SaveableEntityType)
+		if !ok {
+			continue
+		}
+		if data := t.EncodeNBT(e); data != nil {
+			data["identifier"] = t.EncodeEntity()
+			stored.entities = append(stored.entities, data)
+		}
+	}
+	return stored
+}
+
+// decode decodes the memoryColumn to a new Column for the Dimension passed. Entities are decoded using the
+// EntityRegistry passed. The memoryColumn itself is left unchanged, so that it may be decoded again.
+func (stored memoryColumn) decode(dim Dimension, reg EntityRegistry) (*Column, error) {
+	c, err := chunk.DiskDecode(stored.data, dim.Range())
+	if err != nil {
+		return nil, err
+	}
+	col := newColumn(c)
+	for pos, data := range stored.blockEntities {
+		b, ok := BlockByRuntimeID(c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0))
+		if !ok {
+			continue
+		}
+		if hasBlockEntity(b) {
+			col.BlockEntities[pos] = DecodeBlockNBT(b, maps.Clone(data))
+		}
+	}
+	for _, data := range stored.entities {
+		name, _ := data["identifier"].(string)
+		t, ok := reg.Lookup(name)
+		if !ok {
+			continue
+		}
+		if s, ok := t.(SaveableEntityType); ok {
+			if e := s.DecodeNBT(maps.Clone(data)); e != nil {
+				col.Entities = append(col.Entities, e)
+			}
+		}
+	}
+	return col, nil
+}
+
+// Settings returns the Settings of the MemoryProvider.
+func (m *MemoryProvider) Settings() *Settings {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Set == nil {
+		m.Set = defaultSettings()
+	}
+	return m.Set
+}
+
+// SaveSettings does nothing, as the Settings returned by Settings are always up to date.
+func (m *MemoryProvider) SaveSettings(*Settings) {}
+
+// LoadPlayerSpawnPosition returns the spawn position of the player with the UUID passed if it was previously
+// saved using SavePlayerSpawnPosition.
+func (m *MemoryProvider) LoadPlayerSpawnPosition(id uuid.UUID) (cube.Pos, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pos, ok := m.spawns[id]
+	return pos, ok, nil
+}
+
+// SavePlayerSpawnPosition keeps the spawn position of the player with the UUID passed in memory.
+func (m *MemoryProvider) SavePlayerSpawnPosition(id uuid.UUID, pos cube.Pos) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.spawns == nil {
+		m.spawns = make(map[uuid.UUID]cube.Pos)
+	}
+	m.spawns[id] = pos
+	return nil
+}
+
+// LoadStructure returns the StructureTemplate previously stored under the name passed. If no structure
+// template with the name exists, errors.Is(err, leveldb.ErrNotFound) equals true.
+func (m *MemoryProvider) LoadStructure(name string) (*StructureTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.structures[name]
+	if !ok {
+		return nil, leveldb.ErrNotFound
+	}
+	return s, nil
+}
+
+// StoreStructure keeps the StructureTemplate passed in memory under the name passed.
+func (m *MemoryProvider) StoreStructure(name string, s *StructureTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.structures == nil {
+		m.structures = make(map[string]*StructureTemplate)
+	}
+	m.structures[name] = s
+	return nil
+}
+
+// LoadColumn returns the Column previously stored at a position and dimension. If no column was stored there,
+// errors.Is(err, leveldb.ErrNotFound) equals true.
+func (m *MemoryProvider) LoadColumn(pos ChunkPos, dim Dimension) (*Column, error) {
+	m.mu.Lock()
+	stored, ok := m.columns[memoryKey{pos: pos, dim: dim}]
+	m.mu.Unlock()
+	if !ok {
+		return nil, leveldb.ErrNotFound
+	}
+	return stored.decode(dim, m.Entities)
+}
+
+// StoreColumn keeps the Column passed in memory at a position and dimension.
+func (m *MemoryProvider) StoreColumn(pos ChunkPos, dim Dimension, col *Column) error {
+	stored := encodeMemoryColumn(col)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.columns == nil {
+		m.columns = make(map[memoryKey]memoryColumn)
+	}
+	m.columns[memoryKey{pos: pos, dim: dim}] = stored
+	return nil
+}
+
+// Close discards all data held by the MemoryProvider.
+func (m *MemoryProvider) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.columns, m.spawns, m.structures = nil, nil, nil
+	return nil
+}
+
+// OverlayProvider implements a copy-on-write Provider on top of another Provider. Data is read from the Base
+// Provider, but all changes are kept in memory and never written back to it, so that the Base Provider is left
+// untouched. An OverlayProvider may be used to load a template map, let players modify it and discard all
+// changes once the OverlayProvider is no longer used.
+//
+// The Base Provider is never closed by an OverlayProvider, so a single Base Provider may be shared by many
+// OverlayProviders, as long as it is safe for concurrent use. The Settings of the Base Provider are copied
+// when Settings is first called, so that changes to them are not shared between OverlayProviders either.
+type OverlayProvider struct {
+	// Base is the Provider that data is read from. Base is never written to.
+	Base Provider
+	// MemoryProvider holds all data changed since the OverlayProvider was created. Its Set field is filled
+	// with a copy of the Settings of Base if nil.
+	MemoryProvider
+}
+
+// Settings returns a copy of the Settings of the Base Provider.
+func (o *OverlayProvider) Settings() *Settings {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.Set == nil {
+		o.Set = o.Base.Settings().clone()
+	}
+	return o.Set
+}
+
+// LoadPlayerSpawnPosition returns the spawn position of the player with the UUID passed if changed since the
+// OverlayProvider was created, or the spawn position stored in the Base Provider otherwise.
+func (o *OverlayProvider) LoadPlayerSpawnPosition(id uuid.UUID) (cube.Pos, bool, error) {
+	if pos, ok, _ := o.MemoryProvider.LoadPlayerSpawnPosition(id); ok {
+		return pos, true, nil
+	}
+	return o.Base.LoadPlayerSpawnPosition(id)
+}
+
+// LoadStructure returns the StructureTemplate stored under the name passed if it was stored since the
+// OverlayProvider was created, or the one stored in the Base Provider otherwise.
+func (o *OverlayProvider) LoadStructure(name string) (*StructureTemplate, error) {
+	if s, err := o.MemoryProvider.LoadStructure(name); err == nil {
+		return s, nil
+	}
+	if p, ok := o.Base.(StructureProvider); ok {
+		return p.LoadStructure(name)
+	}
+	return nil, leveldb.ErrNotFound
+}
+
+// LoadColumn returns the Column at a position and dimension if it was stored since the OverlayProvider was
+// created, or the Column stored in the Base Provider otherwise.
+func (o *OverlayProvider) LoadColumn(pos ChunkPos, dim Dimension) (*Column, error) {
+	col, err := o.MemoryProvider.LoadColumn(pos, dim)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return o.Base.LoadColumn(pos, dim)
+	}
+	return col, err
+}
+
+// clone returns a copy of the Settings s that is not synchronised with s.
+func (s *Settings) clone() *Settings {
+	s.Lock()
+	defer s.Unlock()
+	return &Settings{
+		Name:              s.Name,
+		Spawn:             s.Spawn,
+		Time:              s.Time,
+		TimeCycle:         s.TimeCycle,
+		RainTime:          s.RainTime,
+		Raining:           s.Raining,
+		ThunderTime:       s.ThunderTime,
+		Thundering:        s.Thundering,
+		WeatherCycle:      s.WeatherCycle,
+		CurrentTick:       s.CurrentTick,
+		DefaultGameMode:   s.DefaultGameMode,
+		ForceGameMode:     s.ForceGameMode,
+		Difficulty:        s.Difficulty,
+		TickRange:         s.TickRange,
+		MaxEntityCramming: s.MaxEntityCramming,
+		GameRules:         maps.Clone(s.GameRules),
+	}
+}
@@ -0,0 +1,141 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"math"
+)
+
+const (
+	// naturalSpawnInterval is the interval in ticks at which entities are spawned naturally.
+	naturalSpawnInterval = 20
+	// naturalSpawnCap is the maximum number of entities of the same EntityType in a chunk. No more entities of
+	// an EntityType are spawned naturally in a chunk that already holds this many.
+	naturalSpawnCap = 4
+	// naturalSpawnMinDistance is the minimum horizontal distance in blocks between the centre of the chunk of
+	// a Loader and the position at which an entity is spawned naturally.
+	naturalSpawnMinDistance = 24
+	// naturalSpawnGroupSpread is the maximum horizontal distance in blocks between the entities of a group
+	// spawned naturally.
+	naturalSpawnGroupSpread = 4
+)
+
+// tickNaturalSpawning selects a random column in every entity ticking chunk every naturalSpawnInterval ticks
+// and spawns a group of entities from the pool of the biome at the surface of the column, if the biome
+// implements SpawningBiome.
+func (t ticker) tickNaturalSpawning(tick int64, loaders []ChunkPos) {
+	if tick%naturalSpawnInterval != 0 || !GameRuleDoMobSpawning.Value(t.w) {
+		return
+	}
+	var columns [][2]int
+	t.w.chunkMu.Lock()
+	for pos := range t.w.chunks {
+		if !t.w.tickets.entityTicking(pos) {
+			continue
+		}
+		v := t.w.r.Int31()
+		columns = append(columns, [2]int{int(pos[0]<<4) + int(v&0xf), int(pos[1]<<4) + int((v>>8)&0xf)})
+	}
+	t.w.chunkMu.Unlock()
+
+	for _, column := range columns {
+		if !nearLoader(column[0], column[1], loaders) {
+			t.w.spawnNaturally(column[0], column[1])
+		}
+	}
+}
+
+// nearLoader checks if the column at the x and z passed is within naturalSpawnMinDistance of the centre of
+// any of the loader chunk positions passed.
+func nearLoader(x, z int, loaders []ChunkPos) bool {
+	for _, pos := range loaders {
+		dx, dz := float64(x-int(pos[0]<<4)-8), float64(z-int(pos[1]<<4)-8)
+		if math.Sqrt(dx*dx+dz*dz) < naturalSpawnMinDistance {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnNaturally spawns a group of entities selected from the pool of the biome at the surface of the column
+// at the x and z passed. Nothing is spawned if the biome does not implement SpawningBiome or if the chunk
+// already holds naturalSpawnCap entities of the selected type.
+func (w *World) spawnNaturally(x, z int) {
+	pos, ok := w.naturalSpawnPos(x, z)
+	if !ok {
+		return
+	}
+	b, ok := w.Biome(pos).(SpawningBiome)
+	if !ok {
+		return
+	}
+	spawn, ok := selectBiomeSpawn(b.Spawns(), w)
+	if !ok {
+		return
+	}
+	chunkPos := chunkPosFromBlockPos(pos)
+	cx, cz := float64(chunkPos[0]<<4), float64(chunkPos[1]<<4)
+	box := cube.Box(cx, float64(w.Range()[0]), cz, cx+16, float64(w.Range()[1]+1), cz+16)
+
+	name := spawn.Type.EncodeEntity()
+	count := len(w.EntitiesWithin(box, func(e Entity) bool { return e.Type().EncodeEntity() != name }))
+
+	n := spawn.MinGroup
+	if spawn.MaxGroup > spawn.MinGroup {
+		n += w.r.Intn(spawn.MaxGroup - spawn.MinGroup + 1)
+	}
+	for i := 0; i < n && count < naturalSpawnCap; i++ {
+		if i != 0 {
+			// Spread the rest of the group around the position of the first entity, staying within the chunk
+			// so that the cap of the chunk is respected.
+			gx := min(max(x+w.r.Intn(naturalSpawnGroupSpread*2+1)-naturalSpawnGroupSpread, int(cx)), int(cx)+15)
+			gz := min(max(z+w.r.Intn(naturalSpawnGroupSpread*2+1)-naturalSpawnGroupSpread, int(cz)), int(cz)+15)
+			if pos, ok = w.naturalSpawnPos(gx, gz); !ok {
+				continue
+			}
+		}
+		w.AddEntity(spawn.New(pos.Vec3Middle()))
+		count++
+	}
+}
+
+// naturalSpawnPos returns the position at the surface of the column at the x and z passed that an entity may
+// be spawned at naturally. False is returned if the surface is not solid or if there is no room for an
+// entity above it.
+func (w *World) naturalSpawnPos(x, z int) (cube.Pos, bool) {
+	pos := cube.Pos{x, w.highestObstructingBlock(x, z), z}
+	if !w.Block(pos).Model().FaceSolid(pos, cube.FaceUp, w) {
+		return pos, false
+	}
+	pos = pos.Side(cube.FaceUp)
+	if pos.Side(cube.FaceUp).OutOfBounds(w.Range()) {
+		return pos, false
+	}
+	if BlockRuntimeID(w.Block(pos)) != airRID || BlockRuntimeID(w.Block(pos.Side(cube.FaceUp))) != airRID {
+		return pos, false
+	}
+	return pos, true
+}
+
+// selectBiomeSpawn selects a random BiomeSpawn from the pool passed, weighted by the Weight of the entries.
+// Entries with a nil New or a Weight of 0 or lower are never selected.
+func selectBiomeSpawn(pool []BiomeSpawn, w *World) (BiomeSpawn, bool) {
+	total := 0
+	for _, spawn := range pool {
+		if spawn.New != nil && spawn.Weight > 0 {
+			total += spawn.Weight
+		}
+	}
+	if total == 0 {
+		return BiomeSpawn{}, false
+	}
+	n := w.r.Intn(total)
+	for _, spawn := range pool {
+		if spawn.New == nil || spawn.Weight <= 0 {
+			continue
+		}
+		if n -= spawn.Weight; n < 0 {
+			return spawn, true
+		}
+	}
+	return BiomeSpawn{}, false
+}
@@ -5,6 +5,14 @@ import "image/color"
 // HugeExplosion is a particle shown when TNT or a creeper explodes.
 type HugeExplosion struct{ particle }
 
+// SonicExplosion is a particle shown along the path of the sonic boom attack
+// of a warden.
+type SonicExplosion struct{ particle }
+
+// EyeOfEnderDeath is a particle shown when an eye of ender shatters at the end
+// of its flight.
+type EyeOfEnderDeath struct{ particle }
+
 // EndermanTeleport is a particle that shows up when an enderman teleports.
 type EndermanTeleport struct{ particle }
 
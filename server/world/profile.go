@@ -0,0 +1,151 @@
+package world
+
+import (
+	"sync"
+	"time"
+
+	"github.com/df-mc/atomic"
+)
+
+// TickSystem is a system of a World that is updated every tick, such as entities or random block ticks. The
+// time spent on each TickSystem is measured while a World is being profiled.
+type TickSystem string
+
+const (
+	// TickSystemWeather covers advancing the time and weather of a World, lightning strikes and precipitation.
+	TickSystemWeather TickSystem = "weather"
+	// TickSystemEntities covers ticking all entities in a World.
+	TickSystemEntities TickSystem = "entities"
+	// TickSystemRandomTicks covers random ticks of blocks, such as crops growing.
+	TickSystemRandomTicks TickSystem = "random_ticks"
+	// TickSystemBlockEntities covers ticking blocks that are block entities, such as furnaces and hoppers.
+	TickSystemBlockEntities TickSystem = "block_entities"
+	// TickSystemScheduledUpdates covers block updates that were scheduled using World.ScheduleBlockUpdate.
+	TickSystemScheduledUpdates TickSystem = "scheduled_updates"
+	// TickSystemNeighbourUpdates covers block updates caused by a neighbouring block changing.
+	TickSystemNeighbourUpdates TickSystem = "neighbour_updates"
+	// TickSystemNaturalSpawning covers spawning entities naturally in biomes implementing SpawningBiome.
+	TickSystemNaturalSpawning TickSystem = "natural_spawning"
+)
+
+// TickProfile holds the time spent on the different systems of a World over a number of ticks. A TickProfile
+// may be obtained by calling World.StartProfiling and World.StopProfiling.
+type TickProfile struct {
+	// Ticks is the number of ticks that were profiled.
+	Ticks int64
+	// Duration is the total time spent ticking the World over all ticks profiled.
+	Duration time.Duration
+	// Systems holds the total time spent on each TickSystem over all ticks profiled.
+	Systems map[TickSystem]time.Duration
+	// BlockEntities holds the total time spent ticking block entities, indexed by the name of the block, such
+	// as 'minecraft:hopper'.
+	BlockEntities map[string]time.Duration
+	// Entities holds the total time spent ticking entities, indexed by the encoded entity type, such as
+	// 'minecraft:zombie'.
+	Entities map[string]time.Duration
+}
+
+// Average returns the average time spent on a single tick of the World.
+func (p TickProfile) Average() time.Duration {
+	if p.Ticks == 0 {
+		return 0
+	}
+	return p.Duration / time.Duration(p.Ticks)
+}
+
+// profiler measures the time spent on each TickSystem of a World while enabled.
+type profiler struct {
+	enabled atomic.Bool
+
+	mu      sync.Mutex
+	profile TickProfile
+}
+
+// StartProfiling starts measuring the time spent on the different systems of the World every tick. Any
+// profile that was previously being recorded is discarded. The resulting TickProfile may be obtained by
+// calling World.StopProfiling.
+func (w *World) StartProfiling() {
+	if w == nil {
+		return
+	}
+	w.profiler.mu.Lock()
+	defer w.profiler.mu.Unlock()
+	w.profiler.profile = TickProfile{
+		Systems:       make(map[TickSystem]time.Duration),
+		BlockEntities: make(map[string]time.Duration),
+		Entities:      make(map[string]time.Duration),
+	}
+	w.profiler.enabled.Store(true)
+}
+
+// Profiling checks if the World is currently being profiled using World.StartProfiling.
+func (w *World) Profiling() bool {
+	return w != nil && w.profiler.enabled.Load()
+}
+
+// StopProfiling stops profiling the World and returns the TickProfile recorded since World.StartProfiling was
+// called. If the World was not being profiled, false is returned.
+func (w *World) StopProfiling() (TickProfile, bool) {
+	if w == nil || !w.profiler.enabled.Swap(false) {
+		return TickProfile{}, false
+	}
+	w.profiler.mu.Lock()
+	defer w.profiler.mu.Unlock()
+	p := w.profiler.profile
+	w.profiler.profile = TickProfile{}
+	return p, true
+}
+
+// start returns the current time if the profiler is enabled, or a zero time.Time if not.
+func (p *profiler) start() time.Time {
+	if !p.enabled.Load() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// tick records the total time spent on a single tick started at the time passed.
+func (p *profiler) tick(start time.Time) {
+	p.record(start, func(profile *TickProfile, d time.Duration) {
+		profile.Ticks++
+		profile.Duration += d
+	})
+}
+
+// system records the time spent on a TickSystem since the start time passed.
+func (p *profiler) system(s TickSystem, start time.Time) {
+	p.record(start, func(profile *TickProfile, d time.Duration) {
+		profile.Systems[s] += d
+	})
+}
+
+// blockEntity records the time spent ticking a block entity with the name passed since the start time passed.
+func (p *profiler) blockEntity(name string, start time.Time) {
+	p.record(start, func(profile *TickProfile, d time.Duration) {
+		profile.BlockEntities[name] += d
+	})
+}
+
+// entity records the time spent ticking an entity of the type passed since the start time passed.
+func (p *profiler) entity(t EntityType, start time.Time) {
+	p.record(start, func(profile *TickProfile, d time.Duration) {
+		profile.Entities[t.EncodeEntity()] += d
+	})
+}
+
+// record calls f with the TickProfile being recorded and the time passed since start. If start is zero, or if
+// profiling was stopped in the meantime, f is not called.
+func (p *profiler) record(start time.Time, f func(profile *TickProfile, d time.Duration)) {
+	if start.IsZero() {
+		return
+	}
+	d := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.profile.Systems == nil {
+		// Profiling was stopped after start was obtained.
+		return
+	}
+	f(&p.profile, d)
+}
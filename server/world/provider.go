@@ -9,6 +9,12 @@ import (
 
 // Provider represents a value that may provide world data to a World value. It usually does the reading and
 // writing of the world data so that the World may use it.
+//
+// Dragonfly ships with mcdb.DB, which stores worlds in the LevelDB format of Minecraft: Bedrock Edition, and
+// sqldb.DB, which stores worlds in a PostgreSQL or MySQL database. Other backends may be implemented by
+// satisfying this interface: A Provider may be shared by the Worlds of multiple dimensions, and its methods
+// may be called from multiple goroutines, so implementations must be safe for concurrent use. A Provider may
+// additionally implement StructureProvider to store structure templates.
 type Provider interface {
 	io.Closer
 	// Settings loads the settings for a World and returns them.
@@ -30,6 +36,16 @@ type Provider interface {
 	StoreColumn(pos ChunkPos, dim Dimension, col *Column) error
 }
 
+// StructureProvider is a Provider that is able to store the structure templates saved using structure blocks.
+// If the Provider of a World does not implement StructureProvider, structure templates are only kept in memory.
+type StructureProvider interface {
+	// LoadStructure loads the StructureTemplate stored under the name passed. If no structure template with
+	// the name exists, errors.Is(err, leveldb.ErrNotFound) equals true.
+	LoadStructure(name string) (*StructureTemplate, error)
+	// StoreStructure stores the StructureTemplate passed under the name passed.
+	StoreStructure(name string, s *StructureTemplate) error
+}
+
 // Compile time check to make sure NopProvider implements Provider.
 var _ Provider = (*NopProvider)(nil)
 
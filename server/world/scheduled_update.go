@@ -0,0 +1,112 @@
+package world
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+// scheduledUpdateBudget is the maximum amount of time spent on executing scheduled block updates in a single
+// tick. Updates that could not be executed within the budget are executed in the next tick, so that large
+// amounts of updates, such as those caused by a big flood, are spread out over multiple ticks instead of
+// freezing the World.
+const scheduledUpdateBudget = time.Second / 40
+
+// scheduledUpdates holds the block updates scheduled in a World. Updates are deduplicated by position and
+// bucketed by the tick at which they are due, so that only updates that are actually due are looked at every
+// tick. Updates in chunks that are not loaded are parked until the chunk is loaded again.
+type scheduledUpdates struct {
+	// at holds the tick at which an update is scheduled, indexed by the position of the update.
+	at map[cube.Pos]int64
+	// buckets holds the positions of updates that are due at a tick, indexed by that tick.
+	buckets map[int64][]cube.Pos
+	// ticks is a min-heap of all ticks that buckets has an entry for.
+	ticks tickHeap
+	// parked holds the positions of updates that were due while their chunk was not loaded.
+	parked map[ChunkPos][]cube.Pos
+}
+
+// newScheduledUpdates returns an empty scheduledUpdates queue.
+func newScheduledUpdates() *scheduledUpdates {
+	return &scheduledUpdates{
+		at:      make(map[cube.Pos]int64),
+		buckets: make(map[int64][]cube.Pos),
+		parked:  make(map[ChunkPos][]cube.Pos),
+	}
+}
+
+// add schedules an update at a position for the tick passed. If an update was already scheduled at the
+// position, add returns false and the update is not scheduled again.
+func (s *scheduledUpdates) add(pos cube.Pos, tick int64) bool {
+	if _, ok := s.at[pos]; ok {
+		return false
+	}
+	s.at[pos] = tick
+	bucket, ok := s.buckets[tick]
+	if !ok {
+		heap.Push(&s.ticks, tick)
+	}
+	s.buckets[tick] = append(bucket, pos)
+	return true
+}
+
+// due removes and returns all updates that are due at the tick passed. The positions returned are sorted by
+// chunk, so that updates in the same chunk are executed right after each other.
+func (s *scheduledUpdates) due(tick int64) []cube.Pos {
+	var positions []cube.Pos
+	for len(s.ticks) > 0 && s.ticks[0] <= tick {
+		t := heap.Pop(&s.ticks).(int64)
+		positions = append(positions, s.buckets[t]...)
+		delete(s.buckets, t)
+	}
+	for _, pos := range positions {
+		delete(s.at, pos)
+	}
+	slices.SortFunc(positions, func(a, b cube.Pos) int {
+		ca, cb := chunkPosFromBlockPos(a), chunkPosFromBlockPos(b)
+		if ca[0] != cb[0] {
+			return cmp.Compare(ca[0], cb[0])
+		}
+		if ca[1] != cb[1] {
+			return cmp.Compare(ca[1], cb[1])
+		}
+		return cmp.Compare(a[1], b[1])
+	})
+	return positions
+}
+
+// park parks the update at the position passed until the chunk it is in is loaded.
+func (s *scheduledUpdates) park(pos cube.Pos) {
+	chunkPos := chunkPosFromBlockPos(pos)
+	s.parked[chunkPos] = append(s.parked[chunkPos], pos)
+}
+
+// unpark schedules all updates parked in the chunk passed for the tick passed.
+func (s *scheduledUpdates) unpark(chunkPos ChunkPos, tick int64) {
+	positions, ok := s.parked[chunkPos]
+	if !ok {
+		return
+	}
+	delete(s.parked, chunkPos)
+	for _, pos := range positions {
+		s.add(pos, tick)
+	}
+}
+
+// tickHeap is a min-heap of ticks, implementing heap.Interface.
+type tickHeap []int64
+
+func (h tickHeap) Len() int           { return len(h) }
+func (h tickHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h tickHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *tickHeap) Push(x any)        { *h = append(*h, x.(int64)) }
+func (h *tickHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
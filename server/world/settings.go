@@ -35,22 +35,34 @@ type Settings struct {
 	CurrentTick int64
 	// DefaultGameMode is the GameMode assigned to players that join the World for the first time.
 	DefaultGameMode GameMode
+	// ForceGameMode specifies if players are given the DefaultGameMode whenever they enter the World, rather
+	// than only when joining for the first time.
+	ForceGameMode bool
 	// Difficulty is the difficulty of the World. Behaviour of hunger, regeneration and monsters differs based on the
 	// difficulty of the world.
 	Difficulty Difficulty
 	// TickRange is the radius in chunks around a Viewer that has its blocks and entities ticked when the world is
 	// ticked. If set to 0, blocks and entities will never be ticked.
 	TickRange int32
+	// MaxEntityCramming is the maximum number of entities that may be pushed into the same space before they start
+	// taking damage. If set to 0, entities never take damage from cramming.
+	MaxEntityCramming int32
+	// GameRules holds the values of game rules that were changed from their default, indexed by the name of the
+	// GameRule. Game rules that are the same as other fields of the Settings, such as TimeCycle, are not held
+	// here. GameRule values should be obtained using World.GameRule or the Value method of the GameRule.
+	GameRules map[string]any
 }
 
 // defaultSettings returns the default Settings for a new World.
 func defaultSettings() *Settings {
 	return &Settings{
-		Name:            "World",
-		DefaultGameMode: GameModeSurvival,
-		Difficulty:      DifficultyNormal,
-		TimeCycle:       true,
-		WeatherCycle:    true,
-		TickRange:       6,
+		Name:              "World",
+		DefaultGameMode:   GameModeSurvival,
+		Difficulty:        DifficultyNormal,
+		TimeCycle:         true,
+		WeatherCycle:      true,
+		TickRange:         6,
+		MaxEntityCramming: 24,
+		GameRules:         make(map[string]any),
 	}
 }
@@ -179,6 +179,12 @@ type ComposterFillLayer struct{ sound }
 // ComposterReady is a sound played when a composter has produced bone meal and is ready to be collected.
 type ComposterReady struct{ sound }
 
+// EnderEyePlace is a sound played when an eye of ender is placed in an end portal frame.
+type EnderEyePlace struct{ sound }
+
+// EndPortalCreate is a sound played when an end portal is opened by filling all of its frames with eyes of ender.
+type EndPortalCreate struct{ sound }
+
 // LecternBookPlace is a sound played when a book is placed in a lectern.
 type LecternBookPlace struct{ sound }
 
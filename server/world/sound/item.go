@@ -32,6 +32,9 @@ type EquipItem struct {
 type BucketFill struct {
 	// Liquid is the liquid that the bucket is filled up with.
 	Liquid world.Liquid
+	// Block is the block that the bucket is filled up with if it is not filled with a liquid, such as powder
+	// snow.
+	Block world.Block
 
 	sound
 }
@@ -40,6 +43,9 @@ type BucketFill struct {
 type BucketEmpty struct {
 	// Liquid is the liquid that the bucket places into the world.
 	Liquid world.Liquid
+	// Block is the block that the bucket places into the world if it does not hold a liquid, such as powder
+	// snow.
+	Block world.Block
 
 	sound
 }
@@ -0,0 +1,158 @@
+package sqldb
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/logging"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// column reads the world.Column at a position and dimension from the database.
+func (db *DB) column(pos world.ChunkPos, dim world.Dimension) (*world.Column, error) {
+	id, _ := world.DimensionID(dim)
+	var subChunks, biomes, blockEntities, entities []byte
+	query := db.conf.Dialect.bind("SELECT sub_chunks, biomes, block_entities, entities FROM " + db.table("columns") +
+		" WHERE world = ? AND dim = ? AND x = ? AND z = ?")
+	err := db.db.QueryRow(query, db.conf.World, id, pos[0], pos[1]).Scan(&subChunks, &biomes, &blockEntities, &entities)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, leveldb.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	r := dim.Range()
+	cdata := chunk.SerialisedData{Biomes: biomes, SubChunks: make([][]byte, (r.Height()>>4)+1)}
+	for i := range cdata.SubChunks {
+		if len(subChunks) < 4 {
+			break
+		}
+		n := binary.LittleEndian.Uint32(subChunks)
+		if uint32(len(subChunks)-4) < n {
+			return nil, fmt.Errorf("sub chunk %v: expected %v bytes, got %v", i, n, len(subChunks)-4)
+		}
+		cdata.SubChunks[i], subChunks = subChunks[4:4+n], subChunks[4+n:]
+	}
+	col := new(world.Column)
+	if col.Chunk, err = chunk.DiskDecode(cdata, r); err != nil {
+		return nil, fmt.Errorf("decode chunk data: %w", err)
+	}
+	if col.BlockEntities, err = db.blockEntities(blockEntities, col.Chunk); err != nil {
+		return nil, fmt.Errorf("read block entities: %w", err)
+	}
+	if col.Entities, err = db.entities(entities); err != nil {
+		return nil, fmt.Errorf("read entities: %w", err)
+	}
+	return col, nil
+}
+
+// blockEntities decodes the block entities from the NBT data passed and returns them by their position.
+func (db *DB) blockEntities(data []byte, c *chunk.Chunk) (map[cube.Pos]world.Block, error) {
+	blockEntities := make(map[cube.Pos]world.Block)
+
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
+	for buf.Len() != 0 {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			return blockEntities, fmt.Errorf("decode nbt: %w", err)
+		}
+		x, _ := m["x"].(int32)
+		y, _ := m["y"].(int32)
+		z, _ := m["z"].(int32)
+		pos := cube.Pos{int(x), int(y), int(z)}
+
+		id := c.Block(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0)
+		b, ok := world.BlockByRuntimeID(id)
+		if !ok {
+			db.conf.Log.Error("No block registered with runtime ID.", "id", id)
+			continue
+		}
+		if _, ok := world.EncodeBlockNBT(b); !ok {
+			db.conf.Log.Error("Block has NBT but does not have a block entity.", "block", fmt.Sprintf("%#v", b))
+			continue
+		}
+		blockEntities[pos] = world.DecodeBlockNBT(b, m)
+	}
+	return blockEntities, nil
+}
+
+// entities decodes the entities from the NBT data passed. Entities of which the type is not registered in the
+// EntityRegistry of the Config are logged and skipped.
+func (db *DB) entities(data []byte) ([]world.Entity, error) {
+	var entities []world.Entity
+
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, nbt.LittleEndian)
+	for buf.Len() != 0 {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			return entities, fmt.Errorf("decode nbt: %w", err)
+		}
+		name, _ := m["identifier"].(string)
+		t, ok := db.conf.Entities.Lookup(name)
+		if !ok {
+			logging.With(db.conf.Log, logging.Entities).Error("Failed reading entity: entity was not registered.", "name", name)
+			continue
+		}
+		if s, ok := t.(world.SaveableEntityType); ok {
+			if e := s.DecodeNBT(m); e != nil {
+				entities = append(entities, e)
+			}
+		}
+	}
+	return entities, nil
+}
+
+// storeColumn writes the world.Column passed to the database at a position and dimension, replacing the
+// column previously stored there.
+func (db *DB) storeColumn(pos world.ChunkPos, dim world.Dimension, col *world.Column) error {
+	data := chunk.Encode(col.Chunk, chunk.DiskEncoding)
+	var subChunks []byte
+	for _, sub := range data.SubChunks {
+		subChunks = binary.LittleEndian.AppendUint32(subChunks, uint32(len(sub)))
+		subChunks = append(subChunks, sub...)
+	}
+
+	blockEntities := bytes.NewBuffer(nil)
+	enc := nbt.NewEncoderWithEncoding(blockEntities, nbt.LittleEndian)
+	for pos, b := range col.BlockEntities {
+		m, ok := world.EncodeBlockNBT(b)
+		if !ok {
+			continue
+		}
+		m["x"], m["y"], m["z"] = int32(pos[0]), int32(pos[1]), int32(pos[2])
+		if err := enc.Encode(m); err != nil {
+			db.conf.Log.Error("Failed encoding NBT of block entity.", "err", err)
+		}
+	}
+
+	entities := bytes.NewBuffer(nil)
+	enc = nbt.NewEncoderWithEncoding(entities, nbt.LittleEndian)
+	for _, e := range col.Entities {
+		t, ok := e.Type().(world.SaveableEntityType)
+		if !ok {
+			continue
+		}
+		m := t.EncodeNBT(e)
+		if m == nil {
+			continue
+		}
+		m["identifier"] = t.EncodeEntity()
+		if err := enc.Encode(m); err != nil {
+			logging.With(db.conf.Log, logging.Entities).Error("Failed encoding NBT of entity.", "err", err)
+		}
+	}
+
+	id, _ := world.DimensionID(dim)
+	query := db.conf.Dialect.insert(db.table("columns"), []string{"world", "dim", "x", "z"},
+		[]string{"sub_chunks", "biomes", "block_entities", "entities"})
+	_, err := db.db.Exec(query, db.conf.World, id, pos[0], pos[1], subChunks, data.Biomes, blockEntities.Bytes(), entities.Bytes())
+	return err
+}
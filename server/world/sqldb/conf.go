@@ -0,0 +1,67 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/mcdb/leveldat"
+	"log/slog"
+)
+
+// Config holds the optional parameters of a DB.
+type Config struct {
+	// Log is the Logger that will be used to log errors and debug messages to.
+	// If set to nil, slog.Default() is used.
+	Log *slog.Logger
+	// Dialect is the SQL dialect spoken by the database passed to Open. It
+	// must be set to either PostgreSQL or MySQL.
+	Dialect Dialect
+	// World is the name under which the data of the world is stored. Multiple
+	// worlds may be stored in the same database by opening a DB for each of
+	// them with a different World. If left empty, World defaults to "world".
+	World string
+	// TablePrefix is prepended to the names of all tables created and used by
+	// the DB. If left empty, TablePrefix defaults to "dragonfly_".
+	TablePrefix string
+
+	// Entities is an EntityRegistry with all entity types registered that may
+	// be read from the DB. Entities will default to entity.DefaultRegistry.
+	Entities world.EntityRegistry
+}
+
+// Open creates a new DB reading and writing world data from/to the SQL
+// database passed. The tables used by the DB are created if they do not yet
+// exist. If the world is already present in the database, Open will read its
+// settings and initialise the world with them.
+//
+// The sql.DB passed must have been opened with a driver for the Dialect set in
+// the Config. The DB does not take ownership of it: Closing the DB leaves the
+// sql.DB open, so that it may be shared by the providers of multiple worlds.
+func (conf Config) Open(db *sql.DB) (*DB, error) {
+	if conf.Dialect.blob == "" {
+		return nil, errors.New("open sql db: no dialect set")
+	}
+	if conf.Log == nil {
+		conf.Log = slog.Default()
+	}
+	if conf.World == "" {
+		conf.World = "world"
+	}
+	if conf.TablePrefix == "" {
+		conf.TablePrefix = "dragonfly_"
+	}
+	if len(conf.Entities.Types()) == 0 {
+		conf.Entities = entity.DefaultRegistry
+	}
+	d := &DB{conf: conf, db: db, ldat: &leveldat.Data{}}
+	if err := d.createTables(); err != nil {
+		return nil, fmt.Errorf("open sql db: %w", err)
+	}
+	if err := d.loadSettings(); err != nil {
+		return nil, fmt.Errorf("open sql db: %w", err)
+	}
+	d.set = d.ldat.Settings()
+	return d, nil
+}
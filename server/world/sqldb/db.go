@@ -0,0 +1,190 @@
+package sqldb
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/mcdb/leveldat"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"sync"
+	"time"
+)
+
+// DB implements a world provider that stores world data in an SQL database,
+// such as PostgreSQL or MySQL. Unlike mcdb.DB, which stores a world in files
+// on the local disk, a DB allows multiple servers to store their worlds in a
+// single, central database.
+type DB struct {
+	conf Config
+	db   *sql.DB
+
+	mu   sync.Mutex
+	ldat *leveldat.Data
+	set  *world.Settings
+}
+
+// Open creates a new DB reading and writing world data from/to the SQL
+// database passed using default options and the Dialect passed. If the world
+// is already present in the database, Open will read its settings and
+// initialise the world with them.
+func Open(db *sql.DB, dialect Dialect) (*DB, error) {
+	conf := Config{Dialect: dialect}
+	return conf.Open(db)
+}
+
+// table returns the name of the table passed prefixed with the TablePrefix of the Config.
+func (db *DB) table(name string) string {
+	return db.conf.TablePrefix + name
+}
+
+// createTables creates the tables used by the DB if they do not yet exist.
+func (db *DB) createTables() error {
+	blob := db.conf.Dialect.blob
+	tables := []string{
+		"CREATE TABLE IF NOT EXISTS " + db.table("worlds") + " (world VARCHAR(255) NOT NULL, settings " + blob + " NOT NULL, PRIMARY KEY (world))",
+		"CREATE TABLE IF NOT EXISTS " + db.table("columns") + " (world VARCHAR(255) NOT NULL, dim INT NOT NULL, x INT NOT NULL, z INT NOT NULL, sub_chunks " + blob + ", biomes " + blob + ", block_entities " + blob + ", entities " + blob + ", PRIMARY KEY (world, dim, x, z))",
+		"CREATE TABLE IF NOT EXISTS " + db.table("players") + " (world VARCHAR(255) NOT NULL, uuid CHAR(36) NOT NULL, spawn_x INT NOT NULL, spawn_y INT NOT NULL, spawn_z INT NOT NULL, PRIMARY KEY (world, uuid))",
+		"CREATE TABLE IF NOT EXISTS " + db.table("structures") + " (world VARCHAR(255) NOT NULL, name VARCHAR(255) NOT NULL, data " + blob + " NOT NULL, PRIMARY KEY (world, name))",
+	}
+	for _, query := range tables {
+		if _, err := db.db.Exec(query); err != nil {
+			return fmt.Errorf("create tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadSettings reads the settings of the world from the database. If the world has no settings stored yet,
+// the default settings are used.
+func (db *DB) loadSettings() error {
+	var data []byte
+	err := db.db.QueryRow(db.conf.Dialect.bind("SELECT settings FROM "+db.table("worlds")+" WHERE world = ?"), db.conf.World).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		db.ldat.FillDefault()
+		db.ldat.LevelName = db.conf.World
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("read settings: %w", err)
+	}
+	if err := nbt.UnmarshalEncoding(data, db.ldat, nbt.LittleEndian); err != nil {
+		return fmt.Errorf("decode settings: %w", err)
+	}
+	return nil
+}
+
+// storeSettings writes the settings of the world to the database.
+func (db *DB) storeSettings() error {
+	db.mu.Lock()
+	data, err := nbt.MarshalEncoding(db.ldat, nbt.LittleEndian)
+	db.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	query := db.conf.Dialect.insert(db.table("worlds"), []string{"world"}, []string{"settings"})
+	if _, err := db.db.Exec(query, db.conf.World, data); err != nil {
+		return fmt.Errorf("write settings: %w", err)
+	}
+	return nil
+}
+
+// Settings returns the world.Settings of the world loaded by the DB.
+func (db *DB) Settings() *world.Settings {
+	return db.set
+}
+
+// SaveSettings saves the world.Settings passed to the database.
+func (db *DB) SaveSettings(s *world.Settings) {
+	db.mu.Lock()
+	db.ldat.PutSettings(s)
+	db.mu.Unlock()
+	if err := db.storeSettings(); err != nil {
+		db.conf.Log.Error("Failed saving settings.", "err", err)
+	}
+}
+
+// LoadPlayerSpawnPosition loads the spawn position of the player with the UUID passed from the database.
+func (db *DB) LoadPlayerSpawnPosition(id uuid.UUID) (pos cube.Pos, exists bool, err error) {
+	query := db.conf.Dialect.bind("SELECT spawn_x, spawn_y, spawn_z FROM " + db.table("players") + " WHERE world = ? AND uuid = ?")
+	err = db.db.QueryRow(query, db.conf.World, id.String()).Scan(&pos[0], &pos[1], &pos[2])
+	if errors.Is(err, sql.ErrNoRows) {
+		return cube.Pos{}, false, nil
+	} else if err != nil {
+		return cube.Pos{}, true, fmt.Errorf("error reading spawn position for player %v: %w", id, err)
+	}
+	return pos, true, nil
+}
+
+// SavePlayerSpawnPosition saves the spawn position of the player with the UUID passed to the database.
+func (db *DB) SavePlayerSpawnPosition(id uuid.UUID, pos cube.Pos) error {
+	query := db.conf.Dialect.insert(db.table("players"), []string{"world", "uuid"}, []string{"spawn_x", "spawn_y", "spawn_z"})
+	if _, err := db.db.Exec(query, db.conf.World, id.String(), pos[0], pos[1], pos[2]); err != nil {
+		return fmt.Errorf("error writing spawn position for player %v: %w", id, err)
+	}
+	return nil
+}
+
+// LoadStructure loads the world.StructureTemplate stored under the name passed.
+// If no structure template with the name exists, errors.Is(err,
+// leveldb.ErrNotFound) equals true.
+func (db *DB) LoadStructure(name string) (*world.StructureTemplate, error) {
+	var data []byte
+	query := db.conf.Dialect.bind("SELECT data FROM " + db.table("structures") + " WHERE world = ? AND name = ?")
+	err := db.db.QueryRow(query, db.conf.World, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, leveldb.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading structure template %v: %w", name, err)
+	}
+	return world.DecodeStructureTemplate(bytes.NewReader(data))
+}
+
+// StoreStructure stores the world.StructureTemplate passed under the name
+// passed.
+func (db *DB) StoreStructure(name string, s *world.StructureTemplate) error {
+	buf := bytes.NewBuffer(nil)
+	if err := s.Encode(buf); err != nil {
+		return err
+	}
+	query := db.conf.Dialect.insert(db.table("structures"), []string{"world", "name"}, []string{"data"})
+	if _, err := db.db.Exec(query, db.conf.World, name, buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing structure template %v: %w", name, err)
+	}
+	return nil
+}
+
+// LoadColumn reads a world.Column from the DB at a position and dimension in
+// the DB. If no column at that position exists, errors.Is(err,
+// leveldb.ErrNotFound) equals true.
+func (db *DB) LoadColumn(pos world.ChunkPos, dim world.Dimension) (*world.Column, error) {
+	col, err := db.column(pos, dim)
+	if err != nil {
+		return nil, fmt.Errorf("load column %v (%v): %w", pos, dim, err)
+	}
+	return col, nil
+}
+
+// StoreColumn stores a world.Column at a position and dimension in the DB. An
+// error is returned if storing was unsuccessful.
+func (db *DB) StoreColumn(pos world.ChunkPos, dim world.Dimension, col *world.Column) error {
+	if err := db.storeColumn(pos, dim, col); err != nil {
+		return fmt.Errorf("store column %v (%v): %w", pos, dim, err)
+	}
+	return nil
+}
+
+// Close closes the provider, saving the settings of the world. Close does not
+// close the sql.DB passed to Open.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	db.ldat.LastPlayed = time.Now().Unix()
+	db.mu.Unlock()
+	if err := db.storeSettings(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqldb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect is an SQL dialect spoken by a database. The dialects supported are PostgreSQL and MySQL, which
+// differ in the syntax of placeholders, binary column types and upserts.
+type Dialect struct {
+	blob     string
+	numbered bool
+	upsert   func(keys, values []string) string
+}
+
+var (
+	// PostgreSQL is the Dialect of PostgreSQL databases.
+	PostgreSQL = Dialect{blob: "BYTEA", numbered: true, upsert: func(keys, values []string) string {
+		set := make([]string, len(values))
+		for i, v := range values {
+			set[i] = v + " = EXCLUDED." + v
+		}
+		return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO UPDATE SET " + strings.Join(set, ", ")
+	}}
+	// MySQL is the Dialect of MySQL and MariaDB databases.
+	MySQL = Dialect{blob: "LONGBLOB", upsert: func(_, values []string) string {
+		set := make([]string, len(values))
+		for i, v := range values {
+			set[i] = v + " = VALUES(" + v + ")"
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+	}}
+)
+
+// bind replaces the ? placeholders in the query passed with the placeholders of the Dialect.
+func (d Dialect) bind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// insert returns a query that inserts a row with the keys and values passed into a table, or updates the
+// values of the row if a row with the same keys already exists.
+func (d Dialect) insert(table string, keys, values []string) string {
+	cols := append(append([]string{}, keys...), values...)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	return d.bind("INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + placeholders + ")" +
+		d.upsert(keys, values))
+}
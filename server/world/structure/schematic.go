@@ -0,0 +1,342 @@
+package structure
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+const (
+	// schematicVersion is the version of the Sponge schematic format written by WriteSchematic.
+	schematicVersion = 3
+	// schematicDataVersion is the data version written to schematics. Schematics hold Bedrock block states, so
+	// the data version has no meaning other than being required by the format.
+	schematicDataVersion = 3700
+)
+
+// ReadSchematic reads a world.StructureTemplate from a gzip compressed Sponge schematic (.schem) in the
+// io.Reader passed. Both version 2 and 3 of the format are supported.
+//
+// Block states in the palette of the schematic are read as Bedrock block states, like those written by
+// WriteSchematic. States with properties that are not known are replaced with the default state of the block,
+// and blocks that do not exist in Bedrock Edition are read as structure voids.
+func ReadSchematic(r io.Reader) (*world.StructureTemplate, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read schematic: %w", err)
+	}
+	var root map[string]any
+	if err := nbt.NewDecoderWithEncoding(gr, nbt.BigEndian).Decode(&root); err != nil {
+		return nil, fmt.Errorf("read schematic: decode nbt: %w", err)
+	}
+	m := root
+	if schematic, ok := root["Schematic"].(map[string]any); ok {
+		// Version 3 schematics hold all data in a 'Schematic' compound.
+		m = schematic
+	}
+	version, _ := m["Version"].(int32)
+	blocks := m
+	if version >= 3 {
+		if blocks, _ = m["Blocks"].(map[string]any); blocks == nil {
+			blocks = map[string]any{}
+		}
+	}
+	width, _ := m["Width"].(int16)
+	height, _ := m["Height"].(int16)
+	length, _ := m["Length"].(int16)
+	s := world.NewStructureTemplate([3]int{int(uint16(width)), int(uint16(height)), int(uint16(length))})
+	size := s.Dimensions()
+
+	paletteNBT, _ := blocks["Palette"].(map[string]any)
+	palette := make(map[int32]world.Block, len(paletteNBT))
+	for state, index := range paletteNBT {
+		i, ok := index.(int32)
+		if !ok {
+			return nil, fmt.Errorf("read schematic: invalid palette index %v for %v", index, state)
+		}
+		palette[i] = parseBlockState(state)
+	}
+	dataKey := "BlockData"
+	if version >= 3 {
+		dataKey = "Data"
+	}
+	data := byteArray(blocks[dataKey])
+	for i := 0; i < size[0]*size[1]*size[2]; i++ {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("read schematic: block data too short")
+		}
+		data = data[n:]
+		s.Set(i%size[0], i/(size[0]*size[2]), (i/size[0])%size[2], palette[int32(v)], nil)
+	}
+
+	blockEntities, _ := blocks["BlockEntities"].([]any)
+	for _, v := range blockEntities {
+		entry, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		pos := intArray(entry["Pos"])
+		if len(pos) != 3 || !within(size, int(pos[0]), int(pos[1]), int(pos[2])) {
+			continue
+		}
+		b, liq := s.At(int(pos[0]), int(pos[1]), int(pos[2]), nil)
+		if _, ok := world.EncodeBlockNBT(b); !ok {
+			continue
+		}
+		d := entryData(entry, version)
+		d["id"] = entry["Id"]
+		s.Set(int(pos[0]), int(pos[1]), int(pos[2]), world.DecodeBlockNBT(b, d), liq)
+	}
+
+	entities, _ := m["Entities"].([]any)
+	for _, v := range entities {
+		entry, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		pos, _ := entry["Pos"].([]any)
+		if len(pos) != 3 {
+			continue
+		}
+		d := entryData(entry, version)
+		d["identifier"] = entry["Id"]
+		d["Pos"] = make([]float32, 3)
+		for i, f := range pos {
+			f64, _ := f.(float64)
+			d["Pos"].([]float32)[i] = float32(f64)
+		}
+		s.AddEntity(d)
+	}
+	return s, nil
+}
+
+// entryData returns the NBT data of a block entity or entity entry in a schematic of the version passed.
+// Version 3 schematics hold this data in a 'Data' compound, whereas older versions hold it in the entry itself.
+func entryData(entry map[string]any, version int32) map[string]any {
+	if version >= 3 {
+		if d, ok := entry["Data"].(map[string]any); ok {
+			return maps.Clone(d)
+		}
+		return map[string]any{}
+	}
+	d := maps.Clone(entry)
+	delete(d, "Pos")
+	delete(d, "Id")
+	return d
+}
+
+// WriteSchematic writes the world.StructureTemplate passed as a gzip compressed version 3 Sponge schematic
+// (.schem) to the io.Writer passed. The palette of the schematic holds Bedrock block states, formatted like
+// 'minecraft:oak_log[pillar_axis=y]'. Structure voids are written as air, and liquids in the same place as
+// other blocks are left out.
+func WriteSchematic(w io.Writer, s *world.StructureTemplate) error {
+	size := s.Dimensions()
+	if size[0] > 0xffff || size[1] > 0xffff || size[2] > 0xffff {
+		return fmt.Errorf("write schematic: size %v too large", size)
+	}
+	palette, indices := map[string]any{}, map[string]int32{}
+	index := func(b world.Block) int32 {
+		state := formatBlockState(b)
+		if i, ok := indices[state]; ok {
+			return i
+		}
+		i := int32(len(indices))
+		indices[state], palette[state] = i, i
+		return i
+	}
+
+	var data []byte
+	var blockEntities []any
+	for y := 0; y < size[1]; y++ {
+		for z := 0; z < size[2]; z++ {
+			for x := 0; x < size[0]; x++ {
+				b, _ := s.At(x, y, z, nil)
+				if b == nil {
+					b = block.Air{}
+				}
+				data = binary.AppendUvarint(data, uint64(index(b)))
+
+				d, ok := world.EncodeBlockNBT(b)
+				if !ok {
+					continue
+				}
+				id, _ := d["id"].(string)
+				delete(d, "id")
+				delete(d, "x")
+				delete(d, "y")
+				delete(d, "z")
+				blockEntities = append(blockEntities, map[string]any{
+					"Pos":  [3]int32{int32(x), int32(y), int32(z)},
+					"Id":   id,
+					"Data": d,
+				})
+			}
+		}
+	}
+
+	entities := make([]any, 0, len(s.Entities()))
+	for _, e := range s.Entities() {
+		d := maps.Clone(e)
+		id, _ := d["identifier"].(string)
+		pos := nbtFloat32s(d["Pos"])
+		delete(d, "identifier")
+		delete(d, "Pos")
+		if len(pos) != 3 {
+			continue
+		}
+		entities = append(entities, map[string]any{
+			"Pos":  []float64{float64(pos[0]), float64(pos[1]), float64(pos[2])},
+			"Id":   id,
+			"Data": d,
+		})
+	}
+	if blockEntities == nil {
+		blockEntities = []any{}
+	}
+
+	gw := gzip.NewWriter(w)
+	err := nbt.NewEncoderWithEncoding(gw, nbt.BigEndian).Encode(map[string]any{
+		"Schematic": map[string]any{
+			"Version":     int32(schematicVersion),
+			"DataVersion": int32(schematicDataVersion),
+			"Width":       int16(uint16(size[0])),
+			"Height":      int16(uint16(size[1])),
+			"Length":      int16(uint16(size[2])),
+			"Offset":      [3]int32{},
+			"Blocks": map[string]any{
+				"Palette":       palette,
+				"Data":          toByteArray(data),
+				"BlockEntities": blockEntities,
+			},
+			"Entities": entities,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("write schematic: encode nbt: %w", err)
+	}
+	return gw.Close()
+}
+
+// formatBlockState formats the block passed as a block state string, such as 'minecraft:oak_log[pillar_axis=y]'.
+// The properties of the block are sorted by their name.
+func formatBlockState(b world.Block) string {
+	name, properties := b.EncodeBlock()
+	if len(properties) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		var v string
+		switch p := properties[k].(type) {
+		case bool:
+			v = strconv.FormatBool(p)
+		case uint8:
+			v = strconv.FormatBool(p != 0)
+		case int32:
+			v = strconv.Itoa(int(p))
+		default:
+			v = fmt.Sprint(p)
+		}
+		values = append(values, k+"="+v)
+	}
+	return name + "[" + strings.Join(values, ",") + "]"
+}
+
+// parseBlockState parses a block state string formatted by formatBlockState into a block. If no block with the
+// exact state exists, the default state of the block is returned. If the block does not exist at all, nil is
+// returned.
+func parseBlockState(state string) world.Block {
+	name, props, _ := strings.Cut(strings.TrimSuffix(state, "]"), "[")
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	properties := make(map[string]any)
+	for _, prop := range strings.Split(props, ",") {
+		k, v, ok := strings.Cut(prop, "=")
+		if !ok {
+			continue
+		}
+		if b, err := strconv.ParseBool(v); err == nil && (v == "true" || v == "false") {
+			properties[k] = b
+		} else if i, err := strconv.ParseInt(v, 10, 32); err == nil {
+			properties[k] = int32(i)
+		} else {
+			properties[k] = v
+		}
+	}
+	if b, ok := world.BlockByName(name, properties); ok {
+		return b
+	}
+	if rid, ok := chunk.StateToRuntimeID(name, properties); ok {
+		b, _ := world.BlockByRuntimeID(rid)
+		return b
+	}
+	return nil
+}
+
+// within checks if the position passed is within a template of the size passed.
+func within(size [3]int, x, y, z int) bool {
+	return x >= 0 && y >= 0 && z >= 0 && x < size[0] && y < size[1] && z < size[2]
+}
+
+// byteArray converts an NBT byte array, which is decoded as a Go array, to a byte slice.
+func byteArray(v any) []byte {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	b := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(b), val)
+	return b
+}
+
+// intArray converts an NBT int array, which is decoded as a Go array, to an int32 slice.
+func intArray(v any) []int32 {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Int32 {
+		return nil
+	}
+	s := make([]int32, val.Len())
+	reflect.Copy(reflect.ValueOf(s), val)
+	return s
+}
+
+// toByteArray converts a byte slice to a Go array, so that it is encoded as an NBT byte array rather than a
+// list of bytes.
+func toByteArray(b []byte) any {
+	arr := reflect.New(reflect.ArrayOf(len(b), reflect.TypeOf(byte(0)))).Elem()
+	reflect.Copy(arr, reflect.ValueOf(b))
+	return arr.Interface()
+}
+
+// nbtFloat32s converts an NBT list of floats to a float32 slice.
+func nbtFloat32s(v any) []float32 {
+	switch l := v.(type) {
+	case []float32:
+		return l
+	case []any:
+		s := make([]float32, len(l))
+		for i, f := range l {
+			s[i], _ = f.(float32)
+		}
+		return s
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package structure implements reading and writing of structure files, such as Sponge schematics (.schem) and
+// Bedrock structure files (.mcstructure), into world.StructureTemplate values. Templates read may be rotated and
+// mirrored using Rotate and Mirror and pasted into a world using world.World.PlaceStructure.
+package structure
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// ReadFile reads a world.StructureTemplate from the file at the path passed. The format of the file is selected
+// by its extension, which must be either '.schem' or '.mcstructure'.
+func ReadFile(path string) (*world.StructureTemplate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read structure: %w", err)
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".schem":
+		return ReadSchematic(f)
+	case ".mcstructure":
+		return ReadMCStructure(f)
+	default:
+		return nil, fmt.Errorf("read structure: unknown file extension %v", ext)
+	}
+}
+
+// WriteFile writes the world.StructureTemplate passed to a file at the path passed, creating it if it does not
+// yet exist. The format of the file is selected by its extension, which must be either '.schem' or
+// '.mcstructure'.
+func WriteFile(path string, s *world.StructureTemplate) error {
+	var write func(w io.Writer, s *world.StructureTemplate) error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".schem":
+		write = WriteSchematic
+	case ".mcstructure":
+		write = WriteMCStructure
+	default:
+		return fmt.Errorf("write structure: unknown file extension %v", ext)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("write structure: %w", err)
+	}
+	if err := write(f, s); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadMCStructure reads a world.StructureTemplate in the Bedrock .mcstructure format from the io.Reader passed.
+func ReadMCStructure(r io.Reader) (*world.StructureTemplate, error) {
+	return world.DecodeStructureTemplate(r)
+}
+
+// WriteMCStructure writes the world.StructureTemplate passed in the Bedrock .mcstructure format to the
+// io.Writer passed.
+func WriteMCStructure(w io.Writer, s *world.StructureTemplate) error {
+	return s.Encode(w)
+}
+
+// Rotate returns a copy of the world.StructureTemplate passed rotated clockwise by the number of quarter turns
+// passed, seen from above. The blocks in the template are rotated along with it.
+func Rotate(s *world.StructureTemplate, turns int) *world.StructureTemplate {
+	return s.Transform(world.StructurePlacement{Rotation: turns})
+}
+
+// Mirror returns a copy of the world.StructureTemplate passed mirrored along the X axis if x is true and along
+// the Z axis if z is true. The blocks in the template are mirrored along with it.
+func Mirror(s *world.StructureTemplate, x, z bool) *world.StructureTemplate {
+	return s.Transform(world.StructurePlacement{MirrorX: x, MirrorZ: z})
+}
@@ -0,0 +1,597 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// StructureTemplate is a Structure that holds a copy of a cuboid region of blocks, liquids and entities.
+// Structure templates are saved and loaded by structure blocks. They may be captured from a World using
+// World.CaptureStructure, encoded to and decoded from the .mcstructure format using StructureTemplate.Encode
+// and DecodeStructureTemplate and placed in a World using World.PlaceStructure.
+type StructureTemplate struct {
+	size [3]int
+	// blocks and liquids hold the blocks and liquids of the template, indexed first by x, then by y and then by
+	// z. A nil block is a structure void, which leaves the block in the world unchanged when the template is
+	// placed.
+	blocks  []Block
+	liquids []Liquid
+	// entities holds the NBT of the entities in the template. The positions in the NBT are relative to the
+	// template.
+	entities []map[string]any
+}
+
+// NewStructureTemplate returns a new StructureTemplate with the dimensions passed, filled with structure voids.
+// Negative dimensions are treated as 0.
+func NewStructureTemplate(size [3]int) *StructureTemplate {
+	size = [3]int{max(size[0], 0), max(size[1], 0), max(size[2], 0)}
+	n := size[0] * size[1] * size[2]
+	return &StructureTemplate{size: size, blocks: make([]Block, n), liquids: make([]Liquid, n)}
+}
+
+// Dimensions returns the dimensions of the StructureTemplate.
+func (s *StructureTemplate) Dimensions() [3]int {
+	return s.size
+}
+
+// At returns the block and liquid at a specific position in the StructureTemplate. A nil block is returned for
+// structure voids.
+func (s *StructureTemplate) At(x, y, z int, _ func(x, y, z int) Block) (Block, Liquid) {
+	i := s.index(x, y, z)
+	return s.blocks[i], s.liquids[i]
+}
+
+// Set sets the block and liquid at a specific position in the StructureTemplate. A nil block may be passed to
+// set a structure void.
+func (s *StructureTemplate) Set(x, y, z int, b Block, liq Liquid) {
+	i := s.index(x, y, z)
+	s.blocks[i], s.liquids[i] = b, liq
+}
+
+// Entities returns the NBT of the entities held by the StructureTemplate. The positions in the NBT, stored under
+// the 'Pos' key, are relative to the template.
+func (s *StructureTemplate) Entities() []map[string]any {
+	return s.entities
+}
+
+// AddEntity adds an entity with the NBT passed to the StructureTemplate. The NBT must hold the identifier of the
+// entity under the 'identifier' key and its position relative to the template under the 'Pos' key.
+func (s *StructureTemplate) AddEntity(m map[string]any) {
+	s.entities = append(s.entities, m)
+}
+
+// index returns the index of a position in the StructureTemplate. This is the same order that is used by the
+// .mcstructure format.
+func (s *StructureTemplate) index(x, y, z int) int {
+	return (x*s.size[1]+y)*s.size[2] + z
+}
+
+// CaptureStructure captures the blocks and liquids in the cuboid region of the size passed starting at pos
+// into a StructureTemplate. If entities is true, the entities within the region are captured too. Entities
+// that cannot be saved, such as players, are never captured.
+func (w *World) CaptureStructure(pos cube.Pos, size [3]int, entities bool) *StructureTemplate {
+	s := NewStructureTemplate(size)
+	if w == nil || len(s.blocks) == 0 {
+		return s
+	}
+	for x := 0; x < size[0]; x++ {
+		for y := 0; y < size[1]; y++ {
+			for z := 0; z < size[2]; z++ {
+				p := pos.Add(cube.Pos{x, y, z})
+				liq, _ := w.additionalLiquid(p)
+				s.Set(x, y, z, w.Block(p), liq)
+			}
+		}
+	}
+	if !entities {
+		return s
+	}
+	origin := pos.Vec3()
+	box := cube.Box(0, 0, 0, float64(size[0]), float64(size[1]), float64(size[2])).Translate(origin)
+	for _, e := range w.EntitiesWithin(box.Grow(1), nil) {
+		rel := e.Position().Sub(origin)
+		if rel[0] < 0 || rel[1] < 0 || rel[2] < 0 || rel[0] >= float64(size[0]) || rel[1] >= float64(size[1]) || rel[2] >= float64(size[2]) {
+			continue
+		}
+		t, ok := e.Type().(SaveableEntityType)
+		if !ok {
+			continue
+		}
+		m := t.EncodeNBT(e)
+		if m == nil {
+			continue
+		}
+		m["identifier"] = t.EncodeEntity()
+		m["Pos"] = vec3ToFloat32Slice(rel)
+		delete(m, "UniqueID")
+		s.entities = append(s.entities, m)
+	}
+	return s
+}
+
+// SaveStructure saves the StructureTemplate passed under the name passed, so that it may later be loaded using
+// LoadStructure. Names without a namespace are saved in the 'mystructure' namespace, like 'mystructure:house'.
+// If the Provider of the World implements StructureProvider, the template is also stored by the Provider.
+func (w *World) SaveStructure(name string, s *StructureTemplate) error {
+	if w == nil {
+		return nil
+	}
+	name = structureName(name)
+	w.structureMu.Lock()
+	w.structures[name] = s
+	w.structureMu.Unlock()
+
+	if p, ok := w.provider().(StructureProvider); ok {
+		if err := p.StoreStructure(name, s); err != nil {
+			return fmt.Errorf("save structure %v: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// LoadStructure loads the StructureTemplate saved under the name passed. If no structure template with the name
+// exists, false is returned.
+func (w *World) LoadStructure(name string) (*StructureTemplate, bool, error) {
+	if w == nil {
+		return nil, false, nil
+	}
+	name = structureName(name)
+	w.structureMu.Lock()
+	defer w.structureMu.Unlock()
+	if s, ok := w.structures[name]; ok {
+		return s, true, nil
+	}
+	p, ok := w.provider().(StructureProvider)
+	if !ok {
+		return nil, false, nil
+	}
+	s, err := p.LoadStructure(name)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("load structure %v: %w", name, err)
+	}
+	w.structures[name] = s
+	return s, true, nil
+}
+
+// structureName returns the name passed with the 'mystructure' namespace if it does not have a namespace.
+func structureName(name string) string {
+	if !strings.Contains(name, ":") {
+		return "mystructure:" + name
+	}
+	return name
+}
+
+// StructurePlacement holds options that change the way a StructureTemplate is placed using
+// World.PlaceStructure. The zero value places the template as it was captured.
+type StructurePlacement struct {
+	// Rotation is the number of quarter turns that the template is rotated clockwise, seen from above.
+	Rotation int
+	// MirrorX and MirrorZ specify if the template is mirrored along the X and Z axis respectively. Mirroring
+	// is applied before rotation.
+	MirrorX, MirrorZ bool
+	// Integrity is the chance between 0 and 1 that each block in the template is placed. If Integrity is 0 or
+	// 1, all blocks are placed.
+	Integrity float64
+	// Seed is the seed used to select the blocks omitted if Integrity is not 1.
+	Seed int64
+	// IgnoreBlocks and IgnoreEntities specify if the blocks and entities of the template respectively should
+	// not be placed.
+	IgnoreBlocks, IgnoreEntities bool
+}
+
+// PlaceStructure places the StructureTemplate passed in the world with its lowest corner at the position
+// passed, transformed according to the StructurePlacement passed. Blocks in the world at structure voids are
+// left unchanged. Unlike BuildStructure, PlaceStructure rotates and mirrors the blocks of the template along
+// with it and spawns the entities held by the template.
+func (w *World) PlaceStructure(pos cube.Pos, s *StructureTemplate, opts StructurePlacement) {
+	if w == nil {
+		return
+	}
+	t := s.Transform(opts)
+	if !opts.IgnoreBlocks {
+		w.BuildStructure(pos, t)
+	}
+	if opts.IgnoreEntities {
+		return
+	}
+	reg := w.EntityRegistry()
+	for _, m := range t.entities {
+		name, _ := m["identifier"].(string)
+		et, ok := reg.Lookup(name)
+		if !ok {
+			continue
+		}
+		st, ok := et.(SaveableEntityType)
+		if !ok {
+			continue
+		}
+		m = maps.Clone(m)
+		m["Pos"] = vec3ToFloat32Slice(nbtVec3(m, "Pos").Add(pos.Vec3()))
+		if e := st.DecodeNBT(m); e != nil {
+			w.AddEntity(e)
+		}
+	}
+}
+
+// Transform returns a copy of the StructureTemplate with the rotation, mirroring and integrity of the
+// StructurePlacement passed applied to it.
+func (s *StructureTemplate) Transform(opts StructurePlacement) *StructureTemplate {
+	turns := (opts.Rotation%4 + 4) % 4
+	size := s.size
+	if turns%2 == 1 {
+		size[0], size[2] = size[2], size[0]
+	}
+	t := NewStructureTemplate(size)
+	r := rand.New(rand.NewSource(opts.Seed))
+	for x := 0; x < s.size[0]; x++ {
+		for y := 0; y < s.size[1]; y++ {
+			for z := 0; z < s.size[2]; z++ {
+				b, liq := s.At(x, y, z, nil)
+				if opts.Integrity > 0 && opts.Integrity < 1 && r.Float64() >= opts.Integrity {
+					continue
+				}
+				if b != nil {
+					b = opts.transformBlock(b)
+				}
+				tx, tz := opts.transformXZ(x, z, s.size)
+				t.Set(tx, y, tz, b, liq)
+			}
+		}
+	}
+	for _, m := range s.entities {
+		m = maps.Clone(m)
+		pos := nbtVec3(m, "Pos")
+		if opts.MirrorX {
+			pos[0] = float64(s.size[0]) - pos[0]
+		}
+		if opts.MirrorZ {
+			pos[2] = float64(s.size[2]) - pos[2]
+		}
+		for i, sizeX, sizeZ := 0, float64(s.size[0]), float64(s.size[2]); i < turns; i++ {
+			pos[0], pos[2] = sizeZ-pos[2], pos[0]
+			sizeX, sizeZ = sizeZ, sizeX
+		}
+		m["Pos"] = vec3ToFloat32Slice(pos)
+		// Entities written by vanilla store their yaw in the Rotation field, whereas Dragonfly uses the Yaw field.
+		if rot := nbtFloat32s(m, "Rotation"); len(rot) == 2 {
+			m["Rotation"] = []float32{opts.transformYaw(rot[0]), rot[1]}
+		}
+		if yaw, ok := m["Yaw"].(float32); ok {
+			m["Yaw"] = opts.transformYaw(yaw)
+		}
+		t.entities = append(t.entities, m)
+	}
+	return t
+}
+
+// transformYaw returns the yaw that an entity with the yaw passed has after applying the StructurePlacement.
+func (opts StructurePlacement) transformYaw(yaw float32) float32 {
+	if opts.MirrorX {
+		yaw = -yaw
+	}
+	if opts.MirrorZ {
+		yaw = 180 - yaw
+	}
+	return yaw + float32((opts.Rotation%4+4)%4*90)
+}
+
+// transformXZ returns the X and Z coordinates that the x and z coordinates passed, in a StructureTemplate of
+// the size passed, have after applying the StructurePlacement.
+func (opts StructurePlacement) transformXZ(x, z int, size [3]int) (int, int) {
+	if opts.MirrorX {
+		x = size[0] - 1 - x
+	}
+	if opts.MirrorZ {
+		z = size[2] - 1 - z
+	}
+	sizeX, sizeZ := size[0], size[2]
+	for i := 0; i < (opts.Rotation%4+4)%4; i++ {
+		x, z = sizeZ-1-z, x
+		sizeX, sizeZ = sizeZ, sizeX
+	}
+	return x, z
+}
+
+// transformDirection returns the direction that the direction passed points in after applying the
+// StructurePlacement.
+func (opts StructurePlacement) transformDirection(d cube.Direction) cube.Direction {
+	if opts.MirrorX && (d == cube.East || d == cube.West) {
+		d = d.Opposite()
+	}
+	if opts.MirrorZ && (d == cube.North || d == cube.South) {
+		d = d.Opposite()
+	}
+	for i := 0; i < (opts.Rotation%4+4)%4; i++ {
+		d = d.RotateRight()
+	}
+	return d
+}
+
+var (
+	// directionStates holds the horizontal directions in the order of the values of the 'direction' block
+	// property used by most blocks.
+	directionStates = []cube.Direction{cube.South, cube.West, cube.North, cube.East}
+	// doorDirectionStates holds the horizontal directions in the order of the values of the 'direction' block
+	// property of doors.
+	doorDirectionStates = []cube.Direction{cube.East, cube.South, cube.West, cube.North}
+	// weirdoDirectionStates holds the horizontal directions in the order of the values of the
+	// 'weirdo_direction' block property of stairs and the 'direction' block property of trapdoors.
+	weirdoDirectionStates = []cube.Direction{cube.East, cube.West, cube.South, cube.North}
+)
+
+// transformBlock returns the block passed rotated and mirrored according to the StructurePlacement. Blocks are
+// transformed by changing the block properties that hold their direction or axis. If the resulting block is
+// not registered, the block is returned unchanged.
+func (opts StructurePlacement) transformBlock(b Block) Block {
+	turns := (opts.Rotation%4 + 4) % 4
+	if turns == 0 && !opts.MirrorX && !opts.MirrorZ {
+		return b
+	}
+	name, properties := b.EncodeBlock()
+	if len(properties) == 0 {
+		return b
+	}
+	states := func(s []cube.Direction, v any) any {
+		i, ok := v.(int32)
+		if !ok || i < 0 || int(i) >= len(s) {
+			return v
+		}
+		d := opts.transformDirection(s[i])
+		for j, other := range s {
+			if other == d {
+				return int32(j)
+			}
+		}
+		return v
+	}
+	transformed := maps.Clone(properties)
+	for k, v := range properties {
+		switch k {
+		case "minecraft:cardinal_direction", "minecraft:facing_direction", "minecraft:block_face", "torch_facing_direction":
+			if s, ok := v.(string); ok {
+				if d, ok := directionByName(s); ok {
+					transformed[k] = opts.transformDirection(d).String()
+				}
+			}
+		case "facing_direction":
+			if i, ok := v.(int32); ok && i >= 2 && i <= 5 {
+				transformed[k] = int32(opts.transformDirection(cube.Face(i).Direction()).Face())
+			}
+		case "weirdo_direction":
+			transformed[k] = states(weirdoDirectionStates, v)
+		case "direction":
+			switch {
+			case strings.HasSuffix(name, "trapdoor"):
+				transformed[k] = states(weirdoDirectionStates, v)
+			case strings.HasSuffix(name, "_door"):
+				transformed[k] = states(doorDirectionStates, v)
+			default:
+				transformed[k] = states(directionStates, v)
+			}
+		case "door_hinge_bit":
+			if hinge, ok := v.(bool); ok && opts.MirrorX != opts.MirrorZ {
+				transformed[k] = !hinge
+			}
+		case "pillar_axis":
+			if turns%2 == 1 && (v == "x" || v == "z") {
+				transformed[k] = map[any]string{"x": "z", "z": "x"}[v]
+			}
+		case "ground_sign_direction":
+			if i, ok := v.(int32); ok {
+				if opts.MirrorX {
+					i = (16 - i) % 16
+				}
+				if opts.MirrorZ {
+					i = (24 - i) % 16
+				}
+				transformed[k] = (i + int32(turns*4)) % 16
+			}
+		}
+	}
+	nb, ok := BlockByName(name, transformed)
+	if !ok {
+		return b
+	}
+	if data, ok := EncodeBlockNBT(b); ok && hasBlockEntity(nb) {
+		nb = DecodeBlockNBT(nb, data)
+	}
+	return nb
+}
+
+// directionByName returns the horizontal cube.Direction with the name passed, such as 'north'.
+func directionByName(s string) (cube.Direction, bool) {
+	for _, d := range cube.Directions() {
+		if d.String() == s {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// structureFormatVersion is the version of the .mcstructure format written by StructureTemplate.Encode.
+const structureFormatVersion = 1
+
+// structureTemplateData is the layout of the NBT of the .mcstructure format.
+type structureTemplateData struct {
+	FormatVersion int32   `nbt:"format_version"`
+	Size          []int32 `nbt:"size"`
+	Origin        []int32 `nbt:"structure_world_origin"`
+	Structure     struct {
+		BlockIndices [][]int32                   `nbt:"block_indices"`
+		Entities     []map[string]any            `nbt:"entities"`
+		Palette      map[string]structurePalette `nbt:"palette"`
+	} `nbt:"structure"`
+}
+
+// structurePalette is a palette of blocks in the .mcstructure format.
+type structurePalette struct {
+	BlockPalette      []blockState              `nbt:"block_palette"`
+	BlockPositionData map[string]map[string]any `nbt:"block_position_data"`
+}
+
+// EncodeNBT encodes the StructureTemplate to a map of NBT data in the .mcstructure format.
+func (s *StructureTemplate) EncodeNBT() map[string]any {
+	var palette []any
+	indices := make(map[uint32]int32)
+	index := func(b Block) int32 {
+		rid := BlockRuntimeID(b)
+		if i, ok := indices[rid]; ok {
+			return i
+		}
+		name, properties := b.EncodeBlock()
+		if properties == nil {
+			properties = map[string]any{}
+		}
+		indices[rid] = int32(len(palette))
+		palette = append(palette, map[string]any{"name": name, "states": properties, "version": chunk.CurrentBlockVersion})
+		return indices[rid]
+	}
+
+	layers := [2][]int32{make([]int32, len(s.blocks)), make([]int32, len(s.blocks))}
+	positionData := make(map[string]any)
+	for x := 0; x < s.size[0]; x++ {
+		for y := 0; y < s.size[1]; y++ {
+			for z := 0; z < s.size[2]; z++ {
+				i := s.index(x, y, z)
+				layers[0][i], layers[1][i] = -1, -1
+				if b := s.blocks[i]; b != nil {
+					layers[0][i] = index(b)
+					if data, ok := EncodeBlockNBT(b); ok && nbtBlocks[BlockRuntimeID(b)] {
+						data["x"], data["y"], data["z"] = int32(x), int32(y), int32(z)
+						positionData[strconv.Itoa(i)] = map[string]any{"block_entity_data": data}
+					}
+				}
+				if liq := s.liquids[i]; liq != nil {
+					layers[1][i] = index(liq)
+				}
+			}
+		}
+	}
+	entities := make([]any, 0, len(s.entities))
+	for _, m := range s.entities {
+		entities = append(entities, m)
+	}
+	if palette == nil {
+		palette = []any{}
+	}
+	return map[string]any{
+		"format_version":         int32(structureFormatVersion),
+		"size":                   []int32{int32(s.size[0]), int32(s.size[1]), int32(s.size[2])},
+		"structure_world_origin": []int32{0, 0, 0},
+		"structure": map[string]any{
+			"block_indices": []any{layers[0], layers[1]},
+			"entities":      entities,
+			"palette": map[string]any{
+				"default": map[string]any{
+					"block_palette":       palette,
+					"block_position_data": positionData,
+				},
+			},
+		},
+	}
+}
+
+// Encode writes the StructureTemplate to the io.Writer passed in the .mcstructure format.
+func (s *StructureTemplate) Encode(w io.Writer) error {
+	return nbt.NewEncoderWithEncoding(w, nbt.LittleEndian).Encode(s.EncodeNBT())
+}
+
+// DecodeStructureTemplate reads a StructureTemplate in the .mcstructure format from the io.Reader passed.
+// Blocks that are not registered are read as structure voids.
+func DecodeStructureTemplate(r io.Reader) (*StructureTemplate, error) {
+	var data structureTemplateData
+	if err := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode structure template: %w", err)
+	}
+	if len(data.Size) != 3 || data.Size[0] < 0 || data.Size[1] < 0 || data.Size[2] < 0 {
+		return nil, fmt.Errorf("decode structure template: invalid size %v", data.Size)
+	}
+	s := NewStructureTemplate([3]int{int(data.Size[0]), int(data.Size[1]), int(data.Size[2])})
+	palette := data.Structure.Palette["default"]
+
+	blocks := make([]Block, len(palette.BlockPalette))
+	for i, state := range palette.BlockPalette {
+		upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{
+			Name:       state.Name,
+			Properties: state.Properties,
+			Version:    state.Version,
+		})
+		blocks[i], _ = BlockByName(upgraded.Name, upgraded.Properties)
+	}
+	block := func(layer, i int) Block {
+		if layer >= len(data.Structure.BlockIndices) || i >= len(data.Structure.BlockIndices[layer]) {
+			return nil
+		}
+		if index := data.Structure.BlockIndices[layer][i]; index >= 0 && int(index) < len(blocks) {
+			return blocks[index]
+		}
+		return nil
+	}
+	for i := range s.blocks {
+		s.blocks[i] = block(0, i)
+		if liq, ok := block(1, i).(Liquid); ok {
+			s.liquids[i] = liq
+		}
+		if !hasBlockEntity(s.blocks[i]) {
+			continue
+		}
+		if entry, ok := palette.BlockPositionData[strconv.Itoa(i)]; ok {
+			if m, ok := entry["block_entity_data"].(map[string]any); ok {
+				s.blocks[i] = DecodeBlockNBT(s.blocks[i], m)
+			}
+		}
+	}
+
+	var origin mgl64.Vec3
+	if len(data.Origin) == 3 {
+		origin = mgl64.Vec3{float64(data.Origin[0]), float64(data.Origin[1]), float64(data.Origin[2])}
+	}
+	for _, m := range data.Structure.Entities {
+		m["Pos"] = vec3ToFloat32Slice(nbtVec3(m, "Pos").Sub(origin))
+		delete(m, "UniqueID")
+		s.entities = append(s.entities, m)
+	}
+	return s, nil
+}
+
+// nbtVec3 reads a vector of three float32 values under the key passed from the NBT map passed.
+func nbtVec3(m map[string]any, k string) mgl64.Vec3 {
+	if l := nbtFloat32s(m, k); len(l) == 3 {
+		return mgl64.Vec3{float64(l[0]), float64(l[1]), float64(l[2])}
+	}
+	return mgl64.Vec3{}
+}
+
+// nbtFloat32s reads a list of float32 values under the key passed from the NBT map passed.
+func nbtFloat32s(m map[string]any, k string) []float32 {
+	switch l := m[k].(type) {
+	case []float32:
+		return l
+	case []any:
+		v := make([]float32, len(l))
+		for i, f := range l {
+			v[i], _ = f.(float32)
+		}
+		return v
+	}
+	return nil
+}
+
+// vec3ToFloat32Slice converts an mgl64.Vec3 to a []float32 with three elements.
+func vec3ToFloat32Slice(v mgl64.Vec3) []float32 {
+	return []float32{float32(v[0]), float32(v[1]), float32(v[2])}
+}
@@ -1,8 +1,11 @@
 package world
 
 import (
+	"fmt"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/internal/crash"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
+	"github.com/df-mc/dragonfly/server/metrics"
 	"golang.org/x/exp/maps"
 	"math/rand"
 	"slices"
@@ -16,6 +19,7 @@ type ticker struct{ w *World }
 // tickLoop starts ticking the World 20 times every second, updating all entities, blocks and other features such as
 // the time and weather of the world, as required.
 func (t ticker) tickLoop() {
+	defer crash.Recover()
 	tc := time.NewTicker(time.Second / 20)
 	defer tc.Stop()
 
@@ -37,14 +41,23 @@ func (t ticker) tick() {
 	viewers, loaders := t.w.allViewers()
 
 	t.w.set.Lock()
-	if len(viewers) == 0 && t.w.set.CurrentTick != 0 {
+	if len(viewers) == 0 && t.w.set.CurrentTick != 0 && t.w.tickets.empty() {
 		t.w.set.Unlock()
 		return
 	}
+	prof := &t.w.profiler
+	start := prof.start()
+	defer prof.tick(start)
+	defer func(start time.Time) {
+		metrics.TickDuration.Observe(fmt.Sprint(t.w.conf.Dim), time.Since(start).Seconds())
+	}(time.Now())
+
+	var dayTime int64 = -1
 	if t.w.advance {
 		t.w.set.CurrentTick++
 		if t.w.set.TimeCycle {
 			t.w.set.Time++
+			dayTime = ((t.w.set.Time % TimeFullDay) + TimeFullDay) % TimeFullDay
 		}
 		if t.w.set.WeatherCycle {
 			t.w.advanceWeather()
@@ -54,6 +67,21 @@ func (t ticker) tick() {
 	rain, thunder, tick, tim := t.w.set.Raining, t.w.set.Thundering && t.w.set.Raining, t.w.set.CurrentTick, int(t.w.set.Time)
 	t.w.set.Unlock()
 
+	loaderPositions := t.loaderPositions(loaders)
+	t.w.loadTicketed(t.w.tickets.update(tick, loaderPositions, int32(t.w.tickRange())))
+	if tick%20 == 0 {
+		t.w.gen.expire(time.Now())
+	}
+
+	if t.w.conf.Dim.TimeCycle() {
+		switch dayTime {
+		case TimeSunrise:
+			t.w.Handler().HandleDawn()
+		case TimeSunset:
+			t.w.Handler().HandleDusk()
+		}
+	}
+
 	if tick%20 == 0 {
 		for _, viewer := range viewers {
 			if t.w.conf.Dim.TimeCycle() {
@@ -67,26 +95,61 @@ func (t ticker) tick() {
 	if thunder {
 		t.w.tickLightning()
 	}
+	if rain {
+		t.tickPrecipitation()
+	}
+	prof.system(TickSystemWeather, start)
 
+	entitiesStart := prof.start()
 	t.tickEntities(tick)
-	t.tickBlocksRandomly(loaders, tick)
+	prof.system(TickSystemEntities, entitiesStart)
+
+	spawningStart := prof.start()
+	t.tickNaturalSpawning(tick, loaderPositions)
+	prof.system(TickSystemNaturalSpawning, spawningStart)
+
+	t.tickBlocksRandomly(tick)
+
+	scheduledStart := prof.start()
 	t.tickScheduledBlocks(tick)
+	prof.system(TickSystemScheduledUpdates, scheduledStart)
+
+	neighbourStart := prof.start()
 	t.performNeighbourUpdates()
+	prof.system(TickSystemNeighbourUpdates, neighbourStart)
 }
 
-// tickScheduledBlocks executes scheduled block updates in chunks that are currently loaded.
+// tickScheduledBlocks executes scheduled block updates that are due. Updates in chunks that are not loaded
+// are parked until the chunk is loaded, and updates that could not be executed within the
+// scheduledUpdateBudget are postponed to the next tick.
 func (t ticker) tickScheduledBlocks(tick int64) {
 	t.w.updateMu.Lock()
-	positions := make([]cube.Pos, 0, len(t.w.scheduledUpdates)/4)
-	for pos, scheduledTick := range t.w.scheduledUpdates {
-		if scheduledTick <= tick {
-			positions = append(positions, pos)
-			delete(t.w.scheduledUpdates, pos)
-		}
-	}
+	positions := t.w.scheduledUpdates.due(tick)
 	t.w.updateMu.Unlock()
 
-	for _, pos := range positions {
+	var (
+		deadline = time.Now().Add(scheduledUpdateBudget)
+		parked   []cube.Pos
+		lastPos  ChunkPos
+		loaded   bool
+	)
+	for i, pos := range positions {
+		if i != 0 && i%64 == 0 && time.Now().After(deadline) {
+			t.w.updateMu.Lock()
+			for _, pos := range positions[i:] {
+				t.w.scheduledUpdates.add(pos, tick+1)
+			}
+			t.w.updateMu.Unlock()
+			break
+		}
+		// Positions are sorted by chunk, so we only need to check if a chunk is loaded once.
+		if chunkPos := chunkPosFromBlockPos(pos); i == 0 || chunkPos != lastPos {
+			lastPos, loaded = chunkPos, t.w.chunkLoaded(chunkPos)
+		}
+		if !loaded {
+			parked = append(parked, pos)
+			continue
+		}
 		if ticker, ok := t.w.Block(pos).(ScheduledTicker); ok {
 			ticker.ScheduledTick(pos, t.w, t.w.r)
 		}
@@ -96,6 +159,13 @@ func (t ticker) tickScheduledBlocks(tick int64) {
 			}
 		}
 	}
+	if len(parked) > 0 {
+		t.w.updateMu.Lock()
+		for _, pos := range parked {
+			t.w.scheduledUpdates.park(pos)
+		}
+		t.w.updateMu.Unlock()
+	}
 }
 
 // performNeighbourUpdates performs all block updates that came as a result of a neighbouring block being changed.
@@ -103,6 +173,7 @@ func (t ticker) performNeighbourUpdates() {
 	t.w.updateMu.Lock()
 	positions := slices.Clone(t.w.neighbourUpdates)
 	t.w.neighbourUpdates = t.w.neighbourUpdates[:0]
+	clear(t.w.queuedNeighbourUpdates)
 	t.w.updateMu.Unlock()
 
 	for _, update := range positions {
@@ -118,33 +189,19 @@ func (t ticker) performNeighbourUpdates() {
 	}
 }
 
-// tickBlocksRandomly executes random block ticks in each sub chunk in the world that has at least one viewer
-// registered from the viewers passed.
-func (t ticker) tickBlocksRandomly(loaders []*Loader, tick int64) {
+// tickBlocksRandomly executes random block ticks in each sub chunk of the chunks in the world that are at least
+// block ticking, and ticks the block entities in those chunks.
+func (t ticker) tickBlocksRandomly(tick int64) {
 	var (
-		r             = int32(t.w.tickRange())
 		g             randUint4
 		blockEntities []cube.Pos
 		randomBlocks  []cube.Pos
+		speed         = t.randomTickSpeed()
 	)
-	if r == 0 {
-		// NOP if the simulation distance is 0.
-		return
-	}
-
-	loaded := make([]ChunkPos, 0, len(loaders))
-	for _, loader := range loaders {
-		loader.mu.RLock()
-		pos := loader.pos
-		loader.mu.RUnlock()
-
-		loaded = append(loaded, pos)
-	}
-
 	t.w.chunkMu.Lock()
 	for pos, c := range t.w.chunks {
-		if !t.anyWithinDistance(pos, loaded, r) {
-			// No loaders in this chunk that are within the simulation distance, so proceed to the next.
+		if t.w.tickets.level(pos) > LoadLevelBlockTicking {
+			// No tickets close enough to this chunk for its blocks to be ticked, so proceed to the next.
 			continue
 		}
 		c.Lock()
@@ -153,7 +210,7 @@ func (t ticker) tickBlocksRandomly(loaders []*Loader, tick int64) {
 		cx, cz := int(pos[0]<<4), int(pos[1]<<4)
 
 		// We generate up to j random positions for every sub chunk.
-		for j := 0; j < t.w.conf.RandomTickSpeed; j++ {
+		for j := 0; j < speed; j++ {
 			x, y, z := g.uint4(t.w.r), g.uint4(t.w.r), g.uint4(t.w.r)
 
 			for i, sub := range c.Sub() {
@@ -178,29 +235,81 @@ func (t ticker) tickBlocksRandomly(loaders []*Loader, tick int64) {
 	}
 	t.w.chunkMu.Unlock()
 
+	prof := &t.w.profiler
+	randomStart := prof.start()
 	for _, pos := range randomBlocks {
 		if rb, ok := t.w.Block(pos).(RandomTicker); ok {
 			rb.RandomTick(pos, t.w, t.w.r)
 		}
 	}
+	prof.system(TickSystemRandomTicks, randomStart)
+
+	blockEntitiesStart := prof.start()
 	for _, pos := range blockEntities {
-		if tb, ok := t.w.Block(pos).(TickerBlock); ok {
-			tb.Tick(tick, pos, t.w)
+		b := t.w.Block(pos)
+		var ticker interface {
+			Tick(currentTick int64, pos cube.Pos, w *World)
+		}
+		switch v := b.(type) {
+		case TickerBlock:
+			ticker = v
+		case BlockEntityHolder:
+			ticker, _ = v.BlockEntityData().(TickingBlockEntityData)
+		}
+		if ticker != nil {
+			tickStart := prof.start()
+			ticker.Tick(tick, pos, t.w)
+			if !tickStart.IsZero() {
+				name, _ := b.EncodeBlock()
+				prof.blockEntity(name, tickStart)
+			}
 		}
 	}
+	prof.system(TickSystemBlockEntities, blockEntitiesStart)
 }
 
-// anyWithinDistance checks if any of the ChunkPos loaded are within the distance r of the ChunkPos pos.
-func (t ticker) anyWithinDistance(pos ChunkPos, loaded []ChunkPos, r int32) bool {
-	for _, chunkPos := range loaded {
-		xDiff, zDiff := chunkPos[0]-pos[0], chunkPos[1]-pos[1]
-		if (xDiff*xDiff)+(zDiff*zDiff) <= r*r {
-			// The chunk was within the simulation distance of at least one viewer, so we can proceed to
-			// ticking the block.
-			return true
+// tickPrecipitation selects a random column in every block ticking chunk with a 1/16 chance
+// every tick, freezing water and accumulating snow at the top of the column if it is snowing there.
+func (t ticker) tickPrecipitation() {
+	if t.randomTickSpeed() <= 0 {
+		// Precipitation is handled as part of random ticking, so it is disabled along with it.
+		return
+	}
+	var columns [][2]int
+	t.w.chunkMu.Lock()
+	for pos := range t.w.chunks {
+		if t.w.r.Intn(16) != 0 || t.w.tickets.level(pos) > LoadLevelBlockTicking {
+			continue
 		}
+		v := t.w.r.Int31()
+		columns = append(columns, [2]int{int(pos[0]<<4) + int(v&0xf), int(pos[1]<<4) + int((v>>8)&0xf)})
+	}
+	t.w.chunkMu.Unlock()
+
+	for _, column := range columns {
+		t.w.precipitate(column[0], column[1])
+	}
+}
+
+// randomTickSpeed returns the amount of blocks that are randomly ticked in every sub chunk each tick.
+func (t ticker) randomTickSpeed() int {
+	if speed := t.w.RandomTickSpeed(); speed != 0 {
+		return speed
+	}
+	return GameRuleRandomTickSpeed.Value(t.w) * 3
+}
+
+// loaderPositions returns the chunk positions of all Loaders passed.
+func (t ticker) loaderPositions(loaders []*Loader) []ChunkPos {
+	loaded := make([]ChunkPos, 0, len(loaders))
+	for _, loader := range loaders {
+		loader.mu.RLock()
+		pos := loader.pos
+		loader.mu.RUnlock()
+
+		loaded = append(loaded, pos)
 	}
-	return false
+	return loaded
 }
 
 // tickEntities ticks all entities in the world, making sure they are still located in the correct chunks and
@@ -227,10 +336,11 @@ func (t ticker) tickEntities(tick int64) {
 		}
 
 		c.Lock()
-		v := len(c.viewers)
+		viewed := len(c.viewers) > 0
 		c.Unlock()
 
-		if v > 0 {
+		// Entities in chunks viewed by a player are always ticked, even if they are outside the tick range.
+		if viewed || t.w.tickets.entityTicking(chunkPos) {
 			if ticker, ok := e.(TickerEntity); ok {
 				entitiesToTick = append(entitiesToTick, ticker)
 			}
@@ -278,12 +388,16 @@ func (t ticker) tickEntities(tick int64) {
 			}
 		}
 	}
+	t.w.grid.Store(newEntityGrid(entitiesToTick))
+	prof := &t.w.profiler
 	for _, ticker := range entitiesToTick {
 		// Make sure the entity is still in world and has not been closed.
 		if ticker.World() == t.w {
 			// We gather entities to ticker and ticker them later, so that the lock on the entity mutex is no longer
 			// active.
+			tickStart := prof.start()
 			ticker.Tick(t.w, tick)
+			prof.entity(ticker.Type(), tickStart)
 		}
 	}
 }
@@ -0,0 +1,272 @@
+package world
+
+import (
+	"golang.org/x/exp/maps"
+	"sync"
+)
+
+// LoadLevel is the level at which a chunk is loaded. It determines which parts of the chunk are ticked by the
+// World. Load levels are derived from the tickets of a World: The closer a chunk is to a ticket, the lower its
+// LoadLevel. Regardless of its LoadLevel, the entities in a chunk viewed by a player are always ticked.
+type LoadLevel int
+
+const (
+	// LoadLevelEntityTicking is the LoadLevel of chunks in which both entities and blocks are ticked.
+	LoadLevelEntityTicking LoadLevel = iota
+	// LoadLevelBlockTicking is the LoadLevel of chunks in which blocks are ticked, but entities are not.
+	LoadLevelBlockTicking
+	// LoadLevelBorder is the LoadLevel of chunks that are kept loaded, but not ticked.
+	LoadLevelBorder
+	// LoadLevelInaccessible is the LoadLevel of chunks without ticket nearby. These chunks are not ticked and
+	// are unloaded when they have no viewers.
+	LoadLevelInaccessible
+)
+
+// TicketType is a type of ticket that may be added to a chunk of a World to keep it and the chunks around it
+// loaded and ticking. The chunks within the radius of a TicketType are entity ticking, followed by a ring of
+// block ticking chunks and a ring of border chunks.
+type TicketType struct {
+	name     string
+	radius   int32
+	lifetime int64
+}
+
+var (
+	// TicketPlayer is the TicketType of tickets held by the Loaders of a World, such as those of players. Its
+	// radius is the tick range of the World. If the tick range is 0, only the entities in the chunk of the
+	// Loader are ticked. Player tickets are not added using World.AddTicket, but derived from the positions of
+	// Loaders every tick.
+	TicketPlayer = TicketType{name: "player"}
+	// TicketForced is the TicketType of chunks force loaded using World.ForceLoad. It keeps the chunk entity
+	// ticking until it is removed.
+	TicketForced = TicketType{name: "forced"}
+	// TicketTeleport is the TicketType added to the chunk that an entity is teleported to. It keeps the chunk
+	// loaded for a few ticks, so that the chunk does not unload before a Loader arrives.
+	TicketTeleport = TicketType{name: "teleport", lifetime: 5}
+	// TicketPortal is the TicketType added to the chunk of the destination of a portal. It keeps the chunks
+	// around the portal ticking for 15 seconds.
+	TicketPortal = TicketType{name: "portal", radius: 1, lifetime: 300}
+)
+
+// String returns the name of the TicketType.
+func (t TicketType) String() string {
+	return t.name
+}
+
+// loadLevel returns the LoadLevel of a chunk with a squared distance of dist to a ticket with a radius r.
+func loadLevel(dist, r int32) LoadLevel {
+	switch {
+	case dist <= r*r:
+		return LoadLevelEntityTicking
+	case dist <= (r+1)*(r+1):
+		return LoadLevelBlockTicking
+	case dist <= (r+2)*(r+2):
+		return LoadLevelBorder
+	}
+	return LoadLevelInaccessible
+}
+
+// tickets holds the tickets added to the chunks of a World and the load levels derived from them.
+type tickets struct {
+	mu sync.Mutex
+	// m holds the tickets of every chunk with at least one ticket. The value of each ticket is the tick at
+	// which it expires, or 0 if it never expires.
+	m map[ChunkPos]map[TicketType]int64
+	// levels holds the LoadLevel of every chunk with a LoadLevel lower than LoadLevelInaccessible, as
+	// calculated during the last tick.
+	levels map[ChunkPos]LoadLevel
+	// entities holds the chunks of loaders that had a tick range of 0 during the last tick. Entities in these
+	// chunks are ticked, but blocks are not.
+	entities map[ChunkPos]struct{}
+}
+
+// newTickets returns an empty tickets value.
+func newTickets() *tickets {
+	return &tickets{
+		m:        make(map[ChunkPos]map[TicketType]int64),
+		levels:   make(map[ChunkPos]LoadLevel),
+		entities: make(map[ChunkPos]struct{}),
+	}
+}
+
+// add adds a ticket of the TicketType passed to a chunk, expiring at the tick passed.
+func (t *tickets) add(typ TicketType, pos ChunkPos, expiry int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m, ok := t.m[pos]
+	if !ok {
+		m = make(map[TicketType]int64)
+		t.m[pos] = m
+	}
+	m[typ] = expiry
+}
+
+// remove removes the ticket of the TicketType passed from a chunk.
+func (t *tickets) remove(typ TicketType, pos ChunkPos) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if m, ok := t.m[pos]; ok {
+		delete(m, typ)
+		if len(m) == 0 {
+			delete(t.m, pos)
+		}
+	}
+}
+
+// positions returns the positions of all chunks that hold a ticket of the TicketType passed.
+func (t *tickets) positions(typ TicketType) []ChunkPos {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var positions []ChunkPos
+	for pos, m := range t.m {
+		if _, ok := m[typ]; ok {
+			positions = append(positions, pos)
+		}
+	}
+	return positions
+}
+
+// update removes the tickets that expired at the current tick passed and recalculates the load levels of all
+// chunks, treating the positions of loaders passed as player tickets with radius r. If r is 0, the chunks of
+// the loaders only have their entities ticked, so that a tick range of 0 disables block ticking without
+// stopping players and the entities around them. The positions of all chunks kept loaded by tickets other than
+// player tickets are returned.
+func (t *tickets) update(current int64, loaders []ChunkPos, r int32) []ChunkPos {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.levels)
+	clear(t.entities)
+
+	for pos, m := range t.m {
+		for typ, expiry := range m {
+			if expiry != 0 && expiry <= current {
+				delete(m, typ)
+				continue
+			}
+			t.spread(pos, typ.radius)
+		}
+		if len(m) == 0 {
+			delete(t.m, pos)
+		}
+	}
+	ticketed := maps.Keys(t.levels)
+	for _, pos := range loaders {
+		if r <= 0 {
+			t.entities[pos] = struct{}{}
+			continue
+		}
+		t.spread(pos, r)
+	}
+	return ticketed
+}
+
+// spread lowers the load levels of the chunks around the position of a ticket with the radius r passed. t.mu
+// must be held when calling spread.
+func (t *tickets) spread(pos ChunkPos, r int32) {
+	outer := r + 2
+	for x := -outer; x <= outer; x++ {
+		for z := -outer; z <= outer; z++ {
+			l := loadLevel(x*x+z*z, r)
+			if l == LoadLevelInaccessible {
+				continue
+			}
+			p := ChunkPos{pos[0] + x, pos[1] + z}
+			if current, ok := t.levels[p]; !ok || l < current {
+				t.levels[p] = l
+			}
+		}
+	}
+}
+
+// level returns the LoadLevel of the chunk at the position passed as calculated during the last tick.
+func (t *tickets) level(pos ChunkPos) LoadLevel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if l, ok := t.levels[pos]; ok {
+		return l
+	}
+	return LoadLevelInaccessible
+}
+
+// entityTicking checks if the entities in the chunk at the position passed should be ticked, which is the case
+// if the chunk was entity ticking or held a loader during the last tick.
+func (t *tickets) entityTicking(pos ChunkPos) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entities[pos]; ok {
+		return true
+	}
+	l, ok := t.levels[pos]
+	return ok && l == LoadLevelEntityTicking
+}
+
+// empty checks if no tickets were added, excluding player tickets.
+func (t *tickets) empty() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.m) == 0
+}
+
+// AddTicket adds a ticket of the TicketType passed to the chunk at the position passed. The chunk, and the
+// chunks around it, are kept loaded and ticking according to the TicketType. Tickets of a TicketType with a
+// lifetime are removed automatically once it passes. Adding a ticket of a TicketType to a chunk that already
+// has a ticket of that type renews it.
+func (w *World) AddTicket(t TicketType, pos ChunkPos) {
+	if w == nil || t == TicketPlayer {
+		return
+	}
+	var expiry int64
+	if t.lifetime != 0 {
+		w.set.Lock()
+		expiry = w.set.CurrentTick + t.lifetime
+		w.set.Unlock()
+	}
+	w.tickets.add(t, pos, expiry)
+}
+
+// RemoveTicket removes the ticket of the TicketType passed from the chunk at the position passed.
+func (w *World) RemoveTicket(t TicketType, pos ChunkPos) {
+	if w == nil {
+		return
+	}
+	w.tickets.remove(t, pos)
+}
+
+// ForceLoad forces the chunk at the position passed to remain loaded and entity ticking, even if no players
+// are nearby, until RemoveForceLoad is called. It is typically used for farms and the spawn chunks of a World.
+func (w *World) ForceLoad(pos ChunkPos) {
+	w.AddTicket(TicketForced, pos)
+}
+
+// RemoveForceLoad stops forcing the chunk at the position passed to remain loaded.
+func (w *World) RemoveForceLoad(pos ChunkPos) {
+	w.RemoveTicket(TicketForced, pos)
+}
+
+// ForceLoaded returns the positions of all chunks force loaded using ForceLoad.
+func (w *World) ForceLoaded() []ChunkPos {
+	if w == nil {
+		return nil
+	}
+	return w.tickets.positions(TicketForced)
+}
+
+// LoadLevel returns the LoadLevel of the chunk at the position passed, as determined by the tickets of the
+// World and the positions of its Loaders during the last tick.
+func (w *World) LoadLevel(pos ChunkPos) LoadLevel {
+	if w == nil {
+		return LoadLevelInaccessible
+	}
+	return w.tickets.level(pos)
+}
+
+// loadTicketed loads the chunks at the positions passed, which hold a ticket, if they are not yet loaded.
+// Chunks that are not yet ready are prepared in the background and loaded during a later tick.
+func (w *World) loadTicketed(positions []ChunkPos) {
+	for _, pos := range positions {
+		if w.chunkLoaded(pos) || !w.chunkReady(pos) {
+			continue
+		}
+		w.chunk(pos).Unlock()
+	}
+}
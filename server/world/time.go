@@ -0,0 +1,153 @@
+package world
+
+import (
+	"math"
+)
+
+const (
+	// TimeDay is the time of day at which the day starts, as set by the /time set day command.
+	TimeDay = 1000
+	// TimeNoon is the time of day at which the sun is at its highest point.
+	TimeNoon = 6000
+	// TimeSunset is the time of day at which the sun starts setting. Handler.HandleDusk is called when the time
+	// of a World reaches TimeSunset.
+	TimeSunset = 12000
+	// TimeNight is the time of day at which the night starts, as set by the /time set night command.
+	TimeNight = 13000
+	// TimeMidnight is the time of day at which the moon is at its highest point.
+	TimeMidnight = 18000
+	// TimeSunrise is the time of day at which the sun starts rising. Handler.HandleDawn is called when the time
+	// of a World reaches TimeSunrise.
+	TimeSunrise = 23000
+	// TimeFullDay is the amount of time that passes in a single full day.
+	TimeFullDay = 24000
+)
+
+// MoonPhase is a phase of the moon. The moon cycles through eight phases, one every day, starting with a
+// FullMoon on the first day of a World.
+type MoonPhase int
+
+const (
+	// FullMoon is the phase in which the moon is fully lit.
+	FullMoon MoonPhase = iota
+	// WaningGibbous is the phase after the FullMoon, in which three quarters of the moon are lit.
+	WaningGibbous
+	// ThirdQuarter is the phase in which half of the moon is lit, while the moon is waning.
+	ThirdQuarter
+	// WaningCrescent is the phase in which a quarter of the moon is lit, while the moon is waning.
+	WaningCrescent
+	// NewMoon is the phase in which the moon is not lit at all.
+	NewMoon
+	// WaxingCrescent is the phase after the NewMoon, in which a quarter of the moon is lit.
+	WaxingCrescent
+	// FirstQuarter is the phase in which half of the moon is lit, while the moon is waxing.
+	FirstQuarter
+	// WaxingGibbous is the phase in which three quarters of the moon are lit, while the moon is waxing.
+	WaxingGibbous
+)
+
+// Brightness returns the brightness of the moon in the MoonPhase, ranging from 0 during a NewMoon to 1 during a
+// FullMoon. The brightness of the moon influences, for example, the regional difficulty of a World and the
+// chance of slimes spawning in swamps.
+func (p MoonPhase) Brightness() float64 {
+	return math.Abs(float64(p%8)-4) / 4
+}
+
+// String returns the name of the MoonPhase.
+func (p MoonPhase) String() string {
+	switch p {
+	case FullMoon:
+		return "full moon"
+	case WaningGibbous:
+		return "waning gibbous"
+	case ThirdQuarter:
+		return "third quarter"
+	case WaningCrescent:
+		return "waning crescent"
+	case NewMoon:
+		return "new moon"
+	case WaxingCrescent:
+		return "waxing crescent"
+	case FirstQuarter:
+		return "first quarter"
+	case WaxingGibbous:
+		return "waxing gibbous"
+	}
+	return "unknown"
+}
+
+// AddTime adds the time passed to the current time of the World. Negative values may be passed to turn back
+// the time. Like SetTime, AddTime works regardless of whether the time is stopped.
+func (w *World) AddTime(d int) {
+	if w == nil {
+		return
+	}
+	w.set.Lock()
+	w.set.Time += int64(d)
+	t := int(w.set.Time)
+	w.set.Unlock()
+
+	viewers, _ := w.allViewers()
+	for _, viewer := range viewers {
+		viewer.ViewTime(t)
+	}
+}
+
+// TimeOfDay returns the current time of day of the World, ranging from 0 to TimeFullDay.
+func (w *World) TimeOfDay() int {
+	return ((w.Time() % TimeFullDay) + TimeFullDay) % TimeFullDay
+}
+
+// Day returns the current day of the World, starting at 0 on the first day.
+func (w *World) Day() int {
+	return int(math.Floor(float64(w.Time()) / TimeFullDay))
+}
+
+// MoonPhase returns the current phase of the moon in the World.
+func (w *World) MoonPhase() MoonPhase {
+	return MoonPhase(((w.Day() % 8) + 8) % 8)
+}
+
+// TimeCycle checks if the time of the World is currently cycling. The time cycle may be stopped using StopTime
+// or by setting GameRuleDoDaylightCycle to false.
+func (w *World) TimeCycle() bool {
+	if w == nil {
+		return false
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return w.set.TimeCycle
+}
+
+// RegionalDifficulty returns the regional difficulty of the World, ranging from 0 to 3.75. The regional
+// difficulty grows with the Difficulty of the World, the age of the World and the brightness of the moon,
+// and may be used to scale the strength of mobs and the chance of them spawning with equipment. Unlike
+// vanilla, the time players spent in a chunk does not influence the regional difficulty.
+func (w *World) RegionalDifficulty() float64 {
+	if w == nil {
+		return 0
+	}
+	w.set.Lock()
+	diff, age := w.set.Difficulty, w.set.CurrentTick
+	w.set.Unlock()
+
+	id, _ := DifficultyID(diff)
+	if id == 0 {
+		return 0
+	}
+	f := 0.75
+	ageFactor := math.Min(math.Max(float64(age-72000)/1440000, 0), 1) * 0.25
+	f += ageFactor
+
+	h := math.Min(w.MoonPhase().Brightness()*0.25, ageFactor)
+	if diff == DifficultyEasy {
+		h *= 0.5
+	}
+	return float64(id) * (f + h)
+}
+
+// ClampedRegionalDifficulty returns the regional difficulty of the World, mapped to a range of 0 to 1. Regional
+// difficulties lower than 2 map to 0 and regional difficulties higher than 4 map to 1.
+func (w *World) ClampedRegionalDifficulty() float64 {
+	return math.Min(math.Max((w.RegionalDifficulty()-2)/2, 0), 1)
+}
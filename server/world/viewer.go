@@ -47,6 +47,11 @@ type Viewer interface {
 	// ViewEntityState views the current state of an entity. It is called whenever an entity changes its
 	// physical appearance, for example when sprinting.
 	ViewEntityState(e Entity)
+	// ViewEntityMount views an entity starting to ride another entity. driver specifies if the rider occupies the
+	// seat that controls the ridden entity.
+	ViewEntityMount(rider, ridden Entity, driver bool)
+	// ViewEntityDismount views an entity that stops riding another entity.
+	ViewEntityDismount(rider, ridden Entity)
 	// ViewEntityAnimation starts viewing an animation performed by an entity. The animation has to be from a resource pack.
 	ViewEntityAnimation(e Entity, animationName string)
 	// ViewParticle views a particle spawned at a given position in the world. It is called when a particle,
@@ -68,6 +73,8 @@ type Viewer interface {
 	ViewWorldSpawn(pos cube.Pos)
 	// ViewWeather views the weather of the world, including rain and thunder.
 	ViewWeather(raining, thunder bool)
+	// ViewDifficulty views the difficulty of the world.
+	ViewDifficulty(d Difficulty)
 }
 
 // NopViewer is a Viewer implementation that does not implement any behaviour. It may be embedded by other structs to
@@ -89,6 +96,8 @@ func (NopViewer) ViewEntityItems(Entity)                                     {}
 func (NopViewer) ViewEntityArmour(Entity)                                    {}
 func (NopViewer) ViewEntityAction(Entity, EntityAction)                      {}
 func (NopViewer) ViewEntityState(Entity)                                     {}
+func (NopViewer) ViewEntityMount(Entity, Entity, bool)                       {}
+func (NopViewer) ViewEntityDismount(Entity, Entity)                          {}
 func (NopViewer) ViewEntityAnimation(Entity, string)                         {}
 func (NopViewer) ViewParticle(mgl64.Vec3, Particle)                          {}
 func (NopViewer) ViewSound(mgl64.Vec3, Sound)                                {}
@@ -98,5 +107,6 @@ func (NopViewer) ViewEmote(Entity, uuid.UUID)                                {}
 func (NopViewer) ViewSkin(Entity)                                            {}
 func (NopViewer) ViewWorldSpawn(cube.Pos)                                    {}
 func (NopViewer) ViewWeather(bool, bool)                                     {}
+func (NopViewer) ViewDifficulty(Difficulty)                                  {}
 func (NopViewer) ViewFurnaceUpdate(time.Duration, time.Duration, time.Duration, time.Duration, time.Duration, time.Duration) {
 }
@@ -154,6 +154,74 @@ func (w weather) enableWeatherCycle(v bool) {
 	w.w.set.WeatherCycle = v
 }
 
+// precipitate freezes water and accumulates snow at the top of the column at the x and z passed if it is snowing
+// there. Water only freezes and snow only accumulates if the block light at the position is low enough.
+func (w weather) precipitate(x, z int) {
+	pos := cube.Pos{x, w.w.HighestBlock(x, z), z}
+	above := pos.Side(cube.FaceUp)
+	if !w.SnowingAt(above) {
+		return
+	}
+	if w.freezable(pos) {
+		if ice, ok := BlockByName("minecraft:ice", nil); ok {
+			w.w.SetBlock(pos, ice, nil)
+		}
+		return
+	}
+	if maxLayers := GameRuleSnowAccumulationHeight.Value(w.w); maxLayers > 0 && w.w.BlockLight(above) < 10 {
+		if name, properties := w.w.Block(pos).EncodeBlock(); name == "minecraft:snow_layer" {
+			// Snow is already present at the top of the column, so we add another layer to it if the game rule
+			// allows it.
+			if height, _ := properties["height"].(int32); int(height)+1 < maxLayers && height < 7 {
+				w.setSnowLayer(pos, height+1)
+			}
+			return
+		}
+		if w.w.Block(above) == air() && w.supportsSnow(pos) {
+			w.setSnowLayer(above, 0)
+		}
+	}
+}
+
+// freezable checks if the block at the position passed is a still source block of water that is exposed to air on
+// at least one of its horizontal sides, and that is dark enough to freeze.
+func (w weather) freezable(pos cube.Pos) bool {
+	liq, ok := w.w.Block(pos).(Liquid)
+	if !ok || liq.LiquidType() != "water" || liq.LiquidDepth() != 8 || liq.LiquidFalling() || w.w.BlockLight(pos) >= 10 {
+		return false
+	}
+	for _, face := range cube.HorizontalFaces() {
+		if side, ok := w.w.Block(pos.Side(face)).(Liquid); !ok || side.LiquidType() != "water" {
+			return true
+		}
+	}
+	return false
+}
+
+// snowRejectingBlocks holds the names of blocks that snow does not accumulate on, despite having a solid top face.
+var snowRejectingBlocks = map[string]bool{
+	"minecraft:ice":        true,
+	"minecraft:packed_ice": true,
+	"minecraft:blue_ice":   true,
+	"minecraft:barrier":    true,
+}
+
+// supportsSnow checks if a snow layer may be placed on top of the block at the position passed.
+func (w weather) supportsSnow(pos cube.Pos) bool {
+	b := w.w.Block(pos)
+	if name, _ := b.EncodeBlock(); snowRejectingBlocks[name] {
+		return false
+	}
+	return b.Model().FaceSolid(pos, cube.FaceUp, w.w)
+}
+
+// setSnowLayer places a snow layer with the height passed at a position in the World.
+func (w weather) setSnowLayer(pos cube.Pos, height int32) {
+	if snow, ok := BlockByName("minecraft:snow_layer", map[string]any{"height": height, "covered_bit": false}); ok {
+		w.w.SetBlock(pos, snow, nil)
+	}
+}
+
 // tickLightning iterates over all loaded chunks in the World, striking lightning in each one with a 1/100,000 chance.
 func (w weather) tickLightning() {
 	w.w.chunkMu.Lock()
@@ -176,9 +244,57 @@ func (w weather) tickLightning() {
 // living entities that might be near the lightning strike. If there is no rain at the final position selected, the
 // lightning strike will fail.
 func (w weather) strikeLightning(c ChunkPos) {
-	if pos := w.lightningPosition(c); w.ThunderingAt(cube.PosFromVec3(pos)) {
-		w.w.AddEntity(w.w.conf.Entities.conf.Lightning(pos))
+	pos := w.lightningPosition(c)
+	if !w.ThunderingAt(cube.PosFromVec3(pos)) {
+		return
+	}
+	if attractor, ok := w.lightningAttractor(cube.PosFromVec3(pos)); ok {
+		pos = attractor.Side(cube.FaceUp).Vec3Middle()
+	}
+	w.w.AddEntity(w.w.conf.Entities.conf.Lightning(pos))
+}
+
+// lightningAttractorRadius is the horizontal distance from a lightning strike within which a LightningAttractor
+// redirects the strike to itself.
+const lightningAttractorRadius = 64
+
+// lightningAttractor finds the LightningAttractor closest to the position passed within the
+// lightningAttractorRadius. Only LightningAttractors that are the highest block at their position in loaded
+// chunks are considered. If no LightningAttractor is found, false is returned.
+func (w weather) lightningAttractor(pos cube.Pos) (cube.Pos, bool) {
+	const r = lightningAttractorRadius
+	minChunk, maxChunk := chunkPosFromBlockPos(pos.Sub(cube.Pos{r, 0, r})), chunkPosFromBlockPos(pos.Add(cube.Pos{r, 0, r}))
+	found, nearest, dist := false, cube.Pos{}, -1
+
+	w.w.chunkMu.Lock()
+	defer w.w.chunkMu.Unlock()
+	for cx := minChunk[0]; cx <= maxChunk[0]; cx++ {
+		for cz := minChunk[1]; cz <= maxChunk[1]; cz++ {
+			c, ok := w.w.chunks[ChunkPos{cx, cz}]
+			if !ok {
+				continue
+			}
+			c.Lock()
+			for x := uint8(0); x < 16; x++ {
+				for z := uint8(0); z < 16; z++ {
+					y := c.HighestBlock(x, z)
+					if !lightningAttractorBlocks[c.Block(x, y, z, 0)] {
+						continue
+					}
+					candidate := cube.Pos{int(cx)<<4 + int(x), int(y), int(cz)<<4 + int(z)}
+					dx, dy, dz := candidate[0]-pos[0], candidate[1]-pos[1], candidate[2]-pos[2]
+					if dx < -r || dx > r || dz < -r || dz > r {
+						continue
+					}
+					if d := dx*dx + dy*dy + dz*dz; !found || d < dist {
+						found, nearest, dist = true, candidate, d
+					}
+				}
+			}
+			c.Unlock()
+		}
 	}
+	return nearest, found
 }
 
 // lightningPosition finds a random position in the ChunkPos to strike lightning and adjusts the position to any of the
@@ -208,7 +324,7 @@ func (w weather) adjustPositionToEntities(vec mgl64.Vec3) mgl64.Vec3 {
 			// Any (living) entity that is positioned higher than the highest block at its position is eligible to be
 			// struck by lightning. We first save all entity positions where this is the case.
 			pos := cube.PosFromVec3(e.Position())
-			if w.w.HighestBlock(pos[0], pos[1]) < pos[2] {
+			if w.w.HighestBlock(pos[0], pos[2]) < pos[1] {
 				list = append(list, e.Position())
 			}
 		}
@@ -2,11 +2,11 @@ package world
 
 import (
 	"errors"
+	"log/slog"
 	"math/rand"
 	"sync"
 	"time"
 
-	"github.com/df-mc/goleveldb/leveldb"
 
 	"slices"
 
@@ -14,6 +14,7 @@ import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
+	"github.com/df-mc/dragonfly/server/metrics"
 	"github.com/df-mc/dragonfly/server/world/chunk"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
@@ -45,6 +46,8 @@ type World struct {
 
 	closing chan struct{}
 	running sync.WaitGroup
+	// saveMu is held while the World is saved using Save, so that the World is not closed while saving.
+	saveMu sync.Mutex
 
 	chunkMu sync.Mutex
 	// chunks holds a cache of chunks currently loaded. These chunks are cleared from this map after some time
@@ -55,18 +58,46 @@ type World struct {
 	// entities holds a map of entities currently loaded and the last ChunkPos that the Entity was in.
 	// These are tracked so that a call to RemoveEntity can find the correct entity.
 	entities map[Entity]ChunkPos
+	// grid is a spatial index of the entities ticked in the current tick. It is
+	// rebuilt every tick and used for lookups by TickedEntitiesWithin.
+	grid atomic.Value[*entityGrid]
 
 	r *rand.Rand
 
 	updateMu sync.Mutex
-	// scheduledUpdates is a map of tick time values indexed by the block position at which an update is
-	// scheduled. If the current tick exceeds the tick value passed, the block update will be performed
-	// and the entry will be removed from the map.
-	scheduledUpdates map[cube.Pos]int64
-	neighbourUpdates []neighbourUpdate
+	// scheduledUpdates holds the block updates scheduled using ScheduleBlockUpdate. If the current tick
+	// reaches the tick at which an update is scheduled, the block update will be performed and the update
+	// will be removed.
+	scheduledUpdates *scheduledUpdates
+	// neighbourUpdates holds the neighbour updates to be performed in the next tick. queuedNeighbourUpdates
+	// holds the same updates and is used to prevent the same update from being queued more than once.
+	neighbourUpdates       []neighbourUpdate
+	queuedNeighbourUpdates map[neighbourUpdate]struct{}
 
 	viewersMu sync.Mutex
 	viewers   map[*Loader]Viewer
+
+	structureMu sync.Mutex
+	// structures holds the structure templates saved or loaded during the lifetime of the World, indexed by
+	// their name.
+	structures map[string]*StructureTemplate
+
+	border *Border
+	// light holds the positions of blocks of which the light needs to be updated by the light worker.
+	light *lightQueue
+	// gen holds the chunks that are being loaded or generated in the background by the generation workers.
+	gen *generationPool
+	// genLog is the Logger of the World with the logging.ChunkGen subsystem.
+	genLog *slog.Logger
+	// tickets holds the tickets that keep chunks loaded and ticking, and the load levels derived from them.
+	tickets *tickets
+	// chunkCache caches the network encoded sub chunks of the World.
+	chunkCache *chunkCache
+
+	// randomTickSpeed is the random tick speed of the World. It is initially set to Config.RandomTickSpeed and
+	// may be changed using World.SetRandomTickSpeed.
+	randomTickSpeed atomic.Int64
+	profiler        profiler
 }
 
 // New creates a new initialised world. The world may be used right away, but it will not be saved or loaded
@@ -95,6 +126,25 @@ func (w *World) Dimension() Dimension {
 	return w.conf.Dim
 }
 
+// RandomTickSpeed returns the random tick speed of the World, as set in Config.RandomTickSpeed or by
+// World.SetRandomTickSpeed. If 0, the value of GameRuleRandomTickSpeed is used to determine the speed instead.
+func (w *World) RandomTickSpeed() int {
+	if w == nil {
+		return 0
+	}
+	return int(w.randomTickSpeed.Load())
+}
+
+// SetRandomTickSpeed changes the random tick speed of the World. Like Config.RandomTickSpeed, the speed is the
+// number of blocks randomly ticked in each sub chunk every tick. Setting it to -1 or lower stops random
+// ticking altogether, while setting it to 0 makes the World use the value of GameRuleRandomTickSpeed.
+func (w *World) SetRandomTickSpeed(speed int) {
+	if w == nil {
+		return
+	}
+	w.randomTickSpeed.Store(int64(speed))
+}
+
 // Range returns the range in blocks of the World (min and max). It is equivalent to calling World.Dimension().Range().
 func (w *World) Range() cube.Range {
 	if w == nil {
@@ -145,7 +195,7 @@ func (w *World) Biome(pos cube.Pos) Biome {
 	id := int(c.Biome(uint8(pos[0]), int16(pos[1]), uint8(pos[2])))
 	b, ok := BiomeByID(id)
 	if !ok {
-		w.conf.Log.Errorf("could not find biome by ID %v", id)
+		w.conf.Log.Error("Could not find biome.", "id", id)
 	}
 	return b
 }
@@ -243,24 +293,21 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 
 	rid := BlockRuntimeID(b)
 
-	var before uint32
-	if rid != airRID && !opts.DisableLiquidDisplacement {
-		before = c.Block(x, y, z, 0)
-	}
+	layersBefore := c.layers(pos)
+	before := layersBefore[0]
 
-	c.modified = true
+	c.markModified()
 	c.SetBlock(x, y, z, 0, rid)
 	if nbtBlocks[rid] {
-		c.BlockEntities[pos] = b
+		c.BlockEntities[pos] = withBlockEntity(b)
 	} else {
 		delete(c.BlockEntities, pos)
 	}
 
 	viewers := slices.Clone(c.viewers)
 
+	var secondLayer Block
 	if !opts.DisableLiquidDisplacement {
-		var secondLayer Block
-
 		if rid == airRID {
 			if li := c.Block(x, y, z, 1); li != airRID {
 				c.SetBlock(x, y, z, 0, li)
@@ -274,16 +321,24 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 				c.SetBlock(x, y, z, 1, before)
 				secondLayer = l
 			}
+		} else if li := c.Block(x, y, z, 1); li != airRID && liquidBlocks[li] {
+			// The block was waterlogged. The liquid is only kept if the new block is able to displace it too.
+			l, _ := BlockByRuntimeID(li)
+			if !liquidDisplacingBlocks[rid] || !b.(LiquidDisplacer).CanDisplace(l.(Liquid)) {
+				c.SetBlock(x, y, z, 1, airRID)
+				secondLayer = air()
+			}
 		}
-		c.Unlock()
+	}
+	if lightChanged(layersBefore, c.layers(pos)) {
+		w.light.add(pos)
+	}
+	c.Unlock()
 
-		if secondLayer != nil {
-			for _, viewer := range viewers {
-				viewer.ViewBlockUpdate(pos, secondLayer, 1)
-			}
+	if secondLayer != nil {
+		for _, viewer := range viewers {
+			viewer.ViewBlockUpdate(pos, secondLayer, 1)
 		}
-	} else {
-		c.Unlock()
 	}
 
 	for _, viewer := range viewers {
@@ -305,7 +360,7 @@ func (w *World) SetBiome(pos cube.Pos, b Biome) {
 	c := w.chunk(chunkPosFromBlockPos(pos))
 	defer c.Unlock()
 
-	c.modified = true
+	c.markModified()
 	c.SetBiome(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), uint32(b.EncodeBiome()))
 }
 
@@ -374,7 +429,7 @@ func (w *World) BuildStructure(pos cube.Pos, s Structure) {
 
 								nbtPos := cube.Pos{xOffset, yOffset, zOffset}
 								if nbtBlocks[rid] {
-									c.BlockEntities[nbtPos] = b
+									c.BlockEntities[nbtPos] = withBlockEntity(b)
 								} else {
 									delete(c.BlockEntities, nbtPos)
 								}
@@ -389,7 +444,8 @@ func (w *World) BuildStructure(pos cube.Pos, s Structure) {
 				}
 			}
 			c.SetBlock(0, 0, 0, 0, c.Block(0, 0, 0, 0)) // Make sure the heightmap is recalculated.
-			c.modified = true
+			c.markModified()
+			w.light.addChunk(chunkPos)
 
 			// After setting all blocks of the structure within a single chunk, we show the new chunk to all
 			// viewers once, and unlock it.
@@ -416,7 +472,7 @@ func (w *World) Liquid(pos cube.Pos) (Liquid, bool) {
 	id := c.Block(x, y, z, 0)
 	b, ok := BlockByRuntimeID(id)
 	if !ok {
-		w.conf.Log.Errorf("failed getting liquid: cannot get block by runtime ID %v", id)
+		w.conf.Log.Error("Failed getting liquid: cannot get block by runtime ID.", "id", id)
 		return nil, false
 	}
 	if liq, ok := b.(Liquid); ok {
@@ -426,7 +482,7 @@ func (w *World) Liquid(pos cube.Pos) (Liquid, bool) {
 
 	b, ok = BlockByRuntimeID(id)
 	if !ok {
-		w.conf.Log.Errorf("failed getting liquid: cannot get block by runtime ID %v", id)
+		w.conf.Log.Error("Failed getting liquid: cannot get block by runtime ID.", "id", id)
 		return nil, false
 	}
 	liq, ok := b.(Liquid)
@@ -444,8 +500,13 @@ func (w *World) SetLiquid(pos cube.Pos, b Liquid) {
 	}
 	chunkPos := chunkPosFromBlockPos(pos)
 	c := w.chunk(chunkPos)
+	layersBefore := c.layers(pos)
 	if b == nil {
 		w.removeLiquids(c, pos)
+		c.markModified()
+		if lightChanged(layersBefore, c.layers(pos)) {
+			w.light.add(pos)
+		}
 		c.Unlock()
 		w.doBlockUpdatesAround(pos)
 		return
@@ -469,7 +530,10 @@ func (w *World) SetLiquid(pos cube.Pos, b Liquid) {
 			v.ViewBlockUpdate(pos, b, 1)
 		}
 	}
-	c.modified = true
+	c.markModified()
+	if lightChanged(layersBefore, c.layers(pos)) {
+		w.light.add(pos)
+	}
 	c.Unlock()
 
 	w.doBlockUpdatesAround(pos)
@@ -505,7 +569,7 @@ func (w *World) removeLiquidOnLayer(c *chunk.Chunk, x uint8, y int16, z, layer u
 
 	b, ok := BlockByRuntimeID(id)
 	if !ok {
-		w.conf.Log.Errorf("failed removing liquids: cannot get block by runtime ID %v", id)
+		w.conf.Log.Error("Failed removing liquids: cannot get block by runtime ID.", "id", id)
 		return false, false
 	}
 	if _, ok := b.(Liquid); ok {
@@ -527,7 +591,7 @@ func (w *World) additionalLiquid(pos cube.Pos) (Liquid, bool) {
 	c.Unlock()
 	b, ok := BlockByRuntimeID(id)
 	if !ok {
-		w.conf.Log.Errorf("failed getting liquid: cannot get block by runtime ID %v", id)
+		w.conf.Log.Error("Failed getting liquid: cannot get block by runtime ID.", "id", id)
 		return nil, false
 	}
 	liq, ok := b.(Liquid)
@@ -568,6 +632,19 @@ func (w *World) SkyLight(pos cube.Pos) uint8 {
 	return c.SkyLight(uint8(pos[0]), int16(pos[1]), uint8(pos[2]))
 }
 
+// BlockLight returns the block light level at the position passed. This light level is only influenced by
+// blocks that emit light, such as torches or glowstone, and not by the sky. The light value, similarly to
+// Light, is a value in the range 0-15, where 0 means no light is present.
+func (w *World) BlockLight(pos cube.Pos) uint8 {
+	if w == nil || pos[1] < w.Range()[0] || pos[1] > w.Range()[1] {
+		// Fast way out.
+		return 0
+	}
+	c := w.chunk(chunkPosFromBlockPos(pos))
+	defer c.Unlock()
+	return c.BlockLight(uint8(pos[0]), int16(pos[1]), uint8(pos[2]))
+}
+
 // Time returns the current time of the world. The time is incremented every 1/20th of a second, unless
 // World.StopTime() is called.
 func (w *World) Time() int {
@@ -767,19 +844,35 @@ func (w *World) EntitiesWithin(box cube.BBox, ignored func(Entity) bool) []Entit
 			c.Unlock()
 
 			for _, entity := range entities {
-				if ignored != nil && ignored(entity) {
+				// Check the position first, as it is generally far cheaper than the ignored function.
+				if !box.Vec3Within(entity.Position()) || (ignored != nil && ignored(entity)) {
 					continue
 				}
-				if box.Vec3Within(entity.Position()) {
-					// The entity position was within the BBox, so we add it to the slice to return.
-					m = append(m, entity)
-				}
+				// The entity position was within the BBox, so we add it to the slice to return.
+				m = append(m, entity)
 			}
 		}
 	}
 	return m
 }
 
+// TickedEntitiesWithin returns all entities ticked in the current tick that
+// are positioned within the BBox passed. Unlike EntitiesWithin, it uses a
+// spatial index that is rebuilt every tick, making it cheap enough to be
+// called by every entity every tick, for example to find entities colliding
+// with it. Entities added to the World during the current tick are not
+// returned.
+func (w *World) TickedEntitiesWithin(box cube.BBox, ignored func(Entity) bool) []Entity {
+	if w == nil {
+		return nil
+	}
+	g := w.grid.Load()
+	if g == nil {
+		return nil
+	}
+	return g.within(box, ignored)
+}
+
 // Entities returns a list of all entities currently added to the World.
 func (w *World) Entities() []Entity {
 	if w == nil {
@@ -794,6 +887,16 @@ func (w *World) Entities() []Entity {
 	return m
 }
 
+// LoadedChunks returns the number of chunks that are currently loaded in the World.
+func (w *World) LoadedChunks() int {
+	if w == nil {
+		return 0
+	}
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
+	return len(w.chunks)
+}
+
 // OfEntity attempts to return a world that an entity is currently in. If the entity was not currently added
 // to a world, the world returned is nil and the bool returned is false.
 func OfEntity(e Entity) (*World, bool) {
@@ -841,15 +944,31 @@ func (w *World) PlayerSpawn(uuid uuid.UUID) cube.Pos {
 	}
 	pos, exist, err := w.conf.Provider.LoadPlayerSpawnPosition(uuid)
 	if err != nil {
-		w.conf.Log.Errorf("failed to get player spawn: %v", err)
+		w.conf.Log.Error("Failed to get player spawn.", "err", err)
 		return w.Spawn()
 	}
 	if !exist {
-		return w.Spawn()
+		return w.randomSpawn()
 	}
 	return pos
 }
 
+// randomSpawn returns a random position within the spawn radius of the world around its spawn, as specified by
+// the GameRuleSpawnRadius. The position returned is on top of the highest block at its x and z coordinates. If
+// that column has no block to stand on, such as over the void, the spawn of the world is returned instead.
+func (w *World) randomSpawn() cube.Pos {
+	spawn, r := w.Spawn(), GameRuleSpawnRadius.Value(w)
+	if r <= 0 {
+		return spawn
+	}
+	x, z := spawn[0]+rand.Intn(r*2+1)-r, spawn[2]+rand.Intn(r*2+1)-r
+	pos := cube.Pos{x, w.highestObstructingBlock(x, z), z}
+	if !w.Block(pos).Model().FaceSolid(pos, cube.FaceUp, w) {
+		return spawn
+	}
+	return pos.Side(cube.FaceUp)
+}
+
 // SetPlayerSpawn sets the spawn position of a player with a UUID in this World. If the player has a spawn in the world,
 // the player will be teleported to this location on respawn.
 func (w *World) SetPlayerSpawn(uuid uuid.UUID, pos cube.Pos) {
@@ -857,7 +976,7 @@ func (w *World) SetPlayerSpawn(uuid uuid.UUID, pos cube.Pos) {
 		return
 	}
 	if err := w.conf.Provider.SavePlayerSpawnPosition(uuid, pos); err != nil {
-		w.conf.Log.Errorf("failed to set player spawn: %v", err)
+		w.conf.Log.Error("Failed to set player spawn.", "err", err)
 	}
 }
 
@@ -874,7 +993,8 @@ func (w *World) DefaultGameMode() GameMode {
 }
 
 // SetTickRange sets the range in chunks around each Viewer that will have the chunks (their blocks and entities)
-// ticked when the World is ticked.
+// ticked when the World is ticked. Entities in chunks viewed by a player are ticked regardless of the tick range,
+// so a tick range of 0 only stops blocks from being ticked.
 func (w *World) SetTickRange(v int) {
 	if w == nil {
 		return
@@ -919,8 +1039,66 @@ func (w *World) SetDifficulty(d Difficulty) {
 		return
 	}
 	w.set.Lock()
-	defer w.set.Unlock()
 	w.set.Difficulty = d
+	w.set.Unlock()
+
+	viewers, _ := w.allViewers()
+	for _, viewer := range viewers {
+		viewer.ViewDifficulty(d)
+	}
+}
+
+// ForceGameMode checks if the default game mode of the world is forced. If true, players are given the
+// default game mode of the world whenever they enter it, rather than only when joining for the first time.
+func (w *World) ForceGameMode() bool {
+	if w == nil {
+		return false
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return w.set.ForceGameMode
+}
+
+// SetForceGameMode changes if the default game mode of the world is forced upon players entering it.
+func (w *World) SetForceGameMode(force bool) {
+	if w == nil {
+		return
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	w.set.ForceGameMode = force
+}
+
+// MaxEntityCramming returns the maximum number of entities that may be pushed
+// into the same space before they start taking damage.
+func (w *World) MaxEntityCramming() int {
+	if w == nil {
+		return 0
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return int(w.set.MaxEntityCramming)
+}
+
+// SetMaxEntityCramming changes the maximum number of entities that may be
+// pushed into the same space before they start taking damage. If set to 0,
+// entities never take damage from cramming.
+func (w *World) SetMaxEntityCramming(n int) {
+	if w == nil {
+		return
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	w.set.MaxEntityCramming = int32(n)
+}
+
+// Border returns the Border of the World. Players cannot place blocks or teleport outside of it, and take
+// damage if they are too far beyond it.
+func (w *World) Border() *Border {
+	if w == nil {
+		return newBorder()
+	}
+	return w.border
 }
 
 // ScheduleBlockUpdate schedules a block update at the position passed after a specific delay. If the block at
@@ -929,16 +1107,13 @@ func (w *World) ScheduleBlockUpdate(pos cube.Pos, delay time.Duration) {
 	if w == nil || pos.OutOfBounds(w.Range()) {
 		return
 	}
-	w.updateMu.Lock()
-	defer w.updateMu.Unlock()
-	if _, exists := w.scheduledUpdates[pos]; exists {
-		return
-	}
 	w.set.Lock()
 	t := w.set.CurrentTick
 	w.set.Unlock()
 
-	w.scheduledUpdates[pos] = t + (delay.Milliseconds() / 50)
+	w.updateMu.Lock()
+	defer w.updateMu.Unlock()
+	w.scheduledUpdates.add(pos, t+(delay.Milliseconds()/50))
 }
 
 // doBlockUpdatesAround schedules block updates directly around and on the position passed.
@@ -962,9 +1137,15 @@ type neighbourUpdate struct {
 	pos, neighbour cube.Pos
 }
 
-// updateNeighbour ticks the position passed as a result of the neighbour passed being updated.
+// updateNeighbour ticks the position passed as a result of the neighbour passed being updated. If the same
+// update was already queued for the next tick, it is not queued again.
 func (w *World) updateNeighbour(pos, changedNeighbour cube.Pos) {
-	w.neighbourUpdates = append(w.neighbourUpdates, neighbourUpdate{pos: pos, neighbour: changedNeighbour})
+	update := neighbourUpdate{pos: pos, neighbour: changedNeighbour}
+	if _, ok := w.queuedNeighbourUpdates[update]; ok {
+		return
+	}
+	w.queuedNeighbourUpdates[update] = struct{}{}
+	w.neighbourUpdates = append(w.neighbourUpdates, update)
 }
 
 // Handle changes the current Handler of the world. As a result, events called by the world will call
@@ -994,6 +1175,20 @@ func (w *World) Viewers(pos mgl64.Vec3) (viewers []Viewer) {
 	return slices.Clone(c.viewers)
 }
 
+// LocateStructure returns the position of the generated structure with the
+// name passed, such as "stronghold", that is nearest to the position passed.
+// False is returned if the Generator of the World does not implement
+// StructureLocator or does not generate this structure.
+func (w *World) LocateStructure(name string, pos cube.Pos) (cube.Pos, bool) {
+	if w == nil {
+		return cube.Pos{}, false
+	}
+	if l, ok := w.conf.Generator.(StructureLocator); ok {
+		return l.LocateStructure(name, pos)
+	}
+	return cube.Pos{}, false
+}
+
 // PortalDestination returns the destination world for a portal of a specific Dimension. If no destination World could
 // be found, the current World is returned.
 func (w *World) PortalDestination(dim Dimension) *World {
@@ -1042,6 +1237,48 @@ func (w *World) Close() error {
 	return nil
 }
 
+// Save stores all chunks currently loaded that were changed since they were loaded or last saved, together with
+// the entities and block entities they hold, to the Provider of the World without unloading them. The settings
+// of the World are saved too. Chunks are stored one at a time, so that the World keeps ticking while it is being
+// saved. Save does nothing if the World is read-only or closed.
+func (w *World) Save() {
+	if w == nil || w.conf.ReadOnly {
+		return
+	}
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	select {
+	case <-w.closing:
+		return
+	default:
+	}
+
+	w.chunkMu.Lock()
+	toSave := maps.Clone(w.chunks)
+	w.chunkMu.Unlock()
+
+	for pos, c := range toSave {
+		w.chunkMu.Lock()
+		if w.chunks[pos] != c {
+			// The column was unloaded by the chunkCacheJanitor after it was copied, which saves it and closes
+			// its entities. Storing it again would remove those entities from the Provider.
+			w.chunkMu.Unlock()
+			continue
+		}
+		// The column is locked before releasing chunkMu, so that the chunkCacheJanitor cannot save and clear it
+		// until it has been stored here.
+		c.Lock()
+		w.chunkMu.Unlock()
+		w.storeColumn(pos, c)
+		c.Unlock()
+	}
+	if w.advance {
+		w.set.Lock()
+		w.provider().SaveSettings(w.set)
+		w.set.Unlock()
+	}
+}
+
 // close stops the World from ticking, saves all chunks to the Provider and updates the world's settings.
 func (w *World) close() {
 	// Let user code run anything that needs to be finished before the World is closed.
@@ -1050,8 +1287,10 @@ func (w *World) close() {
 
 	close(w.closing)
 	w.running.Wait()
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
 
-	w.conf.Log.Debugf("Saving chunks in memory to disk...")
+	w.conf.Log.Debug("Saving chunks in memory to disk...")
 
 	w.chunkMu.Lock()
 	w.lastChunk = nil
@@ -1069,14 +1308,14 @@ func (w *World) close() {
 	}
 
 	if !w.conf.ReadOnly {
-		w.conf.Log.Debugf("Updating level.dat values...")
+		w.conf.Log.Debug("Updating level.dat values...")
 
 		w.provider().SaveSettings(w.set)
 	}
 
-	w.conf.Log.Debugf("Closing provider...")
+	w.conf.Log.Debug("Closing provider...")
 	if err := w.provider().Close(); err != nil {
-		w.conf.Log.Errorf("error closing world provider: %v", err)
+		w.conf.Log.Error("Error closing world provider.", "err", err)
 	}
 }
 
@@ -1104,6 +1343,7 @@ func (w *World) addWorldViewer(l *Loader) {
 	w.set.Unlock()
 	l.viewer.ViewWeather(raining, thundering)
 	l.viewer.ViewWorldSpawn(w.Spawn())
+	l.viewer.ViewDifficulty(w.Difficulty())
 }
 
 // removeWorldViewer removes a viewer from the world. Should only be used while the viewer isn't viewing any chunks.
@@ -1209,7 +1449,7 @@ func (w *World) chunk(pos ChunkPos) *Column {
 		chunk.LightArea([]*chunk.Chunk{c.Chunk}, int(pos[0]), int(pos[1])).Fill()
 		if err != nil {
 			w.chunkMu.Unlock()
-			w.conf.Log.Errorf("load chunk: failed loading %v: %v\n", pos, err)
+			w.genLog.Error("Failed loading chunk.", "pos", pos, "err", err)
 			return c
 		}
 		c.Unlock()
@@ -1220,10 +1460,33 @@ func (w *World) chunk(pos ChunkPos) *Column {
 	w.lastChunk, w.lastPos = c, pos
 	w.chunkMu.Unlock()
 
+	if !ok {
+		// Block updates that were due while the chunk was not loaded can now be executed.
+		w.unparkUpdates(pos)
+	}
 	c.Lock()
 	return c
 }
 
+// chunkLoaded checks if the chunk at the ChunkPos passed is currently loaded.
+func (w *World) chunkLoaded(pos ChunkPos) bool {
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
+	_, ok := w.chunks[pos]
+	return ok
+}
+
+// unparkUpdates schedules the block updates parked in the chunk at the ChunkPos passed for the next tick.
+func (w *World) unparkUpdates(pos ChunkPos) {
+	w.set.Lock()
+	t := w.set.CurrentTick
+	w.set.Unlock()
+
+	w.updateMu.Lock()
+	defer w.updateMu.Unlock()
+	w.scheduledUpdates.unpark(pos, t+1)
+}
+
 // setChunk sets the chunk.Chunk passed at a specific ChunkPos without replacing any entities at that
 // position.
 //
@@ -1240,47 +1503,69 @@ func (w *World) setChunk(pos ChunkPos, c *chunk.Chunk, e map[cube.Pos]Block) {
 	if o, ok := w.chunks[pos]; ok {
 		col.viewers = o.viewers
 	}
+	col.version = columnVersion.Add(1)
 	w.chunks[pos] = col
+	// Make sure a column prepared in the background is not added later on, replacing the chunk set here.
+	w.gen.remove(pos)
 }
 
 // loadChunk attempts to load a chunk from the provider, or generates a chunk if one doesn't currently exist.
+// The chunk is prepared by the generationPool of the World, during which w.chunkMu is released, so that the
+// World may continue ticking. loadChunk must be called with w.chunkMu held. If successful, it returns the
+// column locked and w.chunkMu released.
 func (w *World) loadChunk(pos ChunkPos) (*Column, error) {
-	col, err := w.provider().LoadColumn(pos, w.conf.Dim)
-	switch {
-	case err == nil:
-		w.chunks[pos] = col
-		// Iterate through the entities twice and make sure they're added to all relevant maps. Note that this iteration
-		// happens twice to avoid having to lock both worldsMu and entityMu. This is intentional, to avoid deadlocks.
-		worldsMu.Lock()
-		for _, e := range col.Entities {
-			entityWorlds[e] = w
-		}
-		worldsMu.Unlock()
-
-		w.entityMu.Lock()
-		for _, e := range col.Entities {
-			w.entities[e] = pos
-		}
-		w.entityMu.Unlock()
-
+	f := w.gen.request(pos, true)
+	w.chunkMu.Unlock()
+	select {
+	case <-f.done:
+	case <-w.closing:
+		w.chunkMu.Lock()
+		col := newColumn(chunk.New(airRID, w.Range()))
 		col.Lock()
-		w.chunkMu.Unlock()
-		return col, nil
-	case errors.Is(err, leveldb.ErrNotFound):
-		// The provider doesn't have a chunk saved at this position, so we generate a new one.
-		col = newColumn(chunk.New(airRID, w.Range()))
-		w.chunks[pos] = col
-
+		return col, errors.New("world closed while loading chunk")
+	}
+	w.chunkMu.Lock()
+	if col, ok := w.chunks[pos]; ok {
+		// Another caller waiting for the same column added it to the World already.
 		col.Lock()
 		w.chunkMu.Unlock()
-
-		w.conf.Generator.GenerateChunk(pos, col.Chunk)
 		return col, nil
-	default:
-		col = newColumn(chunk.New(airRID, w.Range()))
+	}
+	w.gen.remove(pos)
+	if f.err != nil {
+		col := newColumn(chunk.New(airRID, w.Range()))
 		col.Lock()
-		return col, err
+		return col, f.err
+	}
+	col := f.col
+	col.version = columnVersion.Add(1)
+	w.chunks[pos] = col
+	w.addColumnEntities(pos, col.Entities)
+
+	col.Lock()
+	w.chunkMu.Unlock()
+	return col, nil
+}
+
+// addColumnEntities adds the entities passed, which were loaded or generated in
+// the chunk at the position passed, to the World.
+func (w *World) addColumnEntities(pos ChunkPos, entities []Entity) {
+	if len(entities) == 0 {
+		return
+	}
+	// Iterate through the entities twice and make sure they're added to all relevant maps. Note that this iteration
+	// happens twice to avoid having to lock both worldsMu and entityMu. This is intentional, to avoid deadlocks.
+	worldsMu.Lock()
+	for _, e := range entities {
+		entityWorlds[e] = w
+	}
+	worldsMu.Unlock()
+
+	w.entityMu.Lock()
+	for _, e := range entities {
+		w.entities[e] = pos
 	}
+	w.entityMu.Unlock()
 }
 
 // calculateLight calculates the light in the chunk passed and spreads the light of any of the surrounding
@@ -1332,12 +1617,7 @@ func (w *World) spreadLight(pos ChunkPos) {
 // the provider.
 func (w *World) saveChunk(pos ChunkPos, c *Column) {
 	c.Lock()
-	if !w.conf.ReadOnly && (len(c.BlockEntities) > 0 || len(c.Entities) > 0 || c.modified) {
-		c.Compact()
-		if err := w.provider().StoreColumn(pos, w.conf.Dim, c); err != nil {
-			w.conf.Log.Errorf("save chunk: %v", err)
-		}
-	}
+	w.storeColumn(pos, c)
 	ent := c.Entities
 	c.Entities = nil
 	c.Unlock()
@@ -1347,6 +1627,24 @@ func (w *World) saveChunk(pos ChunkPos, c *Column) {
 	}
 }
 
+// storeColumn stores the Column at the position passed to the Provider if it was modified or holds entities or
+// block entities, which may have changed without the Column being marked as modified. The Column must be locked
+// when calling storeColumn.
+func (w *World) storeColumn(pos ChunkPos, c *Column) {
+	if w.conf.ReadOnly || (len(c.BlockEntities) == 0 && len(c.Entities) == 0 && !c.modified) {
+		return
+	}
+	c.Compact()
+	start := time.Now()
+	err := w.provider().StoreColumn(pos, w.conf.Dim, c)
+	metrics.ProviderDuration.Observe("store", time.Since(start).Seconds())
+	if err != nil {
+		w.conf.Log.Error("Failed saving chunk.", "pos", pos, "err", err)
+		return
+	}
+	c.modified = false
+}
+
 // chunkCacheJanitor runs until the world is running, cleaning chunks that are no longer in use from the cache.
 func (w *World) chunkCacheJanitor() {
 	t := time.NewTicker(time.Minute * 5)
@@ -1362,7 +1660,7 @@ func (w *World) chunkCacheJanitor() {
 				c.Lock()
 				v := len(c.viewers)
 				c.Unlock()
-				if v == 0 {
+				if v == 0 && w.tickets.level(pos) == LoadLevelInaccessible {
 					chunksToRemove[pos] = c
 					delete(w.chunks, pos)
 					if w.lastPos == pos {
@@ -1376,6 +1674,9 @@ func (w *World) chunkCacheJanitor() {
 				w.saveChunk(pos, c)
 				delete(chunksToRemove, pos)
 			}
+			// The World does not tick without viewers, so columns prepared for loaders that have since left
+			// are also dropped here.
+			w.gen.expire(time.Now())
 		case <-w.closing:
 			w.running.Done()
 			return
@@ -1388,6 +1689,7 @@ func (w *World) chunkCacheJanitor() {
 type Column struct {
 	sync.Mutex
 	modified bool
+	version  uint64
 
 	*chunk.Chunk
 	Entities      []Entity